@@ -5,8 +5,11 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
+	"runtime"
+	"sync"
 	"syscall"
 	"time"
 
@@ -27,6 +30,8 @@ import (
 	"github.com/0xmhha/indexer-go/pkg/token"
 	"github.com/0xmhha/indexer-go/pkg/types/chain"
 	"github.com/0xmhha/indexer-go/pkg/verifier"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 )
 
@@ -59,9 +64,14 @@ type App struct {
 	// Contract verification
 	contractVerifier verifier.Verifier
 
+	// running tracks the indexing loop, which Shutdown waits for before
+	// closing storage
+	running sync.WaitGroup
+
 	// Runtime flags
 	enableGapMode    bool
 	forceAdapterType string
+	reindexEvents    bool
 }
 
 func main() {
@@ -91,7 +101,7 @@ func run() error {
 	}
 
 	// Initialize logger
-	log, err := initLogger(cfg.Log.Level, cfg.Log.Format)
+	log, logLevel, err := initLogger(cfg.Log.Level, cfg.Log.Format)
 	if err != nil {
 		return fmt.Errorf("failed to initialize logger: %w", err)
 	}
@@ -100,6 +110,11 @@ func run() error {
 	// Log startup information
 	logStartupInfo(log, cfg, flags)
 
+	// Check storage integrity and exit without indexing
+	if flags.verify {
+		return verifyStorage(context.Background(), cfg.Database.Path, flags.verifyFrom, flags.verifyTo, os.Stdout)
+	}
+
 	// Clear data folder if requested
 	if flags.clearData {
 		if err := clearDataFolder(cfg.Database.Path, log); err != nil {
@@ -120,6 +135,7 @@ func run() error {
 		return fmt.Errorf("failed to create application: %w", err)
 	}
 	defer app.Shutdown()
+	app.reindexEvents = flags.reindexEvents
 
 	// Create context for graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
@@ -129,13 +145,31 @@ func run() error {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 
-	// Run application
+	// Reload the log level from the config file on SIGHUP
+	hupChan := make(chan os.Signal, 1)
+	signal.Notify(hupChan, syscall.SIGHUP)
+	defer signal.Stop(hupChan)
+	go watchLogLevelReload(ctx, hupChan, flags, cfg.Log.Format, logLevel, log)
+
+	// Run application, or only the receipt repair / failed block retry when requested
+	runFn := app.Run
+	if flags.repairReceipts {
+		runFn = func(ctx context.Context) error {
+			return app.repairReceipts(ctx, flags.repairFrom, flags.repairTo)
+		}
+	}
+	if flags.retryFailed {
+		runFn = app.retryFailedBlocks
+	}
 	errChan := make(chan error, 1)
+	app.running.Add(1)
 	go func() {
-		errChan <- app.Run(ctx)
+		defer app.running.Done()
+		errChan <- runFn(ctx)
 	}()
 
-	// Wait for shutdown signal or error
+	// Wait for shutdown signal or error. The deferred Shutdown waits for the
+	// indexing loop to stop after the context is cancelled.
 	select {
 	case sig := <-sigChan:
 		log.Info("Received shutdown signal", zap.String("signal", sig.String()))
@@ -165,6 +199,14 @@ type Flags struct {
 	enableGapMode    bool
 	clearData        bool
 	reindex          bool // Clear blockchain data only, preserving verification data
+	reindexEvents    bool // Rebuild system contract event indexes from stored receipts
+	repairReceipts   bool // Re-fetch missing receipts of stored blocks and exit
+	repairFrom       uint64
+	repairTo         uint64
+	retryFailed      bool // Re-attempt blocks recorded as failed and exit
+	verify           bool // Check stored blocks for consistency and exit
+	verifyFrom       uint64
+	verifyTo         uint64
 	enableAPI        bool
 	apiHost          string
 	apiPort          int
@@ -172,6 +214,8 @@ type Flags struct {
 	enableJSONRPC    bool
 	enableWebSocket  bool
 	forceAdapterType string // Force specific adapter type: anvil, stableone, evm
+	publishBuffer    int    // EventBus publish channel size (0 = use config.yaml)
+	subscribeBuffer  int    // EventBus per-subscriber channel size (0 = use config.yaml)
 }
 
 // parseFlags parses command-line flags
@@ -190,6 +234,14 @@ func parseFlags() *Flags {
 	flag.BoolVar(&f.enableGapMode, "gap-recovery", false, "Enable gap detection and recovery at startup")
 	flag.BoolVar(&f.clearData, "clear-data", false, "Clear (delete) the data folder before starting")
 	flag.BoolVar(&f.reindex, "reindex", false, "Clear blockchain data only, preserving verification data (ABIs, source code, verification status)")
+	flag.BoolVar(&f.reindexEvents, "reindex-events", false, "Rebuild system contract event indexes from stored receipts before indexing")
+	flag.BoolVar(&f.repairReceipts, "repair-receipts", false, "Re-fetch missing receipts of stored blocks and exit without indexing")
+	flag.Uint64Var(&f.repairFrom, "repair-from", 0, "First block height scanned by --repair-receipts")
+	flag.Uint64Var(&f.repairTo, "repair-to", 0, "Last block height scanned by --repair-receipts (0 = latest indexed height)")
+	flag.BoolVar(&f.retryFailed, "retry-failed", false, "Re-attempt blocks that failed to index and exit without indexing")
+	flag.BoolVar(&f.verify, "verify", false, "Check stored blocks for consistency, print a report and exit (non-zero on inconsistency)")
+	flag.Uint64Var(&f.verifyFrom, "verify-from", 0, "First block height checked by --verify")
+	flag.Uint64Var(&f.verifyTo, "verify-to", 0, "Last block height checked by --verify (0 = latest indexed height)")
 
 	// API server flags
 	flag.BoolVar(&f.enableAPI, "api", false, "Enable API server")
@@ -200,6 +252,8 @@ func parseFlags() *Flags {
 	flag.BoolVar(&f.enableWebSocket, "websocket", false, "Enable WebSocket API")
 
 	// Chain adapter flags
+	flag.IntVar(&f.publishBuffer, "eventbus-publish-buffer", 0, "EventBus publish buffer size (0 = use config.yaml)")
+	flag.IntVar(&f.subscribeBuffer, "eventbus-subscribe-buffer", 0, "EventBus per-subscriber buffer size (0 = use config.yaml)")
 	flag.StringVar(&f.forceAdapterType, "adapter", "", "Force specific adapter type (anvil, stableone, evm). Auto-detected if empty")
 
 	flag.Parse()
@@ -216,6 +270,7 @@ func loadAndValidateConfig(flags *Flags) (*config.Config, error) {
 	// Override config with command-line flags
 	applyFlags(cfg, flags.rpcEndpoint, flags.dbPath, flags.startHeight, flags.workers, flags.batchSize, flags.logLevel, flags.logFormat)
 	applyAPIFlags(cfg, flags.enableAPI, flags.apiHost, flags.apiPort, flags.enableGraphQL, flags.enableJSONRPC, flags.enableWebSocket)
+	applyEventBusFlags(cfg, flags.publishBuffer, flags.subscribeBuffer)
 
 	// Validate configuration
 	if err := validateConfig(cfg); err != nil {
@@ -244,6 +299,10 @@ func logStartupInfo(log *zap.Logger, cfg *config.Config, flags *Flags) {
 		zap.Bool("gap_recovery", flags.enableGapMode),
 		zap.Bool("clear_data", flags.clearData),
 		zap.Bool("reindex", flags.reindex),
+		zap.Bool("reindex_events", flags.reindexEvents),
+		zap.Bool("repair_receipts", flags.repairReceipts),
+		zap.Bool("retry_failed", flags.retryFailed),
+		zap.Bool("verify", flags.verify),
 		zap.String("adapter", adapterInfo),
 	)
 }
@@ -301,7 +360,9 @@ func NewApp(cfg *config.Config, log *zap.Logger, enableGapMode bool, forceAdapte
 		}
 
 		// Initialize fetcher
-		app.initFetcher()
+		if err := app.initFetcher(); err != nil {
+			return nil, err
+		}
 	}
 
 	// Initialize API server if enabled
@@ -317,9 +378,17 @@ func NewApp(cfg *config.Config, log *zap.Logger, enableGapMode bool, forceAdapte
 // initClient initializes the Ethereum client and detects node type
 func (a *App) initClient() error {
 	ethClient, err := client.NewClient(&client.Config{
-		Endpoint: a.config.RPC.Endpoint,
-		Timeout:  a.config.RPC.Timeout,
-		Logger:   a.logger,
+		Endpoint:       a.config.RPC.Endpoint,
+		Timeout:        a.config.RPC.Timeout,
+		MethodTimeouts: a.config.RPC.MethodTimeouts,
+		Logger:         a.logger,
+		Retry: &client.RetryConfig{
+			MaxRetries: a.config.RPC.Retry.MaxRetries,
+			BaseDelay:  a.config.RPC.Retry.BaseDelay,
+			MaxDelay:   a.config.RPC.Retry.MaxDelay,
+			Multiplier: a.config.RPC.Retry.Multiplier,
+			Jitter:     a.config.RPC.Retry.Jitter,
+		},
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create Ethereum client: %w", err)
@@ -368,10 +437,74 @@ func (a *App) testConnection(ctx context.Context) error {
 	return nil
 }
 
+// buildStorageConfig derives pebble settings from the database configuration.
+// Cache and memtable sizes scale with system memory unless explicitly overridden.
+func (a *App) buildStorageConfig() *storage.Config {
+	dbCfg := a.config.Database
+	storageConfig := storage.DefaultConfig(dbCfg.Path)
+	storageConfig.MemoryBudget = dbCfg.MemoryBudgetMB
+	storageConfig.LogAddressTopicIndex = dbCfg.LogAddressTopicIndex
+	storageConfig.IndexUncles = dbCfg.IndexUncles
+	storageConfig.ReadCacheSize = dbCfg.ReadCacheSize
+	storageConfig.InitTransactionCount = dbCfg.InitTransactionCount
+	storageConfig.ScanWorkers = dbCfg.ScanWorkers
+	if storageConfig.ScanWorkers == 0 {
+		storageConfig.ScanWorkers = runtime.NumCPU()
+	}
+	storageConfig.CompactionInterval = dbCfg.CompactionInterval
+	storageConfig.CompactionStartTime = dbCfg.CompactionTime
+	storageConfig.KeepOrphanBlocks = dbCfg.KeepOrphanBlocks
+	storageConfig.KeyNamespace = dbCfg.KeyNamespace
+	storageConfig.MaxOpenIterators = dbCfg.MaxOpenIterators
+	storageConfig.PanicOnIteratorLeak = dbCfg.PanicOnIteratorLeak
+	storageConfig.ReplicaRefreshInterval = dbCfg.ReplicaRefreshInterval
+	storageConfig.ReplicaSnapshotDir = dbCfg.ReplicaSnapshotDir
+	if dbCfg.WriteBufferCount > 0 {
+		storageConfig.WriteBufferCount = dbCfg.WriteBufferCount
+	}
+	if dbCfg.BloomFilterBitsPerKey > 0 {
+		storageConfig.BloomFilterBitsPerKey = dbCfg.BloomFilterBitsPerKey
+	} else if dbCfg.BloomFilterBitsPerKey < 0 {
+		storageConfig.BloomFilterBitsPerKey = 0
+	}
+
+	totalMemoryMB := storage.TotalMemoryMB()
+	storageConfig.ScaleToMemory(totalMemoryMB, dbCfg.MemoryFraction)
+
+	if dbCfg.CacheMB > 0 {
+		storageConfig.Cache = dbCfg.CacheMB
+	}
+	if dbCfg.WriteBufferMB > 0 {
+		storageConfig.WriteBuffer = dbCfg.WriteBufferMB
+	}
+
+	a.logger.Info("Storage settings",
+		zap.Int("cache_mb", storageConfig.Cache),
+		zap.Int("write_buffer_mb", storageConfig.WriteBuffer),
+		zap.Int("write_buffer_count", storageConfig.WriteBufferCount),
+		zap.Int("memory_budget_mb", storageConfig.MemoryBudget),
+		zap.Int("bloom_filter_bits_per_key", storageConfig.BloomFilterBitsPerKey),
+		zap.Int("system_memory_mb", totalMemoryMB),
+		zap.Float64("memory_fraction", dbCfg.MemoryFraction),
+		zap.Bool("log_address_topic_index", storageConfig.LogAddressTopicIndex),
+		zap.Bool("index_uncles", storageConfig.IndexUncles),
+		zap.Int("read_cache_size", storageConfig.ReadCacheSize),
+		zap.Bool("init_transaction_count", storageConfig.InitTransactionCount),
+		zap.Int("scan_workers", storageConfig.ScanWorkers),
+		zap.Duration("compaction_interval", storageConfig.CompactionInterval),
+		zap.String("compaction_time", storageConfig.CompactionStartTime),
+		zap.Bool("keep_orphan_blocks", storageConfig.KeepOrphanBlocks),
+		zap.String("key_namespace", storageConfig.KeyNamespace),
+		zap.Duration("replica_refresh_interval", storageConfig.ReplicaRefreshInterval),
+	)
+
+	return storageConfig
+}
+
 // initStorageOnly initializes only the base storage layer without genesis initialization
 // This is used when multichain mode is enabled (each chain handles its own genesis)
 func (a *App) initStorageOnly(ctx context.Context) error {
-	storageConfig := storage.DefaultConfig(a.config.Database.Path)
+	storageConfig := a.buildStorageConfig()
 	storageConfig.ReadOnly = false
 
 	baseStore, err := storage.NewPebbleStorage(storageConfig)
@@ -380,6 +513,17 @@ func (a *App) initStorageOnly(ctx context.Context) error {
 	}
 	baseStore.SetLogger(a.logger)
 
+	if baseStore.TransactionCountMissing() {
+		a.logger.Warn("Database has indexed blocks but no transaction count; the total transaction count only covers transactions indexed since startup. Set database.init_transaction_count to rebuild it on the next start",
+			zap.String("path", a.config.Database.Path),
+		)
+	}
+
+	// Export Pebble engine metrics (levels, block cache, compactions, WAL) on /metrics
+	if err := prometheus.Register(storage.NewMetricsCollector(baseStore)); err != nil {
+		a.logger.Warn("Failed to register storage metrics", zap.Error(err))
+	}
+
 	// For multichain mode, use base storage directly
 	// For single chain mode, we'll wrap it with genesis initializer later
 	a.storage = baseStore
@@ -396,10 +540,23 @@ func (a *App) initStorageOnly(ctx context.Context) error {
 func (a *App) completeStorageInit(ctx context.Context) error {
 	// Wrap storage with genesis initializer (needs client)
 	if pebbleStore, ok := a.storage.(*storage.PebbleStorage); ok {
-		a.storage = storage.NewGenesisInitializingStorage(pebbleStore, a.client, a.logger)
+		var base storage.Storage = pebbleStore
+		if a.config.Database.ColdPath != "" {
+			tiered, err := a.initColdStorage(ctx, pebbleStore)
+			if err != nil {
+				return err
+			}
+			base = tiered
+		}
+		a.storage = storage.NewGenesisInitializingStorage(base, a.client, a.logger)
 		a.logger.Info("Storage wrapped with genesis auto-initialization")
 	}
 
+	// Store the chain ID so chain identity queries can be answered from storage
+	if err := a.storeChainID(ctx); err != nil {
+		a.logger.Warn("Failed to store chain ID", zap.Error(err))
+	}
+
 	// Initialize system contract verifications if enabled
 	if a.config.SystemContracts.Enabled && a.config.SystemContracts.SourcePath != "" {
 		if err := a.initSystemContractVerifications(ctx); err != nil {
@@ -424,6 +581,74 @@ func (a *App) completeStorageInit(ctx context.Context) error {
 	return nil
 }
 
+// initColdStorage opens the cold archive and serves the blocks below its
+// boundary from it, migrating aged blocks into it when an interval is set
+func (a *App) initColdStorage(ctx context.Context, hot *storage.PebbleStorage) (*storage.TieredStorage, error) {
+	dbCfg := a.config.Database
+	coldConfig := storage.DefaultConfig(dbCfg.ColdPath)
+	coldConfig.KeyNamespace = dbCfg.KeyNamespace
+
+	cold, err := storage.NewPebbleStorage(coldConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cold storage: %w", err)
+	}
+	cold.SetLogger(a.logger)
+
+	tiered, err := storage.OpenTieredStorage(ctx, hot, cold)
+	if err != nil {
+		cold.Close()
+		return nil, err
+	}
+	if dbCfg.ColdMigrationInterval > 0 {
+		tiered.StartMigration(cold, dbCfg.ColdRetainBlocks, dbCfg.ColdMigrationInterval)
+	}
+
+	a.logger.Info("Cold storage initialized",
+		zap.String("path", dbCfg.ColdPath),
+		zap.Uint64("boundary", tiered.Boundary()),
+		zap.Uint64("retain_blocks", dbCfg.ColdRetainBlocks),
+		zap.Duration("migration_interval", dbCfg.ColdMigrationInterval),
+	)
+	return tiered, nil
+}
+
+// storeChainID persists the chain ID reported by the RPC node if none is stored yet
+func (a *App) storeChainID(ctx context.Context) error {
+	reader, ok := a.storage.(storage.ChainIDReader)
+	if !ok {
+		return fmt.Errorf("storage does not support chain ID reads")
+	}
+	writer, ok := a.storage.(storage.ChainIDWriter)
+	if !ok {
+		return fmt.Errorf("storage does not support chain ID writes")
+	}
+
+	chainID, err := a.client.GetChainID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get chain ID: %w", err)
+	}
+
+	stored, err := reader.GetChainID(ctx)
+	if err == nil {
+		if stored.Cmp(chainID) != 0 {
+			a.logger.Warn("Stored chain ID differs from RPC node, keeping stored value",
+				zap.String("stored_chain_id", stored.String()),
+				zap.String("rpc_chain_id", chainID.String()),
+			)
+		}
+		return nil
+	}
+	if !errors.Is(err, storage.ErrNotFound) {
+		return fmt.Errorf("failed to read stored chain ID: %w", err)
+	}
+
+	if err := writer.SetChainID(ctx, chainID); err != nil {
+		return fmt.Errorf("failed to store chain ID: %w", err)
+	}
+	a.logger.Info("Stored chain ID", zap.String("chain_id", chainID.String()))
+	return nil
+}
+
 // initStorage initializes the storage layer (legacy method for compatibility)
 //
 //nolint:unused
@@ -458,12 +683,12 @@ func (a *App) initSystemContractVerifications(ctx context.Context) error {
 
 // initEventBus initializes the event bus
 func (a *App) initEventBus() {
-	a.eventBus = events.NewEventBus(constants.DefaultPublishBufferSize, constants.DefaultSubscribeBufferSize)
+	a.eventBus = events.NewEventBus(a.config.EventBus.PublishBufferSize, a.config.EventBus.SubscribeBufferSize)
 	go a.eventBus.Run()
 
 	a.logger.Info("EventBus initialized",
-		zap.Int("publish_buffer", constants.DefaultPublishBufferSize),
-		zap.Int("subscribe_buffer", constants.DefaultSubscribeBufferSize),
+		zap.Int("publish_buffer", a.eventBus.PublishBufferSize()),
+		zap.Int("subscribe_buffer", a.eventBus.SubscribeBufferSize()),
 	)
 }
 
@@ -602,7 +827,7 @@ func (a *App) initMultiChainManager(ctx context.Context) error {
 }
 
 // initFetcher initializes the block fetcher
-func (a *App) initFetcher() {
+func (a *App) initFetcher() error {
 	// Real-time mode: Use shorter RetryDelay for batch_size=1
 	retryDelay := time.Second * 5
 	if a.config.Indexer.ChunkSize == 1 {
@@ -616,6 +841,51 @@ func (a *App) initFetcher() {
 		RetryDelay:  retryDelay,
 		NumWorkers:  a.config.Indexer.Workers,
 	}
+	fetcherConfig.BatchAddressIndex = a.config.Indexer.BatchAddressIndex
+	fetcherConfig.IndexLogAddresses = a.config.Indexer.IndexLogAddresses
+	fetcherConfig.CommitBatchBlocks = a.config.Indexer.CommitBatchBlocks
+	fetcherConfig.CheckpointPath = a.config.Indexer.CheckpointPath
+	fetcherConfig.CheckpointInterval = a.config.Indexer.CheckpointInterval
+	fetcherConfig.SnapshotPath = a.config.Indexer.SnapshotPath
+	fetcherConfig.StartDelay = a.config.Indexer.StartDelay
+	fetcherConfig.MaxHead = a.config.Indexer.MaxHead
+	fetcherConfig.WaitForNodeSync = a.config.Indexer.WaitForNodeSync
+	fetcherConfig.VerifyOnIngest = a.config.Indexer.VerifyOnIngest
+	fetcherConfig.FailOnBlockNumberMismatch = a.config.Indexer.FailOnBlockNumberMismatch
+	fetcherConfig.SkipPoisonBlocks = a.config.Indexer.SkipPoisonBlocks
+	fetcherConfig.DetectReorgs = a.config.Indexer.DetectReorgs
+	fetcherConfig.MaxReorgDepth = a.config.Indexer.MaxReorgDepth
+	fetcherConfig.SeedOpeningBalances = a.config.Indexer.SeedOpeningBalances
+	fetcherConfig.TrackCoinbaseBalance = a.config.Indexer.CoinbaseBalance
+	blockReward, err := a.config.Indexer.BlockRewardAmount()
+	if err != nil {
+		return err
+	}
+	fetcherConfig.BlockReward = blockReward
+	fetcherConfig.DisabledSystemEvents, err = a.disabledSystemEvents()
+	if err != nil {
+		return err
+	}
+	for _, contract := range a.config.SystemContracts.AllowedContracts {
+		if !common.IsHexAddress(contract) {
+			return fmt.Errorf("invalid system_contracts.allowed_contracts: %q is not an address", contract)
+		}
+		fetcherConfig.AllowedSystemContracts = append(fetcherConfig.AllowedSystemContracts, common.HexToAddress(contract))
+	}
+	fetcherConfig.StrictProposalTransitions = a.config.SystemContracts.StrictProposalTransitions
+	fetcherConfig.GapRecovery = fetch.GapRecoveryConfig{
+		Workers:   a.config.Indexer.GapRecovery.Workers,
+		BatchSize: a.config.Indexer.GapRecovery.BatchSize,
+		RateLimit: a.config.Indexer.GapRecovery.RateLimit,
+	}
+
+	if adaptive := a.config.Indexer.AdaptiveWorkers; adaptive.Enabled {
+		optimizerConfig := fetch.DefaultOptimizerConfig()
+		optimizerConfig.MinWorkers = adaptive.MinWorkers
+		optimizerConfig.MaxWorkers = a.config.Indexer.AdaptiveMaxWorkers()
+		fetcherConfig.EnableAdaptiveOptimization = true
+		fetcherConfig.OptimizerConfig = optimizerConfig
+	}
 
 	// Create fetcher with chain adapter if available
 	if a.chainAdapter != nil {
@@ -634,6 +904,29 @@ func (a *App) initFetcher() {
 		)
 	}
 
+	// Export block check and fetcher state metrics on /metrics
+	if err := prometheus.Register(fetch.NewMetricsCollector(a.fetcher)); err != nil {
+		a.logger.Warn("Failed to register fetcher metrics", zap.Error(err))
+	}
+
+	// Index internal ETH transfers via block tracing (opt-in, needs debug namespace)
+	if a.config.Indexer.TraceInternalTransfers {
+		if writer, ok := a.storage.(storage.InternalTransferWriter); ok {
+			a.fetcher.SetInternalTransferProcessor(fetch.NewInternalTransferProcessor(a.logger, a.client, writer))
+		} else {
+			a.logger.Warn("Storage does not support internal transfer indexing - tracing disabled")
+		}
+	}
+
+	// Index revert reasons of failed transactions (opt-in, one trace per failed tx)
+	if a.config.Indexer.RevertReasons {
+		if writer, ok := a.storage.(storage.RevertReasonWriter); ok {
+			a.fetcher.SetRevertReasonProcessor(fetch.NewRevertReasonProcessor(a.logger, a.client, writer))
+		} else {
+			a.logger.Warn("Storage does not support revert reason indexing - disabled")
+		}
+	}
+
 	// Add token block processor for automatic token metadata indexing
 	tokenProcessor := token.NewBlockProcessorFromEthClient(a.client.EthClient(), a.storage, a.logger)
 	a.fetcher.AddBlockProcessor(tokenProcessor)
@@ -648,6 +941,21 @@ func (a *App) initFetcher() {
 	} else {
 		a.logger.Warn("Failed to create token metadata fetcher - on-demand fetching will be disabled")
 	}
+
+	return nil
+}
+
+// disabledSystemEvents parses system_contracts.disabled_events
+func (a *App) disabledSystemEvents() ([]events.SystemEventCategory, error) {
+	var categories []events.SystemEventCategory
+	for _, name := range a.config.SystemContracts.DisabledEvents {
+		category, err := events.ParseSystemEventCategory(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid system_contracts.disabled_events: %w", err)
+		}
+		categories = append(categories, category)
+	}
+	return categories, nil
 }
 
 // initAPIServer initializes the API server
@@ -665,29 +973,63 @@ func (a *App) initAPIServer() error {
 	}
 
 	apiConfig := &api.Config{
-		Host:                  a.config.API.Host,
-		Port:                  a.config.API.Port,
-		ReadTimeout:           constants.DefaultReadTimeout,
-		WriteTimeout:          constants.DefaultWriteTimeout,
-		IdleTimeout:           constants.DefaultIdleTimeout,
-		EnableCORS:            a.config.API.EnableCORS,
-		AllowedOrigins:        a.config.API.AllowedOrigins,
-		MaxHeaderBytes:        constants.DefaultMaxHeaderBytes,
-		EnableGraphQL:         a.config.API.EnableGraphQL,
-		EnableJSONRPC:         a.config.API.EnableJSONRPC,
-		EnableWebSocket:       a.config.API.EnableWebSocket,
-		GraphQLPath:           constants.DefaultGraphQLPath,
-		GraphQLPlaygroundPath: constants.DefaultGraphQLPlaygroundPath,
-		JSONRPCPath:           constants.DefaultJSONRPCPath,
-		WebSocketPath:         constants.DefaultWebSocketPath,
-		ShutdownTimeout:       constants.DefaultShutdownTimeout,
+		Host:                     a.config.API.Host,
+		Port:                     a.config.API.Port,
+		ReadTimeout:              constants.DefaultReadTimeout,
+		WriteTimeout:             constants.DefaultWriteTimeout,
+		IdleTimeout:              constants.DefaultIdleTimeout,
+		EnableCORS:               a.config.API.EnableCORS,
+		AllowedOrigins:           a.config.API.AllowedOrigins,
+		MaxHeaderBytes:           constants.DefaultMaxHeaderBytes,
+		RequestTimeout:           a.config.API.RequestTimeout,
+		MaxRequestBytes:          a.config.API.MaxRequestBytes,
+		GraphQLMaxDepth:          a.config.API.GraphQLMaxDepth,
+		GraphQLMaxComplexity:     a.config.API.GraphQLMaxComplexity,
+		MaxConcurrentConnections: a.config.API.MaxConcurrentConnections,
+		JSONRPCAllowedMethods:    a.config.API.JSONRPCAllowedMethods,
+		JSONRPCDeniedMethods:     a.config.API.JSONRPCDeniedMethods,
+		MaskedFields:             a.config.API.MaskedFields,
+		MaskedAddresses:          a.config.API.MaskedAddresses,
+		OmitMaskedFields:         a.config.API.OmitMaskedFields,
+		EnableGraphQL:            a.config.API.EnableGraphQL,
+		EnableJSONRPC:            a.config.API.EnableJSONRPC,
+		EnableWebSocket:          a.config.API.EnableWebSocket,
+		EnableWebSocketKeepAlive: a.config.API.EnableWebSocketKeepAlive,
+		WebSocketPingInterval:    a.config.API.WebSocketPingInterval,
+		WebSocketIdleTimeout:     a.config.API.WebSocketIdleTimeout,
+		EnableREST:               a.config.API.EnableREST,
+		EnableAdminAPI:           a.config.API.EnableAdmin,
+		AdminAPIKeys:             adminAPIKeys(a.config.API.AdminKeys),
+		GraphQLPath:              constants.DefaultGraphQLPath,
+		GraphQLPlaygroundPath:    constants.DefaultGraphQLPlaygroundPath,
+		JSONRPCPath:              constants.DefaultJSONRPCPath,
+		WebSocketPath:            constants.DefaultWebSocketPath,
+		ShutdownTimeout:          a.shutdownTimeout(),
+		TLSCertFile:              a.config.API.TLSCertFile,
+		TLSKeyFile:               a.config.API.TLSKeyFile,
+		TLSRedirectPort:          a.config.API.TLSRedirectPort,
 	}
 
 	// Create API server with optional RPC Proxy, Notification Service, and Verifier
+	disabledEvents, err := a.disabledSystemEvents()
+	if err != nil {
+		return err
+	}
 	serverOpts := &api.ServerOptions{
 		RPCProxy:            a.rpcProxy,
 		NotificationService: a.notificationService,
 		Verifier:            a.contractVerifier,
+		Info: &api.InfoOptions{
+			Version:                 version,
+			Commit:                  commit,
+			BuildTime:               buildTime,
+			StartHeight:             a.config.Indexer.StartHeight,
+			DisabledEventCategories: disabledEvents,
+		},
+	}
+	// The fetcher is nil in multi-chain mode, where chains are managed separately
+	if a.fetcher != nil {
+		serverOpts.IndexingController = a.fetcher
 	}
 	apiServer, err := api.NewServerWithOptions(apiConfig, a.logger, a.storage, serverOpts)
 	if err != nil {
@@ -709,6 +1051,9 @@ func (a *App) initAPIServer() error {
 		zap.Bool("graphql", apiConfig.EnableGraphQL),
 		zap.Bool("jsonrpc", apiConfig.EnableJSONRPC),
 		zap.Bool("websocket", apiConfig.EnableWebSocket),
+		zap.Bool("rest", apiConfig.EnableREST),
+		zap.Bool("admin", apiConfig.EnableAdminAPI),
+		zap.Bool("tls", apiConfig.TLSEnabled()),
 		zap.Bool("rpc_proxy", a.rpcProxy != nil),
 		zap.Bool("notifications", a.notificationService != nil),
 		zap.Bool("verifier", a.contractVerifier != nil),
@@ -816,6 +1161,25 @@ func (a *App) Run(ctx context.Context) error {
 		return ctx.Err()
 	}
 
+	// Forward the node's pending transactions to the EventBus (opt-in, needs eth_subscribe)
+	if a.config.Indexer.PendingTransactions {
+		if _, err := a.fetcher.StartPendingTxSubscription(ctx); err != nil {
+			a.logger.Warn("Pending transaction subscription disabled", zap.Error(err))
+		}
+	}
+
+	// Seed state for blocks below the start height before indexing builds on it
+	if err := a.fetcher.ApplyStateSnapshot(ctx); err != nil {
+		return err
+	}
+
+	// Rebuild system contract event indexes from already stored receipts
+	if a.reindexEvents {
+		if err := a.reindexSystemEvents(ctx); err != nil {
+			return fmt.Errorf("failed to reindex system contract events: %w", err)
+		}
+	}
+
 	// Single-chain mode (legacy)
 	if a.enableGapMode {
 		a.logger.Info("Starting with gap recovery enabled")
@@ -826,13 +1190,83 @@ func (a *App) Run(ctx context.Context) error {
 	return a.fetcher.Run(ctx)
 }
 
+// reindexSystemEvents replays every stored receipt up to the indexed head
+// through the system contract event parser
+func (a *App) reindexSystemEvents(ctx context.Context) error {
+	latest, err := a.storage.GetLatestHeight(ctx)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			a.logger.Info("No indexed blocks, skipping system contract event reindex")
+			return nil
+		}
+		return fmt.Errorf("failed to get latest height: %w", err)
+	}
+
+	return a.fetcher.ReindexSystemEvents(ctx, 0, latest)
+}
+
+// repairReceipts re-fetches the missing receipts of stored blocks in
+// [from, to]. A zero to means the latest indexed height.
+func (a *App) repairReceipts(ctx context.Context, from, to uint64) error {
+	if a.fetcher == nil {
+		return fmt.Errorf("receipt repair requires single-chain mode")
+	}
+
+	if to == 0 {
+		latest, err := a.storage.GetLatestHeight(ctx)
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				a.logger.Info("No indexed blocks, skipping receipt repair")
+				return nil
+			}
+			return fmt.Errorf("failed to get latest height: %w", err)
+		}
+		to = latest
+	}
+
+	result, err := a.fetcher.RepairReceipts(ctx, from, to)
+	if err != nil {
+		return fmt.Errorf("failed to repair receipts: %w", err)
+	}
+	if result.ReceiptsUnavailable > 0 {
+		a.logger.Warn("Some missing receipts could not be fetched",
+			zap.Int("receipts_unavailable", result.ReceiptsUnavailable),
+		)
+	}
+	return nil
+}
+
+// retryFailedBlocks re-attempts the blocks recorded in the dead-letter store
+func (a *App) retryFailedBlocks(ctx context.Context) error {
+	if a.fetcher == nil {
+		return fmt.Errorf("failed block retry requires single-chain mode")
+	}
+
+	result, err := a.fetcher.RetryFailedBlocks(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retry failed blocks: %w", err)
+	}
+	if result.StillFailing > 0 {
+		a.logger.Warn("Some failed blocks could not be indexed",
+			zap.Int("still_failing", result.StillFailing),
+		)
+	}
+	return nil
+}
+
 // Shutdown gracefully shuts down all application components
 func (a *App) Shutdown() {
 	a.logger.Info("Shutting down application components...")
 
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	timeout := a.shutdownTimeout()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
+	// Let in-flight writes finish before storage is synced and closed
+	if err := waitGroupContext(shutdownCtx, &a.running); err != nil {
+		a.logger.Warn("Timed out waiting for indexing to stop", zap.Duration("timeout", timeout))
+	}
+
 	// Stop notification service
 	if a.notificationService != nil {
 		if err := a.notificationService.Stop(shutdownCtx); err != nil {
@@ -872,9 +1306,11 @@ func (a *App) Shutdown() {
 		a.logger.Info("Multi-chain manager stopped")
 	}
 
-	// Stop EventBus
+	// Stop EventBus, delivering events published before shutdown
 	if a.eventBus != nil {
-		a.eventBus.Stop()
+		if err := a.eventBus.StopContext(shutdownCtx); err != nil {
+			a.logger.Warn("Timed out waiting for EventBus to drain", zap.Duration("timeout", timeout))
+		}
 	}
 
 	// Close chain adapter (single-chain mode only)
@@ -884,8 +1320,13 @@ func (a *App) Shutdown() {
 		}
 	}
 
-	// Close storage
+	// Close storage, flushing the NoSync writes of recently indexed blocks first
 	if a.storage != nil {
+		if syncer, ok := a.storage.(storage.Syncer); ok {
+			if err := syncer.Sync(); err != nil {
+				a.logger.Error("Failed to sync storage", zap.Error(err))
+			}
+		}
 		if err := a.storage.Close(); err != nil {
 			a.logger.Error("Failed to close storage", zap.Error(err))
 		}
@@ -896,9 +1337,6 @@ func (a *App) Shutdown() {
 		a.client.Close()
 	}
 
-	// Wait for graceful shutdown
-	time.Sleep(time.Second * 2)
-
 	// Log final statistics
 	ctx := context.Background()
 	if a.multichainManager != nil {
@@ -925,6 +1363,39 @@ func (a *App) Shutdown() {
 	a.logger.Info("Application stopped")
 }
 
+// adminAPIKeys labels the configured admin API keys by position for logging
+func adminAPIKeys(keys []string) map[string]string {
+	labeled := make(map[string]string, len(keys))
+	for i, key := range keys {
+		labeled[key] = fmt.Sprintf("admin-%d", i+1)
+	}
+	return labeled
+}
+
+// shutdownTimeout returns how long Shutdown waits for components to stop
+func (a *App) shutdownTimeout() time.Duration {
+	if a.config != nil && a.config.Indexer.ShutdownTimeout > 0 {
+		return a.config.Indexer.ShutdownTimeout
+	}
+	return constants.DefaultShutdownTimeout
+}
+
+// waitGroupContext waits for wg, returning ctx's error if ctx is done first
+func waitGroupContext(ctx context.Context, wg *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // loadConfig loads configuration from YAML file
 func loadConfig(configFile string) (*config.Config, error) {
 	cfg, err := config.Load(configFile)
@@ -982,6 +1453,16 @@ func applyAPIFlags(cfg *config.Config, enableAPI bool, apiHost string, apiPort i
 	}
 }
 
+// applyEventBusFlags applies EventBus buffer size flags to configuration
+func applyEventBusFlags(cfg *config.Config, publishBuffer, subscribeBuffer int) {
+	if publishBuffer != 0 {
+		cfg.EventBus.PublishBufferSize = publishBuffer
+	}
+	if subscribeBuffer != 0 {
+		cfg.EventBus.SubscribeBufferSize = subscribeBuffer
+	}
+}
+
 // validateConfig validates the configuration
 func validateConfig(cfg *config.Config) error {
 	if cfg.RPC.Endpoint == "" {
@@ -996,13 +1477,20 @@ func validateConfig(cfg *config.Config) error {
 	if cfg.Indexer.ChunkSize <= 0 {
 		return fmt.Errorf("batch size must be positive")
 	}
+	if cfg.EventBus.PublishBufferSize <= 0 {
+		return fmt.Errorf("eventbus publish buffer size must be positive")
+	}
+	if cfg.EventBus.SubscribeBufferSize <= 0 {
+		return fmt.Errorf("eventbus subscribe buffer size must be positive")
+	}
 	return nil
 }
 
-// initLogger initializes the logger based on configuration
-func initLogger(level, format string) (*zap.Logger, error) {
+// initLogger initializes the logger based on configuration.
+// The returned AtomicLevel allows the level to be changed at runtime.
+func initLogger(level, format string) (*zap.Logger, zap.AtomicLevel, error) {
 	if format == "json" || format == "production" {
-		return logger.NewProduction()
+		return logger.NewProductionWithLevel(level)
 	}
 
 	// Default to development logger
@@ -1011,7 +1499,7 @@ func initLogger(level, format string) (*zap.Logger, error) {
 		Encoding:    "console",
 		Development: true,
 	}
-	return logger.NewWithConfig(&cfg)
+	return logger.NewWithConfigAndLevel(&cfg)
 }
 
 // clearDataFolder removes the data folder and all its contents
@@ -1096,6 +1584,7 @@ func reindexData(path string, log *zap.Logger) error {
 		"/index/addr/",
 		"/index/blockh/",
 		"/index/time/",
+		"/index/blocktime/",
 		"/index/balance/",
 		"/index/syscontracts/",
 		"/index/wbft/",
@@ -1152,3 +1641,46 @@ func reindexData(path string, log *zap.Logger) error {
 
 	return nil
 }
+
+// verifyStorage checks the stored blocks in [from, to] (to 0 = latest indexed
+// height) and writes the report to out. It fails when an inconsistency is found.
+func verifyStorage(ctx context.Context, path string, from, to uint64, out io.Writer) error {
+	storageConfig := storage.DefaultConfig(path)
+	storageConfig.ReadOnly = true
+	db, err := storage.NewPebbleStorage(storageConfig)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	if to == 0 {
+		latest, err := db.GetLatestHeight(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get latest height (set --verify-to): %w", err)
+		}
+		to = latest
+	}
+
+	report, err := db.Verify(ctx, from, to)
+	if err != nil {
+		return fmt.Errorf("failed to verify storage: %w", err)
+	}
+
+	fmt.Fprintf(out, "Verified blocks %d-%d\n", report.From, report.To)
+	fmt.Fprintf(out, "  blocks checked:       %d\n", report.BlocksChecked)
+	fmt.Fprintf(out, "  transactions checked: %d\n", report.TransactionsChecked)
+	fmt.Fprintf(out, "  missing blocks:       %d\n", report.MissingBlocks)
+	fmt.Fprintf(out, "  issues:               %d\n", len(report.Issues))
+	for _, issue := range report.Issues {
+		if issue.TxHash != (common.Hash{}) {
+			fmt.Fprintf(out, "  [%s] block %d tx %s: %s\n", issue.Kind, issue.Height, issue.TxHash.Hex(), issue.Detail)
+		} else {
+			fmt.Fprintf(out, "  [%s] block %d: %s\n", issue.Kind, issue.Height, issue.Detail)
+		}
+	}
+
+	if !report.OK() {
+		return fmt.Errorf("storage verification found %d issues", len(report.Issues))
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+
+	"github.com/0xmhha/indexer-go/internal/config"
+	"github.com/0xmhha/indexer-go/pkg/events"
+	"github.com/0xmhha/indexer-go/pkg/storage"
+)
+
+// recordingStorage records the order of Sync and Close calls
+type recordingStorage struct {
+	storage.Storage
+	calls []string
+}
+
+func (s *recordingStorage) Sync() error {
+	s.calls = append(s.calls, "sync")
+	return s.Storage.(storage.Syncer).Sync()
+}
+
+func (s *recordingStorage) Close() error {
+	s.calls = append(s.calls, "close")
+	return s.Storage.Close()
+}
+
+func TestAppShutdown_SyncsStorage(t *testing.T) {
+	dir := t.TempDir()
+	store, err := storage.NewPebbleStorage(storage.DefaultConfig(dir))
+	if err != nil {
+		t.Fatalf("NewPebbleStorage() error = %v", err)
+	}
+
+	// Blocks and the latest height are written with NoSync
+	ctx := context.Background()
+	for height := uint64(1); height <= 3; height++ {
+		block := types.NewBlockWithHeader(&types.Header{
+			Number:     new(big.Int).SetUint64(height),
+			Difficulty: big.NewInt(1),
+		})
+		if err := store.SetBlock(ctx, block); err != nil {
+			t.Fatalf("SetBlock(%d) error = %v", height, err)
+		}
+		if err := store.SetLatestHeight(ctx, height); err != nil {
+			t.Fatalf("SetLatestHeight(%d) error = %v", height, err)
+		}
+	}
+
+	recorder := &recordingStorage{Storage: store}
+	app := &App{storage: recorder, logger: zap.NewNop()}
+	app.Shutdown()
+
+	if len(recorder.calls) != 2 || recorder.calls[0] != "sync" || recorder.calls[1] != "close" {
+		t.Fatalf("storage calls = %v, want [sync close]", recorder.calls)
+	}
+
+	reopened, err := storage.NewPebbleStorage(storage.DefaultConfig(dir))
+	if err != nil {
+		t.Fatalf("NewPebbleStorage() reopen error = %v", err)
+	}
+	defer reopened.Close()
+
+	height, err := reopened.GetLatestHeight(ctx)
+	if err != nil {
+		t.Fatalf("GetLatestHeight() error = %v", err)
+	}
+	if height != 3 {
+		t.Errorf("latest height after reopen = %d, want 3", height)
+	}
+	if _, err := reopened.GetBlock(ctx, 3); err != nil {
+		t.Errorf("GetBlock(3) after reopen error = %v", err)
+	}
+}
+
+func TestAppShutdown_ReturnsWhenIndexingStops(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Indexer.ShutdownTimeout = 10 * time.Second
+	app := &App{config: cfg, logger: zap.NewNop(), eventBus: events.NewEventBus(10, 10)}
+	go app.eventBus.Run()
+
+	app.running.Add(1)
+	go func() {
+		defer app.running.Done()
+		time.Sleep(50 * time.Millisecond)
+	}()
+
+	start := time.Now()
+	app.Shutdown()
+	elapsed := time.Since(start)
+
+	if elapsed < 50*time.Millisecond {
+		t.Errorf("Shutdown() returned after %v, before indexing stopped", elapsed)
+	}
+	if elapsed > 2*time.Second {
+		t.Errorf("Shutdown() took %v, want it to return once indexing stopped", elapsed)
+	}
+}
+
+func TestAppShutdown_BoundedByTimeout(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Indexer.ShutdownTimeout = 100 * time.Millisecond
+
+	// Neither the indexing loop nor the EventBus (never run) stops
+	app := &App{config: cfg, logger: zap.NewNop(), eventBus: events.NewEventBus(10, 10)}
+	app.running.Add(1)
+	defer app.running.Done()
+
+	start := time.Now()
+	app.Shutdown()
+	elapsed := time.Since(start)
+
+	if elapsed < 100*time.Millisecond {
+		t.Errorf("Shutdown() returned after %v, before the timeout", elapsed)
+	}
+	if elapsed > 2*time.Second {
+		t.Errorf("Shutdown() took %v, want it bounded by the 100ms timeout", elapsed)
+	}
+}
+
+func TestVerifyStorage(t *testing.T) {
+	dir := t.TempDir()
+	store, err := storage.NewPebbleStorage(storage.DefaultConfig(dir))
+	if err != nil {
+		t.Fatalf("NewPebbleStorage() error = %v", err)
+	}
+
+	ctx := context.Background()
+	for height := uint64(1); height <= 3; height++ {
+		block := types.NewBlockWithHeader(&types.Header{
+			Number:     new(big.Int).SetUint64(height),
+			Difficulty: big.NewInt(1),
+		})
+		if err := store.SetBlock(ctx, block); err != nil {
+			t.Fatalf("SetBlock(%d) error = %v", height, err)
+		}
+	}
+	if err := store.SetLatestHeight(ctx, 2); err != nil {
+		t.Fatalf("SetLatestHeight() error = %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// Up to the latest height everything is consistent
+	var out bytes.Buffer
+	if err := verifyStorage(ctx, dir, 1, 0, &out); err != nil {
+		t.Fatalf("verifyStorage() error = %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "Verified blocks 1-2") {
+		t.Errorf("report = %q, want range 1-2", out.String())
+	}
+
+	// Block 3 is stored beyond the latest height
+	out.Reset()
+	if err := verifyStorage(ctx, dir, 1, 3, &out); err == nil {
+		t.Fatal("verifyStorage() should fail when block 3 is above the latest height")
+	}
+	if !strings.Contains(out.String(), "[latest_height] block 3") {
+		t.Errorf("report = %q, want latest_height issue for block 3", out.String())
+	}
+}
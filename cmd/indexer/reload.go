@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/0xmhha/indexer-go/internal/logger"
+	"go.uber.org/zap"
+)
+
+// watchLogLevelReload reloads the log level each time a signal arrives on sigChan
+// until ctx is cancelled
+func watchLogLevelReload(ctx context.Context, sigChan <-chan os.Signal, flags *Flags, format string, level zap.AtomicLevel, log *zap.Logger) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case sig := <-sigChan:
+			log.Info("Received reload signal", zap.String("signal", sig.String()))
+			if err := reloadLogLevel(flags, format, level, log); err != nil {
+				log.Error("Failed to reload log level", zap.Error(err))
+			}
+		}
+	}
+}
+
+// reloadLogLevel re-reads the config file and applies its log level to level.
+// Log flags keep precedence over the file, as they do at startup.
+// The log format cannot change at runtime; a differing format is only reported.
+func reloadLogLevel(flags *Flags, format string, level zap.AtomicLevel, log *zap.Logger) error {
+	cfg, err := loadConfig(flags.configFile)
+	if err != nil {
+		return err
+	}
+	applyFlags(cfg, "", "", 0, 0, 0, flags.logLevel, flags.logFormat)
+
+	oldLevel := level.Level()
+	if err := logger.SetLevel(level, cfg.Log.Level); err != nil {
+		return fmt.Errorf("failed to apply log level: %w", err)
+	}
+
+	if cfg.Log.Format != format {
+		log.Warn("Log format change requires a restart",
+			zap.String("current", format),
+			zap.String("configured", cfg.Log.Format),
+		)
+	}
+
+	log.Info("Log level reloaded",
+		zap.String("from", oldLevel.String()),
+		zap.String("to", level.Level().String()),
+	)
+	return nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// writeLogConfig writes a minimal config file with the given log level
+func writeLogConfig(t *testing.T, path, level string) {
+	t.Helper()
+	content := "rpc:\n  endpoint: \"http://localhost:8545\"\ndatabase:\n  path: \"./data\"\nlog:\n  level: " + level + "\n  format: json\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+}
+
+func TestReloadLogLevel(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+
+	t.Run("applies level from file", func(t *testing.T) {
+		writeLogConfig(t, path, "info")
+		level := zap.NewAtomicLevelAt(zapcore.InfoLevel)
+
+		writeLogConfig(t, path, "debug")
+		if err := reloadLogLevel(&Flags{configFile: path}, "json", level, zap.NewNop()); err != nil {
+			t.Fatalf("reloadLogLevel() error = %v", err)
+		}
+		if level.Level() != zapcore.DebugLevel {
+			t.Errorf("level = %s, want debug", level.Level())
+		}
+
+		writeLogConfig(t, path, "error")
+		if err := reloadLogLevel(&Flags{configFile: path}, "json", level, zap.NewNop()); err != nil {
+			t.Fatalf("reloadLogLevel() error = %v", err)
+		}
+		if level.Level() != zapcore.ErrorLevel {
+			t.Errorf("level = %s, want error", level.Level())
+		}
+	})
+
+	t.Run("flag takes precedence", func(t *testing.T) {
+		writeLogConfig(t, path, "debug")
+		level := zap.NewAtomicLevelAt(zapcore.InfoLevel)
+
+		if err := reloadLogLevel(&Flags{configFile: path, logLevel: "warn"}, "json", level, zap.NewNop()); err != nil {
+			t.Fatalf("reloadLogLevel() error = %v", err)
+		}
+		if level.Level() != zapcore.WarnLevel {
+			t.Errorf("level = %s, want warn", level.Level())
+		}
+	})
+
+	t.Run("invalid level keeps current", func(t *testing.T) {
+		writeLogConfig(t, path, "verbose")
+		level := zap.NewAtomicLevelAt(zapcore.InfoLevel)
+
+		if err := reloadLogLevel(&Flags{configFile: path}, "json", level, zap.NewNop()); err == nil {
+			t.Error("reloadLogLevel() should fail for an invalid level")
+		}
+		if level.Level() != zapcore.InfoLevel {
+			t.Errorf("level = %s, want info", level.Level())
+		}
+	})
+
+	t.Run("missing file keeps current", func(t *testing.T) {
+		level := zap.NewAtomicLevelAt(zapcore.InfoLevel)
+
+		if err := reloadLogLevel(&Flags{configFile: filepath.Join(t.TempDir(), "missing.yaml")}, "json", level, zap.NewNop()); err == nil {
+			t.Error("reloadLogLevel() should fail when the config file is missing")
+		}
+		if level.Level() != zapcore.InfoLevel {
+			t.Errorf("level = %s, want info", level.Level())
+		}
+	})
+}
@@ -2,7 +2,9 @@ package config
 
 import (
 	"fmt"
+	"math/big"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -31,14 +33,78 @@ type Config struct {
 
 // RPCConfig holds RPC client configuration
 type RPCConfig struct {
-	Endpoint string        `yaml:"endpoint"`
-	Timeout  time.Duration `yaml:"timeout"`
+	Endpoint string         `yaml:"endpoint"`
+	Timeout  time.Duration  `yaml:"timeout"`
+	Retry    RPCRetryConfig `yaml:"retry"`
+	// MethodTimeouts overrides Timeout for individual JSON-RPC methods,
+	// e.g. debug_traceBlockByNumber: 5m
+	MethodTimeouts map[string]time.Duration `yaml:"method_timeouts"`
+}
+
+// RPCRetryConfig holds retry behavior for transient RPC failures
+type RPCRetryConfig struct {
+	// MaxRetries is the number of retries per call after the first attempt
+	// (0 selects the default, negative sends each call once)
+	MaxRetries int `yaml:"max_retries"`
+	// BaseDelay is the delay before the first retry
+	BaseDelay time.Duration `yaml:"base_delay"`
+	// MaxDelay caps the delay between retries
+	MaxDelay time.Duration `yaml:"max_delay"`
+	// Multiplier for exponential backoff (0 or 1 keeps the delay constant)
+	Multiplier float64 `yaml:"multiplier"`
+	// Jitter is the randomized fraction of each delay (0.0-1.0, 0 disables jitter)
+	Jitter float64 `yaml:"jitter"`
 }
 
 // DatabaseConfig holds database configuration
 type DatabaseConfig struct {
 	Path     string `yaml:"path"`
 	ReadOnly bool   `yaml:"readonly"`
+	// CacheMB overrides the block cache size in MB (0 = derived from memory)
+	CacheMB int `yaml:"cache_mb"`
+	// WriteBufferMB overrides the memtable size in MB (0 = derived from memory)
+	WriteBufferMB int `yaml:"write_buffer_mb"`
+	// WriteBufferCount is the number of memtables queued before writes stall (0 = default)
+	WriteBufferCount int `yaml:"write_buffer_count"`
+	// BloomFilterBitsPerKey sizes the SSTable bloom filters used by point lookups (0 = default 10, -1 = disabled)
+	BloomFilterBitsPerKey int `yaml:"bloom_filter_bits_per_key"`
+	// MemoryFraction is the fraction of system memory used to derive cache and memtable sizes
+	MemoryFraction float64 `yaml:"memory_fraction"`
+	// MemoryBudgetMB caps write_buffer_mb * write_buffer_count (0 = unlimited)
+	MemoryBudgetMB int `yaml:"memory_budget_mb"`
+	// LogAddressTopicIndex maintains an extra (address, topic0) log index, roughly doubling log storage
+	LogAddressTopicIndex bool `yaml:"log_address_topic_index"`
+	// IndexUncles stores the uncle (ommer) headers of each block for uncle listings
+	IndexUncles bool `yaml:"index_uncles"`
+	// ReadCacheSize is the number of decoded blocks and receipts kept in memory (0 = disabled, ignored when readonly)
+	ReadCacheSize int `yaml:"read_cache_size"`
+	// InitTransactionCount rebuilds a missing transaction count on startup by scanning every block
+	InitTransactionCount bool `yaml:"init_transaction_count"`
+	// ScanWorkers is the number of goroutines used by full-chain analytics scans (0 = number of CPUs, 1 = serial)
+	ScanWorkers int `yaml:"scan_workers"`
+	// CompactionInterval is the time between scheduled full compactions (0 = disabled)
+	CompactionInterval time.Duration `yaml:"compaction_interval"`
+	// CompactionTime is the local "HH:MM" time of the first scheduled compaction (empty = one interval after start)
+	CompactionTime string `yaml:"compaction_time"`
+	// KeepOrphanBlocks keeps blocks removed by a reorg rollback in an orphan store instead of deleting them
+	KeepOrphanBlocks bool `yaml:"keep_orphan_blocks"`
+	// KeyNamespace prefixes every storage key so several chains' data can live in one database directory;
+	// the process reads and writes only this namespace (e.g. the chain ID; empty = no prefix)
+	KeyNamespace string `yaml:"key_namespace"`
+	// MaxOpenIterators reports when more storage iterators are open at once, catching leaks (0 = disabled)
+	MaxOpenIterators int `yaml:"max_open_iterators"`
+	// PanicOnIteratorLeak panics instead of logging when max_open_iterators is exceeded
+	PanicOnIteratorLeak bool `yaml:"panic_on_iterator_leak"`
+	// ReplicaRefreshInterval serves a database another process writes to from snapshots refreshed this often (0 = disabled, requires readonly)
+	ReplicaRefreshInterval time.Duration `yaml:"replica_refresh_interval"`
+	// ReplicaSnapshotDir is where a read replica keeps its snapshots (empty = parent directory of path)
+	ReplicaSnapshotDir string `yaml:"replica_snapshot_dir"`
+	// ColdPath is an archive database that old blocks migrate to and are then served from (empty = disabled, single-chain mode only)
+	ColdPath string `yaml:"cold_path"`
+	// ColdRetainBlocks is the number of recent blocks kept in the hot database when cold_path is set
+	ColdRetainBlocks uint64 `yaml:"cold_retain_blocks"`
+	// ColdMigrationInterval is the time between migrations of aged blocks to cold_path (0 = no migration, archived blocks are still served)
+	ColdMigrationInterval time.Duration `yaml:"cold_migration_interval"`
 }
 
 // SystemContractsConfig holds system contracts verification configuration
@@ -50,6 +116,16 @@ type SystemContractsConfig struct {
 	SourcePath string `yaml:"source_path"`
 	// IncludeAbstracts determines whether to include abstract contracts in the source code
 	IncludeAbstracts bool `yaml:"include_abstracts"`
+	// DisabledEvents lists system contract event categories that are not indexed
+	// (mint, burn, minter, proposal, vote, member, validator, gas_tip, blacklist,
+	// authorized_account, emergency_pause). Indexing is independent of Enabled.
+	DisabledEvents []string `yaml:"disabled_events"`
+	// AllowedContracts restricts event indexing to logs from these contract
+	// addresses. Empty indexes every system contract.
+	AllowedContracts []string `yaml:"allowed_contracts"`
+	// StrictProposalTransitions reports proposal events with an invalid status
+	// transition as errors instead of skipping them with a warning
+	StrictProposalTransitions bool `yaml:"strict_proposal_transitions"`
 }
 
 // LogConfig holds logging configuration
@@ -60,9 +136,117 @@ type LogConfig struct {
 
 // IndexerConfig holds indexer-specific configuration
 type IndexerConfig struct {
-	Workers     int    `yaml:"workers"`
-	ChunkSize   int    `yaml:"chunk_size"`
-	StartHeight uint64 `yaml:"start_height"`
+	Workers         int                   `yaml:"workers"`
+	ChunkSize       int                   `yaml:"chunk_size"`
+	StartHeight     uint64                `yaml:"start_height"`
+	AdaptiveWorkers AdaptiveWorkersConfig `yaml:"adaptive_workers"`
+	// GapRecovery throttles filling gaps (--gap-recovery) independently of steady-state indexing
+	GapRecovery GapRecoveryConfig `yaml:"gap_recovery"`
+	// TraceInternalTransfers indexes ETH transfers inside contract calls using
+	// debug_traceBlockByNumber. Requires a node with the debug namespace enabled.
+	TraceInternalTransfers bool `yaml:"trace_internal_transfers"`
+	// RevertReasons traces each failed transaction with debug_traceTransaction
+	// and stores its revert reason. Costs one extra RPC call per failed transaction.
+	RevertReasons bool `yaml:"revert_reasons"`
+	// PendingTransactions subscribes to the node's newPendingTransactions feed
+	// and publishes pending transactions to the EventBus. Requires a WebSocket RPC endpoint.
+	PendingTransactions bool `yaml:"pending_transactions"`
+	// BatchAddressIndex writes transaction address index entries in the same
+	// batch as each block, reducing per-entry lock and write overhead during sync
+	BatchAddressIndex bool `yaml:"batch_address_index"`
+	// IndexLogAddresses also indexes transactions under the addresses in their
+	// logs (emitting contracts, token Transfer senders and recipients)
+	IndexLogAddresses bool `yaml:"index_log_addresses"`
+	// CommitBatchBlocks is the number of consecutive blocks committed to storage
+	// in one batch with a single sync (0 or 1: every block on its own)
+	CommitBatchBlocks int `yaml:"commit_batch_blocks"`
+	// CheckpointPath is a file the fetcher periodically fsyncs its progress to,
+	// so heights lost from unsynced storage writes are re-fetched after a crash
+	CheckpointPath string `yaml:"checkpoint_path"`
+	// CheckpointInterval is the minimum time between checkpoint writes (default: 10s)
+	CheckpointInterval time.Duration `yaml:"checkpoint_interval"`
+	// SnapshotPath is a JSON state snapshot as of block start_height-1, seeding
+	// balances, total supply and active minters for blocks that are not indexed
+	SnapshotPath string `yaml:"snapshot_path"`
+	// StartDelay is how long the fetcher waits before fetching the first block
+	StartDelay time.Duration `yaml:"start_delay"`
+	// ShutdownTimeout bounds how long shutdown waits for the fetcher to stop
+	// and the EventBus to drain before closing storage (default: 30s)
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	// MaxHead caps indexing at min(chain head, max_head); 0 disables the cap
+	MaxHead uint64 `yaml:"max_head"`
+	// WaitForNodeSync pauses the fetcher while the node reports it is still
+	// syncing (eth_syncing), instead of retrying heights it cannot serve yet
+	WaitForNodeSync bool `yaml:"wait_for_node_sync"`
+	// VerifyOnIngest recomputes each fetched block's transactions and receipts
+	// roots and dead-letters blocks that do not match their header
+	VerifyOnIngest bool `yaml:"verify_on_ingest"`
+	// FailOnBlockNumberMismatch fails a fetch that returns a block of another
+	// height at once instead of retrying it, for a single RPC node where every
+	// retry reaches the same node
+	FailOnBlockNumberMismatch bool `yaml:"fail_on_block_number_mismatch"`
+	// SkipPoisonBlocks dead-letters a block whose data fails to encode or
+	// decode and continues with the next height, instead of stopping the fetcher
+	SkipPoisonBlocks bool `yaml:"skip_poison_blocks"`
+	// DetectReorgs checks before each batch that the last indexed block is
+	// still canonical and rolls back and re-fetches the blocks a reorg replaced
+	DetectReorgs bool `yaml:"detect_reorgs"`
+	// MaxReorgDepth limits how many indexed blocks reorg detection walks back
+	// looking for the fork point (default: 128)
+	MaxReorgDepth uint64 `yaml:"max_reorg_depth"`
+	// CoinbaseBalance credits each block's coinbase with its transactions'
+	// priority fees plus BlockReward in the native balance history
+	CoinbaseBalance bool `yaml:"coinbase_balance"`
+	// BlockReward is the per-block coinbase reward in wei as a decimal string
+	// (used with coinbase_balance; empty means no reward)
+	BlockReward string `yaml:"block_reward"`
+	// SeedOpeningBalances initializes first-seen addresses from their
+	// balance at the block before StartHeight instead of the block before
+	// the one they appear in
+	SeedOpeningBalances bool `yaml:"seed_opening_balances"`
+}
+
+// BlockRewardAmount parses BlockReward, returning nil if it is empty
+func (c *IndexerConfig) BlockRewardAmount() (*big.Int, error) {
+	if c.BlockReward == "" {
+		return nil, nil
+	}
+	reward, ok := new(big.Int).SetString(c.BlockReward, 10)
+	if !ok || reward.Sign() < 0 {
+		return nil, fmt.Errorf("invalid block reward %q: must be a non-negative decimal wei amount", c.BlockReward)
+	}
+	return reward, nil
+}
+
+// AdaptiveWorkersConfig holds configuration for scaling fetch workers
+// based on observed RPC latency and error rate
+type AdaptiveWorkersConfig struct {
+	// Enabled turns on adaptive worker scaling (Workers is used as the starting count)
+	Enabled bool `yaml:"enabled"`
+	// MinWorkers is the lower bound for the active worker count
+	MinWorkers int `yaml:"min_workers"`
+	// MaxWorkers is the upper bound for the active worker count (0 = twice the indexer workers)
+	MaxWorkers int `yaml:"max_workers"`
+}
+
+// AdaptiveMaxWorkers returns the upper bound for adaptive worker scaling,
+// defaulting to twice Workers so scaling has room to grow past the starting
+// count, capped at constants.MaxWorkers
+func (c *IndexerConfig) AdaptiveMaxWorkers() int {
+	if c.AdaptiveWorkers.MaxWorkers > 0 {
+		return c.AdaptiveWorkers.MaxWorkers
+	}
+	return min(2*c.Workers, constants.MaxWorkers)
+}
+
+// GapRecoveryConfig holds fetch settings used while filling gaps
+type GapRecoveryConfig struct {
+	// Workers is the number of concurrent workers filling a gap (0 = indexer workers)
+	Workers int `yaml:"workers"`
+	// BatchSize is the number of gap blocks fetched per batch (0 = whole gap at once)
+	BatchSize int `yaml:"batch_size"`
+	// RateLimit caps blocks fetched per second while filling gaps (0 = unlimited)
+	RateLimit float64 `yaml:"rate_limit"`
 }
 
 // APIConfig holds API server configuration
@@ -74,8 +258,43 @@ type APIConfig struct {
 	EnableJSONRPC            bool     `yaml:"enable_jsonrpc"`
 	EnableWebSocket          bool     `yaml:"enable_websocket"`
 	EnableWebSocketKeepAlive bool     `yaml:"enable_websocket_keepalive"`
+	EnableREST               bool     `yaml:"enable_rest"`
 	EnableCORS               bool     `yaml:"enable_cors"`
 	AllowedOrigins           []string `yaml:"allowed_origins"`
+	// RequestTimeout bounds a single JSON-RPC or GraphQL request (default: 10s)
+	RequestTimeout time.Duration `yaml:"request_timeout"`
+	// MaxRequestBytes caps JSON-RPC/GraphQL request bodies (default: 2 MB)
+	MaxRequestBytes int64 `yaml:"max_request_bytes"`
+	// GraphQLMaxDepth caps GraphQL field nesting depth (default: 12)
+	GraphQLMaxDepth int `yaml:"graphql_max_depth"`
+	// GraphQLMaxComplexity caps the estimated GraphQL query cost (default: 20000)
+	GraphQLMaxComplexity int `yaml:"graphql_max_complexity"`
+	// MaxConcurrentConnections caps requests served at once; the overflow gets 503 (0 = unlimited)
+	MaxConcurrentConnections int `yaml:"max_concurrent_connections"`
+	// JSONRPCAllowedMethods limits JSON-RPC to these methods (empty = all methods)
+	JSONRPCAllowedMethods []string `yaml:"jsonrpc_allowed_methods"`
+	// JSONRPCDeniedMethods are rejected even when allowed
+	JSONRPCDeniedMethods []string `yaml:"jsonrpc_denied_methods"`
+	// MaskedFields are JSON response fields replaced with "[redacted]" (e.g. "input")
+	MaskedFields []string `yaml:"masked_fields"`
+	// MaskedAddresses are addresses replaced with "[redacted]" wherever they appear in responses
+	MaskedAddresses []string `yaml:"masked_addresses"`
+	// OmitMaskedFields removes masked fields from responses instead of redacting them
+	OmitMaskedFields bool `yaml:"omit_masked_fields"`
+	// WebSocketPingInterval is how often WebSocket ping frames are sent (default: 54s)
+	WebSocketPingInterval time.Duration `yaml:"websocket_ping_interval"`
+	// WebSocketIdleTimeout closes WebSocket connections silent for this long (default: 60s)
+	WebSocketIdleTimeout time.Duration `yaml:"websocket_idle_timeout"`
+	// TLSCertFile and TLSKeyFile enable HTTPS; the certificate is reloaded on SIGHUP
+	TLSCertFile string `yaml:"tls_cert_file"`
+	TLSKeyFile  string `yaml:"tls_key_file"`
+	// TLSRedirectPort is a plain HTTP port redirecting to HTTPS (0 = disabled)
+	TLSRedirectPort int `yaml:"tls_redirect_port"`
+	// EnableAdmin serves /admin endpoints that pause and resume indexing
+	// (requires AdminKeys)
+	EnableAdmin bool `yaml:"enable_admin"`
+	// AdminKeys are the API keys accepted by the /admin endpoints
+	AdminKeys []string `yaml:"admin_keys"`
 }
 
 // MultiChainConfig holds configuration for multi-chain support
@@ -188,6 +407,8 @@ type EventBusConfig struct {
 	Type string `yaml:"type"`
 	// PublishBufferSize is the size of the publish buffer
 	PublishBufferSize int `yaml:"publish_buffer_size"`
+	// SubscribeBufferSize is the per-subscriber channel size for subscriptions that do not set their own
+	SubscribeBufferSize int `yaml:"subscribe_buffer_size"`
 	// HistorySize is the number of events to keep in history for replay
 	HistorySize int `yaml:"history_size"`
 	// Redis holds Redis EventBus configuration
@@ -420,6 +641,10 @@ type StorageNotificationConfig struct {
 // NewConfig creates a new Config with default values
 func NewConfig() *Config {
 	cfg := &Config{}
+	// Zero is a valid retry multiplier and jitter, so their defaults are set
+	// only here, where a config file can still override them with zero
+	cfg.RPC.Retry.Multiplier = 2.0
+	cfg.RPC.Retry.Jitter = 0.5
 	cfg.SetDefaults()
 	return cfg
 }
@@ -430,6 +655,23 @@ func (c *Config) SetDefaults() {
 	if c.RPC.Timeout == 0 {
 		c.RPC.Timeout = constants.DefaultQueryTimeout
 	}
+	if c.RPC.Retry.MaxRetries == 0 {
+		c.RPC.Retry.MaxRetries = 3
+	}
+	if c.RPC.Retry.BaseDelay == 0 {
+		c.RPC.Retry.BaseDelay = constants.InitialRetryDelay
+	}
+	if c.RPC.Retry.MaxDelay == 0 {
+		c.RPC.Retry.MaxDelay = constants.MaxRetryDelay
+	}
+
+	// Database defaults
+	if c.Database.MemoryFraction == 0 {
+		c.Database.MemoryFraction = constants.DefaultDBMemoryFraction
+	}
+	if c.Database.ColdRetainBlocks == 0 {
+		c.Database.ColdRetainBlocks = constants.DefaultColdRetainBlocks
+	}
 
 	// Log defaults
 	if c.Log.Level == "" {
@@ -446,6 +688,12 @@ func (c *Config) SetDefaults() {
 	if c.Indexer.ChunkSize == 0 {
 		c.Indexer.ChunkSize = constants.DefaultMaxPaginationLimit
 	}
+	if c.Indexer.AdaptiveWorkers.MinWorkers == 0 {
+		c.Indexer.AdaptiveWorkers.MinWorkers = constants.MinWorkers
+	}
+	if c.Indexer.ShutdownTimeout == 0 {
+		c.Indexer.ShutdownTimeout = constants.DefaultShutdownTimeout
+	}
 
 	// API defaults
 	if c.API.Host == "" {
@@ -457,6 +705,24 @@ func (c *Config) SetDefaults() {
 	if c.API.AllowedOrigins == nil {
 		c.API.AllowedOrigins = []string{"*"}
 	}
+	if c.API.RequestTimeout == 0 {
+		c.API.RequestTimeout = constants.DefaultRequestTimeout
+	}
+	if c.API.MaxRequestBytes == 0 {
+		c.API.MaxRequestBytes = constants.DefaultMaxRequestBytes
+	}
+	if c.API.GraphQLMaxDepth == 0 {
+		c.API.GraphQLMaxDepth = constants.DefaultGraphQLMaxDepth
+	}
+	if c.API.GraphQLMaxComplexity == 0 {
+		c.API.GraphQLMaxComplexity = constants.DefaultGraphQLMaxComplexity
+	}
+	if c.API.WebSocketPingInterval == 0 {
+		c.API.WebSocketPingInterval = constants.DefaultWebSocketPingInterval
+	}
+	if c.API.WebSocketIdleTimeout == 0 {
+		c.API.WebSocketIdleTimeout = constants.DefaultWebSocketIdleTimeout
+	}
 
 	// MultiChain defaults
 	if c.MultiChain.HealthCheckInterval == 0 {
@@ -572,6 +838,9 @@ func (c *Config) SetDefaults() {
 	if c.EventBus.PublishBufferSize == 0 {
 		c.EventBus.PublishBufferSize = 1000
 	}
+	if c.EventBus.SubscribeBufferSize == 0 {
+		c.EventBus.SubscribeBufferSize = 100
+	}
 	if c.EventBus.HistorySize == 0 {
 		c.EventBus.HistorySize = 100
 	}
@@ -664,6 +933,25 @@ func (c *Config) LoadFromEnv() error {
 		}
 		c.RPC.Timeout = duration
 	}
+	if methodTimeouts := os.Getenv("INDEXER_RPC_METHOD_TIMEOUTS"); methodTimeouts != "" {
+		timeouts := make(map[string]time.Duration)
+		for _, entry := range strings.Split(methodTimeouts, ",") {
+			entry = strings.TrimSpace(entry)
+			if entry == "" {
+				continue
+			}
+			method, value, ok := strings.Cut(entry, "=")
+			if !ok {
+				return fmt.Errorf("invalid INDEXER_RPC_METHOD_TIMEOUTS: %q is not method=duration", entry)
+			}
+			duration, err := time.ParseDuration(strings.TrimSpace(value))
+			if err != nil {
+				return fmt.Errorf("invalid INDEXER_RPC_METHOD_TIMEOUTS: %w", err)
+			}
+			timeouts[strings.TrimSpace(method)] = duration
+		}
+		c.RPC.MethodTimeouts = timeouts
+	}
 
 	// Database configuration
 	if path := os.Getenv("INDEXER_DB_PATH"); path != "" {
@@ -676,6 +964,123 @@ func (c *Config) LoadFromEnv() error {
 		}
 		c.Database.ReadOnly = val
 	}
+	if fraction := os.Getenv("INDEXER_DB_MEMORY_FRACTION"); fraction != "" {
+		val, err := strconv.ParseFloat(fraction, 64)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_DB_MEMORY_FRACTION: %w", err)
+		}
+		c.Database.MemoryFraction = val
+	}
+	if budget := os.Getenv("INDEXER_DB_MEMORY_BUDGET_MB"); budget != "" {
+		val, err := strconv.Atoi(budget)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_DB_MEMORY_BUDGET_MB: %w", err)
+		}
+		c.Database.MemoryBudgetMB = val
+	}
+	if logIndex := os.Getenv("INDEXER_DB_LOG_ADDRESS_TOPIC_INDEX"); logIndex != "" {
+		val, err := strconv.ParseBool(logIndex)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_DB_LOG_ADDRESS_TOPIC_INDEX: %w", err)
+		}
+		c.Database.LogAddressTopicIndex = val
+	}
+	if bloomBits := os.Getenv("INDEXER_DB_BLOOM_FILTER_BITS_PER_KEY"); bloomBits != "" {
+		val, err := strconv.Atoi(bloomBits)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_DB_BLOOM_FILTER_BITS_PER_KEY: %w", err)
+		}
+		c.Database.BloomFilterBitsPerKey = val
+	}
+	if keepOrphans := os.Getenv("INDEXER_DB_KEEP_ORPHAN_BLOCKS"); keepOrphans != "" {
+		val, err := strconv.ParseBool(keepOrphans)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_DB_KEEP_ORPHAN_BLOCKS: %w", err)
+		}
+		c.Database.KeepOrphanBlocks = val
+	}
+	if indexUncles := os.Getenv("INDEXER_DB_INDEX_UNCLES"); indexUncles != "" {
+		val, err := strconv.ParseBool(indexUncles)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_DB_INDEX_UNCLES: %w", err)
+		}
+		c.Database.IndexUncles = val
+	}
+	if cacheSize := os.Getenv("INDEXER_DB_READ_CACHE_SIZE"); cacheSize != "" {
+		val, err := strconv.Atoi(cacheSize)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_DB_READ_CACHE_SIZE: %w", err)
+		}
+		c.Database.ReadCacheSize = val
+	}
+	if initTxCount := os.Getenv("INDEXER_DB_INIT_TRANSACTION_COUNT"); initTxCount != "" {
+		val, err := strconv.ParseBool(initTxCount)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_DB_INIT_TRANSACTION_COUNT: %w", err)
+		}
+		c.Database.InitTransactionCount = val
+	}
+	if scanWorkers := os.Getenv("INDEXER_DB_SCAN_WORKERS"); scanWorkers != "" {
+		val, err := strconv.Atoi(scanWorkers)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_DB_SCAN_WORKERS: %w", err)
+		}
+		c.Database.ScanWorkers = val
+	}
+	if interval := os.Getenv("INDEXER_DB_COMPACTION_INTERVAL"); interval != "" {
+		val, err := time.ParseDuration(interval)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_DB_COMPACTION_INTERVAL: %w", err)
+		}
+		c.Database.CompactionInterval = val
+	}
+	if compactionTime := os.Getenv("INDEXER_DB_COMPACTION_TIME"); compactionTime != "" {
+		c.Database.CompactionTime = compactionTime
+	}
+	if keyNamespace := os.Getenv("INDEXER_DB_KEY_NAMESPACE"); keyNamespace != "" {
+		c.Database.KeyNamespace = keyNamespace
+	}
+	if maxIters := os.Getenv("INDEXER_DB_MAX_OPEN_ITERATORS"); maxIters != "" {
+		val, err := strconv.Atoi(maxIters)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_DB_MAX_OPEN_ITERATORS: %w", err)
+		}
+		c.Database.MaxOpenIterators = val
+	}
+	if panicOnLeak := os.Getenv("INDEXER_DB_PANIC_ON_ITERATOR_LEAK"); panicOnLeak != "" {
+		val, err := strconv.ParseBool(panicOnLeak)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_DB_PANIC_ON_ITERATOR_LEAK: %w", err)
+		}
+		c.Database.PanicOnIteratorLeak = val
+	}
+	if refresh := os.Getenv("INDEXER_DB_REPLICA_REFRESH_INTERVAL"); refresh != "" {
+		val, err := time.ParseDuration(refresh)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_DB_REPLICA_REFRESH_INTERVAL: %w", err)
+		}
+		c.Database.ReplicaRefreshInterval = val
+	}
+	if snapshotDir := os.Getenv("INDEXER_DB_REPLICA_SNAPSHOT_DIR"); snapshotDir != "" {
+		c.Database.ReplicaSnapshotDir = snapshotDir
+	}
+	if coldPath := os.Getenv("INDEXER_DB_COLD_PATH"); coldPath != "" {
+		c.Database.ColdPath = coldPath
+	}
+	if retain := os.Getenv("INDEXER_DB_COLD_RETAIN_BLOCKS"); retain != "" {
+		val, err := strconv.ParseUint(retain, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_DB_COLD_RETAIN_BLOCKS: %w", err)
+		}
+		c.Database.ColdRetainBlocks = val
+	}
+	if interval := os.Getenv("INDEXER_DB_COLD_MIGRATION_INTERVAL"); interval != "" {
+		val, err := time.ParseDuration(interval)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_DB_COLD_MIGRATION_INTERVAL: %w", err)
+		}
+		c.Database.ColdMigrationInterval = val
+	}
 
 	// Log configuration
 	if level := os.Getenv("INDEXER_LOG_LEVEL"); level != "" {
@@ -700,6 +1105,27 @@ func (c *Config) LoadFromEnv() error {
 		}
 		c.Indexer.ChunkSize = val
 	}
+	if gapWorkers := os.Getenv("INDEXER_GAP_RECOVERY_WORKERS"); gapWorkers != "" {
+		val, err := strconv.Atoi(gapWorkers)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_GAP_RECOVERY_WORKERS: %w", err)
+		}
+		c.Indexer.GapRecovery.Workers = val
+	}
+	if gapBatchSize := os.Getenv("INDEXER_GAP_RECOVERY_BATCH_SIZE"); gapBatchSize != "" {
+		val, err := strconv.Atoi(gapBatchSize)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_GAP_RECOVERY_BATCH_SIZE: %w", err)
+		}
+		c.Indexer.GapRecovery.BatchSize = val
+	}
+	if gapRateLimit := os.Getenv("INDEXER_GAP_RECOVERY_RATE_LIMIT"); gapRateLimit != "" {
+		val, err := strconv.ParseFloat(gapRateLimit, 64)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_GAP_RECOVERY_RATE_LIMIT: %w", err)
+		}
+		c.Indexer.GapRecovery.RateLimit = val
+	}
 	if startHeight := os.Getenv("INDEXER_START_HEIGHT"); startHeight != "" {
 		val, err := strconv.ParseUint(startHeight, 10, 64)
 		if err != nil {
@@ -707,6 +1133,141 @@ func (c *Config) LoadFromEnv() error {
 		}
 		c.Indexer.StartHeight = val
 	}
+	if traceInternal := os.Getenv("INDEXER_TRACE_INTERNAL_TRANSFERS"); traceInternal != "" {
+		val, err := strconv.ParseBool(traceInternal)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_TRACE_INTERNAL_TRANSFERS: %w", err)
+		}
+		c.Indexer.TraceInternalTransfers = val
+	}
+	if revertReasons := os.Getenv("INDEXER_REVERT_REASONS"); revertReasons != "" {
+		val, err := strconv.ParseBool(revertReasons)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_REVERT_REASONS: %w", err)
+		}
+		c.Indexer.RevertReasons = val
+	}
+	if pendingTxs := os.Getenv("INDEXER_PENDING_TRANSACTIONS"); pendingTxs != "" {
+		val, err := strconv.ParseBool(pendingTxs)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_PENDING_TRANSACTIONS: %w", err)
+		}
+		c.Indexer.PendingTransactions = val
+	}
+	if batchAddrIdx := os.Getenv("INDEXER_BATCH_ADDRESS_INDEX"); batchAddrIdx != "" {
+		val, err := strconv.ParseBool(batchAddrIdx)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_BATCH_ADDRESS_INDEX: %w", err)
+		}
+		c.Indexer.BatchAddressIndex = val
+	}
+	if indexLogAddrs := os.Getenv("INDEXER_INDEX_LOG_ADDRESSES"); indexLogAddrs != "" {
+		val, err := strconv.ParseBool(indexLogAddrs)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_INDEX_LOG_ADDRESSES: %w", err)
+		}
+		c.Indexer.IndexLogAddresses = val
+	}
+	if commitBatchBlocks := os.Getenv("INDEXER_COMMIT_BATCH_BLOCKS"); commitBatchBlocks != "" {
+		val, err := strconv.Atoi(commitBatchBlocks)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_COMMIT_BATCH_BLOCKS: %w", err)
+		}
+		c.Indexer.CommitBatchBlocks = val
+	}
+	if checkpointPath := os.Getenv("INDEXER_CHECKPOINT_PATH"); checkpointPath != "" {
+		c.Indexer.CheckpointPath = checkpointPath
+	}
+	if checkpointInterval := os.Getenv("INDEXER_CHECKPOINT_INTERVAL"); checkpointInterval != "" {
+		val, err := time.ParseDuration(checkpointInterval)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_CHECKPOINT_INTERVAL: %w", err)
+		}
+		c.Indexer.CheckpointInterval = val
+	}
+	if snapshotPath := os.Getenv("INDEXER_SNAPSHOT_PATH"); snapshotPath != "" {
+		c.Indexer.SnapshotPath = snapshotPath
+	}
+	if startDelay := os.Getenv("INDEXER_START_DELAY"); startDelay != "" {
+		val, err := time.ParseDuration(startDelay)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_START_DELAY: %w", err)
+		}
+		c.Indexer.StartDelay = val
+	}
+	if shutdownTimeout := os.Getenv("INDEXER_SHUTDOWN_TIMEOUT"); shutdownTimeout != "" {
+		val, err := time.ParseDuration(shutdownTimeout)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_SHUTDOWN_TIMEOUT: %w", err)
+		}
+		c.Indexer.ShutdownTimeout = val
+	}
+	if maxHead := os.Getenv("INDEXER_MAX_HEAD"); maxHead != "" {
+		val, err := strconv.ParseUint(maxHead, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_MAX_HEAD: %w", err)
+		}
+		c.Indexer.MaxHead = val
+	}
+	if waitForNodeSync := os.Getenv("INDEXER_WAIT_FOR_NODE_SYNC"); waitForNodeSync != "" {
+		val, err := strconv.ParseBool(waitForNodeSync)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_WAIT_FOR_NODE_SYNC: %w", err)
+		}
+		c.Indexer.WaitForNodeSync = val
+	}
+	if verifyOnIngest := os.Getenv("INDEXER_VERIFY_ON_INGEST"); verifyOnIngest != "" {
+		val, err := strconv.ParseBool(verifyOnIngest)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_VERIFY_ON_INGEST: %w", err)
+		}
+		c.Indexer.VerifyOnIngest = val
+	}
+	if failOnMismatch := os.Getenv("INDEXER_FAIL_ON_BLOCK_NUMBER_MISMATCH"); failOnMismatch != "" {
+		val, err := strconv.ParseBool(failOnMismatch)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_FAIL_ON_BLOCK_NUMBER_MISMATCH: %w", err)
+		}
+		c.Indexer.FailOnBlockNumberMismatch = val
+	}
+	if skipPoison := os.Getenv("INDEXER_SKIP_POISON_BLOCKS"); skipPoison != "" {
+		val, err := strconv.ParseBool(skipPoison)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_SKIP_POISON_BLOCKS: %w", err)
+		}
+		c.Indexer.SkipPoisonBlocks = val
+	}
+	if detectReorgs := os.Getenv("INDEXER_DETECT_REORGS"); detectReorgs != "" {
+		val, err := strconv.ParseBool(detectReorgs)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_DETECT_REORGS: %w", err)
+		}
+		c.Indexer.DetectReorgs = val
+	}
+	if maxReorgDepth := os.Getenv("INDEXER_MAX_REORG_DEPTH"); maxReorgDepth != "" {
+		val, err := strconv.ParseUint(maxReorgDepth, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_MAX_REORG_DEPTH: %w", err)
+		}
+		c.Indexer.MaxReorgDepth = val
+	}
+	if coinbaseBalance := os.Getenv("INDEXER_COINBASE_BALANCE"); coinbaseBalance != "" {
+		val, err := strconv.ParseBool(coinbaseBalance)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_COINBASE_BALANCE: %w", err)
+		}
+		c.Indexer.CoinbaseBalance = val
+	}
+	if blockReward := os.Getenv("INDEXER_BLOCK_REWARD"); blockReward != "" {
+		c.Indexer.BlockReward = blockReward
+	}
+	if seedOpening := os.Getenv("INDEXER_SEED_OPENING_BALANCES"); seedOpening != "" {
+		val, err := strconv.ParseBool(seedOpening)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_SEED_OPENING_BALANCES: %w", err)
+		}
+		c.Indexer.SeedOpeningBalances = val
+	}
 
 	// API configuration
 	if enabled := os.Getenv("INDEXER_API_ENABLED"); enabled != "" {
@@ -754,6 +1315,44 @@ func (c *Config) LoadFromEnv() error {
 		}
 		c.API.EnableWebSocketKeepAlive = val
 	}
+	if pingInterval := os.Getenv("INDEXER_API_WEBSOCKET_PING_INTERVAL"); pingInterval != "" {
+		val, err := time.ParseDuration(pingInterval)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_API_WEBSOCKET_PING_INTERVAL: %w", err)
+		}
+		c.API.WebSocketPingInterval = val
+	}
+	if idleTimeout := os.Getenv("INDEXER_API_WEBSOCKET_IDLE_TIMEOUT"); idleTimeout != "" {
+		val, err := time.ParseDuration(idleTimeout)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_API_WEBSOCKET_IDLE_TIMEOUT: %w", err)
+		}
+		c.API.WebSocketIdleTimeout = val
+	}
+	if enableREST := os.Getenv("INDEXER_API_REST"); enableREST != "" {
+		val, err := strconv.ParseBool(enableREST)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_API_REST: %w", err)
+		}
+		c.API.EnableREST = val
+	}
+	if enableAdmin := os.Getenv("INDEXER_API_ADMIN"); enableAdmin != "" {
+		val, err := strconv.ParseBool(enableAdmin)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_API_ADMIN: %w", err)
+		}
+		c.API.EnableAdmin = val
+	}
+	if adminKeys := os.Getenv("INDEXER_API_ADMIN_KEYS"); adminKeys != "" {
+		keys := make([]string, 0)
+		for _, key := range strings.Split(adminKeys, ",") {
+			key = strings.TrimSpace(key)
+			if key != "" {
+				keys = append(keys, key)
+			}
+		}
+		c.API.AdminKeys = keys
+	}
 	if enableCORS := os.Getenv("INDEXER_API_CORS_ENABLED"); enableCORS != "" {
 		val, err := strconv.ParseBool(enableCORS)
 		if err != nil {
@@ -774,6 +1373,101 @@ func (c *Config) LoadFromEnv() error {
 		}
 		c.API.AllowedOrigins = origins
 	}
+	if requestTimeout := os.Getenv("INDEXER_API_REQUEST_TIMEOUT"); requestTimeout != "" {
+		val, err := time.ParseDuration(requestTimeout)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_API_REQUEST_TIMEOUT: %w", err)
+		}
+		c.API.RequestTimeout = val
+	}
+	if maxRequestBytes := os.Getenv("INDEXER_API_MAX_REQUEST_BYTES"); maxRequestBytes != "" {
+		val, err := strconv.ParseInt(maxRequestBytes, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_API_MAX_REQUEST_BYTES: %w", err)
+		}
+		c.API.MaxRequestBytes = val
+	}
+	if maxDepth := os.Getenv("INDEXER_API_GRAPHQL_MAX_DEPTH"); maxDepth != "" {
+		val, err := strconv.Atoi(maxDepth)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_API_GRAPHQL_MAX_DEPTH: %w", err)
+		}
+		c.API.GraphQLMaxDepth = val
+	}
+	if maxComplexity := os.Getenv("INDEXER_API_GRAPHQL_MAX_COMPLEXITY"); maxComplexity != "" {
+		val, err := strconv.Atoi(maxComplexity)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_API_GRAPHQL_MAX_COMPLEXITY: %w", err)
+		}
+		c.API.GraphQLMaxComplexity = val
+	}
+	if maxConns := os.Getenv("INDEXER_API_MAX_CONCURRENT_CONNECTIONS"); maxConns != "" {
+		val, err := strconv.Atoi(maxConns)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_API_MAX_CONCURRENT_CONNECTIONS: %w", err)
+		}
+		c.API.MaxConcurrentConnections = val
+	}
+	if allowedMethods := os.Getenv("INDEXER_API_JSONRPC_ALLOWED_METHODS"); allowedMethods != "" {
+		methods := make([]string, 0)
+		for _, method := range strings.Split(allowedMethods, ",") {
+			method = strings.TrimSpace(method)
+			if method != "" {
+				methods = append(methods, method)
+			}
+		}
+		c.API.JSONRPCAllowedMethods = methods
+	}
+	if deniedMethods := os.Getenv("INDEXER_API_JSONRPC_DENIED_METHODS"); deniedMethods != "" {
+		methods := make([]string, 0)
+		for _, method := range strings.Split(deniedMethods, ",") {
+			method = strings.TrimSpace(method)
+			if method != "" {
+				methods = append(methods, method)
+			}
+		}
+		c.API.JSONRPCDeniedMethods = methods
+	}
+	if maskedFields := os.Getenv("INDEXER_API_MASKED_FIELDS"); maskedFields != "" {
+		fields := make([]string, 0)
+		for _, field := range strings.Split(maskedFields, ",") {
+			field = strings.TrimSpace(field)
+			if field != "" {
+				fields = append(fields, field)
+			}
+		}
+		c.API.MaskedFields = fields
+	}
+	if maskedAddresses := os.Getenv("INDEXER_API_MASKED_ADDRESSES"); maskedAddresses != "" {
+		addresses := make([]string, 0)
+		for _, addr := range strings.Split(maskedAddresses, ",") {
+			addr = strings.TrimSpace(addr)
+			if addr != "" {
+				addresses = append(addresses, addr)
+			}
+		}
+		c.API.MaskedAddresses = addresses
+	}
+	if omitMasked := os.Getenv("INDEXER_API_OMIT_MASKED_FIELDS"); omitMasked != "" {
+		val, err := strconv.ParseBool(omitMasked)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_API_OMIT_MASKED_FIELDS: %w", err)
+		}
+		c.API.OmitMaskedFields = val
+	}
+	if certFile := os.Getenv("INDEXER_API_TLS_CERT_FILE"); certFile != "" {
+		c.API.TLSCertFile = certFile
+	}
+	if keyFile := os.Getenv("INDEXER_API_TLS_KEY_FILE"); keyFile != "" {
+		c.API.TLSKeyFile = keyFile
+	}
+	if redirectPort := os.Getenv("INDEXER_API_TLS_REDIRECT_PORT"); redirectPort != "" {
+		val, err := strconv.Atoi(redirectPort)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_API_TLS_REDIRECT_PORT: %w", err)
+		}
+		c.API.TLSRedirectPort = val
+	}
 
 	// System contracts configuration
 	if enabled := os.Getenv("INDEXER_SYSTEM_CONTRACTS_ENABLED"); enabled != "" {
@@ -793,6 +1487,33 @@ func (c *Config) LoadFromEnv() error {
 		}
 		c.SystemContracts.IncludeAbstracts = val
 	}
+	if disabledEvents := os.Getenv("INDEXER_SYSTEM_CONTRACTS_DISABLED_EVENTS"); disabledEvents != "" {
+		categories := make([]string, 0)
+		for _, category := range strings.Split(disabledEvents, ",") {
+			category = strings.TrimSpace(category)
+			if category != "" {
+				categories = append(categories, category)
+			}
+		}
+		c.SystemContracts.DisabledEvents = categories
+	}
+	if allowedContracts := os.Getenv("INDEXER_SYSTEM_CONTRACTS_ALLOWED_CONTRACTS"); allowedContracts != "" {
+		contracts := make([]string, 0)
+		for _, contract := range strings.Split(allowedContracts, ",") {
+			contract = strings.TrimSpace(contract)
+			if contract != "" {
+				contracts = append(contracts, contract)
+			}
+		}
+		c.SystemContracts.AllowedContracts = contracts
+	}
+	if strict := os.Getenv("INDEXER_SYSTEM_CONTRACTS_STRICT_PROPOSAL_TRANSITIONS"); strict != "" {
+		val, err := strconv.ParseBool(strict)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_SYSTEM_CONTRACTS_STRICT_PROPOSAL_TRANSITIONS: %w", err)
+		}
+		c.SystemContracts.StrictProposalTransitions = val
+	}
 
 	// Notifications configuration
 	if enabled := os.Getenv("INDEXER_NOTIFICATIONS_ENABLED"); enabled != "" {
@@ -854,6 +1575,13 @@ func (c *Config) LoadFromEnv() error {
 		}
 		c.EventBus.PublishBufferSize = val
 	}
+	if bufferSize := os.Getenv("INDEXER_EVENTBUS_SUBSCRIBE_BUFFER_SIZE"); bufferSize != "" {
+		val, err := strconv.Atoi(bufferSize)
+		if err != nil {
+			return fmt.Errorf("invalid INDEXER_EVENTBUS_SUBSCRIBE_BUFFER_SIZE: %w", err)
+		}
+		c.EventBus.SubscribeBufferSize = val
+	}
 	if historySize := os.Getenv("INDEXER_EVENTBUS_HISTORY_SIZE"); historySize != "" {
 		val, err := strconv.Atoi(historySize)
 		if err != nil {
@@ -971,11 +1699,74 @@ func (c *Config) Validate() error {
 	if c.RPC.Timeout <= 0 {
 		return fmt.Errorf("RPC timeout must be positive")
 	}
+	for method, timeout := range c.RPC.MethodTimeouts {
+		if timeout <= 0 {
+			return fmt.Errorf("RPC method timeout for %s must be positive", method)
+		}
+	}
+	if c.RPC.Retry.BaseDelay < 0 || c.RPC.Retry.MaxDelay < 0 {
+		return fmt.Errorf("RPC retry delays cannot be negative")
+	}
+	if c.RPC.Retry.MaxDelay > 0 && c.RPC.Retry.MaxDelay < c.RPC.Retry.BaseDelay {
+		return fmt.Errorf("RPC retry max delay must not be less than base delay")
+	}
+	if c.RPC.Retry.Multiplier != 0 && c.RPC.Retry.Multiplier < 1 {
+		return fmt.Errorf("RPC retry multiplier must be at least 1")
+	}
+	if c.RPC.Retry.Jitter < 0 || c.RPC.Retry.Jitter > 1 {
+		return fmt.Errorf("RPC retry jitter must be between 0 and 1")
+	}
 
 	// Validate database configuration
 	if c.Database.Path == "" {
 		return fmt.Errorf("database path is required")
 	}
+	if c.Database.CacheMB < 0 || c.Database.WriteBufferMB < 0 || c.Database.WriteBufferCount < 0 || c.Database.MemoryBudgetMB < 0 {
+		return fmt.Errorf("database cache, write buffer, and memory budget settings cannot be negative")
+	}
+	if c.Database.MemoryFraction < 0 || c.Database.MemoryFraction > 1 {
+		return fmt.Errorf("database memory fraction must be between 0 and 1")
+	}
+	if c.Database.ReadCacheSize < 0 {
+		return fmt.Errorf("database read cache size cannot be negative")
+	}
+	if c.Database.ScanWorkers < 0 {
+		return fmt.Errorf("database scan workers cannot be negative")
+	}
+	if c.Database.BloomFilterBitsPerKey < -1 {
+		return fmt.Errorf("database bloom filter bits per key must be -1 (disabled) or greater")
+	}
+	if c.Database.CompactionInterval < 0 {
+		return fmt.Errorf("database compaction interval cannot be negative")
+	}
+	if c.Database.MaxOpenIterators < 0 {
+		return fmt.Errorf("database max open iterators cannot be negative")
+	}
+	if c.Database.ReplicaRefreshInterval < 0 {
+		return fmt.Errorf("database replica refresh interval cannot be negative")
+	}
+	if c.Database.ReplicaRefreshInterval > 0 && !c.Database.ReadOnly {
+		return fmt.Errorf("database replica refresh interval requires readonly")
+	}
+	if c.Database.CompactionTime != "" {
+		if _, err := time.Parse("15:04", c.Database.CompactionTime); err != nil {
+			return fmt.Errorf("invalid database compaction time %q: expected HH:MM", c.Database.CompactionTime)
+		}
+	}
+	if strings.Contains(c.Database.KeyNamespace, "/") {
+		return fmt.Errorf("invalid database key namespace %q: cannot contain '/'", c.Database.KeyNamespace)
+	}
+	if c.Database.ColdMigrationInterval < 0 {
+		return fmt.Errorf("database cold migration interval cannot be negative")
+	}
+	if c.Database.ColdPath != "" {
+		if filepath.Clean(c.Database.ColdPath) == filepath.Clean(c.Database.Path) {
+			return fmt.Errorf("database cold path must differ from path")
+		}
+		if c.MultiChain.Enabled && len(c.MultiChain.Chains) > 0 {
+			return fmt.Errorf("database cold path is not supported in multi-chain mode")
+		}
+	}
 
 	// Validate log configuration
 	validLogLevels := map[string]bool{
@@ -1003,6 +1794,39 @@ func (c *Config) Validate() error {
 	if c.Indexer.ChunkSize <= 0 {
 		return fmt.Errorf("chunk size must be positive")
 	}
+	if c.Indexer.CommitBatchBlocks < 0 {
+		return fmt.Errorf("commit batch blocks cannot be negative")
+	}
+	if c.Indexer.CheckpointInterval < 0 {
+		return fmt.Errorf("checkpoint interval cannot be negative")
+	}
+	if c.Indexer.StartDelay < 0 {
+		return fmt.Errorf("start delay cannot be negative")
+	}
+	if c.Indexer.ShutdownTimeout < 0 {
+		return fmt.Errorf("shutdown timeout cannot be negative")
+	}
+	if _, err := c.Indexer.BlockRewardAmount(); err != nil {
+		return err
+	}
+	if c.Indexer.MaxHead > 0 && c.Indexer.MaxHead < c.Indexer.StartHeight {
+		return fmt.Errorf("max head (%d) cannot be below start height (%d)", c.Indexer.MaxHead, c.Indexer.StartHeight)
+	}
+	if c.Indexer.AdaptiveWorkers.Enabled {
+		if c.Indexer.AdaptiveWorkers.MinWorkers <= 0 {
+			return fmt.Errorf("adaptive min workers must be positive")
+		}
+		if c.Indexer.AdaptiveWorkers.MaxWorkers < 0 {
+			return fmt.Errorf("adaptive max workers cannot be negative")
+		}
+		if maxWorkers := c.Indexer.AdaptiveMaxWorkers(); maxWorkers < c.Indexer.AdaptiveWorkers.MinWorkers {
+			return fmt.Errorf("adaptive max workers (%d) must be >= min workers (%d)",
+				maxWorkers, c.Indexer.AdaptiveWorkers.MinWorkers)
+		}
+	}
+	if gap := c.Indexer.GapRecovery; gap.Workers < 0 || gap.BatchSize < 0 || gap.RateLimit < 0 {
+		return fmt.Errorf("gap recovery workers, batch size and rate limit cannot be negative")
+	}
 
 	// Validate EventBus configuration
 	validEventBusTypes := map[string]bool{
@@ -1017,6 +1841,9 @@ func (c *Config) Validate() error {
 	if c.EventBus.PublishBufferSize <= 0 {
 		return fmt.Errorf("eventbus publish buffer size must be positive")
 	}
+	if c.EventBus.SubscribeBufferSize <= 0 {
+		return fmt.Errorf("eventbus subscribe buffer size must be positive")
+	}
 	if c.EventBus.HistorySize < 0 {
 		return fmt.Errorf("eventbus history size cannot be negative")
 	}
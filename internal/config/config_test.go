@@ -1,11 +1,15 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/0xmhha/indexer-go/internal/constants"
 )
 
 // TestNewConfig tests creating a config with defaults
@@ -57,9 +61,10 @@ func TestConfigValidation(t *testing.T) {
 					ChunkSize: 100,
 				},
 				EventBus: EventBusConfig{
-					Type:              "local",
-					PublishBufferSize: 1000,
-					HistorySize:       100,
+					Type:                "local",
+					PublishBufferSize:   1000,
+					SubscribeBufferSize: 100,
+					HistorySize:         100,
 				},
 				Node: NodeConfig{
 					ID:   "test-node",
@@ -140,6 +145,29 @@ func TestConfigValidation(t *testing.T) {
 			wantErr: true,
 			errMsg:  "chunk size must be positive",
 		},
+		{
+			name: "negative commit batch blocks",
+			config: &Config{
+				RPC: RPCConfig{
+					Endpoint: "http://localhost:8545",
+					Timeout:  30 * time.Second,
+				},
+				Database: DatabaseConfig{
+					Path: "/tmp/indexer-test",
+				},
+				Log: LogConfig{
+					Level:  "info",
+					Format: "json",
+				},
+				Indexer: IndexerConfig{
+					Workers:           100,
+					ChunkSize:         1,
+					CommitBatchBlocks: -1,
+				},
+			},
+			wantErr: true,
+			errMsg:  "commit batch blocks cannot be negative",
+		},
 		{
 			name: "invalid RPC timeout",
 			config: &Config{
@@ -182,6 +210,10 @@ func TestLoadFromEnv(t *testing.T) {
 	os.Setenv("INDEXER_CHUNK_SIZE", "50")
 	os.Setenv("INDEXER_API_CORS_ENABLED", "true")
 	os.Setenv("INDEXER_API_CORS_ALLOWED_ORIGINS", "http://localhost:3001,https://app.example.com")
+	os.Setenv("INDEXER_API_JSONRPC_DENIED_METHODS", "eth_newFilter, eth_newBlockFilter,")
+	os.Setenv("INDEXER_API_MASKED_FIELDS", "input, data")
+	os.Setenv("INDEXER_API_OMIT_MASKED_FIELDS", "true")
+	os.Setenv("INDEXER_API_ADMIN_KEYS", "key1, key2")
 	defer func() {
 		os.Unsetenv("INDEXER_RPC_ENDPOINT")
 		os.Unsetenv("INDEXER_RPC_TIMEOUT")
@@ -192,6 +224,10 @@ func TestLoadFromEnv(t *testing.T) {
 		os.Unsetenv("INDEXER_CHUNK_SIZE")
 		os.Unsetenv("INDEXER_API_CORS_ENABLED")
 		os.Unsetenv("INDEXER_API_CORS_ALLOWED_ORIGINS")
+		os.Unsetenv("INDEXER_API_JSONRPC_DENIED_METHODS")
+		os.Unsetenv("INDEXER_API_MASKED_FIELDS")
+		os.Unsetenv("INDEXER_API_OMIT_MASKED_FIELDS")
+		os.Unsetenv("INDEXER_API_ADMIN_KEYS")
 	}()
 
 	cfg := NewConfig()
@@ -228,6 +264,21 @@ func TestLoadFromEnv(t *testing.T) {
 	if !reflect.DeepEqual(cfg.API.AllowedOrigins, wantOrigins) {
 		t.Errorf("Expected allowed origins %v, got %v", wantOrigins, cfg.API.AllowedOrigins)
 	}
+	wantDenied := []string{"eth_newFilter", "eth_newBlockFilter"}
+	if !reflect.DeepEqual(cfg.API.JSONRPCDeniedMethods, wantDenied) {
+		t.Errorf("Expected JSON-RPC denied methods %v, got %v", wantDenied, cfg.API.JSONRPCDeniedMethods)
+	}
+	wantMasked := []string{"input", "data"}
+	if !reflect.DeepEqual(cfg.API.MaskedFields, wantMasked) {
+		t.Errorf("Expected masked fields %v, got %v", wantMasked, cfg.API.MaskedFields)
+	}
+	if !cfg.API.OmitMaskedFields {
+		t.Errorf("Expected masked fields to be omitted")
+	}
+	wantAdminKeys := []string{"key1", "key2"}
+	if !reflect.DeepEqual(cfg.API.AdminKeys, wantAdminKeys) {
+		t.Errorf("Expected admin keys %v, got %v", wantAdminKeys, cfg.API.AdminKeys)
+	}
 }
 
 // TestLoadFromFile tests loading configuration from YAML file
@@ -616,3 +667,311 @@ func TestLoadFromEnvInvalidStartHeight(t *testing.T) {
 		t.Error("Expected error for invalid start height, got nil")
 	}
 }
+
+// TestRPCRetryDefaultsAndValidation tests RPC retry defaults and validation
+func TestRPCRetryDefaultsAndValidation(t *testing.T) {
+	cfg := NewConfig()
+	cfg.RPC.Endpoint = "http://localhost:8545"
+	cfg.Database.Path = "/tmp/test"
+
+	if cfg.RPC.Retry.MaxRetries != 3 {
+		t.Errorf("Expected default max retries 3, got %d", cfg.RPC.Retry.MaxRetries)
+	}
+	if cfg.RPC.Retry.BaseDelay != 100*time.Millisecond {
+		t.Errorf("Expected default base delay 100ms, got %v", cfg.RPC.Retry.BaseDelay)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Expected default config to be valid, got %v", err)
+	}
+
+	cfg.RPC.Retry.Jitter = 1.5
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for jitter above 1, got nil")
+	}
+
+	cfg.RPC.Retry.Jitter = 0.5
+	cfg.RPC.Retry.MaxDelay = 10 * time.Millisecond
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for max delay below base delay, got nil")
+	}
+
+	// A negative value turns retries off instead of being replaced by the default
+	cfg.RPC.Retry.MaxDelay = 0
+	cfg.RPC.Retry.MaxRetries = -1
+	cfg.SetDefaults()
+	if cfg.RPC.Retry.MaxRetries != -1 {
+		t.Errorf("Expected max retries -1 to be kept, got %d", cfg.RPC.Retry.MaxRetries)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Expected negative max retries to be valid, got %v", err)
+	}
+}
+
+// TestRPCRetryZeroJitterAndMultiplier tests that jitter and multiplier set to
+// zero in a config file are kept, while missing keys get the defaults
+func TestRPCRetryZeroJitterAndMultiplier(t *testing.T) {
+	tmpDir := t.TempDir()
+	base := `
+rpc:
+  endpoint: http://localhost:8545
+database:
+  path: /tmp/test-db
+`
+
+	tests := []struct {
+		name           string
+		retry          string
+		wantJitter     float64
+		wantMultiplier float64
+	}{
+		{"keys missing", "", 0.5, 2.0},
+		{"zero jitter", "  retry:\n    jitter: 0\n", 0, 2.0},
+		{"constant backoff", "  retry:\n    multiplier: 0\n    jitter: 0\n", 0, 0},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configFile := filepath.Join(tmpDir, fmt.Sprintf("config-%d.yaml", i))
+			content := strings.Replace(base, "database:", tt.retry+"database:", 1)
+			if err := os.WriteFile(configFile, []byte(content), 0644); err != nil {
+				t.Fatalf("Failed to write config file: %v", err)
+			}
+
+			cfg, err := Load(configFile)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if cfg.RPC.Retry.Jitter != tt.wantJitter {
+				t.Errorf("Expected jitter %v, got %v", tt.wantJitter, cfg.RPC.Retry.Jitter)
+			}
+			if cfg.RPC.Retry.Multiplier != tt.wantMultiplier {
+				t.Errorf("Expected multiplier %v, got %v", tt.wantMultiplier, cfg.RPC.Retry.Multiplier)
+			}
+		})
+	}
+}
+
+func TestDatabaseCompactionValidation(t *testing.T) {
+	cfg := NewConfig()
+	cfg.RPC.Endpoint = "http://localhost:8545"
+	cfg.Database.Path = "/tmp/test"
+
+	cfg.Database.CompactionInterval = 24 * time.Hour
+	cfg.Database.CompactionTime = "03:00"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Expected nightly compaction to be valid, got %v", err)
+	}
+
+	cfg.Database.CompactionTime = "3am"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for invalid compaction time, got nil")
+	}
+
+	cfg.Database.CompactionTime = ""
+	cfg.Database.CompactionInterval = -time.Minute
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for negative compaction interval, got nil")
+	}
+}
+
+func TestDatabaseColdStorageConfig(t *testing.T) {
+	cfg := NewConfig()
+	cfg.RPC.Endpoint = "http://localhost:8545"
+	cfg.Database.Path = "/tmp/test"
+	cfg.SetDefaults()
+
+	if cfg.Database.ColdRetainBlocks != constants.DefaultColdRetainBlocks {
+		t.Errorf("Expected default cold retain blocks %d, got %d", constants.DefaultColdRetainBlocks, cfg.Database.ColdRetainBlocks)
+	}
+
+	os.Setenv("INDEXER_DB_COLD_PATH", "/tmp/cold")
+	os.Setenv("INDEXER_DB_COLD_RETAIN_BLOCKS", "5000")
+	os.Setenv("INDEXER_DB_COLD_MIGRATION_INTERVAL", "30m")
+	defer func() {
+		os.Unsetenv("INDEXER_DB_COLD_PATH")
+		os.Unsetenv("INDEXER_DB_COLD_RETAIN_BLOCKS")
+		os.Unsetenv("INDEXER_DB_COLD_MIGRATION_INTERVAL")
+	}()
+	if err := cfg.LoadFromEnv(); err != nil {
+		t.Fatalf("LoadFromEnv() error = %v", err)
+	}
+	if cfg.Database.ColdPath != "/tmp/cold" || cfg.Database.ColdRetainBlocks != 5000 || cfg.Database.ColdMigrationInterval != 30*time.Minute {
+		t.Errorf("Unexpected cold storage settings: path %q, retain %d, interval %v",
+			cfg.Database.ColdPath, cfg.Database.ColdRetainBlocks, cfg.Database.ColdMigrationInterval)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Expected cold storage settings to be valid, got %v", err)
+	}
+
+	cfg.Database.ColdPath = "/tmp/test/"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for cold path equal to path, got nil")
+	}
+
+	cfg.Database.ColdPath = "/tmp/cold"
+	cfg.MultiChain.Enabled = true
+	cfg.MultiChain.Chains = []ChainConfig{{ID: "a"}}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "multi-chain") {
+		t.Errorf("Expected multi-chain cold path error, got %v", err)
+	}
+
+	cfg.MultiChain.Enabled = false
+	cfg.MultiChain.Chains = nil
+	cfg.Database.ColdMigrationInterval = -time.Minute
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for negative cold migration interval, got nil")
+	}
+}
+
+func TestIndexerStartDelayAndMaxHeadValidation(t *testing.T) {
+	cfg := NewConfig()
+	cfg.RPC.Endpoint = "http://localhost:8545"
+	cfg.Database.Path = "/tmp/test"
+
+	cfg.Indexer.StartHeight = 100
+	cfg.Indexer.MaxHead = 200
+	cfg.Indexer.StartDelay = 30 * time.Second
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Expected start delay and max head to be valid, got %v", err)
+	}
+
+	cfg.Indexer.MaxHead = 50
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for max head below start height, got nil")
+	}
+
+	cfg.Indexer.MaxHead = 0
+	cfg.Indexer.StartDelay = -time.Second
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for negative start delay, got nil")
+	}
+}
+
+func TestIndexerBlockRewardValidation(t *testing.T) {
+	cfg := NewConfig()
+	cfg.RPC.Endpoint = "http://localhost:8545"
+	cfg.Database.Path = "/tmp/test"
+
+	cfg.Indexer.CoinbaseBalance = true
+	cfg.Indexer.BlockReward = "2000000000000000000"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Expected block reward to be valid, got %v", err)
+	}
+	reward, err := cfg.Indexer.BlockRewardAmount()
+	if err != nil || reward.String() != "2000000000000000000" {
+		t.Errorf("BlockRewardAmount() = %v, %v, want 2000000000000000000", reward, err)
+	}
+
+	for _, invalid := range []string{"2 ether", "-1", "0x10"} {
+		cfg.Indexer.BlockReward = invalid
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("Expected error for block reward %q, got nil", invalid)
+		}
+	}
+}
+
+func TestAdaptiveWorkersDefaultsAndValidation(t *testing.T) {
+	cfg := NewConfig()
+	cfg.RPC.Endpoint = "http://localhost:8545"
+	cfg.Database.Path = "/tmp/test"
+
+	if cfg.Indexer.AdaptiveWorkers.Enabled {
+		t.Error("Expected adaptive workers to be disabled by default")
+	}
+	if cfg.Indexer.AdaptiveWorkers.MinWorkers != 1 {
+		t.Errorf("Expected default min workers 1, got %d", cfg.Indexer.AdaptiveWorkers.MinWorkers)
+	}
+	if got := cfg.Indexer.AdaptiveMaxWorkers(); got != 2*cfg.Indexer.Workers {
+		t.Errorf("Expected default max workers %d, got %d", 2*cfg.Indexer.Workers, got)
+	}
+	// The default follows workers set after defaults are applied, e.g. from a file
+	cfg.Indexer.Workers = 30
+	if got := cfg.Indexer.AdaptiveMaxWorkers(); got != 60 {
+		t.Errorf("Expected max workers 60 for 30 workers, got %d", got)
+	}
+
+	cfg.Indexer.AdaptiveWorkers.Enabled = true
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Expected adaptive defaults to be valid, got %v", err)
+	}
+
+	cfg.Indexer.AdaptiveWorkers.MinWorkers = 50
+	cfg.Indexer.AdaptiveWorkers.MaxWorkers = 10
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for max workers below min workers, got nil")
+	}
+}
+
+func TestRPCMethodTimeoutsFromEnv(t *testing.T) {
+	t.Setenv("INDEXER_RPC_METHOD_TIMEOUTS", "debug_traceBlockByNumber=5m, eth_getBlockReceipts=1m")
+
+	cfg := NewConfig()
+	if err := cfg.LoadFromEnv(); err != nil {
+		t.Fatalf("LoadFromEnv() error = %v", err)
+	}
+	if got := cfg.RPC.MethodTimeouts["debug_traceBlockByNumber"]; got != 5*time.Minute {
+		t.Errorf("Expected debug_traceBlockByNumber timeout 5m, got %v", got)
+	}
+	if got := cfg.RPC.MethodTimeouts["eth_getBlockReceipts"]; got != time.Minute {
+		t.Errorf("Expected eth_getBlockReceipts timeout 1m, got %v", got)
+	}
+
+	t.Setenv("INDEXER_RPC_METHOD_TIMEOUTS", "eth_getLogs")
+	if err := NewConfig().LoadFromEnv(); err == nil {
+		t.Error("Expected error for entry without duration, got nil")
+	}
+
+	cfg = NewConfig()
+	cfg.RPC.Endpoint = "http://localhost:8545"
+	cfg.Database.Path = "/tmp/test"
+	cfg.RPC.MethodTimeouts = map[string]time.Duration{"eth_getLogs": 0}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for non-positive method timeout, got nil")
+	}
+}
+
+func TestGapRecoveryFromEnv(t *testing.T) {
+	t.Setenv("INDEXER_GAP_RECOVERY_WORKERS", "4")
+	t.Setenv("INDEXER_GAP_RECOVERY_BATCH_SIZE", "500")
+	t.Setenv("INDEXER_GAP_RECOVERY_RATE_LIMIT", "50.5")
+
+	cfg := NewConfig()
+	if err := cfg.LoadFromEnv(); err != nil {
+		t.Fatalf("LoadFromEnv() error = %v", err)
+	}
+	gap := cfg.Indexer.GapRecovery
+	if gap.Workers != 4 || gap.BatchSize != 500 || gap.RateLimit != 50.5 {
+		t.Errorf("Expected gap recovery {4 500 50.5}, got %+v", gap)
+	}
+
+	cfg.RPC.Endpoint = "http://localhost:8545"
+	cfg.Database.Path = "/tmp/test"
+	cfg.Indexer.GapRecovery.RateLimit = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for negative gap recovery rate limit, got nil")
+	}
+}
+
+func TestEventBusBufferSizesFromEnv(t *testing.T) {
+	t.Setenv("INDEXER_EVENTBUS_PUBLISH_BUFFER_SIZE", "5000")
+	t.Setenv("INDEXER_EVENTBUS_SUBSCRIBE_BUFFER_SIZE", "1000")
+
+	cfg := NewConfig()
+	if cfg.EventBus.SubscribeBufferSize != 100 {
+		t.Errorf("Expected default subscribe buffer size 100, got %d", cfg.EventBus.SubscribeBufferSize)
+	}
+	if err := cfg.LoadFromEnv(); err != nil {
+		t.Fatalf("LoadFromEnv() error = %v", err)
+	}
+	if cfg.EventBus.PublishBufferSize != 5000 || cfg.EventBus.SubscribeBufferSize != 1000 {
+		t.Errorf("Expected buffer sizes {5000 1000}, got {%d %d}",
+			cfg.EventBus.PublishBufferSize, cfg.EventBus.SubscribeBufferSize)
+	}
+
+	cfg.RPC.Endpoint = "http://localhost:8545"
+	cfg.Database.Path = "/tmp/test"
+	cfg.EventBus.SubscribeBufferSize = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for negative subscribe buffer size, got nil")
+	}
+}
@@ -31,6 +31,26 @@ const (
 	// DefaultMaxHeaderBytes is the default maximum request header size (1 MB)
 	DefaultMaxHeaderBytes = 1 << 20 // 1 MB
 
+	// DefaultRequestTimeout is the default per-request handler timeout for JSON-RPC and GraphQL
+	DefaultRequestTimeout = 10 * time.Second
+
+	// DefaultMaxRequestBytes is the default maximum JSON-RPC/GraphQL request body size (2 MB)
+	DefaultMaxRequestBytes = 2 << 20 // 2 MB
+
+	// DefaultGraphQLMaxDepth is the default maximum GraphQL field nesting depth
+	DefaultGraphQLMaxDepth = 12
+
+	// DefaultGraphQLMaxComplexity is the default maximum estimated GraphQL query cost.
+	// Each field costs 1 and paginated fields multiply their selection cost by the limit.
+	DefaultGraphQLMaxComplexity = 20000
+
+	// DefaultWebSocketPingInterval is how often WebSocket ping frames are sent
+	DefaultWebSocketPingInterval = 54 * time.Second
+
+	// DefaultWebSocketIdleTimeout closes WebSocket connections that have not
+	// answered a ping or sent a message for this long
+	DefaultWebSocketIdleTimeout = 60 * time.Second
+
 	// DefaultRateLimitPerSecond is the default rate limit (requests per second)
 	DefaultRateLimitPerSecond = 1000
 
@@ -52,10 +72,24 @@ const (
 	// DefaultWebSocketPath is the default WebSocket endpoint path
 	DefaultWebSocketPath = "/ws"
 
+	// DefaultRESTPath is the default REST API mount path
+	DefaultRESTPath = "/rest"
+
 	// DefaultGraphQLSubscriptionPath is the default GraphQL subscription (WebSocket) path
 	DefaultGraphQLSubscriptionPath = "/graphql/ws"
 )
 
+// Database Constants
+const (
+	// DefaultDBMemoryFraction is the default fraction of system memory used to size
+	// the pebble block cache and memtables
+	DefaultDBMemoryFraction = 0.25
+
+	// DefaultColdRetainBlocks is the default number of recent blocks kept in the
+	// hot database when a cold archive is configured
+	DefaultColdRetainBlocks = 100000
+)
+
 // Fetcher Constants
 const (
 	// DefaultNumWorkers is the default number of worker goroutines for concurrent fetching
@@ -79,6 +113,9 @@ const (
 	// DefaultRetryBackoffMultiplier is the default backoff multiplier for exponential backoff
 	DefaultRetryBackoffMultiplier = 2
 
+	// DefaultCheckpointInterval is how often the fetcher fsyncs its progress checkpoint
+	DefaultCheckpointInterval = 10 * time.Second
+
 	// Adaptive Optimization Constants
 	// DefaultMetricsWindowSize is the size of the sliding window for metrics averaging
 	DefaultMetricsWindowSize = 100
@@ -38,6 +38,9 @@ type Config struct {
 // contextKey is a private type for context keys to avoid collisions
 type contextKey struct{}
 
+// requestIDKey is a private type for the request ID context key
+type requestIDKey struct{}
+
 // loggerKey is the context key for storing logger instances
 var loggerKey = contextKey{}
 
@@ -62,10 +65,43 @@ func NewProduction() (*zap.Logger, error) {
 	return config.Build()
 }
 
+// NewProductionWithLevel creates a production logger starting at level.
+// The returned AtomicLevel changes the level of the running logger.
+func NewProductionWithLevel(level string) (*zap.Logger, zap.AtomicLevel, error) {
+	config := zap.NewProductionConfig()
+	if level != "" {
+		if err := SetLevel(config.Level, level); err != nil {
+			return nil, zap.AtomicLevel{}, err
+		}
+	}
+	logger, err := config.Build()
+	if err != nil {
+		return nil, zap.AtomicLevel{}, fmt.Errorf("failed to build logger: %w", err)
+	}
+	return logger, config.Level, nil
+}
+
+// SetLevel parses a level name and applies it to level
+func SetLevel(level zap.AtomicLevel, name string) error {
+	var l zapcore.Level
+	if err := l.UnmarshalText([]byte(name)); err != nil {
+		return fmt.Errorf("invalid log level %q: %w", name, err)
+	}
+	level.SetLevel(l)
+	return nil
+}
+
 // NewWithConfig creates a logger with the specified configuration
 func NewWithConfig(cfg *Config) (*zap.Logger, error) {
+	logger, _, err := NewWithConfigAndLevel(cfg)
+	return logger, err
+}
+
+// NewWithConfigAndLevel creates a logger with the specified configuration.
+// The returned AtomicLevel changes the level of the running logger.
+func NewWithConfigAndLevel(cfg *Config) (*zap.Logger, zap.AtomicLevel, error) {
 	if cfg == nil {
-		return nil, fmt.Errorf("config cannot be nil")
+		return nil, zap.AtomicLevel{}, fmt.Errorf("config cannot be nil")
 	}
 
 	// Set defaults
@@ -85,7 +121,7 @@ func NewWithConfig(cfg *Config) (*zap.Logger, error) {
 	// Parse log level
 	level := zap.NewAtomicLevel()
 	if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
-		return nil, fmt.Errorf("invalid log level %q: %w", cfg.Level, err)
+		return nil, zap.AtomicLevel{}, fmt.Errorf("invalid log level %q: %w", cfg.Level, err)
 	}
 
 	// Build encoder config
@@ -115,10 +151,10 @@ func NewWithConfig(cfg *Config) (*zap.Logger, error) {
 		zap.AddCallerSkip(0),
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to build logger: %w", err)
+		return nil, zap.AtomicLevel{}, fmt.Errorf("failed to build logger: %w", err)
 	}
 
-	return logger, nil
+	return logger, level, nil
 }
 
 // WithLogger returns a new context with the given logger attached
@@ -140,6 +176,31 @@ func FromContext(ctx context.Context) *zap.Logger {
 	return zap.NewNop()
 }
 
+// WithRequestID returns a new context carrying the ID of the API request
+// being served
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID stored in the context, if any
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok && id != ""
+}
+
+// ForContext returns base tagged with the request ID from the context, so
+// components that keep their own logger log under the request that invoked
+// them. Without a request ID, base is returned unchanged.
+func ForContext(ctx context.Context, base *zap.Logger) *zap.Logger {
+	if id, ok := RequestIDFromContext(ctx); ok {
+		return base.With(zap.String("request_id", id))
+	}
+	return base
+}
+
 // WithComponent returns a logger with a "component" field
 func WithComponent(logger *zap.Logger, component string) *zap.Logger {
 	return logger.With(zap.String("component", component))
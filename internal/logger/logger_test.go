@@ -283,6 +283,31 @@ func TestContextLoggerFallback(t *testing.T) {
 	logger.Info("test message")
 }
 
+// TestForContext tests tagging a component logger with the request ID
+func TestForContext(t *testing.T) {
+	var buf bytes.Buffer
+	core := zapcore.NewCore(
+		zapcore.NewJSONEncoder(zapcore.EncoderConfig{MessageKey: "msg"}),
+		zapcore.AddSync(&buf),
+		zapcore.InfoLevel,
+	)
+	base := zap.New(core)
+
+	if got := ForContext(context.Background(), base); got != base {
+		t.Error("ForContext() without a request ID should return the base logger")
+	}
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	if id, ok := RequestIDFromContext(ctx); !ok || id != "req-1" {
+		t.Errorf("RequestIDFromContext() = %q, %v, want req-1, true", id, ok)
+	}
+
+	ForContext(ctx, base).Info("tagged")
+	if !strings.Contains(buf.String(), `"request_id":"req-1"`) {
+		t.Errorf("log output %q is missing the request ID", buf.String())
+	}
+}
+
 // TestLoggerWithFields tests adding fields to logger
 func TestLoggerWithFields(t *testing.T) {
 	var buf bytes.Buffer
@@ -363,3 +388,32 @@ func TestConfigValidation(t *testing.T) {
 		})
 	}
 }
+
+// TestSetLevel tests changing the level of a running logger
+func TestSetLevel(t *testing.T) {
+	logger, level, err := NewProductionWithLevel("warn")
+	if err != nil {
+		t.Fatalf("NewProductionWithLevel() error = %v", err)
+	}
+	if logger.Core().Enabled(zapcore.InfoLevel) {
+		t.Error("info should be disabled at warn level")
+	}
+
+	if err := SetLevel(level, "debug"); err != nil {
+		t.Fatalf("SetLevel() error = %v", err)
+	}
+	if !logger.Core().Enabled(zapcore.DebugLevel) {
+		t.Error("debug should be enabled after SetLevel(debug)")
+	}
+
+	if err := SetLevel(level, "verbose"); err == nil {
+		t.Error("SetLevel() should fail for an invalid level")
+	}
+	if level.Level() != zapcore.DebugLevel {
+		t.Errorf("level = %s, want debug", level.Level())
+	}
+
+	if _, _, err := NewProductionWithLevel("verbose"); err == nil {
+		t.Error("NewProductionWithLevel() should fail for an invalid level")
+	}
+}
@@ -181,6 +181,30 @@ func (f *BlockFetcher) GetBlockByHash(ctx context.Context, hash common.Hash) (*t
 	return f.client.GetBlockByHash(ctx, hash)
 }
 
+// BlockRangeClient is implemented by clients that fetch a range of blocks in
+// one batched request
+type BlockRangeClient interface {
+	GetBlocksByRange(ctx context.Context, from, to uint64) ([]*types.Block, error)
+}
+
+// GetBlocksByRange retrieves the blocks in [from, to] in height order, in one
+// batched request when the client supports it
+func (f *BlockFetcher) GetBlocksByRange(ctx context.Context, from, to uint64) ([]*types.Block, error) {
+	if rangeClient, ok := f.client.(BlockRangeClient); ok {
+		return rangeClient.GetBlocksByRange(ctx, from, to)
+	}
+
+	var blocks []*types.Block
+	for number := from; number <= to; number++ {
+		block, err := f.client.GetBlockByNumber(ctx, number)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks, nil
+}
+
 // GetBlockReceipts retrieves all receipts for a block
 func (f *BlockFetcher) GetBlockReceipts(ctx context.Context, blockNumber uint64) (types.Receipts, error) {
 	return f.client.GetBlockReceipts(ctx, blockNumber)
@@ -129,6 +129,9 @@ type FeeDelegationMeta struct {
 	FeePayerV    *big.Int
 	FeePayerR    *big.Int
 	FeePayerS    *big.Int
+	// Sender is the transaction sender reported by the node, as the sender
+	// signature of the converted transaction does not recover it
+	Sender common.Address
 }
 
 // rpcTransaction is a helper for parsing transactions
@@ -454,6 +457,9 @@ func parseRawBlockWithMetas(raw json.RawMessage) (*types.Block, []*FeeDelegation
 				FeePayerR:    tx.feePayerR,
 				FeePayerS:    tx.feePayerS,
 			}
+			if tx.From != nil {
+				meta.Sender = *tx.From
+			}
 			feeDelegationMetas = append(feeDelegationMetas, meta)
 		}
 	}
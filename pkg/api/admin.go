@@ -0,0 +1,73 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	apimiddleware "github.com/0xmhha/indexer-go/pkg/api/middleware"
+	"github.com/go-chi/chi/v5"
+)
+
+// IndexingController pauses and resumes block indexing. It is implemented by
+// the fetcher running in the same process.
+type IndexingController interface {
+	Pause()
+	Resume()
+	Paused() bool
+}
+
+// IndexingStatus is the response of the /admin/indexing endpoints
+type IndexingStatus struct {
+	Paused bool `json:"paused"`
+}
+
+// adminPaths are the admin endpoints, which accept AdminAPIKeys only
+var adminPaths = []string{"/admin/indexing", "/admin/indexing/pause", "/admin/indexing/resume"}
+
+// adminRoutes returns the admin endpoints mounted under /admin, which always
+// require one of the admin API keys
+func (s *Server) adminRoutes() http.Handler {
+	r := chi.NewRouter()
+	r.Use(apimiddleware.APIKeyAuth(apimiddleware.AuthConfig{APIKeys: s.config.AdminAPIKeys}, s.logger))
+	r.Get("/indexing", s.handleIndexingStatus)
+	r.Post("/indexing/pause", s.handlePauseIndexing)
+	r.Post("/indexing/resume", s.handleResumeIndexing)
+	return r
+}
+
+// handleIndexingStatus reports whether indexing is paused
+func (s *Server) handleIndexingStatus(w http.ResponseWriter, r *http.Request) {
+	s.writeIndexingStatus(w, r)
+}
+
+// handlePauseIndexing pauses indexing once the batch in progress is committed
+func (s *Server) handlePauseIndexing(w http.ResponseWriter, r *http.Request) {
+	if s.indexing != nil {
+		s.indexing.Pause()
+	}
+	s.writeIndexingStatus(w, r)
+}
+
+// handleResumeIndexing resumes paused indexing
+func (s *Server) handleResumeIndexing(w http.ResponseWriter, r *http.Request) {
+	if s.indexing != nil {
+		s.indexing.Resume()
+	}
+	s.writeIndexingStatus(w, r)
+}
+
+// writeIndexingStatus writes the indexing state, or 503 if this process does
+// not index (API-only or multi-chain mode)
+func (s *Server) writeIndexingStatus(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if s.indexing == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"error":      "indexing is not controllable in this process",
+			"request_id": apimiddleware.RequestIDFromRequest(r),
+		})
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(IndexingStatus{Paused: s.indexing.Paused()})
+}
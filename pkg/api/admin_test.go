@@ -0,0 +1,162 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// mockIndexingController records the pause state
+type mockIndexingController struct {
+	mu     sync.Mutex
+	paused bool
+}
+
+func (m *mockIndexingController) Pause() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.paused = true
+}
+
+func (m *mockIndexingController) Resume() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.paused = false
+}
+
+func (m *mockIndexingController) Paused() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.paused
+}
+
+// adminTestKey is the admin API key configured by newAdminTestConfig
+const adminTestKey = "admin-secret"
+
+// newAdminTestConfig returns a config with the admin API enabled
+func newAdminTestConfig() *Config {
+	config := DefaultConfig()
+	config.EnableWebSocket = false
+	config.EnableAdminAPI = true
+	config.AdminAPIKeys = map[string]string{adminTestKey: "admin"}
+	return config
+}
+
+func doAdminRequest(t *testing.T, server *Server, method, path string) (int, IndexingStatus) {
+	t.Helper()
+	return doAdminRequestWithKey(t, server, method, path, adminTestKey)
+}
+
+func doAdminRequestWithKey(t *testing.T, server *Server, method, path, key string) (int, IndexingStatus) {
+	t.Helper()
+
+	req := httptest.NewRequest(method, path, nil)
+	if key != "" {
+		req.Header.Set("X-API-Key", key)
+	}
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+
+	var status IndexingStatus
+	if w.Code == http.StatusOK {
+		if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil {
+			t.Fatalf("failed to decode %s response: %v", path, err)
+		}
+	}
+	return w.Code, status
+}
+
+func TestAdminIndexingEndpoints(t *testing.T) {
+	config := newAdminTestConfig()
+	controller := &mockIndexingController{}
+
+	server, err := NewServerWithOptions(config, zap.NewNop(), &infoStorage{}, &ServerOptions{IndexingController: controller})
+	if err != nil {
+		t.Fatalf("NewServerWithOptions() error = %v", err)
+	}
+
+	if code, status := doAdminRequest(t, server, http.MethodPost, "/admin/indexing/pause"); code != http.StatusOK || !status.Paused {
+		t.Fatalf("pause = %d %+v, want 200 paused", code, status)
+	}
+	if !controller.Paused() {
+		t.Error("controller not paused")
+	}
+	if paused := getInfo(t, server).IndexingPaused; paused == nil || !*paused {
+		t.Errorf("info indexing_paused = %v, want true", paused)
+	}
+	if code, status := doAdminRequest(t, server, http.MethodGet, "/admin/indexing"); code != http.StatusOK || !status.Paused {
+		t.Errorf("status = %d %+v, want 200 paused", code, status)
+	}
+
+	if code, status := doAdminRequest(t, server, http.MethodPost, "/admin/indexing/resume"); code != http.StatusOK || status.Paused {
+		t.Fatalf("resume = %d %+v, want 200 running", code, status)
+	}
+	if controller.Paused() {
+		t.Error("controller still paused")
+	}
+	if paused := getInfo(t, server).IndexingPaused; paused == nil || *paused {
+		t.Errorf("info indexing_paused = %v, want false", paused)
+	}
+
+	if code, _ := doAdminRequest(t, server, http.MethodGet, "/admin/indexing/pause"); code != http.StatusMethodNotAllowed {
+		t.Errorf("GET pause = %d, want %d", code, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestAdminIndexingEndpointsUnavailable(t *testing.T) {
+	// Disabled by default
+	server, err := NewServerWithOptions(DefaultConfig(), zap.NewNop(), &infoStorage{}, &ServerOptions{IndexingController: &mockIndexingController{}})
+	if err != nil {
+		t.Fatalf("NewServerWithOptions() error = %v", err)
+	}
+	if code, _ := doAdminRequest(t, server, http.MethodPost, "/admin/indexing/pause"); code != http.StatusNotFound {
+		t.Errorf("pause with admin API disabled = %d, want %d", code, http.StatusNotFound)
+	}
+
+	// Enabled without an indexer in the process
+	server, err = NewServer(newAdminTestConfig(), zap.NewNop(), &infoStorage{})
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	if code, _ := doAdminRequest(t, server, http.MethodPost, "/admin/indexing/pause"); code != http.StatusServiceUnavailable {
+		t.Errorf("pause without indexer = %d, want %d", code, http.StatusServiceUnavailable)
+	}
+	if paused := getInfo(t, server).IndexingPaused; paused != nil {
+		t.Errorf("info indexing_paused = %v, want none", *paused)
+	}
+}
+
+func TestAdminIndexingEndpointsAuth(t *testing.T) {
+	// Enabling the admin API without admin keys is refused
+	config := DefaultConfig()
+	config.EnableAdminAPI = true
+	if _, err := NewServer(config, zap.NewNop(), &infoStorage{}); err == nil {
+		t.Error("NewServer() with the admin API and no admin keys should fail")
+	}
+
+	// API key auth is on with separate keys: only admin keys reach /admin
+	config = newAdminTestConfig()
+	config.EnableAPIKeyAuth = true
+	config.APIKeys = map[string]string{"user-secret": "user"}
+	controller := &mockIndexingController{}
+	server, err := NewServerWithOptions(config, zap.NewNop(), &infoStorage{}, &ServerOptions{IndexingController: controller})
+	if err != nil {
+		t.Fatalf("NewServerWithOptions() error = %v", err)
+	}
+
+	for _, key := range []string{"", "user-secret", "wrong"} {
+		if code, _ := doAdminRequestWithKey(t, server, http.MethodPost, "/admin/indexing/pause", key); code != http.StatusUnauthorized {
+			t.Errorf("pause with key %q = %d, want %d", key, code, http.StatusUnauthorized)
+		}
+	}
+	if controller.Paused() {
+		t.Fatal("controller paused without an admin key")
+	}
+	if code, status := doAdminRequest(t, server, http.MethodPost, "/admin/indexing/pause"); code != http.StatusOK || !status.Paused {
+		t.Errorf("pause with admin key = %d %+v, want 200 paused", code, status)
+	}
+}
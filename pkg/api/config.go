@@ -3,9 +3,11 @@ package api
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/0xmhha/indexer-go/internal/constants"
+	"github.com/ethereum/go-ethereum/common"
 )
 
 // Config holds API server configuration
@@ -34,6 +36,30 @@ type Config struct {
 	// MaxHeaderBytes is the maximum size of request headers
 	MaxHeaderBytes int
 
+	// RequestTimeout bounds how long a single JSON-RPC or GraphQL request may run
+	// Zero uses the default (10s)
+	RequestTimeout time.Duration
+
+	// MaxRequestBytes is the maximum JSON-RPC/GraphQL request body size
+	// Larger bodies are rejected with 413 Request Entity Too Large
+	// Zero uses the default (2 MB)
+	MaxRequestBytes int64
+
+	// GraphQLMaxDepth is the maximum GraphQL field nesting depth
+	// Zero uses the default (12)
+	GraphQLMaxDepth int
+
+	// MaxConcurrentConnections caps the number of requests (including open
+	// WebSocket connections) served at once. Requests over the limit are
+	// rejected with 503 Service Unavailable; /health, /version and /metrics
+	// are exempt. Zero disables the limit.
+	MaxConcurrentConnections int
+
+	// GraphQLMaxComplexity is the maximum estimated GraphQL query cost
+	// Each field costs 1; paginated fields multiply their selection by the limit
+	// Zero uses the default (20000)
+	GraphQLMaxComplexity int
+
 	// EnableGraphQL enables GraphQL API
 	EnableGraphQL bool
 
@@ -43,11 +69,34 @@ type Config struct {
 	// EnableWebSocket enables WebSocket subscriptions
 	EnableWebSocket bool
 
-	// EnableWebSocketKeepAlive enables WebSocket keep-alive (ping/pong)
-	// When enabled, server sends ping every 54 seconds with 60 second timeout
+	// EnableREST enables the read-only REST API under /rest
+	// Default: false
+	EnableREST bool
+
+	// EnableAdminAPI enables the /admin endpoints that pause and resume
+	// indexing. They change indexer state, so they require one of
+	// AdminAPIKeys.
+	// Default: false
+	EnableAdminAPI bool
+
+	// AdminAPIKeys maps the API keys accepted by the /admin endpoints to their
+	// labels. They are checked instead of APIKeys on those endpoints.
+	AdminAPIKeys map[string]string
+
+	// EnableWebSocketKeepAlive enables WebSocket keep-alive (ping/pong) for
+	// GraphQL subscriptions. The /ws endpoint always sends pings.
 	// Default: false
 	EnableWebSocketKeepAlive bool
 
+	// WebSocketPingInterval is how often the server sends ping frames
+	// Zero uses the default (54s)
+	WebSocketPingInterval time.Duration
+
+	// WebSocketIdleTimeout closes connections that have not answered a ping
+	// or sent a message for this long, releasing their subscriptions
+	// Zero uses the default (60s)
+	WebSocketIdleTimeout time.Duration
+
 	// GraphQLPath is the GraphQL endpoint path (default: /graphql)
 	GraphQLPath string
 
@@ -57,6 +106,21 @@ type Config struct {
 	// JSONRPCPath is the JSON-RPC endpoint path (default: /rpc)
 	JSONRPCPath string
 
+	// JSONRPCAllowedMethods, when non-empty, limits JSON-RPC to these methods
+	// JSONRPCDeniedMethods are always rejected, even if also allowed.
+	// Blocked methods get a "method not allowed" MethodNotFound error.
+	JSONRPCAllowedMethods []string
+	JSONRPCDeniedMethods  []string
+
+	// MaskedFields are JSON response fields hidden from clients wherever they
+	// appear, e.g. "input" to withhold transaction input data. MaskedAddresses
+	// are hidden wherever they appear as a string value. Masked values become
+	// "[redacted]", or are removed when OmitMaskedFields is set. WebSocket
+	// and GraphQL subscription messages are masked the same way.
+	MaskedFields     []string
+	MaskedAddresses  []string
+	OmitMaskedFields bool
+
 	// WebSocketPath is the WebSocket endpoint path (default: /ws)
 	WebSocketPath string
 
@@ -82,6 +146,16 @@ type Config struct {
 	// APIKeys maps valid API keys to their labels (for logging/identification)
 	// Example: {"sk-abc123": "frontend-app", "sk-def456": "admin-dashboard"}
 	APIKeys map[string]string
+
+	// TLSCertFile and TLSKeyFile are PEM files for serving HTTPS
+	// When both are set the server only accepts TLS connections.
+	// The certificate is reloaded from disk on SIGHUP.
+	TLSCertFile string
+	TLSKeyFile  string
+
+	// TLSRedirectPort is a plain HTTP port that redirects requests to HTTPS
+	// Zero disables the redirect listener
+	TLSRedirectPort int
 }
 
 // DefaultConfig returns a default API server configuration
@@ -95,6 +169,10 @@ func DefaultConfig() *Config {
 		EnableCORS:               true,
 		AllowedOrigins:           []string{"*"},
 		MaxHeaderBytes:           constants.DefaultMaxHeaderBytes,
+		RequestTimeout:           constants.DefaultRequestTimeout,
+		MaxRequestBytes:          constants.DefaultMaxRequestBytes,
+		GraphQLMaxDepth:          constants.DefaultGraphQLMaxDepth,
+		GraphQLMaxComplexity:     constants.DefaultGraphQLMaxComplexity,
 		EnableGraphQL:            true,
 		EnableJSONRPC:            true,
 		EnableWebSocket:          true,
@@ -131,23 +209,122 @@ func (c *Config) Validate() error {
 	if c.MaxHeaderBytes <= 0 {
 		return errors.New("max header bytes must be positive")
 	}
+	if c.RequestTimeout < 0 {
+		return errors.New("request timeout cannot be negative")
+	}
+	if c.MaxRequestBytes < 0 {
+		return errors.New("max request bytes cannot be negative")
+	}
+	if c.GraphQLMaxDepth < 0 {
+		return errors.New("GraphQL max depth cannot be negative")
+	}
+	if c.GraphQLMaxComplexity < 0 {
+		return errors.New("GraphQL max complexity cannot be negative")
+	}
+	if c.MaxConcurrentConnections < 0 {
+		return errors.New("max concurrent connections cannot be negative")
+	}
 	if c.ShutdownTimeout <= 0 {
 		return errors.New("shutdown timeout must be positive")
 	}
 
 	// At least one API must be enabled
-	if !c.EnableGraphQL && !c.EnableJSONRPC && !c.EnableWebSocket {
-		return errors.New("at least one API (GraphQL, JSON-RPC, or WebSocket) must be enabled")
+	if !c.EnableGraphQL && !c.EnableJSONRPC && !c.EnableWebSocket && !c.EnableREST {
+		return errors.New("at least one API (GraphQL, JSON-RPC, WebSocket, or REST) must be enabled")
+	}
+
+	// Validate JSON-RPC method filter
+	for _, method := range append(append([]string(nil), c.JSONRPCAllowedMethods...), c.JSONRPCDeniedMethods...) {
+		if strings.TrimSpace(method) == "" {
+			return errors.New("JSON-RPC allowed and denied methods cannot be empty")
+		}
+	}
+
+	// Validate response masking
+	for _, field := range c.MaskedFields {
+		if strings.TrimSpace(field) == "" {
+			return errors.New("masked fields cannot be empty")
+		}
+	}
+	for _, addr := range c.MaskedAddresses {
+		if !common.IsHexAddress(addr) {
+			return fmt.Errorf("invalid masked address: %q", addr)
+		}
+	}
+
+	// Validate WebSocket keep-alive intervals
+	if c.WebSocketPingInterval < 0 || c.WebSocketIdleTimeout < 0 {
+		return errors.New("WebSocket ping interval and idle timeout cannot be negative")
+	}
+	if pingInterval, idleTimeout := c.webSocketKeepAlive(); pingInterval >= idleTimeout {
+		return fmt.Errorf("WebSocket ping interval (%s) must be shorter than the idle timeout (%s)", pingInterval, idleTimeout)
 	}
 
 	// Validate API key auth configuration
 	if c.EnableAPIKeyAuth && len(c.APIKeys) == 0 {
 		return errors.New("API key auth is enabled but no API keys are configured")
 	}
+	if c.EnableAdminAPI && len(c.AdminAPIKeys) == 0 {
+		return errors.New("admin API is enabled but no admin API keys are configured")
+	}
+
+	// Validate TLS configuration
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		return errors.New("TLS cert file and key file must be set together")
+	}
+	if c.TLSRedirectPort != 0 {
+		if !c.TLSEnabled() {
+			return errors.New("TLS redirect port requires a TLS cert and key")
+		}
+		if c.TLSRedirectPort < constants.MinPort || c.TLSRedirectPort > constants.MaxPort {
+			return fmt.Errorf("TLS redirect port must be between %d and %d", constants.MinPort, constants.MaxPort)
+		}
+		if c.TLSRedirectPort == c.Port {
+			return errors.New("TLS redirect port must differ from the server port")
+		}
+	}
 
 	return nil
 }
 
+// requestLimits returns the request timeout and body size limit, falling back
+// to the defaults for unset values
+func (c *Config) requestLimits() (time.Duration, int64) {
+	timeout := c.RequestTimeout
+	if timeout == 0 {
+		timeout = constants.DefaultRequestTimeout
+	}
+	maxBytes := c.MaxRequestBytes
+	if maxBytes == 0 {
+		maxBytes = constants.DefaultMaxRequestBytes
+	}
+	return timeout, maxBytes
+}
+
+// webSocketKeepAlive returns the WebSocket ping interval and idle timeout,
+// falling back to the defaults for unset values
+func (c *Config) webSocketKeepAlive() (time.Duration, time.Duration) {
+	pingInterval := c.WebSocketPingInterval
+	if pingInterval == 0 {
+		pingInterval = constants.DefaultWebSocketPingInterval
+	}
+	idleTimeout := c.WebSocketIdleTimeout
+	if idleTimeout == 0 {
+		idleTimeout = constants.DefaultWebSocketIdleTimeout
+	}
+	return pingInterval, idleTimeout
+}
+
+// ResponseMaskEnabled reports whether any response fields or addresses are masked
+func (c *Config) ResponseMaskEnabled() bool {
+	return len(c.MaskedFields) > 0 || len(c.MaskedAddresses) > 0
+}
+
+// TLSEnabled reports whether the server serves HTTPS
+func (c *Config) TLSEnabled() bool {
+	return c.TLSCertFile != "" && c.TLSKeyFile != ""
+}
+
 // Address returns the server address in host:port format
 func (c *Config) Address() string {
 	return c.Host + ":" + fmt.Sprintf("%d", c.Port)
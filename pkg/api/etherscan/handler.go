@@ -3,6 +3,7 @@ package etherscan
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -329,7 +330,7 @@ func (h *Handler) handleGetABI(w http.ResponseWriter, r *http.Request) {
 
 	verification, err := reader.GetContractVerification(context.Background(), address)
 	if err != nil {
-		if err == storage.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			h.sendError(w, "Contract source code not verified")
 			return
 		}
@@ -370,7 +371,7 @@ func (h *Handler) handleGetSourceCode(w http.ResponseWriter, r *http.Request) {
 
 	verification, err := reader.GetContractVerification(context.Background(), address)
 	if err != nil {
-		if err == storage.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			h.sendError(w, "Contract source code not verified")
 			return
 		}
@@ -0,0 +1,296 @@
+package graphql
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/0xmhha/indexer-go/internal/constants"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// maxQueryCost caps intermediate cost values so huge limits cannot overflow
+const maxQueryCost = math.MaxInt64 / 2
+
+// QueryLimits bounds the depth and estimated cost of a GraphQL query
+type QueryLimits struct {
+	// MaxDepth is the maximum field nesting depth (0 uses the default)
+	MaxDepth int
+
+	// MaxComplexity is the maximum estimated query cost (0 uses the default)
+	MaxComplexity int
+}
+
+// withDefaults returns the limits with unset values replaced by the defaults
+func (l QueryLimits) withDefaults() QueryLimits {
+	if l.MaxDepth == 0 {
+		l.MaxDepth = constants.DefaultGraphQLMaxDepth
+	}
+	if l.MaxComplexity == 0 {
+		l.MaxComplexity = constants.DefaultGraphQLMaxComplexity
+	}
+	return l
+}
+
+// QueryLimitError is returned when a query exceeds the depth or complexity limit
+type QueryLimitError struct {
+	Code    string
+	Message string
+}
+
+func (e *QueryLimitError) Error() string {
+	return e.Message
+}
+
+// checkQueryLimits parses query and rejects it when the selected operation is
+// nested deeper than limits.MaxDepth or its estimated cost exceeds
+// limits.MaxComplexity. Each field costs 1; a paginated field multiplies the cost
+// of its selection by the requested limit. Introspection fields are not counted.
+// Queries that fail to parse are left to the executor to report.
+func checkQueryLimits(schema *graphql.Schema, query, operationName string, variables map[string]interface{}, limits QueryLimits) error {
+	if strings.TrimSpace(query) == "" {
+		return nil
+	}
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{
+		Body: []byte(query),
+		Name: "GraphQL request",
+	})})
+	if err != nil {
+		return nil
+	}
+
+	limits = limits.withDefaults()
+	a := &queryAnalyzer{
+		schema:    schema,
+		fragments: make(map[string]*ast.FragmentDefinition),
+	}
+	var operations []*ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		switch d := def.(type) {
+		case *ast.FragmentDefinition:
+			a.fragments[d.Name.Value] = d
+		case *ast.OperationDefinition:
+			if operationName == "" || (d.Name != nil && d.Name.Value == operationName) {
+				operations = append(operations, d)
+			}
+		}
+	}
+
+	for _, op := range operations {
+		a.variables = operationVariables(op, variables)
+		depth, cost := a.selectionSet(op.SelectionSet, a.rootType(op.Operation), 0, make(map[string]bool))
+		if depth > limits.MaxDepth {
+			return &QueryLimitError{
+				Code:    "QUERY_TOO_DEEP",
+				Message: fmt.Sprintf("query depth %d exceeds the maximum of %d", depth, limits.MaxDepth),
+			}
+		}
+		if cost > int64(limits.MaxComplexity) {
+			return &QueryLimitError{
+				Code:    "QUERY_TOO_COMPLEX",
+				Message: fmt.Sprintf("query complexity %d exceeds the maximum of %d", cost, limits.MaxComplexity),
+			}
+		}
+	}
+	return nil
+}
+
+// queryAnalyzer walks a parsed document alongside the schema
+type queryAnalyzer struct {
+	schema    *graphql.Schema
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+}
+
+// fieldsType is implemented by object and interface types
+type fieldsType interface {
+	Fields() graphql.FieldDefinitionMap
+}
+
+// rootType returns the root object type for an operation, or nil if the
+// schema does not define one
+func (a *queryAnalyzer) rootType(operation string) graphql.Type {
+	var root *graphql.Object
+	switch operation {
+	case ast.OperationTypeMutation:
+		root = a.schema.MutationType()
+	case ast.OperationTypeSubscription:
+		root = a.schema.SubscriptionType()
+	default:
+		root = a.schema.QueryType()
+	}
+	if root == nil {
+		return nil
+	}
+	return root
+}
+
+// selectionSet returns the maximum depth below depth and the summed cost of the
+// selections. visiting guards against fragment cycles, which validation rejects later.
+func (a *queryAnalyzer) selectionSet(set *ast.SelectionSet, parent graphql.Type, depth int, visiting map[string]bool) (int, int64) {
+	if set == nil {
+		return depth, 0
+	}
+
+	maxDepth := depth
+	var cost int64
+	for _, selection := range set.Selections {
+		var d int
+		var c int64
+		switch s := selection.(type) {
+		case *ast.Field:
+			d, c = a.field(s, parent, depth, visiting)
+		case *ast.InlineFragment:
+			d, c = a.selectionSet(s.SelectionSet, a.conditionType(s.TypeCondition, parent), depth, visiting)
+		case *ast.FragmentSpread:
+			name := s.Name.Value
+			fragment, ok := a.fragments[name]
+			if !ok || visiting[name] {
+				continue
+			}
+			visiting[name] = true
+			d, c = a.selectionSet(fragment.SelectionSet, a.conditionType(fragment.TypeCondition, parent), depth, visiting)
+			delete(visiting, name)
+		}
+		if d > maxDepth {
+			maxDepth = d
+		}
+		cost = addCost(cost, c)
+	}
+	return maxDepth, cost
+}
+
+// field returns the depth and cost of a single field including its selection
+func (a *queryAnalyzer) field(field *ast.Field, parent graphql.Type, depth int, visiting map[string]bool) (int, int64) {
+	if strings.HasPrefix(field.Name.Value, "__") {
+		return depth, 0
+	}
+
+	var def *graphql.FieldDefinition
+	if t, ok := parent.(fieldsType); ok {
+		def = t.Fields()[field.Name.Value]
+	}
+
+	var childType graphql.Type
+	multiplier := int64(1)
+	if def != nil {
+		childType, _ = graphql.GetNamed(def.Type).(graphql.Type)
+		multiplier = a.listMultiplier(field, def)
+	}
+
+	d, childCost := a.selectionSet(field.SelectionSet, childType, depth+1, visiting)
+	return d, mulCost(multiplier, addCost(1, childCost))
+}
+
+// listMultiplier returns the requested page size for fields that accept a
+// limit or pagination argument, or 1 for all other fields
+func (a *queryAnalyzer) listMultiplier(field *ast.Field, def *graphql.FieldDefinition) int64 {
+	paginated := false
+	for _, arg := range def.Args {
+		if arg.Name() == "limit" || arg.Name() == "pagination" {
+			paginated = true
+			break
+		}
+	}
+	if !paginated {
+		return 1
+	}
+
+	limit := int64(constants.DefaultPaginationLimit)
+	for _, arg := range field.Arguments {
+		var value interface{}
+		switch arg.Name.Value {
+		case "limit":
+			value = resolveValue(arg.Value, a.variables)
+		case "pagination":
+			if pagination, ok := resolveValue(arg.Value, a.variables).(map[string]interface{}); ok {
+				value = pagination["limit"]
+			}
+		}
+		if n, ok := toInt64(value); ok && n > 0 {
+			limit = n
+		}
+	}
+	return limit
+}
+
+// resolveValue converts an argument literal into a Go value, substituting variables
+func resolveValue(value ast.Value, variables map[string]interface{}) interface{} {
+	switch v := value.(type) {
+	case *ast.Variable:
+		return variables[v.Name.Value]
+	case *ast.IntValue:
+		n, err := strconv.ParseInt(v.Value, 10, 64)
+		if err != nil {
+			return nil
+		}
+		return n
+	case *ast.ObjectValue:
+		obj := make(map[string]interface{}, len(v.Fields))
+		for _, f := range v.Fields {
+			obj[f.Name.Value] = resolveValue(f.Value, variables)
+		}
+		return obj
+	}
+	return nil
+}
+
+// conditionType returns the type named by a fragment type condition, or parent
+// when there is none
+func (a *queryAnalyzer) conditionType(condition *ast.Named, parent graphql.Type) graphql.Type {
+	if condition == nil || condition.Name == nil {
+		return parent
+	}
+	if t := a.schema.Type(condition.Name.Value); t != nil {
+		return t
+	}
+	return parent
+}
+
+// operationVariables merges the request variables over the operation defaults
+func operationVariables(op *ast.OperationDefinition, variables map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(variables))
+	for _, def := range op.VariableDefinitions {
+		if def.DefaultValue != nil && def.Variable != nil && def.Variable.Name != nil {
+			merged[def.Variable.Name.Value] = resolveValue(def.DefaultValue, nil)
+		}
+	}
+	for k, v := range variables {
+		merged[k] = v
+	}
+	return merged
+}
+
+// toInt64 converts literal and JSON-decoded numbers to int64
+func toInt64(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return n, true
+	case int:
+		return int64(n), true
+	case float64:
+		if n > float64(maxQueryCost) {
+			return maxQueryCost, true
+		}
+		return int64(n), true
+	}
+	return 0, false
+}
+
+func addCost(a, b int64) int64 {
+	if a > maxQueryCost-b {
+		return maxQueryCost
+	}
+	return a + b
+}
+
+func mulCost(a, b int64) int64 {
+	if a != 0 && b > maxQueryCost/a {
+		return maxQueryCost
+	}
+	return a * b
+}
@@ -0,0 +1,131 @@
+package graphql
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+)
+
+func newLimitedHandler(t *testing.T, limits QueryLimits) *Handler {
+	t.Helper()
+	store := &mockStorage{
+		latestHeight: 100,
+		blocks:       make(map[uint64]*types.Block),
+		blocksByHash: make(map[common.Hash]*types.Block),
+		transactions: make(map[common.Hash]*types.Transaction),
+		receipts:     make(map[common.Hash]*types.Receipt),
+	}
+	handler, err := NewHandlerWithOptions(store, zap.NewNop(), &HandlerOptions{QueryLimits: limits})
+	if err != nil {
+		t.Fatalf("failed to create handler: %v", err)
+	}
+	return handler
+}
+
+// postQuery sends body to the handler and decodes the GraphQL response errors
+func postQuery(t *testing.T, handler *Handler, body string) []map[string]interface{} {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status OK, got %v", w.Code)
+	}
+	var resp struct {
+		Errors []map[string]interface{} `json:"errors"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response %q: %v", w.Body.String(), err)
+	}
+	return resp.Errors
+}
+
+// assertLimitError checks that errs holds a single query limit error with code
+func assertLimitError(t *testing.T, errs []map[string]interface{}, code string) {
+	t.Helper()
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %v", errs)
+	}
+	extensions, _ := errs[0]["extensions"].(map[string]interface{})
+	if extensions["code"] != code {
+		t.Errorf("expected error code %s, got %v", code, errs[0])
+	}
+}
+
+func TestGraphQLQueryLimits(t *testing.T) {
+	t.Run("DepthExceeded", func(t *testing.T) {
+		handler := newLimitedHandler(t, QueryLimits{MaxDepth: 3})
+
+		errs := postQuery(t, handler, `{"query":"{ blocks(pagination: {limit: 1}) { nodes { transactions { hash } } } }"}`)
+		assertLimitError(t, errs, "QUERY_TOO_DEEP")
+		if msg, _ := errs[0]["message"].(string); !strings.Contains(msg, "query depth 4 exceeds the maximum of 3") {
+			t.Errorf("unexpected message %q", msg)
+		}
+	})
+
+	t.Run("DepthWithinLimit", func(t *testing.T) {
+		handler := newLimitedHandler(t, QueryLimits{MaxDepth: 3})
+
+		result := handler.ExecuteQuery(`{ blocks(pagination: {limit: 1}) { nodes { number } } }`, nil)
+		if len(result.Errors) > 0 {
+			t.Errorf("expected no errors, got %v", result.Errors)
+		}
+	})
+
+	t.Run("DepthCountsFragments", func(t *testing.T) {
+		handler := newLimitedHandler(t, QueryLimits{MaxDepth: 3})
+
+		query := `query { blocks { ...page } } fragment page on BlockConnection { nodes { transactions { hash } } }`
+		result := handler.ExecuteQuery(query, nil)
+		if len(result.Errors) != 1 || result.Errors[0].Extensions["code"] != "QUERY_TOO_DEEP" {
+			t.Errorf("expected depth error, got %v", result.Errors)
+		}
+	})
+
+	t.Run("ComplexityExceeded", func(t *testing.T) {
+		handler := newLimitedHandler(t, QueryLimits{MaxComplexity: 200})
+
+		// 100 * (blocks + nodes + number + hash) = 400
+		errs := postQuery(t, handler, `{"query":"{ blocks(pagination: {limit: 100}) { nodes { number hash } } }"}`)
+		assertLimitError(t, errs, "QUERY_TOO_COMPLEX")
+		if msg, _ := errs[0]["message"].(string); !strings.Contains(msg, "query complexity 400 exceeds the maximum of 200") {
+			t.Errorf("unexpected message %q", msg)
+		}
+	})
+
+	t.Run("ComplexityFromVariables", func(t *testing.T) {
+		handler := newLimitedHandler(t, QueryLimits{MaxComplexity: 200})
+
+		body := `{"query":"query($p: PaginationInput) { blocks(pagination: $p) { nodes { number hash } } }","variables":{"p":{"limit":100}}}`
+		assertLimitError(t, postQuery(t, handler, body), "QUERY_TOO_COMPLEX")
+	})
+
+	t.Run("ComplexityWithinLimit", func(t *testing.T) {
+		handler := newLimitedHandler(t, QueryLimits{MaxComplexity: 200})
+
+		// Unset pagination uses the default page size: 10 * 4 = 40
+		errs := postQuery(t, handler, `{"query":"{ blocks { nodes { number hash } } }"}`)
+		if len(errs) > 0 {
+			t.Errorf("expected no errors, got %v", errs)
+		}
+	})
+
+	t.Run("IntrospectionNotCounted", func(t *testing.T) {
+		handler := newLimitedHandler(t, QueryLimits{MaxDepth: 2, MaxComplexity: 5})
+
+		query := `{ __schema { types { name fields { name type { name ofType { name ofType { name } } } } } } }`
+		result := handler.ExecuteQuery(query, nil)
+		if len(result.Errors) > 0 {
+			t.Errorf("expected no errors, got %v", result.Errors)
+		}
+	})
+}
@@ -1,14 +1,19 @@
 package graphql
 
 import (
+	"bytes"
 	"encoding/json"
+	"io"
 	"net/http"
+	"strings"
 
+	"github.com/0xmhha/indexer-go/internal/logger"
 	"github.com/0xmhha/indexer-go/pkg/events"
 	"github.com/0xmhha/indexer-go/pkg/notifications"
 	"github.com/0xmhha/indexer-go/pkg/rpcproxy"
 	"github.com/0xmhha/indexer-go/pkg/storage"
 	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
 	graphqlhandler "github.com/graphql-go/handler"
 	"go.uber.org/zap"
 )
@@ -18,6 +23,7 @@ type Handler struct {
 	schema  *Schema
 	handler *graphqlhandler.Handler
 	logger  *zap.Logger
+	limits  QueryLimits
 }
 
 // HandlerOptions contains optional configuration for the GraphQL handler
@@ -25,6 +31,9 @@ type HandlerOptions struct {
 	RPCProxy                    *rpcproxy.Proxy
 	NotificationService         notifications.Service
 	ContractRegistrationService *events.ContractRegistrationService
+
+	// QueryLimits bounds query depth and complexity (zero values use the defaults)
+	QueryLimits QueryLimits
 }
 
 // NewHandler creates a new GraphQL handler
@@ -80,16 +89,136 @@ func NewHandlerWithOptions(store storage.Storage, logger *zap.Logger, opts *Hand
 		Playground: true,
 	})
 
+	var limits QueryLimits
+	if opts != nil {
+		limits = opts.QueryLimits
+	}
+
 	return &Handler{
 		schema:  schema,
 		handler: h,
 		logger:  logger,
+		limits:  limits.withDefaults(),
 	}, nil
 }
 
-// ServeHTTP implements http.Handler
+// ServeHTTP implements http.Handler.
+// Queries exceeding the depth or complexity limits are rejected before execution.
 func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	h.handler.ServeHTTP(w, r)
+	// Buffer the body so it can be inspected and then handed to the executor
+	var body []byte
+	if r.Body != nil {
+		var err error
+		body, err = io.ReadAll(r.Body)
+		_ = r.Body.Close()
+		if err != nil {
+			h.writeErrors(w, r, gqlerrors.FormatError(err))
+			return
+		}
+	}
+
+	inspect := r.Clone(r.Context())
+	inspect.Body = io.NopCloser(bytes.NewReader(body))
+	opts := graphqlhandler.NewRequestOptions(inspect)
+	if err := checkQueryLimits(&h.schema.schema, opts.Query, opts.OperationName, opts.Variables, h.limits); err != nil {
+		h.logger.Debug("GraphQL query rejected", zap.Error(err))
+		h.writeErrors(w, r, queryLimitFormattedError(err))
+		return
+	}
+
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	id, ok := logger.RequestIDFromContext(r.Context())
+	if !ok {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+
+	// Buffer the executor's response so the request ID can be added to its errors
+	buffered := &bufferedResponse{header: w.Header(), status: http.StatusOK}
+	h.handler.ServeHTTP(buffered, r)
+	out := buffered.body.Bytes()
+	if strings.HasPrefix(buffered.header.Get("Content-Type"), "application/json") {
+		tagged, err := tagResultErrors(out, id)
+		if err != nil {
+			h.logger.Warn("Failed to add request ID to GraphQL errors", zap.Error(err))
+		} else {
+			out = tagged
+		}
+	}
+	w.WriteHeader(buffered.status)
+	_, _ = w.Write(out)
+}
+
+// bufferedResponse is an http.ResponseWriter that holds the status and body
+// until they are copied to the real writer
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) Header() http.Header { return b.header }
+
+func (b *bufferedResponse) WriteHeader(status int) { b.status = status }
+
+func (b *bufferedResponse) Write(p []byte) (int, error) { return b.body.Write(p) }
+
+// tagResultErrors adds the request ID to the errors of an encoded GraphQL
+// result. Results without errors are returned unchanged.
+func tagResultErrors(body []byte, id string) ([]byte, error) {
+	var result map[string]json.RawMessage
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, err
+	}
+	raw, ok := result["errors"]
+	if !ok {
+		return body, nil
+	}
+	var errs []gqlerrors.FormattedError
+	if err := json.Unmarshal(raw, &errs); err != nil {
+		return nil, err
+	}
+	encoded, err := json.Marshal(withRequestID(errs, id))
+	if err != nil {
+		return nil, err
+	}
+	result["errors"] = encoded
+	return json.MarshalIndent(result, "", "\t")
+}
+
+// withRequestID sets the request ID as the "request_id" extension of each error
+func withRequestID(errs []gqlerrors.FormattedError, id string) []gqlerrors.FormattedError {
+	tagged := make([]gqlerrors.FormattedError, len(errs))
+	for i, formatted := range errs {
+		extensions := make(map[string]interface{}, len(formatted.Extensions)+1)
+		for k, v := range formatted.Extensions {
+			extensions[k] = v
+		}
+		extensions["request_id"] = id
+		formatted.Extensions = extensions
+		tagged[i] = formatted
+	}
+	return tagged
+}
+
+// writeErrors writes a GraphQL response containing only errors, tagged with
+// the request ID when the request has one
+func (h *Handler) writeErrors(w http.ResponseWriter, r *http.Request, errs ...gqlerrors.FormattedError) {
+	if id, ok := logger.RequestIDFromContext(r.Context()); ok {
+		errs = withRequestID(errs, id)
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(&graphql.Result{Errors: errs})
+}
+
+// queryLimitFormattedError formats err with its limit code as an error extension
+func queryLimitFormattedError(err error) gqlerrors.FormattedError {
+	formatted := gqlerrors.FormatError(err)
+	if limitErr, ok := err.(*QueryLimitError); ok {
+		formatted.Extensions = map[string]interface{}{"code": limitErr.Code}
+	}
+	return formatted
 }
 
 // PlaygroundHandler returns a handler for GraphQL playground
@@ -129,6 +258,9 @@ func (h *Handler) PlaygroundHandler() http.HandlerFunc {
 
 // ExecuteQuery executes a GraphQL query (for testing)
 func (h *Handler) ExecuteQuery(query string, variables map[string]interface{}) *graphql.Result {
+	if err := checkQueryLimits(&h.schema.schema, query, "", variables, h.limits); err != nil {
+		return &graphql.Result{Errors: []gqlerrors.FormattedError{queryLimitFormattedError(err)}}
+	}
 	params := graphql.Params{
 		Schema:         h.schema.schema,
 		RequestString:  query,
@@ -1,7 +1,9 @@
 package graphql
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"math/big"
 	"net/http"
@@ -9,6 +11,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/0xmhha/indexer-go/internal/logger"
 	"github.com/0xmhha/indexer-go/pkg/storage"
 	"github.com/0xmhha/indexer-go/pkg/userop"
 	"github.com/ethereum/go-ethereum/common"
@@ -70,6 +73,10 @@ func (m *mockStorage) GetTransactions(ctx context.Context, hashes []common.Hash)
 	return txs, locs, nil
 }
 
+func (m *mockStorage) GetTransactionsByBlock(ctx context.Context, height uint64) ([]*types.Transaction, []*storage.TxLocation, error) {
+	return nil, nil, storage.ErrNotFound
+}
+
 func (m *mockStorage) GetReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
 	if receipt, ok := m.receipts[hash]; ok {
 		return receipt, nil
@@ -280,8 +287,8 @@ func (m *mockStorage) GetBurnEvents(ctx context.Context, fromBlock, toBlock uint
 	return []*storage.BurnEvent{}, nil
 }
 
-func (m *mockStorage) GetActiveMinters(ctx context.Context) ([]common.Address, error) {
-	return []common.Address{}, nil
+func (m *mockStorage) GetActiveMinters(ctx context.Context) ([]*storage.ActiveMinter, error) {
+	return []*storage.ActiveMinter{}, nil
 }
 
 func (m *mockStorage) GetMinterAllowance(ctx context.Context, minter common.Address) (*big.Int, error) {
@@ -402,7 +409,7 @@ func (m *mockStorage) UpdateValidatorSigningStats(ctx context.Context, blockNumb
 }
 
 // HistoricalReader methods for mockStorage
-func (m *mockStorage) GetBlocksByTimeRange(ctx context.Context, fromTime, toTime uint64, limit, offset int) ([]*types.Block, error) {
+func (m *mockStorage) GetBlocksByTimeRange(ctx context.Context, fromTime, toTime uint64, limit, offset int, order storage.SortOrder) ([]*types.Block, error) {
 	return []*types.Block{}, nil
 }
 
@@ -711,6 +718,10 @@ func (m *mockStorageWithErrors) GetTransactions(ctx context.Context, hashes []co
 	return nil, nil, storage.ErrNotFound
 }
 
+func (m *mockStorageWithErrors) GetTransactionsByBlock(ctx context.Context, height uint64) ([]*types.Transaction, []*storage.TxLocation, error) {
+	return nil, nil, storage.ErrNotFound
+}
+
 func (m *mockStorageWithErrors) GetReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
 	return nil, storage.ErrNotFound
 }
@@ -893,7 +904,7 @@ func (m *mockStorageWithErrors) GetBurnEvents(ctx context.Context, fromBlock, to
 	return nil, fmt.Errorf("storage error")
 }
 
-func (m *mockStorageWithErrors) GetActiveMinters(ctx context.Context) ([]common.Address, error) {
+func (m *mockStorageWithErrors) GetActiveMinters(ctx context.Context) ([]*storage.ActiveMinter, error) {
 	return nil, fmt.Errorf("storage error")
 }
 
@@ -1015,7 +1026,7 @@ func (m *mockStorageWithErrors) UpdateValidatorSigningStats(ctx context.Context,
 }
 
 // HistoricalReader methods for mockStorageWithErrors
-func (m *mockStorageWithErrors) GetBlocksByTimeRange(ctx context.Context, fromTime, toTime uint64, limit, offset int) ([]*types.Block, error) {
+func (m *mockStorageWithErrors) GetBlocksByTimeRange(ctx context.Context, fromTime, toTime uint64, limit, offset int, order storage.SortOrder) ([]*types.Block, error) {
 	return nil, fmt.Errorf("storage error")
 }
 
@@ -1433,6 +1444,79 @@ func TestGraphQLHandler(t *testing.T) {
 	})
 }
 
+func TestGraphQLHandler_RequestIDInErrors(t *testing.T) {
+	store := &mockStorage{
+		latestHeight: 100,
+		blocks:       make(map[uint64]*types.Block),
+		blocksByHash: make(map[common.Hash]*types.Block),
+		transactions: make(map[common.Hash]*types.Transaction),
+		receipts:     make(map[common.Hash]*types.Receipt),
+	}
+	handler, err := NewHandlerWithOptions(store, zap.NewNop(), &HandlerOptions{
+		QueryLimits: QueryLimits{MaxDepth: 2},
+	})
+	if err != nil {
+		t.Fatalf("failed to create handler: %v", err)
+	}
+
+	serve := func(query string, withID bool) map[string]interface{} {
+		body, _ := json.Marshal(map[string]string{"query": query})
+		req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if withID {
+			req = req.WithContext(logger.WithRequestID(req.Context(), "trace-123"))
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		var resp map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to decode response %q: %v", w.Body.String(), err)
+		}
+		return resp
+	}
+	extensions := func(t *testing.T, resp map[string]interface{}) map[string]interface{} {
+		t.Helper()
+		errs, ok := resp["errors"].([]interface{})
+		if !ok || len(errs) == 0 {
+			t.Fatalf("expected errors, got %v", resp)
+		}
+		ext, _ := errs[0].(map[string]interface{})["extensions"].(map[string]interface{})
+		return ext
+	}
+
+	t.Run("execution error", func(t *testing.T) {
+		ext := extensions(t, serve("{ unknownField }", true))
+		if ext["request_id"] != "trace-123" {
+			t.Errorf("extensions = %v, want request_id trace-123", ext)
+		}
+	})
+
+	t.Run("query limit error keeps its code", func(t *testing.T) {
+		ext := extensions(t, serve("{ block(number: \"1\") { transactions { hash } } }", true))
+		if ext["request_id"] != "trace-123" || ext["code"] == nil {
+			t.Errorf("extensions = %v, want request_id trace-123 and a code", ext)
+		}
+	})
+
+	t.Run("successful result unchanged", func(t *testing.T) {
+		resp := serve("{ latestHeight }", true)
+		if _, ok := resp["errors"]; ok {
+			t.Fatalf("unexpected errors: %v", resp["errors"])
+		}
+		if resp["data"] == nil {
+			t.Error("expected data")
+		}
+	})
+
+	t.Run("without request ID", func(t *testing.T) {
+		ext := extensions(t, serve("{ unknownField }", false))
+		if _, ok := ext["request_id"]; ok {
+			t.Errorf("extensions = %v, want no request_id", ext)
+		}
+	})
+}
+
 func TestGraphQLSchema(t *testing.T) {
 	logger := zap.NewNop()
 	store := &mockStorage{
@@ -1743,6 +1827,43 @@ func TestGraphQLResolvers(t *testing.T) {
 	})
 }
 
+// countingMockStorage reports a fixed transaction count for every address
+type countingMockStorage struct {
+	*mockStorage
+	count uint64
+}
+
+func (m *countingMockStorage) CountTransactionsByAddress(ctx context.Context, addr common.Address) (uint64, error) {
+	return m.count, nil
+}
+
+func TestTransactionsByAddress_TotalCount(t *testing.T) {
+	store := &countingMockStorage{
+		mockStorage: &mockStorage{
+			blocks:       make(map[uint64]*types.Block),
+			blocksByHash: make(map[common.Hash]*types.Block),
+		},
+		count: 42,
+	}
+
+	handler, err := NewHandler(store, zap.NewNop())
+	if err != nil {
+		t.Fatalf("failed to create handler: %v", err)
+	}
+
+	// The page is empty but totalCount covers all of the address's transactions
+	query := `{ transactionsByAddress(address: "0x456", pagination: {limit: 5, offset: 100}) { nodes { hash } totalCount } }`
+	result := handler.ExecuteQuery(query, nil)
+	if len(result.Errors) > 0 {
+		t.Fatalf("expected no errors, got %v", result.Errors)
+	}
+
+	conn := result.Data.(map[string]interface{})["transactionsByAddress"].(map[string]interface{})
+	if got := conn["totalCount"]; got != 42 {
+		t.Errorf("totalCount = %v, want 42", got)
+	}
+}
+
 func TestGraphQLErrorPaths(t *testing.T) {
 	logger := zap.NewNop()
 	errorStore := &mockStorageWithErrors{}
@@ -1878,6 +1999,49 @@ func TestGraphQLMappers(t *testing.T) {
 		}
 	})
 
+	t.Run("TransactionToMap_CachedSender", func(t *testing.T) {
+		// Unsigned, so the sender can only come from the location
+		tx := types.NewTransaction(0, common.HexToAddress("0x456"), common.Big1, 21000, common.Big1, nil)
+		from := common.HexToAddress("0x1111111111111111111111111111111111111111")
+		location := &storage.TxLocation{
+			BlockHeight: 1,
+			BlockHash:   common.HexToHash("0x123"),
+			TxIndex:     0,
+			From:        from,
+		}
+		txMap := schema.transactionToMap(tx, location)
+
+		if txMap["from"] != from.Hex() {
+			t.Errorf("expected from %s, got %v", from.Hex(), txMap["from"])
+		}
+	})
+
+	t.Run("TransactionToMap_Blob", func(t *testing.T) {
+		blobHashes := []common.Hash{common.HexToHash("0x01aa"), common.HexToHash("0x01bb")}
+		tx := types.NewTx(&types.BlobTx{
+			ChainID:    uint256FromBig(big.NewInt(1)),
+			GasTipCap:  uint256FromBig(big.NewInt(1)),
+			GasFeeCap:  uint256FromBig(big.NewInt(2)),
+			Gas:        21000,
+			To:         common.HexToAddress("0x456"),
+			Value:      uint256FromBig(common.Big1),
+			BlobFeeCap: uint256FromBig(big.NewInt(3000000000)),
+			BlobHashes: blobHashes,
+		})
+		txMap := schema.transactionToMap(tx, &storage.TxLocation{BlockHeight: 1})
+
+		if txMap["type"] != int(types.BlobTxType) {
+			t.Errorf("expected type %d, got %v", types.BlobTxType, txMap["type"])
+		}
+		if txMap["maxFeePerBlobGas"] != "3000000000" {
+			t.Errorf("expected maxFeePerBlobGas 3000000000, got %v", txMap["maxFeePerBlobGas"])
+		}
+		hashes, ok := txMap["blobVersionedHashes"].([]interface{})
+		if !ok || len(hashes) != 2 || hashes[0] != blobHashes[0].Hex() || hashes[1] != blobHashes[1].Hex() {
+			t.Errorf("expected blobVersionedHashes %v, got %v", blobHashes, txMap["blobVersionedHashes"])
+		}
+	})
+
 	t.Run("ReceiptToMap", func(t *testing.T) {
 		receipt := &types.Receipt{
 			TxHash:            common.HexToHash("0xabc"),
@@ -119,15 +119,10 @@ func (s *Schema) transactionToMap(tx *types.Transaction, location *storage.TxLoc
 		sStr = "0x0"
 	}
 
-	// Handle potentially nil ChainId for sender derivation
-	var from common.Address
-	chainId := tx.ChainId()
-	if chainId != nil {
-		var err error
-		from, err = types.Sender(types.LatestSignerForChainID(chainId), tx)
-		if err != nil {
-			s.logger.Warn("failed to get transaction sender", zap.Error(err))
-		}
+	// Use the sender cached at index time, recovering it for older records
+	from, err := location.Sender(tx)
+	if err != nil {
+		s.logger.Warn("failed to get transaction sender", zap.Error(err))
 	}
 
 	// Handle potentially nil Value
@@ -159,6 +154,8 @@ func (s *Schema) transactionToMap(tx *types.Transaction, location *storage.TxLoc
 		"s":                    sStr,
 		"chainId":              nil,
 		"accessList":           nil,
+		"maxFeePerBlobGas":     nil,
+		"blobVersionedHashes":  nil,
 		"receipt":              nil,
 		"blockTimestamp":       nil,
 		// Fee Delegation fields (type 0x16 = 22)
@@ -213,6 +210,16 @@ func (s *Schema) transactionToMap(tx *types.Transaction, location *storage.TxLoc
 		result["accessList"] = accessListMap
 	}
 
+	// EIP-4844 blob transaction (type 0x03 = 3)
+	if tx.Type() == types.BlobTxType {
+		result["maxFeePerBlobGas"] = tx.BlobGasFeeCap().String()
+		blobHashes := make([]interface{}, len(tx.BlobHashes()))
+		for i, hash := range tx.BlobHashes() {
+			blobHashes[i] = hash.Hex()
+		}
+		result["blobVersionedHashes"] = blobHashes
+	}
+
 	// EIP-7702 SetCode transaction (type 0x04 = 4)
 	if tx.Type() == types.SetCodeTxType {
 		authList := tx.SetCodeAuthorizations()
@@ -277,11 +284,13 @@ func (s *Schema) receiptToMap(receipt *types.Receipt) map[string]interface{} {
 		blockNumber = "0"
 	}
 
-	var effectiveGasPrice string
+	var effectiveGasPrice, fee string
 	if receipt.EffectiveGasPrice != nil {
 		effectiveGasPrice = receipt.EffectiveGasPrice.String()
+		fee = storage.TransactionFee(receipt.EffectiveGasPrice, receipt.GasUsed).String()
 	} else {
 		effectiveGasPrice = "0"
+		fee = "0"
 	}
 
 	result := map[string]interface{}{
@@ -293,6 +302,7 @@ func (s *Schema) receiptToMap(receipt *types.Receipt) map[string]interface{} {
 		"gasUsed":           fmt.Sprintf("%d", receipt.GasUsed),
 		"cumulativeGasUsed": fmt.Sprintf("%d", receipt.CumulativeGasUsed),
 		"effectiveGasPrice": effectiveGasPrice,
+		"fee":               fee,
 		"status":            int(receipt.Status),
 		"logs":              logs,
 		"logsBloom":         fmt.Sprintf("0x%x", receipt.Bloom[:]),
@@ -305,6 +315,25 @@ func (s *Schema) receiptToMap(receipt *types.Receipt) map[string]interface{} {
 	return result
 }
 
+// receiptWithRevertReason converts a receipt to a map including the indexed
+// revert reason of a failed transaction, if any
+func (s *Schema) receiptWithRevertReason(ctx context.Context, receipt *types.Receipt) map[string]interface{} {
+	result := s.receiptToMap(receipt)
+	reason, err := storage.ReceiptRevertReason(ctx, s.storage, receipt)
+	if err != nil {
+		s.logger.Warn("failed to get revert reason", zap.String("hash", receipt.TxHash.Hex()), zap.Error(err))
+	}
+	if reason == nil {
+		return result
+	}
+
+	result["revertReason"] = reason.Message()
+	if len(reason.Data) > 0 {
+		result["revertData"] = fmt.Sprintf("0x%x", reason.Data)
+	}
+	return result
+}
+
 // logToMap converts a log to a GraphQL-friendly map
 // Always attempts to decode using known event signatures
 func (s *Schema) logToMap(log *types.Log) map[string]interface{} {
@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/0xmhha/indexer-go/internal/constants"
+	"github.com/0xmhha/indexer-go/pkg/storage"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/graphql-go/graphql"
@@ -22,6 +24,121 @@ func extractContext(ctx interface{}) context.Context {
 	return context.Background()
 }
 
+// ============================================================================
+// Block Tag Helpers
+// ============================================================================
+
+// blockTag is a parsed BlockTag: an absolute block number, or an offset below
+// the latest indexed height when relative is set
+type blockTag struct {
+	number   uint64
+	offset   uint64
+	relative bool
+}
+
+// parseBlockTag parses a decimal block number, "earliest", "latest", or an
+// offset below the latest indexed height written as "latest-N" or "-N"
+func parseBlockTag(tag string) (blockTag, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+
+	var offsetStr string
+	switch {
+	case tag == "earliest":
+		return blockTag{number: 0}, nil
+	case tag == "latest":
+		return blockTag{relative: true}, nil
+	case strings.HasPrefix(tag, "latest-"):
+		offsetStr = strings.TrimPrefix(tag, "latest-")
+	case strings.HasPrefix(tag, "-"):
+		offsetStr = strings.TrimPrefix(tag, "-")
+	default:
+		number, err := strconv.ParseUint(tag, 10, 64)
+		if err != nil {
+			return blockTag{}, fmt.Errorf("invalid block tag %q: must be a block number, earliest, latest or latest-N", tag)
+		}
+		return blockTag{number: number}, nil
+	}
+
+	offset, err := strconv.ParseUint(offsetStr, 10, 64)
+	if err != nil {
+		return blockTag{}, fmt.Errorf("invalid block tag offset %q: %w", tag, err)
+	}
+	return blockTag{offset: offset, relative: true}, nil
+}
+
+// validBlockTag returns tag when it parses as a block tag and nil otherwise,
+// for the BlockTag scalar to reject malformed input during validation
+func validBlockTag(tag string) interface{} {
+	if _, err := parseBlockTag(tag); err != nil {
+		return nil
+	}
+	return tag
+}
+
+// resolveBlockTag resolves a BlockTag argument to a block height, reading the
+// latest indexed height for relative tags
+func (s *Schema) resolveBlockTag(ctx context.Context, tag string) (uint64, error) {
+	parsed, err := parseBlockTag(tag)
+	if err != nil {
+		return 0, err
+	}
+	if !parsed.relative {
+		return parsed.number, nil
+	}
+
+	latest, err := s.storage.GetLatestHeight(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get latest height: %w", err)
+	}
+	if parsed.offset > latest {
+		return 0, fmt.Errorf("block tag %q is before genesis (latest height %d)", tag, latest)
+	}
+	return latest - parsed.offset, nil
+}
+
+// resolveOptionalBlockTag resolves the BlockTag in args[name] when it is set
+func (s *Schema) resolveOptionalBlockTag(ctx context.Context, args map[string]interface{}, name string) (uint64, bool, error) {
+	tag, ok := args[name].(string)
+	if !ok {
+		return 0, false, nil
+	}
+	height, err := s.resolveBlockTag(ctx, tag)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return height, true, nil
+}
+
+// getBlockHeader returns the header at height for resolvers that only need
+// header fields, skipping the transaction decode when storage supports it
+func (s *Schema) getBlockHeader(ctx context.Context, height uint64) (*types.Header, error) {
+	if reader, ok := s.storage.(storage.BlockHeaderReader); ok {
+		return reader.GetBlockHeader(ctx, height)
+	}
+	block, err := s.storage.GetBlock(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	return block.Header(), nil
+}
+
+// getLatestBlocks returns up to n of the most recent blocks, newest first
+func (s *Schema) getLatestBlocks(ctx context.Context, n int) ([]*types.Block, error) {
+	if reader, ok := s.storage.(storage.LatestBlocksReader); ok {
+		return reader.GetLatestBlocks(ctx, n)
+	}
+	return storage.LatestBlocks(ctx, s.storage, n)
+}
+
+// getBlocksWithMissing returns the blocks in a height range and the heights
+// that have no block stored
+func (s *Schema) getBlocksWithMissing(ctx context.Context, startHeight, endHeight uint64) ([]*types.Block, []uint64, error) {
+	if reader, ok := s.storage.(storage.BlockRangeReader); ok {
+		return reader.GetBlocksWithMissing(ctx, startHeight, endHeight)
+	}
+	return storage.BlocksWithMissing(ctx, s.storage, startHeight, endHeight)
+}
+
 // ============================================================================
 // Pagination Helpers
 // ============================================================================
@@ -211,7 +328,7 @@ type LogFilter struct {
 }
 
 // parseLogFilter extracts log filter parameters from GraphQL args
-func parseLogFilter(p graphql.ResolveParams) (LogFilter, error) {
+func (s *Schema) parseLogFilter(p graphql.ResolveParams) (LogFilter, error) {
 	filter := LogFilter{}
 
 	f, ok := p.Args["filter"].(map[string]interface{})
@@ -233,16 +350,13 @@ func parseLogFilter(p graphql.ResolveParams) (LogFilter, error) {
 		}
 	}
 
-	if bnf, ok := f["blockNumberFrom"].(string); ok {
-		if bn, err := strconv.ParseUint(bnf, 10, 64); err == nil {
-			filter.BlockNumberFrom = bn
-		}
+	ctx := extractContext(p.Context)
+	var err error
+	if filter.BlockNumberFrom, _, err = s.resolveOptionalBlockTag(ctx, f, "blockNumberFrom"); err != nil {
+		return filter, err
 	}
-
-	if bnt, ok := f["blockNumberTo"].(string); ok {
-		if bn, err := strconv.ParseUint(bnt, 10, 64); err == nil {
-			filter.BlockNumberTo = bn
-		}
+	if filter.BlockNumberTo, _, err = s.resolveOptionalBlockTag(ctx, f, "blockNumberTo"); err != nil {
+		return filter, err
 	}
 
 	return filter, nil
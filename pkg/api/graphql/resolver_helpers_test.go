@@ -1,7 +1,15 @@
 package graphql
 
 import (
+	"context"
+	"math/big"
 	"testing"
+
+	"github.com/0xmhha/indexer-go/pkg/storage"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/graphql-go/graphql"
+	"go.uber.org/zap"
 )
 
 func TestCalculateBlockRangeReverse(t *testing.T) {
@@ -165,3 +173,205 @@ func TestCalculateBlockRangeForward(t *testing.T) {
 		})
 	}
 }
+
+// newBlockTagTestStore returns a mock storage with blocks 0..10 indexed
+func newBlockTagTestStore() *mockStorage {
+	store := &mockStorage{
+		latestHeight: 10,
+		blocks:       make(map[uint64]*types.Block),
+		blocksByHash: make(map[common.Hash]*types.Block),
+		transactions: make(map[common.Hash]*types.Transaction),
+		receipts:     make(map[common.Hash]*types.Receipt),
+	}
+	for i := uint64(0); i <= store.latestHeight; i++ {
+		block := types.NewBlockWithHeader(&types.Header{
+			Number:   new(big.Int).SetUint64(i),
+			GasLimit: 8000000,
+		})
+		store.blocks[i] = block
+		store.blocksByHash[block.Hash()] = block
+	}
+	return store
+}
+
+func TestResolveBlockTag(t *testing.T) {
+	schema, err := NewSchema(newBlockTagTestStore(), zap.NewNop())
+	if err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+
+	tests := []struct {
+		tag     string
+		want    uint64
+		wantErr bool
+	}{
+		{tag: "latest", want: 10},
+		{tag: "LATEST", want: 10},
+		{tag: "earliest", want: 0},
+		{tag: "7", want: 7},
+		{tag: "-2", want: 8},
+		{tag: "latest-3", want: 7},
+		{tag: "latest-10", want: 0},
+		{tag: "latest-11", wantErr: true},
+		{tag: "-abc", wantErr: true},
+		{tag: "pending", wantErr: true},
+		{tag: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			got, err := schema.resolveBlockTag(context.Background(), tt.tag)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("resolveBlockTag(%q) = %d, want error", tt.tag, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveBlockTag(%q) error = %v", tt.tag, err)
+			}
+			if got != tt.want {
+				t.Errorf("resolveBlockTag(%q) = %d, want %d", tt.tag, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlockQuery_BlockTag(t *testing.T) {
+	handler, err := NewHandler(newBlockTagTestStore(), zap.NewNop())
+	if err != nil {
+		t.Fatalf("failed to create handler: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		tag  string
+		want string
+	}{
+		{name: "latest", tag: "latest", want: "10"},
+		{name: "negative offset", tag: "-2", want: "8"},
+		{name: "latest offset", tag: "latest-2", want: "8"},
+		{name: "explicit number", tag: "5", want: "5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := handler.ExecuteQuery(`query($n: BlockTag!) { block(number: $n) { number } }`, map[string]interface{}{"n": tt.tag})
+			if len(result.Errors) > 0 {
+				t.Fatalf("expected no errors, got %v", result.Errors)
+			}
+			data := result.Data.(map[string]interface{})
+			block, ok := data["block"].(map[string]interface{})
+			if !ok {
+				t.Fatalf("expected block in result, got %v", data["block"])
+			}
+			if block["number"] != tt.want {
+				t.Errorf("block number = %v, want %s", block["number"], tt.want)
+			}
+		})
+	}
+
+	t.Run("offset before genesis", func(t *testing.T) {
+		result := handler.ExecuteQuery(`{ block(number: "latest-20") { number } }`, nil)
+		if len(result.Errors) == 0 {
+			t.Error("expected error for offset below genesis")
+		}
+	})
+
+	t.Run("malformed tag rejected in validation", func(t *testing.T) {
+		result := handler.ExecuteQuery(`{ block(number: "pending") { number } }`, nil)
+		if len(result.Errors) == 0 {
+			t.Fatal("expected validation error for malformed tag")
+		}
+		if result.Data != nil {
+			t.Errorf("expected no data for a query failing validation, got %v", result.Data)
+		}
+	})
+
+	t.Run("integer literal", func(t *testing.T) {
+		result := handler.ExecuteQuery(`{ block(number: 4) { number } }`, nil)
+		if len(result.Errors) > 0 {
+			t.Fatalf("expected no errors, got %v", result.Errors)
+		}
+		block := result.Data.(map[string]interface{})["block"].(map[string]interface{})
+		if block["number"] != "4" {
+			t.Errorf("block number = %v, want 4", block["number"])
+		}
+	})
+
+	t.Run("blocksRange", func(t *testing.T) {
+		result := handler.ExecuteQuery(`{ blocksRange(startNumber: "latest-2", endNumber: "latest") { startNumber endNumber count } }`, nil)
+		if len(result.Errors) > 0 {
+			t.Fatalf("expected no errors, got %v", result.Errors)
+		}
+		br := result.Data.(map[string]interface{})["blocksRange"].(map[string]interface{})
+		if br["startNumber"] != "8" || br["endNumber"] != "10" || br["count"] != 3 {
+			t.Errorf("blocksRange = %v, want 8..10 with 3 blocks", br)
+		}
+	})
+}
+
+func TestAddressBalance_EarliestTag(t *testing.T) {
+	store := &mockHistoricalStorage{
+		mockStorage: newBlockTagTestStore(),
+		balance:     big.NewInt(500),
+		balanceHistory: []storage.BalanceSnapshot{
+			{BlockNumber: 0, Balance: big.NewInt(100), Delta: big.NewInt(100)},
+		},
+	}
+	handler, err := NewHandler(store, zap.NewNop())
+	if err != nil {
+		t.Fatalf("failed to create handler: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{name: "earliest", query: `{ addressBalance(address: "0x456", blockNumber: "earliest") }`, want: "100"},
+		{name: "block zero", query: `{ addressBalance(address: "0x456", blockNumber: "0") }`, want: "100"},
+		{name: "omitted", query: `{ addressBalance(address: "0x456") }`, want: "500"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := handler.ExecuteQuery(tt.query, nil)
+			if len(result.Errors) > 0 {
+				t.Fatalf("expected no errors, got %v", result.Errors)
+			}
+			got := result.Data.(map[string]interface{})["addressBalance"]
+			if got != tt.want {
+				t.Errorf("addressBalance = %v, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogFilter_BlockTag(t *testing.T) {
+	schema, err := NewSchema(newBlockTagTestStore(), zap.NewNop())
+	if err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+
+	p := graphql.ResolveParams{
+		Context: context.Background(),
+		Args: map[string]interface{}{
+			"filter": map[string]interface{}{
+				"blockNumberFrom": "latest-3",
+				"blockNumberTo":   "latest",
+			},
+		},
+	}
+	filter, err := schema.parseLogFilter(p)
+	if err != nil {
+		t.Fatalf("parseLogFilter() error = %v", err)
+	}
+	if filter.BlockNumberFrom != 7 || filter.BlockNumberTo != 10 {
+		t.Errorf("block range = %d..%d, want 7..10", filter.BlockNumberFrom, filter.BlockNumberTo)
+	}
+
+	p.Args["filter"] = map[string]interface{}{"blockNumberFrom": "latest-11"}
+	if _, err := schema.parseLogFilter(p); err == nil {
+		t.Error("expected error for offset below genesis")
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
-	"strconv"
 
 	"github.com/0xmhha/indexer-go/internal/constants"
 	"github.com/0xmhha/indexer-go/pkg/storage"
@@ -39,9 +38,9 @@ func (s *Schema) resolveBlock(p graphql.ResolveParams) (interface{}, error) {
 		return nil, fmt.Errorf("invalid block number")
 	}
 
-	number, err := strconv.ParseUint(numberStr, 10, 64)
+	number, err := s.resolveBlockTag(ctx, numberStr)
 	if err != nil {
-		return nil, fmt.Errorf("invalid block number format: %w", err)
+		return nil, err
 	}
 
 	block, err := s.storage.GetBlock(ctx, number)
@@ -193,10 +192,36 @@ func (s *Schema) buildBlockConnectionResponse(blocks []*types.Block, nodes []int
 	})
 }
 
+// resolveLatestBlocks resolves the most recent blocks, newest first
+func (s *Schema) resolveLatestBlocks(p graphql.ResolveParams) (interface{}, error) {
+	ctx := extractContext(p.Context)
+
+	limit := constants.DefaultPaginationLimit
+	if l, ok := p.Args["limit"].(int); ok {
+		if l <= 0 {
+			return nil, fmt.Errorf("limit must be positive")
+		}
+		limit = l
+		if limit > constants.DefaultMaxPaginationLimit {
+			limit = constants.DefaultMaxPaginationLimit
+		}
+	}
+
+	blocks, err := s.getLatestBlocks(ctx, limit)
+	if err != nil {
+		s.logger.Error("failed to get latest blocks",
+			zap.Int("limit", limit),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to get latest blocks: %w", err)
+	}
+
+	return s.blocksToNodes(blocks), nil
+}
+
 // resolveBlocksRange resolves blocks in a specific range (optimized for frontend catch-up)
 // Returns blocks from startNumber to endNumber (inclusive) with a maximum of 100 blocks
 func (s *Schema) resolveBlocksRange(p graphql.ResolveParams) (interface{}, error) {
-	ctx := p.Context
+	ctx := extractContext(p.Context)
 
 	// Parse start and end block numbers
 	startNumberStr, ok := p.Args["startNumber"].(string)
@@ -208,13 +233,13 @@ func (s *Schema) resolveBlocksRange(p graphql.ResolveParams) (interface{}, error
 		return nil, fmt.Errorf("invalid endNumber")
 	}
 
-	startNumber, err := strconv.ParseUint(startNumberStr, 10, 64)
+	startNumber, err := s.resolveBlockTag(ctx, startNumberStr)
 	if err != nil {
-		return nil, fmt.Errorf("invalid startNumber format: %w", err)
+		return nil, fmt.Errorf("invalid startNumber: %w", err)
 	}
-	endNumber, err := strconv.ParseUint(endNumberStr, 10, 64)
+	endNumber, err := s.resolveBlockTag(ctx, endNumberStr)
 	if err != nil {
-		return nil, fmt.Errorf("invalid endNumber format: %w", err)
+		return nil, fmt.Errorf("invalid endNumber: %w", err)
 	}
 
 	// Validate range
@@ -246,12 +271,13 @@ func (s *Schema) resolveBlocksRange(p graphql.ResolveParams) (interface{}, error
 	// Check if there are no blocks to return
 	if startNumber > latestHeight {
 		return map[string]interface{}{
-			"blocks":       []interface{}{},
-			"startNumber":  fmt.Sprintf("%d", startNumber),
-			"endNumber":    fmt.Sprintf("%d", startNumber),
-			"count":        0,
-			"hasMore":      false,
-			"latestHeight": fmt.Sprintf("%d", latestHeight),
+			"blocks":        []interface{}{},
+			"startNumber":   fmt.Sprintf("%d", startNumber),
+			"endNumber":     fmt.Sprintf("%d", startNumber),
+			"count":         0,
+			"hasMore":       false,
+			"latestHeight":  fmt.Sprintf("%d", latestHeight),
+			"missingBlocks": []string{},
 		}, nil
 	}
 
@@ -265,17 +291,25 @@ func (s *Schema) resolveBlocksRange(p graphql.ResolveParams) (interface{}, error
 		includeReceipts = ir
 	}
 
-	// Fetch blocks in range
-	blocks := make([]interface{}, 0, endNumber-startNumber+1)
-	for blockNum := startNumber; blockNum <= endNumber; blockNum++ {
-		block, err := s.storage.GetBlock(ctx, blockNum)
-		if err != nil {
-			s.logger.Warn("failed to get block in range",
-				zap.Uint64("blockNumber", blockNum),
-				zap.Error(err))
-			continue // Skip missing blocks
-		}
+	// Fetch blocks in range, reporting the heights that have no block
+	rangeBlocks, missing, err := s.getBlocksWithMissing(ctx, startNumber, endNumber)
+	if err != nil {
+		s.logger.Error("failed to get blocks in range",
+			zap.Uint64("startNumber", startNumber),
+			zap.Uint64("endNumber", endNumber),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to get blocks: %w", err)
+	}
+	if len(missing) > 0 {
+		s.logger.Warn("blocksRange has missing blocks",
+			zap.Uint64("startNumber", startNumber),
+			zap.Uint64("endNumber", endNumber),
+			zap.Int("missing", len(missing)))
+	}
 
+	blocks := make([]interface{}, 0, len(rangeBlocks))
+	for _, block := range rangeBlocks {
+		blockNum := block.NumberU64()
 		blockMap := s.blockToMap(block)
 
 		// Optionally exclude transactions for lighter response
@@ -308,13 +342,19 @@ func (s *Schema) resolveBlocksRange(p graphql.ResolveParams) (interface{}, error
 	// Determine if there are more blocks available
 	hasMore := endNumber < latestHeight
 
+	missingBlocks := make([]string, len(missing))
+	for i, height := range missing {
+		missingBlocks[i] = fmt.Sprintf("%d", height)
+	}
+
 	return map[string]interface{}{
-		"blocks":       blocks,
-		"startNumber":  fmt.Sprintf("%d", startNumber),
-		"endNumber":    fmt.Sprintf("%d", endNumber),
-		"count":        len(blocks),
-		"hasMore":      hasMore,
-		"latestHeight": fmt.Sprintf("%d", latestHeight),
+		"blocks":        blocks,
+		"startNumber":   fmt.Sprintf("%d", startNumber),
+		"endNumber":     fmt.Sprintf("%d", endNumber),
+		"count":         len(blocks),
+		"hasMore":       hasMore,
+		"latestHeight":  fmt.Sprintf("%d", latestHeight),
+		"missingBlocks": missingBlocks,
 	}, nil
 }
 
@@ -349,38 +389,13 @@ func (s *Schema) resolveTransaction(p graphql.ResolveParams) (interface{}, error
 				receipt.BlockNumber = big.NewInt(int64(location.BlockHeight))
 				receipt.BlockHash = location.BlockHash
 				receipt.TransactionIndex = uint(location.TxIndex)
-
-				// Calculate GasUsed
-				if location.TxIndex == 0 {
-					receipt.GasUsed = receipt.CumulativeGasUsed
-				} else {
-					txs := block.Transactions()
-					if int(location.TxIndex) > 0 && int(location.TxIndex) <= len(txs) {
-						prevTxHash := txs[location.TxIndex-1].Hash()
-						prevReceipt, prevErr := s.storage.GetReceipt(ctx, prevTxHash)
-						if prevErr == nil && prevReceipt != nil {
-							receipt.GasUsed = receipt.CumulativeGasUsed - prevReceipt.CumulativeGasUsed
-						}
-					}
-				}
-
-				// Calculate effective gas price
-				baseFee := block.BaseFee()
-				if baseFee != nil && tx.Type() == types.DynamicFeeTxType {
-					tipCap := tx.GasTipCap()
-					feeCap := tx.GasFeeCap()
-					if tipCap != nil && feeCap != nil {
-						effectiveGasPrice := new(big.Int).Add(baseFee, tipCap)
-						if effectiveGasPrice.Cmp(feeCap) > 0 {
-							effectiveGasPrice = feeCap
-						}
-						receipt.EffectiveGasPrice = effectiveGasPrice
-					}
-				} else if tx.GasPrice() != nil {
-					receipt.EffectiveGasPrice = tx.GasPrice()
-				}
 			}
 		}
+		if _, err := storage.FillReceiptFee(ctx, s.storage, receipt); err != nil {
+			s.logger.Warn("failed to compute transaction fee",
+				zap.String("hash", hash.Hex()),
+				zap.Error(err))
+		}
 		result["receipt"] = s.receiptToMap(receipt)
 	}
 
@@ -592,6 +607,15 @@ func (s *Schema) resolveTransactionsByAddress(p graphql.ResolveParams) (interfac
 		}
 	}
 
+	// Filter arguments are served by the historical filtered scan
+	filter, err := parseAddressTransactionFilter(p.Args)
+	if err != nil {
+		return nil, err
+	}
+	if filter != nil {
+		return s.resolveFilteredAddressTransactions(ctx, address, filter, limit, offset)
+	}
+
 	// Fetch transaction hashes from storage
 	txHashes, err := s.storage.GetTransactionsByAddress(ctx, address, limit+1, offset)
 	if err != nil {
@@ -634,9 +658,9 @@ func (s *Schema) resolveTransactionsByAddress(p graphql.ResolveParams) (interfac
 			if ts, ok := blockTimestamps[location.BlockHeight]; ok {
 				txMap["blockTimestamp"] = ts
 			} else {
-				block, blockErr := s.storage.GetBlock(ctx, location.BlockHeight)
-				if blockErr == nil && block != nil {
-					ts = fmt.Sprintf("%d", block.Header().Time)
+				header, blockErr := s.getBlockHeader(ctx, location.BlockHeight)
+				if blockErr == nil && header != nil {
+					ts = fmt.Sprintf("%d", header.Time)
 					blockTimestamps[location.BlockHeight] = ts
 					txMap["blockTimestamp"] = ts
 				}
@@ -645,8 +669,19 @@ func (s *Schema) resolveTransactionsByAddress(p graphql.ResolveParams) (interfac
 		nodes = append(nodes, txMap)
 	}
 
-	// Calculate pagination info
+	// Calculate pagination info. totalCount covers all pages when the
+	// storage can count the address's transactions.
 	totalCount := len(nodes)
+	if counter, ok := s.storage.(storage.AddressTransactionCounter); ok {
+		count, err := counter.CountTransactionsByAddress(ctx, address)
+		if err == nil {
+			totalCount = int(count)
+		} else {
+			s.logger.Warn("failed to count transactions by address",
+				zap.String("address", addressStr),
+				zap.Error(err))
+		}
+	}
 	hasNextPage := hasMore
 	hasPreviousPage := offset > 0
 
@@ -694,65 +729,28 @@ func (s *Schema) resolveReceipt(p graphql.ResolveParams) (interface{}, error) {
 	}
 
 	// Get transaction to find block info for deriving missing receipt fields
-	tx, location, err := s.storage.GetTransaction(ctx, hash)
+	_, location, err := s.storage.GetTransaction(ctx, hash)
 	if err != nil {
 		s.logger.Warn("failed to get transaction for receipt context",
 			zap.String("hash", hashStr),
 			zap.Error(err))
 		// Still return basic receipt data
-		return s.receiptToMap(receipt), nil
+		return s.receiptWithRevertReason(ctx, receipt), nil
 	}
 
-	// Get block to derive additional fields
-	var baseFee *big.Int
+	// Set receipt block info from location
 	if location != nil {
-		block, err := s.storage.GetBlock(ctx, location.BlockHeight)
-		if err == nil && block != nil {
-			baseFee = block.BaseFee()
-			// Set receipt block info from location
-			receipt.BlockNumber = big.NewInt(int64(location.BlockHeight))
-			receipt.BlockHash = location.BlockHash
-			receipt.TransactionIndex = uint(location.TxIndex)
-
-			// Calculate GasUsed from CumulativeGasUsed
-			// GasUsed = current.CumulativeGasUsed - previous.CumulativeGasUsed
-			if location.TxIndex == 0 {
-				// First transaction in block: gasUsed = cumulativeGasUsed
-				receipt.GasUsed = receipt.CumulativeGasUsed
-			} else {
-				// Get previous transaction's receipt to calculate gas used
-				txs := block.Transactions()
-				if int(location.TxIndex) > 0 && int(location.TxIndex) <= len(txs) {
-					prevTxHash := txs[location.TxIndex-1].Hash()
-					prevReceipt, err := s.storage.GetReceipt(ctx, prevTxHash)
-					if err == nil && prevReceipt != nil {
-						receipt.GasUsed = receipt.CumulativeGasUsed - prevReceipt.CumulativeGasUsed
-					}
-				}
-			}
-		}
+		receipt.BlockNumber = big.NewInt(int64(location.BlockHeight))
+		receipt.BlockHash = location.BlockHash
+		receipt.TransactionIndex = uint(location.TxIndex)
 	}
-
-	// Calculate effective gas price
-	if tx != nil {
-		if baseFee != nil && tx.Type() == types.DynamicFeeTxType {
-			// EIP-1559: effectiveGasPrice = min(baseFee + tipCap, feeCap)
-			tipCap := tx.GasTipCap()
-			feeCap := tx.GasFeeCap()
-			if tipCap != nil && feeCap != nil {
-				effectiveGasPrice := new(big.Int).Add(baseFee, tipCap)
-				if effectiveGasPrice.Cmp(feeCap) > 0 {
-					effectiveGasPrice = feeCap
-				}
-				receipt.EffectiveGasPrice = effectiveGasPrice
-			}
-		} else if tx.GasPrice() != nil {
-			// Legacy/AccessList tx: effectiveGasPrice = gasPrice
-			receipt.EffectiveGasPrice = tx.GasPrice()
-		}
+	if _, err := storage.FillReceiptFee(ctx, s.storage, receipt); err != nil {
+		s.logger.Warn("failed to compute transaction fee",
+			zap.String("hash", hashStr),
+			zap.Error(err))
 	}
 
-	return s.receiptToMap(receipt), nil
+	return s.receiptWithRevertReason(ctx, receipt), nil
 }
 
 // resolveReceiptsByBlock resolves receipts by block number
@@ -763,9 +761,9 @@ func (s *Schema) resolveReceiptsByBlock(p graphql.ResolveParams) (interface{}, e
 		return nil, fmt.Errorf("invalid block number")
 	}
 
-	number, err := strconv.ParseUint(numberStr, 10, 64)
+	number, err := s.resolveBlockTag(ctx, numberStr)
 	if err != nil {
-		return nil, fmt.Errorf("invalid block number format: %w", err)
+		return nil, err
 	}
 
 	// Get block for deriving receipt fields
@@ -785,39 +783,15 @@ func (s *Schema) resolveReceiptsByBlock(p graphql.ResolveParams) (interface{}, e
 		return nil, err
 	}
 
-	// Derive missing fields for each receipt
-	baseFee := block.BaseFee()
-	txs := block.Transactions()
-
 	result := make([]interface{}, len(receipts))
 	for i, receipt := range receipts {
 		// Set block info
 		receipt.BlockNumber = big.NewInt(int64(number))
 		receipt.BlockHash = block.Hash()
-
-		// Calculate GasUsed
-		if i == 0 {
-			receipt.GasUsed = receipt.CumulativeGasUsed
-		} else if i > 0 && receipts[i-1] != nil {
-			receipt.GasUsed = receipt.CumulativeGasUsed - receipts[i-1].CumulativeGasUsed
-		}
-
-		// Calculate effective gas price from transaction
-		if i < len(txs) {
-			tx := txs[i]
-			if baseFee != nil && tx.Type() == types.DynamicFeeTxType {
-				tipCap := tx.GasTipCap()
-				feeCap := tx.GasFeeCap()
-				if tipCap != nil && feeCap != nil {
-					effectiveGasPrice := new(big.Int).Add(baseFee, tipCap)
-					if effectiveGasPrice.Cmp(feeCap) > 0 {
-						effectiveGasPrice = feeCap
-					}
-					receipt.EffectiveGasPrice = effectiveGasPrice
-				}
-			} else if tx.GasPrice() != nil {
-				receipt.EffectiveGasPrice = tx.GasPrice()
-			}
+		if _, err := storage.FillReceiptFee(ctx, s.storage, receipt); err != nil {
+			s.logger.Warn("failed to compute transaction fee",
+				zap.String("hash", receipt.TxHash.Hex()),
+				zap.Error(err))
 		}
 
 		result[i] = s.receiptToMap(receipt)
@@ -832,7 +806,7 @@ func (s *Schema) resolveLogs(p graphql.ResolveParams) (interface{}, error) {
 	decode := s.getDecodeParam(p)
 	pagination := parsePaginationParams(p, 100)
 
-	filter, err := parseLogFilter(p)
+	filter, err := s.parseLogFilter(p)
 	if err != nil {
 		return nil, err
 	}
@@ -972,15 +946,9 @@ func (s *Schema) resolveActiveMinters(p graphql.ResolveParams) (interface{}, err
 
 	var result []map[string]interface{}
 	for _, minter := range minters {
-		allowance, err := reader.GetMinterAllowance(ctx, minter)
-		if err != nil {
-			s.logger.Warn("failed to get minter allowance", zap.String("minter", minter.Hex()), zap.Error(err))
-			continue
-		}
-
 		result = append(result, map[string]interface{}{
-			"address":   minter.Hex(),
-			"allowance": allowance.String(),
+			"address":   minter.Address.Hex(),
+			"allowance": minter.Allowance.String(),
 			"isActive":  true,
 		})
 	}
@@ -1006,7 +974,7 @@ func (s *Schema) resolveActiveMinterAddresses(p graphql.ResolveParams) (interfac
 	// Convert to hex string addresses
 	var result []string
 	for _, minter := range minters {
-		result = append(result, minter.Hex())
+		result = append(result, minter.Address.Hex())
 	}
 
 	return result, nil
@@ -1378,14 +1346,13 @@ func (s *Schema) resolveMintEvents(p graphql.ResolveParams) (interface{}, error)
 	}
 
 	// Parse block range
-	var fromBlock, toBlock uint64
-	if fb, ok := filter["fromBlock"].(string); ok {
-		parsed, _ := strconv.ParseUint(fb, 10, 64)
-		fromBlock = parsed
+	fromBlock, _, err := s.resolveOptionalBlockTag(ctx, filter, "fromBlock")
+	if err != nil {
+		return nil, err
 	}
-	if tb, ok := filter["toBlock"].(string); ok {
-		parsed, _ := strconv.ParseUint(tb, 10, 64)
-		toBlock = parsed
+	toBlock, _, err := s.resolveOptionalBlockTag(ctx, filter, "toBlock")
+	if err != nil {
+		return nil, err
 	}
 
 	// Parse optional minter address (support both 'minter' and 'address' fields)
@@ -1451,14 +1418,13 @@ func (s *Schema) resolveBurnEvents(p graphql.ResolveParams) (interface{}, error)
 	}
 
 	// Parse block range
-	var fromBlock, toBlock uint64
-	if fb, ok := filter["fromBlock"].(string); ok {
-		parsed, _ := strconv.ParseUint(fb, 10, 64)
-		fromBlock = parsed
+	fromBlock, _, err := s.resolveOptionalBlockTag(ctx, filter, "fromBlock")
+	if err != nil {
+		return nil, err
 	}
-	if tb, ok := filter["toBlock"].(string); ok {
-		parsed, _ := strconv.ParseUint(tb, 10, 64)
-		toBlock = parsed
+	toBlock, _, err := s.resolveOptionalBlockTag(ctx, filter, "toBlock")
+	if err != nil {
+		return nil, err
 	}
 
 	// Parse optional burner address (support both 'burner' and 'address' fields)
@@ -1582,14 +1548,13 @@ func (s *Schema) resolveGasTipHistory(p graphql.ResolveParams) (interface{}, err
 	}
 
 	// Parse block range
-	var fromBlock, toBlock uint64
-	if fb, ok := filter["fromBlock"].(string); ok {
-		parsed, _ := strconv.ParseUint(fb, 10, 64)
-		fromBlock = parsed
+	fromBlock, _, err := s.resolveOptionalBlockTag(ctx, filter, "fromBlock")
+	if err != nil {
+		return nil, err
 	}
-	if tb, ok := filter["toBlock"].(string); ok {
-		parsed, _ := strconv.ParseUint(tb, 10, 64)
-		toBlock = parsed
+	toBlock, _, err := s.resolveOptionalBlockTag(ctx, filter, "toBlock")
+	if err != nil {
+		return nil, err
 	}
 
 	reader, ok := s.storage.(storage.SystemContractReader)
@@ -1708,14 +1673,13 @@ func (s *Schema) resolveDepositMintProposals(p graphql.ResolveParams) (interface
 	}
 
 	// Parse block range
-	var fromBlock, toBlock uint64
-	if fb, ok := filter["fromBlock"].(string); ok {
-		parsed, _ := strconv.ParseUint(fb, 10, 64)
-		fromBlock = parsed
+	fromBlock, _, err := s.resolveOptionalBlockTag(ctx, filter, "fromBlock")
+	if err != nil {
+		return nil, err
 	}
-	if tb, ok := filter["toBlock"].(string); ok {
-		parsed, _ := strconv.ParseUint(tb, 10, 64)
-		toBlock = parsed
+	toBlock, _, err := s.resolveOptionalBlockTag(ctx, filter, "toBlock")
+	if err != nil {
+		return nil, err
 	}
 
 	// Parse optional status filter
@@ -1755,14 +1719,13 @@ func (s *Schema) resolveMinterConfigHistory(p graphql.ResolveParams) (interface{
 	}
 
 	// Parse block range
-	var fromBlock, toBlock uint64
-	if fb, ok := filter["fromBlock"].(string); ok {
-		parsed, _ := strconv.ParseUint(fb, 10, 64)
-		fromBlock = parsed
-	}
-	if tb, ok := filter["toBlock"].(string); ok {
-		parsed, _ := strconv.ParseUint(tb, 10, 64)
-		toBlock = parsed
+	fromBlock, _, err := s.resolveOptionalBlockTag(ctx, filter, "fromBlock")
+	if err != nil {
+		return nil, err
+	}
+	toBlock, _, err := s.resolveOptionalBlockTag(ctx, filter, "toBlock")
+	if err != nil {
+		return nil, err
 	}
 
 	reader, ok := s.storage.(storage.SystemContractReader)
@@ -167,15 +167,15 @@ func (s *Schema) resolveAddressOverview(p graphql.ResolveParams) (interface{}, e
 
 		if firstSeen > 0 {
 			// Get timestamp from block
-			block, err := s.storage.GetBlock(ctx, firstSeen)
-			if err == nil && block != nil {
-				overview["firstSeen"] = fmt.Sprintf("%d", block.Time())
+			header, err := s.getBlockHeader(ctx, firstSeen)
+			if err == nil && header != nil {
+				overview["firstSeen"] = fmt.Sprintf("%d", header.Time)
 			}
 		}
 		if lastSeen > 0 {
-			block, err := s.storage.GetBlock(ctx, lastSeen)
-			if err == nil && block != nil {
-				overview["lastSeen"] = fmt.Sprintf("%d", block.Time())
+			header, err := s.getBlockHeader(ctx, lastSeen)
+			if err == nil && header != nil {
+				overview["lastSeen"] = fmt.Sprintf("%d", header.Time)
 			}
 		}
 	}
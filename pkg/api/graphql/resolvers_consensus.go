@@ -38,8 +38,8 @@ func (s *Schema) resolveConsensusData(p graphql.ResolveParams) (interface{}, err
 		return nil, err
 	}
 
-	// Get block for proposer (coinbase)
-	block, err := s.storage.GetBlock(ctx, blockNumber)
+	// Get block header for proposer (coinbase)
+	header, err := s.getBlockHeader(ctx, blockNumber)
 	if err != nil {
 		if errors.Is(err, storage.ErrNotFound) {
 			return nil, nil
@@ -67,7 +67,7 @@ func (s *Schema) resolveConsensusData(p graphql.ResolveParams) (interface{}, err
 	}
 
 	// Convert to ConsensusData
-	data := s.wbftExtraToConsensusData(wbftExtra, block.Header().Coinbase, prepareSigners, commitSigners)
+	data := s.wbftExtraToConsensusData(wbftExtra, header.Coinbase, prepareSigners, commitSigners)
 	if err != nil {
 		if errors.Is(err, storage.ErrNotFound) {
 			return nil, nil
@@ -101,14 +101,14 @@ func (s *Schema) resolveValidatorStats(p graphql.ResolveParams) (interface{}, er
 	}
 
 	address := common.HexToAddress(addressStr)
-	fromBlock, err := strconv.ParseUint(fromBlockStr, 10, 64)
+	fromBlock, err := s.resolveBlockTag(ctx, fromBlockStr)
 	if err != nil {
-		return nil, fmt.Errorf("invalid fromBlock format: %w", err)
+		return nil, fmt.Errorf("invalid fromBlock: %w", err)
 	}
 
-	toBlock, err := strconv.ParseUint(toBlockStr, 10, 64)
+	toBlock, err := s.resolveBlockTag(ctx, toBlockStr)
 	if err != nil {
-		return nil, fmt.Errorf("invalid toBlock format: %w", err)
+		return nil, fmt.Errorf("invalid toBlock: %w", err)
 	}
 
 	// Use ConsensusStorage to aggregate stats from individual signing activities
@@ -154,14 +154,14 @@ func (s *Schema) resolveValidatorParticipation(p graphql.ResolveParams) (interfa
 	}
 
 	address := common.HexToAddress(addressStr)
-	fromBlock, err := strconv.ParseUint(fromBlockStr, 10, 64)
+	fromBlock, err := s.resolveBlockTag(ctx, fromBlockStr)
 	if err != nil {
-		return nil, fmt.Errorf("invalid fromBlock format: %w", err)
+		return nil, fmt.Errorf("invalid fromBlock: %w", err)
 	}
 
-	toBlock, err := strconv.ParseUint(toBlockStr, 10, 64)
+	toBlock, err := s.resolveBlockTag(ctx, toBlockStr)
 	if err != nil {
-		return nil, fmt.Errorf("invalid toBlock format: %w", err)
+		return nil, fmt.Errorf("invalid toBlock: %w", err)
 	}
 
 	// Get pagination parameters
@@ -216,14 +216,14 @@ func (s *Schema) resolveAllValidatorStats(p graphql.ResolveParams) (interface{},
 		return nil, fmt.Errorf("invalid toBlock")
 	}
 
-	fromBlock, err := strconv.ParseUint(fromBlockStr, 10, 64)
+	fromBlock, err := s.resolveBlockTag(ctx, fromBlockStr)
 	if err != nil {
-		return nil, fmt.Errorf("invalid fromBlock format: %w", err)
+		return nil, fmt.Errorf("invalid fromBlock: %w", err)
 	}
 
-	toBlock, err := strconv.ParseUint(toBlockStr, 10, 64)
+	toBlock, err := s.resolveBlockTag(ctx, toBlockStr)
 	if err != nil {
-		return nil, fmt.Errorf("invalid toBlock format: %w", err)
+		return nil, fmt.Errorf("invalid toBlock: %w", err)
 	}
 
 	// Get pagination parameters
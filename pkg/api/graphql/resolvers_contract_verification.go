@@ -2,6 +2,7 @@ package graphql
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -65,7 +66,7 @@ func (s *Schema) resolveContractVerification(params graphql.ResolveParams) (inte
 	// Get contract verification data
 	verification, err := verificationReader.GetContractVerification(ctx, address)
 	if err != nil {
-		if err == storage.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			// Return unverified contract result
 			return &ContractVerificationResponse{
 				Address:    address.Hex(),
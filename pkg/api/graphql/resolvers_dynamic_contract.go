@@ -190,9 +190,9 @@ func (s *Schema) resolveDynamicContractEvents(p graphql.ResolveParams) (interfac
 
 			// Get block timestamp
 			timestamp := "0"
-			block, err := s.storage.GetBlock(ctx, log.BlockNumber)
-			if err == nil && block != nil {
-				timestamp = fmt.Sprintf("%d", block.Header().Time)
+			header, err := s.getBlockHeader(ctx, log.BlockNumber)
+			if err == nil && header != nil {
+				timestamp = fmt.Sprintf("%d", header.Time)
 			}
 
 			allEvents = append(allEvents, map[string]interface{}{
@@ -20,10 +20,10 @@ type richMockStorage struct {
 	mockStorage
 }
 
-func (m *richMockStorage) GetActiveMinters(_ context.Context) ([]common.Address, error) {
-	return []common.Address{
-		common.HexToAddress("0x0000000000000000000000000000000000000001"),
-		common.HexToAddress("0x0000000000000000000000000000000000000002"),
+func (m *richMockStorage) GetActiveMinters(_ context.Context) ([]*storage.ActiveMinter, error) {
+	return []*storage.ActiveMinter{
+		{Address: common.HexToAddress("0x0000000000000000000000000000000000000001"), Allowance: big.NewInt(1000000)},
+		{Address: common.HexToAddress("0x0000000000000000000000000000000000000002"), Allowance: big.NewInt(1000000)},
 	}, nil
 }
 func (m *richMockStorage) GetMinterAllowance(_ context.Context, _ common.Address) (*big.Int, error) {
@@ -1128,6 +1128,24 @@ func TestBlocksRangeResolver(t *testing.T) {
 		assert.Equal(t, true, br["hasMore"])
 	})
 
+	t.Run("range_withMissingBlocks", func(t *testing.T) {
+		// Only block 1 is stored, so 0 and 2-4 are gaps
+		result := handler.ExecuteQuery(`{ blocksRange(startNumber: "0", endNumber: "4") { blocks { number } count missingBlocks } }`, nil)
+		assert.Empty(t, result.Errors)
+		data := result.Data.(map[string]interface{})
+		br := data["blocksRange"].(map[string]interface{})
+		assert.Equal(t, 1, br["count"])
+		assert.Equal(t, []interface{}{"0", "2", "3", "4"}, br["missingBlocks"])
+	})
+
+	t.Run("range_complete", func(t *testing.T) {
+		result := handler.ExecuteQuery(`{ blocksRange(startNumber: "1", endNumber: "1") { missingBlocks } }`, nil)
+		assert.Empty(t, result.Errors)
+		data := result.Data.(map[string]interface{})
+		br := data["blocksRange"].(map[string]interface{})
+		assert.Empty(t, br["missingBlocks"])
+	})
+
 	t.Run("range_beyond_latest", func(t *testing.T) {
 		result := handler.ExecuteQuery(`{ blocksRange(startNumber: "200", endNumber: "300") { count hasMore } }`, nil)
 		assert.Empty(t, result.Errors)
@@ -1147,6 +1165,33 @@ func TestBlocksRangeResolver(t *testing.T) {
 	})
 }
 
+// TestLatestBlocksResolver exercises the resolveLatestBlocks function.
+func TestLatestBlocksResolver(t *testing.T) {
+	handler := newRichTestHandler(t)
+
+	t.Run("window_without_blocks", func(t *testing.T) {
+		// Only block 1 is stored and the latest height is 100
+		result := handler.ExecuteQuery(`{ latestBlocks(limit: 5) { number } }`, nil)
+		assert.Empty(t, result.Errors)
+		data := result.Data.(map[string]interface{})
+		assert.Empty(t, data["latestBlocks"])
+	})
+
+	t.Run("limit_clamped", func(t *testing.T) {
+		result := handler.ExecuteQuery(`{ latestBlocks(limit: 500) { number hash } }`, nil)
+		assert.Empty(t, result.Errors)
+		data := result.Data.(map[string]interface{})
+		blocks := data["latestBlocks"].([]interface{})
+		require.Len(t, blocks, 1)
+		assert.Equal(t, "1", blocks[0].(map[string]interface{})["number"])
+	})
+
+	t.Run("invalid_limit", func(t *testing.T) {
+		result := handler.ExecuteQuery(`{ latestBlocks(limit: 0) { number } }`, nil)
+		assert.NotEmpty(t, result.Errors)
+	})
+}
+
 // TestProposalStatusParsing exercises parseProposalStatus and proposalStatusToString.
 func TestProposalStatusParsing(t *testing.T) {
 	handler := newRichTestHandler(t)
@@ -1,7 +1,9 @@
 package graphql
 
 import (
+	"context"
 	"fmt"
+	"math"
 	"math/big"
 	"strconv"
 
@@ -51,13 +53,19 @@ func (s *Schema) resolveBlocksByTimeRange(p graphql.ResolveParams) (interface{},
 		}
 	}
 
+	orderStr, _ := p.Args["order"].(string)
+	order, err := storage.ParseSortOrder(orderStr)
+	if err != nil {
+		return nil, err
+	}
+
 	// Cast storage to HistoricalReader
 	histStorage, ok := s.storage.(storage.HistoricalReader)
 	if !ok {
 		return nil, fmt.Errorf("storage does not support historical queries")
 	}
 
-	blocks, err := histStorage.GetBlocksByTimeRange(ctx, fromTime, toTime, limit, offset)
+	blocks, err := histStorage.GetBlocksByTimeRange(ctx, fromTime, toTime, limit, offset, order)
 	if err != nil {
 		s.logger.Error("failed to get blocks by time range",
 			zap.Uint64("fromTime", fromTime),
@@ -197,7 +205,34 @@ func (s *Schema) resolveTransactionsByAddressFiltered(p graphql.ResolveParams) (
 		return nil, err
 	}
 
-	// Convert to maps
+	return s.transactionsWithReceiptsConnection(ctx, txsWithReceipts, len(txsWithReceipts) == limit, offset), nil
+}
+
+// resolveFilteredAddressTransactions serves transactionsByAddress when filter arguments are given
+func (s *Schema) resolveFilteredAddressTransactions(ctx context.Context, address common.Address, filter *storage.TransactionFilter, limit, offset int) (interface{}, error) {
+	histStorage, ok := s.storage.(storage.HistoricalReader)
+	if !ok {
+		return nil, fmt.Errorf("storage does not support historical queries")
+	}
+
+	// Fetch one extra result to report whether another page exists
+	txsWithReceipts, err := histStorage.GetTransactionsByAddressFiltered(ctx, address, filter, limit+1, offset)
+	if err != nil {
+		s.logger.Error("failed to get filtered transactions",
+			zap.String("address", address.Hex()),
+			zap.Error(err))
+		return nil, err
+	}
+
+	hasMore := len(txsWithReceipts) > limit
+	if hasMore {
+		txsWithReceipts = txsWithReceipts[:limit]
+	}
+	return s.transactionsWithReceiptsConnection(ctx, txsWithReceipts, hasMore, offset), nil
+}
+
+// transactionsWithReceiptsConnection builds a transaction connection whose nodes include receipts
+func (s *Schema) transactionsWithReceiptsConnection(ctx context.Context, txsWithReceipts []*storage.TransactionWithReceipt, hasNextPage bool, offset int) map[string]interface{} {
 	nodes := make([]interface{}, len(txsWithReceipts))
 	blockTimestamps := make(map[uint64]string) // cache block timestamps
 	for i, txr := range txsWithReceipts {
@@ -209,9 +244,9 @@ func (s *Schema) resolveTransactionsByAddressFiltered(p graphql.ResolveParams) (
 			if ts, ok := blockTimestamps[txr.Location.BlockHeight]; ok {
 				txMap["blockTimestamp"] = ts
 			} else {
-				block, blockErr := s.storage.GetBlock(ctx, txr.Location.BlockHeight)
-				if blockErr == nil && block != nil {
-					ts = fmt.Sprintf("%d", block.Header().Time)
+				header, blockErr := s.getBlockHeader(ctx, txr.Location.BlockHeight)
+				if blockErr == nil && header != nil {
+					ts = fmt.Sprintf("%d", header.Time)
 					blockTimestamps[txr.Location.BlockHeight] = ts
 					txMap["blockTimestamp"] = ts
 				}
@@ -224,12 +259,12 @@ func (s *Schema) resolveTransactionsByAddressFiltered(p graphql.ResolveParams) (
 		"nodes":      nodes,
 		"totalCount": len(txsWithReceipts),
 		"pageInfo": map[string]interface{}{
-			"hasNextPage":     len(txsWithReceipts) == limit,
+			"hasNextPage":     hasNextPage,
 			"hasPreviousPage": offset > 0,
 			"startCursor":     nil,
 			"endCursor":       nil,
 		},
-	}, nil
+	}
 }
 
 // resolveAddressBalance resolves address balance at a specific block
@@ -243,14 +278,11 @@ func (s *Schema) resolveAddressBalance(p graphql.ResolveParams) (interface{}, er
 
 	address := common.HexToAddress(addressStr)
 
-	// Parse block number (optional, defaults to 0 for latest)
-	var blockNumber uint64 = 0
-	if blockNumberStr, ok := p.Args["blockNumber"].(string); ok {
-		bn, err := strconv.ParseUint(blockNumberStr, 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("invalid blockNumber format: %w", err)
-		}
-		blockNumber = bn
+	// Parse block number (optional, latest when omitted). Storage reads block 0
+	// as latest, so a tag resolving to genesis is tracked separately.
+	blockNumber, atBlock, err := s.resolveOptionalBlockTag(ctx, p.Args, "blockNumber")
+	if err != nil {
+		return nil, err
 	}
 
 	// Cast storage to HistoricalReader
@@ -259,7 +291,12 @@ func (s *Schema) resolveAddressBalance(p graphql.ResolveParams) (interface{}, er
 		return nil, fmt.Errorf("storage does not support historical queries")
 	}
 
-	balance, err := histStorage.GetAddressBalance(ctx, address, blockNumber)
+	var balance *big.Int
+	if atBlock && blockNumber == 0 {
+		balance, err = genesisBalance(ctx, histStorage, address)
+	} else {
+		balance, err = histStorage.GetAddressBalance(ctx, address, blockNumber)
+	}
 	if err != nil {
 		s.logger.Error("failed to get address balance",
 			zap.String("address", addressStr),
@@ -271,6 +308,19 @@ func (s *Schema) resolveAddressBalance(p graphql.ResolveParams) (interface{}, er
 	return balance.String(), nil
 }
 
+// genesisBalance returns the balance of addr at block 0, the last snapshot
+// recorded there
+func genesisBalance(ctx context.Context, histStorage storage.HistoricalReader, addr common.Address) (*big.Int, error) {
+	snapshots, err := histStorage.GetBalanceHistory(ctx, addr, 0, 0, math.MaxInt32, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshots) == 0 {
+		return big.NewInt(0), nil
+	}
+	return snapshots[len(snapshots)-1].Balance, nil
+}
+
 // resolveBalanceHistory resolves balance history for an address
 func (s *Schema) resolveBalanceHistory(p graphql.ResolveParams) (interface{}, error) {
 	ctx := p.Context
@@ -292,14 +342,18 @@ func (s *Schema) resolveBalanceHistory(p graphql.ResolveParams) (interface{}, er
 		return nil, fmt.Errorf("invalid toBlock")
 	}
 
-	fromBlock, err := strconv.ParseUint(fromBlockStr, 10, 64)
+	fromBlock, err := s.resolveBlockTag(ctx, fromBlockStr)
 	if err != nil {
-		return nil, fmt.Errorf("invalid fromBlock format: %w", err)
+		return nil, fmt.Errorf("invalid fromBlock: %w", err)
 	}
 
-	toBlock, err := strconv.ParseUint(toBlockStr, 10, 64)
+	toBlock, err := s.resolveBlockTag(ctx, toBlockStr)
 	if err != nil {
-		return nil, fmt.Errorf("invalid toBlock format: %w", err)
+		return nil, fmt.Errorf("invalid toBlock: %w", err)
+	}
+
+	if fromBlock > toBlock {
+		return nil, fmt.Errorf("fromBlock (%d) cannot be greater than toBlock (%d)", fromBlock, toBlock)
 	}
 
 	// Get pagination parameters
@@ -431,7 +485,15 @@ func (s *Schema) resolveTopMiners(p graphql.ResolveParams) (interface{}, error)
 		return nil, fmt.Errorf("storage does not support historical queries")
 	}
 
-	stats, err := histStorage.GetTopMiners(ctx, limit, fromBlock, toBlock)
+	// Skipping fee computation avoids loading every receipt in the range
+	var stats []storage.MinerStats
+	var err error
+	minerReader, hasOptions := s.storage.(storage.MinerStatsReader)
+	if includeRewards, ok := p.Args["includeRewards"].(bool); ok && !includeRewards && hasOptions {
+		stats, err = minerReader.GetTopMinersWithOptions(ctx, limit, fromBlock, toBlock, storage.MinerStatsOptions{SkipRewards: true})
+	} else {
+		stats, err = histStorage.GetTopMiners(ctx, limit, fromBlock, toBlock)
+	}
 	if err != nil {
 		s.logger.Error("failed to get top miners",
 			zap.Int("limit", limit),
@@ -585,6 +647,17 @@ func parseHistoricalTransactionFilter(args map[string]interface{}) (*storage.Tra
 		filter.TxType = storage.TransactionType(txType)
 	}
 
+	// Parse optional transactionTypes (EIP-2718 type bytes)
+	if txTypes, ok := args["transactionTypes"].([]interface{}); ok {
+		for _, item := range txTypes {
+			txType, ok := item.(int)
+			if !ok || txType < 0 || txType > 0xff {
+				return nil, fmt.Errorf("invalid transaction type: %v", item)
+			}
+			filter.TransactionTypes = append(filter.TransactionTypes, uint8(txType))
+		}
+	}
+
 	// Parse optional successOnly
 	if successOnly, ok := args["successOnly"].(bool); ok {
 		filter.SuccessOnly = successOnly
@@ -631,6 +704,65 @@ func parseHistoricalTransactionFilter(args map[string]interface{}) (*storage.Tra
 	return filter, nil
 }
 
+// parseAddressTransactionFilter parses the optional filter arguments of transactionsByAddress.
+// It returns nil when none are set, so the unfiltered address index can serve the query.
+func parseAddressTransactionFilter(args map[string]interface{}) (*storage.TransactionFilter, error) {
+	filter := storage.DefaultTransactionFilter()
+	filtered := false
+
+	if direction, ok := args["txType"].(string); ok {
+		switch direction {
+		case "SENT":
+			filter.TxType = storage.TxTypeSent
+		case "RECEIVED":
+			filter.TxType = storage.TxTypeReceived
+		case "ALL":
+			filter.TxType = storage.TxTypeAll
+		default:
+			return nil, fmt.Errorf("invalid txType: %s", direction)
+		}
+		filtered = true
+	}
+
+	if minValueStr, ok := args["minValue"].(string); ok {
+		minValue, success := new(big.Int).SetString(minValueStr, 10)
+		if !success {
+			return nil, fmt.Errorf("invalid minValue format")
+		}
+		if minValue.Sign() < 0 {
+			return nil, fmt.Errorf("minValue cannot be negative")
+		}
+		filter.MinValue = minValue
+		filtered = true
+	}
+
+	if fromBlockStr, ok := args["fromBlock"].(string); ok {
+		fb, err := strconv.ParseUint(fromBlockStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fromBlock: %w", err)
+		}
+		filter.FromBlock = fb
+		filtered = true
+	}
+
+	if toBlockStr, ok := args["toBlock"].(string); ok {
+		tb, err := strconv.ParseUint(toBlockStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid toBlock: %w", err)
+		}
+		filter.ToBlock = tb
+		filtered = true
+	}
+
+	if !filtered {
+		return nil, nil
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	return filter, nil
+}
+
 // resolveGasStats resolves gas usage statistics for a block range
 func (s *Schema) resolveGasStats(p graphql.ResolveParams) (interface{}, error) {
 	ctx := p.Context
@@ -957,6 +1089,10 @@ func (s *Schema) resolveAddressStats(p graphql.ResolveParams) (interface{}, erro
 	if stats.LastTransactionTimestamp > 0 {
 		result["lastTransactionTimestamp"] = fmt.Sprintf("%d", stats.LastTransactionTimestamp)
 	}
+	if stats.TotalTransactions > 0 {
+		result["firstSeenBlock"] = fmt.Sprintf("%d", stats.FirstSeenBlock)
+		result["lastSeenBlock"] = fmt.Sprintf("%d", stats.LastSeenBlock)
+	}
 
 	return result, nil
 }
@@ -2,12 +2,17 @@ package graphql
 
 import (
 	"context"
+	"crypto/ecdsa"
 	"math/big"
+	"reflect"
 	"testing"
 
 	"github.com/0xmhha/indexer-go/pkg/storage"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/graphql-go/graphql"
 	"go.uber.org/zap"
 )
 
@@ -23,7 +28,7 @@ type mockHistoricalStorage struct {
 	txCount          uint64
 }
 
-func (m *mockHistoricalStorage) GetBlocksByTimeRange(ctx context.Context, fromTime, toTime uint64, limit, offset int) ([]*types.Block, error) {
+func (m *mockHistoricalStorage) GetBlocksByTimeRange(ctx context.Context, fromTime, toTime uint64, limit, offset int, order storage.SortOrder) ([]*types.Block, error) {
 	if m.blocksByTime == nil {
 		return []*types.Block{}, nil
 	}
@@ -710,6 +715,36 @@ func TestParseHistoricalTransactionFilter(t *testing.T) {
 		}
 	})
 
+	t.Run("TransactionTypes", func(t *testing.T) {
+		args := map[string]interface{}{
+			"fromBlock":        "0",
+			"toBlock":          "100",
+			"transactionTypes": []interface{}{2, 3},
+		}
+
+		filter, err := parseHistoricalTransactionFilter(args)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []uint8{2, 3}
+		if !reflect.DeepEqual(filter.TransactionTypes, want) {
+			t.Errorf("expected TransactionTypes %v, got %v", want, filter.TransactionTypes)
+		}
+	})
+
+	t.Run("InvalidTransactionType", func(t *testing.T) {
+		args := map[string]interface{}{
+			"fromBlock":        "0",
+			"toBlock":          "100",
+			"transactionTypes": []interface{}{2, 256},
+		}
+
+		_, err := parseHistoricalTransactionFilter(args)
+		if err == nil {
+			t.Error("expected error for out-of-range transaction type")
+		}
+	})
+
 	t.Run("OptionalFields", func(t *testing.T) {
 		args := map[string]interface{}{
 			"fromBlock": "0",
@@ -735,3 +770,224 @@ func TestParseHistoricalTransactionFilter(t *testing.T) {
 		}
 	})
 }
+
+func TestTransactionsByAddressFilters(t *testing.T) {
+	ctx := context.Background()
+	store, err := storage.NewPebbleStorage(storage.DefaultConfig(t.TempDir()))
+	if err != nil {
+		t.Fatalf("NewPebbleStorage() error = %v", err)
+	}
+	defer store.Close()
+
+	targetKey, _ := crypto.GenerateKey()
+	otherKey, _ := crypto.GenerateKey()
+	target := crypto.PubkeyToAddress(targetKey.PublicKey)
+	other := crypto.PubkeyToAddress(otherKey.PublicKey)
+	signer := types.LatestSignerForChainID(big.NewInt(1))
+
+	// One transaction per block: sent 100 wei, received 5000 wei, sent 10000 wei
+	seeds := []struct {
+		key   *ecdsa.PrivateKey
+		to    common.Address
+		value int64
+	}{
+		{targetKey, other, 100},
+		{otherKey, target, 5000},
+		{targetKey, other, 10000},
+	}
+	hashes := make([]common.Hash, len(seeds))
+	for i, seed := range seeds {
+		tx := types.MustSignNewTx(seed.key, signer, &types.LegacyTx{
+			Nonce:    uint64(i),
+			To:       &seed.to,
+			Value:    big.NewInt(seed.value),
+			Gas:      21000,
+			GasPrice: big.NewInt(1),
+		})
+		hashes[i] = tx.Hash()
+
+		header := &types.Header{Number: big.NewInt(int64(i + 1)), Time: uint64(1000 * (i + 1)), Difficulty: big.NewInt(0)}
+		block := types.NewBlock(header, &types.Body{Transactions: []*types.Transaction{tx}}, nil, trie.NewStackTrie(nil))
+		receipt := &types.Receipt{
+			TxHash:            tx.Hash(),
+			Status:            types.ReceiptStatusSuccessful,
+			CumulativeGasUsed: 21000,
+			Logs:              []*types.Log{},
+		}
+		if err := store.SetBlockWithReceipts(ctx, block, []*types.Receipt{receipt}); err != nil {
+			t.Fatalf("SetBlockWithReceipts() error = %v", err)
+		}
+		if err := store.AddTransactionToAddressIndex(ctx, target, tx.Hash()); err != nil {
+			t.Fatalf("AddTransactionToAddressIndex() error = %v", err)
+		}
+	}
+
+	schema, err := NewSchema(store, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewSchema() error = %v", err)
+	}
+
+	query := func(args string) *graphql.Result {
+		return graphql.Do(graphql.Params{
+			Schema: schema.schema,
+			RequestString: `{ transactionsByAddress(address: "` + target.Hex() + `"` + args + `) {
+				nodes { hash receipt { status } }
+				totalCount
+			} }`,
+			Context: ctx,
+		})
+	}
+
+	tests := []struct {
+		name string
+		args string
+		want []common.Hash
+	}{
+		{"Unfiltered", "", hashes},
+		{"All", ", txType: ALL", hashes},
+		{"Sent", ", txType: SENT", []common.Hash{hashes[0], hashes[2]}},
+		{"Received", ", txType: RECEIVED", []common.Hash{hashes[1]}},
+		{"MinValue", `, minValue: "5000"`, []common.Hash{hashes[1], hashes[2]}},
+		{"BlockRange", `, fromBlock: "2", toBlock: "3"`, []common.Hash{hashes[1], hashes[2]}},
+		{"FromBlockOnly", `, fromBlock: "3"`, []common.Hash{hashes[2]}},
+		{"Combined", `, txType: SENT, minValue: "1000", toBlock: "3"`, []common.Hash{hashes[2]}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := query(tt.args)
+			if len(result.Errors) > 0 {
+				t.Fatalf("unexpected errors: %v", result.Errors)
+			}
+			conn := result.Data.(map[string]interface{})["transactionsByAddress"].(map[string]interface{})
+			nodes := conn["nodes"].([]interface{})
+
+			got := make(map[common.Hash]bool, len(nodes))
+			for _, node := range nodes {
+				n := node.(map[string]interface{})
+				got[common.HexToHash(n["hash"].(string))] = true
+				if tt.args != "" && n["receipt"] == nil {
+					t.Errorf("transaction %s returned without receipt", n["hash"])
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d transactions, want %d", len(got), len(tt.want))
+			}
+			for _, hash := range tt.want {
+				if !got[hash] {
+					t.Errorf("missing transaction %s", hash.Hex())
+				}
+			}
+		})
+	}
+
+	for _, tt := range []struct {
+		name string
+		args string
+	}{
+		{"FromAfterTo", `, fromBlock: "3", toBlock: "1"`},
+		{"NegativeMinValue", `, minValue: "-1"`},
+		{"InvalidMinValue", `, minValue: "abc"`},
+		{"InvalidBlock", `, fromBlock: "abc"`},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := query(tt.args); len(result.Errors) == 0 {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestBalanceHistorySeries(t *testing.T) {
+	ctx := context.Background()
+	store, err := storage.NewPebbleStorage(storage.DefaultConfig(t.TempDir()))
+	if err != nil {
+		t.Fatalf("NewPebbleStorage() error = %v", err)
+	}
+	defer store.Close()
+
+	addr := common.HexToAddress("0x00000000000000000000000000000000000000b1")
+	seeds := []struct {
+		block  uint64
+		delta  int64
+		txHash common.Hash
+	}{
+		{10, 1000, common.HexToHash("0x0a")},
+		{20, 500, common.HexToHash("0x14")},
+		{30, -300, common.Hash{}},
+		{40, 800, common.HexToHash("0x28")},
+	}
+	for _, seed := range seeds {
+		if err := store.UpdateBalance(ctx, addr, seed.block, big.NewInt(seed.delta), seed.txHash); err != nil {
+			t.Fatalf("UpdateBalance(%d) error = %v", seed.block, err)
+		}
+	}
+
+	schema, err := NewSchema(store, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewSchema() error = %v", err)
+	}
+
+	query := func(args string) *graphql.Result {
+		return graphql.Do(graphql.Params{
+			Schema: schema.schema,
+			RequestString: `{ balanceHistory(address: "` + addr.Hex() + `"` + args + `) {
+				nodes { blockNumber balance delta transactionHash }
+				totalCount
+				pageInfo { hasNextPage hasPreviousPage }
+			} }`,
+			Context: ctx,
+		})
+	}
+	nodesOf := func(t *testing.T, result *graphql.Result) []map[string]interface{} {
+		t.Helper()
+		if len(result.Errors) > 0 {
+			t.Fatalf("unexpected errors: %v", result.Errors)
+		}
+		conn := result.Data.(map[string]interface{})["balanceHistory"].(map[string]interface{})
+		var nodes []map[string]interface{}
+		for _, node := range conn["nodes"].([]interface{}) {
+			nodes = append(nodes, node.(map[string]interface{}))
+		}
+		return nodes
+	}
+
+	t.Run("InBlockOrder", func(t *testing.T) {
+		nodes := nodesOf(t, query(`, fromBlock: "0", toBlock: "100"`))
+		if len(nodes) != len(seeds) {
+			t.Fatalf("got %d snapshots, want %d", len(nodes), len(seeds))
+		}
+
+		wantBalances := []string{"1000", "1500", "1200", "2000"}
+		for i, node := range nodes {
+			if node["blockNumber"] != big.NewInt(int64(seeds[i].block)).String() {
+				t.Errorf("snapshot %d blockNumber = %v, want %d", i, node["blockNumber"], seeds[i].block)
+			}
+			if node["balance"] != wantBalances[i] {
+				t.Errorf("snapshot %d balance = %v, want %s", i, node["balance"], wantBalances[i])
+			}
+			if node["delta"] != big.NewInt(seeds[i].delta).String() {
+				t.Errorf("snapshot %d delta = %v, want %d", i, node["delta"], seeds[i].delta)
+			}
+		}
+		if nodes[0]["transactionHash"] != seeds[0].txHash.Hex() {
+			t.Errorf("snapshot 0 transactionHash = %v, want %s", nodes[0]["transactionHash"], seeds[0].txHash.Hex())
+		}
+		if nodes[2]["transactionHash"] != nil {
+			t.Errorf("snapshot 2 transactionHash = %v, want null", nodes[2]["transactionHash"])
+		}
+	})
+
+	t.Run("BlockRangeAndPagination", func(t *testing.T) {
+		nodes := nodesOf(t, query(`, fromBlock: "15", toBlock: "40", pagination: {limit: 2, offset: 1}`))
+		if len(nodes) != 2 || nodes[0]["blockNumber"] != "30" || nodes[1]["blockNumber"] != "40" {
+			t.Errorf("got %v, want blocks 30 and 40", nodes)
+		}
+	})
+
+	t.Run("FromAfterTo", func(t *testing.T) {
+		if result := query(`, fromBlock: "40", toBlock: "10"`); len(result.Errors) == 0 {
+			t.Error("expected an error")
+		}
+	})
+}
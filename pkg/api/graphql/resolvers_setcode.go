@@ -313,9 +313,9 @@ func (s *Schema) resolveSetCodeTransactionsInBlock(p graphql.ResolveParams) (int
 				if ts, ok := blockTimestamps[location.BlockHeight]; ok {
 					txMap["blockTimestamp"] = ts
 				} else {
-					block, blockErr := s.storage.GetBlock(ctx, location.BlockHeight)
-					if blockErr == nil && block != nil {
-						ts = fmt.Sprintf("%d", block.Header().Time)
+					header, blockErr := s.getBlockHeader(ctx, location.BlockHeight)
+					if blockErr == nil && header != nil {
+						ts = fmt.Sprintf("%d", header.Time)
 						blockTimestamps[location.BlockHeight] = ts
 						txMap["blockTimestamp"] = ts
 					}
@@ -383,9 +383,9 @@ func (s *Schema) resolveRecentSetCodeTransactions(p graphql.ResolveParams) (inte
 				if ts, ok := blockTimestamps2[location.BlockHeight]; ok {
 					txMap["blockTimestamp"] = ts
 				} else {
-					block, blockErr := s.storage.GetBlock(ctx, location.BlockHeight)
-					if blockErr == nil && block != nil {
-						ts = fmt.Sprintf("%d", block.Header().Time)
+					header, blockErr := s.getBlockHeader(ctx, location.BlockHeight)
+					if blockErr == nil && header != nil {
+						ts = fmt.Sprintf("%d", header.Time)
 						blockTimestamps2[location.BlockHeight] = ts
 						txMap["blockTimestamp"] = ts
 					}
@@ -1,6 +1,7 @@
 package graphql
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/0xmhha/indexer-go/pkg/storage"
@@ -20,7 +21,7 @@ func (s *Schema) resolveTokenMetadata(p graphql.ResolveParams) (interface{}, err
 
 	metadata, err := s.storage.GetTokenMetadata(ctx, address)
 	if err != nil {
-		if err == storage.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			return nil, nil
 		}
 		return nil, err
@@ -322,7 +323,7 @@ func (s *Schema) resolveTokenBalance(p graphql.ResolveParams) (interface{}, erro
 
 	balance, err := holderReader.GetTokenBalance(ctx, token, holder)
 	if err != nil {
-		if err == storage.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			return "0", nil
 		}
 		return nil, err
@@ -349,7 +350,7 @@ func (s *Schema) resolveTokenHolderStats(p graphql.ResolveParams) (interface{},
 
 	stats, err := holderReader.GetTokenHolderStats(ctx, token)
 	if err != nil {
-		if err == storage.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			return nil, nil
 		}
 		return nil, err
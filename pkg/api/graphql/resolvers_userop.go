@@ -1,6 +1,7 @@
 package graphql
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 
@@ -30,7 +31,7 @@ func (s *Schema) resolveUserOperation(p graphql.ResolveParams) (interface{}, err
 
 	op, err := userOpReader.GetUserOp(ctx, opHash)
 	if err != nil {
-		if err == storagepkg.ErrNotFound {
+		if errors.Is(err, storagepkg.ErrNotFound) {
 			return nil, nil
 		}
 		s.logger.Error("failed to get UserOperation",
@@ -429,7 +430,7 @@ func (s *Schema) resolveSmartAccount(p graphql.ResolveParams) (interface{}, erro
 
 	account, err := userOpReader.GetSmartAccount(ctx, common.HexToAddress(addressStr))
 	if err != nil {
-		if err == storagepkg.ErrNotFound {
+		if errors.Is(err, storagepkg.ErrNotFound) {
 			return nil, nil
 		}
 		s.logger.Error("failed to get smart account", zap.String("address", addressStr), zap.Error(err))
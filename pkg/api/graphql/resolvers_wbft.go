@@ -116,9 +116,9 @@ func (s *Schema) resolveEpochInfo(p graphql.ResolveParams) (interface{}, error)
 	}
 
 	// Add timestamp from the epoch boundary block
-	block, err := s.storage.GetBlock(ctx, epochInfo.BlockNumber)
-	if err == nil && block != nil {
-		result["timestamp"] = fmt.Sprintf("%d", block.Header().Time)
+	header, err := s.getBlockHeader(ctx, epochInfo.BlockNumber)
+	if err == nil && header != nil {
+		result["timestamp"] = fmt.Sprintf("%d", header.Time)
 	}
 
 	return result, nil
@@ -155,9 +155,9 @@ func (s *Schema) resolveLatestEpochInfo(p graphql.ResolveParams) (interface{}, e
 	}
 
 	// Add timestamp from the epoch boundary block
-	block, err := s.storage.GetBlock(ctx, epochInfo.BlockNumber)
-	if err == nil && block != nil {
-		result["timestamp"] = fmt.Sprintf("%d", block.Header().Time)
+	header, err := s.getBlockHeader(ctx, epochInfo.BlockNumber)
+	if err == nil && header != nil {
+		result["timestamp"] = fmt.Sprintf("%d", header.Time)
 	}
 
 	return result, nil
@@ -190,9 +190,9 @@ func (s *Schema) resolveEpochs(p graphql.ResolveParams) (interface{}, error) {
 		}
 
 		// Fetch timestamp from the epoch boundary block
-		block, err := s.storage.GetBlock(ctx, epoch.BlockNumber)
-		if err == nil && block != nil {
-			node["timestamp"] = fmt.Sprintf("%d", block.Header().Time)
+		header, err := s.getBlockHeader(ctx, epoch.BlockNumber)
+		if err == nil && header != nil {
+			node["timestamp"] = fmt.Sprintf("%d", header.Time)
 		}
 
 		nodes[i] = node
@@ -228,14 +228,14 @@ func (s *Schema) resolveValidatorSigningStats(p graphql.ResolveParams) (interfac
 	}
 
 	validatorAddr := common.HexToAddress(validatorAddrStr)
-	fromBlock, err := strconv.ParseUint(fromBlockStr, 10, 64)
+	fromBlock, err := s.resolveBlockTag(ctx, fromBlockStr)
 	if err != nil {
-		return nil, fmt.Errorf("invalid fromBlock format: %w", err)
+		return nil, fmt.Errorf("invalid fromBlock: %w", err)
 	}
 
-	toBlock, err := strconv.ParseUint(toBlockStr, 10, 64)
+	toBlock, err := s.resolveBlockTag(ctx, toBlockStr)
 	if err != nil {
-		return nil, fmt.Errorf("invalid toBlock format: %w", err)
+		return nil, fmt.Errorf("invalid toBlock: %w", err)
 	}
 
 	// Check if storage implements WBFTReader
@@ -274,14 +274,14 @@ func (s *Schema) resolveAllValidatorsSigningStats(p graphql.ResolveParams) (inte
 		return nil, fmt.Errorf("invalid toBlock")
 	}
 
-	fromBlock, err := strconv.ParseUint(fromBlockStr, 10, 64)
+	fromBlock, err := s.resolveBlockTag(ctx, fromBlockStr)
 	if err != nil {
-		return nil, fmt.Errorf("invalid fromBlock format: %w", err)
+		return nil, fmt.Errorf("invalid fromBlock: %w", err)
 	}
 
-	toBlock, err := strconv.ParseUint(toBlockStr, 10, 64)
+	toBlock, err := s.resolveBlockTag(ctx, toBlockStr)
 	if err != nil {
-		return nil, fmt.Errorf("invalid toBlock format: %w", err)
+		return nil, fmt.Errorf("invalid toBlock: %w", err)
 	}
 
 	// Get pagination parameters
@@ -355,14 +355,14 @@ func (s *Schema) resolveValidatorSigningActivity(p graphql.ResolveParams) (inter
 	}
 
 	validatorAddr := common.HexToAddress(validatorAddrStr)
-	fromBlock, err := strconv.ParseUint(fromBlockStr, 10, 64)
+	fromBlock, err := s.resolveBlockTag(ctx, fromBlockStr)
 	if err != nil {
-		return nil, fmt.Errorf("invalid fromBlock format: %w", err)
+		return nil, fmt.Errorf("invalid fromBlock: %w", err)
 	}
 
-	toBlock, err := strconv.ParseUint(toBlockStr, 10, 64)
+	toBlock, err := s.resolveBlockTag(ctx, toBlockStr)
 	if err != nil {
-		return nil, fmt.Errorf("invalid toBlock format: %w", err)
+		return nil, fmt.Errorf("invalid toBlock: %w", err)
 	}
 
 	// Get pagination parameters
@@ -70,7 +70,8 @@ func (b *SchemaBuilder) WithCoreQueries() *SchemaBuilder {
 		Type: blockType,
 		Args: graphql.FieldConfigArgument{
 			"number": &graphql.ArgumentConfig{
-				Type: graphql.NewNonNull(bigIntType),
+				Type:        graphql.NewNonNull(blockTagType),
+				Description: "Block number or tag (earliest, latest, latest-N)",
 			},
 		},
 		Resolve: s.resolveBlock,
@@ -96,17 +97,28 @@ func (b *SchemaBuilder) WithCoreQueries() *SchemaBuilder {
 		},
 		Resolve: s.resolveBlocks,
 	}
+	b.queries["latestBlocks"] = &graphql.Field{
+		Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(blockType))),
+		Description: "Get the most recent blocks, newest first, with header fields only. The limit is clamped to the indexed range.",
+		Args: graphql.FieldConfigArgument{
+			"limit": &graphql.ArgumentConfig{
+				Type:        graphql.Int,
+				Description: "Number of blocks to return (default 10, maximum 100)",
+			},
+		},
+		Resolve: s.resolveLatestBlocks,
+	}
 	b.queries["blocksRange"] = &graphql.Field{
 		Type:        graphql.NewNonNull(blockRangeResultType),
 		Description: "Get blocks in a specific range (optimized for frontend catch-up). Maximum 100 blocks per request.",
 		Args: graphql.FieldConfigArgument{
 			"startNumber": &graphql.ArgumentConfig{
-				Type:        graphql.NewNonNull(bigIntType),
-				Description: "Starting block number (inclusive)",
+				Type:        graphql.NewNonNull(blockTagType),
+				Description: "Starting block number or tag (inclusive)",
 			},
 			"endNumber": &graphql.ArgumentConfig{
-				Type:        graphql.NewNonNull(bigIntType),
-				Description: "Ending block number (inclusive)",
+				Type:        graphql.NewNonNull(blockTagType),
+				Description: "Ending block number or tag (inclusive)",
 			},
 			"includeTransactions": &graphql.ArgumentConfig{
 				Type:         graphql.Boolean,
@@ -148,6 +160,22 @@ func (b *SchemaBuilder) WithCoreQueries() *SchemaBuilder {
 			"address": &graphql.ArgumentConfig{
 				Type: graphql.NewNonNull(addressType),
 			},
+			"txType": &graphql.ArgumentConfig{
+				Type:        transactionDirectionEnum,
+				Description: "Restrict to transactions sent or received by the address",
+			},
+			"minValue": &graphql.ArgumentConfig{
+				Type:        bigIntType,
+				Description: "Minimum transaction value in wei (inclusive)",
+			},
+			"fromBlock": &graphql.ArgumentConfig{
+				Type:        bigIntType,
+				Description: "First block of the range (inclusive)",
+			},
+			"toBlock": &graphql.ArgumentConfig{
+				Type:        bigIntType,
+				Description: "Last block of the range (inclusive)",
+			},
 			"pagination": &graphql.ArgumentConfig{
 				Type: paginationInputType,
 			},
@@ -167,7 +195,8 @@ func (b *SchemaBuilder) WithCoreQueries() *SchemaBuilder {
 		Type: graphql.NewList(graphql.NewNonNull(receiptType)),
 		Args: graphql.FieldConfigArgument{
 			"blockNumber": &graphql.ArgumentConfig{
-				Type: graphql.NewNonNull(bigIntType),
+				Type:        graphql.NewNonNull(blockTagType),
+				Description: "Block number or tag (earliest, latest, latest-N)",
 			},
 		},
 		Resolve: s.resolveReceiptsByBlock,
@@ -216,6 +245,11 @@ func (b *SchemaBuilder) WithHistoricalQueries() *SchemaBuilder {
 			"pagination": &graphql.ArgumentConfig{
 				Type: paginationInputType,
 			},
+			"order": &graphql.ArgumentConfig{
+				Type:         sortOrderEnum,
+				DefaultValue: "asc",
+				Description:  "Timestamp order of the results (default ASC)",
+			},
 		},
 		Resolve: s.resolveBlocksByTimeRange,
 	}
@@ -250,7 +284,8 @@ func (b *SchemaBuilder) WithHistoricalQueries() *SchemaBuilder {
 				Type: graphql.NewNonNull(addressType),
 			},
 			"blockNumber": &graphql.ArgumentConfig{
-				Type: bigIntType,
+				Type:        blockTagType,
+				Description: "Block number or tag (earliest, latest, latest-N)",
 			},
 		},
 		Resolve: s.resolveAddressBalance,
@@ -262,10 +297,10 @@ func (b *SchemaBuilder) WithHistoricalQueries() *SchemaBuilder {
 				Type: graphql.NewNonNull(addressType),
 			},
 			"fromBlock": &graphql.ArgumentConfig{
-				Type: graphql.NewNonNull(bigIntType),
+				Type: graphql.NewNonNull(blockTagType),
 			},
 			"toBlock": &graphql.ArgumentConfig{
-				Type: graphql.NewNonNull(bigIntType),
+				Type: graphql.NewNonNull(blockTagType),
 			},
 			"pagination": &graphql.ArgumentConfig{
 				Type: paginationInputType,
@@ -296,6 +331,11 @@ func (b *SchemaBuilder) WithAnalyticsQueries() *SchemaBuilder {
 				Type:        bigIntType,
 				Description: "End block number (0 = latest)",
 			},
+			"includeRewards": &graphql.ArgumentConfig{
+				Type:         graphql.Boolean,
+				DefaultValue: true,
+				Description:  "Compute totalRewards from transaction fees (default: true). Set to false for faster block-count-only ranking",
+			},
 		},
 		Description: "Get top miners by block count in a given block range",
 		Resolve:     s.resolveTopMiners,
@@ -737,10 +777,10 @@ func (b *SchemaBuilder) WithConsensusQueries() *SchemaBuilder {
 				Type: graphql.NewNonNull(addressType),
 			},
 			"fromBlock": &graphql.ArgumentConfig{
-				Type: graphql.NewNonNull(bigIntType),
+				Type: graphql.NewNonNull(blockTagType),
 			},
 			"toBlock": &graphql.ArgumentConfig{
-				Type: graphql.NewNonNull(bigIntType),
+				Type: graphql.NewNonNull(blockTagType),
 			},
 		},
 		Resolve: s.resolveValidatorSigningStats,
@@ -749,10 +789,10 @@ func (b *SchemaBuilder) WithConsensusQueries() *SchemaBuilder {
 		Type: graphql.NewNonNull(validatorSigningStatsConnectionType),
 		Args: graphql.FieldConfigArgument{
 			"fromBlock": &graphql.ArgumentConfig{
-				Type: graphql.NewNonNull(bigIntType),
+				Type: graphql.NewNonNull(blockTagType),
 			},
 			"toBlock": &graphql.ArgumentConfig{
-				Type: graphql.NewNonNull(bigIntType),
+				Type: graphql.NewNonNull(blockTagType),
 			},
 			"pagination": &graphql.ArgumentConfig{
 				Type: paginationInputType,
@@ -767,10 +807,10 @@ func (b *SchemaBuilder) WithConsensusQueries() *SchemaBuilder {
 				Type: graphql.NewNonNull(addressType),
 			},
 			"fromBlock": &graphql.ArgumentConfig{
-				Type: graphql.NewNonNull(bigIntType),
+				Type: graphql.NewNonNull(blockTagType),
 			},
 			"toBlock": &graphql.ArgumentConfig{
-				Type: graphql.NewNonNull(bigIntType),
+				Type: graphql.NewNonNull(blockTagType),
 			},
 			"pagination": &graphql.ArgumentConfig{
 				Type: paginationInputType,
@@ -803,10 +843,10 @@ func (b *SchemaBuilder) WithConsensusQueries() *SchemaBuilder {
 				Type: graphql.NewNonNull(addressType),
 			},
 			"fromBlock": &graphql.ArgumentConfig{
-				Type: graphql.NewNonNull(bigIntType),
+				Type: graphql.NewNonNull(blockTagType),
 			},
 			"toBlock": &graphql.ArgumentConfig{
-				Type: graphql.NewNonNull(bigIntType),
+				Type: graphql.NewNonNull(blockTagType),
 			},
 		},
 		Resolve: s.resolveValidatorStats,
@@ -818,10 +858,10 @@ func (b *SchemaBuilder) WithConsensusQueries() *SchemaBuilder {
 				Type: graphql.NewNonNull(addressType),
 			},
 			"fromBlock": &graphql.ArgumentConfig{
-				Type: graphql.NewNonNull(bigIntType),
+				Type: graphql.NewNonNull(blockTagType),
 			},
 			"toBlock": &graphql.ArgumentConfig{
-				Type: graphql.NewNonNull(bigIntType),
+				Type: graphql.NewNonNull(blockTagType),
 			},
 			"pagination": &graphql.ArgumentConfig{
 				Type: paginationInputType,
@@ -833,10 +873,10 @@ func (b *SchemaBuilder) WithConsensusQueries() *SchemaBuilder {
 		Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(validatorStatsType))),
 		Args: graphql.FieldConfigArgument{
 			"fromBlock": &graphql.ArgumentConfig{
-				Type: graphql.NewNonNull(bigIntType),
+				Type: graphql.NewNonNull(blockTagType),
 			},
 			"toBlock": &graphql.ArgumentConfig{
-				Type: graphql.NewNonNull(bigIntType),
+				Type: graphql.NewNonNull(blockTagType),
 			},
 			"pagination": &graphql.ArgumentConfig{
 				Type: paginationInputType,
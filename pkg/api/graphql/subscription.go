@@ -10,6 +10,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/0xmhha/indexer-go/internal/constants"
 	"github.com/0xmhha/indexer-go/pkg/events"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
@@ -20,8 +21,6 @@ import (
 const (
 	// WebSocket configuration
 	writeWait      = 10 * time.Second
-	pongWait       = 60 * time.Second
-	pingPeriod     = (pongWait * 9) / 10
 	maxMessageSize = 4096
 )
 
@@ -31,6 +30,11 @@ type SubscriptionServer struct {
 	logger          *zap.Logger
 	upgrader        websocket.Upgrader
 	enableKeepAlive bool
+	// pingInterval and idleTimeout apply when keep-alive is enabled
+	pingInterval time.Duration
+	idleTimeout  time.Duration
+	// mask rewrites each message before it is sent; nil sends messages as is
+	mask func(message []byte) ([]byte, error)
 }
 
 // NewSubscriptionServer creates a new subscription server
@@ -39,6 +43,8 @@ func NewSubscriptionServer(eventBus *events.EventBus, logger *zap.Logger, enable
 		eventBus:        eventBus,
 		logger:          logger,
 		enableKeepAlive: enableKeepAlive,
+		pingInterval:    constants.DefaultWebSocketPingInterval,
+		idleTimeout:     constants.DefaultWebSocketIdleTimeout,
 		upgrader: websocket.Upgrader{
 			ReadBufferSize:  1024,
 			WriteBufferSize: 1024,
@@ -84,6 +90,9 @@ func (s *SubscriptionServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		ctx:             ctx,
 		cancel:          cancel,
 		enableKeepAlive: s.enableKeepAlive,
+		pingInterval:    s.pingInterval,
+		idleTimeout:     s.idleTimeout,
+		mask:            s.mask,
 	}
 
 	go client.writePump()
@@ -101,6 +110,9 @@ type subscriptionClient struct {
 	ctx             context.Context
 	cancel          context.CancelFunc
 	enableKeepAlive bool
+	pingInterval    time.Duration
+	idleTimeout     time.Duration
+	mask            func(message []byte) ([]byte, error)
 }
 
 // clientSubscription holds subscription state
@@ -133,11 +145,15 @@ func (c *subscriptionClient) readPump() {
 	}()
 
 	c.conn.SetReadLimit(maxMessageSize)
-	_ = c.conn.SetReadDeadline(time.Now().Add(pongWait))
-	c.conn.SetPongHandler(func(string) error {
-		c.logger.Debug("received pong message")
-		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
-	})
+	// With keep-alive, a client that answers neither pings nor sends messages
+	// within the idle timeout is disconnected and its subscriptions released
+	if c.enableKeepAlive {
+		_ = c.conn.SetReadDeadline(time.Now().Add(c.idleTimeout))
+		c.conn.SetPongHandler(func(string) error {
+			c.logger.Debug("received pong message")
+			return c.conn.SetReadDeadline(time.Now().Add(c.idleTimeout))
+		})
+	}
 
 	for {
 		_, message, err := c.conn.ReadMessage()
@@ -149,6 +165,9 @@ func (c *subscriptionClient) readPump() {
 			}
 			break
 		}
+		if c.enableKeepAlive {
+			_ = c.conn.SetReadDeadline(time.Now().Add(c.idleTimeout))
+		}
 
 		c.logger.Debug("received message", zap.String("message", string(message)))
 		c.handleMessage(message)
@@ -159,10 +178,10 @@ func (c *subscriptionClient) readPump() {
 func (c *subscriptionClient) writePump() {
 	var ticker *time.Ticker
 	if c.enableKeepAlive {
-		ticker = time.NewTicker(pingPeriod)
+		ticker = time.NewTicker(c.pingInterval)
 		c.logger.Debug("WebSocket keep-alive enabled",
-			zap.Duration("ping_period", pingPeriod),
-			zap.Duration("pong_wait", pongWait))
+			zap.Duration("ping_interval", c.pingInterval),
+			zap.Duration("idle_timeout", c.idleTimeout))
 	}
 
 	defer func() {
@@ -180,6 +199,15 @@ func (c *subscriptionClient) writePump() {
 				_ = c.conn.WriteMessage(websocket.CloseMessage, []byte{})
 				return
 			}
+			if c.mask != nil {
+				masked, err := c.mask(message)
+				if err != nil {
+					// Drop the message rather than send it unmasked
+					c.logger.Warn("dropping subscription message that could not be masked", zap.Error(err))
+					continue
+				}
+				message = masked
+			}
 
 			if err := c.conn.WriteMessage(websocket.TextMessage, message); err != nil {
 				return
@@ -341,8 +369,7 @@ func (c *subscriptionClient) handleSubscribe(id string, payload json.RawMessage)
 	// Create subscription ID
 	subID := events.SubscriptionID(id)
 	opts := events.SubscribeOptions{
-		ChannelSize: 100,
-		ReplayLast:  replayLast,
+		ReplayLast: replayLast, // ChannelSize falls back to the bus's subscribe buffer size
 	}
 	eventSub := c.server.eventBus.SubscribeWithOptions(subID, []events.EventType{eventType}, filter, opts)
 	if eventSub == nil {
@@ -1170,6 +1197,25 @@ func (s *SubscriptionServer) SetEventBus(bus *events.EventBus) {
 	s.eventBus = bus
 }
 
+// SetKeepAliveIntervals sets how often pings are sent and how long a
+// connection may go without a pong or message before it is closed. Zero keeps
+// the current value. Only new connections are affected.
+func (s *SubscriptionServer) SetKeepAliveIntervals(pingInterval, idleTimeout time.Duration) {
+	if pingInterval > 0 {
+		s.pingInterval = pingInterval
+	}
+	if idleTimeout > 0 {
+		s.idleTimeout = idleTimeout
+	}
+}
+
+// SetMessageMask rewrites every message sent on connections accepted
+// afterwards with mask, e.g. to hide fields and addresses. A message that
+// cannot be masked is dropped rather than sent as is.
+func (s *SubscriptionServer) SetMessageMask(mask func(message []byte) ([]byte, error)) {
+	s.mask = mask
+}
+
 // SubscriptionHandler returns a handler that checks for EventBus availability
 func (s *SubscriptionServer) Handler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -2,6 +2,8 @@ package graphql
 
 import (
 	"encoding/json"
+	"errors"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -184,6 +186,114 @@ func TestSubscriptionServer_PingPong(t *testing.T) {
 	}
 }
 
+// subscribeNewBlock connects to the server and starts a newBlock subscription
+func subscribeNewBlock(t *testing.T, url string) *websocket.Conn {
+	t.Helper()
+
+	wsURL := "ws" + strings.TrimPrefix(url, "http")
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+
+	payload, _ := json.Marshal(subscribePayload{
+		Query: "subscription { newBlock { number hash } }",
+	})
+	if err := conn.WriteJSON(wsMessage{ID: "1", Type: "subscribe", Payload: payload}); err != nil {
+		t.Fatalf("failed to send subscribe: %v", err)
+	}
+	return conn
+}
+
+// waitForSubscribers waits until the event bus has want subscribers
+func waitForSubscribers(t *testing.T, eventBus *events.EventBus, want int) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for eventBus.SubscriberCount() != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d subscribers, got %d", want, eventBus.SubscriberCount())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestSubscriptionServer_KeepAlive(t *testing.T) {
+	const (
+		pingInterval = 50 * time.Millisecond
+		idleTimeout  = 200 * time.Millisecond
+	)
+
+	t.Run("unresponsive client is closed", func(t *testing.T) {
+		eventBus := events.NewEventBus(100, 10)
+		go eventBus.Run()
+		defer eventBus.Stop()
+
+		server := NewSubscriptionServer(eventBus, zap.NewNop(), true)
+		server.SetKeepAliveIntervals(pingInterval, idleTimeout)
+		ts := httptest.NewServer(http.HandlerFunc(server.ServeHTTP))
+		defer ts.Close()
+
+		conn := subscribeNewBlock(t, ts.URL)
+		defer conn.Close()
+		waitForSubscribers(t, eventBus, 1)
+
+		// Swallow pings without answering, as a client behind a dead
+		// intermediary would
+		pings := 0
+		conn.SetPingHandler(func(string) error {
+			pings++
+			return nil
+		})
+
+		_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		for {
+			_, _, err := conn.ReadMessage()
+			if err == nil {
+				continue
+			}
+			if isTimeout(err) {
+				t.Fatal("expected the server to close the connection")
+			}
+			break
+		}
+		if pings == 0 {
+			t.Error("expected the server to send pings")
+		}
+
+		waitForSubscribers(t, eventBus, 0)
+	})
+
+	t.Run("responsive client stays connected", func(t *testing.T) {
+		eventBus := events.NewEventBus(100, 10)
+		go eventBus.Run()
+		defer eventBus.Stop()
+
+		server := NewSubscriptionServer(eventBus, zap.NewNop(), true)
+		server.SetKeepAliveIntervals(pingInterval, idleTimeout)
+		ts := httptest.NewServer(http.HandlerFunc(server.ServeHTTP))
+		defer ts.Close()
+
+		conn := subscribeNewBlock(t, ts.URL)
+		defer conn.Close()
+		waitForSubscribers(t, eventBus, 1)
+
+		// The default ping handler answers with a pong while reading
+		_ = conn.SetReadDeadline(time.Now().Add(3 * idleTimeout))
+		if _, _, err := conn.ReadMessage(); !isTimeout(err) {
+			t.Fatalf("expected the connection to stay open, got %v", err)
+		}
+		if eventBus.SubscriberCount() != 1 {
+			t.Errorf("expected 1 subscriber, got %d", eventBus.SubscriberCount())
+		}
+	})
+}
+
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
 func TestParseSubscriptionType(t *testing.T) {
 	logger := zap.NewNop()
 	client := &subscriptionClient{logger: logger}
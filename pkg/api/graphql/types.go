@@ -1,7 +1,10 @@
 package graphql
 
 import (
+	"strconv"
+
 	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
 )
 
 var (
@@ -11,6 +14,39 @@ var (
 	addressType = graphql.String
 	hashType    = graphql.String
 
+	// blockTagType accepts a block number, "earliest", "latest", or an offset
+	// from the latest indexed block ("latest-N" or "-N"), rejecting anything
+	// else during validation
+	blockTagType = graphql.NewScalar(graphql.ScalarConfig{
+		Name:        "BlockTag",
+		Description: "A block number, earliest, latest, or an offset below the latest indexed block (latest-N or -N)",
+		Serialize: func(value interface{}) interface{} {
+			return value
+		},
+		ParseValue: func(value interface{}) interface{} {
+			switch v := value.(type) {
+			case string:
+				return validBlockTag(v)
+			case int:
+				return validBlockTag(strconv.Itoa(v))
+			case float64:
+				if v == float64(uint64(v)) {
+					return validBlockTag(strconv.FormatUint(uint64(v), 10))
+				}
+			}
+			return nil
+		},
+		ParseLiteral: func(valueAST ast.Value) interface{} {
+			switch v := valueAST.(type) {
+			case *ast.StringValue:
+				return validBlockTag(v.Value)
+			case *ast.IntValue:
+				return validBlockTag(v.Value)
+			}
+			return nil
+		},
+	})
+
 	// Block type
 	blockType *graphql.Object
 
@@ -57,6 +93,7 @@ var (
 	paginationInputType             *graphql.InputObject
 	historicalTransactionFilterType *graphql.InputObject
 	transactionDirectionEnum        *graphql.Enum
+	sortOrderEnum                   *graphql.Enum
 
 	// Historical data types
 	balanceSnapshotType          *graphql.Object
@@ -302,6 +339,9 @@ func initCoreTypes() {
 			"effectiveGasPrice": &graphql.Field{
 				Type: graphql.NewNonNull(bigIntType),
 			},
+			"fee": &graphql.Field{
+				Type: graphql.NewNonNull(bigIntType),
+			},
 			"status": &graphql.Field{
 				Type: graphql.NewNonNull(graphql.Int),
 			},
@@ -311,6 +351,14 @@ func initCoreTypes() {
 			"logsBloom": &graphql.Field{
 				Type: graphql.NewNonNull(bytesType),
 			},
+			"revertReason": &graphql.Field{
+				Type:        graphql.String,
+				Description: "Decoded revert reason of a failed transaction (requires indexer.revert_reasons)",
+			},
+			"revertData": &graphql.Field{
+				Type:        bytesType,
+				Description: "Raw revert output of a failed transaction (requires indexer.revert_reasons)",
+			},
 		},
 	})
 
@@ -379,6 +427,15 @@ func initCoreTypes() {
 			"accessList": &graphql.Field{
 				Type: graphql.NewList(graphql.NewNonNull(accessListEntryType)),
 			},
+			// Blob fields (EIP-4844, type 0x03)
+			"maxFeePerBlobGas": &graphql.Field{
+				Type:        bigIntType,
+				Description: "Max fee per blob gas for blob transactions (type 0x03)",
+			},
+			"blobVersionedHashes": &graphql.Field{
+				Type:        graphql.NewList(graphql.NewNonNull(hashType)),
+				Description: "Versioned hashes of the blobs carried by blob transactions (type 0x03)",
+			},
 			"receipt": &graphql.Field{
 				Type: receiptType,
 			},
@@ -546,6 +603,10 @@ func initConnectionTypes() {
 				Type:        graphql.NewNonNull(bigIntType),
 				Description: "Latest known block height for sync status",
 			},
+			"missingBlocks": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(bigIntType))),
+				Description: "Heights in the range with no indexed block, empty when the range is complete",
+			},
 		},
 	})
 
@@ -720,10 +781,10 @@ func initInputTypes() {
 				Type: graphql.NewList(hashType),
 			},
 			"blockNumberFrom": &graphql.InputObjectFieldConfig{
-				Type: bigIntType,
+				Type: blockTagType,
 			},
 			"blockNumberTo": &graphql.InputObjectFieldConfig{
-				Type: bigIntType,
+				Type: blockTagType,
 			},
 		},
 	})
@@ -759,6 +820,21 @@ func initInputTypes() {
 		},
 	})
 
+	sortOrderEnum = graphql.NewEnum(graphql.EnumConfig{
+		Name:        "SortOrder",
+		Description: "Result ordering",
+		Values: graphql.EnumValueConfigMap{
+			"ASC": &graphql.EnumValueConfig{
+				Value:       "asc",
+				Description: "Oldest first",
+			},
+			"DESC": &graphql.EnumValueConfig{
+				Value:       "desc",
+				Description: "Newest first",
+			},
+		},
+	})
+
 	historicalTransactionFilterType = graphql.NewInputObject(graphql.InputObjectConfig{
 		Name: "HistoricalTransactionFilter",
 		Fields: graphql.InputObjectConfigFieldMap{
@@ -777,6 +853,10 @@ func initInputTypes() {
 			"txType": &graphql.InputObjectFieldConfig{
 				Type: graphql.Int,
 			},
+			"transactionTypes": &graphql.InputObjectFieldConfig{
+				Type:        graphql.NewList(graphql.NewNonNull(graphql.Int)),
+				Description: "Filter by EIP-2718 transaction type (0=legacy, 1=access list, 2=dynamic fee, 3=blob, 4=set code)",
+			},
 			"successOnly": &graphql.InputObjectFieldConfig{
 				Type: graphql.Boolean,
 			},
@@ -1068,6 +1148,12 @@ func initStatsTypes() {
 			"lastTransactionTimestamp": &graphql.Field{
 				Type: bigIntType,
 			},
+			"firstSeenBlock": &graphql.Field{
+				Type: bigIntType,
+			},
+			"lastSeenBlock": &graphql.Field{
+				Type: bigIntType,
+			},
 		},
 	})
 }
@@ -1578,10 +1664,10 @@ func initGovernanceTypes() {
 		Name: "SystemContractEventFilter",
 		Fields: graphql.InputObjectConfigFieldMap{
 			"fromBlock": &graphql.InputObjectFieldConfig{
-				Type: bigIntType,
+				Type: blockTagType,
 			},
 			"toBlock": &graphql.InputObjectFieldConfig{
-				Type: bigIntType,
+				Type: blockTagType,
 			},
 			"address": &graphql.InputObjectFieldConfig{
 				Type:        addressType,
@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"sync"
 	"time"
@@ -276,7 +277,7 @@ func (hc *HealthChecker) checkStorageHealth(ctx context.Context) *ComponentHealt
 	message := "Storage is operational"
 	if err != nil {
 		// Check if it's just an empty database (no blocks indexed yet)
-		if err != storage.ErrNotFound {
+		if !errors.Is(err, storage.ErrNotFound) {
 			status = "unhealthy"
 			message = err.Error()
 		}
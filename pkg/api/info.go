@@ -0,0 +1,136 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+
+	"github.com/0xmhha/indexer-go/pkg/events"
+	"github.com/0xmhha/indexer-go/pkg/storage"
+)
+
+// InfoOptions describes the build and indexing setup reported by GET /info
+type InfoOptions struct {
+	Version   string
+	Commit    string
+	BuildTime string
+
+	// StartHeight is the first height the indexer indexes (indexer.start_height)
+	StartHeight uint64
+
+	// DisabledEventCategories are system contract event categories excluded
+	// from indexing
+	DisabledEventCategories []events.SystemEventCategory
+}
+
+// InfoResponse is the response of GET /info
+type InfoResponse struct {
+	Name      string   `json:"name"`
+	Version   string   `json:"version"`
+	Commit    string   `json:"commit"`
+	BuildTime string   `json:"build_time"`
+	ChainID   *big.Int `json:"chain_id,omitempty"`
+	// APIs lists the enabled APIs (graphql, jsonrpc, websocket, rest, etherscan)
+	APIs     []string       `json:"apis"`
+	Indexing *IndexingRange `json:"indexing,omitempty"`
+	// IndexingPaused is set when this process indexes, reporting whether
+	// indexing is paused through the admin API
+	IndexingPaused *bool `json:"indexing_paused,omitempty"`
+	// EventCategories lists the indexed system contract event categories
+	EventCategories []events.SystemEventCategory `json:"event_categories"`
+}
+
+// IndexingRange is the range of heights the indexer has covered
+type IndexingRange struct {
+	Earliest uint64 `json:"earliest"`
+	Latest   uint64 `json:"latest"`
+}
+
+// handleInfo handles the info endpoint
+func (s *Server) handleInfo(w http.ResponseWriter, r *http.Request) {
+	info := s.info
+	if info == nil {
+		info = &InfoOptions{}
+	}
+
+	response := InfoResponse{
+		Name:            "indexer-go",
+		Version:         info.Version,
+		Commit:          info.Commit,
+		BuildTime:       info.BuildTime,
+		ChainID:         s.chainID(r.Context()),
+		APIs:            s.enabledAPIs(),
+		Indexing:        s.indexingRange(r.Context(), info.StartHeight),
+		EventCategories: enabledEventCategories(info.DisabledEventCategories),
+	}
+	if s.indexing != nil {
+		paused := s.indexing.Paused()
+		response.IndexingPaused = &paused
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// chainID returns the stored chain ID, or nil if none is stored yet
+func (s *Server) chainID(ctx context.Context) *big.Int {
+	reader, ok := s.storage.(storage.ChainIDReader)
+	if !ok {
+		return nil
+	}
+	chainID, err := reader.GetChainID(ctx)
+	if err != nil {
+		return nil
+	}
+	return chainID
+}
+
+// enabledAPIs returns the names of the APIs served
+func (s *Server) enabledAPIs() []string {
+	apis := make([]string, 0, 5)
+	if s.config.EnableGraphQL {
+		apis = append(apis, "graphql")
+	}
+	if s.config.EnableJSONRPC {
+		apis = append(apis, "jsonrpc")
+	}
+	if s.config.EnableWebSocket {
+		apis = append(apis, "websocket")
+	}
+	if s.config.EnableREST {
+		apis = append(apis, "rest")
+	}
+	// The Etherscan-compatible API is always served
+	return append(apis, "etherscan")
+}
+
+// indexingRange returns the indexed heights from the start height to the
+// latest height, or nil if no block has been indexed yet
+func (s *Server) indexingRange(ctx context.Context, startHeight uint64) *IndexingRange {
+	latest, err := s.storage.GetLatestHeight(ctx)
+	if err != nil {
+		return nil
+	}
+	earliest := startHeight
+	if earliest > latest {
+		earliest = latest
+	}
+	return &IndexingRange{Earliest: earliest, Latest: latest}
+}
+
+// enabledEventCategories returns all system event categories except disabled
+func enabledEventCategories(disabled []events.SystemEventCategory) []events.SystemEventCategory {
+	skip := make(map[events.SystemEventCategory]bool, len(disabled))
+	for _, category := range disabled {
+		skip[category] = true
+	}
+	categories := make([]events.SystemEventCategory, 0)
+	for _, category := range events.SystemEventCategories() {
+		if !skip[category] {
+			categories = append(categories, category)
+		}
+	}
+	return categories
+}
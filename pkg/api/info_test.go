@@ -0,0 +1,135 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/0xmhha/indexer-go/pkg/events"
+	"github.com/0xmhha/indexer-go/pkg/storage"
+	"go.uber.org/zap"
+)
+
+// infoStorage is a mockStorage with a chain ID and latest height
+type infoStorage struct {
+	mockStorage
+	chainID      *big.Int
+	latestHeight uint64
+	indexed      bool
+}
+
+func (m *infoStorage) GetLatestHeight(ctx context.Context) (uint64, error) {
+	if !m.indexed {
+		return 0, storage.ErrNotFound
+	}
+	return m.latestHeight, nil
+}
+
+func (m *infoStorage) GetChainID(ctx context.Context) (*big.Int, error) {
+	if m.chainID == nil {
+		return nil, storage.ErrNotFound
+	}
+	return m.chainID, nil
+}
+
+func getInfo(t *testing.T, server *Server) InfoResponse {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, "/info", nil)
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("info endpoint returned status %d, want %d", w.Code, http.StatusOK)
+	}
+	if contentType := w.Header().Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("info endpoint returned content type %q, want application/json", contentType)
+	}
+
+	var response InfoResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode info response: %v", err)
+	}
+	return response
+}
+
+func TestServerInfoEndpoint(t *testing.T) {
+	config := DefaultConfig()
+	config.EnableWebSocket = false
+	config.EnableREST = true
+	store := &infoStorage{chainID: big.NewInt(8283), latestHeight: 1500, indexed: true}
+
+	server, err := NewServerWithOptions(config, zap.NewNop(), store, &ServerOptions{
+		Info: &InfoOptions{
+			Version:                 "v1.2.3",
+			Commit:                  "abc1234",
+			BuildTime:               "2024-01-02T03:04:05Z",
+			StartHeight:             1000,
+			DisabledEventCategories: []events.SystemEventCategory{events.SystemEventCategoryVote, events.SystemEventCategoryMint},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewServerWithOptions() error = %v", err)
+	}
+
+	response := getInfo(t, server)
+
+	if response.Name != "indexer-go" || response.Version != "v1.2.3" || response.Commit != "abc1234" || response.BuildTime != "2024-01-02T03:04:05Z" {
+		t.Errorf("build info = %q %q %q %q", response.Name, response.Version, response.Commit, response.BuildTime)
+	}
+	if response.ChainID == nil || response.ChainID.Cmp(big.NewInt(8283)) != 0 {
+		t.Errorf("chain_id = %v, want 8283", response.ChainID)
+	}
+	if want := []string{"graphql", "jsonrpc", "rest", "etherscan"}; !reflect.DeepEqual(response.APIs, want) {
+		t.Errorf("apis = %v, want %v", response.APIs, want)
+	}
+	if response.Indexing == nil || response.Indexing.Earliest != 1000 || response.Indexing.Latest != 1500 {
+		t.Errorf("indexing = %+v, want earliest 1000, latest 1500", response.Indexing)
+	}
+
+	categories := make(map[events.SystemEventCategory]bool)
+	for _, category := range response.EventCategories {
+		categories[category] = true
+	}
+	if len(response.EventCategories) != len(events.SystemEventCategories())-2 {
+		t.Errorf("event_categories = %v, want all but vote and mint", response.EventCategories)
+	}
+	if categories[events.SystemEventCategoryVote] || categories[events.SystemEventCategoryMint] {
+		t.Errorf("event_categories = %v, includes a disabled category", response.EventCategories)
+	}
+	if !categories[events.SystemEventCategoryBurn] {
+		t.Errorf("event_categories = %v, missing burn", response.EventCategories)
+	}
+
+	// The latest height is read on each request
+	store.latestHeight = 1600
+	if response := getInfo(t, server); response.Indexing == nil || response.Indexing.Latest != 1600 {
+		t.Errorf("indexing = %+v, want latest 1600", response.Indexing)
+	}
+}
+
+func TestServerInfoEndpointNothingIndexed(t *testing.T) {
+	server, err := NewServer(DefaultConfig(), zap.NewNop(), &infoStorage{})
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+
+	response := getInfo(t, server)
+
+	if response.ChainID != nil {
+		t.Errorf("chain_id = %v, want none", response.ChainID)
+	}
+	if response.Indexing != nil {
+		t.Errorf("indexing = %+v, want none", response.Indexing)
+	}
+	if want := []string{"graphql", "jsonrpc", "websocket", "etherscan"}; !reflect.DeepEqual(response.APIs, want) {
+		t.Errorf("apis = %v, want %v", response.APIs, want)
+	}
+	if len(response.EventCategories) != len(events.SystemEventCategories()) {
+		t.Errorf("event_categories = %v, want all categories", response.EventCategories)
+	}
+}
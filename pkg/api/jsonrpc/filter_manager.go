@@ -2,6 +2,7 @@ package jsonrpc
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"time"
 
@@ -226,7 +227,7 @@ func (fm *FilterManager) GetLogsSinceLastPoll(ctx context.Context, store storage
 
 	// Get current block height
 	currentHeight, err := store.GetLatestHeight(ctx)
-	if err != nil && err != storage.ErrNotFound {
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
 		return nil, 0, err
 	}
 
@@ -265,7 +266,7 @@ func (fm *FilterManager) GetBlockHashesSinceLastPoll(ctx context.Context, store
 
 	// Get current block height
 	currentHeight, err := store.GetLatestHeight(ctx)
-	if err != nil && err != storage.ErrNotFound {
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
 		return nil, 0, err
 	}
 
@@ -277,14 +278,14 @@ func (fm *FilterManager) GetBlockHashesSinceLastPoll(ctx context.Context, store
 	// Get block hashes for new blocks
 	var hashes []common.Hash
 	for blockNum := filter.LastPollBlock + 1; blockNum <= currentHeight; blockNum++ {
-		block, err := store.GetBlock(ctx, blockNum)
+		header, err := getBlockHeader(ctx, store, blockNum)
 		if err != nil {
-			if err == storage.ErrNotFound {
+			if errors.Is(err, storage.ErrNotFound) {
 				continue
 			}
 			return nil, 0, err
 		}
-		hashes = append(hashes, block.Hash())
+		hashes = append(hashes, header.Hash())
 	}
 
 	return hashes, currentHeight, nil
@@ -344,3 +345,16 @@ func (fm *FilterManager) GetPendingPoolSize() int {
 	}
 	return pool.Size()
 }
+
+// getBlockHeader returns the header at height, avoiding a full block decode
+// when store supports header-only reads
+func getBlockHeader(ctx context.Context, store storage.Storage, height uint64) (*types.Header, error) {
+	if reader, ok := store.(storage.BlockHeaderReader); ok {
+		return reader.GetBlockHeader(ctx, height)
+	}
+	block, err := store.GetBlock(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	return block.Header(), nil
+}
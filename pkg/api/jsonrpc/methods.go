@@ -95,6 +95,10 @@ func (h *Handler) HandleMethod(ctx context.Context, method string, params json.R
 		return h.getBlock(ctx, params)
 	case "getBlockByHash":
 		return h.getBlockByHash(ctx, params)
+	case "getOrphanBlock":
+		return h.getOrphanBlock(ctx, params)
+	case "getOrphanBlocks":
+		return h.getOrphanBlocks(ctx, params)
 	case "getTxResult":
 		return h.getTxResult(ctx, params)
 	case "getTxReceipt":
@@ -131,6 +135,8 @@ func (h *Handler) HandleMethod(ctx context.Context, method string, params json.R
 		return h.getProposals(ctx, params)
 	case "getProposalVotes":
 		return h.getProposalVotes(ctx, params)
+	case "getProposalTally":
+		return h.getProposalTally(ctx, params)
 	case "getMintEvents":
 		return h.getMintEvents(ctx, params)
 	case "getBurnEvents":
@@ -175,6 +181,16 @@ func (h *Handler) HandleMethod(ctx context.Context, method string, params json.R
 		return h.getValidatorSigningActivity(ctx, params)
 	case "getBlockSigners":
 		return h.getBlockSigners(ctx, params)
+	// Ethereum-compatible chain identity methods
+	case "eth_chainId":
+		return h.ethChainID(ctx, params)
+	case "net_version":
+		return h.netVersion(ctx, params)
+	// Ethereum-compatible transaction lookup methods
+	case "eth_getTransactionByBlockNumberAndIndex":
+		return h.ethGetTransactionByBlockNumberAndIndex(ctx, params)
+	case "eth_getTransactionByBlockHashAndIndex":
+		return h.ethGetTransactionByBlockHashAndIndex(ctx, params)
 	// Ethereum-compatible log filtering methods
 	case "eth_getLogs":
 		return h.ethGetLogs(ctx, params)
@@ -298,7 +314,7 @@ func (h *Handler) getBlock(ctx context.Context, params json.RawMessage) (interfa
 
 	block, err := h.storage.GetBlock(ctx, blockNumber)
 	if err != nil {
-		if err == storage.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			return nil, NewError(InternalError, "block not found", nil)
 		}
 		h.logger.Error("failed to get block", zap.Uint64("number", blockNumber), zap.Error(err))
@@ -325,7 +341,7 @@ func (h *Handler) getBlockByHash(ctx context.Context, params json.RawMessage) (i
 	hash := common.HexToHash(p.Hash)
 	block, err := h.storage.GetBlockByHash(ctx, hash)
 	if err != nil {
-		if err == storage.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			return nil, NewError(InternalError, "block not found", nil)
 		}
 		h.logger.Error("failed to get block by hash", zap.String("hash", p.Hash), zap.Error(err))
@@ -352,14 +368,35 @@ func (h *Handler) getTxResult(ctx context.Context, params json.RawMessage) (inte
 	hash := common.HexToHash(p.Hash)
 	tx, location, err := h.storage.GetTransaction(ctx, hash)
 	if err != nil {
-		if err == storage.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			return nil, NewError(InternalError, "transaction not found", nil)
 		}
 		h.logger.Error("failed to get transaction", zap.String("hash", p.Hash), zap.Error(err))
 		return nil, NewError(InternalError, "failed to get transaction", err.Error())
 	}
 
-	return h.transactionToJSON(tx, location), nil
+	result := h.transactionToJSON(tx, location)
+	receipt, err := h.storage.GetReceipt(ctx, hash)
+	if err != nil {
+		if !errors.Is(err, storage.ErrNotFound) {
+			h.logger.Warn("failed to get receipt", zap.String("hash", p.Hash), zap.Error(err))
+		}
+		return result, nil
+	}
+	h.addTransactionFee(ctx, receipt, result)
+	return result, nil
+}
+
+// addTransactionFee fills in the gas used and effective gas price of a stored
+// receipt and adds them to a transaction result as effectiveGasPrice and fee
+func (h *Handler) addTransactionFee(ctx context.Context, receipt *types.Receipt, result map[string]interface{}) {
+	fee, err := storage.FillReceiptFee(ctx, h.storage, receipt)
+	if err != nil {
+		h.logger.Warn("failed to compute transaction fee", zap.String("hash", receipt.TxHash.Hex()), zap.Error(err))
+		return
+	}
+	result["effectiveGasPrice"] = fmt.Sprintf("0x%x", receipt.EffectiveGasPrice)
+	result["fee"] = fmt.Sprintf("0x%x", fee)
 }
 
 // getTxReceipt returns a transaction receipt by hash
@@ -379,14 +416,37 @@ func (h *Handler) getTxReceipt(ctx context.Context, params json.RawMessage) (int
 	hash := common.HexToHash(p.Hash)
 	receipt, err := h.storage.GetReceipt(ctx, hash)
 	if err != nil {
-		if err == storage.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			return nil, NewError(InternalError, "receipt not found", nil)
 		}
 		h.logger.Error("failed to get receipt", zap.String("hash", p.Hash), zap.Error(err))
 		return nil, NewError(InternalError, "failed to get receipt", err.Error())
 	}
 
-	return h.receiptToJSON(receipt), nil
+	if _, err := storage.FillReceiptFee(ctx, h.storage, receipt); err != nil {
+		h.logger.Warn("failed to compute transaction fee", zap.String("hash", p.Hash), zap.Error(err))
+	}
+
+	result := h.receiptToJSON(receipt)
+	h.addRevertReason(ctx, receipt, result)
+	return result, nil
+}
+
+// addRevertReason adds the indexed revert reason of a failed transaction to
+// its receipt as revertReason and revertData
+func (h *Handler) addRevertReason(ctx context.Context, receipt *types.Receipt, result map[string]interface{}) {
+	reason, err := storage.ReceiptRevertReason(ctx, h.storage, receipt)
+	if err != nil {
+		h.logger.Warn("failed to get revert reason", zap.String("hash", receipt.TxHash.Hex()), zap.Error(err))
+	}
+	if reason == nil {
+		return
+	}
+
+	result["revertReason"] = reason.Message()
+	if len(reason.Data) > 0 {
+		result["revertData"] = fmt.Sprintf("0x%x", reason.Data)
+	}
 }
 
 // blockToJSON converts a block to JSON-friendly format
@@ -452,7 +512,7 @@ func (h *Handler) blockToJSON(block *types.Block) map[string]interface{} {
 func (h *Handler) transactionToJSON(tx *types.Transaction, location *storage.TxLocation) map[string]interface{} {
 	v, r, s := tx.RawSignatureValues()
 
-	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	from, err := location.Sender(tx)
 	if err != nil {
 		h.logger.Warn("failed to get transaction sender", zap.Error(err))
 	}
@@ -516,6 +576,16 @@ func (h *Handler) transactionToJSON(tx *types.Transaction, location *storage.TxL
 		result["accessList"] = accessListJSON
 	}
 
+	// EIP-4844 blob transaction (type 0x03 = 3)
+	if tx.Type() == types.BlobTxType {
+		result["maxFeePerBlobGas"] = fmt.Sprintf("0x%x", tx.BlobGasFeeCap())
+		blobHashes := make([]interface{}, len(tx.BlobHashes()))
+		for i, hash := range tx.BlobHashes() {
+			blobHashes[i] = hash.Hex()
+		}
+		result["blobVersionedHashes"] = blobHashes
+	}
+
 	// EIP-7702 SetCode transaction (type 0x04 = 4)
 	if tx.Type() == types.SetCodeTxType {
 		authList := tx.SetCodeAuthorizations()
@@ -599,5 +669,10 @@ func (h *Handler) receiptToJSON(receipt *types.Receipt) map[string]interface{} {
 		result["contractAddress"] = receipt.ContractAddress.Hex()
 	}
 
+	// Fee is known once the effective gas price has been filled in
+	if receipt.EffectiveGasPrice != nil {
+		result["fee"] = fmt.Sprintf("0x%x", storage.TransactionFee(receipt.EffectiveGasPrice, receipt.GasUsed))
+	}
+
 	return result
 }
@@ -3,6 +3,7 @@ package jsonrpc
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	abiDecoder "github.com/0xmhha/indexer-go/pkg/abi"
@@ -92,7 +93,7 @@ func (h *Handler) getContractABI(ctx context.Context, params json.RawMessage) (i
 	// Get ABI from storage
 	abiJSON, err := h.storage.GetABI(ctx, address)
 	if err != nil {
-		if err == storage.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			return nil, NewError(InternalError, fmt.Sprintf("ABI not found for contract %s", address.Hex()), nil)
 		}
 		h.logger.Error("failed to get ABI",
@@ -0,0 +1,50 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+
+	"github.com/0xmhha/indexer-go/pkg/storage"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"go.uber.org/zap"
+)
+
+// eth_chainId returns the chain ID of the indexed chain as a hex quantity
+// https://ethereum.org/en/developers/docs/apis/json-rpc/#eth_chainid
+func (h *Handler) ethChainID(ctx context.Context, params json.RawMessage) (interface{}, *Error) {
+	chainID, rpcErr := h.storedChainID(ctx)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	return (*hexutil.Big)(chainID).String(), nil
+}
+
+// net_version returns the network ID of the indexed chain as a decimal string
+// https://ethereum.org/en/developers/docs/apis/json-rpc/#net_version
+func (h *Handler) netVersion(ctx context.Context, params json.RawMessage) (interface{}, *Error) {
+	chainID, rpcErr := h.storedChainID(ctx)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	return chainID.String(), nil
+}
+
+// storedChainID reads the chain ID persisted at startup
+func (h *Handler) storedChainID(ctx context.Context) (*big.Int, *Error) {
+	reader, ok := h.storage.(storage.ChainIDReader)
+	if !ok {
+		return nil, NewError(InternalError, "storage does not support chain ID", nil)
+	}
+
+	chainID, err := reader.GetChainID(ctx)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, NewError(InternalError, "chain ID not available", nil)
+		}
+		h.logger.Error("failed to get chain ID", zap.Error(err))
+		return nil, NewError(InternalError, "failed to get chain ID", err.Error())
+	}
+	return chainID, nil
+}
@@ -0,0 +1,81 @@
+package jsonrpc
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/0xmhha/indexer-go/pkg/storage"
+	"go.uber.org/zap"
+)
+
+// mockChainIDStorage extends mockStorage with ChainIDReader support
+type mockChainIDStorage struct {
+	*mockStorage
+	chainID *big.Int
+}
+
+func (m *mockChainIDStorage) GetChainID(ctx context.Context) (*big.Int, error) {
+	if m.chainID == nil {
+		return nil, storage.ErrNotFound
+	}
+	return m.chainID, nil
+}
+
+func TestChainIdentityMethods(t *testing.T) {
+	logger := zap.NewNop()
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		chainID     *big.Int
+		wantChainID string
+		wantVersion string
+	}{
+		{"mainnet", big.NewInt(1), "0x1", "1"},
+		{"local", big.NewInt(1337), "0x539", "1337"},
+		{"large", big.NewInt(8283), "0x205b", "8283"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &mockChainIDStorage{mockStorage: &mockStorage{}, chainID: tt.chainID}
+			server := NewServer(store, logger)
+
+			result, err := server.HandleMethodDirect(ctx, "eth_chainId", nil)
+			if err != nil {
+				t.Fatalf("eth_chainId error = %v", err)
+			}
+			if result != tt.wantChainID {
+				t.Errorf("eth_chainId = %v, want %s", result, tt.wantChainID)
+			}
+
+			result, err = server.HandleMethodDirect(ctx, "net_version", nil)
+			if err != nil {
+				t.Fatalf("net_version error = %v", err)
+			}
+			if result != tt.wantVersion {
+				t.Errorf("net_version = %v, want %s", result, tt.wantVersion)
+			}
+		})
+	}
+
+	t.Run("not stored", func(t *testing.T) {
+		store := &mockChainIDStorage{mockStorage: &mockStorage{}}
+		server := NewServer(store, logger)
+
+		for _, method := range []string{"eth_chainId", "net_version"} {
+			if _, err := server.HandleMethodDirect(ctx, method, nil); err == nil {
+				t.Errorf("%s: expected error when chain ID is not stored", method)
+			}
+		}
+	})
+
+	t.Run("unsupported storage", func(t *testing.T) {
+		server := NewServer(&mockStorage{}, logger)
+
+		if _, err := server.HandleMethodDirect(ctx, "eth_chainId", nil); err == nil {
+			t.Error("expected error when storage does not support chain ID")
+		}
+	})
+}
@@ -42,8 +42,13 @@ func (m *mockSystemContractStorage) GetTotalSupply(ctx context.Context) (*big.In
 	return big.NewInt(0), nil
 }
 
-func (m *mockSystemContractStorage) GetActiveMinters(ctx context.Context) ([]common.Address, error) {
-	return m.minters, nil
+func (m *mockSystemContractStorage) GetActiveMinters(ctx context.Context) ([]*storage.ActiveMinter, error) {
+	minters := make([]*storage.ActiveMinter, 0, len(m.minters))
+	for _, addr := range m.minters {
+		allowance, _ := m.GetMinterAllowance(ctx, addr)
+		minters = append(minters, &storage.ActiveMinter{Address: addr, Allowance: allowance})
+	}
+	return minters, nil
 }
 
 func (m *mockSystemContractStorage) GetMinterAllowance(ctx context.Context, minter common.Address) (*big.Int, error) {
@@ -82,6 +87,21 @@ func (m *mockSystemContractStorage) GetProposalVotes(ctx context.Context, contra
 	return []*storage.ProposalVote{}, nil
 }
 
+func (m *mockSystemContractStorage) GetProposalTally(ctx context.Context, contract common.Address, proposalId *big.Int) (*storage.ProposalTally, error) {
+	tally := &storage.ProposalTally{Contract: contract, ProposalID: proposalId, Voters: uint32(len(m.votes))}
+	for _, vote := range m.votes {
+		if vote.Approval {
+			tally.Approvals++
+		} else {
+			tally.Rejections++
+		}
+	}
+	if m.proposalByID != nil {
+		tally.RequiredApprovals = m.proposalByID.RequiredApprovals
+	}
+	return tally, nil
+}
+
 func (m *mockSystemContractStorage) GetMintEvents(ctx context.Context, fromBlock, toBlock uint64, minter common.Address, limit, offset int) ([]*storage.MintEvent, error) {
 	if m.mintEvents != nil {
 		return m.mintEvents, nil
@@ -428,6 +448,48 @@ func TestSystemContractMethods(t *testing.T) {
 		assert.Equal(t, InvalidParams, err.Code)
 	})
 
+	t.Run("GetProposalTally", func(t *testing.T) {
+		store.votes = []*storage.ProposalVote{
+			{Voter: common.HexToAddress("0xvoter1"), Approval: true},
+			{Voter: common.HexToAddress("0xvoter2"), Approval: true},
+			{Voter: common.HexToAddress("0xvoter3"), Approval: false},
+		}
+
+		params := json.RawMessage(`{"contract": "0xcontract", "proposalId": "1"}`)
+		result, err := server.HandleMethodDirect(ctx, "getProposalTally", params)
+		require.Nil(t, err)
+
+		m := result.(map[string]interface{})
+		assert.Equal(t, "1", m["proposalId"])
+		assert.Equal(t, uint32(2), m["approvals"])
+		assert.Equal(t, uint32(1), m["rejections"])
+		assert.Equal(t, uint32(3), m["voters"])
+	})
+
+	t.Run("GetProposalTally_GenesisWrappedStorage", func(t *testing.T) {
+		// Single-chain mode serves the API from the genesis-initializing wrapper
+		wrapped := NewServer(storage.NewGenesisInitializingStorage(store, nil, logger), logger)
+		store.votes = []*storage.ProposalVote{
+			{Voter: common.HexToAddress("0xvoter1"), Approval: true},
+			{Voter: common.HexToAddress("0xvoter2"), Approval: false},
+		}
+
+		params := json.RawMessage(`{"contract": "0xcontract", "proposalId": "1"}`)
+		result, err := wrapped.HandleMethodDirect(ctx, "getProposalTally", params)
+		require.Nil(t, err)
+
+		m := result.(map[string]interface{})
+		assert.Equal(t, uint32(1), m["approvals"])
+		assert.Equal(t, uint32(1), m["rejections"])
+		assert.Equal(t, uint32(2), m["voters"])
+	})
+
+	t.Run("GetProposalTally_MissingParams", func(t *testing.T) {
+		_, err := server.HandleMethodDirect(ctx, "getProposalTally", json.RawMessage(`{"contract": "0x1"}`))
+		require.NotNil(t, err)
+		assert.Equal(t, InvalidParams, err.Code)
+	})
+
 	t.Run("GetMintEvents", func(t *testing.T) {
 		store.mintEvents = []*storage.MintEvent{
 			{
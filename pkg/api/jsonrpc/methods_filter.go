@@ -3,6 +3,7 @@ package jsonrpc
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/0xmhha/indexer-go/pkg/storage"
@@ -44,7 +45,7 @@ func (h *Handler) ethNewFilter(ctx context.Context, params json.RawMessage) (int
 	} else {
 		// Default to latest
 		latestHeight, err := h.storage.GetLatestHeight(ctx)
-		if err != nil && err != storage.ErrNotFound {
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
 			h.logger.Error("failed to get latest height", zap.Error(err))
 			return nil, NewError(InternalError, "failed to get latest height", err.Error())
 		}
@@ -61,7 +62,7 @@ func (h *Handler) ethNewFilter(ctx context.Context, params json.RawMessage) (int
 	} else {
 		// Default to latest
 		latestHeight, err := h.storage.GetLatestHeight(ctx)
-		if err != nil && err != storage.ErrNotFound {
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
 			h.logger.Error("failed to get latest height", zap.Error(err))
 			return nil, NewError(InternalError, "failed to get latest height", err.Error())
 		}
@@ -121,7 +122,7 @@ func (h *Handler) ethNewFilter(ctx context.Context, params json.RawMessage) (int
 
 	// Get current block height for the filter's starting point
 	latestHeight, err := h.storage.GetLatestHeight(ctx)
-	if err != nil && err != storage.ErrNotFound {
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
 		h.logger.Error("failed to get latest height", zap.Error(err))
 		return nil, NewError(InternalError, "failed to get latest height", err.Error())
 	}
@@ -152,7 +153,7 @@ func (h *Handler) ethNewFilter(ctx context.Context, params json.RawMessage) (int
 func (h *Handler) ethNewBlockFilter(ctx context.Context, params json.RawMessage) (interface{}, *Error) {
 	// Get current block height
 	latestHeight, err := h.storage.GetLatestHeight(ctx)
-	if err != nil && err != storage.ErrNotFound {
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
 		h.logger.Error("failed to get latest height", zap.Error(err))
 		return nil, NewError(InternalError, "failed to get latest height", err.Error())
 	}
@@ -173,7 +174,7 @@ func (h *Handler) ethNewBlockFilter(ctx context.Context, params json.RawMessage)
 func (h *Handler) ethNewPendingTransactionFilter(ctx context.Context, params json.RawMessage) (interface{}, *Error) {
 	// Get current block height
 	latestHeight, err := h.storage.GetLatestHeight(ctx)
-	if err != nil && err != storage.ErrNotFound {
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
 		h.logger.Error("failed to get latest height", zap.Error(err))
 		return nil, NewError(InternalError, "failed to get latest height", err.Error())
 	}
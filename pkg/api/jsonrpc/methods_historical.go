@@ -3,6 +3,7 @@ package jsonrpc
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
 	"strconv"
@@ -20,12 +21,18 @@ func (h *Handler) getBlocksByTimeRange(ctx context.Context, params json.RawMessa
 		ToTime   interface{} `json:"toTime"`
 		Limit    *int        `json:"limit,omitempty"`
 		Offset   *int        `json:"offset,omitempty"`
+		Order    string      `json:"order,omitempty"`
 	}
 
 	if err := json.Unmarshal(params, &p); err != nil {
 		return nil, NewError(InvalidParams, "invalid params", err.Error())
 	}
 
+	order, err := storage.ParseSortOrder(p.Order)
+	if err != nil {
+		return nil, NewError(InvalidParams, "invalid order", err.Error())
+	}
+
 	if p.FromTime == nil {
 		return nil, NewError(InvalidParams, "missing required parameter: fromTime", nil)
 	}
@@ -82,7 +89,7 @@ func (h *Handler) getBlocksByTimeRange(ctx context.Context, params json.RawMessa
 		return nil, NewError(InternalError, "storage does not support historical queries", nil)
 	}
 
-	blocks, err := histStorage.GetBlocksByTimeRange(ctx, fromTime, toTime, limit, offset)
+	blocks, err := histStorage.GetBlocksByTimeRange(ctx, fromTime, toTime, limit, offset, order)
 	if err != nil {
 		h.logger.Error("failed to get blocks by time range",
 			zap.Uint64("fromTime", fromTime),
@@ -144,7 +151,7 @@ func (h *Handler) getBlockByTimestamp(ctx context.Context, params json.RawMessag
 
 	block, err := histStorage.GetBlockByTimestamp(ctx, timestamp)
 	if err != nil {
-		if err == storage.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			return nil, NewError(InternalError, "block not found", nil)
 		}
 		h.logger.Error("failed to get block by timestamp",
@@ -216,6 +223,7 @@ func (h *Handler) getTransactionsByAddressFiltered(ctx context.Context, params j
 	for i, txr := range txsWithReceipts {
 		txJSON := h.transactionToJSON(txr.Transaction, txr.Location)
 		if txr.Receipt != nil {
+			h.addTransactionFee(ctx, txr.Receipt, txJSON)
 			txJSON["receipt"] = h.receiptToJSON(txr.Receipt)
 		}
 		nodes[i] = txJSON
@@ -341,6 +349,10 @@ func (h *Handler) getBalanceHistory(ctx context.Context, params json.RawMessage)
 		return nil, NewError(InvalidParams, "toBlock must be a string or number", nil)
 	}
 
+	if fromBlock > toBlock {
+		return nil, NewError(InvalidParams, "fromBlock cannot be greater than toBlock", nil)
+	}
+
 	// Parse pagination
 	limit := constants.DefaultPaginationLimit
 	offset := 0
@@ -524,6 +536,33 @@ func parseTransactionFilter(filter map[string]interface{}) (*storage.Transaction
 		}
 	}
 
+	// Parse optional transactionTypes (EIP-2718 type bytes)
+	if typesVal, ok := filter["transactionTypes"]; ok {
+		list, ok := typesVal.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("transactionTypes must be an array")
+		}
+		for _, item := range list {
+			var txType uint64
+			switch v := item.(type) {
+			case float64:
+				if v < 0 || v > 0xff || v != float64(uint8(v)) {
+					return nil, fmt.Errorf("invalid transaction type: %v", v)
+				}
+				txType = uint64(v)
+			case string:
+				num, err := strconv.ParseUint(v, 0, 8)
+				if err != nil {
+					return nil, fmt.Errorf("invalid transaction type: %w", err)
+				}
+				txType = num
+			default:
+				return nil, fmt.Errorf("transactionTypes must contain strings or numbers")
+			}
+			result.TransactionTypes = append(result.TransactionTypes, uint8(txType))
+		}
+	}
+
 	// Parse optional successOnly
 	if successOnlyVal, ok := filter["successOnly"]; ok {
 		if b, ok := successOnlyVal.(bool); ok {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"math/big"
+	"reflect"
 	"testing"
 
 	"github.com/0xmhha/indexer-go/pkg/storage"
@@ -16,6 +17,7 @@ import (
 type mockHistoricalStorage struct {
 	*mockStorage
 	blocksByTime     []*types.Block
+	lastOrder        storage.SortOrder
 	blockByTimestamp *types.Block
 	txsWithReceipts  []*storage.TransactionWithReceipt
 	balance          *big.Int
@@ -24,7 +26,8 @@ type mockHistoricalStorage struct {
 	txCount          uint64
 }
 
-func (m *mockHistoricalStorage) GetBlocksByTimeRange(ctx context.Context, fromTime, toTime uint64, limit, offset int) ([]*types.Block, error) {
+func (m *mockHistoricalStorage) GetBlocksByTimeRange(ctx context.Context, fromTime, toTime uint64, limit, offset int, order storage.SortOrder) ([]*types.Block, error) {
+	m.lastOrder = order
 	if m.blocksByTime != nil {
 		start := offset
 		end := offset + limit
@@ -255,6 +258,33 @@ func TestHistoricalJSONRPCMethods(t *testing.T) {
 		}
 	})
 
+	t.Run("GetBlocksByTimeRange_Order", func(t *testing.T) {
+		store := &mockHistoricalStorage{
+			mockStorage:  &mockStorage{},
+			blocksByTime: []*types.Block{block1, block2},
+		}
+		server := NewServer(store, logger)
+
+		if _, err := server.HandleMethodDirect(ctx, "getBlocksByTimeRange", json.RawMessage(`{"fromTime": 1000, "toTime": 2000, "order": "desc"}`)); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if store.lastOrder != storage.SortDescending {
+			t.Errorf("order = %v, want SortDescending", store.lastOrder)
+		}
+
+		if _, err := server.HandleMethodDirect(ctx, "getBlocksByTimeRange", json.RawMessage(`{"fromTime": 1000, "toTime": 2000}`)); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if store.lastOrder != storage.SortAscending {
+			t.Errorf("default order = %v, want SortAscending", store.lastOrder)
+		}
+
+		_, err := server.HandleMethodDirect(ctx, "getBlocksByTimeRange", json.RawMessage(`{"fromTime": 1000, "toTime": 2000, "order": "sideways"}`))
+		if err == nil || err.Code != InvalidParams {
+			t.Errorf("expected InvalidParams for bad order, got %v", err)
+		}
+	})
+
 	t.Run("GetBlocksByTimeRange_InvalidParams", func(t *testing.T) {
 		store := &mockHistoricalStorage{
 			mockStorage: &mockStorage{},
@@ -680,6 +710,22 @@ func TestHistoricalJSONRPCMethods(t *testing.T) {
 		}
 	})
 
+	t.Run("GetBalanceHistory_FromAfterTo", func(t *testing.T) {
+		store := &mockHistoricalStorage{
+			mockStorage: &mockStorage{},
+		}
+
+		server := NewServer(store, logger)
+		params := json.RawMessage(`{"address": "0x456", "fromBlock": 200, "toBlock": 100}`)
+		_, err := server.HandleMethodDirect(ctx, "getBalanceHistory", params)
+		if err == nil {
+			t.Fatal("expected error for fromBlock greater than toBlock")
+		}
+		if err.Code != InvalidParams {
+			t.Errorf("expected InvalidParams, got %v", err.Code)
+		}
+	})
+
 	t.Run("GetBlockCount_Success", func(t *testing.T) {
 		store := &mockHistoricalStorage{
 			mockStorage: &mockStorage{},
@@ -825,6 +871,43 @@ func TestParseTransactionFilter(t *testing.T) {
 		}
 	})
 
+	t.Run("TransactionTypes", func(t *testing.T) {
+		filter := map[string]interface{}{
+			"fromBlock":        float64(0),
+			"toBlock":          float64(100),
+			"transactionTypes": []interface{}{float64(0), "0x2", float64(3)},
+		}
+
+		result, err := parseTransactionFilter(filter)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		want := []uint8{0, 2, 3}
+		if !reflect.DeepEqual(result.TransactionTypes, want) {
+			t.Errorf("expected TransactionTypes %v, got %v", want, result.TransactionTypes)
+		}
+	})
+
+	t.Run("InvalidTransactionTypes", func(t *testing.T) {
+		for _, txTypes := range []interface{}{
+			float64(2),
+			[]interface{}{float64(256)},
+			[]interface{}{float64(1.5)},
+			[]interface{}{"0x100"},
+			[]interface{}{true},
+		} {
+			filter := map[string]interface{}{
+				"fromBlock":        float64(0),
+				"toBlock":          float64(100),
+				"transactionTypes": txTypes,
+			}
+
+			if _, err := parseTransactionFilter(filter); err == nil {
+				t.Errorf("expected error for transactionTypes %v", txTypes)
+			}
+		}
+	})
+
 	t.Run("MissingFromBlock", func(t *testing.T) {
 		filter := map[string]interface{}{
 			"toBlock": float64(100),
@@ -3,6 +3,7 @@ package jsonrpc
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/0xmhha/indexer-go/pkg/storage"
@@ -46,7 +47,7 @@ func (h *Handler) ethGetLogs(ctx context.Context, params json.RawMessage) (inter
 		blockHash := common.HexToHash(*filterParam.BlockHash)
 		block, err := h.storage.GetBlockByHash(ctx, blockHash)
 		if err != nil {
-			if err == storage.ErrNotFound {
+			if errors.Is(err, storage.ErrNotFound) {
 				return nil, NewError(InternalError, "block not found", nil)
 			}
 			h.logger.Error("failed to get block by hash", zap.String("hash", *filterParam.BlockHash), zap.Error(err))
@@ -77,7 +78,7 @@ func (h *Handler) ethGetLogs(ctx context.Context, params json.RawMessage) (inter
 		} else {
 			// Default to latest
 			latestHeight, err := h.storage.GetLatestHeight(ctx)
-			if err != nil && err != storage.ErrNotFound {
+			if err != nil && !errors.Is(err, storage.ErrNotFound) {
 				h.logger.Error("failed to get latest height", zap.Error(err))
 				return nil, NewError(InternalError, "failed to get latest height", err.Error())
 			}
@@ -168,14 +169,14 @@ func (h *Handler) parseBlockNumber(blockParam interface{}) (uint64, error) {
 			return 0, nil
 		case "latest":
 			latestHeight, err := h.storage.GetLatestHeight(context.Background())
-			if err != nil && err != storage.ErrNotFound {
+			if err != nil && !errors.Is(err, storage.ErrNotFound) {
 				return 0, fmt.Errorf("failed to get latest height: %w", err)
 			}
 			return latestHeight, nil
 		case "pending":
 			// For now, treat pending as latest
 			latestHeight, err := h.storage.GetLatestHeight(context.Background())
-			if err != nil && err != storage.ErrNotFound {
+			if err != nil && !errors.Is(err, storage.ErrNotFound) {
 				return 0, fmt.Errorf("failed to get latest height: %w", err)
 			}
 			return latestHeight, nil
@@ -0,0 +1,106 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/0xmhha/indexer-go/internal/constants"
+	"github.com/0xmhha/indexer-go/pkg/storage"
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+)
+
+// getOrphanBlock returns a block removed by a reorg rollback by hash
+func (h *Handler) getOrphanBlock(ctx context.Context, params json.RawMessage) (interface{}, *Error) {
+	var p struct {
+		Hash string `json:"hash"`
+	}
+
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, NewError(InvalidParams, "invalid params", err.Error())
+	}
+
+	if p.Hash == "" {
+		return nil, NewError(InvalidParams, "missing required parameter: hash", nil)
+	}
+
+	orphanReader, ok := h.storage.(storage.OrphanBlockReader)
+	if !ok {
+		return nil, NewError(InternalError, "storage does not support orphan blocks", nil)
+	}
+
+	orphan, err := orphanReader.GetOrphanBlock(ctx, common.HexToHash(p.Hash))
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, NewError(InternalError, "orphan block not found", nil)
+		}
+		h.logger.Error("failed to get orphan block", zap.String("hash", p.Hash), zap.Error(err))
+		return nil, NewError(InternalError, "failed to get orphan block", err.Error())
+	}
+
+	return h.orphanBlockToJSON(orphan), nil
+}
+
+// getOrphanBlocks returns the blocks removed by reorg rollbacks, ordered by
+// block number
+func (h *Handler) getOrphanBlocks(ctx context.Context, params json.RawMessage) (interface{}, *Error) {
+	var p struct {
+		Limit  *int `json:"limit,omitempty"`
+		Offset *int `json:"offset,omitempty"`
+	}
+
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, NewError(InvalidParams, "invalid params", err.Error())
+		}
+	}
+
+	limit := constants.DefaultPaginationLimit
+	offset := 0
+	if p.Limit != nil && *p.Limit > 0 {
+		limit = *p.Limit
+		if limit > constants.DefaultMaxPaginationLimit {
+			limit = constants.DefaultMaxPaginationLimit
+		}
+	}
+	if p.Offset != nil && *p.Offset >= 0 {
+		offset = *p.Offset
+	}
+
+	orphanReader, ok := h.storage.(storage.OrphanBlockReader)
+	if !ok {
+		return nil, NewError(InternalError, "storage does not support orphan blocks", nil)
+	}
+
+	orphans, err := orphanReader.GetOrphanBlocks(ctx, limit, offset)
+	if err != nil {
+		h.logger.Error("failed to get orphan blocks", zap.Error(err))
+		return nil, NewError(InternalError, "failed to get orphan blocks", err.Error())
+	}
+
+	nodes := make([]interface{}, len(orphans))
+	for i, orphan := range orphans {
+		nodes[i] = h.orphanBlockToJSON(orphan)
+	}
+
+	return map[string]interface{}{
+		"nodes":      nodes,
+		"totalCount": len(orphans),
+		"pageInfo": map[string]interface{}{
+			"hasNextPage":     len(orphans) == limit,
+			"hasPreviousPage": offset > 0,
+		},
+	}, nil
+}
+
+// orphanBlockToJSON converts an orphan block to JSON, adding the height it
+// was rolled back to and when
+func (h *Handler) orphanBlockToJSON(orphan *storage.OrphanBlock) map[string]interface{} {
+	result := h.blockToJSON(orphan.Block)
+	result["forkHeight"] = fmt.Sprintf("0x%x", orphan.ForkHeight)
+	result["orphanedAt"] = orphan.OrphanedAt.Format(time.RFC3339)
+	return result
+}
@@ -0,0 +1,101 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xmhha/indexer-go/pkg/storage"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+)
+
+// mockOrphanStorage extends mockStorage with OrphanBlockReader support
+type mockOrphanStorage struct {
+	*mockStorage
+	orphans []*storage.OrphanBlock
+}
+
+func (m *mockOrphanStorage) GetOrphanBlock(ctx context.Context, hash common.Hash) (*storage.OrphanBlock, error) {
+	for _, orphan := range m.orphans {
+		if orphan.Block.Hash() == hash {
+			return orphan, nil
+		}
+	}
+	return nil, storage.ErrNotFound
+}
+
+func (m *mockOrphanStorage) GetOrphanBlocks(ctx context.Context, limit, offset int) ([]*storage.OrphanBlock, error) {
+	if offset >= len(m.orphans) {
+		return []*storage.OrphanBlock{}, nil
+	}
+	orphans := m.orphans[offset:]
+	if limit > 0 && len(orphans) > limit {
+		orphans = orphans[:limit]
+	}
+	return orphans, nil
+}
+
+func TestOrphanBlockMethods(t *testing.T) {
+	logger := zap.NewNop()
+	ctx := context.Background()
+	orphanedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	store := &mockOrphanStorage{mockStorage: &mockStorage{}}
+	for height := uint64(3); height <= 4; height++ {
+		block := types.NewBlockWithHeader(&types.Header{Number: new(big.Int).SetUint64(height), Difficulty: big.NewInt(1)})
+		store.orphans = append(store.orphans, &storage.OrphanBlock{Block: block, ForkHeight: 2, OrphanedAt: orphanedAt})
+	}
+	server := NewServer(store, logger)
+
+	t.Run("getOrphanBlock", func(t *testing.T) {
+		hash := store.orphans[1].Block.Hash()
+		params, _ := json.Marshal(map[string]interface{}{"hash": hash.Hex()})
+		result, err := server.HandleMethodDirect(ctx, "getOrphanBlock", params)
+		if err != nil {
+			t.Fatalf("getOrphanBlock error = %v", err)
+		}
+		block := result.(map[string]interface{})
+		if block["hash"] != hash.Hex() || block["number"] != "0x4" {
+			t.Errorf("getOrphanBlock = block %v %v, want 0x4 %s", block["number"], block["hash"], hash.Hex())
+		}
+		if block["forkHeight"] != "0x2" || block["orphanedAt"] != "2024-01-02T03:04:05Z" {
+			t.Errorf("getOrphanBlock fork fields = %v, %v", block["forkHeight"], block["orphanedAt"])
+		}
+
+		params, _ = json.Marshal(map[string]interface{}{"hash": common.Hash{}.Hex()})
+		if _, err := server.HandleMethodDirect(ctx, "getOrphanBlock", params); err == nil {
+			t.Error("expected error for unknown orphan block")
+		}
+	})
+
+	t.Run("getOrphanBlocks", func(t *testing.T) {
+		result, err := server.HandleMethodDirect(ctx, "getOrphanBlocks", nil)
+		if err != nil {
+			t.Fatalf("getOrphanBlocks error = %v", err)
+		}
+		if nodes := result.(map[string]interface{})["nodes"].([]interface{}); len(nodes) != 2 {
+			t.Errorf("getOrphanBlocks returned %d blocks, want 2", len(nodes))
+		}
+
+		params, _ := json.Marshal(map[string]interface{}{"limit": 1, "offset": 1})
+		result, err = server.HandleMethodDirect(ctx, "getOrphanBlocks", params)
+		if err != nil {
+			t.Fatalf("getOrphanBlocks page error = %v", err)
+		}
+		nodes := result.(map[string]interface{})["nodes"].([]interface{})
+		if len(nodes) != 1 || nodes[0].(map[string]interface{})["number"] != "0x4" {
+			t.Errorf("getOrphanBlocks page = %v, want block 0x4", nodes)
+		}
+	})
+
+	t.Run("unsupported storage", func(t *testing.T) {
+		server := NewServer(&mockStorage{}, logger)
+		if _, err := server.HandleMethodDirect(ctx, "getOrphanBlocks", nil); err == nil {
+			t.Error("expected error when storage does not support orphan blocks")
+		}
+	})
+}
@@ -44,15 +44,9 @@ func (h *Handler) getActiveMinters(ctx context.Context, params json.RawMessage)
 
 	result := make([]map[string]interface{}, 0, len(minters))
 	for _, minter := range minters {
-		allowance, err := reader.GetMinterAllowance(ctx, minter)
-		if err != nil {
-			h.logger.Warn("failed to get minter allowance", zap.String("minter", minter.Hex()), zap.Error(err))
-			continue
-		}
-
 		result = append(result, map[string]interface{}{
-			"address":   minter.Hex(),
-			"allowance": allowance.String(),
+			"address":   minter.Address.Hex(),
+			"allowance": minter.Allowance.String(),
 			"isActive":  true,
 		})
 	}
@@ -297,6 +291,54 @@ func (h *Handler) getProposalVotes(ctx context.Context, params json.RawMessage)
 	}, nil
 }
 
+// getProposalTally returns the aggregated vote counts of a proposal
+func (h *Handler) getProposalTally(ctx context.Context, params json.RawMessage) (interface{}, *Error) {
+	var p struct {
+		Contract   string `json:"contract"`
+		ProposalID string `json:"proposalId"`
+	}
+
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, NewError(InvalidParams, "invalid params", err.Error())
+	}
+
+	if p.Contract == "" {
+		return nil, NewError(InvalidParams, "missing required parameter: contract", nil)
+	}
+	if p.ProposalID == "" {
+		return nil, NewError(InvalidParams, "missing required parameter: proposalId", nil)
+	}
+
+	reader, ok := h.storage.(storage.ProposalTallyReader)
+	if !ok {
+		return nil, NewError(InternalError, "proposal tally reader not available", nil)
+	}
+
+	contract := common.HexToAddress(p.Contract)
+	proposalID, ok := new(big.Int).SetString(p.ProposalID, 10)
+	if !ok {
+		return nil, NewError(InvalidParams, "invalid proposal ID format", nil)
+	}
+
+	tally, err := reader.GetProposalTally(ctx, contract, proposalID)
+	if err != nil {
+		h.logger.Error("failed to get proposal tally",
+			zap.String("contract", p.Contract),
+			zap.String("proposalId", p.ProposalID),
+			zap.Error(err))
+		return nil, NewError(InternalError, "failed to get proposal tally", err.Error())
+	}
+
+	return map[string]interface{}{
+		"contract":          tally.Contract.Hex(),
+		"proposalId":        tally.ProposalID.String(),
+		"approvals":         tally.Approvals,
+		"rejections":        tally.Rejections,
+		"voters":            tally.Voters,
+		"requiredApprovals": tally.RequiredApprovals,
+	}, nil
+}
+
 // getMintEvents returns mint events with filtering
 func (h *Handler) getMintEvents(ctx context.Context, params json.RawMessage) (interface{}, *Error) {
 	var p struct {
@@ -0,0 +1,112 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/0xmhha/indexer-go/pkg/storage"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+)
+
+// eth_getTransactionByBlockNumberAndIndex returns the transaction at the given
+// position of a block, or null if the block or index does not exist
+// https://ethereum.org/en/developers/docs/apis/json-rpc/#eth_gettransactionbyblocknumberandindex
+func (h *Handler) ethGetTransactionByBlockNumberAndIndex(ctx context.Context, params json.RawMessage) (interface{}, *Error) {
+	blockParam, index, rpcErr := parsePositionalTxParams(params)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	height, err := h.parseBlockNumber(blockParam)
+	if err != nil {
+		return nil, NewError(InvalidParams, "invalid block number", err.Error())
+	}
+
+	block, err := h.storage.GetBlock(ctx, height)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, nil
+		}
+		h.logger.Error("failed to get block", zap.Uint64("number", height), zap.Error(err))
+		return nil, NewError(InternalError, "failed to get block", err.Error())
+	}
+
+	return h.blockTransactionToJSON(block, index), nil
+}
+
+// eth_getTransactionByBlockHashAndIndex returns the transaction at the given
+// position of a block, or null if the block or index does not exist
+// https://ethereum.org/en/developers/docs/apis/json-rpc/#eth_gettransactionbyblockhashandindex
+func (h *Handler) ethGetTransactionByBlockHashAndIndex(ctx context.Context, params json.RawMessage) (interface{}, *Error) {
+	blockParam, index, rpcErr := parsePositionalTxParams(params)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	hashStr, ok := blockParam.(string)
+	if !ok {
+		return nil, NewError(InvalidParams, "block hash must be a string", nil)
+	}
+
+	block, err := h.storage.GetBlockByHash(ctx, common.HexToHash(hashStr))
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, nil
+		}
+		h.logger.Error("failed to get block by hash", zap.String("hash", hashStr), zap.Error(err))
+		return nil, NewError(InternalError, "failed to get block", err.Error())
+	}
+
+	return h.blockTransactionToJSON(block, index), nil
+}
+
+// parsePositionalTxParams parses the [block, index] params shared by the
+// positional transaction lookups
+func parsePositionalTxParams(params json.RawMessage) (interface{}, uint64, *Error) {
+	var p []interface{}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, 0, NewError(InvalidParams, "invalid params", err.Error())
+	}
+	if len(p) < 2 {
+		return nil, 0, NewError(InvalidParams, "missing required parameters: block and transaction index", nil)
+	}
+
+	var index uint64
+	switch v := p[1].(type) {
+	case string:
+		parsed, err := hexutil.DecodeUint64(v)
+		if err != nil {
+			return nil, 0, NewError(InvalidParams, "invalid transaction index", err.Error())
+		}
+		index = parsed
+	case float64:
+		if v < 0 {
+			return nil, 0, NewError(InvalidParams, "invalid transaction index", fmt.Sprintf("negative index %v", v))
+		}
+		index = uint64(v)
+	default:
+		return nil, 0, NewError(InvalidParams, "transaction index must be a hex string or number", nil)
+	}
+
+	return p[0], index, nil
+}
+
+// blockTransactionToJSON returns the JSON form of the transaction at index in
+// block, or nil if the index is out of range
+func (h *Handler) blockTransactionToJSON(block *types.Block, index uint64) interface{} {
+	txs := block.Transactions()
+	if index >= uint64(len(txs)) {
+		return nil
+	}
+
+	return h.transactionToJSON(txs[index], &storage.TxLocation{
+		BlockHeight: block.NumberU64(),
+		TxIndex:     index,
+		BlockHash:   block.Hash(),
+	})
+}
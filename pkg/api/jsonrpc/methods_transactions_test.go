@@ -0,0 +1,246 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/0xmhha/indexer-go/pkg/storage"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"go.uber.org/zap"
+)
+
+// newPositionalTxStore returns a store holding block 5 with two signed transactions
+func newPositionalTxStore(t *testing.T) (*mockStorage, *types.Block, common.Address) {
+	t.Helper()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	signer := types.LatestSignerForChainID(big.NewInt(1337))
+
+	var txs []*types.Transaction
+	for nonce := uint64(0); nonce < 2; nonce++ {
+		tx, err := types.SignTx(types.NewTransaction(nonce, common.HexToAddress("0x456"), big.NewInt(1), 21000, big.NewInt(1), nil), signer, key)
+		if err != nil {
+			t.Fatalf("SignTx() error = %v", err)
+		}
+		txs = append(txs, tx)
+	}
+
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(5)}).WithBody(types.Body{Transactions: txs})
+	store := &mockStorage{
+		latestHeight: 5,
+		blocks:       map[uint64]*types.Block{5: block},
+		blocksByHash: map[common.Hash]*types.Block{block.Hash(): block},
+	}
+	return store, block, crypto.PubkeyToAddress(key.PublicKey)
+}
+
+func TestEthGetTransactionByBlockAndIndex(t *testing.T) {
+	store, block, sender := newPositionalTxStore(t)
+	server := NewServer(store, zap.NewNop())
+	ctx := context.Background()
+
+	call := func(t *testing.T, method string, params ...interface{}) interface{} {
+		t.Helper()
+		raw, err := json.Marshal(params)
+		if err != nil {
+			t.Fatalf("Marshal() error = %v", err)
+		}
+		result, rpcErr := server.HandleMethodDirect(ctx, method, raw)
+		if rpcErr != nil {
+			t.Fatalf("%s error = %v", method, rpcErr)
+		}
+		return result
+	}
+
+	t.Run("valid indices", func(t *testing.T) {
+		for i, tx := range block.Transactions() {
+			index := "0x" + big.NewInt(int64(i)).Text(16)
+			for _, result := range []interface{}{
+				call(t, "eth_getTransactionByBlockNumberAndIndex", "0x5", index),
+				call(t, "eth_getTransactionByBlockNumberAndIndex", "latest", index),
+				call(t, "eth_getTransactionByBlockHashAndIndex", block.Hash().Hex(), index),
+			} {
+				got, ok := result.(map[string]interface{})
+				if !ok {
+					t.Fatalf("result = %v, want transaction object", result)
+				}
+				if got["hash"] != tx.Hash().Hex() {
+					t.Errorf("hash = %v, want %s", got["hash"], tx.Hash().Hex())
+				}
+				if got["transactionIndex"] != index {
+					t.Errorf("transactionIndex = %v, want %s", got["transactionIndex"], index)
+				}
+				if got["blockHash"] != block.Hash().Hex() || got["blockNumber"] != "0x5" {
+					t.Errorf("block = %v/%v, want %s/0x5", got["blockHash"], got["blockNumber"], block.Hash().Hex())
+				}
+				if got["from"] != sender.Hex() {
+					t.Errorf("from = %v, want %s", got["from"], sender.Hex())
+				}
+			}
+		}
+	})
+
+	t.Run("out of range index", func(t *testing.T) {
+		if result := call(t, "eth_getTransactionByBlockNumberAndIndex", "0x5", "0x2"); result != nil {
+			t.Errorf("by number result = %v, want null", result)
+		}
+		if result := call(t, "eth_getTransactionByBlockHashAndIndex", block.Hash().Hex(), "0x2"); result != nil {
+			t.Errorf("by hash result = %v, want null", result)
+		}
+	})
+
+	t.Run("unknown block", func(t *testing.T) {
+		if result := call(t, "eth_getTransactionByBlockNumberAndIndex", "0x63", "0x0"); result != nil {
+			t.Errorf("by number result = %v, want null", result)
+		}
+		if result := call(t, "eth_getTransactionByBlockHashAndIndex", common.HexToHash("0xdead").Hex(), "0x0"); result != nil {
+			t.Errorf("by hash result = %v, want null", result)
+		}
+	})
+
+	t.Run("invalid params", func(t *testing.T) {
+		for _, params := range []string{`["0x5"]`, `["0x5", "zz"]`, `["0x5", true]`, `{}`} {
+			if _, rpcErr := server.HandleMethodDirect(ctx, "eth_getTransactionByBlockNumberAndIndex", json.RawMessage(params)); rpcErr == nil || rpcErr.Code != InvalidParams {
+				t.Errorf("params %s: error = %v, want InvalidParams", params, rpcErr)
+			}
+		}
+		if _, rpcErr := server.HandleMethodDirect(ctx, "eth_getTransactionByBlockHashAndIndex", json.RawMessage(`[5, "0x0"]`)); rpcErr == nil || rpcErr.Code != InvalidParams {
+			t.Errorf("numeric block hash: error = %v, want InvalidParams", rpcErr)
+		}
+	})
+}
+
+// revertReasonStorage is a mockStorage holding one receipt and its revert reason
+type revertReasonStorage struct {
+	*mockStorage
+	receipt *types.Receipt
+	reason  *storage.RevertReason
+}
+
+func (m *revertReasonStorage) GetReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	if hash != m.receipt.TxHash {
+		return nil, storage.ErrNotFound
+	}
+	return m.receipt, nil
+}
+
+func (m *revertReasonStorage) GetRevertReason(ctx context.Context, txHash common.Hash) (*storage.RevertReason, error) {
+	if m.reason == nil || txHash != m.reason.TxHash {
+		return nil, storage.ErrNotFound
+	}
+	return m.reason, nil
+}
+
+func TestGetTxReceipt_RevertReason(t *testing.T) {
+	txHash := common.HexToHash("0xfa11")
+	store := &revertReasonStorage{
+		mockStorage: &mockStorage{},
+		receipt:     &types.Receipt{TxHash: txHash, Status: types.ReceiptStatusFailed, BlockNumber: big.NewInt(5), Logs: []*types.Log{}},
+		reason:      &storage.RevertReason{TxHash: txHash, Error: "execution reverted", Data: []byte{0x08, 0xc3, 0x79, 0xa0}, Reason: "insufficient balance"},
+	}
+	server := NewServer(store, zap.NewNop())
+	ctx := context.Background()
+
+	receipt := func(t *testing.T) map[string]interface{} {
+		t.Helper()
+		result, rpcErr := server.HandleMethodDirect(ctx, "getTxReceipt", json.RawMessage(`{"hash": "`+txHash.Hex()+`"}`))
+		if rpcErr != nil {
+			t.Fatalf("getTxReceipt error = %v", rpcErr)
+		}
+		got, ok := result.(map[string]interface{})
+		if !ok {
+			t.Fatalf("result = %v, want receipt object", result)
+		}
+		return got
+	}
+
+	got := receipt(t)
+	if got["status"] != "0x0" {
+		t.Errorf("status = %v, want 0x0", got["status"])
+	}
+	if got["revertReason"] != "insufficient balance" {
+		t.Errorf("revertReason = %v, want insufficient balance", got["revertReason"])
+	}
+	if got["revertData"] != "0x08c379a0" {
+		t.Errorf("revertData = %v, want 0x08c379a0", got["revertData"])
+	}
+
+	// Without an indexed reason the receipt has no revert fields
+	store.reason = nil
+	got = receipt(t)
+	if _, ok := got["revertReason"]; ok {
+		t.Errorf("revertReason = %v, want none", got["revertReason"])
+	}
+}
+
+// feeStorage is a mockStorage holding one dynamic-fee transaction, its
+// receipt, and the block it was included in
+type feeStorage struct {
+	*mockStorage
+	block   *types.Block
+	tx      *types.Transaction
+	receipt *types.Receipt
+}
+
+func (m *feeStorage) GetBlock(ctx context.Context, height uint64) (*types.Block, error) {
+	if height != m.block.NumberU64() {
+		return nil, storage.ErrNotFound
+	}
+	return m.block, nil
+}
+
+func (m *feeStorage) GetTransaction(ctx context.Context, hash common.Hash) (*types.Transaction, *storage.TxLocation, error) {
+	if hash != m.tx.Hash() {
+		return nil, nil, storage.ErrNotFound
+	}
+	return m.tx, &storage.TxLocation{BlockHeight: m.block.NumberU64(), BlockHash: m.block.Hash()}, nil
+}
+
+func (m *feeStorage) GetReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	if hash != m.receipt.TxHash {
+		return nil, storage.ErrNotFound
+	}
+	return m.receipt, nil
+}
+
+func TestGetTxResult_Fee(t *testing.T) {
+	to := common.HexToAddress("0x3333333333333333333333333333333333333333")
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   big.NewInt(1337),
+		To:        &to,
+		Gas:       21000,
+		GasFeeCap: big.NewInt(30),
+		GasTipCap: big.NewInt(5),
+	})
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(5), BaseFee: big.NewInt(10)})
+	store := &feeStorage{
+		mockStorage: &mockStorage{},
+		block:       block,
+		tx:          tx,
+		receipt:     &types.Receipt{TxHash: tx.Hash(), Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: 21000, Logs: []*types.Log{}},
+	}
+	server := NewServer(store, zap.NewNop())
+
+	result, rpcErr := server.HandleMethodDirect(context.Background(), "getTxResult", json.RawMessage(`{"hash": "`+tx.Hash().Hex()+`"}`))
+	if rpcErr != nil {
+		t.Fatalf("getTxResult error = %v", rpcErr)
+	}
+	got, ok := result.(map[string]interface{})
+	if !ok {
+		t.Fatalf("result = %v, want transaction object", result)
+	}
+	// min(30, 10 + 5) = 15 wei per gas for 21000 gas
+	if got["effectiveGasPrice"] != "0xf" {
+		t.Errorf("effectiveGasPrice = %v, want 0xf", got["effectiveGasPrice"])
+	}
+	if got["fee"] != "0x4ce78" {
+		t.Errorf("fee = %v, want 0x4ce78", got["fee"])
+	}
+}
@@ -3,9 +3,14 @@ package jsonrpc
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"time"
 
+	"github.com/0xmhha/indexer-go/internal/constants"
+	"github.com/0xmhha/indexer-go/internal/logger"
 	"github.com/0xmhha/indexer-go/pkg/notifications"
 	"github.com/0xmhha/indexer-go/pkg/storage"
 	"go.uber.org/zap"
@@ -13,18 +18,65 @@ import (
 
 // Server handles JSON-RPC HTTP requests
 type Server struct {
-	handler *Handler
-	logger  *zap.Logger
+	handler         *Handler
+	logger          *zap.Logger
+	requestTimeout  time.Duration
+	maxRequestBytes int64
+
+	// allowedMethods and deniedMethods gate dispatch; nil means no restriction
+	allowedMethods map[string]bool
+	deniedMethods  map[string]bool
 }
 
 // NewServer creates a new JSON-RPC server
 func NewServer(store storage.Storage, logger *zap.Logger) *Server {
 	return &Server{
-		handler: NewHandler(store, logger),
-		logger:  logger,
+		handler:         NewHandler(store, logger),
+		logger:          logger,
+		requestTimeout:  constants.DefaultRequestTimeout,
+		maxRequestBytes: constants.DefaultMaxRequestBytes,
+	}
+}
+
+// SetRequestLimits sets the per-request timeout and maximum body size.
+// Non-positive values leave the current limit unchanged.
+func (s *Server) SetRequestLimits(timeout time.Duration, maxBytes int64) {
+	if timeout > 0 {
+		s.requestTimeout = timeout
+	}
+	if maxBytes > 0 {
+		s.maxRequestBytes = maxBytes
 	}
 }
 
+// SetMethodFilter restricts which methods may be called. When allowed is
+// non-empty only those methods are dispatched; methods in denied are always
+// rejected, even if also allowed. Blocked methods get a MethodNotFound error.
+func (s *Server) SetMethodFilter(allowed, denied []string) {
+	s.allowedMethods = methodSet(allowed)
+	s.deniedMethods = methodSet(denied)
+}
+
+// methodAllowed reports whether method passes the configured filter
+func (s *Server) methodAllowed(method string) bool {
+	if s.deniedMethods[method] {
+		return false
+	}
+	return s.allowedMethods == nil || s.allowedMethods[method]
+}
+
+// methodSet builds a lookup set from method names, or nil if there are none
+func methodSet(methods []string) map[string]bool {
+	if len(methods) == 0 {
+		return nil
+	}
+	set := make(map[string]bool, len(methods))
+	for _, method := range methods {
+		set[method] = true
+	}
+	return set
+}
+
 // ServeHTTP implements http.Handler
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// Only accept POST requests
@@ -33,15 +85,23 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Limit request body size to prevent memory exhaustion (2MB)
-	const maxRequestBodySize = 2 << 20 // 2 MB
-	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodySize)
+	// Limit request body size to prevent memory exhaustion
+	if r.ContentLength > s.maxRequestBytes {
+		s.writeRequestTooLarge(w, r, r.ContentLength)
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, s.maxRequestBytes)
 
 	// Read request body
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			s.writeRequestTooLarge(w, r, -1)
+			return
+		}
 		s.logger.Error("failed to read request body", zap.Error(err))
-		s.writeErrorResponse(w, nil, NewError(ParseError, "request body too large or unreadable", err.Error()))
+		s.writeErrorResponse(w, r, nil, NewError(ParseError, "request body unreadable", err.Error()))
 		return
 	}
 	defer r.Body.Close()
@@ -70,29 +130,30 @@ func (s *Server) handleSingleRequest(w http.ResponseWriter, r *http.Request, bod
 	var req Request
 	if err := json.Unmarshal(body, &req); err != nil {
 		s.logger.Error("failed to parse request", zap.Error(err))
-		s.writeErrorResponse(w, nil, NewError(ParseError, "parse error", err.Error()))
+		s.writeErrorResponse(w, r, nil, NewError(ParseError, "parse error", err.Error()))
 		return
 	}
 
 	// Validate JSON-RPC version
 	if req.JSONRPC != "2.0" {
-		s.writeErrorResponse(w, req.ID, NewError(InvalidRequest, "invalid jsonrpc version", nil))
+		s.writeErrorResponse(w, r, req.ID, NewError(InvalidRequest, "invalid jsonrpc version", nil))
 		return
 	}
 
 	// Validate method
 	if req.Method == "" {
-		s.writeErrorResponse(w, req.ID, NewError(InvalidRequest, "missing method", nil))
+		s.writeErrorResponse(w, r, req.ID, NewError(InvalidRequest, "missing method", nil))
 		return
 	}
 
 	// Execute method
-	ctx := r.Context()
-	result, rpcErr := s.handler.HandleMethod(ctx, req.Method, req.Params)
+	ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout)
+	defer cancel()
+	result, rpcErr := s.callMethod(ctx, req.Method, req.Params)
 
 	// Write response
 	if rpcErr != nil {
-		s.writeErrorResponse(w, req.ID, rpcErr)
+		s.writeErrorResponse(w, r, req.ID, rpcErr)
 	} else {
 		s.writeSuccessResponse(w, req.ID, result)
 	}
@@ -103,13 +164,13 @@ func (s *Server) handleBatchRequest(w http.ResponseWriter, r *http.Request, body
 	var batch BatchRequest
 	if err := json.Unmarshal(body, &batch); err != nil {
 		s.logger.Error("failed to parse batch request", zap.Error(err))
-		s.writeErrorResponse(w, nil, NewError(ParseError, "parse error", err.Error()))
+		s.writeErrorResponse(w, r, nil, NewError(ParseError, "parse error", err.Error()))
 		return
 	}
 
 	// Empty batch is invalid
 	if len(batch) == 0 {
-		s.writeErrorResponse(w, nil, NewError(InvalidRequest, "empty batch", nil))
+		s.writeErrorResponse(w, r, nil, NewError(InvalidRequest, "empty batch", nil))
 		return
 	}
 
@@ -119,31 +180,33 @@ func (s *Server) handleBatchRequest(w http.ResponseWriter, r *http.Request, body
 		s.logger.Warn("batch request too large",
 			zap.Int("batch_size", len(batch)),
 			zap.Int("max_batch_size", maxBatchSize))
-		s.writeErrorResponse(w, nil, NewError(InvalidRequest, "batch too large (max 100 requests)", nil))
+		s.writeErrorResponse(w, r, nil, NewError(InvalidRequest, "batch too large (max 100 requests)", nil))
 		return
 	}
 
-	ctx := r.Context()
+	// The timeout covers the whole batch, not each entry
+	ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout)
+	defer cancel()
 	responses := make(BatchResponse, 0, len(batch))
 
 	for _, req := range batch {
 		// Validate JSON-RPC version
 		if req.JSONRPC != "2.0" {
-			responses = append(responses, *NewErrorResponse(req.ID, NewError(InvalidRequest, "invalid jsonrpc version", nil)))
+			responses = append(responses, *NewErrorResponse(req.ID, withRequestID(r, NewError(InvalidRequest, "invalid jsonrpc version", nil))))
 			continue
 		}
 
 		// Validate method
 		if req.Method == "" {
-			responses = append(responses, *NewErrorResponse(req.ID, NewError(InvalidRequest, "missing method", nil)))
+			responses = append(responses, *NewErrorResponse(req.ID, withRequestID(r, NewError(InvalidRequest, "missing method", nil))))
 			continue
 		}
 
 		// Execute method
-		result, rpcErr := s.handler.HandleMethod(ctx, req.Method, req.Params)
+		result, rpcErr := s.callMethod(ctx, req.Method, req.Params)
 
 		if rpcErr != nil {
-			responses = append(responses, *NewErrorResponse(req.ID, rpcErr))
+			responses = append(responses, *NewErrorResponse(req.ID, withRequestID(r, rpcErr)))
 		} else {
 			responses = append(responses, *NewResponse(req.ID, result))
 		}
@@ -157,6 +220,68 @@ func (s *Server) handleBatchRequest(w http.ResponseWriter, r *http.Request, body
 	}
 }
 
+// callMethod runs a method and gives up once ctx expires, so a method that
+// ignores cancellation cannot hold the request past the timeout
+func (s *Server) callMethod(ctx context.Context, method string, params json.RawMessage) (interface{}, *Error) {
+	if !s.methodAllowed(method) {
+		return nil, NewError(MethodNotFound, fmt.Sprintf("method '%s' not allowed", method), nil)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, s.timeoutError(method)
+	}
+
+	type callResult struct {
+		result interface{}
+		err    *Error
+	}
+	done := make(chan callResult, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				s.logger.Error("panic in JSON-RPC method",
+					zap.String("method", method),
+					zap.Any("error", p))
+				done <- callResult{err: NewError(InternalError, "internal error", nil)}
+			}
+		}()
+		result, rpcErr := s.handler.HandleMethod(ctx, method, params)
+		done <- callResult{result: result, err: rpcErr}
+	}()
+
+	select {
+	case res := <-done:
+		return res.result, res.err
+	case <-ctx.Done():
+		return nil, s.timeoutError(method)
+	}
+}
+
+// timeoutError logs and builds the error returned for a timed out method
+func (s *Server) timeoutError(method string) *Error {
+	s.logger.Warn("JSON-RPC request timed out",
+		zap.String("method", method),
+		zap.Duration("timeout", s.requestTimeout))
+	return NewError(RequestTimeout, fmt.Sprintf("request timed out after %s", s.requestTimeout), nil)
+}
+
+// writeRequestTooLarge writes a 413 response carrying a JSON-RPC error.
+// size is the declared body size, or -1 when it is unknown.
+func (s *Server) writeRequestTooLarge(w http.ResponseWriter, r *http.Request, size int64) {
+	fields := []zap.Field{zap.Int64("max_request_bytes", s.maxRequestBytes)}
+	if size >= 0 {
+		fields = append(fields, zap.Int64("content_length", size))
+	}
+	s.logger.Warn("JSON-RPC request body too large", fields...)
+
+	msg := fmt.Sprintf("request body too large (max %d bytes)", s.maxRequestBytes)
+	resp := NewErrorResponse(nil, withRequestID(r, NewError(InvalidRequest, msg, nil)))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusRequestEntityTooLarge)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		s.logger.Error("failed to encode error response", zap.Error(err))
+	}
+}
+
 // writeSuccessResponse writes a successful JSON-RPC response
 func (s *Server) writeSuccessResponse(w http.ResponseWriter, id interface{}, result interface{}) {
 	resp := NewResponse(id, result)
@@ -168,8 +293,8 @@ func (s *Server) writeSuccessResponse(w http.ResponseWriter, id interface{}, res
 }
 
 // writeErrorResponse writes an error JSON-RPC response
-func (s *Server) writeErrorResponse(w http.ResponseWriter, id interface{}, rpcErr *Error) {
-	resp := NewErrorResponse(id, rpcErr)
+func (s *Server) writeErrorResponse(w http.ResponseWriter, r *http.Request, id interface{}, rpcErr *Error) {
+	resp := NewErrorResponse(id, withRequestID(r, rpcErr))
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK) // JSON-RPC errors still return 200 OK
 	if err := json.NewEncoder(w).Encode(resp); err != nil {
@@ -177,6 +302,34 @@ func (s *Server) writeErrorResponse(w http.ResponseWriter, id interface{}, rpcEr
 	}
 }
 
+// withRequestID returns a copy of rpcErr whose data carries the request ID
+// of r under "request_id". Errors with data that is not an object, such as
+// revert data, are returned unchanged so clients can still decode it.
+func withRequestID(r *http.Request, rpcErr *Error) *Error {
+	id, ok := logger.RequestIDFromContext(r.Context())
+	if !ok || rpcErr == nil {
+		return rpcErr
+	}
+
+	var data map[string]interface{}
+	switch d := rpcErr.Data.(type) {
+	case nil:
+		data = make(map[string]interface{}, 1)
+	case map[string]interface{}:
+		data = make(map[string]interface{}, len(d)+1)
+		for k, v := range d {
+			data[k] = v
+		}
+	default:
+		return rpcErr
+	}
+	data["request_id"] = id
+
+	tagged := *rpcErr
+	tagged.Data = data
+	return &tagged
+}
+
 // HandleMethodDirect directly handles a method call (for testing)
 func (s *Server) HandleMethodDirect(ctx context.Context, method string, params json.RawMessage) (interface{}, *Error) {
 	return s.handler.HandleMethod(ctx, method, params)
@@ -10,11 +10,14 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/0xmhha/indexer-go/internal/logger"
 	"github.com/0xmhha/indexer-go/pkg/storage"
 	"github.com/0xmhha/indexer-go/pkg/userop"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/holiman/uint256"
 	"go.uber.org/zap"
 )
 
@@ -50,6 +53,10 @@ func (m *mockStorage) GetTransactions(ctx context.Context, hashes []common.Hash)
 	return nil, nil, nil
 }
 
+func (m *mockStorage) GetTransactionsByBlock(ctx context.Context, height uint64) ([]*types.Transaction, []*storage.TxLocation, error) {
+	return nil, nil, nil
+}
+
 func (m *mockStorage) GetReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
 	return nil, storage.ErrNotFound
 }
@@ -271,8 +278,8 @@ func (m *mockStorage) GetBurnEvents(ctx context.Context, fromBlock, toBlock uint
 	return []*storage.BurnEvent{}, nil
 }
 
-func (m *mockStorage) GetActiveMinters(ctx context.Context) ([]common.Address, error) {
-	return []common.Address{}, nil
+func (m *mockStorage) GetActiveMinters(ctx context.Context) ([]*storage.ActiveMinter, error) {
+	return []*storage.ActiveMinter{}, nil
 }
 
 func (m *mockStorage) GetMinterAllowance(ctx context.Context, minter common.Address) (*big.Int, error) {
@@ -393,7 +400,7 @@ func (m *mockStorage) UpdateValidatorSigningStats(ctx context.Context, blockNumb
 }
 
 // HistoricalReader methods
-func (m *mockStorage) GetBlocksByTimeRange(ctx context.Context, fromTime, toTime uint64, limit, offset int) ([]*types.Block, error) {
+func (m *mockStorage) GetBlocksByTimeRange(ctx context.Context, fromTime, toTime uint64, limit, offset int, order storage.SortOrder) ([]*types.Block, error) {
 	return []*types.Block{}, nil
 }
 
@@ -720,6 +727,10 @@ func (m *mockStorageWithData) GetTransactions(ctx context.Context, hashes []comm
 	return txs, locs, nil
 }
 
+func (m *mockStorageWithData) GetTransactionsByBlock(ctx context.Context, height uint64) ([]*types.Transaction, []*storage.TxLocation, error) {
+	return nil, nil, storage.ErrNotFound
+}
+
 func (m *mockStorageWithData) GetReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
 	if receipt, ok := m.receipts[hash]; ok {
 		return receipt, nil
@@ -852,6 +863,84 @@ func TestJSONRPCServer(t *testing.T) {
 	})
 }
 
+func TestJSONRPCServer_RequestIDInErrors(t *testing.T) {
+	store := &mockStorage{
+		latestHeight: 100,
+		blocks:       make(map[uint64]*types.Block),
+		blocksByHash: make(map[common.Hash]*types.Block),
+	}
+	server := NewServer(store, zap.NewNop())
+
+	serve := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/rpc", bytes.NewBufferString(body))
+		req = req.WithContext(logger.WithRequestID(req.Context(), "trace-123"))
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		return w
+	}
+	requestID := func(t *testing.T, rpcErr *Error) interface{} {
+		t.Helper()
+		if rpcErr == nil {
+			t.Fatal("expected an error")
+		}
+		data, ok := rpcErr.Data.(map[string]interface{})
+		if !ok {
+			t.Fatalf("error data = %#v, want an object", rpcErr.Data)
+		}
+		return data["request_id"]
+	}
+
+	t.Run("single", func(t *testing.T) {
+		w := serve(`{"jsonrpc":"2.0","method":"invalidMethod","params":{},"id":1}`)
+		var resp Response
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if id := requestID(t, resp.Error); id != "trace-123" {
+			t.Errorf("request_id = %v, want trace-123", id)
+		}
+	})
+
+	t.Run("batch", func(t *testing.T) {
+		w := serve(`[
+			{"jsonrpc":"2.0","method":"getLatestHeight","params":{},"id":1},
+			{"jsonrpc":"1.0","method":"getLatestHeight","params":{},"id":2}
+		]`)
+		var batch BatchResponse
+		if err := json.NewDecoder(w.Body).Decode(&batch); err != nil {
+			t.Fatalf("failed to decode batch response: %v", err)
+		}
+		if len(batch) != 2 || batch[0].Error != nil {
+			t.Fatalf("unexpected batch response: %+v", batch)
+		}
+		if id := requestID(t, batch[1].Error); id != "trace-123" {
+			t.Errorf("request_id = %v, want trace-123", id)
+		}
+	})
+
+	t.Run("keeps non-object data", func(t *testing.T) {
+		rpcErr := NewError(InternalError, "execution reverted", "0x08c379a0")
+		req := httptest.NewRequest(http.MethodPost, "/rpc", nil)
+		req = req.WithContext(logger.WithRequestID(req.Context(), "trace-123"))
+		if got := withRequestID(req, rpcErr); got != rpcErr {
+			t.Errorf("withRequestID() = %+v, want the error unchanged", got)
+		}
+	})
+
+	t.Run("does not modify shared errors", func(t *testing.T) {
+		rpcErr := NewError(InternalError, "internal error", map[string]interface{}{"reason": "x"})
+		req := httptest.NewRequest(http.MethodPost, "/rpc", nil)
+		req = req.WithContext(logger.WithRequestID(req.Context(), "trace-123"))
+		got := withRequestID(req, rpcErr)
+		if id := requestID(t, got); id != "trace-123" {
+			t.Errorf("request_id = %v, want trace-123", id)
+		}
+		if _, ok := rpcErr.Data.(map[string]interface{})["request_id"]; ok {
+			t.Error("withRequestID() modified the original error data")
+		}
+	})
+}
+
 func TestJSONRPCTypes(t *testing.T) {
 	t.Run("NewError", func(t *testing.T) {
 		err := NewError(InvalidParams, "test error", "test data")
@@ -1340,6 +1429,10 @@ func (m *mockStorageWithErrors) GetTransactions(ctx context.Context, hashes []co
 	return nil, nil, storage.ErrNotFound
 }
 
+func (m *mockStorageWithErrors) GetTransactionsByBlock(ctx context.Context, height uint64) ([]*types.Transaction, []*storage.TxLocation, error) {
+	return nil, nil, storage.ErrNotFound
+}
+
 func (m *mockStorageWithErrors) GetReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
 	return nil, storage.ErrNotFound
 }
@@ -1522,7 +1615,7 @@ func (m *mockStorageWithErrors) GetBurnEvents(ctx context.Context, fromBlock, to
 	return nil, storage.ErrNotFound
 }
 
-func (m *mockStorageWithErrors) GetActiveMinters(ctx context.Context) ([]common.Address, error) {
+func (m *mockStorageWithErrors) GetActiveMinters(ctx context.Context) ([]*storage.ActiveMinter, error) {
 	return nil, storage.ErrNotFound
 }
 
@@ -1644,7 +1737,7 @@ func (m *mockStorageWithErrors) UpdateValidatorSigningStats(ctx context.Context,
 }
 
 // HistoricalReader methods for mockStorageWithErrors
-func (m *mockStorageWithErrors) GetBlocksByTimeRange(ctx context.Context, fromTime, toTime uint64, limit, offset int) ([]*types.Block, error) {
+func (m *mockStorageWithErrors) GetBlocksByTimeRange(ctx context.Context, fromTime, toTime uint64, limit, offset int, order storage.SortOrder) ([]*types.Block, error) {
 	return nil, storage.ErrNotFound
 }
 
@@ -1991,6 +2084,10 @@ func (m *mockStorageWithNonNotFoundErrors) GetTransactions(ctx context.Context,
 	return nil, nil, fmt.Errorf("database connection failed")
 }
 
+func (m *mockStorageWithNonNotFoundErrors) GetTransactionsByBlock(ctx context.Context, height uint64) ([]*types.Transaction, []*storage.TxLocation, error) {
+	return nil, nil, fmt.Errorf("database connection failed")
+}
+
 func (m *mockStorageWithNonNotFoundErrors) GetReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
 	return nil, fmt.Errorf("database connection failed")
 }
@@ -2173,7 +2270,7 @@ func (m *mockStorageWithNonNotFoundErrors) GetBurnEvents(ctx context.Context, fr
 	return nil, fmt.Errorf("database connection failed")
 }
 
-func (m *mockStorageWithNonNotFoundErrors) GetActiveMinters(ctx context.Context) ([]common.Address, error) {
+func (m *mockStorageWithNonNotFoundErrors) GetActiveMinters(ctx context.Context) ([]*storage.ActiveMinter, error) {
 	return nil, fmt.Errorf("database connection failed")
 }
 
@@ -2295,7 +2392,7 @@ func (m *mockStorageWithNonNotFoundErrors) UpdateValidatorSigningStats(ctx conte
 }
 
 // HistoricalReader methods for mockStorageWithNonNotFoundErrors
-func (m *mockStorageWithNonNotFoundErrors) GetBlocksByTimeRange(ctx context.Context, fromTime, toTime uint64, limit, offset int) ([]*types.Block, error) {
+func (m *mockStorageWithNonNotFoundErrors) GetBlocksByTimeRange(ctx context.Context, fromTime, toTime uint64, limit, offset int, order storage.SortOrder) ([]*types.Block, error) {
 	return nil, fmt.Errorf("database connection failed")
 }
 
@@ -2853,6 +2950,52 @@ func TestTransactionJSONConversion(t *testing.T) {
 		}
 	})
 
+	t.Run("BlobTransaction", func(t *testing.T) {
+		blobHash := common.HexToHash("0x01aa")
+		blobTx := types.NewTx(&types.BlobTx{
+			ChainID:    uint256.NewInt(1),
+			GasTipCap:  uint256.NewInt(1),
+			GasFeeCap:  uint256.NewInt(2),
+			Gas:        21000,
+			To:         common.HexToAddress("0x456"),
+			Value:      uint256.NewInt(1),
+			BlobFeeCap: uint256.NewInt(0x10),
+			BlobHashes: []common.Hash{blobHash},
+		})
+
+		store := &mockStorageWithData{
+			mockStorage: &mockStorage{
+				latestHeight: 1,
+				blocks:       make(map[uint64]*types.Block),
+				blocksByHash: make(map[common.Hash]*types.Block),
+			},
+			transactions: map[common.Hash]*types.Transaction{blobTx.Hash(): blobTx},
+			receipts:     map[common.Hash]*types.Receipt{},
+		}
+
+		server := NewServer(store, logger)
+		params := json.RawMessage(`{"hash": "` + blobTx.Hash().Hex() + `"}`)
+		result, err := server.HandleMethodDirect(ctx, "getTxResult", params)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		resultMap, ok := result.(map[string]interface{})
+		if !ok {
+			t.Fatal("expected result to be map")
+		}
+		if resultMap["type"] != "0x3" {
+			t.Errorf("expected type 0x3, got %v", resultMap["type"])
+		}
+		if resultMap["maxFeePerBlobGas"] != "0x10" {
+			t.Errorf("expected maxFeePerBlobGas 0x10, got %v", resultMap["maxFeePerBlobGas"])
+		}
+		hashes, ok := resultMap["blobVersionedHashes"].([]interface{})
+		if !ok || len(hashes) != 1 || hashes[0] != blobHash.Hex() {
+			t.Errorf("expected blobVersionedHashes [%s], got %v", blobHash.Hex(), resultMap["blobVersionedHashes"])
+		}
+	})
+
 	// Test receipt with contract address
 	t.Run("ReceiptWithContractAddress", func(t *testing.T) {
 		contractTx := types.NewContractCreation(0, common.Big1, 21000, common.Big1, []byte{0x60})
@@ -2954,3 +3097,230 @@ func TestJSONRPCErrorLogging(t *testing.T) {
 		}
 	})
 }
+
+// slowStorage blocks GetLatestHeight until release is closed, ignoring the
+// request context like a misbehaving backend would
+type slowStorage struct {
+	*mockStorage
+	release chan struct{}
+}
+
+func (s *slowStorage) GetLatestHeight(ctx context.Context) (uint64, error) {
+	<-s.release
+	return s.latestHeight, nil
+}
+
+func TestJSONRPCServer_RequestLimits(t *testing.T) {
+	logger := zap.NewNop()
+	store := &mockStorage{
+		latestHeight: 100,
+		blocks:       make(map[uint64]*types.Block),
+		blocksByHash: make(map[common.Hash]*types.Block),
+	}
+
+	t.Run("OversizedBody", func(t *testing.T) {
+		server := NewServer(store, logger)
+		server.SetRequestLimits(0, 64)
+
+		reqBody := `{"jsonrpc":"2.0","method":"getLatestHeight","params":{"padding":"` + strings.Repeat("x", 128) + `"},"id":1}`
+		req := httptest.NewRequest(http.MethodPost, "/rpc", bytes.NewBufferString(reqBody))
+		w := httptest.NewRecorder()
+
+		server.ServeHTTP(w, req)
+
+		if w.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("expected status 413, got %v", w.Code)
+		}
+
+		var resp Response
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if resp.Error == nil || resp.Error.Code != InvalidRequest {
+			t.Fatalf("expected InvalidRequest error, got %+v", resp.Error)
+		}
+		if !strings.Contains(resp.Error.Message, "max 64 bytes") {
+			t.Errorf("expected error message to mention the limit, got %q", resp.Error.Message)
+		}
+	})
+
+	t.Run("OversizedBodyWithoutContentLength", func(t *testing.T) {
+		server := NewServer(store, logger)
+		server.SetRequestLimits(0, 64)
+
+		reqBody := `[` + strings.Repeat(`{"jsonrpc":"2.0","method":"getLatestHeight","id":1},`, 10) + `]`
+		req := httptest.NewRequest(http.MethodPost, "/rpc", bytes.NewBufferString(reqBody))
+		req.ContentLength = -1
+		w := httptest.NewRecorder()
+
+		server.ServeHTTP(w, req)
+
+		if w.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("expected status 413, got %v", w.Code)
+		}
+	})
+
+	t.Run("BodyWithinLimit", func(t *testing.T) {
+		server := NewServer(store, logger)
+		server.SetRequestLimits(0, 1024)
+
+		reqBody := `{"jsonrpc":"2.0","method":"getLatestHeight","params":{},"id":1}`
+		req := httptest.NewRequest(http.MethodPost, "/rpc", bytes.NewBufferString(reqBody))
+		w := httptest.NewRecorder()
+
+		server.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("expected status OK, got %v", w.Code)
+		}
+	})
+
+	t.Run("SlowMethodTimesOut", func(t *testing.T) {
+		slow := &slowStorage{mockStorage: store, release: make(chan struct{})}
+		defer close(slow.release)
+
+		server := NewServer(slow, logger)
+		server.SetRequestLimits(50*time.Millisecond, 0)
+
+		reqBody := `{"jsonrpc":"2.0","method":"getLatestHeight","params":{},"id":7}`
+		req := httptest.NewRequest(http.MethodPost, "/rpc", bytes.NewBufferString(reqBody))
+		w := httptest.NewRecorder()
+
+		start := time.Now()
+		server.ServeHTTP(w, req)
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Fatalf("request was not cut off by the timeout, took %v", elapsed)
+		}
+
+		var resp Response
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if resp.Error == nil || resp.Error.Code != RequestTimeout {
+			t.Fatalf("expected RequestTimeout error, got %+v", resp.Error)
+		}
+		if id, ok := resp.ID.(float64); !ok || id != 7 {
+			t.Errorf("expected response id 7, got %v", resp.ID)
+		}
+	})
+
+	t.Run("SlowBatchTimesOut", func(t *testing.T) {
+		slow := &slowStorage{mockStorage: store, release: make(chan struct{})}
+		defer close(slow.release)
+
+		server := NewServer(slow, logger)
+		server.SetRequestLimits(50*time.Millisecond, 0)
+
+		reqBody := `[{"jsonrpc":"2.0","method":"getLatestHeight","id":1},{"jsonrpc":"2.0","method":"getLatestHeight","id":2}]`
+		req := httptest.NewRequest(http.MethodPost, "/rpc", bytes.NewBufferString(reqBody))
+		w := httptest.NewRecorder()
+
+		start := time.Now()
+		server.ServeHTTP(w, req)
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Fatalf("batch was not cut off by the timeout, took %v", elapsed)
+		}
+
+		var resp BatchResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if len(resp) != 2 {
+			t.Fatalf("expected 2 responses, got %d", len(resp))
+		}
+		for _, r := range resp {
+			if r.Error == nil || r.Error.Code != RequestTimeout {
+				t.Errorf("expected RequestTimeout error for id %v, got %+v", r.ID, r.Error)
+			}
+		}
+	})
+}
+
+func TestJSONRPCServer_MethodFilter(t *testing.T) {
+	logger := zap.NewNop()
+	store := &mockStorage{
+		latestHeight: 100,
+		blocks:       make(map[uint64]*types.Block),
+		blocksByHash: make(map[common.Hash]*types.Block),
+	}
+
+	call := func(t *testing.T, server *Server, reqBody string) []byte {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/rpc", bytes.NewBufferString(reqBody))
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status OK, got %v", w.Code)
+		}
+		return w.Body.Bytes()
+	}
+	single := func(t *testing.T, server *Server, method string) *Response {
+		t.Helper()
+		var resp Response
+		body := call(t, server, `{"jsonrpc":"2.0","method":"`+method+`","params":{},"id":1}`)
+		if err := json.Unmarshal(body, &resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return &resp
+	}
+
+	t.Run("DeniedMethodRejected", func(t *testing.T) {
+		server := NewServer(store, logger)
+		server.SetMethodFilter(nil, []string{"getLatestHeight"})
+
+		resp := single(t, server, "getLatestHeight")
+		if resp.Error == nil || resp.Error.Code != MethodNotFound {
+			t.Fatalf("expected MethodNotFound error, got %+v", resp.Error)
+		}
+		if !strings.Contains(resp.Error.Message, "not allowed") {
+			t.Errorf("expected error message to say the method is not allowed, got %q", resp.Error.Message)
+		}
+		if resp := single(t, server, "eth_newBlockFilter"); resp.Error != nil {
+			t.Errorf("expected eth_newBlockFilter to pass the denylist, got %+v", resp.Error)
+		}
+	})
+
+	t.Run("AllowlistRejectsOtherMethods", func(t *testing.T) {
+		server := NewServer(store, logger)
+		server.SetMethodFilter([]string{"getLatestHeight"}, nil)
+
+		if resp := single(t, server, "getLatestHeight"); resp.Error != nil {
+			t.Errorf("expected allowed method to pass, got %+v", resp.Error)
+		}
+		if resp := single(t, server, "eth_newBlockFilter"); resp.Error == nil || resp.Error.Code != MethodNotFound {
+			t.Errorf("expected MethodNotFound for a method outside the allowlist, got %+v", resp.Error)
+		}
+	})
+
+	t.Run("DenylistOverridesAllowlist", func(t *testing.T) {
+		server := NewServer(store, logger)
+		server.SetMethodFilter([]string{"getLatestHeight", "eth_newBlockFilter"}, []string{"eth_newBlockFilter"})
+
+		if resp := single(t, server, "eth_newBlockFilter"); resp.Error == nil || resp.Error.Code != MethodNotFound {
+			t.Errorf("expected MethodNotFound for a denied method, got %+v", resp.Error)
+		}
+	})
+
+	t.Run("BatchRejectsOnlyDeniedEntries", func(t *testing.T) {
+		server := NewServer(store, logger)
+		server.SetMethodFilter(nil, []string{"eth_newBlockFilter"})
+
+		body := call(t, server, `[{"jsonrpc":"2.0","method":"getLatestHeight","id":1},{"jsonrpc":"2.0","method":"eth_newBlockFilter","id":2},{"jsonrpc":"2.0","method":"getLatestHeight","id":3}]`)
+		var responses BatchResponse
+		if err := json.Unmarshal(body, &responses); err != nil {
+			t.Fatalf("failed to decode batch response: %v", err)
+		}
+		if len(responses) != 3 {
+			t.Fatalf("expected 3 responses, got %d", len(responses))
+		}
+		for _, r := range responses {
+			denied := r.ID.(float64) == 2
+			switch {
+			case denied && (r.Error == nil || r.Error.Code != MethodNotFound):
+				t.Errorf("expected MethodNotFound for denied entry, got %+v", r.Error)
+			case !denied && r.Error != nil:
+				t.Errorf("expected entry %v to succeed, got %+v", r.ID, r.Error)
+			}
+		}
+	})
+}
@@ -36,6 +36,7 @@ const (
 	InvalidParams  = -32602
 	InternalError  = -32603
 	FilterNotFound = -32000 // Custom error code for filter not found
+	RequestTimeout = -32001 // Custom error code for requests exceeding the server timeout
 )
 
 // NewError creates a new JSON-RPC error
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// MaxRequestBytes returns a middleware that rejects request bodies larger than
+// maxBytes with 413 Request Entity Too Large. Bodies without a Content-Length
+// are read up to the limit so chunked uploads are caught as well.
+func MaxRequestBytes(maxBytes int64) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > maxBytes {
+				writeRequestTooLarge(w, maxBytes)
+				return
+			}
+
+			if r.Body != nil && r.Body != http.NoBody {
+				body, err := io.ReadAll(io.LimitReader(r.Body, maxBytes+1))
+				_ = r.Body.Close()
+				if err != nil {
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(http.StatusBadRequest)
+					_, _ = w.Write([]byte(`{"error":"bad request","message":"failed to read request body"}`))
+					return
+				}
+				if int64(len(body)) > maxBytes {
+					writeRequestTooLarge(w, maxBytes)
+					return
+				}
+				r.Body = io.NopCloser(bytes.NewReader(body))
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// writeRequestTooLarge writes a 413 response for a body over maxBytes
+func writeRequestTooLarge(w http.ResponseWriter, maxBytes int64) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusRequestEntityTooLarge)
+	_, _ = fmt.Fprintf(w, `{"error":"request too large","message":"request body exceeds %d bytes"}`, maxBytes)
+}
+
+// MaxConcurrent returns a middleware that serves at most limit requests at a
+// time and rejects the overflow with 503 Service Unavailable instead of
+// queueing it. Requests to exemptPaths (e.g. health checks) are never limited.
+func MaxConcurrent(limit int, exemptPaths map[string]bool) func(next http.Handler) http.Handler {
+	sem := make(chan struct{}, limit)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if exemptPaths[r.URL.Path] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			default:
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusServiceUnavailable)
+				_, _ = fmt.Fprintf(w, `{"error":"server busy","message":"too many concurrent connections (max %d)"}`, limit)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Timeout returns a middleware that cancels the request context after timeout
+// and responds with 503 Service Unavailable if the handler has not finished by then
+func Timeout(timeout time.Duration) func(next http.Handler) http.Handler {
+	msg := fmt.Sprintf(`{"error":"request timeout","message":"request exceeded %s"}`, timeout)
+	return func(next http.Handler) http.Handler {
+		h := http.TimeoutHandler(next, timeout, msg)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Overwritten by the handler's own headers when it completes in time
+			w.Header().Set("Content-Type", "application/json")
+			h.ServeHTTP(w, r)
+		})
+	}
+}
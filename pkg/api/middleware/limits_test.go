@@ -0,0 +1,180 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMaxRequestBytes(t *testing.T) {
+	var gotBody string
+	handler := MaxRequestBytes(16)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name          string
+		body          string
+		contentLength int64
+		wantStatus    int
+	}{
+		{"within limit", "small body", 10, http.StatusOK},
+		{"exactly at limit", strings.Repeat("a", 16), 16, http.StatusOK},
+		{"declared too large", strings.Repeat("a", 32), 32, http.StatusRequestEntityTooLarge},
+		{"chunked too large", strings.Repeat("a", 32), -1, http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotBody = ""
+			req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(tt.body))
+			req.ContentLength = tt.contentLength
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			if tt.wantStatus == http.StatusOK && gotBody != tt.body {
+				t.Errorf("handler received %q, want %q", gotBody, tt.body)
+			}
+			if tt.wantStatus == http.StatusRequestEntityTooLarge && gotBody != "" {
+				t.Error("handler should not run for an oversized body")
+			}
+		})
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	slow := Timeout(50 * time.Millisecond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+	w := httptest.NewRecorder()
+
+	start := time.Now()
+	slow.ServeHTTP(w, req)
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("request was not cut off by the timeout, took %v", elapsed)
+	}
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected JSON content type, got %q", ct)
+	}
+	if !strings.Contains(w.Body.String(), "request timeout") {
+		t.Errorf("unexpected body: %s", w.Body.String())
+	}
+
+	fast := Timeout(time.Second)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte("ok"))
+	}))
+	w = httptest.NewRecorder()
+	fast.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/graphql", nil))
+
+	if w.Code != http.StatusOK || w.Body.String() != "ok" {
+		t.Errorf("expected fast handler response, got %d %q", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "text/plain" {
+		t.Errorf("expected handler content type to win, got %q", ct)
+	}
+}
+
+func TestMaxConcurrent(t *testing.T) {
+	const limit = 2
+	started := make(chan struct{}, limit)
+	release := make(chan struct{})
+
+	handler := MaxConcurrent(limit, map[string]bool{"/health": true})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			started <- struct{}{}
+			<-release
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	// Occupy every slot with a request that blocks until released
+	var wg sync.WaitGroup
+	held := make([]int, limit)
+	for i := 0; i < limit; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			resp, err := http.Get(server.URL + "/slow")
+			if err != nil {
+				t.Errorf("held request %d failed: %v", i, err)
+				return
+			}
+			defer resp.Body.Close()
+			held[i] = resp.StatusCode
+		}(i)
+	}
+	for i := 0; i < limit; i++ {
+		select {
+		case <-started:
+		case <-time.After(2 * time.Second):
+			close(release)
+			t.Fatal("held requests did not start")
+		}
+	}
+
+	resp, err := http.Get(server.URL + "/slow")
+	if err != nil {
+		t.Fatalf("overflow request failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected overflow status 503, got %d", resp.StatusCode)
+	}
+	if !strings.Contains(string(body), "too many concurrent connections") {
+		t.Errorf("unexpected overflow body: %s", body)
+	}
+
+	resp, err = http.Get(server.URL + "/health")
+	if err != nil {
+		t.Fatalf("health request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected exempt health check to succeed, got %d", resp.StatusCode)
+	}
+
+	// The held requests complete normally and free their slots
+	close(release)
+	wg.Wait()
+	for i, code := range held {
+		if code != http.StatusOK {
+			t.Errorf("held request %d: expected status 200, got %d", i, code)
+		}
+	}
+
+	resp, err = http.Get(server.URL + "/fast")
+	if err != nil {
+		t.Fatalf("request after release failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status 200 after slots were released, got %d", resp.StatusCode)
+	}
+}
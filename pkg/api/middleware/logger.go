@@ -63,6 +63,7 @@ func Logger(logger *zap.Logger) func(next http.Handler) http.Handler {
 				zap.Int("status", wrapped.status),
 				zap.Duration("duration", duration),
 				zap.String("user_agent", r.UserAgent()),
+				zap.String("request_id", RequestIDFromRequest(r)),
 			)
 		}
 
@@ -91,6 +92,7 @@ func LoggerWithLevel(logger *zap.Logger) func(next http.Handler) http.Handler {
 				zap.Int("status", status),
 				zap.Duration("duration", duration),
 				zap.String("user_agent", r.UserAgent()),
+				zap.String("request_id", RequestIDFromRequest(r)),
 			}
 
 			// Log with appropriate level based on status code
@@ -0,0 +1,245 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// MaskedValue replaces the value of a masked response field
+const MaskedValue = "[redacted]"
+
+// MaskConfig selects what ResponseMask hides from JSON responses
+type MaskConfig struct {
+	// Fields are JSON object keys masked wherever they appear in a response,
+	// matched case-insensitively (e.g. "input" hides transaction input data)
+	Fields []string
+
+	// Addresses are hex addresses masked wherever they appear as a JSON
+	// string, either as-is or left-padded to 32 bytes as in log topics.
+	// Object entries keyed by a masked address are removed.
+	Addresses []string
+
+	// Omit removes masked object fields instead of replacing their values
+	// with MaskedValue. Masked array elements are always replaced so the
+	// positions of the remaining elements do not shift.
+	Omit bool
+}
+
+// ResponseMask returns a middleware that rewrites JSON responses to hide the
+// configured fields and addresses. Other content types, such as event streams,
+// and WebSocket upgrades pass through untouched and unbuffered; WebSocket
+// servers mask each message with a ResponseMasker instead. JSON responses are
+// held until the handler returns, so flushing them has no effect, and one that
+// cannot be parsed is replaced with a 500 error rather than sent unmasked.
+func ResponseMask(cfg MaskConfig) func(next http.Handler) http.Handler {
+	m := NewResponseMasker(cfg)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			rec := &bufferedResponseWriter{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+			if rec.passthrough {
+				return
+			}
+
+			body := rec.body.Bytes()
+			if len(body) > 0 && isJSONContentType(w.Header().Get("Content-Type")) {
+				masked, err := m.MaskJSON(body)
+				if err != nil {
+					w.Header().Set("Content-Type", "application/json")
+					w.Header().Del("Content-Length")
+					w.WriteHeader(http.StatusInternalServerError)
+					_, _ = w.Write([]byte(`{"error":"internal server error","message":"failed to mask response"}`))
+					return
+				}
+				body = masked
+				w.Header().Del("Content-Length")
+			}
+
+			w.WriteHeader(rec.status)
+			_, _ = w.Write(body)
+		})
+	}
+}
+
+// bufferedResponseWriter holds the status and body of a JSON response so they
+// can be rewritten before reaching the client. Headers go straight to the
+// underlying writer, and a response whose Content-Type is not JSON when the
+// header is written is passed through instead of buffered.
+type bufferedResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	passthrough bool
+	body        bytes.Buffer
+}
+
+func (w *bufferedResponseWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.status = status
+	w.wroteHeader = true
+	if !isJSONContentType(w.Header().Get("Content-Type")) {
+		w.passthrough = true
+		w.ResponseWriter.WriteHeader(status)
+	}
+}
+
+func (w *bufferedResponseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.passthrough {
+		return w.ResponseWriter.Write(b)
+	}
+	return w.body.Write(b)
+}
+
+// Flush sends a passed-through response on to the client. A buffered JSON
+// response is only sent once masked, so flushing it does nothing.
+func (w *bufferedResponseWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if !w.passthrough {
+		return
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// isJSONContentType reports whether a Content-Type header names JSON
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// ResponseMasker hides the fields and addresses selected by a MaskConfig in
+// JSON documents
+type ResponseMasker struct {
+	fields    map[string]bool
+	addresses map[string]bool
+	omit      bool
+}
+
+// NewResponseMasker creates a masker for cfg
+func NewResponseMasker(cfg MaskConfig) *ResponseMasker {
+	m := &ResponseMasker{
+		fields:    make(map[string]bool, len(cfg.Fields)),
+		addresses: make(map[string]bool, len(cfg.Addresses)),
+		omit:      cfg.Omit,
+	}
+	for _, field := range cfg.Fields {
+		m.fields[strings.ToLower(field)] = true
+	}
+	for _, addr := range cfg.Addresses {
+		m.addresses["0x"+strings.TrimPrefix(strings.ToLower(addr), "0x")] = true
+	}
+	return m
+}
+
+// MaskJSON decodes body, masks it and encodes it again. Numbers are kept as
+// written so large integers do not lose precision.
+func (m *ResponseMasker) MaskJSON(body []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	// Keep <, > and & as written instead of escaping them for HTML
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(m.mask(v)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// MaskMessage masks a single JSON WebSocket message. Unlike MaskJSON it does
+// not end the result with a newline, which would add an empty line to frames
+// that batch several messages.
+func (m *ResponseMasker) MaskMessage(message []byte) ([]byte, error) {
+	masked, err := m.MaskJSON(message)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(masked, []byte("\n")), nil
+}
+
+// mask returns v with masked fields removed or replaced and masked address
+// strings replaced, recursing into objects and arrays in place. Entries keyed
+// by a masked address are removed, since replacing several such keys with
+// MaskedValue would collapse them into one.
+func (m *ResponseMasker) mask(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, val := range v {
+			if m.isMaskedAddress(key) {
+				delete(v, key)
+				continue
+			}
+			if m.fields[strings.ToLower(key)] || m.isMaskedAddress(val) {
+				if m.omit {
+					delete(v, key)
+				} else {
+					v[key] = MaskedValue
+				}
+				continue
+			}
+			v[key] = m.mask(val)
+		}
+		return v
+	case []interface{}:
+		for i, val := range v {
+			if m.isMaskedAddress(val) {
+				v[i] = MaskedValue
+				continue
+			}
+			v[i] = m.mask(val)
+		}
+		return v
+	default:
+		if m.isMaskedAddress(v) {
+			return MaskedValue
+		}
+		return v
+	}
+}
+
+// isMaskedAddress reports whether v is a string holding a masked address,
+// either as a 20-byte hex address or left-padded to a 32-byte word
+func (m *ResponseMasker) isMaskedAddress(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok || len(m.addresses) == 0 {
+		return false
+	}
+	switch len(s) {
+	case 42:
+		return m.addresses[strings.ToLower(s)]
+	case 66:
+		if !strings.HasPrefix(s, "0x") || strings.Trim(s[2:26], "0") != "" {
+			return false
+		}
+		return m.addresses["0x"+strings.ToLower(s[26:])]
+	}
+	return false
+}
@@ -0,0 +1,243 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+const (
+	maskedAddr = "0x1111111111111111111111111111111111111111"
+	otherAddr  = "0x2222222222222222222222222222222222222222"
+)
+
+// maskedTestResponse is a JSON-RPC style transaction response with the
+// masked address as sender, in a log topic and in an address list
+const maskedTestResponse = `{
+	"jsonrpc": "2.0",
+	"id": 1,
+	"result": {
+		"hash": "0xabc",
+		"from": "0x1111111111111111111111111111111111111111",
+		"to": "0x2222222222222222222222222222222222222222",
+		"value": 123456789012345678901234567890,
+		"Input": "0xa9059cbb",
+		"logs": [{
+			"address": "0x2222222222222222222222222222222222222222",
+			"topics": [
+				"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
+				"0x0000000000000000000000001111111111111111111111111111111111111111"
+			],
+			"data": "0x01"
+		}],
+		"accessList": ["0x1111111111111111111111111111111111111111", "0x2222222222222222222222222222222222222222"]
+	}
+}`
+
+func serveMasked(t *testing.T, cfg MaskConfig, contentType, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	handler := ResponseMask(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(body))
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/rpc", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w
+}
+
+func decodeMasked(t *testing.T, w *httptest.ResponseRecorder) map[string]interface{} {
+	t.Helper()
+	var resp map[string]interface{}
+	dec := json.NewDecoder(w.Body)
+	dec.UseNumber()
+	if err := dec.Decode(&resp); err != nil {
+		t.Fatalf("response is not valid JSON: %v", err)
+	}
+	return resp
+}
+
+func TestResponseMask_Redact(t *testing.T) {
+	w := serveMasked(t, MaskConfig{
+		Fields:    []string{"input", "data"},
+		Addresses: []string{"0x1111111111111111111111111111111111111111"},
+	}, "application/json; charset=utf-8", maskedTestResponse)
+
+	if w.Code != http.StatusCreated {
+		t.Errorf("expected status %d, got %d", http.StatusCreated, w.Code)
+	}
+	resp := decodeMasked(t, w)
+	result := resp["result"].(map[string]interface{})
+
+	want := map[string]interface{}{
+		"hash":  "0xabc",
+		"from":  MaskedValue,
+		"to":    otherAddr,
+		"value": json.Number("123456789012345678901234567890"),
+		"Input": MaskedValue,
+		"logs": []interface{}{map[string]interface{}{
+			"address": otherAddr,
+			"topics": []interface{}{
+				"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
+				MaskedValue,
+			},
+			"data": MaskedValue,
+		}},
+		"accessList": []interface{}{MaskedValue, otherAddr},
+	}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("masked result = %v, want %v", result, want)
+	}
+	if resp["jsonrpc"] != "2.0" || resp["id"] != json.Number("1") {
+		t.Errorf("envelope changed: %v", resp)
+	}
+}
+
+func TestResponseMask_Omit(t *testing.T) {
+	w := serveMasked(t, MaskConfig{
+		Fields:    []string{"input"},
+		Addresses: []string{"1111111111111111111111111111111111111111"},
+		Omit:      true,
+	}, "application/json", maskedTestResponse)
+
+	result := decodeMasked(t, w)["result"].(map[string]interface{})
+
+	for _, key := range []string{"from", "Input"} {
+		if _, ok := result[key]; ok {
+			t.Errorf("expected %q to be omitted, got %v", key, result[key])
+		}
+	}
+	for _, key := range []string{"hash", "to", "value", "logs"} {
+		if _, ok := result[key]; !ok {
+			t.Errorf("expected %q to be kept", key)
+		}
+	}
+	// Array elements are redacted rather than dropped so positions stay stable
+	wantAccessList := []interface{}{MaskedValue, otherAddr}
+	if !reflect.DeepEqual(result["accessList"], wantAccessList) {
+		t.Errorf("accessList = %v, want %v", result["accessList"], wantAccessList)
+	}
+}
+
+func TestResponseMask_Passthrough(t *testing.T) {
+	cfg := MaskConfig{Fields: []string{"input"}, Addresses: []string{maskedAddr}}
+
+	t.Run("non-JSON content type", func(t *testing.T) {
+		body := `{"input":"0xa9059cbb","from":"` + maskedAddr + `"}`
+		w := serveMasked(t, cfg, "text/plain", body)
+		if w.Body.String() != body {
+			t.Errorf("body = %q, want it unchanged", w.Body.String())
+		}
+		if w.Code != http.StatusCreated {
+			t.Errorf("expected status %d, got %d", http.StatusCreated, w.Code)
+		}
+	})
+
+	t.Run("nothing to mask", func(t *testing.T) {
+		w := serveMasked(t, cfg, "application/json", `{"hash":"0xabc","to":"`+otherAddr+`"}`)
+		want := map[string]interface{}{"hash": "0xabc", "to": otherAddr}
+		if got := decodeMasked(t, w); !reflect.DeepEqual(got, want) {
+			t.Errorf("response = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		w := serveMasked(t, cfg, "application/json", `{"input":"0xa9059cbb"`)
+		if w.Code != http.StatusInternalServerError {
+			t.Errorf("expected status 500, got %d", w.Code)
+		}
+		if body := w.Body.String(); body == `{"input":"0xa9059cbb"` {
+			t.Error("unparseable response must not be sent unmasked")
+		}
+	})
+}
+
+func TestResponseMask_KeepsHTMLCharacters(t *testing.T) {
+	body := `{"name":"<b>Tom & Jerry</b>","from":"` + maskedAddr + `"}`
+	w := serveMasked(t, MaskConfig{Addresses: []string{maskedAddr}}, "application/json", body)
+
+	want := `{"from":"` + MaskedValue + `","name":"<b>Tom & Jerry</b>"}` + "\n"
+	if got := w.Body.String(); got != want {
+		t.Errorf("body = %q, want %q", got, want)
+	}
+}
+
+func TestResponseMask_Flush(t *testing.T) {
+	cfg := MaskConfig{Addresses: []string{maskedAddr}}
+
+	t.Run("event stream", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler := ResponseMask(cfg)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			flusher, ok := rw.(http.Flusher)
+			if !ok {
+				t.Fatal("response writer does not implement http.Flusher")
+			}
+			rw.Header().Set("Content-Type", "text/event-stream")
+			_, _ = rw.Write([]byte("data: " + maskedAddr + "\n\n"))
+			flusher.Flush()
+
+			if !w.Flushed {
+				t.Error("Flush did not reach the client")
+			}
+			if got := w.Body.String(); got != "data: "+maskedAddr+"\n\n" {
+				t.Errorf("body after Flush = %q, want the first event", got)
+			}
+			_, _ = rw.Write([]byte("data: done\n\n"))
+		}))
+
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/events", nil))
+		if got, want := w.Body.String(), "data: "+maskedAddr+"\n\ndata: done\n\n"; got != want {
+			t.Errorf("body = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("JSON is held until masked", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler := ResponseMask(cfg)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			rw.Header().Set("Content-Type", "application/json")
+			_, _ = rw.Write([]byte(`{"from":"` + maskedAddr + `"}`))
+			rw.(http.Flusher).Flush()
+
+			if w.Flushed || w.Body.Len() > 0 {
+				t.Errorf("unmasked JSON was flushed: %q", w.Body.String())
+			}
+		}))
+
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/rpc", nil))
+		want := map[string]interface{}{"from": MaskedValue}
+		if got := decodeMasked(t, w); !reflect.DeepEqual(got, want) {
+			t.Errorf("response = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestResponseMask_AddressKeys(t *testing.T) {
+	body := `{"balances":{"` + maskedAddr + `":"10","` + otherAddr + `":"20"}}`
+	w := serveMasked(t, MaskConfig{Addresses: []string{maskedAddr}}, "application/json", body)
+
+	want := map[string]interface{}{"balances": map[string]interface{}{otherAddr: "20"}}
+	if got := decodeMasked(t, w); !reflect.DeepEqual(got, want) {
+		t.Errorf("response = %v, want %v", got, want)
+	}
+}
+
+func TestResponseMasker_MaskMessage(t *testing.T) {
+	m := NewResponseMasker(MaskConfig{Fields: []string{"input"}, Addresses: []string{maskedAddr}})
+
+	got, err := m.MaskMessage([]byte(`{"type":"event","payload":{"from":"` + maskedAddr + `","input":"0x01","to":"` + otherAddr + `"}}`))
+	if err != nil {
+		t.Fatalf("MaskMessage() error = %v", err)
+	}
+	want := `{"payload":{"from":"[redacted]","input":"[redacted]","to":"` + otherAddr + `"},"type":"event"}`
+	if string(got) != want {
+		t.Errorf("MaskMessage() = %s, want %s", got, want)
+	}
+
+	if _, err := m.MaskMessage([]byte(`{"type":`)); err == nil {
+		t.Error("MaskMessage() of invalid JSON succeeded, want an error")
+	}
+}
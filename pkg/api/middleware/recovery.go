@@ -19,6 +19,7 @@ func Recovery(logger *zap.Logger) func(next http.Handler) http.Handler {
 						zap.String("method", r.Method),
 						zap.String("path", r.URL.Path),
 						zap.String("remote_addr", r.RemoteAddr),
+						zap.String("request_id", w.Header().Get(RequestIDHeader)),
 						zap.Any("error", err),
 						zap.String("stack", string(debug.Stack())),
 					)
@@ -26,6 +27,12 @@ func Recovery(logger *zap.Logger) func(next http.Handler) http.Handler {
 					// Return 500 Internal Server Error
 					w.WriteHeader(http.StatusInternalServerError)
 					w.Header().Set("Content-Type", "application/json")
+					// Recovery runs ahead of RequestID, which has already
+					// stamped the ID on the response headers
+					if id := w.Header().Get(RequestIDHeader); id != "" {
+						fmt.Fprintf(w, `{"error":"Internal Server Error","request_id":%q}`, id)
+						return
+					}
 					fmt.Fprintf(w, `{"error":"Internal Server Error"}`)
 				}
 			}()
@@ -48,6 +55,7 @@ func RecoveryWithWriter(logger *zap.Logger, writeError func(w http.ResponseWrite
 						zap.String("method", r.Method),
 						zap.String("path", r.URL.Path),
 						zap.String("remote_addr", r.RemoteAddr),
+						zap.String("request_id", w.Header().Get(RequestIDHeader)),
 						zap.Any("error", err),
 						zap.String("stack", string(debug.Stack())),
 					)
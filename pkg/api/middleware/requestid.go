@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"go.uber.org/zap"
+
+	"github.com/0xmhha/indexer-go/internal/logger"
+)
+
+// RequestIDHeader is the header used to propagate the request ID.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds client-supplied request IDs so they stay usable
+// as log fields.
+const maxRequestIDLength = 128
+
+// RequestID returns a middleware that assigns each request an ID, reusing a
+// valid X-Request-ID header from the client when present. The ID is echoed in
+// the response header and stored in the request context together with a
+// logger tagged with it, so handlers and the storage or fetcher calls they
+// make log under the same ID.
+func RequestID(log *zap.Logger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(RequestIDHeader)
+			if !validRequestID(id) {
+				id = newRequestID()
+			}
+			w.Header().Set(RequestIDHeader, id)
+
+			ctx := logger.WithRequestID(r.Context(), id)
+			ctx = logger.WithLogger(ctx, log.With(zap.String("request_id", id)))
+			// Keep chi's accessor working for handlers that use it
+			ctx = context.WithValue(ctx, chimiddleware.RequestIDKey, id)
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// RequestIDFromRequest returns the ID assigned to r by RequestID, or "" when
+// the middleware did not run.
+func RequestIDFromRequest(r *http.Request) string {
+	id, _ := logger.RequestIDFromContext(r.Context())
+	return id
+}
+
+// validRequestID accepts non-empty printable ASCII IDs of bounded length
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID returns a random 128-bit hex ID
+func newRequestID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
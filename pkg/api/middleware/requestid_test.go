@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/0xmhha/indexer-go/internal/logger"
+)
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		wantSame bool
+	}{
+		{name: "propagates client ID", header: "client-id-42", wantSame: true},
+		{name: "generates when missing", header: ""},
+		{name: "replaces invalid ID", header: "bad id\n"},
+		{name: "replaces oversized ID", header: strings.Repeat("a", maxRequestIDLength+1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zap.InfoLevel)
+			component := zap.New(core).With(zap.String("component", "storage"))
+
+			var seen string
+			handler := RequestID(zap.New(core))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				seen = RequestIDFromRequest(r)
+				logger.FromContext(r.Context()).Info("handler")
+				logger.ForContext(r.Context(), component).Info("storage call")
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			if tt.header != "" {
+				req.Header.Set(RequestIDHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			id := w.Header().Get(RequestIDHeader)
+			if id == "" {
+				t.Fatal("response is missing the request ID header")
+			}
+			if tt.wantSame && id != tt.header {
+				t.Errorf("request ID = %q, want %q", id, tt.header)
+			}
+			if !tt.wantSame && id == tt.header {
+				t.Errorf("request ID %q was not replaced", id)
+			}
+			if seen != id {
+				t.Errorf("handler saw request ID %q, response has %q", seen, id)
+			}
+
+			if n := logs.FilterField(zap.String("request_id", id)).Len(); n != 2 {
+				t.Errorf("%d log entries carry the request ID, want 2", n)
+			}
+		})
+	}
+}
+
+func TestRecoveryIncludesRequestID(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	log := zap.New(core)
+
+	handler := Recovery(log)(RequestID(log)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})))
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set(RequestIDHeader, "panic-id")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+	if body := w.Body.String(); !strings.Contains(body, `"request_id":"panic-id"`) {
+		t.Errorf("error response %s is missing the request ID", body)
+	}
+	if n := logs.FilterMessage("panic recovered").FilterField(zap.String("request_id", "panic-id")).Len(); n != 1 {
+		t.Errorf("panic logged %d times with the request ID, want 1", n)
+	}
+}
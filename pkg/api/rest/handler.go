@@ -0,0 +1,579 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/0xmhha/indexer-go/internal/constants"
+	"github.com/0xmhha/indexer-go/internal/logger"
+	"github.com/0xmhha/indexer-go/pkg/storage"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// Handler serves a read-only REST API over the indexed chain data
+type Handler struct {
+	storage storage.Storage
+	logger  *zap.Logger
+}
+
+// ErrorResponse is the body returned for failed requests
+type ErrorResponse struct {
+	Error     string `json:"error"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// Block is the REST representation of a block
+type Block struct {
+	Number        hexutil.Uint64 `json:"number"`
+	Hash          common.Hash    `json:"hash"`
+	ParentHash    common.Hash    `json:"parentHash"`
+	Miner         common.Address `json:"miner"`
+	StateRoot     common.Hash    `json:"stateRoot"`
+	ReceiptsRoot  common.Hash    `json:"receiptsRoot"`
+	GasLimit      hexutil.Uint64 `json:"gasLimit"`
+	GasUsed       hexutil.Uint64 `json:"gasUsed"`
+	BaseFeePerGas *hexutil.Big   `json:"baseFeePerGas,omitempty"`
+	Timestamp     hexutil.Uint64 `json:"timestamp"`
+	ExtraData     hexutil.Bytes  `json:"extraData"`
+	Size          hexutil.Uint64 `json:"size"`
+	Transactions  []common.Hash  `json:"transactions"`
+}
+
+// Transaction is the REST representation of a transaction
+type Transaction struct {
+	Hash             common.Hash     `json:"hash"`
+	BlockHash        common.Hash     `json:"blockHash"`
+	BlockNumber      hexutil.Uint64  `json:"blockNumber"`
+	TransactionIndex hexutil.Uint64  `json:"transactionIndex"`
+	Type             hexutil.Uint64  `json:"type"`
+	From             *common.Address `json:"from,omitempty"`
+	To               *common.Address `json:"to"`
+	Nonce            hexutil.Uint64  `json:"nonce"`
+	Value            *hexutil.Big    `json:"value"`
+	Gas              hexutil.Uint64  `json:"gas"`
+	GasPrice         *hexutil.Big    `json:"gasPrice"`
+	Input            hexutil.Bytes   `json:"input"`
+	// Blob fields are set for EIP-4844 blob transactions (type 0x03)
+	MaxFeePerBlobGas    *hexutil.Big  `json:"maxFeePerBlobGas,omitempty"`
+	BlobVersionedHashes []common.Hash `json:"blobVersionedHashes,omitempty"`
+}
+
+// Receipt is a receipt in its JSON-RPC form, plus the transaction fee and
+// the indexed revert reason when the transaction failed
+type Receipt struct {
+	*types.Receipt
+	Fee          *hexutil.Big
+	RevertReason string
+	RevertData   hexutil.Bytes
+}
+
+// MarshalJSON adds fee, revertReason and revertData to the encoded receipt
+// when set
+func (r Receipt) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(r.Receipt)
+	if err != nil || (r.Fee == nil && r.RevertReason == "") {
+		return data, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	if r.Fee != nil {
+		if fields["fee"], err = json.Marshal(r.Fee); err != nil {
+			return nil, err
+		}
+	}
+	if r.RevertReason == "" {
+		return json.Marshal(fields)
+	}
+	if fields["revertReason"], err = json.Marshal(r.RevertReason); err != nil {
+		return nil, err
+	}
+	if len(r.RevertData) > 0 {
+		if fields["revertData"], err = json.Marshal(r.RevertData); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// LatestBlocks is the response for the most recent blocks, newest first
+type LatestBlocks struct {
+	Limit  int      `json:"limit"`
+	Blocks []*Block `json:"blocks"`
+}
+
+// AddressTransactions is the response for an address transaction listing
+type AddressTransactions struct {
+	Address common.Address `json:"address"`
+	Limit   int            `json:"limit"`
+	Offset  int            `json:"offset"`
+	// Total is the number of transactions of the address across all pages,
+	// omitted when the storage cannot count them
+	Total        *uint64        `json:"total,omitempty"`
+	Transactions []*Transaction `json:"transactions"`
+}
+
+// BalanceSnapshot is the REST representation of an address balance after a change
+type BalanceSnapshot struct {
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	Balance     *hexutil.Big   `json:"balance"`
+	// Delta is the signed balance change at BlockNumber
+	Delta *hexutil.Big `json:"delta"`
+	// TransactionHash is null for changes not caused by a transaction
+	TransactionHash *common.Hash `json:"transactionHash"`
+}
+
+// BalanceHistory is the response for an address balance history listing
+type BalanceHistory struct {
+	Address   common.Address     `json:"address"`
+	FromBlock uint64             `json:"fromBlock"`
+	ToBlock   uint64             `json:"toBlock"`
+	Limit     int                `json:"limit"`
+	Offset    int                `json:"offset"`
+	Snapshots []*BalanceSnapshot `json:"snapshots"`
+}
+
+// SearchResponse is the result of a search query. q may be a block number,
+// a block or transaction hash, or an address; types optionally restricts the
+// result types as a comma-separated list.
+type SearchResponse struct {
+	Query   string                 `json:"query"`
+	Results []storage.SearchResult `json:"results"`
+}
+
+// NewHandler creates a new REST API handler
+func NewHandler(store storage.Storage, logger *zap.Logger) *Handler {
+	return &Handler{
+		storage: store,
+		logger:  logger,
+	}
+}
+
+// Routes returns a router serving all REST endpoints
+func (h *Handler) Routes() http.Handler {
+	r := chi.NewRouter()
+	r.Get("/blocks/latest", h.handleGetLatestBlocks)
+	r.Get("/blocks/{number}", h.handleGetBlock)
+	r.Get("/blocks/hash/{hash}", h.handleGetBlockByHash)
+	r.Get("/tx/{hash}", h.handleGetTransaction)
+	r.Get("/tx/{hash}/receipt", h.handleGetReceipt)
+	r.Get("/address/{addr}/txs", h.handleGetAddressTransactions)
+	r.Get("/address/{addr}/balance-history", h.handleGetBalanceHistory)
+	r.Get("/search", h.handleSearch)
+	h.systemContractRoutes(r)
+	return r
+}
+
+// handleGetBlock handles GET /blocks/{number}
+func (h *Handler) handleGetBlock(w http.ResponseWriter, r *http.Request) {
+	number, err := strconv.ParseUint(chi.URLParam(r, "number"), 10, 64)
+	if err != nil {
+		h.writeError(w, r, http.StatusBadRequest, "invalid block number")
+		return
+	}
+
+	block, err := h.storage.GetBlock(r.Context(), number)
+	if err != nil {
+		h.writeStorageError(w, r, err, "block not found", zap.Uint64("number", number))
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, blockToREST(block))
+}
+
+// handleGetLatestBlocks handles GET /blocks/latest?limit
+func (h *Handler) handleGetLatestBlocks(w http.ResponseWriter, r *http.Request) {
+	limit, _, err := parsePagination(r)
+	if err != nil {
+		h.writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	blocks, err := h.latestBlocks(r.Context(), limit)
+	if err != nil {
+		h.writeStorageError(w, r, err, "blocks not found", zap.Int("limit", limit))
+		return
+	}
+
+	response := &LatestBlocks{
+		Limit:  limit,
+		Blocks: make([]*Block, len(blocks)),
+	}
+	for i, block := range blocks {
+		response.Blocks[i] = blockToREST(block)
+	}
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// latestBlocks returns up to n of the most recent blocks, newest first
+func (h *Handler) latestBlocks(ctx context.Context, n int) ([]*types.Block, error) {
+	if reader, ok := h.storage.(storage.LatestBlocksReader); ok {
+		return reader.GetLatestBlocks(ctx, n)
+	}
+	return storage.LatestBlocks(ctx, h.storage, n)
+}
+
+// handleGetBlockByHash handles GET /blocks/hash/{hash}
+func (h *Handler) handleGetBlockByHash(w http.ResponseWriter, r *http.Request) {
+	hash, ok := parseHash(chi.URLParam(r, "hash"))
+	if !ok {
+		h.writeError(w, r, http.StatusBadRequest, "invalid block hash")
+		return
+	}
+
+	block, err := h.storage.GetBlockByHash(r.Context(), hash)
+	if err != nil {
+		h.writeStorageError(w, r, err, "block not found", zap.String("hash", hash.Hex()))
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, blockToREST(block))
+}
+
+// handleGetTransaction handles GET /tx/{hash}
+func (h *Handler) handleGetTransaction(w http.ResponseWriter, r *http.Request) {
+	hash, ok := parseHash(chi.URLParam(r, "hash"))
+	if !ok {
+		h.writeError(w, r, http.StatusBadRequest, "invalid transaction hash")
+		return
+	}
+
+	tx, location, err := h.storage.GetTransaction(r.Context(), hash)
+	if err != nil {
+		h.writeStorageError(w, r, err, "transaction not found", zap.String("hash", hash.Hex()))
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, transactionToREST(tx, location))
+}
+
+// handleGetReceipt handles GET /tx/{hash}/receipt
+func (h *Handler) handleGetReceipt(w http.ResponseWriter, r *http.Request) {
+	hash, ok := parseHash(chi.URLParam(r, "hash"))
+	if !ok {
+		h.writeError(w, r, http.StatusBadRequest, "invalid transaction hash")
+		return
+	}
+
+	receipt, err := h.storage.GetReceipt(r.Context(), hash)
+	if err != nil {
+		h.writeStorageError(w, r, err, "receipt not found", zap.String("hash", hash.Hex()))
+		return
+	}
+
+	fee, err := storage.FillReceiptFee(r.Context(), h.storage, receipt)
+	if err != nil {
+		logger.ForContext(r.Context(), h.logger).Warn("failed to compute transaction fee", zap.String("hash", hash.Hex()), zap.Error(err))
+	}
+
+	response := h.receiptWithRevertReason(r.Context(), receipt)
+	response.Fee = (*hexutil.Big)(fee)
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// receiptWithRevertReason attaches the indexed revert reason of a failed transaction
+func (h *Handler) receiptWithRevertReason(ctx context.Context, receipt *types.Receipt) Receipt {
+	response := Receipt{Receipt: receipt}
+	reason, err := storage.ReceiptRevertReason(ctx, h.storage, receipt)
+	if err != nil {
+		logger.ForContext(ctx, h.logger).Warn("failed to get revert reason", zap.String("hash", receipt.TxHash.Hex()), zap.Error(err))
+	}
+	if reason == nil {
+		return response
+	}
+
+	response.RevertReason = reason.Message()
+	response.RevertData = reason.Data
+	return response
+}
+
+// handleGetAddressTransactions handles GET /address/{addr}/txs?limit&offset
+func (h *Handler) handleGetAddressTransactions(w http.ResponseWriter, r *http.Request) {
+	addrParam := chi.URLParam(r, "addr")
+	if !common.IsHexAddress(addrParam) {
+		h.writeError(w, r, http.StatusBadRequest, "invalid address")
+		return
+	}
+	addr := common.HexToAddress(addrParam)
+
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		h.writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := r.Context()
+	hashes, err := h.storage.GetTransactionsByAddress(ctx, addr, limit, offset)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		h.writeStorageError(w, r, err, "transactions not found", zap.String("address", addr.Hex()))
+		return
+	}
+
+	txs, err := h.loadTransactions(ctx, hashes)
+	if err != nil {
+		h.writeStorageError(w, r, err, "transactions not found", zap.String("address", addr.Hex()))
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, &AddressTransactions{
+		Address:      addr,
+		Limit:        limit,
+		Offset:       offset,
+		Total:        h.countAddressTransactions(ctx, addr),
+		Transactions: txs,
+	})
+}
+
+// handleGetBalanceHistory handles GET /address/{addr}/balance-history?fromBlock&toBlock&limit&offset
+func (h *Handler) handleGetBalanceHistory(w http.ResponseWriter, r *http.Request) {
+	addrParam := chi.URLParam(r, "addr")
+	if !common.IsHexAddress(addrParam) {
+		h.writeError(w, r, http.StatusBadRequest, "invalid address")
+		return
+	}
+	addr := common.HexToAddress(addrParam)
+
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		h.writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := r.Context()
+	fromBlock, err := parseBlockParam(r, "fromBlock", 0)
+	if err != nil {
+		h.writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Default the upper bound to the indexed head
+	latest, err := h.storage.GetLatestHeight(ctx)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		h.writeStorageError(w, r, err, "latest height not found")
+		return
+	}
+	toBlock, err := parseBlockParam(r, "toBlock", latest)
+	if err != nil {
+		h.writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	if fromBlock > toBlock {
+		h.writeError(w, r, http.StatusBadRequest, "fromBlock must not exceed toBlock")
+		return
+	}
+
+	snapshots, err := h.storage.GetBalanceHistory(ctx, addr, fromBlock, toBlock, limit, offset)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		h.writeStorageError(w, r, err, "balance history not found", zap.String("address", addr.Hex()))
+		return
+	}
+
+	result := make([]*BalanceSnapshot, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		entry := &BalanceSnapshot{
+			BlockNumber: hexutil.Uint64(snapshot.BlockNumber),
+			Balance:     (*hexutil.Big)(snapshot.Balance),
+			Delta:       (*hexutil.Big)(snapshot.Delta),
+		}
+		if snapshot.TxHash != (common.Hash{}) {
+			txHash := snapshot.TxHash
+			entry.TransactionHash = &txHash
+		}
+		result = append(result, entry)
+	}
+
+	h.writeJSON(w, http.StatusOK, &BalanceHistory{
+		Address:   addr,
+		FromBlock: fromBlock,
+		ToBlock:   toBlock,
+		Limit:     limit,
+		Offset:    offset,
+		Snapshots: result,
+	})
+}
+
+// handleSearch handles GET /search?q&types&limit
+func (h *Handler) handleSearch(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		h.writeError(w, r, http.StatusBadRequest, "missing query")
+		return
+	}
+
+	limit, _, err := parsePagination(r)
+	if err != nil {
+		h.writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var resultTypes []string
+	if v := r.URL.Query().Get("types"); v != "" {
+		resultTypes = strings.Split(v, ",")
+	}
+
+	results, err := h.storage.Search(r.Context(), query, resultTypes, limit)
+	if err != nil {
+		h.writeStorageError(w, r, err, "no match", zap.String("query", query))
+		return
+	}
+	if results == nil {
+		results = []storage.SearchResult{}
+	}
+
+	h.writeJSON(w, http.StatusOK, &SearchResponse{Query: query, Results: results})
+}
+
+// countAddressTransactions returns the total number of transactions of addr,
+// or nil when the storage cannot count them
+func (h *Handler) countAddressTransactions(ctx context.Context, addr common.Address) *uint64 {
+	counter, ok := h.storage.(storage.AddressTransactionCounter)
+	if !ok {
+		return nil
+	}
+	count, err := counter.CountTransactionsByAddress(ctx, addr)
+	if err != nil {
+		logger.ForContext(ctx, h.logger).Warn("failed to count address transactions", zap.String("address", addr.Hex()), zap.Error(err))
+		return nil
+	}
+	return &count
+}
+
+// loadTransactions resolves transaction hashes, skipping hashes that are no longer stored
+func (h *Handler) loadTransactions(ctx context.Context, hashes []common.Hash) ([]*Transaction, error) {
+	result := make([]*Transaction, 0, len(hashes))
+	for _, hash := range hashes {
+		tx, location, err := h.storage.GetTransaction(ctx, hash)
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		result = append(result, transactionToREST(tx, location))
+	}
+	return result, nil
+}
+
+// writeStorageError maps storage errors to HTTP responses
+func (h *Handler) writeStorageError(w http.ResponseWriter, r *http.Request, err error, notFoundMsg string, fields ...zap.Field) {
+	if errors.Is(err, storage.ErrNotFound) {
+		h.writeError(w, r, http.StatusNotFound, notFoundMsg)
+		return
+	}
+	logger.ForContext(r.Context(), h.logger).Error("REST storage query failed", append(fields, zap.Error(err))...)
+	h.writeError(w, r, http.StatusInternalServerError, "internal error")
+}
+
+// writeError writes an error response carrying the request ID, if any
+func (h *Handler) writeError(w http.ResponseWriter, r *http.Request, status int, message string) {
+	requestID, _ := logger.RequestIDFromContext(r.Context())
+	h.writeJSON(w, status, &ErrorResponse{Error: message, RequestID: requestID})
+}
+
+// writeJSON writes a JSON response with the given status code
+func (h *Handler) writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		h.logger.Warn("failed to encode REST response", zap.Error(err))
+	}
+}
+
+// parseHash parses a 0x-prefixed 32-byte hex hash
+func parseHash(s string) (common.Hash, bool) {
+	b, err := hexutil.Decode(s)
+	if err != nil || len(b) != common.HashLength {
+		return common.Hash{}, false
+	}
+	return common.BytesToHash(b), true
+}
+
+// parsePagination reads limit and offset query parameters
+func parsePagination(r *http.Request) (limit, offset int, err error) {
+	limit = constants.DefaultPaginationLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit < constants.MinPaginationLimit {
+			return 0, 0, errors.New("invalid limit")
+		}
+		if limit > constants.DefaultMaxPaginationLimit {
+			limit = constants.DefaultMaxPaginationLimit
+		}
+	}
+
+	if v := r.URL.Query().Get("offset"); v != "" {
+		offset, err = strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return 0, 0, errors.New("invalid offset")
+		}
+	}
+
+	return limit, offset, nil
+}
+
+// blockToREST converts a block to its REST representation
+func blockToREST(block *types.Block) *Block {
+	header := block.Header()
+
+	txs := block.Transactions()
+	hashes := make([]common.Hash, len(txs))
+	for i, tx := range txs {
+		hashes[i] = tx.Hash()
+	}
+
+	result := &Block{
+		Number:       hexutil.Uint64(block.NumberU64()),
+		Hash:         block.Hash(),
+		ParentHash:   header.ParentHash,
+		Miner:        header.Coinbase,
+		StateRoot:    header.Root,
+		ReceiptsRoot: header.ReceiptHash,
+		GasLimit:     hexutil.Uint64(header.GasLimit),
+		GasUsed:      hexutil.Uint64(header.GasUsed),
+		Timestamp:    hexutil.Uint64(header.Time),
+		ExtraData:    header.Extra,
+		Size:         hexutil.Uint64(block.Size()),
+		Transactions: hashes,
+	}
+	if header.BaseFee != nil {
+		result.BaseFeePerGas = (*hexutil.Big)(header.BaseFee)
+	}
+	return result
+}
+
+// transactionToREST converts a transaction and its location to its REST representation
+func transactionToREST(tx *types.Transaction, location *storage.TxLocation) *Transaction {
+	result := &Transaction{
+		Hash:     tx.Hash(),
+		Type:     hexutil.Uint64(tx.Type()),
+		To:       tx.To(),
+		Nonce:    hexutil.Uint64(tx.Nonce()),
+		Value:    (*hexutil.Big)(tx.Value()),
+		Gas:      hexutil.Uint64(tx.Gas()),
+		GasPrice: (*hexutil.Big)(tx.GasPrice()),
+		Input:    tx.Data(),
+	}
+	if location != nil {
+		result.BlockHash = location.BlockHash
+		result.BlockNumber = hexutil.Uint64(location.BlockHeight)
+		result.TransactionIndex = hexutil.Uint64(location.TxIndex)
+	}
+	if from, err := location.Sender(tx); err == nil {
+		result.From = &from
+	}
+	if tx.Type() == types.BlobTxType {
+		result.MaxFeePerBlobGas = (*hexutil.Big)(tx.BlobGasFeeCap())
+		result.BlobVersionedHashes = tx.BlobHashes()
+	}
+	return result
+}
@@ -0,0 +1,546 @@
+package rest
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/0xmhha/indexer-go/internal/constants"
+	"github.com/0xmhha/indexer-go/pkg/storage"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var (
+	testRecipient  = common.HexToAddress("0x2222222222222222222222222222222222222222")
+	unknownHash    = common.HexToHash("0xdeadbeef00000000000000000000000000000000000000000000000000000000")
+	testChainID    = big.NewInt(1337)
+	testSenderKey  = mustGenerateKey()
+	testSenderAddr = crypto.PubkeyToAddress(testSenderKey.PublicKey)
+)
+
+func mustGenerateKey() *ecdsa.PrivateKey {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+// failingStorage returns a non-NotFound error for every lookup
+type failingStorage struct {
+	storage.Storage
+}
+
+func (f *failingStorage) GetBlock(_ context.Context, _ uint64) (*types.Block, error) {
+	return nil, errors.New("disk failure")
+}
+
+// setupTestHandler creates a handler over a pebble store holding one block
+// with a single signed transaction and its receipt
+func setupTestHandler(t *testing.T) (*Handler, *types.Block, *types.Transaction) {
+	t.Helper()
+
+	store, err := storage.NewPebbleStorage(storage.DefaultConfig(t.TempDir()))
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+
+	signer := types.LatestSignerForChainID(testChainID)
+	tx, err := types.SignTx(types.NewTransaction(0, testRecipient, big.NewInt(1000), 21000, big.NewInt(1), nil), signer, testSenderKey)
+	require.NoError(t, err)
+
+	header := &types.Header{
+		Number:   big.NewInt(1),
+		GasLimit: 8000000,
+		GasUsed:  21000,
+		Time:     1700000000,
+	}
+	block := types.NewBlockWithHeader(header).WithBody(types.Body{Transactions: []*types.Transaction{tx}})
+	receipt := &types.Receipt{
+		Status:            types.ReceiptStatusSuccessful,
+		CumulativeGasUsed: 21000,
+		TxHash:            tx.Hash(),
+		BlockHash:         block.Hash(),
+		BlockNumber:       block.Number(),
+		Logs:              []*types.Log{},
+	}
+
+	ctx := context.Background()
+	require.NoError(t, store.SetBlockWithReceipts(ctx, block, []*types.Receipt{receipt}))
+	require.NoError(t, store.AddTransactionToAddressIndex(ctx, testSenderAddr, tx.Hash()))
+	require.NoError(t, store.AddTransactionToAddressIndex(ctx, testRecipient, tx.Hash()))
+
+	return NewHandler(store, zap.NewNop()), block, tx
+}
+
+// doRequest serves a GET request through the handler's routes
+func doRequest(t *testing.T, h *Handler, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	rec := httptest.NewRecorder()
+	h.Routes().ServeHTTP(rec, req)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	return rec
+}
+
+// decodeError decodes an error response body
+func decodeError(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	var resp ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	return resp.Error
+}
+
+func TestGetBlock(t *testing.T) {
+	h, block, tx := setupTestHandler(t)
+
+	t.Run("found", func(t *testing.T) {
+		rec := doRequest(t, h, "/blocks/1")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var resp Block
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, uint64(1), uint64(resp.Number))
+		assert.Equal(t, block.Hash(), resp.Hash)
+		assert.Equal(t, []common.Hash{tx.Hash()}, resp.Transactions)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		rec := doRequest(t, h, "/blocks/999")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, "block not found", decodeError(t, rec))
+	})
+
+	t.Run("bad input", func(t *testing.T) {
+		for _, path := range []string{"/blocks/abc", "/blocks/-1", "/blocks/0x1"} {
+			rec := doRequest(t, h, path)
+			assert.Equal(t, http.StatusBadRequest, rec.Code, path)
+		}
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		rec := doRequest(t, NewHandler(&failingStorage{}, zap.NewNop()), "/blocks/1")
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+}
+
+func TestGetLatestBlocks(t *testing.T) {
+	h, block, _ := setupTestHandler(t)
+
+	ctx := context.Background()
+	next := types.NewBlockWithHeader(&types.Header{
+		Number:     big.NewInt(2),
+		ParentHash: block.Hash(),
+		GasLimit:   8000000,
+		Time:       1700000012,
+	})
+	require.NoError(t, h.storage.SetBlock(ctx, next))
+	require.NoError(t, h.storage.SetLatestHeight(ctx, 2))
+
+	t.Run("newest first", func(t *testing.T) {
+		rec := doRequest(t, h, "/blocks/latest?limit=1")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var resp LatestBlocks
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, 1, resp.Limit)
+		require.Len(t, resp.Blocks, 1)
+		assert.Equal(t, next.Hash(), resp.Blocks[0].Hash)
+	})
+
+	t.Run("limit beyond indexed range", func(t *testing.T) {
+		rec := doRequest(t, h, "/blocks/latest?limit=50")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var resp LatestBlocks
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		// Genesis was never stored, so only heights 2 and 1 come back
+		require.Len(t, resp.Blocks, 2)
+		assert.Equal(t, uint64(2), uint64(resp.Blocks[0].Number))
+		assert.Equal(t, uint64(1), uint64(resp.Blocks[1].Number))
+	})
+
+	t.Run("bad input", func(t *testing.T) {
+		rec := doRequest(t, h, "/blocks/latest?limit=abc")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestGetBlockByHash(t *testing.T) {
+	h, block, _ := setupTestHandler(t)
+
+	t.Run("found", func(t *testing.T) {
+		rec := doRequest(t, h, "/blocks/hash/"+block.Hash().Hex())
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var resp Block
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, block.Hash(), resp.Hash)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		rec := doRequest(t, h, "/blocks/hash/"+unknownHash.Hex())
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("bad input", func(t *testing.T) {
+		for _, path := range []string{"/blocks/hash/xyz", "/blocks/hash/0x1234", "/blocks/hash/" + block.Hash().Hex()[2:]} {
+			rec := doRequest(t, h, path)
+			assert.Equal(t, http.StatusBadRequest, rec.Code, path)
+		}
+	})
+}
+
+func TestGetTransaction(t *testing.T) {
+	h, block, tx := setupTestHandler(t)
+
+	t.Run("found", func(t *testing.T) {
+		rec := doRequest(t, h, "/tx/"+tx.Hash().Hex())
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var resp Transaction
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, tx.Hash(), resp.Hash)
+		assert.Equal(t, block.Hash(), resp.BlockHash)
+		assert.Equal(t, uint64(1), uint64(resp.BlockNumber))
+		require.NotNil(t, resp.From)
+		assert.Equal(t, testSenderAddr, *resp.From)
+		require.NotNil(t, resp.To)
+		assert.Equal(t, testRecipient, *resp.To)
+	})
+
+	t.Run("legacy omits blob fields", func(t *testing.T) {
+		rec := doRequest(t, h, "/tx/"+tx.Hash().Hex())
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.NotContains(t, rec.Body.String(), "maxFeePerBlobGas")
+		assert.NotContains(t, rec.Body.String(), "blobVersionedHashes")
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		rec := doRequest(t, h, "/tx/"+unknownHash.Hex())
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, "transaction not found", decodeError(t, rec))
+	})
+
+	t.Run("bad input", func(t *testing.T) {
+		rec := doRequest(t, h, "/tx/not-a-hash")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestTransactionToREST_Blob(t *testing.T) {
+	blobHashes := []common.Hash{common.HexToHash("0x01aa"), common.HexToHash("0x01bb")}
+	tx, err := types.SignNewTx(testSenderKey, types.LatestSignerForChainID(testChainID), &types.BlobTx{
+		ChainID:    uint256.MustFromBig(testChainID),
+		GasTipCap:  uint256.NewInt(1),
+		GasFeeCap:  uint256.NewInt(2),
+		Gas:        21000,
+		To:         testRecipient,
+		Value:      uint256.NewInt(1),
+		BlobFeeCap: uint256.NewInt(3000000000),
+		BlobHashes: blobHashes,
+	})
+	require.NoError(t, err)
+
+	data, err := json.Marshal(transactionToREST(tx, &storage.TxLocation{BlockHeight: 1}))
+	require.NoError(t, err)
+
+	var resp Transaction
+	require.NoError(t, json.Unmarshal(data, &resp))
+	assert.Equal(t, uint64(types.BlobTxType), uint64(resp.Type))
+	require.NotNil(t, resp.MaxFeePerBlobGas)
+	assert.Equal(t, int64(3000000000), resp.MaxFeePerBlobGas.ToInt().Int64())
+	assert.Equal(t, blobHashes, resp.BlobVersionedHashes)
+	require.NotNil(t, resp.From)
+	assert.Equal(t, testSenderAddr, *resp.From)
+}
+
+func TestGetReceipt(t *testing.T) {
+	h, _, tx := setupTestHandler(t)
+
+	t.Run("found", func(t *testing.T) {
+		rec := doRequest(t, h, "/tx/"+tx.Hash().Hex()+"/receipt")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var resp types.Receipt
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, tx.Hash(), resp.TxHash)
+		assert.Equal(t, types.ReceiptStatusSuccessful, resp.Status)
+		assert.NotContains(t, rec.Body.String(), "revertReason")
+
+		// Legacy transaction at gas price 1 using 21000 gas
+		var fees struct {
+			GasUsed           string `json:"gasUsed"`
+			EffectiveGasPrice string `json:"effectiveGasPrice"`
+			Fee               string `json:"fee"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &fees))
+		assert.Equal(t, "0x5208", fees.GasUsed)
+		assert.Equal(t, "0x1", fees.EffectiveGasPrice)
+		assert.Equal(t, "0x5208", fees.Fee)
+	})
+
+	t.Run("failed with revert reason", func(t *testing.T) {
+		store := h.storage.(*storage.PebbleStorage)
+		ctx := context.Background()
+		receipt, err := store.GetReceipt(ctx, tx.Hash())
+		require.NoError(t, err)
+		receipt.Status = types.ReceiptStatusFailed
+		require.NoError(t, store.SetReceipt(ctx, receipt))
+		require.NoError(t, store.SetRevertReason(ctx, &storage.RevertReason{
+			TxHash: tx.Hash(),
+			Error:  "execution reverted",
+			Data:   []byte{0x08, 0xc3, 0x79, 0xa0},
+			Reason: "insufficient balance",
+		}))
+
+		rec := doRequest(t, h, "/tx/"+tx.Hash().Hex()+"/receipt")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var resp types.Receipt
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, tx.Hash(), resp.TxHash)
+		assert.Equal(t, types.ReceiptStatusFailed, resp.Status)
+
+		var fields struct {
+			RevertReason string `json:"revertReason"`
+			RevertData   string `json:"revertData"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &fields))
+		assert.Equal(t, "insufficient balance", fields.RevertReason)
+		assert.Equal(t, "0x08c379a0", fields.RevertData)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		rec := doRequest(t, h, "/tx/"+unknownHash.Hex()+"/receipt")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, "receipt not found", decodeError(t, rec))
+	})
+
+	t.Run("bad input", func(t *testing.T) {
+		rec := doRequest(t, h, "/tx/0xzz/receipt")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestGetAddressTransactions(t *testing.T) {
+	h, _, tx := setupTestHandler(t)
+
+	t.Run("found", func(t *testing.T) {
+		rec := doRequest(t, h, "/address/"+testSenderAddr.Hex()+"/txs?limit=5&offset=0")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var resp AddressTransactions
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, testSenderAddr, resp.Address)
+		assert.Equal(t, 5, resp.Limit)
+		require.Len(t, resp.Transactions, 1)
+		assert.Equal(t, tx.Hash(), resp.Transactions[0].Hash)
+		require.NotNil(t, resp.Total)
+		assert.Equal(t, uint64(1), *resp.Total)
+	})
+
+	t.Run("offset past end", func(t *testing.T) {
+		rec := doRequest(t, h, "/address/"+testSenderAddr.Hex()+"/txs?offset=10")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var resp AddressTransactions
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Empty(t, resp.Transactions)
+		require.NotNil(t, resp.Total)
+		assert.Equal(t, uint64(1), *resp.Total)
+	})
+
+	t.Run("unknown address", func(t *testing.T) {
+		rec := doRequest(t, h, "/address/0x3333333333333333333333333333333333333333/txs")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var resp AddressTransactions
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Empty(t, resp.Transactions)
+		require.NotNil(t, resp.Total)
+		assert.Equal(t, uint64(0), *resp.Total)
+	})
+
+	t.Run("bad input", func(t *testing.T) {
+		paths := []string{
+			"/address/0x1234/txs",
+			"/address/" + testSenderAddr.Hex() + "/txs?limit=abc",
+			"/address/" + testSenderAddr.Hex() + "/txs?limit=0",
+			"/address/" + testSenderAddr.Hex() + "/txs?offset=-1",
+		}
+		for _, path := range paths {
+			rec := doRequest(t, h, path)
+			assert.Equal(t, http.StatusBadRequest, rec.Code, path)
+		}
+	})
+}
+
+func TestGetBalanceHistory(t *testing.T) {
+	h, _, tx := setupTestHandler(t)
+	store := h.storage.(*storage.PebbleStorage)
+
+	ctx := context.Background()
+	require.NoError(t, store.UpdateBalance(ctx, testRecipient, 1, big.NewInt(1000), tx.Hash()))
+	require.NoError(t, store.UpdateBalance(ctx, testRecipient, 2, big.NewInt(500), common.HexToHash("0x02")))
+	require.NoError(t, store.UpdateBalance(ctx, testRecipient, 3, big.NewInt(-300), common.Hash{}))
+
+	// Deltas may be negative, which hexutil.Big cannot decode
+	type snapshot struct {
+		BlockNumber     hexutil.Uint64 `json:"blockNumber"`
+		Balance         *hexutil.Big   `json:"balance"`
+		Delta           string         `json:"delta"`
+		TransactionHash *common.Hash   `json:"transactionHash"`
+	}
+	type response struct {
+		Address   common.Address `json:"address"`
+		FromBlock uint64         `json:"fromBlock"`
+		ToBlock   uint64         `json:"toBlock"`
+		Snapshots []snapshot     `json:"snapshots"`
+	}
+	get := func(t *testing.T, path string) response {
+		t.Helper()
+		rec := doRequest(t, h, path)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp response
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		return resp
+	}
+
+	t.Run("series in block order", func(t *testing.T) {
+		resp := get(t, "/address/"+testRecipient.Hex()+"/balance-history?toBlock=10")
+		assert.Equal(t, testRecipient, resp.Address)
+		assert.Equal(t, uint64(10), resp.ToBlock)
+		require.Len(t, resp.Snapshots, 3)
+
+		wantBalances := []int64{1000, 1500, 1200}
+		wantDeltas := []string{"0x3e8", "0x1f4", "-0x12c"}
+		for i, snap := range resp.Snapshots {
+			assert.Equal(t, uint64(i+1), uint64(snap.BlockNumber))
+			assert.Equal(t, wantBalances[i], snap.Balance.ToInt().Int64())
+			assert.Equal(t, wantDeltas[i], snap.Delta)
+		}
+		require.NotNil(t, resp.Snapshots[0].TransactionHash)
+		assert.Equal(t, tx.Hash(), *resp.Snapshots[0].TransactionHash)
+		assert.Nil(t, resp.Snapshots[2].TransactionHash)
+	})
+
+	t.Run("defaults to indexed head", func(t *testing.T) {
+		resp := get(t, "/address/"+testRecipient.Hex()+"/balance-history")
+		assert.Equal(t, uint64(1), resp.ToBlock)
+		require.Len(t, resp.Snapshots, 1)
+	})
+
+	t.Run("block range and pagination", func(t *testing.T) {
+		resp := get(t, "/address/"+testRecipient.Hex()+"/balance-history?fromBlock=2&toBlock=3&limit=1&offset=1")
+		require.Len(t, resp.Snapshots, 1)
+		assert.Equal(t, uint64(3), uint64(resp.Snapshots[0].BlockNumber))
+	})
+
+	t.Run("unknown address", func(t *testing.T) {
+		resp := get(t, "/address/0x3333333333333333333333333333333333333333/balance-history?toBlock=10")
+		assert.Empty(t, resp.Snapshots)
+	})
+
+	t.Run("bad input", func(t *testing.T) {
+		paths := []string{
+			"/address/0x1234/balance-history",
+			"/address/" + testRecipient.Hex() + "/balance-history?fromBlock=abc",
+			"/address/" + testRecipient.Hex() + "/balance-history?fromBlock=3&toBlock=2",
+			"/address/" + testRecipient.Hex() + "/balance-history?limit=0",
+		}
+		for _, path := range paths {
+			rec := doRequest(t, h, path)
+			assert.Equal(t, http.StatusBadRequest, rec.Code, path)
+		}
+	})
+}
+
+func TestSearch(t *testing.T) {
+	h, block, tx := setupTestHandler(t)
+
+	search := func(t *testing.T, path string) SearchResponse {
+		t.Helper()
+		rec := doRequest(t, h, path)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp SearchResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		return resp
+	}
+	resultTypes := func(resp SearchResponse) []string {
+		out := make([]string, 0, len(resp.Results))
+		for _, r := range resp.Results {
+			out = append(out, r.Type)
+		}
+		return out
+	}
+
+	t.Run("block number", func(t *testing.T) {
+		resp := search(t, "/search?q=1")
+		assert.Equal(t, "1", resp.Query)
+		require.Len(t, resp.Results, 1)
+		assert.Equal(t, "block", resp.Results[0].Type)
+		assert.Equal(t, "1", resp.Results[0].Value)
+	})
+
+	t.Run("block hash", func(t *testing.T) {
+		resp := search(t, "/search?q="+block.Hash().Hex())
+		assert.Equal(t, []string{"block"}, resultTypes(resp))
+	})
+
+	t.Run("transaction hash", func(t *testing.T) {
+		resp := search(t, "/search?q="+tx.Hash().Hex())
+		require.Len(t, resp.Results, 1)
+		assert.Equal(t, "transaction", resp.Results[0].Type)
+		assert.Equal(t, tx.Hash().Hex(), resp.Results[0].Value)
+	})
+
+	t.Run("address", func(t *testing.T) {
+		resp := search(t, "/search?q="+testSenderAddr.Hex())
+		require.Len(t, resp.Results, 1)
+		assert.Equal(t, "address", resp.Results[0].Type)
+	})
+
+	t.Run("types filter", func(t *testing.T) {
+		resp := search(t, "/search?q="+tx.Hash().Hex()+"&types=block")
+		assert.Empty(t, resp.Results)
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		for _, q := range []string{"99", "0x1234", "foo"} {
+			resp := search(t, "/search?q="+q)
+			assert.NotNil(t, resp.Results, q)
+			assert.Empty(t, resp.Results, q)
+		}
+	})
+
+	t.Run("missing query", func(t *testing.T) {
+		rec := doRequest(t, h, "/search?q=%20")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "missing query", decodeError(t, rec))
+	})
+
+	t.Run("invalid limit", func(t *testing.T) {
+		rec := doRequest(t, h, "/search?q=1&limit=x")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestParsePagination_ClampsLimit(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/?limit=100000", nil)
+	limit, offset, err := parsePagination(req)
+	require.NoError(t, err)
+	assert.Equal(t, constants.DefaultMaxPaginationLimit, limit)
+	assert.Equal(t, 0, offset)
+}
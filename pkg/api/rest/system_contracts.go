@@ -0,0 +1,243 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/0xmhha/indexer-go/pkg/storage"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// GasTipUpdate is the REST representation of a gas tip update event
+type GasTipUpdate struct {
+	BlockNumber     hexutil.Uint64 `json:"blockNumber"`
+	TransactionHash common.Hash    `json:"transactionHash"`
+	OldTip          *hexutil.Big   `json:"oldTip"`
+	NewTip          *hexutil.Big   `json:"newTip"`
+	Updater         common.Address `json:"updater"`
+	Timestamp       hexutil.Uint64 `json:"timestamp"`
+}
+
+// GasTipHistory is the response for a gas tip history listing
+type GasTipHistory struct {
+	FromBlock uint64          `json:"fromBlock"`
+	ToBlock   uint64          `json:"toBlock"`
+	Limit     int             `json:"limit"`
+	Offset    int             `json:"offset"`
+	Total     int             `json:"total"`
+	Events    []*GasTipUpdate `json:"events"`
+}
+
+// MinterAllowance is a minter together with its remaining allowance
+type MinterAllowance struct {
+	Minter    common.Address `json:"minter"`
+	Allowance *hexutil.Big   `json:"allowance"`
+}
+
+// Minters is the response for an active minter listing
+type Minters struct {
+	Limit   int                `json:"limit"`
+	Offset  int                `json:"offset"`
+	Total   int                `json:"total"`
+	Minters []*MinterAllowance `json:"minters"`
+}
+
+// AddressList is the response for a paginated address listing
+type AddressList struct {
+	Limit     int              `json:"limit"`
+	Offset    int              `json:"offset"`
+	Total     int              `json:"total"`
+	Addresses []common.Address `json:"addresses"`
+}
+
+// systemContractRoutes registers the system contract endpoints on r
+func (h *Handler) systemContractRoutes(r chi.Router) {
+	r.Get("/system/gas-tips", h.handleGetGasTipHistory)
+	r.Get("/system/minters", h.handleGetActiveMinters)
+	r.Get("/system/minters/{addr}/allowance", h.handleGetMinterAllowance)
+	r.Get("/system/validators", h.handleGetActiveValidators)
+	r.Get("/system/blacklist", h.handleGetBlacklistedAddresses)
+}
+
+// handleGetGasTipHistory handles GET /system/gas-tips?fromBlock&toBlock&limit&offset
+func (h *Handler) handleGetGasTipHistory(w http.ResponseWriter, r *http.Request) {
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		h.writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := r.Context()
+	fromBlock, err := parseBlockParam(r, "fromBlock", 0)
+	if err != nil {
+		h.writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Default the upper bound to the indexed head
+	latest, err := h.storage.GetLatestHeight(ctx)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		h.writeStorageError(w, r, err, "latest height not found")
+		return
+	}
+	toBlock, err := parseBlockParam(r, "toBlock", latest)
+	if err != nil {
+		h.writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	if fromBlock > toBlock {
+		h.writeError(w, r, http.StatusBadRequest, "fromBlock must not exceed toBlock")
+		return
+	}
+
+	events, err := h.storage.GetGasTipHistory(ctx, fromBlock, toBlock)
+	if err != nil {
+		h.writeStorageError(w, r, err, "gas tip history not found",
+			zap.Uint64("fromBlock", fromBlock), zap.Uint64("toBlock", toBlock))
+		return
+	}
+
+	start, end := pageBounds(len(events), limit, offset)
+	result := make([]*GasTipUpdate, 0, end-start)
+	for _, event := range events[start:end] {
+		result = append(result, &GasTipUpdate{
+			BlockNumber:     hexutil.Uint64(event.BlockNumber),
+			TransactionHash: event.TxHash,
+			OldTip:          (*hexutil.Big)(event.OldTip),
+			NewTip:          (*hexutil.Big)(event.NewTip),
+			Updater:         event.Updater,
+			Timestamp:       hexutil.Uint64(event.Timestamp),
+		})
+	}
+
+	h.writeJSON(w, http.StatusOK, &GasTipHistory{
+		FromBlock: fromBlock,
+		ToBlock:   toBlock,
+		Limit:     limit,
+		Offset:    offset,
+		Total:     len(events),
+		Events:    result,
+	})
+}
+
+// handleGetActiveMinters handles GET /system/minters?limit&offset
+func (h *Handler) handleGetActiveMinters(w http.ResponseWriter, r *http.Request) {
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		h.writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	minters, err := h.storage.GetActiveMinters(r.Context())
+	if err != nil {
+		h.writeStorageError(w, r, err, "minters not found")
+		return
+	}
+
+	start, end := pageBounds(len(minters), limit, offset)
+	result := make([]*MinterAllowance, 0, end-start)
+	for _, minter := range minters[start:end] {
+		result = append(result, &MinterAllowance{Minter: minter.Address, Allowance: (*hexutil.Big)(minter.Allowance)})
+	}
+
+	h.writeJSON(w, http.StatusOK, &Minters{
+		Limit:   limit,
+		Offset:  offset,
+		Total:   len(minters),
+		Minters: result,
+	})
+}
+
+// handleGetMinterAllowance handles GET /system/minters/{addr}/allowance
+func (h *Handler) handleGetMinterAllowance(w http.ResponseWriter, r *http.Request) {
+	addrParam := chi.URLParam(r, "addr")
+	if !common.IsHexAddress(addrParam) {
+		h.writeError(w, r, http.StatusBadRequest, "invalid address")
+		return
+	}
+	minter := common.HexToAddress(addrParam)
+
+	allowance, err := h.storage.GetMinterAllowance(r.Context(), minter)
+	if err != nil {
+		h.writeStorageError(w, r, err, "minter allowance not found", zap.String("minter", minter.Hex()))
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, &MinterAllowance{Minter: minter, Allowance: (*hexutil.Big)(allowance)})
+}
+
+// handleGetActiveValidators handles GET /system/validators?limit&offset
+func (h *Handler) handleGetActiveValidators(w http.ResponseWriter, r *http.Request) {
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		h.writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	validators, err := h.storage.GetActiveValidators(r.Context())
+	if err != nil {
+		h.writeStorageError(w, r, err, "validators not found")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, addressPage(validators, limit, offset))
+}
+
+// handleGetBlacklistedAddresses handles GET /system/blacklist?limit&offset
+func (h *Handler) handleGetBlacklistedAddresses(w http.ResponseWriter, r *http.Request) {
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		h.writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	addresses, err := h.storage.GetBlacklistedAddresses(r.Context())
+	if err != nil {
+		h.writeStorageError(w, r, err, "blacklisted addresses not found")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, addressPage(addresses, limit, offset))
+}
+
+// addressPage slices addresses into a paginated AddressList
+func addressPage(addresses []common.Address, limit, offset int) *AddressList {
+	start, end := pageBounds(len(addresses), limit, offset)
+	page := make([]common.Address, 0, end-start)
+	page = append(page, addresses[start:end]...)
+	return &AddressList{
+		Limit:     limit,
+		Offset:    offset,
+		Total:     len(addresses),
+		Addresses: page,
+	}
+}
+
+// pageBounds returns the slice bounds of a limit/offset page over n items
+func pageBounds(n, limit, offset int) (start, end int) {
+	if offset >= n {
+		return n, n
+	}
+	end = offset + limit
+	if end > n {
+		end = n
+	}
+	return offset, end
+}
+
+// parseBlockParam reads a decimal block number query parameter, returning def if absent
+func parseBlockParam(r *http.Request, name string, def uint64) (uint64, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		return 0, errors.New("invalid " + name)
+	}
+	return n, nil
+}
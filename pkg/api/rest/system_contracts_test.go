@@ -0,0 +1,189 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/0xmhha/indexer-go/pkg/storage"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var (
+	testMinterA     = common.HexToAddress("0xaaaa000000000000000000000000000000000001")
+	testMinterB     = common.HexToAddress("0xaaaa000000000000000000000000000000000002")
+	testValidator   = common.HexToAddress("0xbbbb000000000000000000000000000000000001")
+	testBlacklisted = common.HexToAddress("0xcccc000000000000000000000000000000000001")
+	testGasUpdater  = common.HexToAddress("0xdddd000000000000000000000000000000000001")
+)
+
+func (f *failingStorage) GetActiveValidators(_ context.Context) ([]common.Address, error) {
+	return nil, errors.New("disk failure")
+}
+
+// setupSystemContractHandler creates a handler over a pebble store seeded with
+// gas tip updates at blocks 100, 200 and 300, two minters, one validator and
+// one blacklisted address
+func setupSystemContractHandler(t *testing.T) *Handler {
+	t.Helper()
+
+	store, err := storage.NewPebbleStorage(storage.DefaultConfig(t.TempDir()))
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+
+	ctx := context.Background()
+	for i, block := range []uint64{100, 200, 300} {
+		require.NoError(t, store.StoreGasTipUpdateEvent(ctx, &storage.GasTipUpdateEvent{
+			BlockNumber: block,
+			TxHash:      common.BigToHash(big.NewInt(int64(block))),
+			OldTip:      big.NewInt(int64(i)),
+			NewTip:      big.NewInt(int64(i + 1)),
+			Updater:     testGasUpdater,
+			Timestamp:   1700000000 + block,
+		}))
+	}
+	require.NoError(t, store.UpdateActiveMinter(ctx, testMinterA, big.NewInt(1000), true))
+	require.NoError(t, store.UpdateActiveMinter(ctx, testMinterB, big.NewInt(2000), true))
+	require.NoError(t, store.UpdateActiveValidator(ctx, testValidator, true))
+	require.NoError(t, store.UpdateBlacklistStatus(ctx, testBlacklisted, true))
+	require.NoError(t, store.SetLatestHeight(ctx, 300))
+
+	return NewHandler(store, zap.NewNop())
+}
+
+func TestGetGasTipHistory(t *testing.T) {
+	h := setupSystemContractHandler(t)
+
+	t.Run("defaults to full range", func(t *testing.T) {
+		rec := doRequest(t, h, "/system/gas-tips")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var resp GasTipHistory
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, uint64(0), resp.FromBlock)
+		assert.Equal(t, uint64(300), resp.ToBlock)
+		assert.Equal(t, 3, resp.Total)
+		require.Len(t, resp.Events, 3)
+		assert.Equal(t, uint64(100), uint64(resp.Events[0].BlockNumber))
+		assert.Equal(t, int64(1), resp.Events[0].NewTip.ToInt().Int64())
+		assert.Equal(t, testGasUpdater, resp.Events[0].Updater)
+	})
+
+	t.Run("block range", func(t *testing.T) {
+		rec := doRequest(t, h, "/system/gas-tips?fromBlock=150&toBlock=300")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var resp GasTipHistory
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		require.Len(t, resp.Events, 2)
+		assert.Equal(t, uint64(200), uint64(resp.Events[0].BlockNumber))
+		assert.Equal(t, uint64(300), uint64(resp.Events[1].BlockNumber))
+	})
+
+	t.Run("paginated", func(t *testing.T) {
+		rec := doRequest(t, h, "/system/gas-tips?limit=1&offset=1")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var resp GasTipHistory
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, 3, resp.Total)
+		require.Len(t, resp.Events, 1)
+		assert.Equal(t, uint64(200), uint64(resp.Events[0].BlockNumber))
+	})
+
+	t.Run("bad input", func(t *testing.T) {
+		paths := []string{
+			"/system/gas-tips?fromBlock=abc",
+			"/system/gas-tips?toBlock=-1",
+			"/system/gas-tips?fromBlock=300&toBlock=100",
+			"/system/gas-tips?limit=0",
+		}
+		for _, path := range paths {
+			rec := doRequest(t, h, path)
+			assert.Equal(t, http.StatusBadRequest, rec.Code, path)
+		}
+	})
+}
+
+func TestGetActiveMinters(t *testing.T) {
+	h := setupSystemContractHandler(t)
+
+	rec := doRequest(t, h, "/system/minters")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp Minters
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, 2, resp.Total)
+	require.Len(t, resp.Minters, 2)
+
+	allowances := make(map[common.Address]int64)
+	for _, m := range resp.Minters {
+		allowances[m.Minter] = m.Allowance.ToInt().Int64()
+	}
+	assert.Equal(t, map[common.Address]int64{testMinterA: 1000, testMinterB: 2000}, allowances)
+
+	rec = doRequest(t, h, "/system/minters?limit=1&offset=1")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, 2, resp.Total)
+	assert.Len(t, resp.Minters, 1)
+}
+
+func TestGetMinterAllowance(t *testing.T) {
+	h := setupSystemContractHandler(t)
+
+	rec := doRequest(t, h, "/system/minters/"+testMinterB.Hex()+"/allowance")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp MinterAllowance
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, testMinterB, resp.Minter)
+	assert.Equal(t, int64(2000), resp.Allowance.ToInt().Int64())
+
+	rec = doRequest(t, h, "/system/minters/0x1234/allowance")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestGetActiveValidatorsAndBlacklist(t *testing.T) {
+	h := setupSystemContractHandler(t)
+
+	tests := []struct {
+		path string
+		want common.Address
+	}{
+		{"/system/validators", testValidator},
+		{"/system/blacklist", testBlacklisted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := doRequest(t, h, tt.path)
+			require.Equal(t, http.StatusOK, rec.Code)
+
+			var resp AddressList
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			assert.Equal(t, 1, resp.Total)
+			assert.Equal(t, []common.Address{tt.want}, resp.Addresses)
+
+			rec = doRequest(t, h, tt.path+"?offset=5")
+			require.Equal(t, http.StatusOK, rec.Code)
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			assert.Equal(t, 1, resp.Total)
+			assert.Empty(t, resp.Addresses)
+		})
+	}
+}
+
+func TestSystemContractRoutes_StorageError(t *testing.T) {
+	h := NewHandler(&failingStorage{}, zap.NewNop())
+
+	rec := doRequest(t, h, "/system/validators")
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, "internal error", decodeError(t, rec))
+}
@@ -2,15 +2,21 @@ package api
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
+	"strconv"
+	"sync"
 	"time"
 
+	"github.com/0xmhha/indexer-go/internal/constants"
 	"github.com/0xmhha/indexer-go/pkg/api/etherscan"
 	"github.com/0xmhha/indexer-go/pkg/api/graphql"
 	"github.com/0xmhha/indexer-go/pkg/api/jsonrpc"
 	apimiddleware "github.com/0xmhha/indexer-go/pkg/api/middleware"
+	"github.com/0xmhha/indexer-go/pkg/api/rest"
 	"github.com/0xmhha/indexer-go/pkg/api/websocket"
 	"github.com/0xmhha/indexer-go/pkg/events"
 	"github.com/0xmhha/indexer-go/pkg/notifications"
@@ -36,6 +42,15 @@ type Server struct {
 	rpcProxy            *rpcproxy.Proxy
 	verifier            verifier.Verifier
 	notificationService notifications.Service
+	info                *InfoOptions
+	indexing            IndexingController
+
+	// certs serves the TLS certificate (nil when TLS is disabled)
+	certs *certReloader
+	// redirectServer redirects plain HTTP to HTTPS (nil when disabled)
+	redirectServer *http.Server
+	stopped        chan struct{}
+	stopOnce       sync.Once
 }
 
 // ServerOptions contains optional configuration for the API server
@@ -43,6 +58,11 @@ type ServerOptions struct {
 	RPCProxy            *rpcproxy.Proxy
 	Verifier            verifier.Verifier
 	NotificationService notifications.Service
+	// Info describes the build and indexing setup reported by GET /info
+	Info *InfoOptions
+	// IndexingController pauses and resumes indexing through the admin API
+	// and reports the indexing state in GET /info (nil when not indexing)
+	IndexingController IndexingController
 }
 
 // NewServer creates a new API server
@@ -62,6 +82,7 @@ func NewServerWithOptions(config *Config, logger *zap.Logger, store storage.Stor
 		logger:  logger,
 		storage: store,
 		router:  chi.NewRouter(),
+		stopped: make(chan struct{}),
 	}
 
 	// Set optional RPC Proxy before setting up routes
@@ -82,6 +103,11 @@ func NewServerWithOptions(config *Config, logger *zap.Logger, store storage.Stor
 		logger.Info("Notification service configured for API server")
 	}
 
+	if opts != nil {
+		s.info = opts.Info
+		s.indexing = opts.IndexingController
+	}
+
 	// Setup middleware
 	s.setupMiddleware()
 
@@ -98,6 +124,27 @@ func NewServerWithOptions(config *Config, logger *zap.Logger, store storage.Stor
 		MaxHeaderBytes: config.MaxHeaderBytes,
 	}
 
+	if config.TLSEnabled() {
+		certs, err := newCertReloader(config.TLSCertFile, config.TLSKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		s.certs = certs
+		s.server.TLSConfig = &tls.Config{
+			GetCertificate: certs.GetCertificate,
+			MinVersion:     tls.VersionTLS12,
+		}
+
+		if config.TLSRedirectPort != 0 {
+			s.redirectServer = &http.Server{
+				Addr:              net.JoinHostPort(config.Host, strconv.Itoa(config.TLSRedirectPort)),
+				Handler:           s.redirectHandler(),
+				ReadHeaderTimeout: config.ReadTimeout,
+				IdleTimeout:       config.IdleTimeout,
+			}
+		}
+	}
+
 	return s, nil
 }
 
@@ -110,6 +157,12 @@ func (s *Server) SetEventBus(bus *events.EventBus) {
 		s.gqlSubServer.SetEventBus(bus)
 		s.logger.Info("EventBus set for GraphQL subscriptions")
 	}
+
+	// Feed pending transactions and logs to WebSocket subscribers
+	if s.wsServer != nil {
+		s.wsServer.SubscribeToEventBus(bus)
+		s.logger.Info("EventBus set for WebSocket pending transaction and logs feeds")
+	}
 }
 
 // SetRPCProxy sets the RPC Proxy for the server (enables contract call queries)
@@ -129,8 +182,8 @@ func (s *Server) setupMiddleware() {
 	// Recovery middleware (must be first)
 	s.router.Use(apimiddleware.Recovery(s.logger))
 
-	// Request ID middleware
-	s.router.Use(middleware.RequestID)
+	// Request ID middleware (honors X-Request-ID, tags the request logger)
+	s.router.Use(apimiddleware.RequestID(s.logger))
 
 	// Real IP middleware
 	s.router.Use(middleware.RealIP)
@@ -141,6 +194,19 @@ func (s *Server) setupMiddleware() {
 	// Recoverer middleware (chi's built-in)
 	s.router.Use(middleware.Recoverer)
 
+	// Concurrent connection limit (if configured)
+	if s.config.MaxConcurrentConnections > 0 {
+		s.router.Use(apimiddleware.MaxConcurrent(s.config.MaxConcurrentConnections, map[string]bool{
+			"/health":  true,
+			"/version": true,
+			"/info":    true,
+			"/metrics": true,
+		}))
+		s.logger.Info("concurrent connection limit enabled",
+			zap.Int("max_connections", s.config.MaxConcurrentConnections),
+		)
+	}
+
 	// Rate limiting middleware (if enabled)
 	if s.config.EnableRateLimit {
 		s.router.Use(apimiddleware.RateLimit(
@@ -161,9 +227,16 @@ func (s *Server) setupMiddleware() {
 			AllowedPaths: map[string]bool{
 				"/health":  true,
 				"/version": true,
+				"/info":    true,
 				"/metrics": true,
 			},
 		}
+		// The admin endpoints check the admin API keys instead
+		if s.config.EnableAdminAPI {
+			for _, path := range adminPaths {
+				authCfg.AllowedPaths[path] = true
+			}
+		}
 		s.router.Use(apimiddleware.APIKeyAuth(authCfg, s.logger))
 		s.logger.Info("API key authentication enabled",
 			zap.Int("configured_keys", len(s.config.APIKeys)),
@@ -206,6 +279,26 @@ func (s *Server) setupMiddleware() {
 			})
 		})
 	}
+
+	// Response masking middleware (if fields or addresses are configured)
+	if s.config.ResponseMaskEnabled() {
+		s.router.Use(apimiddleware.ResponseMask(s.maskConfig()))
+		s.logger.Info("response masking enabled",
+			zap.Strings("fields", s.config.MaskedFields),
+			zap.Int("addresses", len(s.config.MaskedAddresses)),
+			zap.Bool("omit", s.config.OmitMaskedFields),
+		)
+	}
+}
+
+// maskConfig returns the response masking settings shared by HTTP responses
+// and WebSocket messages
+func (s *Server) maskConfig() apimiddleware.MaskConfig {
+	return apimiddleware.MaskConfig{
+		Fields:    s.config.MaskedFields,
+		Addresses: s.config.MaskedAddresses,
+		Omit:      s.config.OmitMaskedFields,
+	}
 }
 
 // setupRoutes configures the API routes
@@ -216,6 +309,11 @@ func (s *Server) setupRoutes() {
 
 		// Create WebSocket server
 		s.wsServer = websocket.NewServer(s.logger)
+		s.wsServer.SetKeepAliveIntervals(s.config.webSocketKeepAlive())
+		s.wsServer.SetReceiptReader(s.storage)
+		if s.config.ResponseMaskEnabled() {
+			s.wsServer.SetMessageMask(apimiddleware.NewResponseMasker(s.maskConfig()).MaskMessage)
+		}
 		s.router.Get(s.config.WebSocketPath, s.wsServer.ServeHTTP)
 	}
 
@@ -225,6 +323,9 @@ func (s *Server) setupRoutes() {
 	// API version endpoint
 	s.router.Get("/version", s.handleVersion)
 
+	// Build, chain and indexing info endpoint
+	s.router.Get("/info", s.handleInfo)
+
 	// Prometheus metrics endpoint
 	s.router.Handle("/metrics", promhttp.Handler())
 
@@ -239,22 +340,37 @@ func (s *Server) setupRoutes() {
 		opts := &graphql.HandlerOptions{
 			RPCProxy:            s.rpcProxy,
 			NotificationService: s.notificationService,
+			QueryLimits: graphql.QueryLimits{
+				MaxDepth:      s.config.GraphQLMaxDepth,
+				MaxComplexity: s.config.GraphQLMaxComplexity,
+			},
 		}
 		graphqlHandler, err := graphql.NewHandlerWithOptions(s.storage, s.logger, opts)
 		if err != nil {
 			s.logger.Error("failed to create GraphQL handler", zap.Error(err))
 		} else {
-			s.router.Handle(s.config.GraphQLPath, graphqlHandler)
+			timeout, maxBytes := s.config.requestLimits()
+			s.router.With(
+				apimiddleware.MaxRequestBytes(maxBytes),
+				apimiddleware.Timeout(timeout),
+			).Handle(s.config.GraphQLPath, graphqlHandler)
 			s.router.Get(s.config.GraphQLPlaygroundPath, graphqlHandler.PlaygroundHandler())
 			s.logger.Info("GraphQL playground enabled", zap.String("path", s.config.GraphQLPlaygroundPath))
 		}
 
 		// Create GraphQL Subscription server (EventBus will be set later via SetEventBus)
 		s.gqlSubServer = graphql.NewSubscriptionServer(nil, s.logger, s.config.EnableWebSocketKeepAlive)
+		pingInterval, idleTimeout := s.config.webSocketKeepAlive()
+		s.gqlSubServer.SetKeepAliveIntervals(pingInterval, idleTimeout)
+		if s.config.ResponseMaskEnabled() {
+			s.gqlSubServer.SetMessageMask(apimiddleware.NewResponseMasker(s.maskConfig()).MaskMessage)
+		}
 		s.router.Get("/graphql/ws", s.gqlSubServer.Handler())
 		s.logger.Info("GraphQL subscriptions endpoint registered",
 			zap.String("path", "/graphql/ws"),
-			zap.Bool("keep_alive", s.config.EnableWebSocketKeepAlive))
+			zap.Bool("keep_alive", s.config.EnableWebSocketKeepAlive),
+			zap.Duration("ping_interval", pingInterval),
+			zap.Duration("idle_timeout", idleTimeout))
 	}
 
 	// JSON-RPC endpoints
@@ -263,6 +379,8 @@ func (s *Server) setupRoutes() {
 
 		// Create JSON-RPC handler
 		jsonrpcServer := jsonrpc.NewServer(s.storage, s.logger)
+		jsonrpcServer.SetRequestLimits(s.config.requestLimits())
+		jsonrpcServer.SetMethodFilter(s.config.JSONRPCAllowedMethods, s.config.JSONRPCDeniedMethods)
 
 		// Set notification service if available
 		if s.notificationService != nil {
@@ -273,6 +391,18 @@ func (s *Server) setupRoutes() {
 		s.router.Post(s.config.JSONRPCPath, jsonrpcServer.ServeHTTP)
 	}
 
+	// REST endpoints
+	if s.config.EnableREST {
+		s.logger.Info("REST API enabled", zap.String("path", constants.DefaultRESTPath))
+		s.router.Mount(constants.DefaultRESTPath, rest.NewHandler(s.storage, s.logger).Routes())
+	}
+
+	// Admin endpoints (opt-in, authenticated with the admin API keys)
+	if s.config.EnableAdminAPI {
+		s.logger.Info("Admin API enabled", zap.String("path", "/admin"))
+		s.router.Mount("/admin", s.adminRoutes())
+	}
+
 	// Etherscan-compatible API endpoints (for Forge verification)
 	etherscanHandler := etherscan.NewHandler(s.storage, s.verifier, s.logger)
 	s.router.Get("/api", etherscanHandler.ServeHTTP)
@@ -285,6 +415,14 @@ type HealthResponse struct {
 	Status    string              `json:"status"`
 	Timestamp string              `json:"timestamp"`
 	EventBus  *EventBusHealthInfo `json:"eventbus,omitempty"`
+	Indexer   *IndexerHealthInfo  `json:"indexer,omitempty"`
+}
+
+// IndexerHealthInfo contains indexing progress relative to the chain head
+type IndexerHealthInfo struct {
+	LatestHeight uint64 `json:"latest_height"`
+	ChainHead    uint64 `json:"chain_head"`
+	Lag          uint64 `json:"lag"`
 }
 
 // EventBusHealthInfo contains EventBus health information
@@ -315,10 +453,39 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Add indexing lag if the fetcher has recorded a chain head
+	response.Indexer = s.indexerHealth(r.Context())
+
 	w.WriteHeader(http.StatusOK)
 	_ = json.NewEncoder(w).Encode(response)
 }
 
+// indexerHealth returns the indexed height, the last chain head recorded by the
+// fetcher and the lag between them, or nil if either is unavailable
+func (s *Server) indexerHealth(ctx context.Context) *IndexerHealthInfo {
+	reader, ok := s.storage.(storage.ChainHeadReader)
+	if !ok {
+		return nil
+	}
+	chainHead, err := reader.GetChainHead(ctx)
+	if err != nil {
+		return nil
+	}
+	latestHeight, err := s.storage.GetLatestHeight(ctx)
+	if err != nil {
+		return nil
+	}
+
+	info := &IndexerHealthInfo{
+		LatestHeight: latestHeight,
+		ChainHead:    chainHead,
+	}
+	if chainHead > latestHeight {
+		info.Lag = chainHead - latestHeight
+	}
+	return info
+}
+
 // handleVersion handles the version endpoint
 func (s *Server) handleVersion(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
@@ -364,12 +531,41 @@ func (s *Server) Start() error {
 		zap.Bool("graphql", s.config.EnableGraphQL),
 		zap.Bool("jsonrpc", s.config.EnableJSONRPC),
 		zap.Bool("websocket", s.config.EnableWebSocket),
+		zap.Bool("rest", s.config.EnableREST),
+		zap.Bool("tls", s.config.TLSEnabled()),
 	)
 
-	if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+	ln, err := net.Listen("tcp", s.config.Address())
+	if err != nil {
 		return fmt.Errorf("server failed: %w", err)
 	}
+	return s.serve(ln)
+}
 
+// serve accepts connections on ln, over TLS when configured
+func (s *Server) serve(ln net.Listener) error {
+	if s.certs == nil {
+		if err := s.server.Serve(ln); err != nil && err != http.ErrServerClosed {
+			return fmt.Errorf("server failed: %w", err)
+		}
+		return nil
+	}
+
+	s.watchCertificateReload()
+
+	if s.redirectServer != nil {
+		go func() {
+			s.logger.Info("starting HTTP to HTTPS redirect", zap.String("address", s.redirectServer.Addr))
+			if err := s.redirectServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				s.logger.Error("HTTP redirect server failed", zap.Error(err))
+			}
+		}()
+	}
+
+	// Certificates come from TLSConfig.GetCertificate so they can be reloaded
+	if err := s.server.ServeTLS(ln, "", ""); err != nil && err != http.ErrServerClosed {
+		return fmt.Errorf("server failed: %w", err)
+	}
 	return nil
 }
 
@@ -386,7 +582,14 @@ func (s *Server) Stop(ctx context.Context) error {
 	shutdownCtx, cancel := context.WithTimeout(ctx, s.config.ShutdownTimeout)
 	defer cancel()
 
+	s.stopOnce.Do(func() { close(s.stopped) })
+
 	// Shutdown server
+	if s.redirectServer != nil {
+		if err := s.redirectServer.Shutdown(shutdownCtx); err != nil {
+			return fmt.Errorf("redirect server shutdown failed: %w", err)
+		}
+	}
 	if err := s.server.Shutdown(shutdownCtx); err != nil {
 		return fmt.Errorf("server shutdown failed: %w", err)
 	}
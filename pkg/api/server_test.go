@@ -2,8 +2,13 @@ package api
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -11,6 +16,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 // mockStorage is a mock implementation of storage.Storage for testing
@@ -123,6 +129,20 @@ func TestNewServer(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "REST only",
+			config: &Config{
+				Host:            "localhost",
+				Port:            8080,
+				ReadTimeout:     10 * time.Second,
+				WriteTimeout:    10 * time.Second,
+				IdleTimeout:     60 * time.Second,
+				MaxHeaderBytes:  1 << 20,
+				ShutdownTimeout: 30 * time.Second,
+				EnableREST:      true,
+			},
+			wantErr: false,
+		},
 	}
 
 	logger := zap.NewNop()
@@ -169,6 +189,46 @@ func TestServerHealthEndpoint(t *testing.T) {
 	}
 }
 
+// chainHeadStorage is a mockStorage that also records a chain head
+type chainHeadStorage struct {
+	mockStorage
+	latestHeight uint64
+	chainHead    uint64
+}
+
+func (m *chainHeadStorage) GetLatestHeight(ctx context.Context) (uint64, error) {
+	return m.latestHeight, nil
+}
+
+func (m *chainHeadStorage) GetChainHead(ctx context.Context) (uint64, error) {
+	return m.chainHead, nil
+}
+
+func TestServerHealthEndpointIndexerLag(t *testing.T) {
+	store := &chainHeadStorage{latestHeight: 90, chainHead: 100}
+
+	server, err := NewServer(DefaultConfig(), zap.NewNop(), store)
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	w := httptest.NewRecorder()
+
+	server.Router().ServeHTTP(w, req)
+
+	var response HealthResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode health response: %v", err)
+	}
+	if response.Indexer == nil {
+		t.Fatal("health response is missing indexer info")
+	}
+	if response.Indexer.LatestHeight != 90 || response.Indexer.ChainHead != 100 || response.Indexer.Lag != 10 {
+		t.Errorf("indexer info = %+v, want latest 90, head 100, lag 10", *response.Indexer)
+	}
+}
+
 func TestServerVersionEndpoint(t *testing.T) {
 	config := DefaultConfig()
 	logger := zap.NewNop()
@@ -326,6 +386,172 @@ func TestConfigValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "negative request timeout",
+			config: &Config{
+				Host:            "localhost",
+				Port:            8080,
+				ReadTimeout:     10 * time.Second,
+				WriteTimeout:    10 * time.Second,
+				IdleTimeout:     60 * time.Second,
+				MaxHeaderBytes:  1 << 20,
+				RequestTimeout:  -1 * time.Second,
+				ShutdownTimeout: 30 * time.Second,
+				EnableGraphQL:   true,
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative max request bytes",
+			config: &Config{
+				Host:            "localhost",
+				Port:            8080,
+				ReadTimeout:     10 * time.Second,
+				WriteTimeout:    10 * time.Second,
+				IdleTimeout:     60 * time.Second,
+				MaxHeaderBytes:  1 << 20,
+				MaxRequestBytes: -1,
+				ShutdownTimeout: 30 * time.Second,
+				EnableGraphQL:   true,
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative GraphQL max depth",
+			config: &Config{
+				Host:            "localhost",
+				Port:            8080,
+				ReadTimeout:     10 * time.Second,
+				WriteTimeout:    10 * time.Second,
+				IdleTimeout:     60 * time.Second,
+				MaxHeaderBytes:  1 << 20,
+				GraphQLMaxDepth: -1,
+				ShutdownTimeout: 30 * time.Second,
+				EnableGraphQL:   true,
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative GraphQL max complexity",
+			config: &Config{
+				Host:                 "localhost",
+				Port:                 8080,
+				ReadTimeout:          10 * time.Second,
+				WriteTimeout:         10 * time.Second,
+				IdleTimeout:          60 * time.Second,
+				MaxHeaderBytes:       1 << 20,
+				GraphQLMaxComplexity: -1,
+				ShutdownTimeout:      30 * time.Second,
+				EnableGraphQL:        true,
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative max concurrent connections",
+			config: &Config{
+				Host:                     "localhost",
+				Port:                     8080,
+				ReadTimeout:              10 * time.Second,
+				WriteTimeout:             10 * time.Second,
+				IdleTimeout:              60 * time.Second,
+				MaxHeaderBytes:           1 << 20,
+				MaxConcurrentConnections: -1,
+				ShutdownTimeout:          30 * time.Second,
+				EnableGraphQL:            true,
+			},
+			wantErr: true,
+		},
+		{
+			name: "empty JSON-RPC denied method",
+			config: &Config{
+				Host:                 "localhost",
+				Port:                 8080,
+				ReadTimeout:          10 * time.Second,
+				WriteTimeout:         10 * time.Second,
+				IdleTimeout:          60 * time.Second,
+				MaxHeaderBytes:       1 << 20,
+				JSONRPCDeniedMethods: []string{"eth_newFilter", " "},
+				ShutdownTimeout:      30 * time.Second,
+				EnableJSONRPC:        true,
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid masked address",
+			config: &Config{
+				Host:            "localhost",
+				Port:            8080,
+				ReadTimeout:     10 * time.Second,
+				WriteTimeout:    10 * time.Second,
+				IdleTimeout:     60 * time.Second,
+				MaxHeaderBytes:  1 << 20,
+				MaskedAddresses: []string{"0x1234"},
+				ShutdownTimeout: 30 * time.Second,
+				EnableJSONRPC:   true,
+			},
+			wantErr: true,
+		},
+		{
+			name: "empty masked field",
+			config: &Config{
+				Host:            "localhost",
+				Port:            8080,
+				ReadTimeout:     10 * time.Second,
+				WriteTimeout:    10 * time.Second,
+				IdleTimeout:     60 * time.Second,
+				MaxHeaderBytes:  1 << 20,
+				MaskedFields:    []string{"input", ""},
+				ShutdownTimeout: 30 * time.Second,
+				EnableJSONRPC:   true,
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative websocket idle timeout",
+			config: &Config{
+				Host:                 "localhost",
+				Port:                 8080,
+				ReadTimeout:          10 * time.Second,
+				WriteTimeout:         10 * time.Second,
+				IdleTimeout:          60 * time.Second,
+				MaxHeaderBytes:       1 << 20,
+				WebSocketIdleTimeout: -time.Second,
+				ShutdownTimeout:      30 * time.Second,
+				EnableGraphQL:        true,
+			},
+			wantErr: true,
+		},
+		{
+			name: "websocket ping interval not below idle timeout",
+			config: &Config{
+				Host:                  "localhost",
+				Port:                  8080,
+				ReadTimeout:           10 * time.Second,
+				WriteTimeout:          10 * time.Second,
+				IdleTimeout:           60 * time.Second,
+				MaxHeaderBytes:        1 << 20,
+				WebSocketPingInterval: 90 * time.Second,
+				ShutdownTimeout:       30 * time.Second,
+				EnableGraphQL:         true,
+			},
+			wantErr: true,
+		},
+		{
+			name: "custom websocket keep-alive",
+			config: &Config{
+				Host:                  "localhost",
+				Port:                  8080,
+				ReadTimeout:           10 * time.Second,
+				WriteTimeout:          10 * time.Second,
+				IdleTimeout:           60 * time.Second,
+				MaxHeaderBytes:        1 << 20,
+				WebSocketPingInterval: 10 * time.Second,
+				WebSocketIdleTimeout:  30 * time.Second,
+				ShutdownTimeout:       30 * time.Second,
+				EnableGraphQL:         true,
+			},
+			wantErr: false,
+		},
 		{
 			name: "zero max header bytes",
 			config: &Config{
@@ -379,6 +605,56 @@ func TestServerMiddleware(t *testing.T) {
 	}
 }
 
+// failingBlockStorage fails every block lookup with a non-not-found error
+type failingBlockStorage struct {
+	mockStorage
+}
+
+func (m *failingBlockStorage) GetBlock(ctx context.Context, height uint64) (*types.Block, error) {
+	return nil, errors.New("disk failure")
+}
+
+func TestServerRequestID(t *testing.T) {
+	config := DefaultConfig()
+	config.EnableREST = true
+
+	core, logs := observer.New(zap.InfoLevel)
+	server, err := NewServer(config, zap.New(core), &failingBlockStorage{})
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/rest/blocks/1", nil)
+	req.Header.Set("X-Request-ID", "req-1624")
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+	if got := w.Header().Get("X-Request-ID"); got != "req-1624" {
+		t.Errorf("X-Request-ID header = %q, want %q", got, "req-1624")
+	}
+	var body struct {
+		Error     string `json:"error"`
+		RequestID string `json:"request_id"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode error response: %v", err)
+	}
+	if body.RequestID != "req-1624" {
+		t.Errorf("error response request_id = %q, want %q", body.RequestID, "req-1624")
+	}
+
+	// Both the storage failure and the access log carry the request ID
+	for _, msg := range []string{"REST storage query failed", "http request - server error"} {
+		entries := logs.FilterMessage(msg).FilterField(zap.String("request_id", "req-1624")).All()
+		if len(entries) != 1 {
+			t.Errorf("%q logged %d times with the request ID, want 1", msg, len(entries))
+		}
+	}
+}
+
 func TestConfigDefaults(t *testing.T) {
 	config := DefaultConfig()
 
@@ -409,3 +685,112 @@ func TestConfigDefaults(t *testing.T) {
 		t.Errorf("expected address %s, got %s", expectedAddr, config.Address())
 	}
 }
+
+func TestServerMaxRequestBytes(t *testing.T) {
+	config := DefaultConfig()
+	config.MaxRequestBytes = 256
+
+	server, err := NewServer(config, zap.NewNop(), &mockStorage{})
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+
+	oversized := `{"query":"{ latestHeight }","padding":"` + strings.Repeat("x", 512) + `"}`
+	for _, path := range []string{config.GraphQLPath, config.JSONRPCPath} {
+		t.Run(path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(oversized))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			server.Router().ServeHTTP(w, req)
+
+			if w.Code != http.StatusRequestEntityTooLarge {
+				t.Errorf("expected status 413, got %d: %s", w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+// maskTestStorage serves a single transaction for the response masking test
+type maskTestStorage struct {
+	mockStorage
+	tx *types.Transaction
+}
+
+func (m *maskTestStorage) GetTransaction(ctx context.Context, hash common.Hash) (*types.Transaction, *storage.TxLocation, error) {
+	return m.tx, &storage.TxLocation{BlockHeight: 7, BlockHash: common.HexToHash("0xb7")}, nil
+}
+
+func (m *maskTestStorage) GetReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	return nil, storage.ErrNotFound
+}
+
+func TestServerResponseMask(t *testing.T) {
+	masked := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	tx := types.NewTransaction(3, masked, big.NewInt(42), 21000, big.NewInt(1), []byte{0xa9, 0x05, 0x9c, 0xbb})
+
+	for _, omit := range []bool{false, true} {
+		t.Run(fmt.Sprintf("omit=%v", omit), func(t *testing.T) {
+			config := DefaultConfig()
+			config.MaskedFields = []string{"input"}
+			config.MaskedAddresses = []string{strings.ToLower(masked.Hex())}
+			config.OmitMaskedFields = omit
+
+			server, err := NewServer(config, zap.NewNop(), &maskTestStorage{tx: tx})
+			if err != nil {
+				t.Fatalf("NewServer() error = %v", err)
+			}
+
+			body := `{"jsonrpc":"2.0","method":"getTxResult","params":{"hash":"` + tx.Hash().Hex() + `"},"id":1}`
+			req := httptest.NewRequest(http.MethodPost, config.JSONRPCPath, strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			server.Router().ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+			}
+			var resp struct {
+				Result map[string]interface{} `json:"result"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+
+			for _, key := range []string{"input", "to"} {
+				val, ok := resp.Result[key]
+				if omit && ok {
+					t.Errorf("expected %q to be omitted, got %v", key, val)
+				}
+				if !omit && val != "[redacted]" {
+					t.Errorf("expected %q to be redacted, got %v", key, val)
+				}
+			}
+			if resp.Result["hash"] != tx.Hash().Hex() {
+				t.Errorf("expected hash %s, got %v", tx.Hash().Hex(), resp.Result["hash"])
+			}
+			if resp.Result["nonce"] != "0x3" || resp.Result["value"] != "0x2a" || resp.Result["blockNumber"] != "0x7" {
+				t.Errorf("unmasked fields changed: %v", resp.Result)
+			}
+		})
+	}
+
+	t.Run("unmasked by default", func(t *testing.T) {
+		server, err := NewServer(DefaultConfig(), zap.NewNop(), &maskTestStorage{tx: tx})
+		if err != nil {
+			t.Fatalf("NewServer() error = %v", err)
+		}
+
+		body := `{"jsonrpc":"2.0","method":"getTxResult","params":{"hash":"` + tx.Hash().Hex() + `"},"id":1}`
+		req := httptest.NewRequest(http.MethodPost, "/rpc", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		server.Router().ServeHTTP(w, req)
+
+		if !strings.Contains(w.Body.String(), `"input":"0xa9059cbb"`) {
+			t.Errorf("expected raw input data, got %s", w.Body.String())
+		}
+	})
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
+
+	"go.uber.org/zap"
+)
+
+// certReloader serves a certificate that can be replaced at runtime, so a
+// rotated certificate takes effect without restarting the server
+type certReloader struct {
+	certFile string
+	keyFile  string
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+}
+
+// newCertReloader loads the certificate and key from disk
+func newCertReloader(certFile, keyFile string) (*certReloader, error) {
+	r := &certReloader{certFile: certFile, keyFile: keyFile}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// reload re-reads the certificate and key. On failure the current
+// certificate stays in use.
+func (r *certReloader) reload() error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+
+	r.mu.Lock()
+	r.cert = &cert
+	r.mu.Unlock()
+	return nil
+}
+
+// GetCertificate implements tls.Config.GetCertificate
+func (r *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+// ReloadTLSCertificate re-reads the configured certificate and key.
+// It is a no-op when TLS is not enabled.
+func (s *Server) ReloadTLSCertificate() error {
+	if s.certs == nil {
+		return nil
+	}
+	if err := s.certs.reload(); err != nil {
+		return err
+	}
+	s.logger.Info("TLS certificate reloaded", zap.String("cert_file", s.config.TLSCertFile))
+	return nil
+}
+
+// watchCertificateReload reloads the TLS certificate on SIGHUP until the
+// server is stopped
+func (s *Server) watchCertificateReload() {
+	hupChan := make(chan os.Signal, 1)
+	signal.Notify(hupChan, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(hupChan)
+		for {
+			select {
+			case <-s.stopped:
+				return
+			case <-hupChan:
+				if err := s.ReloadTLSCertificate(); err != nil {
+					s.logger.Error("Failed to reload TLS certificate", zap.Error(err))
+				}
+			}
+		}
+	}()
+}
+
+// redirectHandler redirects plain HTTP requests to the HTTPS listener
+func (s *Server) redirectHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if s.config.Port != 443 {
+			host = net.JoinHostPort(host, strconv.Itoa(s.config.Port))
+		}
+
+		target := "https://" + host + r.URL.RequestURI()
+		http.Redirect(w, r, target, http.StatusMovedPermanently)
+	})
+}
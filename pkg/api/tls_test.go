@@ -0,0 +1,201 @@
+package api
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// writeSelfSignedCert writes a self-signed certificate for 127.0.0.1 with the
+// given common name to dir and returns the cert and key paths
+func writeSelfSignedCert(t *testing.T, dir, commonName string) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate() error = %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalECPrivateKey() error = %v", err)
+	}
+
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	return certFile, keyFile
+}
+
+func TestServerTLS(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeSelfSignedCert(t, dir, "first")
+
+	config := DefaultConfig()
+	config.Host = "127.0.0.1"
+	config.TLSCertFile = certFile
+	config.TLSKeyFile = keyFile
+	server, err := NewServer(config, zap.NewNop(), &mockStorage{})
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	errChan := make(chan error, 1)
+	go func() { errChan <- server.serve(ln) }()
+	defer func() {
+		if err := server.Stop(context.Background()); err != nil {
+			t.Errorf("Stop() error = %v", err)
+		}
+		if err := <-errChan; err != nil {
+			t.Errorf("serve() error = %v", err)
+		}
+	}()
+
+	url := "https://" + ln.Addr().String() + "/health"
+	// peerName returns the common name of the certificate the server presents
+	peerName := func() string {
+		t.Helper()
+		client := &http.Client{
+			Timeout: 5 * time.Second,
+			Transport: &http.Transport{
+				// The certificate is self-signed; each request opens a new connection
+				TLSClientConfig:   &tls.Config{InsecureSkipVerify: true},
+				DisableKeepAlives: true,
+			},
+		}
+		resp, err := client.Get(url)
+		if err != nil {
+			t.Fatalf("GET %s error = %v", url, err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("GET %s status = %d, want %d", url, resp.StatusCode, http.StatusOK)
+		}
+		return resp.TLS.PeerCertificates[0].Subject.CommonName
+	}
+
+	if got := peerName(); got != "first" {
+		t.Errorf("served certificate = %q, want %q", got, "first")
+	}
+
+	// Plain HTTP is rejected on the TLS listener
+	resp, err := http.Get("http://" + ln.Addr().String() + "/health")
+	if err == nil {
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("plain HTTP status = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+		}
+		resp.Body.Close()
+	}
+
+	// Rotate the certificate on disk and reload it
+	writeSelfSignedCert(t, dir, "second")
+	if err := server.ReloadTLSCertificate(); err != nil {
+		t.Fatalf("ReloadTLSCertificate() error = %v", err)
+	}
+	if got := peerName(); got != "second" {
+		t.Errorf("served certificate after reload = %q, want %q", got, "second")
+	}
+
+	// A broken certificate keeps the current one in use
+	if err := os.WriteFile(certFile, []byte("invalid"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if err := server.ReloadTLSCertificate(); err == nil {
+		t.Error("ReloadTLSCertificate() should fail for an invalid certificate")
+	}
+	if got := peerName(); got != "second" {
+		t.Errorf("served certificate after failed reload = %q, want %q", got, "second")
+	}
+}
+
+func TestServerTLSRedirect(t *testing.T) {
+	certFile, keyFile := writeSelfSignedCert(t, t.TempDir(), "redirect")
+
+	config := DefaultConfig()
+	config.Port = 8443
+	config.TLSCertFile = certFile
+	config.TLSKeyFile = keyFile
+	config.TLSRedirectPort = 8080
+	server, err := NewServer(config, zap.NewNop(), &mockStorage{})
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "http://indexer.example:8080/graphql?query=1", nil)
+	w := httptest.NewRecorder()
+	server.redirectHandler().ServeHTTP(w, req)
+
+	if w.Code != http.StatusMovedPermanently {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusMovedPermanently)
+	}
+	if got, want := w.Header().Get("Location"), "https://indexer.example:8443/graphql?query=1"; got != want {
+		t.Errorf("Location = %q, want %q", got, want)
+	}
+}
+
+func TestConfigValidation_TLS(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*Config)
+		wantErr bool
+	}{
+		{"cert without key", func(c *Config) { c.TLSCertFile = "cert.pem" }, true},
+		{"key without cert", func(c *Config) { c.TLSKeyFile = "key.pem" }, true},
+		{"redirect without TLS", func(c *Config) { c.TLSRedirectPort = 80 }, true},
+		{"redirect on server port", func(c *Config) {
+			c.TLSCertFile, c.TLSKeyFile, c.TLSRedirectPort = "cert.pem", "key.pem", c.Port
+		}, true},
+		{"redirect port out of range", func(c *Config) {
+			c.TLSCertFile, c.TLSKeyFile, c.TLSRedirectPort = "cert.pem", "key.pem", 70000
+		}, true},
+		{"TLS with redirect", func(c *Config) {
+			c.TLSCertFile, c.TLSKeyFile, c.TLSRedirectPort = "cert.pem", "key.pem", 80
+		}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			tt.modify(config)
+			if err := config.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,25 +2,26 @@ package websocket
 
 import (
 	"encoding/json"
+	"fmt"
 	"sync"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/gorilla/websocket"
 	"go.uber.org/zap"
+
+	"github.com/0xmhha/indexer-go/internal/constants"
+	"github.com/0xmhha/indexer-go/pkg/events"
 )
 
 const (
 	// Time allowed to write a message to the peer
 	writeWait = 10 * time.Second
 
-	// Time allowed to read the next pong message from the peer
-	pongWait = 60 * time.Second
-
-	// Send pings to peer with this period (must be less than pongWait)
-	pingPeriod = (pongWait * 9) / 10
-
-	// Maximum message size allowed from peer
-	maxMessageSize = 512
+	// Maximum message size allowed from peer (room for a logs filter with topic sets)
+	maxMessageSize = 4096
 )
 
 // Client represents a WebSocket client connection
@@ -31,7 +32,20 @@ type Client struct {
 
 	// Subscriptions tracks which event types this client is subscribed to
 	subscriptions map[SubscriptionType]bool
-	mu            sync.RWMutex
+	// addressFilters restricts a subscription to events touching one address
+	addressFilters map[SubscriptionType]common.Address
+	// logFilters holds independent logs subscriptions keyed by subscription ID
+	logFilters map[string]*events.Filter
+	nextLogID  uint64
+	mu         sync.RWMutex
+
+	// pingInterval is how often pings are sent; idleTimeout is how long the
+	// peer may go without a pong or message before it is disconnected
+	pingInterval time.Duration
+	idleTimeout  time.Duration
+
+	// mask rewrites each message before it is sent; nil sends messages as is
+	mask func(message []byte) ([]byte, error)
 
 	logger *zap.Logger
 }
@@ -39,11 +53,15 @@ type Client struct {
 // NewClient creates a new WebSocket client
 func NewClient(hub *Hub, conn *websocket.Conn, logger *zap.Logger) *Client {
 	return &Client{
-		hub:           hub,
-		conn:          conn,
-		send:          make(chan []byte, 256),
-		subscriptions: make(map[SubscriptionType]bool),
-		logger:        logger,
+		hub:            hub,
+		conn:           conn,
+		send:           make(chan []byte, 256),
+		subscriptions:  make(map[SubscriptionType]bool),
+		addressFilters: make(map[SubscriptionType]common.Address),
+		logFilters:     make(map[string]*events.Filter),
+		pingInterval:   constants.DefaultWebSocketPingInterval,
+		idleTimeout:    constants.DefaultWebSocketIdleTimeout,
+		logger:         logger,
 	}
 }
 
@@ -59,6 +77,15 @@ func (c *Client) Subscribe(eventType SubscriptionType) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.subscriptions[eventType] = true
+	delete(c.addressFilters, eventType)
+}
+
+// SubscribeAddress subscribes the client to events of a type that touch the address
+func (c *Client) SubscribeAddress(eventType SubscriptionType, address common.Address) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.subscriptions[eventType] = true
+	c.addressFilters[eventType] = address
 }
 
 // Unsubscribe unsubscribes the client from an event type
@@ -66,6 +93,75 @@ func (c *Client) Unsubscribe(eventType SubscriptionType) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	delete(c.subscriptions, eventType)
+	delete(c.addressFilters, eventType)
+	if eventType == SubscribeLogs {
+		c.logFilters = make(map[string]*events.Filter)
+	}
+}
+
+// SubscribeLogs adds a logs subscription and returns its ID
+// Each call creates an independent subscription with its own filter
+func (c *Client) SubscribeLogs(filter *events.Filter) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nextLogID++
+	id := fmt.Sprintf("0x%x", c.nextLogID)
+	c.logFilters[id] = filter
+	return id
+}
+
+// UnsubscribeLogs removes a logs subscription, reporting whether it existed
+func (c *Client) UnsubscribeLogs(id string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.logFilters[id]; !ok {
+		return false
+	}
+	delete(c.logFilters, id)
+	return true
+}
+
+// hasLogSubscriptions reports whether the client has any logs subscription
+func (c *Client) hasLogSubscriptions() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.logFilters) > 0
+}
+
+// matchLogSubscriptions returns the IDs of the logs subscriptions the log matches
+func (c *Client) matchLogSubscriptions(log *types.Log) []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var ids []string
+	event := &events.LogEvent{Log: log}
+	for id, filter := range c.logFilters {
+		if filter.MatchLog(event) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// wants reports whether the event should be delivered to the client
+func (c *Client) wants(event *Event) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if !c.subscriptions[event.Type] {
+		return false
+	}
+
+	filter, ok := c.addressFilters[event.Type]
+	if !ok {
+		return true
+	}
+	for _, addr := range event.addresses {
+		if addr == filter {
+			return true
+		}
+	}
+	return false
 }
 
 // ReadPump pumps messages from the WebSocket connection to the hub
@@ -76,9 +172,9 @@ func (c *Client) ReadPump() {
 	}()
 
 	c.conn.SetReadLimit(maxMessageSize)
-	_ = c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	_ = c.conn.SetReadDeadline(time.Now().Add(c.idleTimeout))
 	c.conn.SetPongHandler(func(string) error {
-		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+		return c.conn.SetReadDeadline(time.Now().Add(c.idleTimeout))
 	})
 
 	for {
@@ -89,6 +185,7 @@ func (c *Client) ReadPump() {
 			}
 			break
 		}
+		_ = c.conn.SetReadDeadline(time.Now().Add(c.idleTimeout))
 
 		c.handleMessage(message)
 	}
@@ -96,7 +193,7 @@ func (c *Client) ReadPump() {
 
 // WritePump pumps messages from the hub to the WebSocket connection
 func (c *Client) WritePump() {
-	ticker := time.NewTicker(pingPeriod)
+	ticker := time.NewTicker(c.pingInterval)
 	defer func() {
 		ticker.Stop()
 		c.conn.Close()
@@ -111,6 +208,9 @@ func (c *Client) WritePump() {
 				_ = c.conn.WriteMessage(websocket.CloseMessage, []byte{})
 				return
 			}
+			if message = c.maskMessage(message); message == nil {
+				continue
+			}
 
 			w, err := c.conn.NextWriter(websocket.TextMessage)
 			if err != nil {
@@ -121,8 +221,10 @@ func (c *Client) WritePump() {
 			// Add queued messages to the current websocket message
 			n := len(c.send)
 			for i := 0; i < n; i++ {
-				_, _ = w.Write([]byte{'\n'})
-				_, _ = w.Write(<-c.send)
+				if queued := c.maskMessage(<-c.send); queued != nil {
+					_, _ = w.Write([]byte{'\n'})
+					_, _ = w.Write(queued)
+				}
 			}
 
 			if err := w.Close(); err != nil {
@@ -138,6 +240,20 @@ func (c *Client) WritePump() {
 	}
 }
 
+// maskMessage applies the message mask, if any. It returns nil for a message
+// that cannot be masked, which is dropped rather than sent unmasked.
+func (c *Client) maskMessage(message []byte) []byte {
+	if c.mask == nil {
+		return message
+	}
+	masked, err := c.mask(message)
+	if err != nil {
+		c.logger.Warn("dropping websocket message that could not be masked", zap.Error(err))
+		return nil
+	}
+	return masked
+}
+
 // handleMessage handles incoming messages from the client
 func (c *Client) handleMessage(message []byte) {
 	var msg Message
@@ -169,16 +285,86 @@ func (c *Client) handleSubscribe(payload json.RawMessage) {
 	}
 
 	// Validate subscription type
-	if req.Type != SubscribeNewBlock && req.Type != SubscribeNewTransaction {
+	if req.Type != SubscribeNewBlock && req.Type != SubscribeNewTransaction && req.Type != SubscribePendingTransactions && req.Type != SubscribeLogs {
 		c.sendError("invalid subscription type")
 		return
 	}
 
-	c.Subscribe(req.Type)
+	if req.Type == SubscribeLogs {
+		c.handleSubscribeLogs(req)
+		return
+	}
+	if len(req.Topics) > 0 {
+		c.sendError("topics filter is only supported for " + string(SubscribeLogs))
+		return
+	}
+
+	if req.Address == "" {
+		c.Subscribe(req.Type)
+	} else {
+		if req.Type != SubscribePendingTransactions {
+			c.sendError("address filter is only supported for " + string(SubscribePendingTransactions))
+			return
+		}
+		if !common.IsHexAddress(req.Address) {
+			c.sendError("invalid address")
+			return
+		}
+		c.SubscribeAddress(req.Type, common.HexToAddress(req.Address))
+	}
 	c.sendSuccess("subscribed to " + string(req.Type))
 
 	c.logger.Info("client subscribed",
-		zap.String("type", string(req.Type)))
+		zap.String("type", string(req.Type)),
+		zap.String("address", req.Address))
+}
+
+// handleSubscribeLogs creates a logs subscription from the request filter
+func (c *Client) handleSubscribeLogs(req SubscribeRequest) {
+	filter := events.NewFilter()
+	if req.Address != "" {
+		if !common.IsHexAddress(req.Address) {
+			c.sendError("invalid address")
+			return
+		}
+		filter.Addresses = append(filter.Addresses, common.HexToAddress(req.Address))
+	}
+	for _, position := range req.Topics {
+		topicSet := make([]common.Hash, 0, len(position))
+		for _, topic := range position {
+			hash, err := parseTopic(topic)
+			if err != nil {
+				c.sendError(err.Error())
+				return
+			}
+			topicSet = append(topicSet, hash)
+		}
+		filter.Topics = append(filter.Topics, topicSet)
+	}
+
+	id := c.SubscribeLogs(filter)
+
+	msg := Message{
+		Type: "success",
+	}
+	payload, _ := json.Marshal(SuccessMessage{Message: "subscribed to " + string(SubscribeLogs), Subscription: id})
+	msg.Payload = payload
+	c.sendMessage(msg)
+
+	c.logger.Info("client subscribed",
+		zap.String("type", string(SubscribeLogs)),
+		zap.String("subscription", id),
+		zap.String("address", req.Address),
+		zap.Int("topics", len(req.Topics)))
+}
+
+// parseTopic parses a 32-byte hex topic
+func parseTopic(topic string) (common.Hash, error) {
+	b, err := hexutil.Decode(topic)
+	if err != nil || len(b) != common.HashLength {
+		return common.Hash{}, fmt.Errorf("invalid topic: %s", topic)
+	}
+	return common.BytesToHash(b), nil
 }
 
 // handleUnsubscribe handles unsubscribe requests
@@ -190,6 +376,15 @@ func (c *Client) handleUnsubscribe(payload json.RawMessage) {
 		return
 	}
 
+	if req.Type == SubscribeLogs && req.ID != "" {
+		if !c.UnsubscribeLogs(req.ID) {
+			c.sendError("unknown subscription: " + req.ID)
+			return
+		}
+		c.sendSuccess("unsubscribed from " + string(req.Type) + " " + req.ID)
+		return
+	}
+
 	c.Unsubscribe(req.Type)
 	c.sendSuccess("unsubscribed from " + string(req.Type))
 
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"sync"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"go.uber.org/zap"
 )
 
@@ -88,6 +90,11 @@ func (h *Hub) Run() {
 
 // broadcastEvent broadcasts an event to all subscribed clients
 func (h *Hub) broadcastEvent(event *Event) {
+	if event.Type == SubscribeLogs {
+		h.broadcastLogs(event.logs)
+		return
+	}
+
 	message := Message{
 		Type: "event",
 	}
@@ -110,7 +117,7 @@ func (h *Hub) broadcastEvent(event *Event) {
 
 	sentCount := 0
 	for client := range h.clients {
-		if client.IsSubscribed(event.Type) {
+		if client.wants(event) {
 			select {
 			case client.send <- messageBytes:
 				sentCount++
@@ -128,6 +135,82 @@ func (h *Hub) broadcastEvent(event *Event) {
 		zap.Int("recipients", sentCount))
 }
 
+// broadcastLogs sends each log to every logs subscription whose filter it matches
+func (h *Hub) broadcastLogs(logs []*types.Log) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	sentCount := 0
+	for client := range h.clients {
+		if !client.hasLogSubscriptions() {
+			continue
+		}
+	logLoop:
+		for _, log := range logs {
+			for _, id := range client.matchLogSubscriptions(log) {
+				messageBytes, err := marshalEvent(&Event{Type: SubscribeLogs, Subscription: id, Data: log})
+				if err != nil {
+					h.logger.Error("failed to marshal log event", zap.Error(err))
+					return
+				}
+				select {
+				case client.send <- messageBytes:
+					sentCount++
+				default:
+					// Client buffer full, close the connection
+					h.logger.Warn("client buffer full, closing connection")
+					close(client.send)
+					delete(h.clients, client)
+					break logLoop
+				}
+			}
+		}
+	}
+
+	h.logger.Debug("logs broadcasted",
+		zap.Int("logs", len(logs)),
+		zap.Int("deliveries", sentCount))
+}
+
+// marshalEvent wraps an event in an event message
+func marshalEvent(event *Event) ([]byte, error) {
+	eventData, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(Message{Type: "event", Payload: eventData})
+}
+
+// HasLogSubscribers reports whether any client has a logs subscription
+func (h *Hub) HasLogSubscribers() bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for client := range h.clients {
+		if client.hasLogSubscriptions() {
+			return true
+		}
+	}
+	return false
+}
+
+// BroadcastLogs delivers the logs of a newly indexed block to matching logs subscriptions
+func (h *Hub) BroadcastLogs(logs []*types.Log) {
+	if len(logs) == 0 {
+		return
+	}
+
+	event := &Event{
+		Type: SubscribeLogs,
+		logs: logs,
+	}
+
+	select {
+	case h.broadcast <- event:
+	default:
+		h.logger.Warn("broadcast channel full, dropping event")
+	}
+}
+
 // BroadcastNewBlock broadcasts a new block event
 func (h *Hub) BroadcastNewBlock(blockData interface{}) {
 	event := &Event{
@@ -156,6 +239,27 @@ func (h *Hub) BroadcastNewTransaction(txData interface{}) {
 	}
 }
 
+// BroadcastPendingTransaction broadcasts a pending transaction event
+// Clients with an address filter only receive it when the address is the sender or recipient
+func (h *Hub) BroadcastPendingTransaction(tx *PendingTransaction) {
+	addresses := []common.Address{tx.From}
+	if tx.To != nil {
+		addresses = append(addresses, *tx.To)
+	}
+
+	event := &Event{
+		Type:      SubscribePendingTransactions,
+		Data:      tx,
+		addresses: addresses,
+	}
+
+	select {
+	case h.broadcast <- event:
+	default:
+		h.logger.Warn("broadcast channel full, dropping event")
+	}
+}
+
 // ClientCount returns the number of connected clients
 func (h *Hub) ClientCount() int {
 	h.mu.RLock()
@@ -1,12 +1,35 @@
 package websocket
 
 import (
+	"context"
 	"net/http"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/gorilla/websocket"
 	"go.uber.org/zap"
+
+	"github.com/0xmhha/indexer-go/internal/constants"
+	"github.com/0xmhha/indexer-go/pkg/events"
 )
 
+const (
+	// PendingTxSubscriptionID is the EventBus subscription ID for the pending transaction feed
+	PendingTxSubscriptionID = "websocket-pending-tx"
+
+	// LogsSubscriptionID is the EventBus subscription ID for the logs feed
+	LogsSubscriptionID = "websocket-logs"
+
+	// receiptReadTimeout bounds the receipt lookup for one block of the logs feed
+	receiptReadTimeout = 5 * time.Second
+)
+
+// ReceiptReader reads the receipts stored for a block
+type ReceiptReader interface {
+	GetReceiptsByBlockNumber(ctx context.Context, blockNumber uint64) ([]*types.Receipt, error)
+}
+
 var upgrader = websocket.Upgrader{
 	ReadBufferSize:  1024,
 	WriteBufferSize: 1024,
@@ -18,8 +41,17 @@ var upgrader = websocket.Upgrader{
 
 // Server handles WebSocket connections
 type Server struct {
-	hub    *Hub
-	logger *zap.Logger
+	hub      *Hub
+	eventBus *events.EventBus
+	receipts ReceiptReader
+	logsFeed bool
+	logger   *zap.Logger
+
+	pingInterval time.Duration
+	idleTimeout  time.Duration
+
+	// mask rewrites each message before it is sent; nil sends messages as is
+	mask func(message []byte) ([]byte, error)
 }
 
 // NewServer creates a new WebSocket server
@@ -28,8 +60,10 @@ func NewServer(logger *zap.Logger) *Server {
 	go hub.Run()
 
 	return &Server{
-		hub:    hub,
-		logger: logger,
+		hub:          hub,
+		logger:       logger,
+		pingInterval: constants.DefaultWebSocketPingInterval,
+		idleTimeout:  constants.DefaultWebSocketIdleTimeout,
 	}
 }
 
@@ -42,6 +76,9 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	client := NewClient(s.hub, conn, s.logger)
+	client.pingInterval = s.pingInterval
+	client.idleTimeout = s.idleTimeout
+	client.mask = s.mask
 	s.hub.register <- client
 
 	// Start client goroutines
@@ -57,7 +94,160 @@ func (s *Server) Hub() *Hub {
 	return s.hub
 }
 
+// SetKeepAliveIntervals overrides the ping interval and idle timeout of
+// connections accepted afterwards. A zero value is left unchanged.
+func (s *Server) SetKeepAliveIntervals(pingInterval, idleTimeout time.Duration) {
+	if pingInterval > 0 {
+		s.pingInterval = pingInterval
+	}
+	if idleTimeout > 0 {
+		s.idleTimeout = idleTimeout
+	}
+}
+
+// SetMessageMask rewrites every message sent on connections accepted
+// afterwards with mask, e.g. to hide fields and addresses. A message that
+// cannot be masked is dropped rather than sent as is.
+func (s *Server) SetMessageMask(mask func(message []byte) ([]byte, error)) {
+	s.mask = mask
+}
+
+// SetReceiptReader enables the logs feed, which reads the receipts of each
+// newly indexed block. It must be called before SubscribeToEventBus.
+func (s *Server) SetReceiptReader(reader ReceiptReader) {
+	s.receipts = reader
+}
+
+// SubscribeToEventBus forwards pending transaction events from the EventBus to
+// pendingTransactions subscribers. Pending transactions are not stored, so the
+// feed is a pass-through of what the fetcher's node subscription publishes.
+// With a receipt reader set, block events also drive the logs feed.
+func (s *Server) SubscribeToEventBus(bus *events.EventBus) {
+	if bus == nil || s.eventBus != nil {
+		return
+	}
+
+	sub := bus.Subscribe(
+		events.SubscriptionID(PendingTxSubscriptionID),
+		[]events.EventType{events.EventTypeTransaction},
+		nil,
+		256,
+	)
+	if sub == nil {
+		s.logger.Warn("failed to subscribe websocket server to EventBus")
+		return
+	}
+	s.eventBus = bus
+
+	go s.forwardPendingTransactions(sub)
+
+	if s.receipts == nil {
+		return
+	}
+	logsSub := bus.Subscribe(
+		events.SubscriptionID(LogsSubscriptionID),
+		[]events.EventType{events.EventTypeBlock},
+		nil,
+		256,
+	)
+	if logsSub == nil {
+		s.logger.Warn("failed to subscribe websocket logs feed to EventBus")
+		return
+	}
+	s.logsFeed = true
+
+	go s.forwardLogs(logsSub)
+}
+
+// forwardPendingTransactions broadcasts pending transaction events until the subscription closes
+func (s *Server) forwardPendingTransactions(sub *events.Subscription) {
+	for event := range sub.Channel {
+		txEvent, ok := event.(*events.TransactionEvent)
+		if !ok {
+			continue
+		}
+
+		// Only pending transactions (BlockNumber == 0) belong to this feed
+		if txEvent.BlockNumber != 0 {
+			continue
+		}
+
+		s.hub.BroadcastPendingTransaction(&PendingTransaction{
+			Hash: txEvent.Hash,
+			From: txEvent.From,
+			To:   txEvent.To,
+		})
+	}
+}
+
+// forwardLogs broadcasts the logs of each indexed block until the subscription closes
+func (s *Server) forwardLogs(sub *events.Subscription) {
+	for event := range sub.Channel {
+		blockEvent, ok := event.(*events.BlockEvent)
+		if !ok || blockEvent.Block == nil {
+			continue
+		}
+
+		// Skip the receipt lookup when nobody is listening
+		if !s.hub.HasLogSubscribers() {
+			continue
+		}
+
+		logs, err := s.blockLogs(blockEvent.Block)
+		if err != nil {
+			s.logger.Warn("failed to read receipts for logs feed",
+				zap.Uint64("block", blockEvent.Number),
+				zap.Error(err))
+			continue
+		}
+		s.hub.BroadcastLogs(logs)
+	}
+}
+
+// blockLogs returns the logs of a block's stored receipts. Stored receipts keep
+// only the consensus fields of their logs, so the block and transaction
+// positions are filled in from the block.
+func (s *Server) blockLogs(block *types.Block) ([]*types.Log, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), receiptReadTimeout)
+	defer cancel()
+
+	receipts, err := s.receipts.GetReceiptsByBlockNumber(ctx, block.NumberU64())
+	if err != nil {
+		return nil, err
+	}
+
+	txIndex := make(map[common.Hash]uint, len(block.Transactions()))
+	for i, tx := range block.Transactions() {
+		txIndex[tx.Hash()] = uint(i)
+	}
+
+	var logs []*types.Log
+	var logIndex uint
+	for _, receipt := range receipts {
+		for _, stored := range receipt.Logs {
+			if stored == nil {
+				continue
+			}
+			log := *stored
+			log.BlockNumber = block.NumberU64()
+			log.BlockHash = block.Hash()
+			log.TxHash = receipt.TxHash
+			log.TxIndex = txIndex[receipt.TxHash]
+			log.Index = logIndex
+			logIndex++
+			logs = append(logs, &log)
+		}
+	}
+	return logs, nil
+}
+
 // Stop stops the WebSocket server
 func (s *Server) Stop() {
+	if s.eventBus != nil {
+		s.eventBus.Unsubscribe(events.SubscriptionID(PendingTxSubscriptionID))
+		if s.logsFeed {
+			s.eventBus.Unsubscribe(events.SubscriptionID(LogsSubscriptionID))
+		}
+	}
 	s.hub.Stop()
 }
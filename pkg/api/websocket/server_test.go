@@ -1,16 +1,23 @@
 package websocket
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/trie"
 	"github.com/gorilla/websocket"
 	"go.uber.org/zap"
+
+	"github.com/0xmhha/indexer-go/pkg/events"
 )
 
 func TestWebSocketServer(t *testing.T) {
@@ -690,3 +697,388 @@ func TestClient(t *testing.T) {
 
 	time.Sleep(200 * time.Millisecond)
 }
+
+func TestKeepAlive(t *testing.T) {
+	server := NewServer(zap.NewNop())
+	defer server.Stop()
+	server.SetKeepAliveIntervals(50*time.Millisecond, 200*time.Millisecond)
+
+	ts := httptest.NewServer(http.HandlerFunc(server.ServeHTTP))
+	defer ts.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(ts.URL, "http")
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer conn.Close()
+
+	payload, _ := json.Marshal(SubscribeRequest{Type: SubscribeNewBlock})
+	if err := conn.WriteJSON(Message{Type: "subscribe", Payload: payload}); err != nil {
+		t.Fatalf("failed to send subscribe: %v", err)
+	}
+	var resp Message
+	if err := conn.ReadJSON(&resp); err != nil {
+		t.Fatalf("failed to read response: %v", err)
+	}
+
+	// Stop answering pings; the server must drop the client
+	conn.SetPingHandler(func(string) error { return nil })
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			if netErr, ok := err.(interface{ Timeout() bool }); ok && netErr.Timeout() {
+				t.Fatal("expected the server to close the connection")
+			}
+			break
+		}
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for server.Hub().ClientCount() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected 0 clients, got %d", server.Hub().ClientCount())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestMessageMask(t *testing.T) {
+	server := NewServer(zap.NewNop())
+	defer server.Stop()
+	server.SetMessageMask(func(message []byte) ([]byte, error) {
+		if strings.Contains(string(message), `"error"`) {
+			return nil, fmt.Errorf("cannot mask")
+		}
+		return []byte(strings.Replace(string(message), "subscribed to", "[redacted]", 1)), nil
+	})
+
+	ts := httptest.NewServer(http.HandlerFunc(server.ServeHTTP))
+	defer ts.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(ts.URL, "http")
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer conn.Close()
+
+	// The error reply to an unknown message type cannot be masked and is dropped
+	if err := conn.WriteJSON(Message{Type: "unknown"}); err != nil {
+		t.Fatalf("failed to send message: %v", err)
+	}
+	payload, _ := json.Marshal(SubscribeRequest{Type: SubscribeNewBlock})
+	if err := conn.WriteJSON(Message{Type: "subscribe", Payload: payload}); err != nil {
+		t.Fatalf("failed to send subscribe: %v", err)
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, data, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatalf("failed to read response: %v", err)
+	}
+	if strings.Contains(string(data), `"error"`) {
+		t.Errorf("unmaskable message was sent: %s", data)
+	}
+	if !strings.Contains(string(data), "[redacted]") || strings.Contains(string(data), "subscribed to") {
+		t.Errorf("response = %s, want it masked", data)
+	}
+}
+
+// readPendingHashes reads pendingTransactions events until the deadline passes.
+// Queued messages may be batched into a single frame separated by newlines.
+func readPendingHashes(t *testing.T, conn *websocket.Conn, wait time.Duration) []common.Hash {
+	t.Helper()
+	var hashes []common.Hash
+	_ = conn.SetReadDeadline(time.Now().Add(wait))
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return hashes
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			var msg Message
+			if err := json.Unmarshal([]byte(line), &msg); err != nil || msg.Type != "event" {
+				continue
+			}
+			var event struct {
+				Type SubscriptionType   `json:"type"`
+				Data PendingTransaction `json:"data"`
+			}
+			if err := json.Unmarshal(msg.Payload, &event); err != nil {
+				t.Fatalf("failed to unmarshal event: %v", err)
+			}
+			if event.Type == SubscribePendingTransactions {
+				hashes = append(hashes, event.Data.Hash)
+			}
+		}
+	}
+}
+
+func TestPendingTransactionFeed(t *testing.T) {
+	bus := events.NewEventBus(100, 100)
+	go bus.Run()
+	defer bus.Stop()
+
+	server := NewServer(zap.NewNop())
+	server.SubscribeToEventBus(bus)
+	defer server.Stop()
+
+	ts := httptest.NewServer(http.HandlerFunc(server.ServeHTTP))
+	defer ts.Close()
+	wsURL := "ws" + strings.TrimPrefix(ts.URL, "http")
+
+	watched := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	other := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+
+	subscribe := func(req SubscribeRequest) (*websocket.Conn, Message) {
+		conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+		if err != nil {
+			t.Fatalf("failed to connect: %v", err)
+		}
+		payload, _ := json.Marshal(req)
+		if err := conn.WriteJSON(Message{Type: "subscribe", Payload: payload}); err != nil {
+			t.Fatalf("failed to send subscribe: %v", err)
+		}
+		var resp Message
+		if err := conn.ReadJSON(&resp); err != nil {
+			t.Fatalf("failed to read response: %v", err)
+		}
+		return conn, resp
+	}
+
+	filtered, resp := subscribe(SubscribeRequest{Type: SubscribePendingTransactions, Address: watched.Hex()})
+	defer filtered.Close()
+	if resp.Type != "success" {
+		t.Fatalf("expected success for filtered subscribe, got %s", resp.Type)
+	}
+	unfiltered, resp := subscribe(SubscribeRequest{Type: SubscribePendingTransactions})
+	defer unfiltered.Close()
+	if resp.Type != "success" {
+		t.Fatalf("expected success for unfiltered subscribe, got %s", resp.Type)
+	}
+
+	unrelatedTx := types.NewTransaction(0, other, big.NewInt(1), 21000, big.NewInt(1), nil)
+	watchedTx := types.NewTransaction(1, watched, big.NewInt(1), 21000, big.NewInt(1), nil)
+	minedTx := types.NewTransaction(2, watched, big.NewInt(1), 21000, big.NewInt(1), nil)
+
+	bus.Publish(events.NewTransactionEvent(unrelatedTx, 0, common.Hash{}, 0, other, nil))
+	bus.Publish(events.NewTransactionEvent(watchedTx, 0, common.Hash{}, 0, other, nil))
+	// Mined transactions are not part of the pending feed
+	bus.Publish(events.NewTransactionEvent(minedTx, 5, common.HexToHash("0x05"), 0, other, nil))
+
+	got := readPendingHashes(t, filtered, 500*time.Millisecond)
+	if len(got) != 1 || got[0] != watchedTx.Hash() {
+		t.Errorf("filtered client got %v, want only %s", got, watchedTx.Hash().Hex())
+	}
+
+	got = readPendingHashes(t, unfiltered, 500*time.Millisecond)
+	if len(got) != 2 || got[0] != unrelatedTx.Hash() || got[1] != watchedTx.Hash() {
+		t.Errorf("unfiltered client got %v, want both pending hashes", got)
+	}
+
+	t.Run("InvalidAddress", func(t *testing.T) {
+		conn, resp := subscribe(SubscribeRequest{Type: SubscribePendingTransactions, Address: "0x1234"})
+		defer conn.Close()
+		if resp.Type != "error" {
+			t.Errorf("expected error response, got %s", resp.Type)
+		}
+	})
+
+	t.Run("AddressFilterOnUnsupportedType", func(t *testing.T) {
+		conn, resp := subscribe(SubscribeRequest{Type: SubscribeNewBlock, Address: watched.Hex()})
+		defer conn.Close()
+		if resp.Type != "error" {
+			t.Errorf("expected error response, got %s", resp.Type)
+		}
+	})
+}
+
+// blockReceipts is a ReceiptReader serving receipts per block number
+type blockReceipts map[uint64][]*types.Receipt
+
+func (r blockReceipts) GetReceiptsByBlockNumber(_ context.Context, blockNumber uint64) ([]*types.Receipt, error) {
+	return r[blockNumber], nil
+}
+
+type logEvent struct {
+	Subscription string
+	Log          types.Log
+}
+
+// readLogEvents reads logs events until want have arrived or the deadline passes.
+// With want <= 0 it reads until the deadline, which leaves the connection unusable.
+func readLogEvents(t *testing.T, conn *websocket.Conn, want int, wait time.Duration) []logEvent {
+	t.Helper()
+	var got []logEvent
+	_ = conn.SetReadDeadline(time.Now().Add(wait))
+	defer func() { _ = conn.SetReadDeadline(time.Time{}) }()
+	for want <= 0 || len(got) < want {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return got
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			var msg Message
+			if err := json.Unmarshal([]byte(line), &msg); err != nil || msg.Type != "event" {
+				continue
+			}
+			var event struct {
+				Type         SubscriptionType `json:"type"`
+				Subscription string           `json:"subscription"`
+				Data         types.Log        `json:"data"`
+			}
+			if err := json.Unmarshal(msg.Payload, &event); err != nil {
+				t.Fatalf("failed to unmarshal event: %v", err)
+			}
+			if event.Type == SubscribeLogs {
+				got = append(got, logEvent{Subscription: event.Subscription, Log: event.Data})
+			}
+		}
+	}
+	return got
+}
+
+func TestLogsFeed(t *testing.T) {
+	contractA := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	contractB := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+	contractC := common.HexToAddress("0x00000000000000000000000000000000000000cc")
+	transfer := common.HexToHash("0x01")
+	approval := common.HexToHash("0x02")
+
+	newLog := func(address common.Address, topic common.Hash) *types.Log {
+		return &types.Log{Address: address, Topics: []common.Hash{topic}, Data: []byte{}}
+	}
+	newBlock := func(number uint64, txs ...*types.Transaction) *types.Block {
+		header := &types.Header{Number: new(big.Int).SetUint64(number), Difficulty: big.NewInt(0)}
+		return types.NewBlock(header, &types.Body{Transactions: txs}, nil, trie.NewStackTrie(nil))
+	}
+
+	tx1 := types.NewTransaction(0, contractA, big.NewInt(0), 21000, big.NewInt(1), nil)
+	tx2 := types.NewTransaction(1, contractB, big.NewInt(0), 21000, big.NewInt(1), nil)
+	tx3 := types.NewTransaction(2, contractA, big.NewInt(0), 21000, big.NewInt(1), nil)
+	block1 := newBlock(1, tx1, tx2)
+	block2 := newBlock(2, tx3)
+
+	reader := blockReceipts{
+		1: {
+			{TxHash: tx1.Hash(), Logs: []*types.Log{newLog(contractA, transfer), newLog(contractB, transfer)}},
+			{TxHash: tx2.Hash(), Logs: []*types.Log{newLog(contractB, approval), newLog(contractC, approval)}},
+		},
+		2: {
+			{TxHash: tx3.Hash(), Logs: []*types.Log{newLog(contractA, approval), newLog(contractB, approval)}},
+		},
+	}
+
+	bus := events.NewEventBus(100, 100)
+	go bus.Run()
+	defer bus.Stop()
+
+	server := NewServer(zap.NewNop())
+	server.SetReceiptReader(reader)
+	server.SubscribeToEventBus(bus)
+	defer server.Stop()
+
+	ts := httptest.NewServer(http.HandlerFunc(server.ServeHTTP))
+	defer ts.Close()
+	wsURL := "ws" + strings.TrimPrefix(ts.URL, "http")
+
+	dial := func() *websocket.Conn {
+		conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+		if err != nil {
+			t.Fatalf("failed to connect: %v", err)
+		}
+		t.Cleanup(func() { conn.Close() })
+		return conn
+	}
+	send := func(conn *websocket.Conn, msgType string, req interface{}) Message {
+		payload, _ := json.Marshal(req)
+		if err := conn.WriteJSON(Message{Type: msgType, Payload: payload}); err != nil {
+			t.Fatalf("failed to send %s: %v", msgType, err)
+		}
+		var resp Message
+		if err := conn.ReadJSON(&resp); err != nil {
+			t.Fatalf("failed to read response: %v", err)
+		}
+		return resp
+	}
+	conn := dial()
+	subscribe := func(req SubscribeRequest) string {
+		resp := send(conn, "subscribe", req)
+		if resp.Type != "success" {
+			t.Fatalf("expected success response, got %s: %s", resp.Type, resp.Payload)
+		}
+		var success SuccessMessage
+		if err := json.Unmarshal(resp.Payload, &success); err != nil {
+			t.Fatalf("failed to unmarshal success: %v", err)
+		}
+		if success.Subscription == "" {
+			t.Fatal("logs subscription has no ID")
+		}
+		return success.Subscription
+	}
+
+	// Two independent subscriptions on one connection
+	byAddress := subscribe(SubscribeRequest{Type: SubscribeLogs, Address: contractA.Hex()})
+	byTopic := subscribe(SubscribeRequest{
+		Type:    SubscribeLogs,
+		Address: contractB.Hex(),
+		Topics:  [][]string{{approval.Hex()}},
+	})
+	if byAddress == byTopic {
+		t.Fatalf("subscriptions share ID %s", byAddress)
+	}
+
+	bus.Publish(events.NewBlockEvent(block1))
+	got := readLogEvents(t, conn, 2, 2*time.Second)
+	if len(got) != 2 {
+		t.Fatalf("got %d log events, want 2: %+v", len(got), got)
+	}
+	for _, event := range got {
+		switch event.Subscription {
+		case byAddress:
+			if event.Log.Address != contractA || event.Log.TxHash != tx1.Hash() || event.Log.Index != 0 {
+				t.Errorf("address subscription got %+v", event.Log)
+			}
+		case byTopic:
+			if event.Log.Address != contractB || event.Log.Topics[0] != approval ||
+				event.Log.TxIndex != 1 || event.Log.Index != 2 || event.Log.BlockNumber != 1 {
+				t.Errorf("topic subscription got %+v", event.Log)
+			}
+		default:
+			t.Errorf("unexpected subscription %s", event.Subscription)
+		}
+	}
+
+	// Cancelling one subscription leaves the other running
+	if resp := send(conn, "unsubscribe", UnsubscribeRequest{Type: SubscribeLogs, ID: byAddress}); resp.Type != "success" {
+		t.Fatalf("expected success response, got %s", resp.Type)
+	}
+	bus.Publish(events.NewBlockEvent(block2))
+	// Read to the deadline so non-matching or stale deliveries would show up
+	got = readLogEvents(t, conn, 0, 500*time.Millisecond)
+	if len(got) != 1 || got[0].Subscription != byTopic || got[0].Log.BlockHash != block2.Hash() {
+		t.Errorf("after unsubscribe got %+v, want one log for %s", got, byTopic)
+	}
+
+	t.Run("InvalidTopic", func(t *testing.T) {
+		resp := send(dial(), "subscribe", SubscribeRequest{Type: SubscribeLogs, Topics: [][]string{{"0x1234"}}})
+		if resp.Type != "error" {
+			t.Errorf("expected error response, got %s", resp.Type)
+		}
+	})
+
+	t.Run("TopicsOnUnsupportedType", func(t *testing.T) {
+		resp := send(dial(), "subscribe", SubscribeRequest{Type: SubscribeNewBlock, Topics: [][]string{{transfer.Hex()}}})
+		if resp.Type != "error" {
+			t.Errorf("expected error response, got %s", resp.Type)
+		}
+	})
+
+	t.Run("UnknownSubscription", func(t *testing.T) {
+		resp := send(dial(), "unsubscribe", UnsubscribeRequest{Type: SubscribeLogs, ID: "0xff"})
+		if resp.Type != "error" {
+			t.Errorf("expected error response, got %s", resp.Type)
+		}
+	})
+}
@@ -2,6 +2,9 @@ package websocket
 
 import (
 	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 )
 
 // SubscriptionType represents the type of subscription
@@ -13,6 +16,12 @@ const (
 
 	// SubscribeNewTransaction subscribes to new transaction events
 	SubscribeNewTransaction SubscriptionType = "newTransaction"
+
+	// SubscribePendingTransactions subscribes to pending transaction hashes from the node's mempool
+	SubscribePendingTransactions SubscriptionType = "pendingTransactions"
+
+	// SubscribeLogs subscribes to logs of newly indexed blocks that match a filter
+	SubscribeLogs SubscriptionType = "logs"
 )
 
 // Message represents a WebSocket message
@@ -22,19 +31,41 @@ type Message struct {
 }
 
 // SubscribeRequest represents a subscription request
+// Address optionally restricts pendingTransactions events to transactions
+// sent from or to that address, and logs events to logs emitted by that contract.
+// Topics filters logs events by position with eth_subscribe semantics: an empty
+// position matches anything, otherwise any of the listed topics.
 type SubscribeRequest struct {
-	Type SubscriptionType `json:"type"`
+	Type    SubscriptionType `json:"type"`
+	Address string           `json:"address,omitempty"`
+	Topics  [][]string       `json:"topics,omitempty"`
 }
 
 // UnsubscribeRequest represents an unsubscribe request
+// ID cancels a single logs subscription; without it every logs subscription is cancelled
 type UnsubscribeRequest struct {
 	Type SubscriptionType `json:"type"`
+	ID   string           `json:"id,omitempty"`
 }
 
 // Event represents a subscription event
 type Event struct {
 	Type SubscriptionType `json:"type"`
-	Data interface{}      `json:"data"`
+	// Subscription is the logs subscription the event was matched for
+	Subscription string      `json:"subscription,omitempty"`
+	Data         interface{} `json:"data"`
+
+	// addresses are the accounts the event touches, used for address filters
+	addresses []common.Address
+	// logs are the logs of one block, matched per logs subscription
+	logs []*types.Log
+}
+
+// PendingTransaction is the payload of a pendingTransactions event
+type PendingTransaction struct {
+	Hash common.Hash     `json:"hash"`
+	From common.Address  `json:"from"`
+	To   *common.Address `json:"to,omitempty"`
 }
 
 // ErrorMessage represents an error message
@@ -43,6 +74,8 @@ type ErrorMessage struct {
 }
 
 // SuccessMessage represents a success message
+// Subscription is set when a logs subscription is created
 type SuccessMessage struct {
-	Message string `json:"message"`
+	Message      string `json:"message"`
+	Subscription string `json:"subscription,omitempty"`
 }
@@ -2,8 +2,11 @@ package client
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
+	"net/http"
 	"time"
 
 	"github.com/ethereum/go-ethereum"
@@ -14,12 +17,27 @@ import (
 	"go.uber.org/zap"
 )
 
+// ErrBatchNotSupported is returned by batch requests when the RPC endpoint
+// does not accept JSON-RPC batches
+var ErrBatchNotSupported = errors.New("JSON-RPC batch requests not supported by endpoint")
+
+// JSON-RPC error codes a node returns for every element of a batch it rejects
+const (
+	rpcCodeInvalidRequest = -32600
+	rpcCodeMethodNotFound = -32601
+)
+
 // Client wraps Ethereum JSON-RPC client with additional functionality
 type Client struct {
 	ethClient *ethclient.Client
 	rpcClient *rpc.Client
 	endpoint  string
 	logger    *zap.Logger
+	retry     *RetryConfig
+
+	// timeout and methodTimeouts bound each call attempt (see callTimeout)
+	timeout        time.Duration
+	methodTimeouts map[string]time.Duration
 }
 
 // BatchReceiptError represents an error for a single receipt in a batch operation
@@ -50,8 +68,20 @@ func (r *BatchReceiptResult) AllSucceeded() bool {
 // Config holds client configuration
 type Config struct {
 	Endpoint string
-	Timeout  time.Duration
-	Logger   *zap.Logger
+
+	// Timeout bounds connecting and each RPC call attempt of methods without
+	// an entry in MethodTimeouts (0 = no limit)
+	Timeout time.Duration
+
+	// MethodTimeouts overrides Timeout for individual JSON-RPC methods, such as
+	// a longer limit for debug_traceBlockByNumber than for eth_getBlockByNumber.
+	// Non-positive entries are ignored.
+	MethodTimeouts map[string]time.Duration
+
+	Logger *zap.Logger
+
+	// Retry configures backoff for transient RPC failures (nil uses DefaultRetryConfig)
+	Retry *RetryConfig
 }
 
 // NewClient creates a new Ethereum client
@@ -84,11 +114,26 @@ func NewClient(cfg *Config) (*Client, error) {
 
 	ethClient := ethclient.NewClient(rpcClient)
 
+	retry := cfg.Retry
+	if retry == nil {
+		retry = DefaultRetryConfig()
+	}
+
+	methodTimeouts := make(map[string]time.Duration, len(cfg.MethodTimeouts))
+	for method, timeout := range cfg.MethodTimeouts {
+		if timeout > 0 {
+			methodTimeouts[method] = timeout
+		}
+	}
+
 	client := &Client{
-		ethClient: ethClient,
-		rpcClient: rpcClient,
-		endpoint:  cfg.Endpoint,
-		logger:    logger,
+		ethClient:      ethClient,
+		rpcClient:      rpcClient,
+		endpoint:       cfg.Endpoint,
+		logger:         logger,
+		retry:          retry,
+		timeout:        cfg.Timeout,
+		methodTimeouts: methodTimeouts,
 	}
 
 	// Verify connection
@@ -128,7 +173,11 @@ func (c *Client) RPCClient() *rpc.Client {
 
 // GetLatestBlockNumber returns the latest block number
 func (c *Client) GetLatestBlockNumber(ctx context.Context) (uint64, error) {
-	blockNumber, err := c.ethClient.BlockNumber(ctx)
+	var blockNumber uint64
+	err := c.withRetry(ctx, "eth_blockNumber", func(ctx context.Context) (err error) {
+		blockNumber, err = c.ethClient.BlockNumber(ctx)
+		return err
+	})
 	if err != nil {
 		return 0, fmt.Errorf("failed to get latest block number: %w", err)
 	}
@@ -138,7 +187,11 @@ func (c *Client) GetLatestBlockNumber(ctx context.Context) (uint64, error) {
 // GetBlockByNumber fetches a block by its number
 func (c *Client) GetBlockByNumber(ctx context.Context, number uint64) (*types.Block, error) {
 	blockNum := new(big.Int).SetUint64(number)
-	block, err := c.ethClient.BlockByNumber(ctx, blockNum)
+	var block *types.Block
+	err := c.withRetry(ctx, "eth_getBlockByNumber", func(ctx context.Context) (err error) {
+		block, err = c.ethClient.BlockByNumber(ctx, blockNum)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get block %d: %w", number, err)
 	}
@@ -147,7 +200,11 @@ func (c *Client) GetBlockByNumber(ctx context.Context, number uint64) (*types.Bl
 
 // GetBlockByHash fetches a block by its hash
 func (c *Client) GetBlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
-	block, err := c.ethClient.BlockByHash(ctx, hash)
+	var block *types.Block
+	err := c.withRetry(ctx, "eth_getBlockByHash", func(ctx context.Context) (err error) {
+		block, err = c.ethClient.BlockByHash(ctx, hash)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get block %s: %w", hash.Hex(), err)
 	}
@@ -156,7 +213,12 @@ func (c *Client) GetBlockByHash(ctx context.Context, hash common.Hash) (*types.B
 
 // GetTransactionByHash fetches a transaction by its hash
 func (c *Client) GetTransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
-	tx, isPending, err := c.ethClient.TransactionByHash(ctx, hash)
+	var tx *types.Transaction
+	var isPending bool
+	err := c.withRetry(ctx, "eth_getTransactionByHash", func(ctx context.Context) (err error) {
+		tx, isPending, err = c.ethClient.TransactionByHash(ctx, hash)
+		return err
+	})
 	if err != nil {
 		return nil, false, fmt.Errorf("failed to get transaction %s: %w", hash.Hex(), err)
 	}
@@ -165,7 +227,11 @@ func (c *Client) GetTransactionByHash(ctx context.Context, hash common.Hash) (*t
 
 // GetTransactionReceipt fetches a transaction receipt
 func (c *Client) GetTransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
-	receipt, err := c.ethClient.TransactionReceipt(ctx, hash)
+	var receipt *types.Receipt
+	err := c.withRetry(ctx, "eth_getTransactionReceipt", func(ctx context.Context) (err error) {
+		receipt, err = c.ethClient.TransactionReceipt(ctx, hash)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get receipt for %s: %w", hash.Hex(), err)
 	}
@@ -177,7 +243,11 @@ func (c *Client) GetBlockReceipts(ctx context.Context, blockNumber uint64) (type
 	blockNum := new(big.Int).SetUint64(blockNumber)
 
 	// Use BlockReceipts method from ethclient
-	receipts, err := c.ethClient.BlockReceipts(ctx, rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(blockNum.Int64())))
+	var receipts []*types.Receipt
+	err := c.withRetry(ctx, "eth_getBlockReceipts", func(ctx context.Context) (err error) {
+		receipts, err = c.ethClient.BlockReceipts(ctx, rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(blockNum.Int64())))
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get receipts for block %d: %w", blockNumber, err)
 	}
@@ -187,7 +257,11 @@ func (c *Client) GetBlockReceipts(ctx context.Context, blockNumber uint64) (type
 
 // GetChainID returns the chain ID
 func (c *Client) GetChainID(ctx context.Context) (*big.Int, error) {
-	chainID, err := c.ethClient.ChainID(ctx)
+	var chainID *big.Int
+	err := c.withRetry(ctx, "eth_chainId", func(ctx context.Context) (err error) {
+		chainID, err = c.ethClient.ChainID(ctx)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get chain ID: %w", err)
 	}
@@ -196,17 +270,39 @@ func (c *Client) GetChainID(ctx context.Context) (*big.Int, error) {
 
 // GetNetworkID returns the network ID
 func (c *Client) GetNetworkID(ctx context.Context) (*big.Int, error) {
-	networkID, err := c.ethClient.NetworkID(ctx)
+	var networkID *big.Int
+	err := c.withRetry(ctx, "net_version", func(ctx context.Context) (err error) {
+		networkID, err = c.ethClient.NetworkID(ctx)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get network ID: %w", err)
 	}
 	return networkID, nil
 }
 
+// SyncProgress returns the node's sync progress as reported by eth_syncing,
+// or nil if the node is not syncing
+func (c *Client) SyncProgress(ctx context.Context) (*ethereum.SyncProgress, error) {
+	var progress *ethereum.SyncProgress
+	err := c.withRetry(ctx, "eth_syncing", func(ctx context.Context) (err error) {
+		progress, err = c.ethClient.SyncProgress(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sync progress: %w", err)
+	}
+	return progress, nil
+}
+
 // BalanceAt returns the balance of an account at a specific block number
 // If blockNumber is nil, returns the balance at the latest block
 func (c *Client) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
-	balance, err := c.ethClient.BalanceAt(ctx, account, blockNumber)
+	var balance *big.Int
+	err := c.withRetry(ctx, "eth_getBalance", func(ctx context.Context) (err error) {
+		balance, err = c.ethClient.BalanceAt(ctx, account, blockNumber)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get balance for %s at block %v: %w", account.Hex(), blockNumber, err)
 	}
@@ -228,22 +324,31 @@ func (c *Client) BatchGetBlocks(ctx context.Context, numbers []uint64) ([]*types
 		return nil, nil
 	}
 
-	blocks := make([]*types.Block, len(numbers))
+	raws := make([]json.RawMessage, len(numbers))
 	batch := make([]rpc.BatchElem, len(numbers))
 
 	for i, num := range numbers {
 		batch[i] = rpc.BatchElem{
 			Method: "eth_getBlockByNumber",
 			Args:   []interface{}{fmt.Sprintf("0x%x", num), true}, // true to include transactions
-			Result: &blocks[i],
+			Result: &raws[i],
 		}
 	}
 
-	if err := c.rpcClient.BatchCallContext(ctx, batch); err != nil {
+	if err := c.withRetry(ctx, "eth_getBlockByNumber(batch)", func(ctx context.Context) error {
+		return c.rpcClient.BatchCallContext(ctx, batch)
+	}); err != nil {
+		if isBatchRejectedError(err) {
+			return nil, fmt.Errorf("%w: %v", ErrBatchNotSupported, err)
+		}
 		return nil, fmt.Errorf("batch call failed: %w", err)
 	}
+	if batchRejected(batch) {
+		return nil, fmt.Errorf("%w: %v", ErrBatchNotSupported, batch[0].Error)
+	}
 
 	// Check for individual errors
+	blocks := make([]*types.Block, len(numbers))
 	for i, elem := range batch {
 		if elem.Error != nil {
 			c.logger.Error("failed to fetch block in batch",
@@ -251,11 +356,109 @@ func (c *Client) BatchGetBlocks(ctx context.Context, numbers []uint64) ([]*types
 				zap.Error(elem.Error))
 			return nil, fmt.Errorf("failed to fetch block %d: %w", numbers[i], elem.Error)
 		}
+
+		block, err := decodeRPCBlock(raws[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode block %d: %w", numbers[i], err)
+		}
+		// Uncle headers are not part of the block response; fetch such blocks on their own
+		if block.UncleHash() != types.EmptyUncleHash {
+			if block, err = c.GetBlockByNumber(ctx, numbers[i]); err != nil {
+				return nil, err
+			}
+		}
+		blocks[i] = block
 	}
 
 	return blocks, nil
 }
 
+// GetBlocksByRange fetches the blocks in [from, to] with one batched
+// eth_getBlockByNumber request and returns them in height order. It returns
+// an error wrapping ErrBatchNotSupported when the endpoint rejects batches.
+func (c *Client) GetBlocksByRange(ctx context.Context, from, to uint64) ([]*types.Block, error) {
+	if to < from {
+		return nil, fmt.Errorf("invalid block range %d-%d", from, to)
+	}
+
+	numbers := make([]uint64, 0, to-from+1)
+	for num := from; num <= to; num++ {
+		numbers = append(numbers, num)
+	}
+	return c.BatchGetBlocks(ctx, numbers)
+}
+
+// rpcBlockBody is the part of an eth_getBlockByNumber result outside the header
+type rpcBlockBody struct {
+	Transactions []*types.Transaction `json:"transactions"`
+	UncleHashes  []common.Hash        `json:"uncles"`
+	Withdrawals  []*types.Withdrawal  `json:"withdrawals,omitempty"`
+}
+
+// decodeRPCBlock decodes a block returned with full transactions. Uncle
+// headers are not included in the result and are left empty.
+func decodeRPCBlock(raw json.RawMessage) (*types.Block, error) {
+	var head *types.Header
+	if err := json.Unmarshal(raw, &head); err != nil {
+		return nil, err
+	}
+	// A block that does not exist is returned as JSON null
+	if head == nil {
+		return nil, ethereum.NotFound
+	}
+
+	var body rpcBlockBody
+	if err := json.Unmarshal(raw, &body); err != nil {
+		return nil, err
+	}
+	if head.TxHash == types.EmptyTxsHash && len(body.Transactions) > 0 {
+		return nil, errors.New("server returned non-empty transaction list but block header indicates no transactions")
+	}
+	if head.TxHash != types.EmptyTxsHash && len(body.Transactions) == 0 {
+		return nil, errors.New("server returned empty transaction list but block header indicates transactions")
+	}
+
+	return types.NewBlockWithHeader(head).WithBody(types.Body{
+		Transactions: body.Transactions,
+		Withdrawals:  body.Withdrawals,
+	}), nil
+}
+
+// isBatchRejectedError reports whether a failed batch call means the endpoint
+// does not accept batches: a client error status, or a response that is not
+// a JSON array
+func isBatchRejectedError(err error) bool {
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode >= http.StatusBadRequest &&
+			httpErr.StatusCode < http.StatusInternalServerError &&
+			!IsRetryableError(err)
+	}
+	var typeErr *json.UnmarshalTypeError
+	return errors.As(err, &typeErr)
+}
+
+// batchRejected reports whether every element of a batch failed because the
+// endpoint refused the batch as a whole rather than the individual calls
+func batchRejected(batch []rpc.BatchElem) bool {
+	for _, elem := range batch {
+		if elem.Error == nil {
+			return false
+		}
+		if errors.Is(elem.Error, rpc.ErrMissingBatchResponse) {
+			continue
+		}
+		var rpcErr rpc.Error
+		if !errors.As(elem.Error, &rpcErr) {
+			return false
+		}
+		if code := rpcErr.ErrorCode(); code != rpcCodeInvalidRequest && code != rpcCodeMethodNotFound {
+			return false
+		}
+	}
+	return len(batch) > 0
+}
+
 // BatchGetReceiptsWithDetails fetches multiple transaction receipts and returns detailed results
 // including partial successes and individual error tracking
 func (c *Client) BatchGetReceiptsWithDetails(ctx context.Context, hashes []common.Hash) (*BatchReceiptResult, error) {
@@ -278,7 +481,9 @@ func (c *Client) BatchGetReceiptsWithDetails(ctx context.Context, hashes []commo
 		}
 	}
 
-	if err := c.rpcClient.BatchCallContext(ctx, batch); err != nil {
+	if err := c.withRetry(ctx, "eth_getTransactionReceipt(batch)", func(ctx context.Context) error {
+		return c.rpcClient.BatchCallContext(ctx, batch)
+	}); err != nil {
 		return nil, fmt.Errorf("batch call failed: %w", err)
 	}
 
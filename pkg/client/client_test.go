@@ -3,17 +3,21 @@ package client
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
@@ -529,6 +533,123 @@ func TestClient_BalanceAt(t *testing.T) {
 	})
 }
 
+// ---- Tests: TraceTransaction ----
+
+func TestClient_TraceTransaction(t *testing.T) {
+	txHash := common.HexToHash("0xabc")
+
+	t.Run("reverted call", func(t *testing.T) {
+		client := newTestClient(t, map[string]methodHandler{
+			"debug_traceTransaction": func(params json.RawMessage) (json.RawMessage, *jrpcError) {
+				var args []json.RawMessage
+				if err := json.Unmarshal(params, &args); err != nil || len(args) != 2 {
+					return nil, &jrpcError{Code: -32602, Message: "invalid params"}
+				}
+				if string(args[0]) != `"`+txHash.Hex()+`"` {
+					return nil, &jrpcError{Code: -32602, Message: "unexpected hash " + string(args[0])}
+				}
+				return json.RawMessage(`{
+					"type": "CALL",
+					"from": "0x0000000000000000000000000000000000000001",
+					"to": "0x0000000000000000000000000000000000000002",
+					"gas": "0x5208",
+					"gasUsed": "0x5208",
+					"output": "0x08c379a0",
+					"error": "execution reverted"
+				}`), nil
+			},
+		})
+		frame, err := client.TraceTransaction(context.Background(), txHash)
+		require.NoError(t, err)
+		assert.Equal(t, "execution reverted", frame.Error)
+		assert.Equal(t, []byte{0x08, 0xc3, 0x79, 0xa0}, []byte(frame.Output))
+	})
+
+	t.Run("error", func(t *testing.T) {
+		client := newTestClient(t, map[string]methodHandler{
+			"debug_traceTransaction": rpcErrorHandler("transaction not found"),
+		})
+		_, err := client.TraceTransaction(context.Background(), txHash)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to trace transaction")
+	})
+}
+
+// ---- Tests: Per-Method Timeouts ----
+
+// delayedHandler answers with result after delay
+func delayedHandler(delay time.Duration, result string) methodHandler {
+	return func(_ json.RawMessage) (json.RawMessage, *jrpcError) {
+		time.Sleep(delay)
+		return json.RawMessage(result), nil
+	}
+}
+
+func TestClient_MethodTimeouts(t *testing.T) {
+	server := newMockRPCServer(t, map[string]methodHandler{
+		"eth_chainId":              chainIDHandler(),
+		"eth_blockNumber":          delayedHandler(300*time.Millisecond, `"0x2a"`),
+		"eth_getBlockByNumber":     delayedHandler(300*time.Millisecond, string(makeBlockJSON(1))),
+		"debug_traceBlockByNumber": delayedHandler(300*time.Millisecond, `[]`),
+	})
+	client, err := NewClient(&Config{
+		Endpoint: server.URL,
+		Timeout:  150 * time.Millisecond,
+		MethodTimeouts: map[string]time.Duration{
+			"eth_blockNumber":          30 * time.Millisecond,
+			"debug_traceBlockByNumber": 2 * time.Second,
+			"eth_getBalance":           0,
+		},
+		Retry: &RetryConfig{},
+	})
+	require.NoError(t, err)
+	defer client.Close()
+
+	// elapsed runs fn and returns how long it took and its error
+	elapsed := func(fn func(ctx context.Context) error) (time.Duration, error) {
+		start := time.Now()
+		err := fn(context.Background())
+		return time.Since(start), err
+	}
+
+	t.Run("method timeout shorter than default", func(t *testing.T) {
+		took, err := elapsed(func(ctx context.Context) error {
+			_, err := client.GetLatestBlockNumber(ctx)
+			return err
+		})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, took, 150*time.Millisecond, "eth_blockNumber should use its 30ms timeout")
+	})
+
+	t.Run("default timeout", func(t *testing.T) {
+		took, err := elapsed(func(ctx context.Context) error {
+			_, err := client.GetBlockByNumber(ctx, 1)
+			return err
+		})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.GreaterOrEqual(t, took, 150*time.Millisecond)
+		assert.Less(t, took, 300*time.Millisecond)
+	})
+
+	t.Run("method timeout longer than default", func(t *testing.T) {
+		took, err := elapsed(func(ctx context.Context) error {
+			_, err := client.TraceBlockByNumber(ctx, 1)
+			return err
+		})
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, took, 300*time.Millisecond)
+	})
+
+	t.Run("batch uses method timeout", func(t *testing.T) {
+		assert.Equal(t, 30*time.Millisecond, client.callTimeout("eth_blockNumber(batch)"))
+		assert.Equal(t, 150*time.Millisecond, client.callTimeout("eth_getTransactionReceipt(batch)"))
+	})
+
+	t.Run("non-positive entries fall back to default", func(t *testing.T) {
+		assert.Equal(t, 150*time.Millisecond, client.callTimeout("eth_getBalance"))
+	})
+}
+
 // ---- Tests: BatchGetBlocks ----
 
 func TestClient_BatchGetBlocks(t *testing.T) {
@@ -591,6 +712,144 @@ func TestClient_BatchGetBlocks(t *testing.T) {
 	})
 }
 
+// ---- Tests: GetBlocksByRange ----
+
+// blockByNumberHandler answers eth_getBlockByNumber with a block of the requested number
+func blockByNumberHandler(params json.RawMessage) (json.RawMessage, *jrpcError) {
+	var args []json.RawMessage
+	json.Unmarshal(params, &args)
+	var num uint64
+	fmt.Sscanf(strings.Trim(string(args[0]), `"`), "0x%x", &num)
+	return makeBlockJSON(num), nil
+}
+
+// newRawTestClient returns a client for a server whose HTTP handling is
+// entirely up to handler
+func newRawTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	rpcClient, err := rpc.DialContext(context.Background(), server.URL)
+	require.NoError(t, err)
+	t.Cleanup(rpcClient.Close)
+
+	return &Client{
+		ethClient: ethclient.NewClient(rpcClient),
+		rpcClient: rpcClient,
+		endpoint:  server.URL,
+		logger:    zap.NewNop(),
+	}
+}
+
+func TestClient_GetBlocksByRange(t *testing.T) {
+	t.Run("one batched request in height order", func(t *testing.T) {
+		var requests, batchSize atomic.Int64
+		handlers := map[string]methodHandler{"eth_getBlockByNumber": blockByNumberHandler}
+		client := newRawTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			var reqs []jrpcRequest
+			body, _ := io.ReadAll(r.Body)
+			if err := json.Unmarshal(body, &reqs); err != nil {
+				http.Error(w, "expected a batch", http.StatusBadRequest)
+				return
+			}
+			batchSize.Store(int64(len(reqs)))
+			responses := make([]jrpcResponse, len(reqs))
+			for i, req := range reqs {
+				responses[i] = dispatchRequest(req, handlers)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(responses)
+		})
+
+		blocks, err := client.GetBlocksByRange(context.Background(), 5, 8)
+		require.NoError(t, err)
+		require.Len(t, blocks, 4)
+		for i, block := range blocks {
+			assert.Equal(t, uint64(5+i), block.NumberU64())
+		}
+		assert.Equal(t, int64(1), requests.Load())
+		assert.Equal(t, int64(4), batchSize.Load())
+	})
+
+	t.Run("invalid range", func(t *testing.T) {
+		client := newTestClient(t, map[string]methodHandler{})
+		_, err := client.GetBlocksByRange(context.Background(), 8, 5)
+		assert.Error(t, err)
+	})
+
+	t.Run("missing block", func(t *testing.T) {
+		client := newTestClient(t, map[string]methodHandler{
+			"eth_getBlockByNumber": func(params json.RawMessage) (json.RawMessage, *jrpcError) {
+				if strings.Contains(string(params), `"0x2"`) {
+					return json.RawMessage("null"), nil
+				}
+				return blockByNumberHandler(params)
+			},
+		})
+		_, err := client.GetBlocksByRange(context.Background(), 1, 3)
+		assert.ErrorIs(t, err, ethereum.NotFound)
+		assert.False(t, errors.Is(err, ErrBatchNotSupported))
+	})
+
+	t.Run("element error is not a rejected batch", func(t *testing.T) {
+		client := newTestClient(t, map[string]methodHandler{
+			"eth_getBlockByNumber": rpcErrorHandler("header not found"),
+		})
+		_, err := client.GetBlocksByRange(context.Background(), 1, 2)
+		require.Error(t, err)
+		assert.False(t, errors.Is(err, ErrBatchNotSupported))
+		assert.Contains(t, err.Error(), "failed to fetch block 1")
+	})
+}
+
+func TestClient_GetBlocksByRange_BatchNotSupported(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{
+			name: "single error object instead of array",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprint(w, `{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"batch requests not supported"}}`)
+			},
+		},
+		{
+			name: "client error status",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "batch requests not supported", http.StatusBadRequest)
+			},
+		},
+		{
+			name: "every element rejected",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				var reqs []jrpcRequest
+				body, _ := io.ReadAll(r.Body)
+				json.Unmarshal(body, &reqs)
+				responses := make([]jrpcResponse, len(reqs))
+				for i, req := range reqs {
+					responses[i] = jrpcResponse{
+						JSONRPC: "2.0",
+						ID:      req.ID,
+						Error:   &jrpcError{Code: -32600, Message: "batch requests not supported"},
+					}
+				}
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(responses)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newRawTestClient(t, tt.handler)
+			_, err := client.GetBlocksByRange(context.Background(), 1, 3)
+			assert.ErrorIs(t, err, ErrBatchNotSupported)
+		})
+	}
+}
+
 // ---- Tests: BatchGetReceiptsWithDetails ----
 
 func TestClient_BatchGetReceiptsWithDetails(t *testing.T) {
@@ -732,3 +991,82 @@ func TestClient_BatchGetReceipts(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+// newPendingTxNode starts a fake WebSocket node that acknowledges a
+// newPendingTransactions subscription and then emits the given hashes
+func newPendingTxNode(t *testing.T, hashes []common.Hash) string {
+	t.Helper()
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		for {
+			var req jrpcRequest
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+			// NewClient pings the node with eth_chainId before subscribing
+			if req.Method == "eth_chainId" {
+				_ = conn.WriteJSON(jrpcResponse{JSONRPC: "2.0", ID: req.ID, Result: json.RawMessage(`"0x1"`)})
+				continue
+			}
+			if req.Method != "eth_subscribe" || !strings.Contains(string(req.Params), "newPendingTransactions") {
+				_ = conn.WriteJSON(jrpcResponse{JSONRPC: "2.0", ID: req.ID, Error: &jrpcError{Code: -32601, Message: "method not found"}})
+				continue
+			}
+
+			_ = conn.WriteJSON(jrpcResponse{JSONRPC: "2.0", ID: req.ID, Result: json.RawMessage(`"0xcafe"`)})
+			for _, hash := range hashes {
+				_ = conn.WriteJSON(map[string]interface{}{
+					"jsonrpc": "2.0",
+					"method":  "eth_subscription",
+					"params":  map[string]interface{}{"subscription": "0xcafe", "result": hash},
+				})
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func TestClient_SubscribePendingTransactions(t *testing.T) {
+	hashes := []common.Hash{
+		common.HexToHash("0x01"),
+		common.HexToHash("0x02"),
+		common.HexToHash("0x03"),
+	}
+	endpoint := newPendingTxNode(t, hashes)
+
+	client, err := NewClient(&Config{Endpoint: endpoint, Timeout: 5 * time.Second})
+	require.NoError(t, err)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ch, sub, err := client.SubscribePendingTransactions(ctx)
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	for i, want := range hashes {
+		select {
+		case got := <-ch:
+			assert.Equal(t, want, got, "hash %d", i)
+		case err := <-sub.Err():
+			t.Fatalf("subscription error: %v", err)
+		case <-ctx.Done():
+			t.Fatalf("timed out waiting for pending hash %d", i)
+		}
+	}
+}
+
+func TestClient_SubscribePendingTransactions_HTTPUnsupported(t *testing.T) {
+	client := newTestClient(t, map[string]methodHandler{})
+
+	_, _, err := client.SubscribePendingTransactions(context.Background())
+	assert.Error(t, err)
+}
@@ -0,0 +1,196 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/rpc"
+	"go.uber.org/zap"
+
+	"github.com/0xmhha/indexer-go/internal/constants"
+)
+
+// JSON-RPC error codes that indicate a transient server-side condition
+const (
+	RPCCodeInternalError = -32603
+	// RPCCodeLimitExceeded is the code nodes use when a request limit is hit
+	RPCCodeLimitExceeded = -32005
+)
+
+// RetryConfig holds retry behavior for RPC calls
+type RetryConfig struct {
+	// MaxRetries is the maximum number of retries after the initial attempt (0 or less disables retries)
+	MaxRetries int
+
+	// BaseDelay is the delay before the first retry
+	BaseDelay time.Duration
+
+	// MaxDelay caps the delay between retries
+	MaxDelay time.Duration
+
+	// Multiplier is the factor applied to the delay after each failed attempt
+	Multiplier float64
+
+	// Jitter is the fraction of each delay (0.0-1.0) that is randomized
+	// to spread out retries from concurrent callers
+	Jitter float64
+}
+
+// DefaultRetryConfig returns the default retry configuration
+func DefaultRetryConfig() *RetryConfig {
+	return &RetryConfig{
+		MaxRetries: 3,
+		BaseDelay:  constants.InitialRetryDelay,
+		MaxDelay:   constants.MaxRetryDelay,
+		Multiplier: 2.0,
+		Jitter:     0.5,
+	}
+}
+
+// backoff returns the delay before the given retry attempt (1-based)
+func (r *RetryConfig) backoff(attempt int) time.Duration {
+	multiplier := r.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	delay := float64(r.BaseDelay)
+	for i := 1; i < attempt; i++ {
+		delay *= multiplier
+		if r.MaxDelay > 0 && delay >= float64(r.MaxDelay) {
+			break
+		}
+	}
+	if r.MaxDelay > 0 && delay > float64(r.MaxDelay) {
+		delay = float64(r.MaxDelay)
+	}
+
+	// Equal jitter: keep (1-Jitter) of the delay fixed and randomize the rest,
+	// so delays still grow with each attempt while concurrent callers spread out
+	jitter := r.Jitter
+	if jitter > 1 {
+		jitter = 1
+	}
+	if jitter > 0 {
+		fixed := delay * (1 - jitter)
+		delay = fixed + rand.Float64()*(delay-fixed)
+	}
+
+	return time.Duration(delay)
+}
+
+// IsRetryableError reports whether an RPC error is transient and worth retrying.
+// Timeouts, connection failures, HTTP 429 and 5xx responses are retryable;
+// JSON-RPC application errors (e.g. method not found) and not-found results are not.
+func IsRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	// Caller cancellation is never retried; deadline errors are handled by the caller's context
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	if errors.Is(err, ethereum.NotFound) {
+		return false
+	}
+
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusTooManyRequests ||
+			httpErr.StatusCode == http.StatusRequestTimeout ||
+			httpErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		switch rpcErr.ErrorCode() {
+		case RPCCodeInternalError, RPCCodeLimitExceeded:
+			return true
+		default:
+			return false
+		}
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+
+	return false
+}
+
+// withRetry executes fn, retrying retryable errors with exponential backoff and jitter.
+// Each attempt gets its own context bounded by the method's timeout, so a timed
+// out attempt is retried while the caller's context is still live.
+func (c *Client) withRetry(ctx context.Context, method string, fn func(ctx context.Context) error) error {
+	timeout := c.callTimeout(method)
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = callWithTimeout(ctx, timeout, fn)
+		if err == nil {
+			return nil
+		}
+
+		if c.retry == nil || attempt >= c.retry.MaxRetries || !IsRetryableError(err) {
+			return err
+		}
+		if ctx.Err() != nil {
+			return err
+		}
+
+		delay := c.retry.backoff(attempt + 1)
+		c.logger.Debug("retrying RPC call",
+			zap.String("method", method),
+			zap.Int("attempt", attempt+1),
+			zap.Int("max_retries", c.retry.MaxRetries),
+			zap.Duration("delay", delay),
+			zap.Error(err),
+		)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// callTimeout returns the per-attempt timeout of an RPC method: its
+// MethodTimeouts entry, or the client Timeout. Batch calls, logged as
+// "method(batch)", use the timeout of their method.
+func (c *Client) callTimeout(method string) time.Duration {
+	if timeout, ok := c.methodTimeouts[strings.TrimSuffix(method, "(batch)")]; ok {
+		return timeout
+	}
+	return c.timeout
+}
+
+// callWithTimeout runs fn with ctx bounded by timeout (0 = no limit)
+func callWithTimeout(ctx context.Context, timeout time.Duration, fn func(ctx context.Context) error) error {
+	if timeout <= 0 {
+		return fn(ctx)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return fn(ctx)
+}
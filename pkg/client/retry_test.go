@@ -0,0 +1,226 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// flakyServer fails the first `failures` requests with the given HTTP status,
+// then answers eth_blockNumber successfully. It records the arrival time of each request.
+type flakyServer struct {
+	mu       sync.Mutex
+	failures int
+	status   int
+	calls    []time.Time
+}
+
+func (s *flakyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	defer r.Body.Close()
+
+	s.mu.Lock()
+	s.calls = append(s.calls, time.Now())
+	n := len(s.calls)
+	s.mu.Unlock()
+
+	if n <= s.failures {
+		http.Error(w, "unavailable", s.status)
+		return
+	}
+
+	var req jrpcRequest
+	_ = json.Unmarshal(body, &req)
+	w.Header().Set("Content-Type", "application/json")
+	resp := jrpcResponse{JSONRPC: "2.0", ID: req.ID}
+	if req.Method == "eth_blockNumber" {
+		resp.Result = json.RawMessage(`"0x2a"`)
+	} else {
+		resp.Error = &jrpcError{Code: -32601, Message: "method not found"}
+	}
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (s *flakyServer) callTimes() []time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]time.Time(nil), s.calls...)
+}
+
+func newRetryTestClient(t *testing.T, handler http.Handler, retry *RetryConfig) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	rpcClient, err := rpc.DialContext(context.Background(), server.URL)
+	require.NoError(t, err)
+	t.Cleanup(rpcClient.Close)
+
+	return &Client{
+		ethClient: ethclient.NewClient(rpcClient),
+		rpcClient: rpcClient,
+		endpoint:  server.URL,
+		logger:    zap.NewNop(),
+		retry:     retry,
+	}
+}
+
+func TestWithRetry_SucceedsAfterTransientFailures(t *testing.T) {
+	server := &flakyServer{failures: 3, status: http.StatusServiceUnavailable}
+	c := newRetryTestClient(t, server, &RetryConfig{
+		MaxRetries: 5,
+		BaseDelay:  20 * time.Millisecond,
+		MaxDelay:   time.Second,
+		Multiplier: 2,
+		Jitter:     0,
+	})
+
+	height, err := c.GetLatestBlockNumber(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(42), height)
+
+	calls := server.callTimes()
+	require.Len(t, calls, 4)
+
+	// Gaps between attempts must grow with each retry
+	var prev time.Duration
+	for i := 1; i < len(calls); i++ {
+		gap := calls[i].Sub(calls[i-1])
+		assert.Greater(t, gap, prev, "retry gap %d should be larger than the previous one", i)
+		prev = gap
+	}
+	assert.GreaterOrEqual(t, prev, 80*time.Millisecond)
+}
+
+func TestWithRetry_TooManyRequestsIsRetried(t *testing.T) {
+	server := &flakyServer{failures: 1, status: http.StatusTooManyRequests}
+	c := newRetryTestClient(t, server, &RetryConfig{
+		MaxRetries: 2,
+		BaseDelay:  time.Millisecond,
+		MaxDelay:   10 * time.Millisecond,
+		Multiplier: 2,
+	})
+
+	_, err := c.GetLatestBlockNumber(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, server.callTimes(), 2)
+}
+
+func TestWithRetry_GivesUpAfterMaxRetries(t *testing.T) {
+	server := &flakyServer{failures: 100, status: http.StatusBadGateway}
+	c := newRetryTestClient(t, server, &RetryConfig{
+		MaxRetries: 2,
+		BaseDelay:  time.Millisecond,
+		MaxDelay:   10 * time.Millisecond,
+		Multiplier: 2,
+	})
+
+	_, err := c.GetLatestBlockNumber(context.Background())
+	require.Error(t, err)
+	assert.Len(t, server.callTimes(), 3)
+}
+
+func TestWithRetry_NegativeMaxRetriesDisablesRetries(t *testing.T) {
+	server := &flakyServer{failures: 100, status: http.StatusBadGateway}
+	c := newRetryTestClient(t, server, &RetryConfig{
+		MaxRetries: -1,
+		BaseDelay:  time.Millisecond,
+		MaxDelay:   10 * time.Millisecond,
+		Multiplier: 2,
+	})
+
+	_, err := c.GetLatestBlockNumber(context.Background())
+	require.Error(t, err)
+	assert.Len(t, server.callTimes(), 1)
+}
+
+func TestWithRetry_NonRetryableFailsFast(t *testing.T) {
+	server := &flakyServer{}
+	c := newRetryTestClient(t, server, &RetryConfig{
+		MaxRetries: 5,
+		BaseDelay:  time.Second,
+		MaxDelay:   10 * time.Second,
+		Multiplier: 2,
+	})
+
+	start := time.Now()
+	_, err := c.GetChainID(context.Background())
+	require.Error(t, err)
+	assert.Len(t, server.callTimes(), 1)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
+
+func TestWithRetry_StopsOnContextCancel(t *testing.T) {
+	server := &flakyServer{failures: 100, status: http.StatusServiceUnavailable}
+	c := newRetryTestClient(t, server, &RetryConfig{
+		MaxRetries: 10,
+		BaseDelay:  time.Second,
+		MaxDelay:   10 * time.Second,
+		Multiplier: 2,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := c.GetLatestBlockNumber(ctx)
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
+
+func TestRetryConfig_Backoff(t *testing.T) {
+	cfg := &RetryConfig{
+		BaseDelay:  100 * time.Millisecond,
+		MaxDelay:   time.Second,
+		Multiplier: 2,
+	}
+
+	assert.Equal(t, 100*time.Millisecond, cfg.backoff(1))
+	assert.Equal(t, 200*time.Millisecond, cfg.backoff(2))
+	assert.Equal(t, 400*time.Millisecond, cfg.backoff(3))
+	assert.Equal(t, time.Second, cfg.backoff(10), "delay should be capped at MaxDelay")
+
+	cfg.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		d := cfg.backoff(3)
+		assert.GreaterOrEqual(t, d, 200*time.Millisecond)
+		assert.LessOrEqual(t, d, 400*time.Millisecond)
+	}
+}
+
+func TestIsRetryableError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"canceled", context.Canceled, false},
+		{"deadline", context.DeadlineExceeded, true},
+		{"not found", ethereum.NotFound, false},
+		{"http 429", rpc.HTTPError{StatusCode: http.StatusTooManyRequests}, true},
+		{"http 503", rpc.HTTPError{StatusCode: http.StatusServiceUnavailable}, true},
+		{"http 400", rpc.HTTPError{StatusCode: http.StatusBadRequest}, false},
+		{"eof", io.ErrUnexpectedEOF, true},
+		{"unknown", errors.New("boom"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsRetryableError(tt.err))
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// CallFrame is a single call in a callTracer result
+type CallFrame struct {
+	Type    string          `json:"type"`
+	From    common.Address  `json:"from"`
+	To      *common.Address `json:"to,omitempty"`
+	Value   *hexutil.Big    `json:"value,omitempty"`
+	Gas     hexutil.Uint64  `json:"gas"`
+	GasUsed hexutil.Uint64  `json:"gasUsed"`
+	Input   hexutil.Bytes   `json:"input,omitempty"`
+	Output  hexutil.Bytes   `json:"output,omitempty"`
+	Error   string          `json:"error,omitempty"`
+	Calls   []CallFrame     `json:"calls,omitempty"`
+}
+
+// TxTraceResult is the callTracer result for one transaction in a block trace
+// TxHash is only populated by nodes that include it in debug_traceBlockByNumber results
+type TxTraceResult struct {
+	TxHash common.Hash `json:"txHash"`
+	Result *CallFrame  `json:"result"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// TraceBlockByNumber traces all transactions of a block with the callTracer
+// Results are returned in transaction order. Requires the node to expose the debug namespace.
+func (c *Client) TraceBlockByNumber(ctx context.Context, blockNumber uint64) ([]*TxTraceResult, error) {
+	var results []*TxTraceResult
+	err := c.withRetry(ctx, "debug_traceBlockByNumber", func(ctx context.Context) error {
+		return c.rpcClient.CallContext(ctx, &results, "debug_traceBlockByNumber",
+			hexutil.EncodeUint64(blockNumber), map[string]interface{}{"tracer": "callTracer"})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to trace block %d: %w", blockNumber, err)
+	}
+	return results, nil
+}
+
+// TraceTransaction traces a single transaction with the callTracer and returns
+// its top-level call frame. Requires the node to expose the debug namespace.
+func (c *Client) TraceTransaction(ctx context.Context, hash common.Hash) (*CallFrame, error) {
+	var result *CallFrame
+	err := c.withRetry(ctx, "debug_traceTransaction", func(ctx context.Context) error {
+		return c.rpcClient.CallContext(ctx, &result, "debug_traceTransaction",
+			hash, map[string]interface{}{"tracer": "callTracer"})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to trace transaction %s: %w", hash.Hex(), err)
+	}
+	if result == nil {
+		return nil, fmt.Errorf("no trace for transaction %s", hash.Hex())
+	}
+	return result, nil
+}
@@ -5,6 +5,8 @@ import (
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"github.com/0xmhha/indexer-go/internal/constants"
 )
 
 // Default configuration values
@@ -38,7 +40,7 @@ type SubscribeOptions struct {
 	ReplayLast int
 
 	// ChannelSize is the buffer size for the subscription channel
-	// Default is 100 if not specified
+	// Defaults to the bus's subscribe buffer size if not specified
 	ChannelSize int
 }
 
@@ -87,6 +89,9 @@ type EventBus struct {
 	// publishCh is the channel for publishing events
 	publishCh chan Event
 
+	// subscribeBufferSize is the channel size of subscriptions that do not set one
+	subscribeBufferSize int
+
 	// done signals when the event bus should stop
 	done chan struct{}
 
@@ -114,9 +119,13 @@ type EventBus struct {
 }
 
 // NewEventBus creates a new EventBus with the given buffer sizes
-// subscribeBufferSize is kept for backward compatibility but no longer used
+// subscribeBufferSize is used for subscriptions that do not request a channel size
 func NewEventBus(publishBufferSize, subscribeBufferSize int) *EventBus {
-	return NewEventBusWithHistory(publishBufferSize, DefaultEventHistorySize)
+	eb := NewEventBusWithHistory(publishBufferSize, DefaultEventHistorySize)
+	if subscribeBufferSize > 0 {
+		eb.subscribeBufferSize = subscribeBufferSize
+	}
+	return eb
 }
 
 // NewEventBusWithHistory creates a new EventBus with configurable history size
@@ -128,17 +137,28 @@ func NewEventBusWithHistory(publishBufferSize, historySize int) *EventBus {
 	}
 
 	return &EventBus{
-		subscribers:      make(map[SubscriptionID]*Subscription),
-		publishCh:        make(chan Event, publishBufferSize),
-		done:             make(chan struct{}),
-		ctx:              ctx,
-		cancel:           cancel,
-		eventHistory:     make([]eventHistoryEntry, historySize),
-		eventHistorySize: historySize,
-		eventHistoryIdx:  0,
+		subscribers:         make(map[SubscriptionID]*Subscription),
+		publishCh:           make(chan Event, publishBufferSize),
+		subscribeBufferSize: constants.DefaultSubscribeBufferSize,
+		done:                make(chan struct{}),
+		ctx:                 ctx,
+		cancel:              cancel,
+		eventHistory:        make([]eventHistoryEntry, historySize),
+		eventHistorySize:    historySize,
+		eventHistoryIdx:     0,
 	}
 }
 
+// PublishBufferSize returns the capacity of the publish channel
+func (eb *EventBus) PublishBufferSize() int {
+	return cap(eb.publishCh)
+}
+
+// SubscribeBufferSize returns the channel size given to subscriptions that do not set one
+func (eb *EventBus) SubscribeBufferSize() int {
+	return eb.subscribeBufferSize
+}
+
 // SetMetrics enables Prometheus metrics for the EventBus
 // This is optional - if not called, metrics will not be collected
 func (eb *EventBus) SetMetrics(metrics *Metrics) {
@@ -153,23 +173,40 @@ func (eb *EventBus) Run() {
 	for {
 		select {
 		case <-eb.ctx.Done():
-			// Shutdown: close all subscriptions
+			// Shutdown: deliver what was already published, then close all subscriptions
+			eb.drainPublished()
 			eb.closeAllSubscriptions()
 			return
 
 		case event := <-eb.publishCh:
-			// Handle event publishing
-			eb.stats.totalEvents.Add(1)
+			eb.handlePublished(event)
+		}
+	}
+}
 
-			// Store in history for replay
-			eb.storeEventInHistory(event)
+// handlePublished records a published event and broadcasts it to subscribers
+func (eb *EventBus) handlePublished(event Event) {
+	eb.stats.totalEvents.Add(1)
 
-			// Record metrics
-			if eb.metrics != nil {
-				eb.metrics.RecordEventPublished(event.Type())
-			}
+	// Store in history for replay
+	eb.storeEventInHistory(event)
+
+	// Record metrics
+	if eb.metrics != nil {
+		eb.metrics.RecordEventPublished(event.Type())
+	}
+
+	eb.broadcastEvent(event)
+}
 
-			eb.broadcastEvent(event)
+// drainPublished broadcasts the events still queued when the bus stops
+func (eb *EventBus) drainPublished() {
+	for {
+		select {
+		case event := <-eb.publishCh:
+			eb.handlePublished(event)
+		default:
+			return
 		}
 	}
 }
@@ -305,12 +342,24 @@ func (eb *EventBus) closeAllSubscriptions() {
 	eb.subscribers = make(map[SubscriptionID]*Subscription)
 }
 
-// Stop gracefully stops the event bus
+// Stop gracefully stops the event bus, delivering events already published
 func (eb *EventBus) Stop() {
 	eb.cancel()
 	<-eb.done
 }
 
+// StopContext stops the event bus like Stop, but stops waiting for queued
+// events to be delivered once ctx is done and returns its error
+func (eb *EventBus) StopContext(ctx context.Context) error {
+	eb.cancel()
+	select {
+	case <-eb.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // SubscriberCount returns the current number of active subscribers
 func (eb *EventBus) SubscriberCount() int {
 	eb.mu.RLock()
@@ -386,7 +435,7 @@ func (eb *EventBus) SubscribeWithOptions(id SubscriptionID, eventTypes []EventTy
 	// Apply default channel size
 	channelSize := opts.ChannelSize
 	if channelSize <= 0 {
-		channelSize = 100
+		channelSize = eb.subscribeBufferSize
 	}
 
 	// Create subscription context for cancellation
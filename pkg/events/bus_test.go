@@ -1,6 +1,8 @@
 package events
 
 import (
+	"context"
+	"errors"
 	"math/big"
 	"testing"
 	"time"
@@ -363,3 +365,88 @@ func TestDefaultSubscribeOptions(t *testing.T) {
 		t.Errorf("expected ChannelSize 100, got %d", opts.ChannelSize)
 	}
 }
+
+func TestEventBus_CustomBufferSizes(t *testing.T) {
+	bus := NewEventBus(4096, 512)
+	go bus.Run()
+	defer bus.Stop()
+
+	if got := bus.PublishBufferSize(); got != 4096 {
+		t.Errorf("expected publish buffer size 4096, got %d", got)
+	}
+	if got := bus.SubscribeBufferSize(); got != 512 {
+		t.Errorf("expected subscribe buffer size 512, got %d", got)
+	}
+
+	// Subscriptions without a channel size get the bus's subscribe buffer size
+	sub := bus.Subscribe("default-size", []EventType{EventTypeBlock}, nil, 0)
+	if sub == nil {
+		t.Fatal("subscription should not be nil")
+	}
+	if got := cap(sub.Channel); got != 512 {
+		t.Errorf("expected subscription channel size 512, got %d", got)
+	}
+
+	// An explicit channel size still takes precedence
+	sub = bus.Subscribe("explicit-size", []EventType{EventTypeBlock}, nil, 8)
+	if sub == nil {
+		t.Fatal("subscription should not be nil")
+	}
+	if got := cap(sub.Channel); got != 8 {
+		t.Errorf("expected subscription channel size 8, got %d", got)
+	}
+
+	// Non-positive sizes fall back to the default
+	if got := NewEventBus(1000, 0).SubscribeBufferSize(); got != 100 {
+		t.Errorf("expected default subscribe buffer size 100, got %d", got)
+	}
+}
+
+func TestEventBus_StopDeliversQueuedEvents(t *testing.T) {
+	bus := NewEventBus(100, 10)
+	sub := bus.Subscribe("drain", []EventType{EventTypeBlock}, nil, 10)
+	if sub == nil {
+		t.Fatal("subscription should not be nil")
+	}
+
+	// Queue events before the bus runs, then stop it
+	for i := int64(1); i <= 3; i++ {
+		block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(i)})
+		if !bus.Publish(NewBlockEvent(block)) {
+			t.Fatalf("publish %d should succeed", i)
+		}
+	}
+	go bus.Run()
+	bus.Stop()
+
+	received := 0
+	for range sub.Channel {
+		received++
+	}
+	if received != 3 {
+		t.Errorf("expected 3 queued events delivered before close, got %d", received)
+	}
+}
+
+func TestEventBus_StopContextTimeout(t *testing.T) {
+	// A bus that never runs never finishes stopping
+	bus := NewEventBus(100, 10)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if err := bus.StopContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("StopContext took %v, expected to return at the deadline", elapsed)
+	}
+
+	// A running bus stops well within the deadline
+	bus = NewEventBus(100, 10)
+	go bus.Run()
+	if err := bus.StopContext(context.Background()); err != nil {
+		t.Errorf("expected nil error, got %v", err)
+	}
+}
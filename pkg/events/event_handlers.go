@@ -107,6 +107,8 @@ func (t *EventTransformer) ToMintEvent(event *ParsedEvent) (*storage.MintEvent,
 		Minter:      minter,
 		To:          to,
 		Amount:      amount,
+		TxIndex:     eventTxIndex(event),
+		LogIndex:    uint64(event.LogIndex),
 	}, nil
 }
 
@@ -130,9 +132,19 @@ func (t *EventTransformer) ToBurnEvent(event *ParsedEvent) (*storage.BurnEvent,
 		Burner:       burner,
 		Amount:       amount,
 		WithdrawalID: withdrawalID,
+		TxIndex:      eventTxIndex(event),
+		LogIndex:     uint64(event.LogIndex),
 	}, nil
 }
 
+// eventTxIndex returns the transaction index of the event's raw log, if any
+func eventTxIndex(event *ParsedEvent) uint64 {
+	if event.RawLog == nil {
+		return 0
+	}
+	return uint64(event.RawLog.TxIndex)
+}
+
 // ToProposal converts a ParsedEvent to storage.Proposal
 func (t *EventTransformer) ToProposal(event *ParsedEvent) (*storage.Proposal, error) {
 	proposalID, ok := event.Data["proposalId"].(*big.Int)
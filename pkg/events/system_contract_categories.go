@@ -0,0 +1,141 @@
+package events
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// SystemEventCategory groups system contract events that can be excluded from
+// indexing together
+type SystemEventCategory string
+
+const (
+	// SystemEventCategoryMint covers Mint and DepositMintProposed
+	SystemEventCategoryMint SystemEventCategory = "mint"
+	// SystemEventCategoryBurn covers Burn, BurnPrepaid and BurnExecuted
+	SystemEventCategoryBurn SystemEventCategory = "burn"
+	// SystemEventCategoryMinter covers minter configuration and allowance changes
+	SystemEventCategoryMinter SystemEventCategory = "minter"
+	// SystemEventCategoryProposal covers the proposal lifecycle and governance parameters
+	SystemEventCategoryProposal SystemEventCategory = "proposal"
+	// SystemEventCategoryVote covers ProposalVoted
+	SystemEventCategoryVote SystemEventCategory = "vote"
+	// SystemEventCategoryMember covers member changes outside GovValidator
+	SystemEventCategoryMember SystemEventCategory = "member"
+	// SystemEventCategoryValidator covers member changes of GovValidator
+	SystemEventCategoryValidator SystemEventCategory = "validator"
+	// SystemEventCategoryGasTip covers GasTipUpdated
+	SystemEventCategoryGasTip SystemEventCategory = "gas_tip"
+	// SystemEventCategoryBlacklist covers AddressBlacklisted and AddressUnblacklisted
+	SystemEventCategoryBlacklist SystemEventCategory = "blacklist"
+	// SystemEventCategoryAuthorizedAccount covers AuthorizedAccountAdded and AuthorizedAccountRemoved
+	SystemEventCategoryAuthorizedAccount SystemEventCategory = "authorized_account"
+	// SystemEventCategoryEmergencyPause covers EmergencyPaused and EmergencyUnpaused
+	SystemEventCategoryEmergencyPause SystemEventCategory = "emergency_pause"
+)
+
+// systemEventCategories maps event signatures to their category. Member events
+// are resolved per contract in systemEventCategoryOf.
+var systemEventCategories = map[common.Hash]SystemEventCategory{
+	EventSigMint:                SystemEventCategoryMint,
+	EventSigDepositMintProposed: SystemEventCategoryMint,
+
+	EventSigBurn:         SystemEventCategoryBurn,
+	EventSigBurnPrepaid:  SystemEventCategoryBurn,
+	EventSigBurnExecuted: SystemEventCategoryBurn,
+
+	EventSigMinterConfigured:          SystemEventCategoryMinter,
+	EventSigMinterRemoved:             SystemEventCategoryMinter,
+	EventSigMasterMinterChanged:       SystemEventCategoryMinter,
+	EventSigMaxMinterAllowanceUpdated: SystemEventCategoryMinter,
+
+	EventSigProposalCreated:              SystemEventCategoryProposal,
+	EventSigProposalApproved:             SystemEventCategoryProposal,
+	EventSigProposalRejected:             SystemEventCategoryProposal,
+	EventSigProposalExecuted:             SystemEventCategoryProposal,
+	EventSigProposalFailed:               SystemEventCategoryProposal,
+	EventSigProposalExpired:              SystemEventCategoryProposal,
+	EventSigProposalCancelled:            SystemEventCategoryProposal,
+	EventSigProposalExecutionSkipped:     SystemEventCategoryProposal,
+	EventSigQuorumUpdated:                SystemEventCategoryProposal,
+	EventSigMaxProposalsPerMemberUpdated: SystemEventCategoryProposal,
+
+	EventSigProposalVoted: SystemEventCategoryVote,
+
+	EventSigMemberAdded:   SystemEventCategoryMember,
+	EventSigMemberRemoved: SystemEventCategoryMember,
+	EventSigMemberChanged: SystemEventCategoryMember,
+
+	EventSigGasTipUpdated: SystemEventCategoryGasTip,
+
+	EventSigAddressBlacklisted:   SystemEventCategoryBlacklist,
+	EventSigAddressUnblacklisted: SystemEventCategoryBlacklist,
+
+	EventSigAuthorizedAccountAdded:   SystemEventCategoryAuthorizedAccount,
+	EventSigAuthorizedAccountRemoved: SystemEventCategoryAuthorizedAccount,
+
+	EventSigEmergencyPaused:   SystemEventCategoryEmergencyPause,
+	EventSigEmergencyUnpaused: SystemEventCategoryEmergencyPause,
+}
+
+// systemEventCategoryOf returns the category of a system contract log
+func systemEventCategoryOf(log *types.Log) (SystemEventCategory, bool) {
+	if len(log.Topics) == 0 {
+		return "", false
+	}
+	category, ok := systemEventCategories[log.Topics[0]]
+	if category == SystemEventCategoryMember && log.Address == GovValidatorAddress {
+		category = SystemEventCategoryValidator
+	}
+	return category, ok
+}
+
+// SystemEventCategories returns all system event categories in name order
+func SystemEventCategories() []SystemEventCategory {
+	seen := map[SystemEventCategory]bool{SystemEventCategoryValidator: true}
+	for _, category := range systemEventCategories {
+		seen[category] = true
+	}
+	categories := make([]SystemEventCategory, 0, len(seen))
+	for category := range seen {
+		categories = append(categories, category)
+	}
+	sort.Slice(categories, func(i, j int) bool { return categories[i] < categories[j] })
+	return categories
+}
+
+// ParseSystemEventCategory validates a category name from configuration
+func ParseSystemEventCategory(name string) (SystemEventCategory, error) {
+	category := SystemEventCategory(strings.ToLower(strings.TrimSpace(name)))
+	categories := SystemEventCategories()
+	names := make([]string, len(categories))
+	for i, known := range categories {
+		if category == known {
+			return category, nil
+		}
+		names[i] = string(known)
+	}
+	return "", fmt.Errorf("unknown system event category %q, must be one of: %s", name, strings.Join(names, ", "))
+}
+
+// SetDisabledCategories excludes events of the given categories from indexing.
+// Disabled events are neither stored nor published to the EventBus.
+func (p *SystemContractEventParser) SetDisabledCategories(categories ...SystemEventCategory) {
+	p.disabled = make(map[SystemEventCategory]bool, len(categories))
+	for _, category := range categories {
+		p.disabled[category] = true
+	}
+}
+
+// categoryDisabled reports whether log belongs to a disabled category
+func (p *SystemContractEventParser) categoryDisabled(log *types.Log) bool {
+	if len(p.disabled) == 0 {
+		return false
+	}
+	category, ok := systemEventCategoryOf(log)
+	return ok && p.disabled[category]
+}
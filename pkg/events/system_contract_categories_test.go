@@ -0,0 +1,136 @@
+package events
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/0xmhha/indexer-go/internal/constants"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestParseSystemEventCategory(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    SystemEventCategory
+		wantErr bool
+	}{
+		{"mint", SystemEventCategoryMint, false},
+		{" Gas_Tip ", SystemEventCategoryGasTip, false},
+		{"validator", SystemEventCategoryValidator, false},
+		{"authorized_account", SystemEventCategoryAuthorizedAccount, false},
+		{"transfer", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSystemEventCategory(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSystemEventCategory(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseSystemEventCategory(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSystemEventCategoryOf(t *testing.T) {
+	tests := []struct {
+		name    string
+		address common.Address
+		sig     common.Hash
+		want    SystemEventCategory
+	}{
+		{"mint", constants.NativeCoinAdapterAddress, constants.EventSigMint, SystemEventCategoryMint},
+		{"vote", constants.GovMasterMinterAddress, constants.EventSigProposalVoted, SystemEventCategoryVote},
+		{"validator member", constants.GovValidatorAddress, constants.EventSigMemberAdded, SystemEventCategoryValidator},
+		{"council member", constants.GovMasterMinterAddress, constants.EventSigMemberAdded, SystemEventCategoryMember},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := systemEventCategoryOf(&types.Log{Address: tt.address, Topics: []common.Hash{tt.sig}})
+			if !ok || got != tt.want {
+				t.Errorf("systemEventCategoryOf() = %q, %v, want %q", got, ok, tt.want)
+			}
+		})
+	}
+
+	if _, ok := systemEventCategoryOf(&types.Log{Topics: []common.Hash{common.HexToHash("0x1234")}}); ok {
+		t.Error("expected unknown event signature to have no category")
+	}
+}
+
+func TestSystemContractEventParser_DisabledCategories(t *testing.T) {
+	parser, mock := newTestParser()
+	parser.SetDisabledCategories(SystemEventCategoryMint, SystemEventCategoryValidator)
+	ctx := context.Background()
+
+	minter := common.HexToAddress("0xaaaa")
+	to := common.HexToAddress("0xbbbb")
+	member := common.HexToAddress("0xcccc")
+	amount := big.NewInt(1000)
+
+	memberData := make([]byte, 64)
+	copy(memberData[0:32], common.LeftPadBytes(big.NewInt(5).Bytes(), 32))
+	copy(memberData[32:64], common.LeftPadBytes(big.NewInt(3).Bytes(), 32))
+
+	logs := []*types.Log{
+		{
+			Address:     constants.NativeCoinAdapterAddress,
+			Topics:      []common.Hash{constants.EventSigMint, common.BytesToHash(minter.Bytes()), common.BytesToHash(to.Bytes())},
+			Data:        common.LeftPadBytes(amount.Bytes(), 32),
+			BlockNumber: 100,
+		},
+		{
+			Address:     constants.NativeCoinAdapterAddress,
+			Topics:      []common.Hash{constants.EventSigBurn, common.BytesToHash(minter.Bytes())},
+			Data:        common.LeftPadBytes(amount.Bytes(), 32),
+			BlockNumber: 100,
+		},
+		{
+			Address:     constants.GovValidatorAddress,
+			Topics:      []common.Hash{constants.EventSigMemberAdded, common.BytesToHash(member.Bytes())},
+			Data:        memberData,
+			BlockNumber: 100,
+		},
+		{
+			Address:     constants.GovMasterMinterAddress,
+			Topics:      []common.Hash{constants.EventSigMemberAdded, common.BytesToHash(member.Bytes())},
+			Data:        memberData,
+			BlockNumber: 100,
+		},
+	}
+
+	if err := parser.ParseAndIndexLogs(ctx, logs); err != nil {
+		t.Fatalf("ParseAndIndexLogs error: %v", err)
+	}
+
+	if len(mock.mintEvents) != 0 {
+		t.Errorf("expected disabled mint events to be skipped, got %d", len(mock.mintEvents))
+	}
+	if mock.totalSupplyDelta.Cmp(new(big.Int).Neg(amount)) != 0 {
+		t.Errorf("expected total supply delta to reflect only the burn, got %s", mock.totalSupplyDelta)
+	}
+	if len(mock.burnEvents) != 1 {
+		t.Errorf("expected 1 burn event, got %d", len(mock.burnEvents))
+	}
+	if mock.activeValidators[member] {
+		t.Error("expected disabled validator change to be skipped")
+	}
+	if len(mock.memberChangeEvents) != 1 || mock.memberChangeEvents[0].Contract != constants.GovMasterMinterAddress {
+		t.Errorf("expected only the GovMasterMinter member change, got %d events", len(mock.memberChangeEvents))
+	}
+
+	// Re-enabling all categories indexes every event again
+	parser.SetDisabledCategories()
+	if err := parser.ParseAndIndexLogs(ctx, logs[:1]); err != nil {
+		t.Fatalf("ParseAndIndexLogs error: %v", err)
+	}
+	if len(mock.mintEvents) != 1 {
+		t.Errorf("expected 1 mint event after re-enabling, got %d", len(mock.mintEvents))
+	}
+}
@@ -2,6 +2,7 @@ package events
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 
@@ -73,6 +74,16 @@ type SystemContractEventParser struct {
 	storage  storage.SystemContractWriter
 	logger   *zap.Logger
 	eventBus *EventBus
+
+	// disabled holds event categories excluded from indexing
+	disabled map[SystemEventCategory]bool
+
+	// allowed restricts indexing to logs from these contracts when non-empty
+	allowed map[common.Address]bool
+
+	// strictTransitions reports invalid proposal status transitions as errors
+	// instead of skipping them with a warning
+	strictTransitions bool
 }
 
 // NewSystemContractEventParser creates a new system contract event parser
@@ -88,6 +99,46 @@ func (p *SystemContractEventParser) SetEventBus(eventBus *EventBus) {
 	p.eventBus = eventBus
 }
 
+// SetStrictProposalTransitions controls how proposal events that would make an
+// invalid status transition are handled. Such events can arrive out of order
+// during a reorg; by default they are skipped with a warning.
+func (p *SystemContractEventParser) SetStrictProposalTransitions(strict bool) {
+	p.strictTransitions = strict
+}
+
+// SetAllowedContracts restricts indexing to logs emitted by the given
+// contracts. Logs from other addresses are skipped before any decoding.
+// An empty list indexes every system contract.
+func (p *SystemContractEventParser) SetAllowedContracts(contracts ...common.Address) {
+	p.allowed = make(map[common.Address]bool, len(contracts))
+	for _, contract := range contracts {
+		p.allowed[contract] = true
+	}
+}
+
+// contractAllowed reports whether logs from address pass the allowlist
+func (p *SystemContractEventParser) contractAllowed(address common.Address) bool {
+	return len(p.allowed) == 0 || p.allowed[address]
+}
+
+// updateProposalStatus updates the status of the proposal and reports whether
+// the update was applied
+func (p *SystemContractEventParser) updateProposalStatus(ctx context.Context, log *types.Log, proposalID *big.Int, status storage.ProposalStatus, executedAt uint64) (bool, error) {
+	err := p.storage.UpdateProposalStatus(ctx, log.Address, proposalID, status, executedAt)
+	if err == nil {
+		return true, nil
+	}
+	if errors.Is(err, storage.ErrInvalidProposalTransition) && !p.strictTransitions {
+		p.logger.Warn("Skipping invalid proposal status transition",
+			zap.String("contract", log.Address.Hex()),
+			zap.String("proposalId", proposalID.String()),
+			zap.Uint64("blockNumber", log.BlockNumber),
+			zap.Error(err))
+		return false, nil
+	}
+	return false, fmt.Errorf("failed to update proposal status: %w", err)
+}
+
 // publishEvent publishes a system contract event to the event bus
 func (p *SystemContractEventParser) publishEvent(contract common.Address, eventName SystemContractEventType, log *types.Log, data map[string]interface{}) {
 	if p.eventBus == nil {
@@ -124,8 +175,8 @@ func (p *SystemContractEventParser) ParseAndIndexLogs(ctx context.Context, logs
 
 // parseAndIndexLog parses and indexes a single log
 func (p *SystemContractEventParser) parseAndIndexLog(ctx context.Context, log *types.Log) error {
-	// Check if log is from system contract
-	if !isSystemContract(log.Address) {
+	// Check if log is from an allowed system contract
+	if !p.contractAllowed(log.Address) || !isSystemContract(log.Address) {
 		return nil
 	}
 
@@ -133,6 +184,9 @@ func (p *SystemContractEventParser) parseAndIndexLog(ctx context.Context, log *t
 	if len(log.Topics) == 0 {
 		return nil
 	}
+	if p.categoryDisabled(log) {
+		return nil
+	}
 
 	eventSig := log.Topics[0]
 
@@ -242,6 +296,8 @@ func (p *SystemContractEventParser) parseMintEvent(ctx context.Context, log *typ
 		To:          to,
 		Amount:      amount,
 		Timestamp:   0, // Will be set by storage layer
+		TxIndex:     uint64(log.TxIndex),
+		LogIndex:    uint64(log.Index),
 	}
 
 	if err := p.storage.StoreMintEvent(ctx, event); err != nil {
@@ -281,6 +337,8 @@ func (p *SystemContractEventParser) parseBurnEvent(ctx context.Context, log *typ
 		Amount:       amount,
 		Timestamp:    0,  // Will be set by storage layer
 		WithdrawalID: "", // Not set for NativeCoinAdapter burns
+		TxIndex:      uint64(log.TxIndex),
+		LogIndex:     uint64(log.Index),
 	}
 
 	if err := p.storage.StoreBurnEvent(ctx, event); err != nil {
@@ -518,8 +576,8 @@ func (p *SystemContractEventParser) parseProposalApprovedEvent(ctx context.Conte
 	approver := common.BytesToAddress(log.Topics[2].Bytes())
 
 	// Update proposal status to Approved
-	if err := p.storage.UpdateProposalStatus(ctx, log.Address, proposalID, storage.ProposalStatusApproved, 0); err != nil {
-		return fmt.Errorf("failed to update proposal status: %w", err)
+	if applied, err := p.updateProposalStatus(ctx, log, proposalID, storage.ProposalStatusApproved, 0); err != nil || !applied {
+		return err
 	}
 
 	// Publish event to EventBus
@@ -541,8 +599,8 @@ func (p *SystemContractEventParser) parseProposalRejectedEvent(ctx context.Conte
 	rejector := common.BytesToAddress(log.Topics[2].Bytes())
 
 	// Update proposal status to Rejected
-	if err := p.storage.UpdateProposalStatus(ctx, log.Address, proposalID, storage.ProposalStatusRejected, 0); err != nil {
-		return fmt.Errorf("failed to update proposal status: %w", err)
+	if applied, err := p.updateProposalStatus(ctx, log, proposalID, storage.ProposalStatusRejected, 0); err != nil || !applied {
+		return err
 	}
 
 	// Publish event to EventBus
@@ -570,8 +628,8 @@ func (p *SystemContractEventParser) parseProposalExecutedEvent(ctx context.Conte
 	}
 
 	// Update proposal status to Executed with current block number as execution time
-	if err := p.storage.UpdateProposalStatus(ctx, log.Address, proposalID, storage.ProposalStatusExecuted, log.BlockNumber); err != nil {
-		return fmt.Errorf("failed to update proposal status: %w", err)
+	if applied, err := p.updateProposalStatus(ctx, log, proposalID, storage.ProposalStatusExecuted, log.BlockNumber); err != nil || !applied {
+		return err
 	}
 
 	// Publish event to EventBus
@@ -594,8 +652,8 @@ func (p *SystemContractEventParser) parseProposalFailedEvent(ctx context.Context
 	executor := common.BytesToAddress(log.Topics[2].Bytes())
 
 	// Update proposal status to Failed
-	if err := p.storage.UpdateProposalStatus(ctx, log.Address, proposalID, storage.ProposalStatusFailed, log.BlockNumber); err != nil {
-		return fmt.Errorf("failed to update proposal status: %w", err)
+	if applied, err := p.updateProposalStatus(ctx, log, proposalID, storage.ProposalStatusFailed, log.BlockNumber); err != nil || !applied {
+		return err
 	}
 
 	// Publish event to EventBus
@@ -617,8 +675,8 @@ func (p *SystemContractEventParser) parseProposalExpiredEvent(ctx context.Contex
 	executor := common.BytesToAddress(log.Topics[2].Bytes())
 
 	// Update proposal status to Expired
-	if err := p.storage.UpdateProposalStatus(ctx, log.Address, proposalID, storage.ProposalStatusExpired, 0); err != nil {
-		return fmt.Errorf("failed to update proposal status: %w", err)
+	if applied, err := p.updateProposalStatus(ctx, log, proposalID, storage.ProposalStatusExpired, 0); err != nil || !applied {
+		return err
 	}
 
 	// Publish event to EventBus
@@ -640,8 +698,8 @@ func (p *SystemContractEventParser) parseProposalCancelledEvent(ctx context.Cont
 	canceller := common.BytesToAddress(log.Topics[2].Bytes())
 
 	// Update proposal status to Cancelled
-	if err := p.storage.UpdateProposalStatus(ctx, log.Address, proposalID, storage.ProposalStatusCancelled, 0); err != nil {
-		return fmt.Errorf("failed to update proposal status: %w", err)
+	if applied, err := p.updateProposalStatus(ctx, log, proposalID, storage.ProposalStatusCancelled, 0); err != nil || !applied {
+		return err
 	}
 
 	// Publish event to EventBus
@@ -1079,6 +1137,8 @@ func (p *SystemContractEventParser) parseBurnExecutedEvent(ctx context.Context,
 		Amount:       amount,
 		Timestamp:    0, // Will be set by storage layer
 		WithdrawalID: withdrawalID,
+		TxIndex:      uint64(log.TxIndex),
+		LogIndex:     uint64(log.Index),
 	}
 
 	if err := p.storage.StoreBurnEvent(ctx, event); err != nil {
@@ -2,6 +2,7 @@ package events
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"testing"
@@ -333,6 +334,38 @@ func TestParseMintEvent(t *testing.T) {
 	}
 }
 
+func TestParseMintEvent_SameBlockLogIndices(t *testing.T) {
+	parser, mock := newTestParser()
+	ctx := context.Background()
+
+	minter := common.HexToAddress("0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")
+	var logs []*types.Log
+	for _, logIndex := range []uint{4, 9} {
+		logs = append(logs, &types.Log{
+			Address:     constants.NativeCoinAdapterAddress,
+			Topics:      []common.Hash{constants.EventSigMint, common.BytesToHash(minter.Bytes()), common.BytesToHash(minter.Bytes())},
+			Data:        common.LeftPadBytes(big.NewInt(1).Bytes(), 32),
+			BlockNumber: 100,
+			TxIndex:     2,
+			Index:       logIndex,
+		})
+	}
+
+	if err := parser.ParseAndIndexLogs(ctx, logs); err != nil {
+		t.Fatalf("ParseAndIndexLogs error: %v", err)
+	}
+
+	if len(mock.mintEvents) != 2 {
+		t.Fatalf("expected 2 mint events, got %d", len(mock.mintEvents))
+	}
+	for i, want := range []uint64{4, 9} {
+		if mock.mintEvents[i].TxIndex != 2 || mock.mintEvents[i].LogIndex != want {
+			t.Errorf("mint event %d: expected tx index 2 and log index %d, got %d and %d",
+				i, want, mock.mintEvents[i].TxIndex, mock.mintEvents[i].LogIndex)
+		}
+	}
+}
+
 func TestParseMintEvent_InvalidTopics(t *testing.T) {
 	parser, _ := newTestParser()
 	ctx := context.Background()
@@ -667,6 +700,37 @@ func TestParseProposalCancelledEvent(t *testing.T) {
 	}
 }
 
+func TestParseProposalEvent_InvalidTransition(t *testing.T) {
+	ctx := context.Background()
+	proposalID := big.NewInt(7)
+	log := &types.Log{
+		Address:     constants.GovValidatorAddress,
+		Topics:      []common.Hash{constants.EventSigProposalApproved, common.BytesToHash(proposalID.Bytes()), common.BytesToHash(common.Address{}.Bytes())},
+		BlockNumber: 950,
+	}
+	transitionErr := fmt.Errorf("%w: proposal 7 from executed to approved", storage.ErrInvalidProposalTransition)
+
+	// By default an invalid transition is skipped without an error
+	parser, mock := newTestParser()
+	mock.storeErr = transitionErr
+	if err := parser.parseAndIndexLog(ctx, log); err != nil {
+		t.Errorf("expected invalid transition to be skipped, got %v", err)
+	}
+
+	// In strict mode it is reported
+	parser.SetStrictProposalTransitions(true)
+	if err := parser.parseAndIndexLog(ctx, log); !errors.Is(err, storage.ErrInvalidProposalTransition) {
+		t.Errorf("expected ErrInvalidProposalTransition in strict mode, got %v", err)
+	}
+
+	// Other storage errors are reported regardless of the mode
+	parser.SetStrictProposalTransitions(false)
+	mock.storeErr = fmt.Errorf("disk full")
+	if err := parser.parseAndIndexLog(ctx, log); err == nil {
+		t.Error("expected storage error to be reported")
+	}
+}
+
 // ========== Member Event Tests ==========
 
 func TestParseMemberAddedEvent(t *testing.T) {
@@ -1221,3 +1285,51 @@ func TestParseEmergencyPausedEvent_InvalidTopics(t *testing.T) {
 	}
 	parser.ParseAndIndexLogs(ctx, []*types.Log{log})
 }
+
+func TestSystemContractEventParser_AllowedContracts(t *testing.T) {
+	parser, mock := newTestParser()
+	parser.SetAllowedContracts(constants.NativeCoinAdapterAddress)
+	ctx := context.Background()
+
+	minter := common.HexToAddress("0xaaaa")
+	to := common.HexToAddress("0xbbbb")
+	member := common.HexToAddress("0xcccc")
+	amount := big.NewInt(1000)
+
+	memberData := make([]byte, 64)
+	copy(memberData[0:32], common.LeftPadBytes(big.NewInt(5).Bytes(), 32))
+	copy(memberData[32:64], common.LeftPadBytes(big.NewInt(3).Bytes(), 32))
+
+	mintLog := &types.Log{
+		Address:     constants.NativeCoinAdapterAddress,
+		Topics:      []common.Hash{constants.EventSigMint, common.BytesToHash(minter.Bytes()), common.BytesToHash(to.Bytes())},
+		Data:        common.LeftPadBytes(amount.Bytes(), 32),
+		BlockNumber: 100,
+	}
+	memberLog := &types.Log{
+		Address:     constants.GovValidatorAddress,
+		Topics:      []common.Hash{constants.EventSigMemberAdded, common.BytesToHash(member.Bytes())},
+		Data:        memberData,
+		BlockNumber: 100,
+	}
+
+	if err := parser.ParseAndIndexLogs(ctx, []*types.Log{mintLog, memberLog}); err != nil {
+		t.Fatalf("ParseAndIndexLogs error: %v", err)
+	}
+
+	if len(mock.mintEvents) != 1 {
+		t.Errorf("expected 1 mint event from the allowed contract, got %d", len(mock.mintEvents))
+	}
+	if len(mock.memberChangeEvents) != 0 || mock.activeValidators[member] {
+		t.Error("expected logs from a contract off the allowlist to be ignored")
+	}
+
+	// An empty allowlist indexes every system contract again
+	parser.SetAllowedContracts()
+	if err := parser.ParseAndIndexLogs(ctx, []*types.Log{memberLog}); err != nil {
+		t.Fatalf("ParseAndIndexLogs error: %v", err)
+	}
+	if !mock.activeValidators[member] {
+		t.Error("expected validator change to be indexed without an allowlist")
+	}
+}
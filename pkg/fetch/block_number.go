@@ -0,0 +1,31 @@
+package fetch
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ErrBlockNumberMismatch is returned when the RPC endpoint answers a request
+// for one height with a block of another, e.g. a misconfigured load balancer
+// or a node serving a different chain
+var ErrBlockNumberMismatch = errors.New("block number mismatch")
+
+// checkBlockNumber rejects a block returned for a different height than
+// requested and counts it in the fetcher metrics
+func (f *Fetcher) checkBlockNumber(block *types.Block, height uint64) error {
+	if block.NumberU64() != height {
+		f.metrics.RecordMismatchedBlock()
+		return fmt.Errorf("%w: requested %d, endpoint returned %d", ErrBlockNumberMismatch, height, block.NumberU64())
+	}
+	return nil
+}
+
+// retryable reports whether a failed block fetch should be attempted again.
+// A block number mismatch is retried unless FailOnBlockNumberMismatch is set:
+// behind a load balancer the next attempt may reach a healthy node, while a
+// single endpoint keeps answering with the same block.
+func (f *Fetcher) retryable(err error) bool {
+	return !f.config.FailOnBlockNumberMismatch || !errors.Is(err, ErrBlockNumberMismatch)
+}
@@ -0,0 +1,170 @@
+package fetch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+
+	"github.com/0xmhha/indexer-go/pkg/client"
+)
+
+// newMismatchNode starts a JSON-RPC node that answers the first mismatches
+// eth_getBlockByNumber requests with the block at height+1 instead of the
+// requested one, and returns a client connected to it along with a counter
+// of block requests
+func newMismatchNode(t *testing.T, mismatches int32) (*client.Client, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage   `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch req.Method {
+		case "eth_getBlockByNumber":
+			var number string
+			if len(req.Params) == 0 || json.Unmarshal(req.Params[0], &number) != nil {
+				http.Error(w, "missing block number", http.StatusBadRequest)
+				return
+			}
+			height, _ := new(big.Int).SetString(number[2:], 16)
+			if calls.Add(1) <= mismatches {
+				height.Add(height, big.NewInt(1))
+			}
+			header, _ := json.Marshal(&types.Header{
+				Number:      height,
+				Difficulty:  big.NewInt(1),
+				GasLimit:    8000000,
+				UncleHash:   types.EmptyUncleHash,
+				TxHash:      types.EmptyTxsHash,
+				ReceiptHash: types.EmptyReceiptsHash,
+			})
+			var block map[string]interface{}
+			_ = json.Unmarshal(header, &block)
+			block["transactions"] = []interface{}{}
+			block["uncles"] = []interface{}{}
+			result, _ := json.Marshal(block)
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":%s}`, req.ID, result)
+		case "eth_getBlockReceipts":
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":[]}`, req.ID)
+		case "eth_chainId":
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":"0x1"}`, req.ID)
+		default:
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"error":{"code":-32601,"message":"method not found"}}`, req.ID)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	node, err := client.NewClient(&client.Config{Endpoint: server.URL, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	t.Cleanup(node.Close)
+	return node, &calls
+}
+
+func TestFetchBlock_RejectsMismatchedNumber(t *testing.T) {
+	node, calls := newMismatchNode(t, 100)
+	storage := newMockStorage()
+	config := &Config{BatchSize: 10, MaxRetries: 2, RetryDelay: time.Millisecond}
+	f := NewFetcher(node, storage, config, zap.NewNop(), nil)
+
+	err := f.FetchBlock(context.Background(), 5)
+	if !errors.Is(err, ErrBlockNumberMismatch) {
+		t.Fatalf("FetchBlock() error = %v, want ErrBlockNumberMismatch", err)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("eth_getBlockByNumber called %d times, want 3 (one attempt and two retries)", got)
+	}
+	if len(storage.blocks) != 0 {
+		t.Errorf("stored %d blocks, want none", len(storage.blocks))
+	}
+	if got := f.GetMetrics().MismatchedBlocks; got != 3 {
+		t.Errorf("MismatchedBlocks = %d, want 3", got)
+	}
+}
+
+func TestFetchBlock_FailOnBlockNumberMismatch(t *testing.T) {
+	node, calls := newMismatchNode(t, 1)
+	storage := newMockStorage()
+	config := &Config{BatchSize: 10, MaxRetries: 2, RetryDelay: time.Millisecond, FailOnBlockNumberMismatch: true}
+	f := NewFetcher(node, storage, config, zap.NewNop(), nil)
+
+	err := f.FetchBlock(context.Background(), 5)
+	if !errors.Is(err, ErrBlockNumberMismatch) {
+		t.Fatalf("FetchBlock() error = %v, want ErrBlockNumberMismatch", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("eth_getBlockByNumber called %d times, want 1 (no retries)", got)
+	}
+	if len(storage.blocks) != 0 {
+		t.Errorf("stored %d blocks, want none", len(storage.blocks))
+	}
+}
+
+func TestFetchBlock_RetriesMismatchedNumber(t *testing.T) {
+	node, calls := newMismatchNode(t, 1)
+	storage := newMockStorage()
+	config := &Config{BatchSize: 10, MaxRetries: 2, RetryDelay: time.Millisecond}
+	f := NewFetcher(node, storage, config, zap.NewNop(), nil)
+
+	if err := f.FetchBlock(context.Background(), 5); err != nil {
+		t.Fatalf("FetchBlock() error = %v", err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("eth_getBlockByNumber called %d times, want 2", got)
+	}
+	block, ok := storage.blocks[5]
+	if !ok || block.NumberU64() != 5 {
+		t.Fatalf("block 5 not stored under its height")
+	}
+	if _, ok := storage.blocks[6]; ok {
+		t.Error("mismatched block 6 was stored")
+	}
+}
+
+func TestFetchBlockJob_RejectsMismatchedNumber(t *testing.T) {
+	node, _ := newMismatchNode(t, 100)
+	config := &Config{BatchSize: 10, MaxRetries: 1, RetryDelay: time.Millisecond}
+	f := NewFetcher(node, newMockStorage(), config, zap.NewNop(), nil)
+
+	result := f.fetchBlockJob(context.Background(), 5)
+	if !errors.Is(result.err, ErrBlockNumberMismatch) {
+		t.Fatalf("fetchBlockJob() error = %v, want ErrBlockNumberMismatch", result.err)
+	}
+	if result.block != nil {
+		t.Error("fetchBlockJob() returned the mismatched block")
+	}
+}
+
+func TestFetchBlockJob_FailOnBlockNumberMismatch(t *testing.T) {
+	node, calls := newMismatchNode(t, 1)
+	config := &Config{BatchSize: 10, MaxRetries: 2, RetryDelay: time.Millisecond, FailOnBlockNumberMismatch: true}
+	f := NewFetcher(node, newMockStorage(), config, zap.NewNop(), nil)
+
+	result := f.fetchBlockJob(context.Background(), 5)
+	if !errors.Is(result.err, ErrBlockNumberMismatch) {
+		t.Fatalf("fetchBlockJob() error = %v, want ErrBlockNumberMismatch", result.err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("eth_getBlockByNumber called %d times, want 1 (no retries)", got)
+	}
+}
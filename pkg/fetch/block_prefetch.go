@@ -0,0 +1,62 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+
+	"github.com/0xmhha/indexer-go/pkg/client"
+)
+
+// prefetchBlocks fetches the blocks in [from, to] in one batched request when
+// the block source supports it, keyed by height. It returns nil for a single
+// block, when batching is unavailable or when the request fails; the blocks
+// are then fetched one at a time.
+func (f *Fetcher) prefetchBlocks(ctx context.Context, from, to uint64) map[uint64]*types.Block {
+	if from >= to || f.batchBlocksUnsupported.Load() {
+		return nil
+	}
+	rangeClient, ok := f.blockRangeClient()
+	if !ok {
+		return nil
+	}
+
+	blocks, err := rangeClient.GetBlocksByRange(ctx, from, to)
+	if err != nil {
+		if errors.Is(err, client.ErrBatchNotSupported) {
+			f.batchBlocksUnsupported.Store(true)
+			f.logger.Warn("RPC endpoint does not support batch requests, fetching blocks one at a time",
+				zap.Error(err),
+			)
+			return nil
+		}
+		f.logger.Warn("Batched block fetch failed, fetching blocks one at a time",
+			zap.Uint64("from", from),
+			zap.Uint64("to", to),
+			zap.Error(err),
+		)
+		return nil
+	}
+
+	prefetched := make(map[uint64]*types.Block, len(blocks))
+	for _, block := range blocks {
+		if block != nil {
+			prefetched[block.NumberU64()] = block
+		}
+	}
+	return prefetched
+}
+
+// blockRangeClient returns the batched block source. With a chain adapter,
+// blocks come from its block fetcher, so batching is only used when that
+// fetcher supports it.
+func (f *Fetcher) blockRangeClient() (BlockRangeClient, bool) {
+	if f.chainAdapter != nil {
+		rangeClient, ok := f.chainAdapter.BlockFetcher().(BlockRangeClient)
+		return rangeClient, ok
+	}
+	rangeClient, ok := f.client.(BlockRangeClient)
+	return rangeClient, ok
+}
@@ -0,0 +1,149 @@
+package fetch
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+
+	"github.com/0xmhha/indexer-go/pkg/client"
+)
+
+// rangeMockClient is a mockClient that also serves batched block ranges
+type rangeMockClient struct {
+	*mockClient
+	rangeErr   error
+	rangeCalls int
+	blockCalls int
+}
+
+func (m *rangeMockClient) GetBlockByNumber(ctx context.Context, number uint64) (*types.Block, error) {
+	m.blockCalls++
+	return m.mockClient.GetBlockByNumber(ctx, number)
+}
+
+func (m *rangeMockClient) GetBlocksByRange(ctx context.Context, from, to uint64) ([]*types.Block, error) {
+	m.rangeCalls++
+	if m.rangeErr != nil {
+		return nil, m.rangeErr
+	}
+	blocks := make([]*types.Block, 0, to-from+1)
+	for number := from; number <= to; number++ {
+		block, ok := m.blocks[number]
+		if !ok {
+			return nil, fmt.Errorf("block %d not found", number)
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks, nil
+}
+
+func newRangeMockClient(blocks uint64) *rangeMockClient {
+	m := &rangeMockClient{mockClient: newMockClient()}
+	for i := uint64(0); i < blocks; i++ {
+		block := types.NewBlockWithHeader(&types.Header{
+			Number:     big.NewInt(int64(i)),
+			Difficulty: big.NewInt(1000),
+			GasLimit:   8000000,
+		})
+		m.blocks[i] = block
+		m.receipts[block.Hash()] = types.Receipts{}
+	}
+	m.latestBlock = blocks - 1
+	return m
+}
+
+func TestFetchRange_BatchedBlocks(t *testing.T) {
+	tests := []struct {
+		name           string
+		rangeErr       error
+		wantRangeCalls int
+		wantBlockCalls int
+	}{
+		{
+			name:           "batched",
+			wantRangeCalls: 2,
+			wantBlockCalls: 0,
+		},
+		{
+			name:           "batch failure falls back per chunk",
+			rangeErr:       fmt.Errorf("connection reset"),
+			wantRangeCalls: 2,
+			wantBlockCalls: 10,
+		},
+		{
+			name:           "unsupported batching is turned off",
+			rangeErr:       fmt.Errorf("%w: invalid batch", client.ErrBatchNotSupported),
+			wantRangeCalls: 1,
+			wantBlockCalls: 10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rpc := newRangeMockClient(10)
+			rpc.rangeErr = tt.rangeErr
+			storage := newMockStorage()
+			config := &Config{
+				BatchSize:  5,
+				MaxRetries: 3,
+				RetryDelay: time.Millisecond,
+			}
+			fetcher := NewFetcher(rpc, storage, config, zap.NewNop(), nil)
+
+			if err := fetcher.FetchRange(context.Background(), 0, 9); err != nil {
+				t.Fatalf("FetchRange() error = %v", err)
+			}
+
+			if rpc.rangeCalls != tt.wantRangeCalls {
+				t.Errorf("GetBlocksByRange calls = %d, want %d", rpc.rangeCalls, tt.wantRangeCalls)
+			}
+			if rpc.blockCalls != tt.wantBlockCalls {
+				t.Errorf("GetBlockByNumber calls = %d, want %d", rpc.blockCalls, tt.wantBlockCalls)
+			}
+			for i := uint64(0); i < 10; i++ {
+				stored, err := storage.GetBlockByHeight(i)
+				if err != nil {
+					t.Fatalf("block %d not stored: %v", i, err)
+				}
+				if stored.Hash() != rpc.blocks[i].Hash() {
+					t.Errorf("block %d hash = %s, want %s", i, stored.Hash(), rpc.blocks[i].Hash())
+				}
+			}
+		})
+	}
+}
+
+func TestFetchRange_BatchedBlocksWithCommitGroups(t *testing.T) {
+	rpc := newRangeMockClient(10)
+	storage := newMockStorage()
+	config := &Config{
+		BatchSize:         4,
+		MaxRetries:        3,
+		RetryDelay:        time.Millisecond,
+		CommitBatchBlocks: 3,
+	}
+	fetcher := NewFetcher(rpc, storage, config, zap.NewNop(), nil)
+
+	if err := fetcher.FetchRange(context.Background(), 0, 9); err != nil {
+		t.Fatalf("FetchRange() error = %v", err)
+	}
+
+	// Prefetches are stretched to cover commit groups that cross a chunk:
+	// 0-3, then 4-7 for group 3-5, then 8-9 for group 6-8
+	if rpc.rangeCalls != 3 {
+		t.Errorf("GetBlocksByRange calls = %d, want 3", rpc.rangeCalls)
+	}
+	if rpc.blockCalls != 0 {
+		t.Errorf("GetBlockByNumber calls = %d, want 0", rpc.blockCalls)
+	}
+	for i := uint64(0); i < 10; i++ {
+		if _, err := storage.GetBlockByHeight(i); err != nil {
+			t.Errorf("block %d not stored: %v", i, err)
+		}
+	}
+}
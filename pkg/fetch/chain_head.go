@@ -0,0 +1,37 @@
+package fetch
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	storagepkg "github.com/0xmhha/indexer-go/pkg/storage"
+)
+
+// ChainHead returns the latest block height reported by the node at the last
+// poll, or 0 if the chain head has not been polled yet
+func (f *Fetcher) ChainHead() uint64 {
+	return f.chainHead.Load()
+}
+
+// refreshChainHead polls the node for its latest block height, caches it and
+// records it in storage when it changed, so lag can be computed without RPC calls
+func (f *Fetcher) refreshChainHead(ctx context.Context) (uint64, error) {
+	head, err := f.client.GetLatestBlockNumber(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if previous := f.chainHead.Swap(head); previous == head {
+		return head, nil
+	}
+
+	if writer, ok := f.storage.(storagepkg.ChainHeadWriter); ok {
+		if err := writer.SetChainHead(ctx, head); err != nil {
+			// The cached value stays current; storage catches up on the next change
+			f.logger.Warn("Failed to store chain head", zap.Uint64("height", head), zap.Error(err))
+		}
+	}
+
+	return head, nil
+}
@@ -0,0 +1,98 @@
+package fetch
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	storagepkg "github.com/0xmhha/indexer-go/pkg/storage"
+)
+
+func TestFetcher_ChainHeadTracking(t *testing.T) {
+	store := setupTestStorage(t)
+
+	ctx := context.Background()
+	client := newMockClient()
+	fetcher := newBalanceTestFetcher(client, store)
+
+	if _, err := store.GetChainHead(ctx); err != storagepkg.ErrNotFound {
+		t.Fatalf("GetChainHead() before polling error = %v, want ErrNotFound", err)
+	}
+
+	for _, head := range []uint64{5, 12, 12, 20} {
+		client.latestBlock = head
+
+		got, err := fetcher.refreshChainHead(ctx)
+		if err != nil {
+			t.Fatalf("refreshChainHead() error = %v", err)
+		}
+		if got != head {
+			t.Errorf("refreshChainHead() = %d, want %d", got, head)
+		}
+		if fetcher.ChainHead() != head {
+			t.Errorf("ChainHead() = %d, want %d", fetcher.ChainHead(), head)
+		}
+
+		stored, err := store.GetChainHead(ctx)
+		if err != nil {
+			t.Fatalf("GetChainHead() error = %v", err)
+		}
+		if stored != head {
+			t.Errorf("GetChainHead() = %d, want %d", stored, head)
+		}
+	}
+}
+
+func TestFetcher_ChainHeadPollFailure(t *testing.T) {
+	store := setupTestStorage(t)
+
+	ctx := context.Background()
+	client := newMockClient()
+	client.latestBlock = 7
+	fetcher := newBalanceTestFetcher(client, store)
+
+	if _, err := fetcher.refreshChainHead(ctx); err != nil {
+		t.Fatalf("refreshChainHead() error = %v", err)
+	}
+
+	// A failed poll keeps the last known head
+	client.latestBlock = 9
+	client.failCount = 1
+	if _, err := fetcher.refreshChainHead(ctx); err == nil {
+		t.Fatal("refreshChainHead() should fail when the node errors")
+	}
+	if fetcher.ChainHead() != 7 {
+		t.Errorf("ChainHead() = %d, want 7", fetcher.ChainHead())
+	}
+	if stored, err := store.GetChainHead(ctx); err != nil || stored != 7 {
+		t.Errorf("GetChainHead() = %d, %v, want 7", stored, err)
+	}
+}
+
+func TestFetcher_RunRecordsChainHead(t *testing.T) {
+	store := setupTestStorage(t)
+
+	ctx := context.Background()
+	if err := store.SetLatestHeight(ctx, 10); err != nil {
+		t.Fatalf("SetLatestHeight() error = %v", err)
+	}
+
+	// The indexer is caught up, so Run only polls the chain head
+	client := newMockClient()
+	client.latestBlock = 10
+	fetcher := newBalanceTestFetcher(client, store)
+
+	runCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if err := fetcher.Run(runCtx); err != context.DeadlineExceeded {
+		t.Fatalf("Run() error = %v, want context.DeadlineExceeded", err)
+	}
+
+	stored, err := store.GetChainHead(ctx)
+	if err != nil {
+		t.Fatalf("GetChainHead() error = %v", err)
+	}
+	if stored != 10 {
+		t.Errorf("GetChainHead() = %d, want 10", stored)
+	}
+}
@@ -0,0 +1,158 @@
+package fetch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/0xmhha/indexer-go/internal/constants"
+)
+
+// Checkpoint records fetcher progress durably. The latest height in storage is
+// written without fsync for throughput, so after a crash it can fall behind
+// blocks the fetcher had already reported as indexed; the checkpoint bounds
+// how far back that loss can go.
+type Checkpoint struct {
+	Height    uint64    `json:"height"`
+	ChainHead uint64    `json:"chain_head"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ReadCheckpoint loads a checkpoint from path. A missing file is reported as
+// an error wrapping os.ErrNotExist.
+func ReadCheckpoint(path string) (*Checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cp Checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("failed to decode checkpoint %s: %w", path, err)
+	}
+	return &cp, nil
+}
+
+// WriteCheckpoint atomically replaces the checkpoint at path and fsyncs it,
+// so a reader after a crash sees either the previous or the new checkpoint
+func WriteCheckpoint(path string, cp *Checkpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint: %w", err)
+	}
+
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create checkpoint file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to sync checkpoint: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace checkpoint: %w", err)
+	}
+
+	// Sync the directory so the rename itself survives a crash
+	d, err := os.Open(dir)
+	if err != nil {
+		return fmt.Errorf("failed to open checkpoint directory: %w", err)
+	}
+	defer d.Close()
+	if err := d.Sync(); err != nil {
+		return fmt.Errorf("failed to sync checkpoint directory: %w", err)
+	}
+	return nil
+}
+
+// maybeWriteCheckpoint records height as indexed if checkpointing is enabled
+// and the checkpoint interval has elapsed since the last write
+func (f *Fetcher) maybeWriteCheckpoint(height uint64) {
+	if f.config.CheckpointPath == "" {
+		return
+	}
+
+	interval := f.config.CheckpointInterval
+	if interval <= 0 {
+		interval = constants.DefaultCheckpointInterval
+	}
+	now := time.Now()
+	if !f.lastCheckpoint.IsZero() && now.Sub(f.lastCheckpoint) < interval {
+		return
+	}
+
+	cp := &Checkpoint{
+		Height:    height,
+		ChainHead: f.ChainHead(),
+		UpdatedAt: now,
+	}
+	if err := WriteCheckpoint(f.config.CheckpointPath, cp); err != nil {
+		// Progress is still in storage; the next interval retries the write
+		f.logger.Warn("Failed to write fetcher checkpoint",
+			zap.String("path", f.config.CheckpointPath),
+			zap.Uint64("height", height),
+			zap.Error(err),
+		)
+		return
+	}
+	f.lastCheckpoint = now
+}
+
+// reconcileCheckpoint compares the checkpoint against the latest height in
+// storage and re-fetches any heights the checkpoint covers but storage lost.
+// Failures are logged; the fetcher still resumes from storage's latest height.
+func (f *Fetcher) reconcileCheckpoint(ctx context.Context) {
+	if f.config.CheckpointPath == "" {
+		return
+	}
+
+	cp, err := ReadCheckpoint(f.config.CheckpointPath)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			f.logger.Warn("Failed to read fetcher checkpoint",
+				zap.String("path", f.config.CheckpointPath),
+				zap.Error(err),
+			)
+		}
+		return
+	}
+
+	from := f.config.StartHeight
+	if latest, err := f.storage.GetLatestHeight(ctx); err == nil && latest+1 > from {
+		from = latest + 1
+	}
+	if cp.Height < from {
+		return
+	}
+
+	f.logger.Warn("Latest height is behind checkpoint, backfilling lost blocks",
+		zap.Uint64("checkpoint_height", cp.Height),
+		zap.Uint64("checkpoint_chain_head", cp.ChainHead),
+		zap.Time("checkpoint_updated_at", cp.UpdatedAt),
+		zap.Uint64("from", from),
+		zap.Uint64("missing", cp.Height-from+1),
+	)
+	if err := f.FetchRange(ctx, from, cp.Height); err != nil {
+		f.logger.Error("Failed to backfill blocks behind checkpoint",
+			zap.Uint64("from", from),
+			zap.Uint64("to", cp.Height),
+			zap.Error(err),
+		)
+	}
+}
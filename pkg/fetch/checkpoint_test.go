@@ -0,0 +1,153 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+
+	storagepkg "github.com/0xmhha/indexer-go/pkg/storage"
+)
+
+func TestCheckpoint_WriteRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+
+	if _, err := ReadCheckpoint(path); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("ReadCheckpoint() missing file error = %v, want os.ErrNotExist", err)
+	}
+
+	want := &Checkpoint{Height: 42, ChainHead: 50, UpdatedAt: time.Unix(1700000000, 0).UTC()}
+	if err := WriteCheckpoint(path, want); err != nil {
+		t.Fatalf("WriteCheckpoint() error = %v", err)
+	}
+	want.Height = 43
+	if err := WriteCheckpoint(path, want); err != nil {
+		t.Fatalf("WriteCheckpoint() overwrite error = %v", err)
+	}
+
+	got, err := ReadCheckpoint(path)
+	if err != nil {
+		t.Fatalf("ReadCheckpoint() error = %v", err)
+	}
+	if got.Height != 43 || got.ChainHead != 50 || !got.UpdatedAt.Equal(want.UpdatedAt) {
+		t.Errorf("ReadCheckpoint() = %+v, want %+v", got, want)
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("directory has %d entries, want only the checkpoint", len(entries))
+	}
+}
+
+func newCheckpointTestFetcher(client *mockClient, store Storage, path string) *Fetcher {
+	config := &Config{BatchSize: 10, MaxRetries: 1, RetryDelay: time.Millisecond, CheckpointPath: path}
+	return NewFetcher(client, store, config, zap.NewNop(), nil)
+}
+
+func TestCheckpoint_BackfillsLostHeights(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "checkpoint.json")
+	store, err := storagepkg.NewPebbleStorage(storagepkg.DefaultConfig(filepath.Join(dir, "db")))
+	if err != nil {
+		t.Fatalf("NewPebbleStorage() error = %v", err)
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+	client := newMockClient()
+	for h := uint64(0); h <= 10; h++ {
+		client.blocks[h] = types.NewBlockWithHeader(&types.Header{Number: new(big.Int).SetUint64(h), Time: h})
+	}
+	client.latestBlock = 10
+
+	fetcher := newCheckpointTestFetcher(client, store, path)
+	if _, err := fetcher.refreshChainHead(ctx); err != nil {
+		t.Fatalf("refreshChainHead() error = %v", err)
+	}
+	if err := fetcher.FetchRange(ctx, 0, 10); err != nil {
+		t.Fatalf("FetchRange() error = %v", err)
+	}
+	fetcher.maybeWriteCheckpoint(10)
+
+	cp, err := ReadCheckpoint(path)
+	if err != nil {
+		t.Fatalf("ReadCheckpoint() error = %v", err)
+	}
+	if cp.Height != 10 || cp.ChainHead != 10 {
+		t.Fatalf("checkpoint = %+v, want height 10 and chain head 10", cp)
+	}
+
+	// Simulate a crash that lost the unsynced writes for blocks 7..10
+	for h := uint64(7); h <= 10; h++ {
+		if err := store.DeleteBlock(ctx, h); err != nil {
+			t.Fatalf("DeleteBlock(%d) error = %v", h, err)
+		}
+	}
+	if err := store.SetLatestHeight(ctx, 6); err != nil {
+		t.Fatalf("SetLatestHeight() error = %v", err)
+	}
+
+	restarted := newCheckpointTestFetcher(client, store, path)
+	restarted.reconcileCheckpoint(ctx)
+
+	for h := uint64(7); h <= 10; h++ {
+		if _, err := store.GetBlock(ctx, h); err != nil {
+			t.Errorf("GetBlock(%d) after reconcile error = %v", h, err)
+		}
+	}
+	latest, err := store.GetLatestHeight(ctx)
+	if err != nil {
+		t.Fatalf("GetLatestHeight() error = %v", err)
+	}
+	if latest != 10 {
+		t.Errorf("GetLatestHeight() = %d, want 10", latest)
+	}
+	if next := restarted.GetNextHeight(ctx); next != 11 {
+		t.Errorf("GetNextHeight() = %d, want 11", next)
+	}
+}
+
+func TestCheckpoint_NoBackfillWhenStorageAhead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	if err := WriteCheckpoint(path, &Checkpoint{Height: 5}); err != nil {
+		t.Fatalf("WriteCheckpoint() error = %v", err)
+	}
+
+	store := newMockStorage()
+	store.latestHeight = 8
+	// Any fetch would fail since the client has no blocks
+	client := newMockClient()
+
+	fetcher := newCheckpointTestFetcher(client, store, path)
+	fetcher.reconcileCheckpoint(context.Background())
+
+	if len(store.blocks) != 0 {
+		t.Errorf("reconcile stored %d blocks, want 0", len(store.blocks))
+	}
+}
+
+func TestCheckpoint_IntervalThrottlesWrites(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	fetcher := newCheckpointTestFetcher(newMockClient(), newMockStorage(), path)
+	fetcher.config.CheckpointInterval = time.Hour
+
+	fetcher.maybeWriteCheckpoint(1)
+	fetcher.maybeWriteCheckpoint(2)
+
+	cp, err := ReadCheckpoint(path)
+	if err != nil {
+		t.Fatalf("ReadCheckpoint() error = %v", err)
+	}
+	if cp.Height != 1 {
+		t.Errorf("checkpoint height = %d, want 1 (second write within interval)", cp.Height)
+	}
+}
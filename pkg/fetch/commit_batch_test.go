@@ -0,0 +1,158 @@
+package fetch
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"go.uber.org/zap"
+
+	storagepkg "github.com/0xmhha/indexer-go/pkg/storage"
+)
+
+const (
+	commitBatchTestBlocks  = 90
+	commitBatchTestFunding = 1_000_000_000
+	commitBatchTestValue   = 1000
+)
+
+// setupTransferChain adds blocks 1..latest to the mock client, each with one
+// transfer from the returned sender to balanceTestRecipient
+func setupTransferChain(tb testing.TB, client *mockClient, latest uint64) common.Address {
+	tb.Helper()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		tb.Fatalf("GenerateKey() error = %v", err)
+	}
+	signer := types.LatestSignerForChainID(balanceTestChainID)
+
+	for height := uint64(1); height <= latest; height++ {
+		tx, err := types.SignTx(types.NewTransaction(height-1, balanceTestRecipient, big.NewInt(commitBatchTestValue), 21000, big.NewInt(1), nil), signer, key)
+		if err != nil {
+			tb.Fatalf("SignTx() error = %v", err)
+		}
+		block := types.NewBlockWithHeader(&types.Header{
+			Number:     new(big.Int).SetUint64(height),
+			Difficulty: big.NewInt(1),
+			GasLimit:   8000000,
+			GasUsed:    21000,
+		}).WithBody(types.Body{Transactions: []*types.Transaction{tx}})
+
+		client.blocks[height] = block
+		client.receipts[block.Hash()] = types.Receipts{{
+			Status:            types.ReceiptStatusSuccessful,
+			CumulativeGasUsed: 21000,
+			GasUsed:           21000,
+			TxHash:            tx.Hash(),
+			BlockNumber:       new(big.Int).SetUint64(height),
+			Logs:              []*types.Log{},
+		}}
+	}
+	client.latestBlock = latest
+	return crypto.PubkeyToAddress(key.PublicKey)
+}
+
+// newCommitBatchStore opens a storage with the sender funded before block 1
+func newCommitBatchStore(tb testing.TB, sender common.Address) *storagepkg.PebbleStorage {
+	tb.Helper()
+
+	store := setupTestStorage(tb)
+	if err := store.SetBalance(context.Background(), sender, 0, big.NewInt(commitBatchTestFunding)); err != nil {
+		tb.Fatalf("SetBalance() error = %v", err)
+	}
+	return store
+}
+
+func TestFetchRange_CommitBatchBlocks(t *testing.T) {
+	client := newMockClient()
+	sender := setupTransferChain(t, client, commitBatchTestBlocks)
+
+	for _, commitBatchBlocks := range []int{1, 50} {
+		for _, concurrent := range []bool{false, true} {
+			t.Run(fmt.Sprintf("N=%d/concurrent=%t", commitBatchBlocks, concurrent), func(t *testing.T) {
+				ctx := context.Background()
+				store := newCommitBatchStore(t, sender)
+				defer store.Close()
+
+				config := &Config{
+					BatchSize:         10,
+					MaxRetries:        1,
+					RetryDelay:        time.Millisecond,
+					NumWorkers:        4,
+					CommitBatchBlocks: commitBatchBlocks,
+				}
+				fetcher := NewFetcher(client, store, config, zap.NewNop(), nil)
+
+				fetch := fetcher.FetchRange
+				if concurrent {
+					fetch = fetcher.FetchRangeConcurrent
+				}
+				if err := fetch(ctx, 1, commitBatchTestBlocks); err != nil {
+					t.Fatalf("fetch error = %v", err)
+				}
+
+				latest, err := store.GetLatestHeight(ctx)
+				if err != nil {
+					t.Fatalf("GetLatestHeight() error = %v", err)
+				}
+				if latest != commitBatchTestBlocks {
+					t.Errorf("latest height = %d, want %d", latest, commitBatchTestBlocks)
+				}
+
+				for height := uint64(1); height <= commitBatchTestBlocks; height++ {
+					if _, err := store.GetBlock(ctx, height); err != nil {
+						t.Fatalf("GetBlock(%d) error = %v", height, err)
+					}
+					tx := client.blocks[height].Transactions()[0]
+					if _, err := store.GetReceipt(ctx, tx.Hash()); err != nil {
+						t.Fatalf("GetReceipt(%d) error = %v", height, err)
+					}
+				}
+
+				// Balances carry over between blocks of the same batch. The
+				// recipient's first entry is the zero balance initialized from RPC.
+				assertBalance(t, store, sender, commitBatchTestFunding-commitBatchTestBlocks*(commitBatchTestValue+21000), commitBatchTestBlocks+1)
+				assertBalance(t, store, balanceTestRecipient, commitBatchTestBlocks*commitBatchTestValue, commitBatchTestBlocks+1)
+			})
+		}
+	}
+}
+
+func BenchmarkFetchRange_CommitBatchBlocks(b *testing.B) {
+	client := newMockClient()
+	sender := setupTransferChain(b, client, commitBatchTestBlocks)
+
+	for _, commitBatchBlocks := range []int{1, 50} {
+		b.Run(fmt.Sprintf("N=%d", commitBatchBlocks), func(b *testing.B) {
+			ctx := context.Background()
+			config := &Config{
+				BatchSize:         10,
+				MaxRetries:        1,
+				RetryDelay:        time.Millisecond,
+				CommitBatchBlocks: commitBatchBlocks,
+			}
+
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				store := newCommitBatchStore(b, sender)
+				fetcher := NewFetcher(client, store, config, zap.NewNop(), nil)
+				b.StartTimer()
+
+				if err := fetcher.FetchRange(ctx, 1, commitBatchTestBlocks); err != nil {
+					b.Fatalf("FetchRange() error = %v", err)
+				}
+
+				b.StopTimer()
+				store.Close()
+				b.StartTimer()
+			}
+			b.ReportMetric(float64(commitBatchTestBlocks*b.N)/b.Elapsed().Seconds(), "blocks/s")
+		})
+	}
+}
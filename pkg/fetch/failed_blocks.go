@@ -0,0 +1,150 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	storagepkg "github.com/0xmhha/indexer-go/pkg/storage"
+)
+
+// FailedBlockRetryResult summarizes a RetryFailedBlocks run
+type FailedBlockRetryResult struct {
+	// Retried is the number of dead-letter records that were re-attempted
+	Retried int
+	// Recovered is the number of blocks indexed and cleared from the dead-letter store
+	Recovered int
+	// StillFailing is the number of blocks that failed again and remain recorded
+	StillFailing int
+}
+
+// recordFailedBlock writes a dead-letter record for a block that failed to
+// index, incrementing the attempt count of an existing record. Cancellation
+// is not a failure and is not recorded. Storage errors are only logged so
+// they do not mask the original failure.
+func (f *Fetcher) recordFailedBlock(ctx context.Context, height uint64, cause error) {
+	if ctx.Err() != nil {
+		return
+	}
+	writer, ok := f.storage.(storagepkg.FailedBlockWriter)
+	if !ok {
+		return
+	}
+
+	attempts := 1
+	if reader, ok := f.storage.(storagepkg.FailedBlockReader); ok {
+		if previous, err := reader.GetFailedBlock(ctx, height); err == nil {
+			attempts = previous.Attempts + 1
+		}
+	}
+
+	record := &storagepkg.FailedBlock{
+		Height:   height,
+		Error:    cause.Error(),
+		Attempts: attempts,
+		FailedAt: time.Now().UTC(),
+	}
+	if err := writer.SetFailedBlock(ctx, record); err != nil {
+		f.logger.Warn("Failed to record failed block",
+			zap.Uint64("height", height),
+			zap.Error(err),
+		)
+		return
+	}
+
+	f.logger.Error("Recorded failed block",
+		zap.Uint64("height", height),
+		zap.Int("attempts", attempts),
+		zap.Error(cause),
+	)
+}
+
+// clearFailedBlock removes the dead-letter record of a block that has now
+// been indexed, if there is one
+func (f *Fetcher) clearFailedBlock(ctx context.Context, height uint64) {
+	reader, ok := f.storage.(storagepkg.FailedBlockReader)
+	if !ok {
+		return
+	}
+	writer, ok := f.storage.(storagepkg.FailedBlockWriter)
+	if !ok {
+		return
+	}
+
+	if _, err := reader.GetFailedBlock(ctx, height); err != nil {
+		if !errors.Is(err, storagepkg.ErrNotFound) {
+			f.logger.Warn("Failed to check failed block record",
+				zap.Uint64("height", height),
+				zap.Error(err),
+			)
+		}
+		return
+	}
+
+	if err := writer.DeleteFailedBlock(ctx, height); err != nil {
+		f.logger.Warn("Failed to clear failed block record",
+			zap.Uint64("height", height),
+			zap.Error(err),
+		)
+		return
+	}
+	f.logger.Info("Indexed previously failed block", zap.Uint64("height", height))
+}
+
+// RetryFailedBlocks re-attempts every block in the dead-letter store.
+// Recovered blocks are cleared; blocks that fail again keep their record with
+// an incremented attempt count. The latest indexed height is left unchanged
+// when a retried block lies below it.
+func (f *Fetcher) RetryFailedBlocks(ctx context.Context) (*FailedBlockRetryResult, error) {
+	reader, ok := f.storage.(storagepkg.FailedBlockReader)
+	if !ok {
+		return nil, fmt.Errorf("storage does not support failed block records")
+	}
+
+	records, err := reader.GetFailedBlocks(ctx, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get failed blocks: %w", err)
+	}
+
+	result := &FailedBlockRetryResult{}
+	if len(records) == 0 {
+		f.logger.Info("No failed blocks to retry")
+		return result, nil
+	}
+
+	f.logger.Info("Retrying failed blocks", zap.Int("count", len(records)))
+
+	latest, latestErr := f.storage.GetLatestHeight(ctx)
+	for _, record := range records {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		result.Retried++
+		if err := f.FetchBlock(ctx, record.Height); err != nil {
+			if ctx.Err() != nil {
+				return result, ctx.Err()
+			}
+			result.StillFailing++
+			continue
+		}
+		result.Recovered++
+
+		// FetchBlock moved the latest height down to the retried block
+		if latestErr == nil && latest > record.Height {
+			if err := f.storage.SetLatestHeight(ctx, latest); err != nil {
+				return result, fmt.Errorf("failed to restore latest height to %d: %w", latest, err)
+			}
+		}
+	}
+
+	f.logger.Info("Failed block retry completed",
+		zap.Int("retried", result.Retried),
+		zap.Int("recovered", result.Recovered),
+		zap.Int("still_failing", result.StillFailing),
+	)
+	return result, nil
+}
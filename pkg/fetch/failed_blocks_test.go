@@ -0,0 +1,100 @@
+package fetch
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	storagepkg "github.com/0xmhha/indexer-go/pkg/storage"
+)
+
+func TestFetchBlock_RecordsAndRetriesFailedBlock(t *testing.T) {
+	store := setupTestStorage(t)
+
+	ctx := context.Background()
+	client := newMockClient()
+	addMockChain(client, 5)
+	fetcher := newBalanceTestFetcher(client, store)
+
+	// Fail every attempt (MaxRetries 1 = two fetches) of block 2
+	client.failCount = 2
+	if err := fetcher.FetchBlock(ctx, 2); err == nil {
+		t.Fatal("FetchBlock() should fail while the node errors")
+	}
+
+	record, err := store.GetFailedBlock(ctx, 2)
+	if err != nil {
+		t.Fatalf("GetFailedBlock() error = %v", err)
+	}
+	if record.Attempts != 1 || !strings.Contains(record.Error, "mock error") || record.FailedAt.IsZero() {
+		t.Errorf("failed block record = %+v", record)
+	}
+
+	// A second failure increments the attempt count
+	client.failCount = 2
+	if err := fetcher.FetchBlock(ctx, 2); err == nil {
+		t.Fatal("FetchBlock() should fail while the node errors")
+	}
+	records, err := store.GetFailedBlocks(ctx, 0)
+	if err != nil {
+		t.Fatalf("GetFailedBlocks() error = %v", err)
+	}
+	if len(records) != 1 || records[0].Height != 2 || records[0].Attempts != 2 {
+		t.Fatalf("GetFailedBlocks() = %+v, want block 2 with 2 attempts", records)
+	}
+
+	// Later blocks are indexed while block 2 stays recorded
+	for height := uint64(3); height <= 5; height++ {
+		if err := fetcher.FetchBlock(ctx, height); err != nil {
+			t.Fatalf("FetchBlock(%d) error = %v", height, err)
+		}
+	}
+
+	result, err := fetcher.RetryFailedBlocks(ctx)
+	if err != nil {
+		t.Fatalf("RetryFailedBlocks() error = %v", err)
+	}
+	if result.Retried != 1 || result.Recovered != 1 || result.StillFailing != 0 {
+		t.Errorf("RetryFailedBlocks() = %+v, want 1 retried and recovered", result)
+	}
+
+	if _, err := store.GetFailedBlock(ctx, 2); err != storagepkg.ErrNotFound {
+		t.Errorf("GetFailedBlock() after retry error = %v, want ErrNotFound", err)
+	}
+	if has, err := store.HasBlock(ctx, 2); err != nil || !has {
+		t.Errorf("HasBlock(2) = %v, %v, want true", has, err)
+	}
+	if latest, err := store.GetLatestHeight(ctx); err != nil || latest != 5 {
+		t.Errorf("GetLatestHeight() = %d, %v, want 5", latest, err)
+	}
+}
+
+func TestRetryFailedBlocks_StillFailing(t *testing.T) {
+	store := setupTestStorage(t)
+
+	ctx := context.Background()
+	client := newMockClient()
+	addMockChain(client, 1)
+	fetcher := newBalanceTestFetcher(client, store)
+
+	// Block 7 does not exist on the node, so every retry fails
+	if err := fetcher.FetchBlock(ctx, 7); err == nil {
+		t.Fatal("FetchBlock() of a missing block should fail")
+	}
+
+	result, err := fetcher.RetryFailedBlocks(ctx)
+	if err != nil {
+		t.Fatalf("RetryFailedBlocks() error = %v", err)
+	}
+	if result.Retried != 1 || result.Recovered != 0 || result.StillFailing != 1 {
+		t.Errorf("RetryFailedBlocks() = %+v, want 1 still failing", result)
+	}
+
+	record, err := store.GetFailedBlock(ctx, 7)
+	if err != nil {
+		t.Fatalf("GetFailedBlock() error = %v", err)
+	}
+	if record.Attempts != 2 {
+		t.Errorf("attempts = %d, want 2", record.Attempts)
+	}
+}
@@ -2,14 +2,18 @@ package fetch
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"go.uber.org/zap"
+	"golang.org/x/time/rate"
 
 	"github.com/0xmhha/indexer-go/internal/constants"
 	"github.com/0xmhha/indexer-go/pkg/events"
@@ -39,10 +43,9 @@ type PendingTxClient interface {
 }
 
 // Subscription defines the interface for subscription management
-type Subscription interface {
-	Err() <-chan error
-	Unsubscribe()
-}
+// It aliases ethereum.Subscription so RPC clients returning geth subscriptions
+// satisfy PendingTxClient without an adapter
+type Subscription = ethereum.Subscription
 
 // FeeDelegationMeta contains fee delegation metadata for a transaction
 // This is copied from factory package to avoid circular import
@@ -54,6 +57,8 @@ type FeeDelegationMeta struct {
 	FeePayerV    *big.Int
 	FeePayerR    *big.Int
 	FeePayerS    *big.Int
+	// Sender is the transaction sender reported by the node
+	Sender common.Address
 }
 
 // FeeDelegationClient is an optional interface for clients that support
@@ -63,6 +68,13 @@ type FeeDelegationClient interface {
 	GetBlockWithFeeDelegationMeta(ctx context.Context, number uint64) (*types.Block, []*FeeDelegationMeta, error)
 }
 
+// BlockRangeClient is an optional interface for clients that fetch a
+// contiguous range of blocks in one batched request, used during FetchRange.
+// An error wrapping client.ErrBatchNotSupported turns batching off.
+type BlockRangeClient interface {
+	GetBlocksByRange(ctx context.Context, from, to uint64) ([]*types.Block, error)
+}
+
 // BlockProcessor defines an interface for processing blocks after indexing
 // This is used by external modules like watchlist to hook into block processing
 type BlockProcessor interface {
@@ -97,6 +109,13 @@ type FeeDelegationStorage interface {
 	SetFeeDelegationTxMeta(ctx context.Context, meta *storagepkg.FeeDelegationTxMeta) error
 }
 
+// BatchStorage is an optional interface for storages that support atomic
+// batches. When the batch also implements storagepkg.BalanceWriter, a block,
+// its receipts and its balance changes are committed together.
+type BatchStorage interface {
+	NewBatch() storagepkg.Batch
+}
+
 // ============================================================================
 // Config
 // ============================================================================
@@ -124,6 +143,107 @@ type Config struct {
 
 	// OptimizerConfig holds configuration for adaptive optimization (optional)
 	OptimizerConfig *OptimizerConfig
+
+	// BatchAddressIndex writes transaction address index entries into the
+	// per-block batch with the block and receipts, reserving their sequence
+	// numbers in one step. Reduces lock contention on blocks with many transfers.
+	BatchAddressIndex bool
+
+	// IndexLogAddresses also indexes each transaction under the addresses its
+	// logs involve: emitting contracts and ERC-20/ERC-721 Transfer senders and
+	// recipients, so token transfers show up for the token recipient. Adds
+	// index entries for every log, which is expensive on log-heavy chains.
+	IndexLogAddresses bool
+
+	// CommitBatchBlocks is the number of consecutive blocks written to storage
+	// in one batch with a single sync. Larger values speed up initial sync; a
+	// range shorter than this, as when following the chain head, is committed
+	// as soon as it is fetched. 0 or 1 commits every block on its own.
+	CommitBatchBlocks int
+
+	// CheckpointPath is a file the fetcher periodically fsyncs its progress to.
+	// On startup, heights between the stored latest height and the checkpoint
+	// are re-fetched. Empty disables checkpointing.
+	CheckpointPath string
+
+	// CheckpointInterval is the minimum time between checkpoint writes
+	// If 0, defaults to 10s
+	CheckpointInterval time.Duration
+
+	// SnapshotPath is a JSON state snapshot (balances, total supply, active
+	// minters) as of the block before StartHeight, applied once before indexing
+	// for deployments that skip earlier blocks. Empty disables.
+	SnapshotPath string
+
+	// DisabledSystemEvents lists system contract event categories that are
+	// not indexed, for chains that do not use them
+	DisabledSystemEvents []events.SystemEventCategory
+
+	// AllowedSystemContracts restricts system contract event indexing to logs
+	// from these addresses. Empty indexes every system contract.
+	AllowedSystemContracts []common.Address
+
+	// StrictProposalTransitions reports proposal events with an invalid status
+	// transition as errors instead of skipping them with a warning
+	StrictProposalTransitions bool
+
+	// StartDelay is how long Run waits before fetching the first batch
+	StartDelay time.Duration
+
+	// MaxHead caps the height Run indexes up to at min(chain head, MaxHead)
+	// If 0, there is no cap
+	MaxHead uint64
+
+	// WaitForNodeSync pauses Run while the node reports it is still syncing
+	// (eth_syncing). Requires a client implementing SyncStatusClient.
+	WaitForNodeSync bool
+
+	// TrackCoinbaseBalance credits each block's coinbase with the priority
+	// fees of its transactions plus BlockReward during balance tracking
+	TrackCoinbaseBalance bool
+
+	// BlockReward is the native amount credited to the coinbase per block
+	// when TrackCoinbaseBalance is set. Nil means no reward.
+	BlockReward *big.Int
+
+	// GapRecovery throttles gap filling independently of steady-state
+	// indexing. Zero values fall back to the settings above.
+	GapRecovery GapRecoveryConfig
+
+	// VerifyOnIngest recomputes each fetched block's transactions root, and
+	// its receipts root when receipts are present, and rejects blocks that do
+	// not match their header instead of storing them
+	VerifyOnIngest bool
+
+	// FailOnBlockNumberMismatch fails a block fetch at once when the endpoint
+	// returns a block of another height, instead of retrying it. Set it for a
+	// single node, where every retry reaches the same node; leave it unset
+	// behind a load balancer, where a retry may be routed to a healthy node.
+	FailOnBlockNumberMismatch bool
+
+	// SeedOpeningBalances initializes the balance of an address first seen
+	// during balance tracking from eth_getBalance at the block before
+	// StartHeight, rather than the block before the one it appears in. Blocks
+	// indexed out of order then all apply their deltas to the same opening
+	// balance. Fetched balances are cached per address.
+	SeedOpeningBalances bool
+
+	// SkipPoisonBlocks makes Run and gap filling dead-letter a block whose
+	// data fails to encode or decode and continue with the next height. By
+	// default they stop with a PoisonBlockError, since retrying the block
+	// cannot succeed.
+	SkipPoisonBlocks bool
+
+	// DetectReorgs makes Run check before each batch that the last indexed
+	// block is still on the node's canonical chain. After a reorg, storage is
+	// rolled back to the newest block the node agrees with and the replaced
+	// heights are fetched again. Costs one block request per batch.
+	DetectReorgs bool
+
+	// MaxReorgDepth is how many indexed blocks reorg detection walks back
+	// through looking for the fork point before giving up
+	// If 0, defaults to DefaultMaxReorgDepth
+	MaxReorgDepth uint64
 }
 
 // Validate validates the fetcher configuration
@@ -137,6 +257,12 @@ func (c *Config) Validate() error {
 	if c.RetryDelay <= 0 {
 		return fmt.Errorf("retry delay must be positive")
 	}
+	if c.StartDelay < 0 {
+		return fmt.Errorf("start delay cannot be negative")
+	}
+	if err := c.GapRecovery.Validate(); err != nil {
+		return fmt.Errorf("gap recovery: %w", err)
+	}
 	// NumWorkers can be 0 (will use default)
 	return nil
 }
@@ -178,6 +304,31 @@ type Fetcher struct {
 
 	// userOpProcessor handles ERC-4337 UserOperation indexing
 	userOpProcessor *UserOpProcessor
+
+	// internalTransferProcessor indexes ETH transfers inside contract calls (optional)
+	internalTransferProcessor *InternalTransferProcessor
+
+	// revertReasonProcessor indexes why failed transactions reverted (optional)
+	revertReasonProcessor *RevertReasonProcessor
+
+	// chainHead caches the latest block height reported by the node
+	chainHead atomic.Uint64
+
+	// openingBalances caches balances fetched for SeedOpeningBalances
+	openingBalances *openingBalanceCache
+
+	// batchBlocksUnsupported is set once the RPC endpoint rejects batched block fetches
+	batchBlocksUnsupported atomic.Bool
+
+	// lastCheckpoint is when the progress checkpoint was last written
+	lastCheckpoint time.Time
+
+	// nodeSyncedAt is when the node last reported it is not syncing
+	nodeSyncedAt time.Time
+
+	// resumeCh is non-nil while indexing is paused and closed on Resume
+	resumeCh chan struct{}
+	pauseMu  sync.Mutex
 }
 
 // NewFetcher creates a new Fetcher instance
@@ -188,6 +339,7 @@ func NewFetcher(client Client, storage Storage, config *Config, logger *zap.Logg
 
 	// Initialize large block processor
 	largeBlockProcessor := NewLargeBlockProcessor(storage, logger)
+	largeBlockProcessor.SetIndexLogAddresses(config.IndexLogAddresses)
 
 	// Initialize adaptive optimizer if enabled
 	var optimizer *AdaptiveOptimizer
@@ -198,6 +350,13 @@ func NewFetcher(client Client, storage Storage, config *Config, logger *zap.Logg
 		}
 		optimizer = NewAdaptiveOptimizer(metrics, optimizerConfig, logger)
 
+		// Start from the configured worker count, within the optimizer bounds
+		initialWorkers := config.NumWorkers
+		if initialWorkers == 0 {
+			initialWorkers = constants.DefaultNumWorkers
+		}
+		metrics.SetOptimalWorkerCount(optimizer.clampWorkers(initialWorkers))
+
 		logger.Info("Adaptive optimization enabled",
 			zap.Int("min_workers", optimizerConfig.MinWorkers),
 			zap.Int("max_workers", optimizerConfig.MaxWorkers),
@@ -211,6 +370,9 @@ func NewFetcher(client Client, storage Storage, config *Config, logger *zap.Logg
 	var systemContractEventParser *events.SystemContractEventParser
 	if scWriter, ok := storage.(storagepkg.SystemContractWriter); ok {
 		systemContractEventParser = events.NewSystemContractEventParser(scWriter, logger)
+		systemContractEventParser.SetDisabledCategories(config.DisabledSystemEvents...)
+		systemContractEventParser.SetAllowedContracts(config.AllowedSystemContracts...)
+		systemContractEventParser.SetStrictProposalTransitions(config.StrictProposalTransitions)
 		logger.Info("System contract event parser initialized")
 	} else {
 		logger.Warn("Storage does not support system contract event parsing - continuing without it")
@@ -226,6 +388,7 @@ func NewFetcher(client Client, storage Storage, config *Config, logger *zap.Logg
 		optimizer:                 optimizer,
 		largeBlockProcessor:       largeBlockProcessor,
 		systemContractEventParser: systemContractEventParser,
+		openingBalances:           newOpeningBalanceCache(),
 	}
 }
 
@@ -304,6 +467,18 @@ func (f *Fetcher) SetUserOpProcessor(processor *UserOpProcessor) {
 	f.logger.Info("UserOp processor configured")
 }
 
+// SetInternalTransferProcessor enables internal ETH transfer indexing via block tracing
+func (f *Fetcher) SetInternalTransferProcessor(processor *InternalTransferProcessor) {
+	f.internalTransferProcessor = processor
+	f.logger.Info("Internal transfer processor configured")
+}
+
+// SetRevertReasonProcessor enables revert reason indexing for failed transactions
+func (f *Fetcher) SetRevertReasonProcessor(processor *RevertReasonProcessor) {
+	f.revertReasonProcessor = processor
+	f.logger.Info("Revert reason processor configured")
+}
+
 // AddBlockProcessor adds a block processor to be called after each block is indexed
 // Block processors receive the block and receipts to process (e.g., watchlist, analytics)
 func (f *Fetcher) AddBlockProcessor(processor BlockProcessor) {
@@ -350,31 +525,123 @@ func (f *Fetcher) processBlockWithProcessors(ctx context.Context, block *types.B
 // Core Fetching API
 // ============================================================================
 
-// FetchBlock fetches a single block and its receipts and stores them
+// FetchBlock fetches a single block and its receipts and stores them.
+// A block that fails to index is recorded in the dead-letter store, and its
+// record is cleared once it is indexed.
 func (f *Fetcher) FetchBlock(ctx context.Context, height uint64) error {
-	// Fetch block and receipts with retry logic
-	startTime := time.Now()
-	block, receipts, hadError, err := f.fetchBlockAndReceiptsWithRetry(ctx, height, startTime)
+	return f.fetchBlock(ctx, height, nil)
+}
+
+// fetchBlock is FetchBlock for a block that prefetchBlocks may already have
+// fetched. A nil prefetched block is fetched from the client.
+func (f *Fetcher) fetchBlock(ctx context.Context, height uint64, prefetched *types.Block) error {
+	if err := f.indexBlock(ctx, height, prefetched); err != nil {
+		f.recordFailedBlock(ctx, height, err)
+		return poisonBlockError(height, err)
+	}
+	f.clearFailedBlock(ctx, height)
+	return nil
+}
+
+// indexBlock fetches, stores and indexes a single block
+func (f *Fetcher) indexBlock(ctx context.Context, height uint64, prefetched *types.Block) error {
+	block, receipts, err := f.fetchBlockForIndexing(ctx, height, prefetched)
 	if err != nil {
 		return err
 	}
+	return f.storeAndIndexBlock(ctx, block, receipts)
+}
+
+// storeAndIndexBlock stores and indexes a fetched block
+func (f *Fetcher) storeAndIndexBlock(ctx context.Context, block *types.Block, receipts types.Receipts) error {
+	// Store block, receipts and balance changes in one batch when supported
+	batched, err := f.storeBlockBatch(ctx, block, receipts)
+	if err != nil {
+		return fmt.Errorf("failed to store block %d: %w", block.NumberU64(), err)
+	}
+	return f.indexStoredBlock(ctx, block, receipts, batched)
+}
+
+// indexBlockGroup fetches the blocks in [start, end] and stores them in one
+// batch before indexing each in order. Blocks in prefetched are not fetched
+// again. Failures are recorded in the dead-letter store like FetchBlock does.
+func (f *Fetcher) indexBlockGroup(ctx context.Context, start, end uint64, prefetched map[uint64]*types.Block) error {
+	group := make([]*jobResult, 0, end-start+1)
+	for height := start; height <= end; height++ {
+		block, receipts, err := f.fetchBlockForIndexing(ctx, height, prefetched[height])
+		if err != nil {
+			f.recordFailedBlock(ctx, height, err)
+			return fmt.Errorf("failed to fetch block %d: %w", height, err)
+		}
+		group = append(group, &jobResult{height: height, block: block, receipts: receipts})
+	}
+
+	batched, err := f.storeBlocksBatch(ctx, group)
+	if err != nil {
+		if isPoisonError(err) {
+			// Store the blocks one at a time to find the one that cannot be stored
+			return f.indexBlocksSeparately(ctx, group)
+		}
+		f.recordFailedBlock(ctx, start, err)
+		return fmt.Errorf("failed to store blocks %d-%d: %w", start, end, err)
+	}
+
+	for _, res := range group {
+		if err := f.indexStoredBlock(ctx, res.block, res.receipts, batched); err != nil {
+			f.recordFailedBlock(ctx, res.height, err)
+			return fmt.Errorf("failed to index block %d: %w", res.height, poisonBlockError(res.height, err))
+		}
+		f.clearFailedBlock(ctx, res.height)
+	}
+	return nil
+}
+
+// indexBlocksSeparately stores and indexes fetched blocks one at a time,
+// stopping at the first that fails
+func (f *Fetcher) indexBlocksSeparately(ctx context.Context, group []*jobResult) error {
+	for _, res := range group {
+		if err := f.storeAndIndexBlock(ctx, res.block, res.receipts); err != nil {
+			f.recordFailedBlock(ctx, res.height, err)
+			return fmt.Errorf("failed to index block %d: %w", res.height, poisonBlockError(res.height, err))
+		}
+		f.clearFailedBlock(ctx, res.height)
+	}
+	return nil
+}
+
+// fetchBlockForIndexing fetches a block and its receipts with retry logic and
+// records the request metrics. Only the receipts are fetched for a non-nil
+// prefetched block.
+func (f *Fetcher) fetchBlockForIndexing(ctx context.Context, height uint64, prefetched *types.Block) (*types.Block, types.Receipts, error) {
+	startTime := time.Now()
+	block, receipts, hadError, err := f.fetchBlockAndReceiptsWithRetry(ctx, height, prefetched, startTime)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := f.verifyIngest(block, receipts); err != nil {
+		return nil, nil, err
+	}
 
 	// Record successful fetch metrics
 	if !hadError {
 		f.metrics.RecordRequest(time.Since(startTime), false, false)
 	}
+	return block, receipts, nil
+}
 
-	// Store block
-	if err := f.storage.SetBlock(ctx, block); err != nil {
-		return fmt.Errorf("failed to store block %d: %w", height, err)
-	}
-
-	// Process metadata and indexing
-	if err := f.processBlockMetadata(ctx, block, receipts, height); err != nil {
-		return err
+// indexStoredBlock runs the indexing steps for a block after storeBlockBatch
+// or storeBlocksBatch, storing the block itself when batched is false, and
+// advances the latest height
+func (f *Fetcher) indexStoredBlock(ctx context.Context, block *types.Block, receipts types.Receipts, batched bool) error {
+	height := block.NumberU64()
+	if !batched {
+		if err := f.storage.SetBlock(ctx, block); err != nil {
+			return fmt.Errorf("failed to store block %d: %w", height, err)
+		}
 	}
 
-	// Process fee delegation metadata
+	// Process fee delegation metadata before address indexing, which reads
+	// the senders it records
 	if err := f.processFeeDelegationMetadata(ctx, height); err != nil {
 		// Log but don't fail block processing
 		f.logger.Warn("Fee delegation metadata processing failed",
@@ -383,7 +650,17 @@ func (f *Fetcher) FetchBlock(ctx context.Context, height uint64) error {
 		)
 	}
 
-	// Publish block event
+	// Process metadata and indexing
+	if err := f.processBlockMetadata(ctx, block, receipts, height, batched); err != nil {
+		return err
+	}
+
+	// Store receipts and index logs
+	if err := f.storeAndProcessReceipts(ctx, block, receipts, height, batched); err != nil {
+		return err
+	}
+
+	// Publish block event once its receipts are readable from storage
 	if f.eventBus != nil {
 		blockEvent := events.NewBlockEvent(block)
 		if !f.eventBus.Publish(blockEvent) {
@@ -393,11 +670,6 @@ func (f *Fetcher) FetchBlock(ctx context.Context, height uint64) error {
 		}
 	}
 
-	// Store receipts and index logs
-	if err := f.storeAndProcessReceipts(ctx, block, receipts, height); err != nil {
-		return err
-	}
-
 	// Publish transaction and log events
 	if f.eventBus != nil {
 		f.publishBlockEvents(block, receipts, height)
@@ -431,6 +703,9 @@ func (f *Fetcher) FetchRange(ctx context.Context, start, end uint64) error {
 		zap.Uint64("total", end-start+1),
 	)
 
+	groupSize := uint64(f.commitBatchBlocks())
+	prefetched := make(map[uint64]*types.Block)
+	nextPrefetch := start
 	for height := start; height <= end; height++ {
 		// Check context cancellation
 		select {
@@ -439,20 +714,50 @@ func (f *Fetcher) FetchRange(ctx context.Context, start, end uint64) error {
 		default:
 		}
 
-		// Fetch and store block
-		if err := f.FetchBlock(ctx, height); err != nil {
+		// The next group of blocks stored together, or a single block
+		groupEnd := height + groupSize - 1
+		if groupEnd > end {
+			groupEnd = end
+		}
+
+		// Fetch blocks ahead in BatchSize requests when the client batches
+		if groupEnd >= nextPrefetch {
+			prefetchEnd := nextPrefetch + uint64(f.config.BatchSize) - 1
+			if prefetchEnd < groupEnd {
+				prefetchEnd = groupEnd
+			}
+			if prefetchEnd > end || prefetchEnd < nextPrefetch {
+				prefetchEnd = end
+			}
+			for h, block := range f.prefetchBlocks(ctx, nextPrefetch, prefetchEnd) {
+				prefetched[h] = block
+			}
+			nextPrefetch = prefetchEnd + 1
+		}
+
+		// Fetch and store the group
+		if groupEnd > height {
+			if err := f.indexBlockGroup(ctx, height, groupEnd, prefetched); err != nil {
+				return fmt.Errorf("failed to fetch blocks %d-%d: %w", height, groupEnd, err)
+			}
+		} else if err := f.fetchBlock(ctx, height, prefetched[height]); err != nil {
 			return fmt.Errorf("failed to fetch block %d: %w", height, err)
 		}
+		for h := height; h <= groupEnd; h++ {
+			delete(prefetched, h)
+		}
 
 		// Log progress periodically
-		if (height-start+1)%100 == 0 || height == end {
-			progress := float64(height-start+1) / float64(end-start+1) * 100
+		done := groupEnd - start + 1
+		if done/100 > (height-start)/100 || groupEnd == end {
+			progress := float64(done) / float64(end-start+1) * 100
 			f.logger.Info("Fetch progress",
-				zap.Uint64("current", height),
+				zap.Uint64("current", groupEnd),
 				zap.Uint64("end", end),
 				zap.Float64("progress", progress),
 			)
 		}
+		height = groupEnd
 	}
 
 	f.logger.Info("Completed block range fetch",
@@ -474,6 +779,15 @@ type jobResult struct {
 
 // FetchRangeConcurrent fetches a range of blocks concurrently using a worker pool
 func (f *Fetcher) FetchRangeConcurrent(ctx context.Context, start, end uint64) error {
+	// With adaptive optimization the pool is sized for the maximum worker count
+	// and the limiter controls how many of those workers are active
+	numWorkers, activeWorkers := f.workerPoolSize()
+	return f.fetchRangeConcurrent(ctx, start, end, numWorkers, activeWorkers, nil)
+}
+
+// fetchRangeConcurrent fetches a range with numWorkers workers, of which
+// activeWorkers run at once. A non-nil throttle caps block fetches per second.
+func (f *Fetcher) fetchRangeConcurrent(ctx context.Context, start, end uint64, numWorkers, activeWorkers int, throttle *rate.Limiter) error {
 	// Check context cancellation before starting
 	select {
 	case <-ctx.Done():
@@ -481,16 +795,14 @@ func (f *Fetcher) FetchRangeConcurrent(ctx context.Context, start, end uint64) e
 	default:
 	}
 
-	numWorkers := f.config.NumWorkers
-	if numWorkers == 0 {
-		numWorkers = constants.DefaultNumWorkers // Default worker pool size
-	}
+	limiter := newWorkerLimiter(activeWorkers, f.metrics)
 
 	f.logger.Info("Starting concurrent block range fetch",
 		zap.Uint64("start", start),
 		zap.Uint64("end", end),
 		zap.Uint64("total", end-start+1),
 		zap.Int("workers", numWorkers),
+		zap.Int("active_workers", activeWorkers),
 	)
 
 	totalBlocks := end - start + 1
@@ -505,7 +817,17 @@ func (f *Fetcher) FetchRangeConcurrent(ctx context.Context, start, end uint64) e
 		wg.Add(1)
 		go func(workerID int) {
 			defer wg.Done()
-			for height := range jobs {
+			for {
+				// Park while this worker is above the active limit
+				if err := limiter.Wait(ctx, workerID); err != nil {
+					return
+				}
+
+				height, ok := <-jobs
+				if !ok {
+					return
+				}
+
 				// Check context cancellation
 				select {
 				case <-ctx.Done():
@@ -514,6 +836,13 @@ func (f *Fetcher) FetchRangeConcurrent(ctx context.Context, start, end uint64) e
 				default:
 				}
 
+				if throttle != nil {
+					if err := throttle.Wait(ctx); err != nil {
+						results <- &jobResult{height: height, err: err}
+						return
+					}
+				}
+
 				// Fetch block and receipts with retry logic
 				result := f.fetchBlockJob(ctx, height)
 				results <- result
@@ -545,21 +874,66 @@ func (f *Fetcher) FetchRangeConcurrent(ctx context.Context, start, end uint64) e
 	nextHeight := start
 	processedCount := uint64(0)
 
+	// Blocks of the current group that are stored but not yet indexed
+	pending := 0
+	batched := false
+
 	for result := range results {
 		// Handle errors
 		if result.err != nil {
+			f.recordFailedBlock(ctx, result.height, result.err)
 			return fmt.Errorf("failed to fetch block %d: %w", result.height, result.err)
 		}
 
 		// Store result in map
 		resultMap[result.height] = result
 
+		// Adjust the number of active workers based on recent RPC performance
+		f.scaleWorkers(limiter)
+
 		// Process results in sequential order
 		for {
 			if res, ok := resultMap[nextHeight]; ok {
-				// Store block
-				if err := f.storage.SetBlock(ctx, res.block); err != nil {
-					return fmt.Errorf("failed to store block %d: %w", nextHeight, err)
+				// Store the next group of blocks, their receipts and balance
+				// changes in one batch when supported
+				if pending == 0 {
+					group := readyBlockGroup(resultMap, nextHeight, end, f.commitBatchBlocks())
+					if group == nil {
+						break
+					}
+					var err error
+					batched, err = f.storeBlocksBatch(ctx, group)
+					if err != nil {
+						if !isPoisonError(err) {
+							return fmt.Errorf("failed to store blocks %d-%d: %w", nextHeight, nextHeight+uint64(len(group))-1, err)
+						}
+						// Store the blocks one at a time to find the one that cannot be stored
+						if err := f.indexBlocksSeparately(ctx, group); err != nil {
+							return err
+						}
+						for range group {
+							delete(resultMap, nextHeight)
+							processedCount++
+							nextHeight++
+						}
+						continue
+					}
+					pending = len(group)
+				}
+				if !batched {
+					if err := f.storage.SetBlock(ctx, res.block); err != nil {
+						f.recordFailedBlock(ctx, nextHeight, err)
+						return fmt.Errorf("failed to store block %d: %w", nextHeight, poisonBlockError(nextHeight, err))
+					}
+				}
+
+				// Process fee delegation metadata before address indexing, which
+				// reads the senders it records
+				if err := f.processFeeDelegationMetadata(ctx, nextHeight); err != nil {
+					f.logger.Warn("Fee delegation metadata processing failed",
+						zap.Uint64("height", nextHeight),
+						zap.Error(err),
+					)
 				}
 
 				// Process WBFT metadata
@@ -568,37 +942,30 @@ func (f *Fetcher) FetchRangeConcurrent(ctx context.Context, start, end uint64) e
 				}
 
 				// Process address indexing (contract creation, token transfers)
-				if err := f.processAddressIndexing(ctx, res.block, res.receipts); err != nil {
+				if err := f.processAddressIndexing(ctx, res.block, res.receipts, batched && f.batchesAddressIndex()); err != nil {
 					return fmt.Errorf("failed to process address indexing for block %d: %w", nextHeight, err)
 				}
 
-				// Process native balance tracking
-				if err := f.processBalanceTracking(ctx, res.block, res.receipts); err != nil {
-					return fmt.Errorf("failed to process balance tracking for block %d: %w", nextHeight, err)
-				}
+				// Index internal ETH transfers from block traces
+				f.processInternalTransfers(ctx, res.block)
 
-				// Process fee delegation metadata
-				if err := f.processFeeDelegationMetadata(ctx, nextHeight); err != nil {
-					f.logger.Warn("Fee delegation metadata processing failed",
-						zap.Uint64("height", nextHeight),
-						zap.Error(err),
-					)
-				}
+				// Index revert reasons of failed transactions
+				f.processRevertReasons(ctx, res.block, res.receipts)
 
-				// Publish block event if EventBus is configured
-				if f.eventBus != nil {
-					blockEvent := events.NewBlockEvent(res.block)
-					if !f.eventBus.Publish(blockEvent) {
-						f.logger.Warn("Failed to publish block event (channel full)",
-							zap.Uint64("height", nextHeight),
-						)
+				// Process native balance tracking (already part of the block batch when batched)
+				if !batched {
+					if err := f.processBalanceTracking(ctx, res.block, res.receipts); err != nil {
+						return fmt.Errorf("failed to process balance tracking for block %d: %w", nextHeight, err)
 					}
 				}
 
 				// Store receipts and index logs
 				for _, receipt := range res.receipts {
-					if err := f.storage.SetReceipt(ctx, receipt); err != nil {
-						return fmt.Errorf("failed to store receipt for tx %s: %w", receipt.TxHash.Hex(), err)
+					if !batched {
+						if err := f.storage.SetReceipt(ctx, receipt); err != nil {
+							f.recordFailedBlock(ctx, nextHeight, err)
+							return fmt.Errorf("failed to store receipt for tx %s: %w", receipt.TxHash.Hex(), poisonBlockError(nextHeight, err))
+						}
 					}
 
 					// Index logs from this receipt
@@ -614,6 +981,16 @@ func (f *Fetcher) FetchRangeConcurrent(ctx context.Context, start, end uint64) e
 					}
 				}
 
+				// Publish block event once its receipts are readable from storage
+				if f.eventBus != nil {
+					blockEvent := events.NewBlockEvent(res.block)
+					if !f.eventBus.Publish(blockEvent) {
+						f.logger.Warn("Failed to publish block event (channel full)",
+							zap.Uint64("height", nextHeight),
+						)
+					}
+				}
+
 				// Publish transaction events if EventBus is configured
 				if f.eventBus != nil {
 					transactions := res.block.Transactions()
@@ -645,6 +1022,7 @@ func (f *Fetcher) FetchRangeConcurrent(ctx context.Context, start, end uint64) e
 				if err := f.storage.SetLatestHeight(ctx, nextHeight); err != nil {
 					return fmt.Errorf("failed to update latest height to %d: %w", nextHeight, err)
 				}
+				f.clearFailedBlock(ctx, nextHeight)
 
 				f.logger.Debug("Stored block",
 					zap.Uint64("height", nextHeight),
@@ -655,6 +1033,7 @@ func (f *Fetcher) FetchRangeConcurrent(ctx context.Context, start, end uint64) e
 
 				// Clean up and move to next height
 				delete(resultMap, nextHeight)
+				pending--
 				processedCount++
 				nextHeight++
 
@@ -684,6 +1063,7 @@ func (f *Fetcher) FetchRangeConcurrent(ctx context.Context, start, end uint64) e
 		zap.Uint64("end", end),
 		zap.Uint64("total", totalBlocks),
 		zap.Int("workers", numWorkers),
+		zap.Int("active_workers", limiter.Limit()),
 	)
 
 	return nil
@@ -694,8 +1074,21 @@ func (f *Fetcher) Run(ctx context.Context) error {
 	f.logger.Info("Starting fetcher",
 		zap.Uint64("start_height", f.config.StartHeight),
 		zap.Int("batch_size", f.config.BatchSize),
+		zap.Uint64("max_head", f.config.MaxHead),
 	)
 
+	if err := f.waitStartDelay(ctx); err != nil {
+		f.logger.Info("Fetcher stopped", zap.Error(err))
+		return err
+	}
+	return f.run(ctx)
+}
+
+// run is the fetching loop of Run, entered once the start delay has passed
+func (f *Fetcher) run(ctx context.Context) error {
+	// Re-fetch heights whose NoSync writes were lost in a crash
+	f.reconcileCheckpoint(ctx)
+
 	// Get next height to fetch
 	nextHeight := f.GetNextHeight(ctx)
 
@@ -708,13 +1101,26 @@ func (f *Fetcher) Run(ctx context.Context) error {
 		default:
 		}
 
+		// Hold between batches while paused, so writes stop at a block boundary
+		if err := f.waitWhilePaused(ctx); err != nil {
+			f.logger.Info("Fetcher stopped", zap.Error(err))
+			return err
+		}
+
+		// Pause while the node cannot serve its reported head yet
+		if err := f.waitForNodeSync(ctx); err != nil {
+			f.logger.Info("Fetcher stopped", zap.Error(err))
+			return err
+		}
+
 		// Get latest block from chain
-		latestChainBlock, err := f.client.GetLatestBlockNumber(ctx)
+		chainHead, err := f.refreshChainHead(ctx)
 		if err != nil {
 			f.logger.Error("Failed to get latest block number", zap.Error(err))
 			time.Sleep(f.config.RetryDelay)
 			continue
 		}
+		latestChainBlock := f.targetHeight(chainHead)
 
 		// Check if we're caught up
 		if nextHeight > latestChainBlock {
@@ -726,6 +1132,15 @@ func (f *Fetcher) Run(ctx context.Context) error {
 			continue
 		}
 
+		// Roll back blocks the chain has replaced since the last batch
+		resumeHeight, err := f.checkReorg(ctx, nextHeight)
+		if err != nil {
+			f.logger.Error("Failed to check for chain reorganization", zap.Error(err))
+			time.Sleep(f.config.RetryDelay)
+			continue
+		}
+		nextHeight = resumeHeight
+
 		// Calculate batch end
 		batchEnd := nextHeight + uint64(f.config.BatchSize) - 1
 		if batchEnd > latestChainBlock {
@@ -740,11 +1155,30 @@ func (f *Fetcher) Run(ctx context.Context) error {
 		)
 
 		if err := f.FetchRange(ctx, nextHeight, batchEnd); err != nil {
+			var poison *PoisonBlockError
+			if errors.As(err, &poison) {
+				if !f.config.SkipPoisonBlocks {
+					f.logger.Error("Stopping fetcher on poison block",
+						zap.Uint64("height", poison.Height),
+						zap.Error(poison.Err),
+					)
+					return err
+				}
+				// The block is dead-lettered; continue after it
+				f.logger.Warn("Skipping poison block",
+					zap.Uint64("height", poison.Height),
+					zap.Error(poison.Err),
+				)
+				nextHeight = poison.Height + 1
+				continue
+			}
 			f.logger.Error("Failed to fetch batch", zap.Error(err))
 			time.Sleep(f.config.RetryDelay)
 			continue
 		}
 
+		f.maybeWriteCheckpoint(batchEnd)
+
 		// Update next height
 		nextHeight = batchEnd + 1
 	}
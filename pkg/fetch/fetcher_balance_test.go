@@ -0,0 +1,234 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"go.uber.org/zap"
+
+	storagepkg "github.com/0xmhha/indexer-go/pkg/storage"
+)
+
+var (
+	balanceTestRecipient = common.HexToAddress("0x00000000000000000000000000000000000000c1")
+	balanceTestChainID   = big.NewInt(1337)
+)
+
+// failingCommitStorage hands out batches whose Commit fails, simulating a crash
+// after the block and balance changes were staged but before they were persisted
+type failingCommitStorage struct {
+	*storagepkg.PebbleStorage
+}
+
+func (s *failingCommitStorage) NewBatch() storagepkg.Batch {
+	batch := s.PebbleStorage.NewBatch()
+	return &failingCommitBatch{Batch: batch, BalanceWriter: batch.(storagepkg.BalanceWriter)}
+}
+
+type failingCommitBatch struct {
+	storagepkg.Batch
+	storagepkg.BalanceWriter
+}
+
+func (b *failingCommitBatch) Commit() error {
+	return errors.New("simulated crash before commit")
+}
+
+// setupBalanceBlock creates a block with two signed transfers from a funded sender
+// and registers it with the mock client
+func setupBalanceBlock(t *testing.T, mockClient *mockClient, height uint64) (common.Address, *types.Block) {
+	t.Helper()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+	signer := types.LatestSignerForChainID(balanceTestChainID)
+
+	var txs []*types.Transaction
+	var receipts types.Receipts
+	for nonce, value := range []int64{1000, 2000} {
+		tx, err := types.SignTx(types.NewTransaction(uint64(nonce), balanceTestRecipient, big.NewInt(value), 21000, big.NewInt(1), nil), signer, key)
+		if err != nil {
+			t.Fatalf("SignTx() error = %v", err)
+		}
+		txs = append(txs, tx)
+		receipts = append(receipts, &types.Receipt{
+			Status:            types.ReceiptStatusSuccessful,
+			CumulativeGasUsed: uint64(21000 * (nonce + 1)),
+			GasUsed:           21000,
+			TxHash:            tx.Hash(),
+			BlockNumber:       new(big.Int).SetUint64(height),
+			Logs:              []*types.Log{},
+		})
+	}
+
+	block := types.NewBlockWithHeader(&types.Header{
+		Number:     new(big.Int).SetUint64(height),
+		Difficulty: big.NewInt(1),
+		GasLimit:   8000000,
+		GasUsed:    42000,
+	}).WithBody(types.Body{Transactions: txs})
+
+	mockClient.blocks[height] = block
+	mockClient.receipts[block.Hash()] = receipts
+	return sender, block
+}
+
+// assertBalance checks the latest balance and the number of history entries for an address
+func assertBalance(t *testing.T, store *storagepkg.PebbleStorage, addr common.Address, want int64, wantHistory int) {
+	t.Helper()
+	ctx := context.Background()
+
+	balance, err := store.GetAddressBalance(ctx, addr, 0)
+	if err != nil {
+		t.Fatalf("GetAddressBalance() error = %v", err)
+	}
+	if balance.Cmp(big.NewInt(want)) != 0 {
+		t.Errorf("balance of %s = %s, want %d", addr.Hex(), balance, want)
+	}
+
+	history, err := store.GetBalanceHistory(ctx, addr, 0, 100, 100, 0)
+	if err != nil {
+		t.Fatalf("GetBalanceHistory() error = %v", err)
+	}
+	if len(history) != wantHistory {
+		t.Errorf("history of %s has %d entries, want %d", addr.Hex(), len(history), wantHistory)
+	}
+}
+
+func newBalanceTestFetcher(mockClient *mockClient, store Storage) *Fetcher {
+	config := &Config{BatchSize: 10, MaxRetries: 1, RetryDelay: time.Millisecond}
+	return NewFetcher(mockClient, store, config, zap.NewNop(), nil)
+}
+
+func TestFetchBlock_BalancesCommittedWithBlock(t *testing.T) {
+	store := setupTestStorage(t)
+
+	ctx := context.Background()
+	mockClient := newMockClient()
+	sender, block := setupBalanceBlock(t, mockClient, 1)
+	if err := store.SetBalance(ctx, sender, 0, big.NewInt(1_000_000)); err != nil {
+		t.Fatalf("SetBalance() error = %v", err)
+	}
+
+	if err := newBalanceTestFetcher(mockClient, store).FetchBlock(ctx, 1); err != nil {
+		t.Fatalf("FetchBlock() error = %v", err)
+	}
+
+	if _, err := store.GetBlock(ctx, 1); err != nil {
+		t.Errorf("GetBlock() error = %v", err)
+	}
+	for _, tx := range block.Transactions() {
+		if _, err := store.GetReceipt(ctx, tx.Hash()); err != nil {
+			t.Errorf("GetReceipt(%s) error = %v", tx.Hash().Hex(), err)
+		}
+	}
+
+	// Both transfers from the same sender accumulate within the block batch.
+	// The recipient's first entry is the zero balance initialized from RPC.
+	assertBalance(t, store, sender, 1_000_000-1000-2000-2*21000, 3)
+	assertBalance(t, store, balanceTestRecipient, 3000, 3)
+}
+
+func TestFetchBlock_BatchCommitFailureLeavesNoPartialState(t *testing.T) {
+	store := setupTestStorage(t)
+
+	ctx := context.Background()
+	mockClient := newMockClient()
+	sender, _ := setupBalanceBlock(t, mockClient, 1)
+	if err := store.SetBalance(ctx, sender, 0, big.NewInt(1_000_000)); err != nil {
+		t.Fatalf("SetBalance() error = %v", err)
+	}
+
+	if err := newBalanceTestFetcher(mockClient, &failingCommitStorage{store}).FetchBlock(ctx, 1); err == nil {
+		t.Fatal("FetchBlock() should fail when the block batch cannot be committed")
+	}
+
+	// Neither the block nor any balance change is visible
+	if _, err := store.GetBlock(ctx, 1); !errors.Is(err, storagepkg.ErrNotFound) {
+		t.Errorf("GetBlock() error = %v, want ErrNotFound", err)
+	}
+	assertBalance(t, store, sender, 1_000_000, 1)
+	assertBalance(t, store, balanceTestRecipient, 0, 0)
+
+	// Recovery: indexing the block again produces a consistent state
+	if err := newBalanceTestFetcher(mockClient, store).FetchBlock(ctx, 1); err != nil {
+		t.Fatalf("FetchBlock() retry error = %v", err)
+	}
+	assertBalance(t, store, sender, 1_000_000-1000-2000-2*21000, 3)
+	assertBalance(t, store, balanceTestRecipient, 3000, 3)
+}
+
+func TestFetchBlock_CoinbaseBalance(t *testing.T) {
+	coinbase := common.HexToAddress("0x00000000000000000000000000000000000000cb")
+
+	for _, track := range []bool{false, true} {
+		store := setupTestStorage(t)
+
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatalf("GenerateKey() error = %v", err)
+		}
+		sender := crypto.PubkeyToAddress(key.PublicKey)
+		ctx := context.Background()
+		if err := store.SetBalance(ctx, sender, 0, big.NewInt(1_000_000)); err != nil {
+			t.Fatalf("SetBalance() error = %v", err)
+		}
+
+		// Base fee 5 and tip cap 2 give an effective gas price of 7
+		tx, err := types.SignNewTx(key, types.LatestSignerForChainID(balanceTestChainID), &types.DynamicFeeTx{
+			ChainID:   balanceTestChainID,
+			GasTipCap: big.NewInt(2),
+			GasFeeCap: big.NewInt(10),
+			Gas:       21000,
+			To:        &balanceTestRecipient,
+			Value:     big.NewInt(1000),
+		})
+		if err != nil {
+			t.Fatalf("SignNewTx() error = %v", err)
+		}
+		block := types.NewBlockWithHeader(&types.Header{
+			Number:     big.NewInt(1),
+			Coinbase:   coinbase,
+			Difficulty: big.NewInt(1),
+			GasLimit:   8000000,
+			GasUsed:    21000,
+			BaseFee:    big.NewInt(5),
+		}).WithBody(types.Body{Transactions: []*types.Transaction{tx}})
+
+		mockClient := newMockClient()
+		mockClient.blocks[1] = block
+		mockClient.receipts[block.Hash()] = types.Receipts{{
+			Status:            types.ReceiptStatusSuccessful,
+			CumulativeGasUsed: 21000,
+			GasUsed:           21000,
+			TxHash:            tx.Hash(),
+			BlockNumber:       big.NewInt(1),
+			Logs:              []*types.Log{},
+		}}
+
+		fetcher := newBalanceTestFetcher(mockClient, store)
+		fetcher.config.TrackCoinbaseBalance = track
+		fetcher.config.BlockReward = big.NewInt(1_000_000_000)
+		if err := fetcher.FetchBlock(ctx, 1); err != nil {
+			t.Fatalf("FetchBlock() error = %v", err)
+		}
+
+		assertBalance(t, store, sender, 1_000_000-1000-21000*7, 2)
+		assertBalance(t, store, balanceTestRecipient, 1000, 2)
+		if track {
+			// Block reward plus the tip above the burned base fee
+			assertBalance(t, store, coinbase, 1_000_000_000+21000*2, 2)
+		} else {
+			assertBalance(t, store, coinbase, 0, 0)
+		}
+	}
+}
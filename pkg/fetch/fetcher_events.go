@@ -8,9 +8,13 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"go.uber.org/zap"
 
+	"github.com/0xmhha/indexer-go/pkg/client"
 	"github.com/0xmhha/indexer-go/pkg/events"
 )
 
+// The RPC client must satisfy PendingTxClient for StartPendingTxSubscription to work
+var _ PendingTxClient = (*client.Client)(nil)
+
 // ============================================================================
 // Event Publishing and System Event Detection Methods
 // ============================================================================
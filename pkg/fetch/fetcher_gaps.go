@@ -2,11 +2,13 @@ package fetch
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"go.uber.org/zap"
+	"golang.org/x/time/rate"
 )
 
 // ============================================================================
@@ -27,6 +29,55 @@ func (g GapRange) Size() uint64 {
 	return g.End - g.Start + 1
 }
 
+// GapRecoveryConfig holds the fetch settings used while filling gaps, so a
+// large gap can be recovered without overwhelming the node that also serves
+// steady-state indexing
+type GapRecoveryConfig struct {
+	// Workers is the number of concurrent workers filling a gap
+	// If 0, the fetcher's worker settings are used
+	Workers int
+
+	// BatchSize is the number of blocks of a gap fetched before the next
+	// batch starts. If 0, the whole gap is fetched at once.
+	BatchSize int
+
+	// RateLimit caps the blocks fetched per second while filling gaps
+	// If 0, gap filling is not rate limited
+	RateLimit float64
+}
+
+// Validate validates the gap recovery configuration
+func (c *GapRecoveryConfig) Validate() error {
+	if c.Workers < 0 {
+		return fmt.Errorf("workers cannot be negative")
+	}
+	if c.BatchSize < 0 {
+		return fmt.Errorf("batch size cannot be negative")
+	}
+	if c.RateLimit < 0 {
+		return fmt.Errorf("rate limit cannot be negative")
+	}
+	return nil
+}
+
+// gapWorkerPoolSize returns the worker pool size and active worker count for
+// filling gaps
+func (f *Fetcher) gapWorkerPoolSize() (poolSize, active int) {
+	numWorkers, activeWorkers := f.workerPoolSize()
+	if workers := f.config.GapRecovery.Workers; workers > 0 {
+		return workers, min(workers, activeWorkers)
+	}
+	return numWorkers, activeWorkers
+}
+
+// gapThrottle returns a limiter for gap fetches, or nil when unlimited
+func (f *Fetcher) gapThrottle() *rate.Limiter {
+	if f.config.GapRecovery.RateLimit <= 0 {
+		return nil
+	}
+	return rate.NewLimiter(rate.Limit(f.config.GapRecovery.RateLimit), 1)
+}
+
 // ReceiptGapInfo contains information about missing receipts for a block
 type ReceiptGapInfo struct {
 	BlockNumber     uint64
@@ -110,13 +161,63 @@ func (f *Fetcher) FillGap(ctx context.Context, gap GapRange) error {
 		zap.Uint64("size", gap.Size()),
 	)
 
-	// Use concurrent fetching for larger gaps
-	if gap.Size() > 10 {
-		return f.FetchRangeConcurrent(ctx, gap.Start, gap.End)
+	throttle := f.gapThrottle()
+
+	// Use sequential fetching for small unthrottled gaps
+	if throttle == nil && gap.Size() <= 10 {
+		if err := f.waitWhilePaused(ctx); err != nil {
+			return err
+		}
+		return f.FetchRange(ctx, gap.Start, gap.End)
+	}
+
+	// Fetch larger gaps concurrently, one batch at a time
+	numWorkers, activeWorkers := f.gapWorkerPoolSize()
+	batchSize := gap.Size()
+	if size := f.config.GapRecovery.BatchSize; size > 0 && uint64(size) < batchSize {
+		batchSize = uint64(size)
 	}
 
-	// Use sequential fetching for small gaps
-	return f.FetchRange(ctx, gap.Start, gap.End)
+	for start := gap.Start; start <= gap.End; start += batchSize {
+		end := start + batchSize - 1
+		if end > gap.End || end < start {
+			end = gap.End
+		}
+
+		// Hold between batches while paused, as Run does
+		if err := f.waitWhilePaused(ctx); err != nil {
+			return err
+		}
+		if err := f.fetchRangeConcurrent(ctx, start, end, numWorkers, activeWorkers, throttle); err != nil {
+			return err
+		}
+		if end == gap.End {
+			break
+		}
+	}
+	return nil
+}
+
+// fillGapSkippingPoison fills gap like FillGap. With SkipPoisonBlocks, a
+// poison block inside the gap is left dead-lettered and the rest of the gap
+// is still filled, as Run does for new blocks.
+func (f *Fetcher) fillGapSkippingPoison(ctx context.Context, gap GapRange) error {
+	for {
+		err := f.FillGap(ctx, gap)
+		var poison *PoisonBlockError
+		if err == nil || !f.config.SkipPoisonBlocks || !errors.As(err, &poison) ||
+			poison.Height < gap.Start || poison.Height > gap.End {
+			return err
+		}
+		f.logger.Warn("Skipping poison block in gap",
+			zap.Uint64("height", poison.Height),
+			zap.Error(poison.Err),
+		)
+		if poison.Height == gap.End {
+			return nil
+		}
+		gap.Start = poison.Height + 1
+	}
 }
 
 // FillGaps fills all detected gaps concurrently
@@ -140,7 +241,7 @@ func (f *Fetcher) FillGaps(ctx context.Context, gaps []GapRange) error {
 			zap.Uint64("size", gap.Size()),
 		)
 
-		if err := f.FillGap(ctx, gap); err != nil {
+		if err := f.fillGapSkippingPoison(ctx, gap); err != nil {
 			return fmt.Errorf("failed to fill gap [%d-%d]: %w", gap.Start, gap.End, err)
 		}
 
@@ -295,6 +396,9 @@ func (f *Fetcher) FillReceiptGaps(ctx context.Context, gaps []ReceiptGapInfo) er
 			return ctx.Err()
 		default:
 		}
+		if err := f.waitWhilePaused(ctx); err != nil {
+			return err
+		}
 
 		f.logger.Debug("Filling receipt gap",
 			zap.Int("gap_num", i+1),
@@ -321,8 +425,21 @@ func (f *Fetcher) RunWithGapRecovery(ctx context.Context) error {
 	f.logger.Info("Starting fetcher with gap recovery enabled",
 		zap.Uint64("start_height", f.config.StartHeight),
 		zap.Int("batch_size", f.config.BatchSize),
+		zap.Int("gap_workers", f.config.GapRecovery.Workers),
+		zap.Int("gap_batch_size", f.config.GapRecovery.BatchSize),
+		zap.Float64("gap_rate_limit", f.config.GapRecovery.RateLimit),
 	)
 
+	// Gap filling fetches blocks too, so it waits for the node like Run does
+	if err := f.waitStartDelay(ctx); err != nil {
+		f.logger.Info("Fetcher stopped", zap.Error(err))
+		return err
+	}
+	if err := f.waitForNodeSync(ctx); err != nil {
+		f.logger.Info("Fetcher stopped", zap.Error(err))
+		return err
+	}
+
 	// First, check for gaps in existing data
 	latestHeight, err := f.storage.GetLatestHeight(ctx)
 	if err == nil && latestHeight > f.config.StartHeight {
@@ -360,6 +477,6 @@ func (f *Fetcher) RunWithGapRecovery(ctx context.Context) error {
 		}
 	}
 
-	// Run normal fetching loop
-	return f.Run(ctx)
+	// Run normal fetching loop; the start delay has already passed
+	return f.run(ctx)
 }
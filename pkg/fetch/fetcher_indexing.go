@@ -16,8 +16,10 @@ import (
 // Address Indexing and Balance Tracking Methods
 // ============================================================================
 
-// processAddressIndexing parses and stores address indexing data from block and receipts
-func (f *Fetcher) processAddressIndexing(ctx context.Context, block *types.Block, receipts types.Receipts) error {
+// processAddressIndexing parses and stores address indexing data from block and receipts.
+// Transaction address index entries are skipped when addressTxsStored is set,
+// since storeBlockBatch already added them to the block batch.
+func (f *Fetcher) processAddressIndexing(ctx context.Context, block *types.Block, receipts types.Receipts, addressTxsStored bool) error {
 	// Check if storage implements AddressIndexWriter
 	addressWriter, ok := f.storage.(storagepkg.AddressIndexWriter)
 	if !ok {
@@ -25,29 +27,38 @@ func (f *Fetcher) processAddressIndexing(ctx context.Context, block *types.Block
 		return nil
 	}
 
-	// Check if storage implements Writer for transaction address indexing
-	storageWriter, hasWriter := f.storage.(storagepkg.Writer)
-
 	blockNumber := block.NumberU64()
 	blockTime := block.Time()
 	transactions := block.Transactions()
 
-	// Build receipt map for O(1) lookup (avoids O(n²) matching)
-	receiptMap := buildReceiptMap(receipts)
-
-	// Fee Delegation transaction type constant (StableNet-specific)
-	const FeeDelegateDynamicFeeTxType = 22
-
-	// getFeePayer extracts fee payer from stored fee delegation metadata
-	getFeePayer := func(tx *types.Transaction) *common.Address {
-		if fdReader, ok := f.storage.(storagepkg.FeeDelegationReader); ok {
-			if meta, err := fdReader.GetFeeDelegationTxMeta(ctx, tx.Hash()); err == nil && meta != nil {
-				return &meta.FeePayer
+	// 0. Index transaction addresses (from, to, feePayer and, if enabled, log
+	// addresses) for transactionsByAddress query
+	if storageWriter, ok := f.storage.(storagepkg.Writer); ok && !addressTxsStored {
+		for _, entry := range f.collectAddressTransactions(ctx, block, receipts) {
+			if err := storageWriter.AddTransactionToAddressIndex(ctx, entry.Address, entry.TxHash); err != nil {
+				f.logger.Warn("Failed to index transaction for address",
+					zap.Uint64("block", blockNumber),
+					zap.String("tx", entry.TxHash.Hex()),
+					zap.String("address", entry.Address.Hex()),
+					zap.Error(err),
+				)
 			}
 		}
-		return nil
 	}
 
+	// Update running per-address aggregates (tx count, value sent/received)
+	if statsWriter, ok := f.storage.(storagepkg.AddressStatsWriter); ok {
+		if err := statsWriter.IndexAddressStats(ctx, block, receipts); err != nil {
+			f.logger.Warn("Failed to update address stats",
+				zap.Uint64("block", blockNumber),
+				zap.Error(err),
+			)
+		}
+	}
+
+	// Build receipt map for O(1) lookup (avoids O(n²) matching)
+	receiptMap := buildReceiptMap(receipts)
+
 	// Process each transaction and its receipt
 	for txIdx, tx := range transactions {
 		// O(1) receipt lookup
@@ -56,56 +67,6 @@ func (f *Fetcher) processAddressIndexing(ctx context.Context, block *types.Block
 			continue
 		}
 
-		// 0. Index transaction addresses (from, to, feePayer) for transactionsByAddress query
-		if hasWriter {
-			txHash := tx.Hash()
-
-			// Index 'from' address
-			from := getTransactionSender(tx)
-			if from != (common.Address{}) {
-				if err := storageWriter.AddTransactionToAddressIndex(ctx, from, txHash); err != nil {
-					f.logger.Warn("Failed to index transaction for from address",
-						zap.Uint64("block", blockNumber),
-						zap.String("tx", txHash.Hex()),
-						zap.String("from", from.Hex()),
-						zap.Error(err),
-					)
-				}
-			}
-
-			// Index 'to' address (if not contract creation)
-			if tx.To() != nil {
-				to := *tx.To()
-				if to != from { // Avoid duplicate indexing for self-transfers
-					if err := storageWriter.AddTransactionToAddressIndex(ctx, to, txHash); err != nil {
-						f.logger.Warn("Failed to index transaction for to address",
-							zap.Uint64("block", blockNumber),
-							zap.String("tx", txHash.Hex()),
-							zap.String("to", to.Hex()),
-							zap.Error(err),
-						)
-					}
-				}
-			}
-
-			// Index 'feePayer' address for Fee Delegation transactions (type 0x16)
-			if tx.Type() == FeeDelegateDynamicFeeTxType {
-				if feePayer := getFeePayer(tx); feePayer != nil {
-					// Avoid duplicate indexing if feePayer is same as from or to
-					if *feePayer != from && (tx.To() == nil || *feePayer != *tx.To()) {
-						if err := storageWriter.AddTransactionToAddressIndex(ctx, *feePayer, txHash); err != nil {
-							f.logger.Warn("Failed to index transaction for feePayer address",
-								zap.Uint64("block", blockNumber),
-								zap.String("tx", txHash.Hex()),
-								zap.String("feePayer", feePayer.Hex()),
-								zap.Error(err),
-							)
-						}
-					}
-				}
-			}
-		}
-
 		// 1. Contract Creation Detection
 		// Contract creation is indicated by tx.To() == nil
 		if tx.To() == nil && receipt.ContractAddress != (common.Address{}) {
@@ -243,9 +204,103 @@ func (f *Fetcher) processAddressIndexing(ctx context.Context, block *types.Block
 	return nil
 }
 
+// collectAddressTransactions returns the transaction address index entries for a
+// block in indexing order: for each transaction with a receipt, the addresses
+// returned by transactionAddresses. With Config.IndexLogAddresses the addresses
+// involved in its logs are included as well.
+func (f *Fetcher) collectAddressTransactions(ctx context.Context, block *types.Block, receipts types.Receipts) []storagepkg.AddressTransactionEntry {
+	receiptMap := buildReceiptMap(receipts)
+	transactions := block.Transactions()
+	entries := make([]storagepkg.AddressTransactionEntry, 0, 2*len(transactions))
+
+	for _, tx := range transactions {
+		receipt := receiptMap[tx.Hash()]
+		if receipt == nil {
+			continue
+		}
+		txHash := tx.Hash()
+		for _, addr := range transactionAddresses(tx, receipt, lookupFeePayer(ctx, f.storage, tx), f.config.IndexLogAddresses) {
+			entries = append(entries, storagepkg.AddressTransactionEntry{Address: addr, TxHash: txHash})
+		}
+	}
+
+	return entries
+}
+
+// feeDelegateDynamicFeeTxType is the StableNet fee delegation transaction type (0x16)
+const feeDelegateDynamicFeeTxType = 22
+
+// lookupFeePayer returns the fee payer of a fee delegation transaction from the
+// stored fee delegation metadata, or nil for other transactions
+func lookupFeePayer(ctx context.Context, storage Storage, tx *types.Transaction) *common.Address {
+	if tx.Type() != feeDelegateDynamicFeeTxType {
+		return nil
+	}
+	fdReader, ok := storage.(storagepkg.FeeDelegationReader)
+	if !ok {
+		return nil
+	}
+	meta, err := fdReader.GetFeeDelegationTxMeta(ctx, tx.Hash())
+	if err != nil || meta == nil {
+		return nil
+	}
+	return &meta.FeePayer
+}
+
+// transactionAddresses returns the addresses a transaction is indexed under, in
+// order: sender, to-address (unless a contract creation) and fee payer, then with
+// includeLogs the addresses from logAddresses. Each address appears once and the
+// zero address is never included.
+func transactionAddresses(tx *types.Transaction, receipt *types.Receipt, feePayer *common.Address, includeLogs bool) []common.Address {
+	addrs := make([]common.Address, 0, 3)
+	seen := make(map[common.Address]bool, 3)
+	add := func(addr common.Address) {
+		if addr == (common.Address{}) || seen[addr] {
+			return
+		}
+		seen[addr] = true
+		addrs = append(addrs, addr)
+	}
+
+	add(getTransactionSender(tx))
+	if tx.To() != nil {
+		add(*tx.To())
+	}
+	if feePayer != nil {
+		add(*feePayer)
+	}
+	if includeLogs && receipt != nil {
+		for _, addr := range logAddresses(receipt.Logs) {
+			add(addr)
+		}
+	}
+	return addrs
+}
+
+// erc20TransferTopic is storagepkg.ERC20TransferTopic as a hash
+var erc20TransferTopic = common.HexToHash(storagepkg.ERC20TransferTopic)
+
+// logAddresses returns the addresses involved in logs: each emitting contract
+// and the from and to addresses of ERC-20 and ERC-721 Transfer events, which
+// share the Transfer(address,address,uint256) signature with both addresses indexed.
+// The result may contain duplicates.
+func logAddresses(logs []*types.Log) []common.Address {
+	var addrs []common.Address
+	for _, log := range logs {
+		if log == nil {
+			continue
+		}
+		addrs = append(addrs, log.Address)
+		if len(log.Topics) >= 3 && log.Topics[0] == erc20TransferTopic {
+			addrs = append(addrs, common.BytesToAddress(log.Topics[1].Bytes()), common.BytesToAddress(log.Topics[2].Bytes()))
+		}
+	}
+	return addrs
+}
+
 // ensureAddressBalanceInitialized checks if an address has balance history,
 // and if not, fetches the current balance from RPC and initializes it
-func (f *Fetcher) ensureAddressBalanceInitialized(ctx context.Context, histReader storagepkg.HistoricalReader, histWriter storagepkg.HistoricalWriter, addr common.Address, blockNumber uint64) error {
+func (f *Fetcher) ensureAddressBalanceInitialized(ctx context.Context, histReader storagepkg.HistoricalReader, histWriter storagepkg.BalanceWriter, addr common.Address, blockNumber uint64) error {
 	// Check if address already has balance history
 	currentBalance, err := histReader.GetAddressBalance(ctx, addr, 0)
 	if err != nil {
@@ -269,16 +324,21 @@ func (f *Fetcher) ensureAddressBalanceInitialized(ctx context.Context, histReade
 	}
 
 	// No history found - this is the first time we see this address
-	// Fetch the actual balance from RPC at the block BEFORE this transaction
-	var rpcBlockNumber *big.Int
-	if blockNumber > 0 {
-		rpcBlockNumber = new(big.Int).SetUint64(blockNumber - 1)
+	var rpcBalance *big.Int
+	if f.config.SeedOpeningBalances {
+		// Seed from the state before indexing started
+		rpcBalance, err = f.openingBalance(ctx, addr)
 	} else {
-		// Genesis block - use block 0
-		rpcBlockNumber = big.NewInt(0)
+		// Fetch the actual balance from RPC at the block BEFORE this transaction
+		var rpcBlockNumber *big.Int
+		if blockNumber > 0 {
+			rpcBlockNumber = new(big.Int).SetUint64(blockNumber - 1)
+		} else {
+			// Genesis block - use block 0
+			rpcBlockNumber = big.NewInt(0)
+		}
+		rpcBalance, err = f.client.BalanceAt(ctx, addr, rpcBlockNumber)
 	}
-
-	rpcBalance, err := f.client.BalanceAt(ctx, addr, rpcBlockNumber)
 	if err != nil {
 		// Log warning but don't fail - balance tracking is best-effort
 		f.logger.Warn("Failed to fetch initial balance from RPC, starting from 0",
@@ -453,12 +513,32 @@ func (f *Fetcher) processBalanceTracking(ctx context.Context, block *types.Block
 		return nil
 	}
 
+	return f.trackBalances(ctx, block, receipts, histReader, histWriter, make(map[common.Address]bool))
+}
+
+// trackBalances records the balance changes of a block through histWriter, which
+// is either the storage itself or a block batch. histReader only sees committed
+// data, so addresses initialized or updated earlier in the same batch are
+// remembered in initialized.
+func (f *Fetcher) trackBalances(ctx context.Context, block *types.Block, receipts types.Receipts, histReader storagepkg.HistoricalReader, histWriter storagepkg.BalanceWriter, initialized map[common.Address]bool) error {
 	blockNumber := block.NumberU64()
 	transactions := block.Transactions()
 
 	// Build receipt map for O(1) lookup (avoids O(n²) matching)
 	receiptMap := buildReceiptMap(receipts)
 
+	// Priority fees paid to the coinbase by this block's transactions
+	coinbaseFees := new(big.Int)
+	baseFee := block.BaseFee()
+
+	ensureInitialized := func(addr common.Address) error {
+		if initialized[addr] {
+			return nil
+		}
+		initialized[addr] = true
+		return f.ensureAddressBalanceInitialized(ctx, histReader, histWriter, addr, blockNumber)
+	}
+
 	// Track balance changes for each transaction
 	for _, tx := range transactions {
 		// O(1) receipt lookup
@@ -476,12 +556,18 @@ func (f *Fetcher) processBalanceTracking(ctx context.Context, block *types.Block
 
 		// Calculate gas cost (gas used * effective gas price)
 		gasUsed := new(big.Int).SetUint64(receipt.GasUsed)
-		gasPrice := tx.GasPrice()
-		if gasPrice == nil {
-			gasPrice = big.NewInt(0)
-		}
+		gasPrice := storagepkg.EffectiveGasPrice(tx, receipt, baseFee)
 		gasCost := new(big.Int).Mul(gasUsed, gasPrice)
 
+		// The coinbase receives the gas price above the burned base fee
+		tip := new(big.Int).Set(gasPrice)
+		if baseFee != nil {
+			tip.Sub(tip, baseFee)
+		}
+		if tip.Sign() > 0 {
+			coinbaseFees.Add(coinbaseFees, tip.Mul(tip, gasUsed))
+		}
+
 		// Calculate total deduction from sender: value + gas cost
 		value := tx.Value()
 		if value == nil {
@@ -490,7 +576,7 @@ func (f *Fetcher) processBalanceTracking(ctx context.Context, block *types.Block
 		totalDeduction := new(big.Int).Add(value, gasCost)
 
 		// Ensure sender address balance is initialized from RPC if first time seeing it
-		if err := f.ensureAddressBalanceInitialized(ctx, histReader, histWriter, from, blockNumber); err != nil {
+		if err := ensureInitialized(from); err != nil {
 			f.logger.Warn("Failed to initialize sender balance",
 				zap.String("address", from.Hex()),
 				zap.Uint64("block", blockNumber),
@@ -522,7 +608,7 @@ func (f *Fetcher) processBalanceTracking(ctx context.Context, block *types.Block
 
 		if to != nil && value.Sign() > 0 {
 			// Ensure receiver address balance is initialized from RPC if first time seeing it
-			if err := f.ensureAddressBalanceInitialized(ctx, histReader, histWriter, *to, blockNumber); err != nil {
+			if err := ensureInitialized(*to); err != nil {
 				f.logger.Warn("Failed to initialize receiver balance",
 					zap.String("address", to.Hex()),
 					zap.Uint64("block", blockNumber),
@@ -545,6 +631,10 @@ func (f *Fetcher) processBalanceTracking(ctx context.Context, block *types.Block
 		}
 	}
 
+	if f.config.TrackCoinbaseBalance {
+		f.creditCoinbase(ctx, block, coinbaseFees, histWriter, ensureInitialized)
+	}
+
 	f.logger.Debug("Processed balance tracking",
 		zap.Uint64("height", blockNumber),
 		zap.Int("transactions", len(transactions)),
@@ -552,3 +642,36 @@ func (f *Fetcher) processBalanceTracking(ctx context.Context, block *types.Block
 
 	return nil
 }
+
+// creditCoinbase credits the block's coinbase with the priority fees of its
+// transactions plus the configured block reward
+func (f *Fetcher) creditCoinbase(ctx context.Context, block *types.Block, fees *big.Int, histWriter storagepkg.BalanceWriter, ensureInitialized func(common.Address) error) {
+	credit := new(big.Int).Set(fees)
+	if f.config.BlockReward != nil {
+		credit.Add(credit, f.config.BlockReward)
+	}
+	if credit.Sign() == 0 {
+		return
+	}
+
+	coinbase := block.Coinbase()
+	blockNumber := block.NumberU64()
+	if err := ensureInitialized(coinbase); err != nil {
+		f.logger.Warn("Failed to initialize coinbase balance",
+			zap.String("address", coinbase.Hex()),
+			zap.Uint64("block", blockNumber),
+			zap.Error(err),
+		)
+		// Continue - balance tracking is best-effort
+	}
+
+	// Rewards and fees are not tied to a single transaction
+	if err := histWriter.UpdateBalance(ctx, coinbase, blockNumber, credit, common.Hash{}); err != nil {
+		f.logger.Warn("Failed to update coinbase balance",
+			zap.Uint64("block", blockNumber),
+			zap.String("coinbase", coinbase.Hex()),
+			zap.String("delta", credit.String()),
+			zap.Error(err),
+		)
+	}
+}
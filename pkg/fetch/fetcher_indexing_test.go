@@ -0,0 +1,206 @@
+package fetch
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"go.uber.org/zap"
+
+	storagepkg "github.com/0xmhha/indexer-go/pkg/storage"
+)
+
+// indexBlocksWithAddressBatching fetches two blocks of transfers into a fresh
+// store and returns the address index of each sender and the shared recipient
+func indexBlocksWithAddressBatching(t *testing.T, batchAddressIndex bool) map[string][]common.Hash {
+	t.Helper()
+
+	store := setupTestStorage(t)
+
+	ctx := context.Background()
+	mockClient := newMockClient()
+	fetcher := newBalanceTestFetcher(mockClient, store)
+	fetcher.config.BatchAddressIndex = batchAddressIndex
+
+	addresses := map[string]common.Address{"recipient": balanceTestRecipient}
+	for height, name := range map[uint64]string{1: "sender1", 2: "sender2"} {
+		sender, _ := setupBalanceBlock(t, mockClient, height)
+		if err := store.SetBalance(ctx, sender, 0, big.NewInt(1_000_000)); err != nil {
+			t.Fatalf("SetBalance() error = %v", err)
+		}
+		addresses[name] = sender
+	}
+
+	for height := uint64(1); height <= 2; height++ {
+		if err := fetcher.FetchBlock(ctx, height); err != nil {
+			t.Fatalf("FetchBlock(%d) error = %v", height, err)
+		}
+	}
+
+	indexed := make(map[string][]common.Hash)
+	for name, addr := range addresses {
+		hashes, err := store.GetTransactionsByAddress(ctx, addr, 100, 0)
+		if err != nil {
+			t.Fatalf("GetTransactionsByAddress(%s) error = %v", name, err)
+		}
+		indexed[name] = hashes
+	}
+	return indexed
+}
+
+func TestProcessAddressIndexing_BatchedMatchesPerEntry(t *testing.T) {
+	perEntry := indexBlocksWithAddressBatching(t, false)
+	batched := indexBlocksWithAddressBatching(t, true)
+
+	wantCounts := map[string]int{"sender1": 2, "sender2": 2, "recipient": 4}
+	for name, want := range wantCounts {
+		if len(perEntry[name]) != want {
+			t.Errorf("per-entry index for %s has %d transactions, want %d", name, len(perEntry[name]), want)
+		}
+		if len(batched[name]) != len(perEntry[name]) {
+			t.Errorf("batched index for %s has %d transactions, per-entry has %d", name, len(batched[name]), len(perEntry[name]))
+		}
+	}
+
+	// Signing keys differ between runs, so compare the recipient's ordering by
+	// block and position through the senders' own indexes
+	for _, index := range []map[string][]common.Hash{perEntry, batched} {
+		want := append(append([]common.Hash{}, index["sender1"]...), index["sender2"]...)
+		got := index["recipient"]
+		if len(got) != len(want) {
+			continue
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("recipient transaction %d = %s, want %s", i, got[i].Hex(), want[i].Hex())
+			}
+		}
+	}
+}
+
+var (
+	tokenTestContract  = common.HexToAddress("0x00000000000000000000000000000000000000d1")
+	tokenTestRecipient = common.HexToAddress("0x00000000000000000000000000000000000000d2")
+)
+
+// setupTokenTransferBlock registers a block at height 1 with one transaction
+// calling tokenTestContract, whose receipt logs an ERC-20 Transfer from the
+// sender to tokenTestRecipient
+func setupTokenTransferBlock(t *testing.T, mockClient *mockClient) (common.Address, *types.Transaction, *types.Receipt) {
+	t.Helper()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+	tx, err := types.SignTx(types.NewTransaction(0, tokenTestContract, big.NewInt(0), 60000, big.NewInt(1), nil), types.LatestSignerForChainID(balanceTestChainID), key)
+	if err != nil {
+		t.Fatalf("SignTx() error = %v", err)
+	}
+
+	block := types.NewBlockWithHeader(&types.Header{
+		Number:     big.NewInt(1),
+		Difficulty: big.NewInt(1),
+		GasLimit:   8000000,
+		GasUsed:    60000,
+	}).WithBody(types.Body{Transactions: []*types.Transaction{tx}})
+	receipt := &types.Receipt{
+		Status:            types.ReceiptStatusSuccessful,
+		CumulativeGasUsed: 60000,
+		GasUsed:           60000,
+		TxHash:            tx.Hash(),
+		BlockNumber:       big.NewInt(1),
+		Logs: []*types.Log{{
+			Address: tokenTestContract,
+			Topics: []common.Hash{
+				erc20TransferTopic,
+				common.BytesToHash(sender.Bytes()),
+				common.BytesToHash(tokenTestRecipient.Bytes()),
+			},
+			Data:        common.LeftPadBytes(big.NewInt(500).Bytes(), 32),
+			BlockNumber: 1,
+			TxHash:      tx.Hash(),
+		}},
+	}
+
+	mockClient.blocks[1] = block
+	mockClient.receipts[block.Hash()] = types.Receipts{receipt}
+	mockClient.latestBlock = 1
+	return sender, tx, receipt
+}
+
+// assertAddressIndexed checks whether the address index of addr holds exactly txHash
+func assertAddressIndexed(t *testing.T, store *storagepkg.PebbleStorage, addr common.Address, txHash common.Hash, want bool) {
+	t.Helper()
+
+	hashes, err := store.GetTransactionsByAddress(context.Background(), addr, 100, 0)
+	if err != nil {
+		t.Fatalf("GetTransactionsByAddress(%s) error = %v", addr.Hex(), err)
+	}
+	if !want {
+		if len(hashes) != 0 {
+			t.Errorf("address %s indexed %d transactions, want none", addr.Hex(), len(hashes))
+		}
+		return
+	}
+	if len(hashes) != 1 || hashes[0] != txHash {
+		t.Errorf("address %s indexed %v, want [%s]", addr.Hex(), hashes, txHash.Hex())
+	}
+}
+
+func TestProcessAddressIndexing_TokenTransferRecipient(t *testing.T) {
+	tests := []struct {
+		name              string
+		indexLogAddresses bool
+		batchAddressIndex bool
+	}{
+		{name: "log addresses disabled"},
+		{name: "log addresses enabled", indexLogAddresses: true},
+		{name: "log addresses enabled, batched", indexLogAddresses: true, batchAddressIndex: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := setupTestStorage(t)
+
+			ctx := context.Background()
+			mockClient := newMockClient()
+			sender, tx, _ := setupTokenTransferBlock(t, mockClient)
+			if err := store.SetBalance(ctx, sender, 0, big.NewInt(1_000_000)); err != nil {
+				t.Fatalf("SetBalance() error = %v", err)
+			}
+
+			fetcher := newBalanceTestFetcher(mockClient, store)
+			fetcher.config.IndexLogAddresses = tt.indexLogAddresses
+			fetcher.config.BatchAddressIndex = tt.batchAddressIndex
+			if err := fetcher.FetchBlock(ctx, 1); err != nil {
+				t.Fatalf("FetchBlock() error = %v", err)
+			}
+
+			// The sender is indexed once even though the Transfer log names it again
+			assertAddressIndexed(t, store, sender, tx.Hash(), true)
+			assertAddressIndexed(t, store, tokenTestContract, tx.Hash(), true)
+			assertAddressIndexed(t, store, tokenTestRecipient, tx.Hash(), tt.indexLogAddresses)
+		})
+	}
+}
+
+func TestLargeBlockProcessor_IndexLogAddresses(t *testing.T) {
+	for _, indexLogAddresses := range []bool{false, true} {
+		store := setupTestStorage(t)
+
+		_, tx, receipt := setupTokenTransferBlock(t, newMockClient())
+		processor := NewLargeBlockProcessor(store, zap.NewNop())
+		processor.SetIndexLogAddresses(indexLogAddresses)
+		if err := processor.processAddressIndexing(context.Background(), tx, receipt, 1, 0, store); err != nil {
+			t.Fatalf("processAddressIndexing() error = %v", err)
+		}
+
+		assertAddressIndexed(t, store, tokenTestRecipient, tx.Hash(), indexLogAddresses)
+		store.Close()
+	}
+}
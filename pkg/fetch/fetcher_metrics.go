@@ -1,6 +1,7 @@
 package fetch
 
 import (
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 )
 
@@ -33,6 +34,10 @@ func (f *Fetcher) LogPerformanceMetrics() {
 		zap.Uint64("blocks_processed", stats.BlocksProcessed),
 		zap.Uint64("receipts_processed", stats.ReceiptsProcessed),
 		zap.Float64("throughput_bps", stats.Throughput),
+		zap.Uint64("mismatched_blocks", stats.MismatchedBlocks),
+		zap.Uint64("rejected_blocks", stats.RejectedBlocks),
+		zap.Bool("paused", stats.Paused),
+		zap.Int("active_workers", stats.ActiveWorkers),
 		zap.Int("optimal_workers", stats.OptimalWorkerCount),
 		zap.Int("optimal_batch_size", stats.OptimalBatchSize),
 		zap.Duration("uptime", stats.Uptime),
@@ -61,3 +66,50 @@ func (f *Fetcher) GetOptimalBatchSize() int {
 	}
 	return f.config.BatchSize
 }
+
+// metricsCollector exports the block check and fetcher state metrics of a
+// Fetcher, reading them from its RPCMetrics on every scrape
+type metricsCollector struct {
+	metrics *RPCMetrics
+
+	mismatchedBlocks *prometheus.Desc
+	rejectedBlocks   *prometheus.Desc
+	paused           *prometheus.Desc
+	activeWorkers    *prometheus.Desc
+}
+
+// NewMetricsCollector returns a Prometheus collector for the metrics of f.
+// Register it once per fetcher, e.g. with prometheus.Register.
+func NewMetricsCollector(f *Fetcher) prometheus.Collector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName("indexer", "fetcher", name), help, nil, nil)
+	}
+	return &metricsCollector{
+		metrics:          f.metrics,
+		mismatchedBlocks: desc("mismatched_blocks_total", "Fetched blocks rejected because their number did not match the requested height"),
+		rejectedBlocks:   desc("rejected_blocks_total", "Fetched blocks rejected because their transactions or receipts root did not match the header"),
+		paused:           desc("paused", "Whether block indexing is paused (1) or running (0)"),
+		activeWorkers:    desc("active_workers", "Current number of active block fetch workers"),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *metricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{c.mismatchedBlocks, c.rejectedBlocks, c.paused, c.activeWorkers} {
+		ch <- d
+	}
+}
+
+// Collect implements prometheus.Collector
+func (c *metricsCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.metrics.GetStats()
+
+	var paused float64
+	if stats.Paused {
+		paused = 1
+	}
+	ch <- prometheus.MustNewConstMetric(c.mismatchedBlocks, prometheus.CounterValue, float64(stats.MismatchedBlocks))
+	ch <- prometheus.MustNewConstMetric(c.rejectedBlocks, prometheus.CounterValue, float64(stats.RejectedBlocks))
+	ch <- prometheus.MustNewConstMetric(c.paused, prometheus.GaugeValue, paused)
+	ch <- prometheus.MustNewConstMetric(c.activeWorkers, prometheus.GaugeValue, float64(stats.ActiveWorkers))
+}
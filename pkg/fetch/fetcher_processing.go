@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"go.uber.org/zap"
 
@@ -15,8 +16,9 @@ import (
 // Block Processing Internal Methods
 // ============================================================================
 
-// fetchBlockAndReceiptsWithRetry fetches block and receipts with exponential backoff retry logic
-func (f *Fetcher) fetchBlockAndReceiptsWithRetry(ctx context.Context, height uint64, startTime time.Time) (*types.Block, types.Receipts, bool, error) {
+// fetchBlockAndReceiptsWithRetry fetches block and receipts with exponential backoff retry logic.
+// A non-nil prefetched block is used instead of fetching the block.
+func (f *Fetcher) fetchBlockAndReceiptsWithRetry(ctx context.Context, height uint64, prefetched *types.Block, startTime time.Time) (*types.Block, types.Receipts, bool, error) {
 	var block *types.Block
 	var receipts types.Receipts
 	var err error
@@ -36,19 +38,30 @@ func (f *Fetcher) fetchBlockAndReceiptsWithRetry(ctx context.Context, height uin
 		}
 
 		// Fetch block - use chain adapter if available (for EIP-4844 compatibility)
-		if f.chainAdapter != nil {
+		switch {
+		case prefetched != nil:
+			block, err = prefetched, nil
+		case f.chainAdapter != nil:
 			block, err = f.chainAdapter.BlockFetcher().GetBlockByNumber(ctx, height)
-		} else {
+		default:
 			block, err = f.client.GetBlockByNumber(ctx, height)
 		}
+		if err == nil {
+			err = f.checkBlockNumber(block, height)
+		}
 		if err != nil {
+			// Fetch a prefetched block again rather than retrying it as is
+			prefetched = nil
 			hadError = true
 			f.logger.Error("Failed to fetch block",
 				zap.Uint64("height", height),
 				zap.Int("attempt", attempt),
 				zap.Error(err),
 			)
-			f.metrics.RecordRequest(time.Since(startTime), true, false)
+			f.metrics.RecordRequest(time.Since(startTime), true, isRateLimitError(err))
+			if !f.retryable(err) {
+				return nil, nil, hadError, fmt.Errorf("failed to fetch block %d: %w", height, err)
+			}
 			if attempt == f.config.MaxRetries {
 				return nil, nil, hadError, fmt.Errorf("failed to fetch block %d after %d attempts: %w", height, f.config.MaxRetries, err)
 			}
@@ -68,7 +81,7 @@ func (f *Fetcher) fetchBlockAndReceiptsWithRetry(ctx context.Context, height uin
 				zap.Int("attempt", attempt),
 				zap.Error(err),
 			)
-			f.metrics.RecordRequest(time.Since(startTime), true, false)
+			f.metrics.RecordRequest(time.Since(startTime), true, isRateLimitError(err))
 			if attempt == f.config.MaxRetries {
 				return nil, nil, hadError, fmt.Errorf("failed to fetch receipts for block %d after %d attempts: %w", height, f.config.MaxRetries, err)
 			}
@@ -116,6 +129,7 @@ func (f *Fetcher) processFeeDelegationMetadata(ctx context.Context, height uint6
 			FeePayerV:    meta.FeePayerV,
 			FeePayerR:    meta.FeePayerR,
 			FeePayerS:    meta.FeePayerS,
+			Sender:       meta.Sender,
 		}
 		if err := fdStorage.SetFeeDelegationTxMeta(ctx, storageMeta); err != nil {
 			f.logger.Warn("Failed to store fee delegation metadata",
@@ -137,21 +151,140 @@ func (f *Fetcher) processFeeDelegationMetadata(ctx context.Context, height uint6
 	return nil
 }
 
+// storeBlockBatch writes the block, its receipts and the native balance changes
+// they cause in a single atomic batch, so a crash cannot leave balances out of
+// sync with the indexed blocks. With Config.BatchAddressIndex the transaction
+// address index entries join the same batch. It returns false without writing
+// anything when the storage cannot batch balance updates; the caller then stores
+// them separately.
+func (f *Fetcher) storeBlockBatch(ctx context.Context, block *types.Block, receipts types.Receipts) (bool, error) {
+	return f.storeBlocksBatch(ctx, []*jobResult{{height: block.NumberU64(), block: block, receipts: receipts}})
+}
+
+// storeBlocksBatch is storeBlockBatch for a group of consecutive blocks, which
+// are committed together with a single sync
+func (f *Fetcher) storeBlocksBatch(ctx context.Context, group []*jobResult) (bool, error) {
+	batchStorage, ok := f.storage.(BatchStorage)
+	if !ok {
+		return false, nil
+	}
+	histReader, ok := f.storage.(storagepkg.HistoricalReader)
+	if !ok {
+		return false, nil
+	}
+
+	batch := batchStorage.NewBatch()
+	defer batch.Close()
+
+	balanceWriter, ok := batch.(storagepkg.BalanceWriter)
+	if !ok {
+		return false, nil
+	}
+
+	// Shared across the group, since balances initialized by an earlier block
+	// are not visible to histReader until the batch commits
+	initialized := make(map[common.Address]bool)
+	for _, res := range group {
+		if err := batch.SetBlock(ctx, res.block); err != nil {
+			return false, fmt.Errorf("failed to add block %d to batch: %w", res.height, err)
+		}
+		if err := batch.SetReceipts(ctx, res.receipts); err != nil {
+			return false, fmt.Errorf("failed to add receipts of block %d to batch: %w", res.height, err)
+		}
+		if err := f.trackBalances(ctx, res.block, res.receipts, histReader, balanceWriter, initialized); err != nil {
+			return false, fmt.Errorf("failed to add balance changes of block %d to batch: %w", res.height, err)
+		}
+		if f.batchesAddressIndex() {
+			if err := addAddressTransactionsToBatch(ctx, batch, f.collectAddressTransactions(ctx, res.block, res.receipts)); err != nil {
+				return false, fmt.Errorf("failed to add address index entries of block %d to batch: %w", res.height, err)
+			}
+		}
+	}
+
+	if err := batch.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit block batch: %w", err)
+	}
+	return true, nil
+}
+
+// commitBatchBlocks returns the number of blocks stored per batch
+func (f *Fetcher) commitBatchBlocks() int {
+	if f.config.CommitBatchBlocks < 1 {
+		return 1
+	}
+	return f.config.CommitBatchBlocks
+}
+
+// readyBlockGroup returns the next group of up to size consecutive results
+// starting at next, or nil while one of them is still being fetched. The group
+// is cut short at end.
+func readyBlockGroup(resultMap map[uint64]*jobResult, next, end uint64, size int) []*jobResult {
+	if remaining := end - next + 1; uint64(size) > remaining {
+		size = int(remaining)
+	}
+	group := make([]*jobResult, 0, size)
+	for height := next; height < next+uint64(size); height++ {
+		res, ok := resultMap[height]
+		if !ok {
+			return nil
+		}
+		group = append(group, res)
+	}
+	return group
+}
+
+// batchesAddressIndex reports whether storeBlockBatch adds the transaction address
+// index entries to the block batch instead of processAddressIndexing writing them
+func (f *Fetcher) batchesAddressIndex() bool {
+	if !f.config.BatchAddressIndex {
+		return false
+	}
+	if _, ok := f.storage.(storagepkg.AddressIndexWriter); !ok {
+		return false
+	}
+	_, ok := f.storage.(storagepkg.Writer)
+	return ok
+}
+
+// addAddressTransactionsToBatch adds address index entries to batch, in one call
+// when the batch supports it
+func addAddressTransactionsToBatch(ctx context.Context, batch storagepkg.Batch, entries []storagepkg.AddressTransactionEntry) error {
+	if bulk, ok := batch.(storagepkg.AddressIndexBatchWriter); ok {
+		return bulk.AddTransactionsToAddressIndex(ctx, entries)
+	}
+	for _, entry := range entries {
+		if err := batch.AddTransactionToAddressIndex(ctx, entry.Address, entry.TxHash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // processBlockMetadata processes WBFT metadata, address indexing, balance tracking, and genesis initialization
-func (f *Fetcher) processBlockMetadata(ctx context.Context, block *types.Block, receipts types.Receipts, height uint64) error {
+// Balance tracking and batched address index entries are skipped when batched is set,
+// since storeBlockBatch already recorded them
+func (f *Fetcher) processBlockMetadata(ctx context.Context, block *types.Block, receipts types.Receipts, height uint64, batched bool) error {
 	// Process WBFT metadata
 	if err := f.processWBFTMetadata(ctx, block); err != nil {
 		return fmt.Errorf("failed to process WBFT metadata for block %d: %w", height, err)
 	}
 
 	// Process address indexing (contract creation, token transfers)
-	if err := f.processAddressIndexing(ctx, block, receipts); err != nil {
+	if err := f.processAddressIndexing(ctx, block, receipts, batched && f.batchesAddressIndex()); err != nil {
 		return fmt.Errorf("failed to process address indexing for block %d: %w", height, err)
 	}
 
+	// Index internal ETH transfers from block traces
+	f.processInternalTransfers(ctx, block)
+
+	// Index revert reasons of failed transactions
+	f.processRevertReasons(ctx, block, receipts)
+
 	// Process native balance tracking
-	if err := f.processBalanceTracking(ctx, block, receipts); err != nil {
-		return fmt.Errorf("failed to process balance tracking for block %d: %w", height, err)
+	if !batched {
+		if err := f.processBalanceTracking(ctx, block, receipts); err != nil {
+			return fmt.Errorf("failed to process balance tracking for block %d: %w", height, err)
+		}
 	}
 
 	// Initialize genesis allocation balances (block 0 only)
@@ -178,7 +311,8 @@ func (f *Fetcher) processBlockMetadata(ctx context.Context, block *types.Block,
 }
 
 // storeAndProcessReceipts stores receipts and indexes logs using appropriate processing strategy
-func (f *Fetcher) storeAndProcessReceipts(ctx context.Context, block *types.Block, receipts types.Receipts, height uint64) error {
+// Receipts are not written again when receiptsStored is set by storeBlockBatch
+func (f *Fetcher) storeAndProcessReceipts(ctx context.Context, block *types.Block, receipts types.Receipts, height uint64, receiptsStored bool) error {
 	// Use large block processor for blocks exceeding threshold
 	if f.largeBlockProcessor.ShouldProcessInBatches(block, receipts) {
 		f.logger.Info("Using parallel processing for large block",
@@ -207,8 +341,10 @@ func (f *Fetcher) storeAndProcessReceipts(ctx context.Context, block *types.Bloc
 	} else {
 		// Standard sequential processing for normal blocks
 		for _, receipt := range receipts {
-			if err := f.storage.SetReceipt(ctx, receipt); err != nil {
-				return fmt.Errorf("failed to store receipt for tx %s: %w", receipt.TxHash.Hex(), err)
+			if !receiptsStored {
+				if err := f.storage.SetReceipt(ctx, receipt); err != nil {
+					return fmt.Errorf("failed to store receipt for tx %s: %w", receipt.TxHash.Hex(), err)
+				}
 			}
 
 			// Index logs from this receipt
@@ -266,17 +402,25 @@ func (f *Fetcher) fetchBlockJob(ctx context.Context, height uint64) *jobResult {
 		}
 
 		// Fetch block - use chain adapter if available (for EIP-4844 compatibility)
+		attemptStart := time.Now()
 		if f.chainAdapter != nil {
 			block, err = f.chainAdapter.BlockFetcher().GetBlockByNumber(ctx, height)
 		} else {
 			block, err = f.client.GetBlockByNumber(ctx, height)
 		}
+		if err == nil {
+			err = f.checkBlockNumber(block, height)
+		}
 		if err != nil {
 			f.logger.Error("Failed to fetch block",
 				zap.Uint64("height", height),
 				zap.Int("attempt", attempt),
 				zap.Error(err),
 			)
+			f.metrics.RecordRequest(time.Since(attemptStart), true, isRateLimitError(err))
+			if !f.retryable(err) {
+				return &jobResult{height: height, err: fmt.Errorf("failed to fetch block: %w", err)}
+			}
 			if attempt == f.config.MaxRetries {
 				return &jobResult{
 					height: height,
@@ -298,6 +442,7 @@ func (f *Fetcher) fetchBlockJob(ctx context.Context, height uint64) *jobResult {
 				zap.Int("attempt", attempt),
 				zap.Error(err),
 			)
+			f.metrics.RecordRequest(time.Since(attemptStart), true, isRateLimitError(err))
 			if attempt == f.config.MaxRetries {
 				return &jobResult{
 					height: height,
@@ -308,9 +453,14 @@ func (f *Fetcher) fetchBlockJob(ctx context.Context, height uint64) *jobResult {
 		}
 
 		// Success - break retry loop
+		f.metrics.RecordRequest(time.Since(attemptStart), false, false)
 		break
 	}
 
+	if err := f.verifyIngest(block, receipts); err != nil {
+		return &jobResult{height: height, err: err}
+	}
+
 	return &jobResult{
 		height:   height,
 		block:    block,
@@ -95,6 +95,19 @@ func newMockStorage() *mockStorage {
 	}
 }
 
+// setupTestStorage opens a pebble storage in a temporary directory that is
+// closed when the test ends
+func setupTestStorage(tb testing.TB) *storage.PebbleStorage {
+	tb.Helper()
+
+	store, err := storage.NewPebbleStorage(storage.DefaultConfig(tb.TempDir()))
+	if err != nil {
+		tb.Fatalf("NewPebbleStorage() error = %v", err)
+	}
+	tb.Cleanup(func() { store.Close() })
+	return store
+}
+
 func (m *mockStorage) GetLatestHeight(ctx context.Context) (uint64, error) {
 	if m.latestHeight == 0 {
 		return 0, fmt.Errorf("no blocks indexed")
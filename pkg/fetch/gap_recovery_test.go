@@ -0,0 +1,117 @@
+package fetch
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+)
+
+// concurrencyMockClient is a mockClient that holds each block request for
+// delay and records the peak number of requests in flight
+type concurrencyMockClient struct {
+	*mockClient
+	delay time.Duration
+
+	mu       sync.Mutex
+	inFlight int
+	peak     int
+}
+
+func (m *concurrencyMockClient) GetBlockByNumber(ctx context.Context, number uint64) (*types.Block, error) {
+	m.mu.Lock()
+	m.inFlight++
+	if m.inFlight > m.peak {
+		m.peak = m.inFlight
+	}
+	m.mu.Unlock()
+
+	time.Sleep(m.delay)
+
+	m.mu.Lock()
+	m.inFlight--
+	m.mu.Unlock()
+	return m.mockClient.GetBlockByNumber(ctx, number)
+}
+
+func (m *concurrencyMockClient) peakInFlight() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.peak
+}
+
+// fillGapWith fills blocks 1..24 with gap recovery settings gapCfg on a
+// fetcher whose steady-state config runs 8 workers
+func fillGapWith(t *testing.T, gapCfg GapRecoveryConfig) (*concurrencyMockClient, time.Duration) {
+	t.Helper()
+
+	client := &concurrencyMockClient{mockClient: newMockClient(), delay: 10 * time.Millisecond}
+	addMockChain(client.mockClient, 24)
+	storage := newMockStorage()
+
+	config := &Config{
+		BatchSize:   24,
+		MaxRetries:  3,
+		RetryDelay:  10 * time.Millisecond,
+		NumWorkers:  8,
+		GapRecovery: gapCfg,
+	}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	fetcher := NewFetcher(client, storage, config, zap.NewNop(), nil)
+
+	started := time.Now()
+	if err := fetcher.FillGap(context.Background(), GapRange{Start: 1, End: 24}); err != nil {
+		t.Fatalf("FillGap() error = %v", err)
+	}
+	elapsed := time.Since(started)
+
+	for height := uint64(1); height <= 24; height++ {
+		if _, ok := storage.blocks[height]; !ok {
+			t.Errorf("block %d was not stored", height)
+		}
+	}
+	return client, elapsed
+}
+
+func TestFillGap_DefaultsToFetchSettings(t *testing.T) {
+	client, _ := fillGapWith(t, GapRecoveryConfig{})
+	if peak := client.peakInFlight(); peak <= 2 {
+		t.Errorf("peak concurrent fetches = %d, want the fetcher's 8 workers to overlap", peak)
+	}
+}
+
+func TestFillGap_HonorsGapRecoveryWorkers(t *testing.T) {
+	client, _ := fillGapWith(t, GapRecoveryConfig{Workers: 2})
+	if peak := client.peakInFlight(); peak > 2 {
+		t.Errorf("peak concurrent fetches = %d, want at most gap recovery's 2 workers", peak)
+	}
+}
+
+func TestFillGap_HonorsGapRecoveryBatchSize(t *testing.T) {
+	// Batches of 3 never have more than 3 blocks in flight, despite 8 workers
+	client, _ := fillGapWith(t, GapRecoveryConfig{BatchSize: 3})
+	if peak := client.peakInFlight(); peak < 2 || peak > 3 {
+		t.Errorf("peak concurrent fetches = %d, want concurrent batches of at most 3", peak)
+	}
+}
+
+func TestFillGap_HonorsGapRecoveryRateLimit(t *testing.T) {
+	// 24 blocks at 200 blocks/s take at least 23 intervals of 5ms
+	_, elapsed := fillGapWith(t, GapRecoveryConfig{RateLimit: 200})
+	if elapsed < 100*time.Millisecond {
+		t.Errorf("FillGap() took %v, want the 200 blocks/s rate limit to spread fetches over >100ms", elapsed)
+	}
+}
+
+func TestGapRecoveryConfig_Validate(t *testing.T) {
+	for _, cfg := range []GapRecoveryConfig{{Workers: -1}, {BatchSize: -1}, {RateLimit: -1}} {
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("Validate(%+v) = nil, want error", cfg)
+		}
+	}
+}
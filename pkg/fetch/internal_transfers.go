@@ -0,0 +1,131 @@
+package fetch
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+
+	"github.com/0xmhha/indexer-go/pkg/client"
+	storagepkg "github.com/0xmhha/indexer-go/pkg/storage"
+)
+
+// BlockTracer traces all transactions of a block with the callTracer
+type BlockTracer interface {
+	TraceBlockByNumber(ctx context.Context, blockNumber uint64) ([]*client.TxTraceResult, error)
+}
+
+// InternalTransferProcessor extracts ETH value transfers made inside contract calls
+// from block traces and indexes them for the sender and receiver
+type InternalTransferProcessor struct {
+	logger  *zap.Logger
+	tracer  BlockTracer
+	storage storagepkg.InternalTransferWriter
+}
+
+// NewInternalTransferProcessor creates a new internal transfer processor
+func NewInternalTransferProcessor(logger *zap.Logger, tracer BlockTracer, storage storagepkg.InternalTransferWriter) *InternalTransferProcessor {
+	return &InternalTransferProcessor{
+		logger:  logger.Named("internal-transfers"),
+		tracer:  tracer,
+		storage: storage,
+	}
+}
+
+// ProcessBlock traces the block and indexes every nested call that moved ETH
+func (p *InternalTransferProcessor) ProcessBlock(ctx context.Context, block *types.Block) error {
+	transactions := block.Transactions()
+	if len(transactions) == 0 {
+		return nil
+	}
+
+	traces, err := p.tracer.TraceBlockByNumber(ctx, block.NumberU64())
+	if err != nil {
+		return err
+	}
+	if len(traces) != len(transactions) {
+		return fmt.Errorf("trace count %d does not match transaction count %d", len(traces), len(transactions))
+	}
+
+	var transfers []*storagepkg.InternalTransaction
+	for i, trace := range traces {
+		if trace == nil || trace.Result == nil || trace.Error != "" {
+			continue
+		}
+
+		txHash := trace.TxHash
+		if txHash == (common.Hash{}) {
+			txHash = transactions[i].Hash()
+		}
+
+		// The root frame is the top-level transaction itself, which is already indexed
+		index := 0
+		for j := range trace.Result.Calls {
+			transfers = collectInternalTransfers(&trace.Result.Calls[j], txHash, block.NumberU64(), 1, &index, transfers)
+		}
+	}
+
+	if len(transfers) == 0 {
+		return nil
+	}
+
+	if err := p.storage.SaveInternalTransfers(ctx, transfers); err != nil {
+		return fmt.Errorf("failed to save internal transfers: %w", err)
+	}
+
+	p.logger.Debug("Indexed internal transfers",
+		zap.Uint64("block", block.NumberU64()),
+		zap.Int("count", len(transfers)),
+	)
+	return nil
+}
+
+// collectInternalTransfers walks a call frame depth-first and appends the frames
+// that moved ETH. Reverted frames and their children are skipped since their
+// value transfers were rolled back. DELEGATECALL frames carry the caller's value
+// without moving it, so they are not counted as transfers.
+func collectInternalTransfers(frame *client.CallFrame, txHash common.Hash, blockNumber uint64, depth int, index *int, out []*storagepkg.InternalTransaction) []*storagepkg.InternalTransaction {
+	if frame.Error != "" {
+		return out
+	}
+
+	if frame.Type != storagepkg.InternalTxTypeDelegateCall && frame.To != nil && frame.Value != nil && frame.Value.ToInt().Sign() > 0 {
+		out = append(out, &storagepkg.InternalTransaction{
+			TransactionHash: txHash,
+			BlockNumber:     blockNumber,
+			Index:           *index,
+			Type:            frame.Type,
+			From:            frame.From,
+			To:              *frame.To,
+			Value:           new(big.Int).Set(frame.Value.ToInt()),
+			Gas:             uint64(frame.Gas),
+			GasUsed:         uint64(frame.GasUsed),
+			Depth:           depth,
+		})
+		*index++
+	}
+
+	for i := range frame.Calls {
+		out = collectInternalTransfers(&frame.Calls[i], txHash, blockNumber, depth+1, index, out)
+	}
+	return out
+}
+
+// processInternalTransfers indexes internal ETH transfers when tracing is enabled
+// Tracing failures are logged and do not fail block indexing, since not every
+// node serves the debug namespace reliably
+func (f *Fetcher) processInternalTransfers(ctx context.Context, block *types.Block) {
+	if f.internalTransferProcessor == nil {
+		return
+	}
+
+	if err := f.internalTransferProcessor.ProcessBlock(ctx, block); err != nil {
+		f.logger.Warn("Failed to index internal transfers",
+			zap.Uint64("height", block.NumberU64()),
+			zap.Error(err),
+		)
+	}
+}
@@ -0,0 +1,228 @@
+package fetch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+
+	"github.com/0xmhha/indexer-go/pkg/client"
+)
+
+var (
+	traceEOA      = common.HexToAddress("0x1000000000000000000000000000000000000001")
+	traceRouter   = common.HexToAddress("0x2000000000000000000000000000000000000002")
+	traceVault    = common.HexToAddress("0x3000000000000000000000000000000000000003")
+	traceReceiver = common.HexToAddress("0x4000000000000000000000000000000000000004")
+	traceLibrary  = common.HexToAddress("0x5000000000000000000000000000000000000005")
+)
+
+// nestedCallTrace is a callTracer result where the EOA calls a router which
+// forwards 3 ETH to a vault, and the vault pays 1 ETH to the receiver. It also
+// contains a DELEGATECALL carrying value, a zero-value STATICCALL and a reverted
+// value transfer, none of which should be indexed.
+const nestedCallTrace = `[{
+	"result": {
+		"type": "CALL",
+		"from": "0x1000000000000000000000000000000000000001",
+		"to": "0x2000000000000000000000000000000000000002",
+		"value": "0x29a2241af62c0000",
+		"gas": "0x30d40",
+		"gasUsed": "0x1d4c0",
+		"calls": [
+			{
+				"type": "CALL",
+				"from": "0x2000000000000000000000000000000000000002",
+				"to": "0x3000000000000000000000000000000000000003",
+				"value": "0x29a2241af62c0000",
+				"gas": "0x1d4c0",
+				"gasUsed": "0x9c40",
+				"calls": [
+					{
+						"type": "CALL",
+						"from": "0x3000000000000000000000000000000000000003",
+						"to": "0x4000000000000000000000000000000000000004",
+						"value": "0xde0b6b3a7640000",
+						"gas": "0x2710",
+						"gasUsed": "0x0"
+					},
+					{
+						"type": "DELEGATECALL",
+						"from": "0x3000000000000000000000000000000000000003",
+						"to": "0x5000000000000000000000000000000000000005",
+						"value": "0x29a2241af62c0000",
+						"gas": "0x2710",
+						"gasUsed": "0x100"
+					}
+				]
+			},
+			{
+				"type": "STATICCALL",
+				"from": "0x2000000000000000000000000000000000000002",
+				"to": "0x5000000000000000000000000000000000000005",
+				"gas": "0x2710",
+				"gasUsed": "0x100"
+			},
+			{
+				"type": "CALL",
+				"from": "0x2000000000000000000000000000000000000002",
+				"to": "0x4000000000000000000000000000000000000004",
+				"value": "0x1",
+				"gas": "0x2710",
+				"gasUsed": "0x2710",
+				"error": "execution reverted"
+			}
+		]
+	}
+}]`
+
+// fakeTracer returns a fixed callTracer response
+type fakeTracer struct {
+	response string
+	err      error
+	calls    int
+}
+
+func (t *fakeTracer) TraceBlockByNumber(ctx context.Context, blockNumber uint64) ([]*client.TxTraceResult, error) {
+	t.calls++
+	if t.err != nil {
+		return nil, t.err
+	}
+	var results []*client.TxTraceResult
+	if err := json.Unmarshal([]byte(t.response), &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// createTracedBlock creates a block with a single transaction from the EOA to the router
+func createTracedBlock(height uint64) *types.Block {
+	tx := types.NewTransaction(0, traceRouter, big.NewInt(3e18), 200000, big.NewInt(1), nil)
+	return types.NewBlockWithHeader(&types.Header{
+		Number:     big.NewInt(int64(height)),
+		Difficulty: big.NewInt(1),
+		GasLimit:   8000000,
+	}).WithBody(types.Body{Transactions: []*types.Transaction{tx}})
+}
+
+func TestInternalTransferProcessor_NestedCalls(t *testing.T) {
+	store := setupTestStorage(t)
+	processor := NewInternalTransferProcessor(zap.NewNop(), &fakeTracer{response: nestedCallTrace}, store)
+	block := createTracedBlock(10)
+	ctx := context.Background()
+
+	if err := processor.ProcessBlock(ctx, block); err != nil {
+		t.Fatalf("ProcessBlock() error = %v", err)
+	}
+
+	txHash := block.Transactions()[0].Hash()
+	threeEth := new(big.Int).Mul(big.NewInt(3), big.NewInt(1e18))
+
+	tests := []struct {
+		name      string
+		addr      common.Address
+		wantCount int
+		wantFirst *big.Int
+	}{
+		{"router as sender", traceRouter, 1, threeEth},
+		{"vault as receiver and sender", traceVault, 2, threeEth},
+		{"receiver", traceReceiver, 1, big.NewInt(1e18)},
+		{"top-level sender not indexed", traceEOA, 0, nil},
+		{"delegatecall target not indexed", traceLibrary, 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transfers, err := store.GetInternalTransfers(ctx, tt.addr, 10, 0)
+			if err != nil {
+				t.Fatalf("GetInternalTransfers() error = %v", err)
+			}
+			if len(transfers) != tt.wantCount {
+				t.Fatalf("got %d transfers, want %d", len(transfers), tt.wantCount)
+			}
+			if tt.wantCount == 0 {
+				return
+			}
+			if transfers[0].Value.Cmp(tt.wantFirst) != 0 {
+				t.Errorf("first transfer value = %s, want %s", transfers[0].Value, tt.wantFirst)
+			}
+			for _, transfer := range transfers {
+				if transfer.TransactionHash != txHash {
+					t.Errorf("transfer tx hash = %s, want %s", transfer.TransactionHash.Hex(), txHash.Hex())
+				}
+				if transfer.BlockNumber != 10 {
+					t.Errorf("transfer block = %d, want 10", transfer.BlockNumber)
+				}
+			}
+		})
+	}
+
+	// The vault's payout to the receiver is nested one level deeper
+	transfers, _ := store.GetInternalTransfers(ctx, traceReceiver, 10, 0)
+	if transfers[0].From != traceVault || transfers[0].Depth != 2 {
+		t.Errorf("receiver transfer from=%s depth=%d, want from vault at depth 2", transfers[0].From.Hex(), transfers[0].Depth)
+	}
+
+	// Re-processing the same block must not duplicate entries
+	if err := processor.ProcessBlock(ctx, block); err != nil {
+		t.Fatalf("ProcessBlock() second run error = %v", err)
+	}
+	if transfers, _ := store.GetInternalTransfers(ctx, traceVault, 10, 0); len(transfers) != 2 {
+		t.Errorf("got %d vault transfers after re-processing, want 2", len(transfers))
+	}
+}
+
+func TestInternalTransferProcessor_TraceCountMismatch(t *testing.T) {
+	store := setupTestStorage(t)
+	processor := NewInternalTransferProcessor(zap.NewNop(), &fakeTracer{response: `[]`}, store)
+
+	if err := processor.ProcessBlock(context.Background(), createTracedBlock(1)); err == nil {
+		t.Error("expected error when trace count does not match transaction count")
+	}
+}
+
+func TestFetchBlock_InternalTransfers(t *testing.T) {
+	mockClient := newMockClient()
+	block := createTracedBlock(5)
+	mockClient.blocks[5] = block
+	mockClient.receipts[block.Hash()] = types.Receipts{}
+
+	config := &Config{BatchSize: 10, MaxRetries: 3, RetryDelay: time.Millisecond}
+	f := NewFetcher(mockClient, newMockStorage(), config, zap.NewNop(), nil)
+
+	store := setupTestStorage(t)
+	tracer := &fakeTracer{response: nestedCallTrace}
+	f.SetInternalTransferProcessor(NewInternalTransferProcessor(zap.NewNop(), tracer, store))
+
+	if err := f.FetchBlock(context.Background(), 5); err != nil {
+		t.Fatalf("FetchBlock() error = %v", err)
+	}
+	if tracer.calls != 1 {
+		t.Errorf("tracer called %d times, want 1", tracer.calls)
+	}
+	if transfers, _ := store.GetInternalTransfers(context.Background(), traceReceiver, 10, 0); len(transfers) != 1 {
+		t.Errorf("got %d receiver transfers, want 1", len(transfers))
+	}
+}
+
+func TestFetchBlock_InternalTransfersTraceErrorDoesNotFail(t *testing.T) {
+	mockClient := newMockClient()
+	block := createTracedBlock(5)
+	mockClient.blocks[5] = block
+	mockClient.receipts[block.Hash()] = types.Receipts{}
+
+	config := &Config{BatchSize: 10, MaxRetries: 3, RetryDelay: time.Millisecond}
+	f := NewFetcher(mockClient, newMockStorage(), config, zap.NewNop(), nil)
+	tracer := &fakeTracer{err: errors.New("the method debug_traceBlockByNumber does not exist")}
+	f.SetInternalTransferProcessor(NewInternalTransferProcessor(zap.NewNop(), tracer, setupTestStorage(t)))
+
+	if err := f.FetchBlock(context.Background(), 5); err != nil {
+		t.Fatalf("FetchBlock() should succeed when tracing fails, got %v", err)
+	}
+}
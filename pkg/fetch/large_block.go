@@ -32,6 +32,9 @@ type LargeBlockProcessor struct {
 
 	// userOpProcessor handles ERC-4337 UserOperation indexing
 	userOpProcessor *UserOpProcessor
+
+	// indexLogAddresses also indexes transactions under the addresses in their logs
+	indexLogAddresses bool
 }
 
 // NewLargeBlockProcessor creates a new large block processor
@@ -60,6 +63,12 @@ func (p *LargeBlockProcessor) SetUserOpProcessor(processor *UserOpProcessor) {
 	p.userOpProcessor = processor
 }
 
+// SetIndexLogAddresses sets whether transactions are also indexed under the
+// addresses involved in their logs
+func (p *LargeBlockProcessor) SetIndexLogAddresses(enabled bool) {
+	p.indexLogAddresses = enabled
+}
+
 // IsLargeBlock returns true if the block's gas used exceeds the large block threshold
 func (p *LargeBlockProcessor) IsLargeBlock(block *types.Block) bool {
 	return block.GasUsed() >= p.largeBlockThreshold
@@ -249,67 +258,20 @@ func (p *LargeBlockProcessor) processAddressIndexing(
 	blockTime uint64,
 	addressWriter storage.AddressIndexWriter,
 ) error {
-	// Fee Delegation transaction type constant (StableNet-specific)
-	const FeeDelegateDynamicFeeTxType = 22
-
-	// getFeePayer extracts fee payer from stored fee delegation metadata
-	getFeePayer := func(tx *types.Transaction) *common.Address {
-		if fdReader, ok := p.storage.(storage.FeeDelegationReader); ok {
-			if meta, err := fdReader.GetFeeDelegationTxMeta(ctx, tx.Hash()); err == nil && meta != nil {
-				return &meta.FeePayer
-			}
-		}
-		return nil
-	}
-
-	// 0. Index transaction addresses (from, to, feePayer) for transactionsByAddress query
+	// 0. Index transaction addresses (from, to, feePayer and, if enabled, log
+	// addresses) for transactionsByAddress query
 	if storageWriter, ok := p.storage.(storage.Writer); ok {
 		txHash := tx.Hash()
-
-		// Index 'from' address
-		from := getTransactionSender(tx)
-		if from != (common.Address{}) {
-			if err := storageWriter.AddTransactionToAddressIndex(ctx, from, txHash); err != nil {
-				p.logger.Warn("Failed to index transaction for from address",
+		for _, addr := range transactionAddresses(tx, receipt, lookupFeePayer(ctx, p.storage, tx), p.indexLogAddresses) {
+			if err := storageWriter.AddTransactionToAddressIndex(ctx, addr, txHash); err != nil {
+				p.logger.Warn("Failed to index transaction for address",
 					zap.Uint64("block", blockNumber),
 					zap.String("tx", txHash.Hex()),
-					zap.String("from", from.Hex()),
+					zap.String("address", addr.Hex()),
 					zap.Error(err),
 				)
 			}
 		}
-
-		// Index 'to' address (if not contract creation)
-		if tx.To() != nil {
-			to := *tx.To()
-			if to != from { // Avoid duplicate indexing for self-transfers
-				if err := storageWriter.AddTransactionToAddressIndex(ctx, to, txHash); err != nil {
-					p.logger.Warn("Failed to index transaction for to address",
-						zap.Uint64("block", blockNumber),
-						zap.String("tx", txHash.Hex()),
-						zap.String("to", to.Hex()),
-						zap.Error(err),
-					)
-				}
-			}
-		}
-
-		// Index 'feePayer' address for Fee Delegation transactions (type 0x16)
-		if tx.Type() == FeeDelegateDynamicFeeTxType {
-			if feePayer := getFeePayer(tx); feePayer != nil {
-				// Avoid duplicate indexing if feePayer is same as from or to
-				if *feePayer != from && (tx.To() == nil || *feePayer != *tx.To()) {
-					if err := storageWriter.AddTransactionToAddressIndex(ctx, *feePayer, txHash); err != nil {
-						p.logger.Warn("Failed to index transaction for feePayer address",
-							zap.Uint64("block", blockNumber),
-							zap.String("tx", txHash.Hex()),
-							zap.String("feePayer", feePayer.Hex()),
-							zap.Error(err),
-						)
-					}
-				}
-			}
-		}
 	}
 
 	// 1. Contract Creation Detection
@@ -37,6 +37,14 @@ type RPCMetrics struct {
 	lastThroughputUpdate time.Time
 	currentThroughput    float64 // Blocks per second
 
+	// Block checks
+	mismatchedBlocks uint64 // Blocks returned for another height than requested
+	rejectedBlocks   uint64 // Blocks rejected by VerifyOnIngest
+
+	// Fetcher state
+	paused        bool
+	activeWorkers int
+
 	// Adaptive parameters
 	optimalWorkerCount int
 	optimalBatchSize   int
@@ -136,6 +144,38 @@ func (m *RPCMetrics) RecordBlockProcessed(receiptCount int) {
 	}
 }
 
+// RecordMismatchedBlock records a block returned for another height than requested
+func (m *RPCMetrics) RecordMismatchedBlock() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.mismatchedBlocks++
+}
+
+// RecordRejectedBlock records a block rejected by VerifyOnIngest
+func (m *RPCMetrics) RecordRejectedBlock() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.rejectedBlocks++
+}
+
+// SetPaused records whether indexing is paused
+func (m *RPCMetrics) SetPaused(paused bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.paused = paused
+}
+
+// SetActiveWorkers records the number of workers currently allowed to fetch blocks
+func (m *RPCMetrics) SetActiveWorkers(count int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.activeWorkers = count
+}
+
 // GetAverageResponseTime returns the average response time in milliseconds
 func (m *RPCMetrics) GetAverageResponseTime() uint64 {
 	m.mu.RLock()
@@ -287,6 +327,10 @@ func (m *RPCMetrics) GetStats() MetricsSnapshot {
 		BlocksProcessed:       m.blocksProcessed,
 		ReceiptsProcessed:     m.receiptsProcessed,
 		Throughput:            m.currentThroughput,
+		MismatchedBlocks:      m.mismatchedBlocks,
+		RejectedBlocks:        m.rejectedBlocks,
+		Paused:                m.paused,
+		ActiveWorkers:         m.activeWorkers,
 		OptimalWorkerCount:    m.optimalWorkerCount,
 		OptimalBatchSize:      m.optimalBatchSize,
 		Uptime:                time.Since(m.startTime),
@@ -311,6 +355,10 @@ type MetricsSnapshot struct {
 	BlocksProcessed       uint64
 	ReceiptsProcessed     uint64
 	Throughput            float64
+	MismatchedBlocks      uint64
+	RejectedBlocks        uint64
+	Paused                bool
+	ActiveWorkers         int
 	OptimalWorkerCount    int
 	OptimalBatchSize      int
 	Uptime                time.Duration
@@ -338,4 +386,6 @@ func (m *RPCMetrics) Reset() {
 	m.startTime = time.Now()
 	m.lastThroughputUpdate = time.Now()
 	m.currentThroughput = 0
+	m.mismatchedBlocks = 0
+	m.rejectedBlocks = 0
 }
@@ -3,6 +3,9 @@ package fetch
 import (
 	"testing"
 	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
 )
 
 func TestNewRPCMetrics(t *testing.T) {
@@ -403,3 +406,42 @@ func TestRecordRequest_ErrorDoesNotTrackResponseTime(t *testing.T) {
 		t.Error("errors should not contribute to total response time")
 	}
 }
+
+func TestNewMetricsCollector(t *testing.T) {
+	f := NewFetcher(nil, newMockStorage(), &Config{BatchSize: 10, MaxRetries: 1}, zap.NewNop(), nil)
+	f.metrics.RecordMismatchedBlock()
+	f.metrics.RecordRejectedBlock()
+	f.metrics.RecordRejectedBlock()
+	f.metrics.SetActiveWorkers(4)
+	f.Pause()
+
+	registry := prometheus.NewRegistry()
+	if err := registry.Register(NewMetricsCollector(f)); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+
+	found := make(map[string]float64)
+	for _, family := range families {
+		metric := family.GetMetric()[0]
+		if counter := metric.GetCounter(); counter != nil {
+			found[family.GetName()] = counter.GetValue()
+		} else {
+			found[family.GetName()] = metric.GetGauge().GetValue()
+		}
+	}
+	want := map[string]float64{
+		"indexer_fetcher_mismatched_blocks_total": 1,
+		"indexer_fetcher_rejected_blocks_total":   2,
+		"indexer_fetcher_paused":                  1,
+		"indexer_fetcher_active_workers":          4,
+	}
+	for name, value := range want {
+		if got, ok := found[name]; !ok || got != value {
+			t.Errorf("%s = %v (exported %v), want %v", name, got, ok, value)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package fetch
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"go.uber.org/zap"
+)
+
+// SyncStatusClient is an optional interface for clients that can report
+// whether the node is still syncing (eth_syncing). A nil progress means the
+// node is synced.
+type SyncStatusClient interface {
+	SyncProgress(ctx context.Context) (*ethereum.SyncProgress, error)
+}
+
+// waitStartDelay blocks for the configured start delay or until ctx is done
+func (f *Fetcher) waitStartDelay(ctx context.Context) error {
+	if f.config.StartDelay <= 0 {
+		return nil
+	}
+
+	f.logger.Info("Delaying fetcher start", zap.Duration("delay", f.config.StartDelay))
+	return sleepContext(ctx, f.config.StartDelay)
+}
+
+// nodeSyncRecheckInterval is how long a synced report from the node is
+// trusted before eth_syncing is called again
+const nodeSyncRecheckInterval = time.Minute
+
+// waitForNodeSync blocks while the node reports it is still syncing, polling
+// every RetryDelay. It returns immediately unless WaitForNodeSync is set and
+// the client implements SyncStatusClient, or when the node reported it was
+// synced within nodeSyncRecheckInterval. Errors from eth_syncing are logged
+// and treated as synced, so nodes without the method are not blocked forever.
+func (f *Fetcher) waitForNodeSync(ctx context.Context) error {
+	if !f.config.WaitForNodeSync {
+		return nil
+	}
+	syncClient, ok := f.client.(SyncStatusClient)
+	if !ok {
+		return nil
+	}
+	if !f.nodeSyncedAt.IsZero() && time.Since(f.nodeSyncedAt) < nodeSyncRecheckInterval {
+		return nil
+	}
+
+	waiting := false
+	for {
+		progress, err := syncClient.SyncProgress(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			f.logger.Warn("Failed to get node sync status", zap.Error(err))
+			return nil
+		}
+		if progress == nil {
+			f.nodeSyncedAt = time.Now()
+			if waiting {
+				f.logger.Info("Node finished syncing, resuming fetcher")
+			}
+			return nil
+		}
+
+		if !waiting {
+			f.logger.Info("Node is still syncing, pausing fetcher",
+				zap.Uint64("current_block", progress.CurrentBlock),
+				zap.Uint64("highest_block", progress.HighestBlock),
+			)
+			waiting = true
+		}
+		if err := sleepContext(ctx, f.config.RetryDelay); err != nil {
+			return err
+		}
+	}
+}
+
+// targetHeight clamps the chain head to the configured MaxHead
+func (f *Fetcher) targetHeight(chainHead uint64) uint64 {
+	if f.config.MaxHead > 0 && chainHead > f.config.MaxHead {
+		return f.config.MaxHead
+	}
+	return chainHead
+}
+
+// sleepContext sleeps for d or until ctx is done, returning ctx.Err() in the latter case
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
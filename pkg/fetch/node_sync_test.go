@@ -0,0 +1,228 @@
+package fetch
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+)
+
+// syncingMockClient is a mockClient whose node reports eth_syncing=true for
+// the first syncingPolls calls and records when blocks are first requested
+type syncingMockClient struct {
+	*mockClient
+
+	mu           sync.Mutex
+	syncingPolls int
+	syncCalls    int
+	firstSync    time.Time
+	// syncCallsAtFirstFetch is the number of eth_syncing calls made before the
+	// first block was requested, or -1 if no block was requested
+	syncCallsAtFirstFetch int
+}
+
+func (m *syncingMockClient) SyncProgress(ctx context.Context) (*ethereum.SyncProgress, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.syncCalls++
+	if m.firstSync.IsZero() {
+		m.firstSync = time.Now()
+	}
+	if m.syncCalls <= m.syncingPolls {
+		return &ethereum.SyncProgress{CurrentBlock: 1, HighestBlock: m.latestBlock}, nil
+	}
+	return nil, nil
+}
+
+func (m *syncingMockClient) GetBlockByNumber(ctx context.Context, number uint64) (*types.Block, error) {
+	m.mu.Lock()
+	if m.syncCallsAtFirstFetch < 0 {
+		m.syncCallsAtFirstFetch = m.syncCalls
+	}
+	m.mu.Unlock()
+	return m.mockClient.GetBlockByNumber(ctx, number)
+}
+
+// addMockChain adds empty blocks 0..latest to the mock client
+func addMockChain(client *mockClient, latest uint64) {
+	for i := uint64(0); i <= latest; i++ {
+		block := types.NewBlockWithHeader(&types.Header{
+			Number:     new(big.Int).SetUint64(i),
+			Difficulty: big.NewInt(1000),
+			GasLimit:   8000000,
+		})
+		client.blocks[i] = block
+		client.receipts[block.Hash()] = types.Receipts{}
+	}
+	client.latestBlock = latest
+}
+
+func TestRun_WaitsForNodeSync(t *testing.T) {
+	client := &syncingMockClient{mockClient: newMockClient(), syncingPolls: 3, syncCallsAtFirstFetch: -1}
+	addMockChain(client.mockClient, 4)
+	storage := newMockStorage()
+
+	config := &Config{
+		BatchSize:       2,
+		MaxRetries:      3,
+		RetryDelay:      10 * time.Millisecond,
+		StartDelay:      50 * time.Millisecond,
+		WaitForNodeSync: true,
+	}
+	fetcher := NewFetcher(client, storage, config, zap.NewNop(), nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if err := fetcher.Run(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("Run() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	if waited := client.firstSync.Sub(start); waited < config.StartDelay {
+		t.Errorf("first eth_syncing call after %v, want at least %v", waited, config.StartDelay)
+	}
+	// Blocks are only requested once the node stopped reporting it is syncing
+	if client.syncCallsAtFirstFetch <= client.syncingPolls {
+		t.Errorf("first block requested after %d eth_syncing calls, want more than %d", client.syncCallsAtFirstFetch, client.syncingPolls)
+	}
+
+	latestHeight, err := storage.GetLatestHeight(context.Background())
+	if err != nil {
+		t.Fatalf("GetLatestHeight() error = %v", err)
+	}
+	if latestHeight != 4 {
+		t.Errorf("latest height = %d, want 4", latestHeight)
+	}
+}
+
+func TestRun_MaxHead(t *testing.T) {
+	client := newMockClient()
+	addMockChain(client, 10)
+	storage := newMockStorage()
+
+	config := &Config{
+		BatchSize:  2,
+		MaxRetries: 3,
+		RetryDelay: 10 * time.Millisecond,
+		MaxHead:    5,
+	}
+	fetcher := NewFetcher(client, storage, config, zap.NewNop(), nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+
+	if err := fetcher.Run(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("Run() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	latestHeight, err := storage.GetLatestHeight(context.Background())
+	if err != nil {
+		t.Fatalf("GetLatestHeight() error = %v", err)
+	}
+	if latestHeight != 5 {
+		t.Errorf("latest height = %d, want 5", latestHeight)
+	}
+	if has, _ := storage.HasBlock(context.Background(), 6); has {
+		t.Error("block 6 above max head should not be indexed")
+	}
+	// The real chain head is still tracked for lag reporting
+	if head := fetcher.ChainHead(); head != 10 {
+		t.Errorf("ChainHead() = %d, want 10", head)
+	}
+}
+
+func TestRun_StartDelayCancelled(t *testing.T) {
+	config := &Config{BatchSize: 2, MaxRetries: 3, RetryDelay: time.Millisecond, StartDelay: time.Hour}
+	fetcher := NewFetcher(newMockClient(), newMockStorage(), config, zap.NewNop(), nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := fetcher.Run(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Run() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestRun_CachesNodeSyncStatus(t *testing.T) {
+	client := &syncingMockClient{mockClient: newMockClient(), syncCallsAtFirstFetch: -1}
+	addMockChain(client.mockClient, 4)
+
+	config := &Config{BatchSize: 2, MaxRetries: 3, RetryDelay: 10 * time.Millisecond, WaitForNodeSync: true}
+	fetcher := NewFetcher(client, newMockStorage(), config, zap.NewNop(), nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	if err := fetcher.Run(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("Run() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	// The loop runs every RetryDelay once caught up, but a synced node is not
+	// asked again until the recheck interval has passed
+	if client.syncCalls != 1 {
+		t.Errorf("eth_syncing called %d times, want 1", client.syncCalls)
+	}
+}
+
+func TestRunWithGapRecovery_WaitsForNodeSync(t *testing.T) {
+	client := &syncingMockClient{mockClient: newMockClient(), syncingPolls: 3, syncCallsAtFirstFetch: -1}
+	addMockChain(client.mockClient, 4)
+
+	// Blocks 0..4 are indexed except block 2
+	storage := newMockStorage()
+	ctx := context.Background()
+	for height := uint64(0); height <= 4; height++ {
+		if height == 2 {
+			continue
+		}
+		if err := storage.SetBlock(ctx, client.blocks[height]); err != nil {
+			t.Fatalf("SetBlock(%d) error = %v", height, err)
+		}
+	}
+	if err := storage.SetLatestHeight(ctx, 4); err != nil {
+		t.Fatalf("SetLatestHeight() error = %v", err)
+	}
+
+	config := &Config{
+		BatchSize:       2,
+		MaxRetries:      3,
+		RetryDelay:      10 * time.Millisecond,
+		StartDelay:      50 * time.Millisecond,
+		WaitForNodeSync: true,
+	}
+	fetcher := NewFetcher(client, storage, config, zap.NewNop(), nil)
+
+	runCtx, cancel := context.WithTimeout(ctx, 500*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if err := fetcher.RunWithGapRecovery(runCtx); err != context.DeadlineExceeded {
+		t.Fatalf("RunWithGapRecovery() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	if waited := client.firstSync.Sub(start); waited < config.StartDelay {
+		t.Errorf("first eth_syncing call after %v, want at least %v", waited, config.StartDelay)
+	}
+	// The gap is only filled once the node stopped reporting it is syncing
+	if client.syncCallsAtFirstFetch <= client.syncingPolls {
+		t.Errorf("first block requested after %d eth_syncing calls, want more than %d", client.syncCallsAtFirstFetch, client.syncingPolls)
+	}
+	if has, _ := storage.HasBlock(ctx, 2); !has {
+		t.Error("gap block 2 was not filled")
+	}
+}
@@ -0,0 +1,66 @@
+package fetch
+
+import (
+	"context"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// maxOpeningBalances bounds the opening balance cache. An entry is only read
+// until the address has balance history in storage, so dropping the cache
+// when it fills costs at most one more eth_getBalance per address.
+const maxOpeningBalances = 100_000
+
+// openingBalanceCache holds opening balances fetched over RPC, keyed by
+// address, so each address is queried once even when it is first seen by
+// several blocks indexed concurrently or in a batch that is retried
+type openingBalanceCache struct {
+	mu       sync.Mutex
+	balances map[common.Address]*big.Int
+}
+
+func newOpeningBalanceCache() *openingBalanceCache {
+	return &openingBalanceCache{balances: make(map[common.Address]*big.Int)}
+}
+
+func (c *openingBalanceCache) get(addr common.Address) (*big.Int, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	balance, ok := c.balances[addr]
+	return balance, ok
+}
+
+func (c *openingBalanceCache) add(addr common.Address, balance *big.Int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.balances) >= maxOpeningBalances {
+		c.balances = make(map[common.Address]*big.Int)
+	}
+	c.balances[addr] = balance
+}
+
+// openingBalanceHeight is the height whose state seeds first-seen addresses
+// when SeedOpeningBalances is set: the last block before StartHeight, so the
+// deltas of every indexed block apply on top of it
+func (f *Fetcher) openingBalanceHeight() uint64 {
+	if f.config.StartHeight == 0 {
+		return 0
+	}
+	return f.config.StartHeight - 1
+}
+
+// openingBalance returns addr's balance at the opening height, from the cache
+// or through eth_getBalance. The returned value must not be modified.
+func (f *Fetcher) openingBalance(ctx context.Context, addr common.Address) (*big.Int, error) {
+	if balance, ok := f.openingBalances.get(addr); ok {
+		return balance, nil
+	}
+	balance, err := f.client.BalanceAt(ctx, addr, new(big.Int).SetUint64(f.openingBalanceHeight()))
+	if err != nil {
+		return nil, err
+	}
+	f.openingBalances.add(addr, balance)
+	return balance, nil
+}
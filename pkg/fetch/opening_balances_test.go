@@ -0,0 +1,175 @@
+package fetch
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	storagepkg "github.com/0xmhha/indexer-go/pkg/storage"
+)
+
+// balanceNode is a mockClient that answers eth_getBalance from per-height
+// balances and counts the calls made for each address
+type balanceNode struct {
+	*mockClient
+
+	mu       sync.Mutex
+	balances map[uint64]map[common.Address]*big.Int
+	calls    map[common.Address]int
+}
+
+func newBalanceNode() *balanceNode {
+	return &balanceNode{
+		mockClient: newMockClient(),
+		balances:   make(map[uint64]map[common.Address]*big.Int),
+		calls:      make(map[common.Address]int),
+	}
+}
+
+func (n *balanceNode) setBalance(height uint64, addr common.Address, balance int64) {
+	if n.balances[height] == nil {
+		n.balances[height] = make(map[common.Address]*big.Int)
+	}
+	n.balances[height][addr] = big.NewInt(balance)
+}
+
+func (n *balanceNode) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.calls[account]++
+	if balance, ok := n.balances[blockNumber.Uint64()][account]; ok {
+		return new(big.Int).Set(balance), nil
+	}
+	return big.NewInt(0), nil
+}
+
+func (n *balanceNode) callCount(addr common.Address) int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.calls[addr]
+}
+
+// addTransferBlock registers a block at height holding one transfer of value
+// from key to balanceTestRecipient with a gas price of 1
+func addTransferBlock(t *testing.T, node *balanceNode, key *ecdsa.PrivateKey, height, nonce uint64, value int64) {
+	t.Helper()
+
+	tx, err := types.SignTx(types.NewTransaction(nonce, balanceTestRecipient, big.NewInt(value), 21000, big.NewInt(1), nil), types.LatestSignerForChainID(balanceTestChainID), key)
+	if err != nil {
+		t.Fatalf("SignTx() error = %v", err)
+	}
+	block := types.NewBlockWithHeader(&types.Header{
+		Number:     new(big.Int).SetUint64(height),
+		Difficulty: big.NewInt(1),
+		GasLimit:   8000000,
+		GasUsed:    21000,
+	}).WithBody(types.Body{Transactions: []*types.Transaction{tx}})
+
+	node.blocks[height] = block
+	node.receipts[block.Hash()] = types.Receipts{{
+		Status:            types.ReceiptStatusSuccessful,
+		CumulativeGasUsed: 21000,
+		GasUsed:           21000,
+		TxHash:            tx.Hash(),
+		BlockNumber:       new(big.Int).SetUint64(height),
+		Logs:              []*types.Log{},
+	}}
+}
+
+// setupOpeningBalanceChain starts indexing at 100 with a sender holding
+// 1,000,000 before it, spending 1000 + gas in each of blocks 100 and 101.
+// The node reports the sender's later balances too, which are only correct
+// as opening balances for the block right after them.
+func setupOpeningBalanceChain(t *testing.T) (*balanceNode, common.Address) {
+	t.Helper()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+
+	node := newBalanceNode()
+	addTransferBlock(t, node, key, 100, 0, 1000)
+	addTransferBlock(t, node, key, 101, 1, 1000)
+	node.setBalance(99, sender, 1_000_000)
+	node.setBalance(100, sender, 1_000_000-22_000)
+	node.setBalance(99, balanceTestRecipient, 50)
+	node.setBalance(100, balanceTestRecipient, 1050)
+	return node, sender
+}
+
+func newOpeningBalanceFetcher(t *testing.T, node *balanceNode, seed bool) (*Fetcher, *storagepkg.PebbleStorage) {
+	t.Helper()
+
+	store := setupTestStorage(t)
+
+	fetcher := newBalanceTestFetcher(node.mockClient, store)
+	fetcher.client = node
+	fetcher.config.StartHeight = 100
+	fetcher.config.SeedOpeningBalances = seed
+	return fetcher, store
+}
+
+func TestSeedOpeningBalances_OutOfOrderBlocks(t *testing.T) {
+	node, sender := setupOpeningBalanceChain(t)
+	fetcher, store := newOpeningBalanceFetcher(t, node, true)
+	ctx := context.Background()
+
+	// Index the later block first, as concurrent workers may
+	for _, height := range []uint64{101, 100} {
+		if err := fetcher.FetchBlock(ctx, height); err != nil {
+			t.Fatalf("FetchBlock(%d) error = %v", height, err)
+		}
+	}
+
+	// Opening balance at 99 minus two transfers of 1000 + 21000 gas. The
+	// history checked up to height 100 holds only block 100's change.
+	assertBalance(t, store, sender, 1_000_000-2*22_000, 1)
+	assertBalance(t, store, balanceTestRecipient, 50+2*1000, 1)
+	if calls := node.callCount(sender); calls != 1 {
+		t.Errorf("eth_getBalance called %d times for the sender, want 1", calls)
+	}
+}
+
+func TestSeedOpeningBalances_DisabledUsesBlockBeforeFirstSight(t *testing.T) {
+	node, sender := setupOpeningBalanceChain(t)
+	fetcher, store := newOpeningBalanceFetcher(t, node, false)
+	ctx := context.Background()
+
+	for _, height := range []uint64{101, 100} {
+		if err := fetcher.FetchBlock(ctx, height); err != nil {
+			t.Fatalf("FetchBlock(%d) error = %v", height, err)
+		}
+	}
+
+	// Seeded from the balance at 100, which block 100 is then applied to again
+	assertBalance(t, store, sender, 1_000_000-3*22_000, 1)
+}
+
+func TestSeedOpeningBalances_CachedAcrossRetries(t *testing.T) {
+	node, sender := setupOpeningBalanceChain(t)
+	fetcher, store := newOpeningBalanceFetcher(t, node, true)
+	ctx := context.Background()
+
+	// The first attempt seeds the sender, then fails to commit
+	fetcher.storage = &failingCommitStorage{PebbleStorage: store}
+	if err := fetcher.FetchBlock(ctx, 100); err == nil {
+		t.Fatal("FetchBlock() with failing commit succeeded")
+	}
+	fetcher.storage = store
+	if err := fetcher.FetchBlock(ctx, 100); err != nil {
+		t.Fatalf("FetchBlock() retry error = %v", err)
+	}
+
+	assertBalance(t, store, sender, 1_000_000-22_000, 2)
+	if calls := node.callCount(sender); calls != 1 {
+		t.Errorf("eth_getBalance called %d times for the sender, want 1 cached call", calls)
+	}
+}
@@ -0,0 +1,61 @@
+package fetch
+
+import (
+	"context"
+)
+
+// Pause stops Run and gap recovery from starting new batches until Resume is
+// called. A batch already being fetched is committed first, so indexing
+// always stops at a block boundary. Pausing an already paused fetcher does
+// nothing.
+func (f *Fetcher) Pause() {
+	f.pauseMu.Lock()
+	defer f.pauseMu.Unlock()
+
+	if f.resumeCh != nil {
+		return
+	}
+	f.resumeCh = make(chan struct{})
+	f.metrics.SetPaused(true)
+	f.logger.Info("Indexing paused")
+}
+
+// Resume lets a paused Run continue from the next unindexed height
+func (f *Fetcher) Resume() {
+	f.pauseMu.Lock()
+	defer f.pauseMu.Unlock()
+
+	if f.resumeCh == nil {
+		return
+	}
+	close(f.resumeCh)
+	f.resumeCh = nil
+	f.metrics.SetPaused(false)
+	f.logger.Info("Indexing resumed")
+}
+
+// Paused reports whether indexing is paused
+func (f *Fetcher) Paused() bool {
+	f.pauseMu.Lock()
+	defer f.pauseMu.Unlock()
+	return f.resumeCh != nil
+}
+
+// waitWhilePaused blocks while the fetcher is paused or until ctx is done
+func (f *Fetcher) waitWhilePaused(ctx context.Context) error {
+	f.pauseMu.Lock()
+	resumeCh := f.resumeCh
+	f.pauseMu.Unlock()
+
+	if resumeCh == nil {
+		return nil
+	}
+	f.logger.Debug("Fetcher waiting for resume")
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-resumeCh:
+		return nil
+	}
+}
@@ -0,0 +1,194 @@
+package fetch
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+)
+
+// growingMockClient is a mockClient whose chain can be extended while Run is
+// fetching from it
+type growingMockClient struct {
+	*mockClient
+	mu sync.Mutex
+}
+
+func (m *growingMockClient) extend(latest uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	addMockChain(m.mockClient, latest)
+}
+
+func (m *growingMockClient) GetLatestBlockNumber(ctx context.Context) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.mockClient.GetLatestBlockNumber(ctx)
+}
+
+func (m *growingMockClient) GetBlockByNumber(ctx context.Context, number uint64) (*types.Block, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.mockClient.GetBlockByNumber(ctx, number)
+}
+
+func (m *growingMockClient) GetBlockReceipts(ctx context.Context, blockNumber uint64) (types.Receipts, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.mockClient.GetBlockReceipts(ctx, blockNumber)
+}
+
+// waitForHeight polls storage until the latest height reaches want
+func waitForHeight(t *testing.T, storage *mockStorage, want uint64) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if height, err := storage.GetLatestHeight(context.Background()); err == nil && height == want {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	height, _ := storage.GetLatestHeight(context.Background())
+	t.Fatalf("latest height = %d, want %d", height, want)
+}
+
+func TestRun_PauseResume(t *testing.T) {
+	client := &growingMockClient{mockClient: newMockClient()}
+	client.extend(4)
+	storage := newMockStorage()
+
+	config := &Config{BatchSize: 2, MaxRetries: 3, RetryDelay: 10 * time.Millisecond}
+	fetcher := NewFetcher(client, storage, config, zap.NewNop(), nil)
+
+	// Paused before Run starts: nothing is indexed
+	fetcher.Pause()
+	fetcher.Pause()
+	if !fetcher.Paused() {
+		t.Fatal("Paused() = false after Pause()")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- fetcher.Run(ctx) }()
+
+	time.Sleep(100 * time.Millisecond)
+	if has, _ := storage.HasBlock(context.Background(), 0); has {
+		t.Fatal("block 0 indexed while paused")
+	}
+
+	fetcher.Resume()
+	if fetcher.Paused() {
+		t.Fatal("Paused() = true after Resume()")
+	}
+	waitForHeight(t, storage, 4)
+
+	// Paused after catching up: new chain blocks are not indexed
+	fetcher.Pause()
+	time.Sleep(50 * time.Millisecond)
+	client.extend(8)
+	time.Sleep(100 * time.Millisecond)
+	if height, _ := storage.GetLatestHeight(context.Background()); height != 4 {
+		t.Fatalf("latest height = %d while paused, want 4", height)
+	}
+	if has, _ := storage.HasBlock(context.Background(), 5); has {
+		t.Fatal("block 5 indexed while paused")
+	}
+
+	// Resumed: indexing continues from where it stopped
+	fetcher.Resume()
+	fetcher.Resume()
+	waitForHeight(t, storage, 8)
+	for height := uint64(0); height <= 8; height++ {
+		if has, _ := storage.HasBlock(context.Background(), height); !has {
+			t.Errorf("block %d not indexed", height)
+		}
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Run() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestRun_PausedStopsOnCancel(t *testing.T) {
+	config := &Config{BatchSize: 2, MaxRetries: 3, RetryDelay: time.Millisecond}
+	fetcher := NewFetcher(newMockClient(), newMockStorage(), config, zap.NewNop(), nil)
+	fetcher.Pause()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := fetcher.Run(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Run() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestRunWithGapRecovery_Paused(t *testing.T) {
+	client := newMockClient()
+	addMockChain(client, 12)
+	storage := newMockStorage()
+
+	// Blocks 1..9 are a gap below the indexed head
+	ctx := context.Background()
+	for _, height := range []uint64{0, 10} {
+		if err := storage.SetBlock(ctx, client.blocks[height]); err != nil {
+			t.Fatalf("SetBlock(%d) error = %v", height, err)
+		}
+	}
+	if err := storage.SetLatestHeight(ctx, 10); err != nil {
+		t.Fatalf("SetLatestHeight() error = %v", err)
+	}
+
+	config := &Config{BatchSize: 2, MaxRetries: 3, RetryDelay: 10 * time.Millisecond}
+	fetcher := NewFetcher(client, storage, config, zap.NewNop(), nil)
+	fetcher.Pause()
+
+	runCtx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() { done <- fetcher.RunWithGapRecovery(runCtx) }()
+
+	// Paused: the gap is detected but not filled
+	time.Sleep(100 * time.Millisecond)
+	for height := uint64(1); height <= 9; height++ {
+		if has, _ := storage.HasBlock(ctx, height); has {
+			t.Fatalf("gap block %d indexed while paused", height)
+		}
+	}
+
+	fetcher.Resume()
+	waitForHeight(t, storage, 12)
+	for height := uint64(0); height <= 12; height++ {
+		if has, _ := storage.HasBlock(ctx, height); !has {
+			t.Errorf("block %d not indexed", height)
+		}
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("RunWithGapRecovery() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestFillReceiptGaps_Paused(t *testing.T) {
+	client := newMockClient()
+	addMockChain(client, 1)
+	storage := newMockStorage()
+
+	config := &Config{BatchSize: 2, MaxRetries: 3, RetryDelay: time.Millisecond}
+	fetcher := NewFetcher(client, storage, config, zap.NewNop(), nil)
+	fetcher.Pause()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// Paused: the receipt gap is held until the context ends
+	gaps := []ReceiptGapInfo{{BlockNumber: 1, MissingReceipts: []common.Hash{{0x01}}}}
+	if err := fetcher.FillReceiptGaps(ctx, gaps); err != context.DeadlineExceeded {
+		t.Errorf("FillReceiptGaps() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,41 @@
+package fetch
+
+import (
+	"errors"
+	"fmt"
+
+	storagepkg "github.com/0xmhha/indexer-go/pkg/storage"
+)
+
+// PoisonBlockError reports a block whose data cannot be encoded for storage
+// or decoded back from it. Fetching the block again yields the same data, so
+// retrying cannot succeed.
+type PoisonBlockError struct {
+	// Height is the height of the offending block
+	Height uint64
+	// Err is the encode or decode failure
+	Err error
+}
+
+func (e *PoisonBlockError) Error() string {
+	return fmt.Sprintf("poison block %d: %v", e.Height, e.Err)
+}
+
+func (e *PoisonBlockError) Unwrap() error {
+	return e.Err
+}
+
+// isPoisonError reports whether err is an encode or decode failure
+func isPoisonError(err error) bool {
+	return errors.Is(err, storagepkg.ErrEncodeFailed) || errors.Is(err, storagepkg.ErrDecodeFailed)
+}
+
+// poisonBlockError wraps err in a PoisonBlockError for the block at height
+// when it is an encode or decode failure, and returns it unchanged otherwise
+func poisonBlockError(height uint64, err error) error {
+	var poison *PoisonBlockError
+	if err == nil || !isPoisonError(err) || errors.As(err, &poison) {
+		return err
+	}
+	return &PoisonBlockError{Height: height, Err: err}
+}
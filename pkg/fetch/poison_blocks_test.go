@@ -0,0 +1,219 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+
+	storagepkg "github.com/0xmhha/indexer-go/pkg/storage"
+)
+
+// poisonStorage is a mockStorage that cannot store the block at poison, as
+// if its data failed to decode, and keeps dead-letter records
+type poisonStorage struct {
+	*mockStorage
+	poison uint64
+	failed map[uint64]*storagepkg.FailedBlock
+}
+
+func newPoisonStorage(poison uint64) *poisonStorage {
+	return &poisonStorage{
+		mockStorage: newMockStorage(),
+		poison:      poison,
+		failed:      make(map[uint64]*storagepkg.FailedBlock),
+	}
+}
+
+func (s *poisonStorage) SetBlock(ctx context.Context, block *types.Block) error {
+	if block.NumberU64() == s.poison {
+		_, err := storagepkg.DecodeBlock([]byte{0xff})
+		return fmt.Errorf("%w block: %w", storagepkg.ErrDecodeFailed, err)
+	}
+	return s.mockStorage.SetBlock(ctx, block)
+}
+
+func (s *poisonStorage) SetFailedBlock(ctx context.Context, record *storagepkg.FailedBlock) error {
+	s.failed[record.Height] = record
+	return nil
+}
+
+func (s *poisonStorage) DeleteFailedBlock(ctx context.Context, height uint64) error {
+	delete(s.failed, height)
+	return nil
+}
+
+func (s *poisonStorage) GetFailedBlock(ctx context.Context, height uint64) (*storagepkg.FailedBlock, error) {
+	record, ok := s.failed[height]
+	if !ok {
+		return nil, storagepkg.ErrNotFound
+	}
+	return record, nil
+}
+
+func (s *poisonStorage) GetFailedBlocks(ctx context.Context, limit int) ([]*storagepkg.FailedBlock, error) {
+	records := make([]*storagepkg.FailedBlock, 0, len(s.failed))
+	for _, record := range s.failed {
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+func newPoisonTestFetcher(store *poisonStorage, skip bool) *Fetcher {
+	return newPoisonTestFetcherWithHead(store, skip, 5)
+}
+
+// newPoisonTestFetcherWithHead is newPoisonTestFetcher for a chain of blocks 0 to head
+func newPoisonTestFetcherWithHead(store *poisonStorage, skip bool, head uint64) *Fetcher {
+	client := newMockClient()
+	for i := uint64(0); i <= head; i++ {
+		block := types.NewBlockWithHeader(&types.Header{
+			Number:     big.NewInt(int64(i)),
+			Difficulty: big.NewInt(1),
+			GasLimit:   8000000,
+		})
+		client.blocks[i] = block
+		client.receipts[block.Hash()] = types.Receipts{}
+	}
+	client.latestBlock = head
+
+	config := &Config{
+		BatchSize:         10,
+		MaxRetries:        1,
+		RetryDelay:        time.Millisecond,
+		CommitBatchBlocks: 3,
+		SkipPoisonBlocks:  skip,
+	}
+	return NewFetcher(client, store, config, zap.NewNop(), nil)
+}
+
+func TestRun_PoisonBlockFailsFast(t *testing.T) {
+	store := newPoisonStorage(3)
+	fetcher := newPoisonTestFetcher(store, false)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := fetcher.Run(ctx)
+	var poison *PoisonBlockError
+	if !errors.As(err, &poison) || poison.Height != 3 {
+		t.Fatalf("Run() error = %v, want a PoisonBlockError for block 3", err)
+	}
+	if !errors.Is(err, storagepkg.ErrDecodeFailed) {
+		t.Errorf("Run() error = %v, want it to wrap ErrDecodeFailed", err)
+	}
+	if _, ok := store.failed[3]; !ok {
+		t.Error("poison block 3 was not dead-lettered")
+	}
+	if store.latestHeight != 2 {
+		t.Errorf("latest height = %d, want 2", store.latestHeight)
+	}
+}
+
+func TestRun_PoisonBlockSkipped(t *testing.T) {
+	store := newPoisonStorage(3)
+	fetcher := newPoisonTestFetcher(store, true)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	if err := fetcher.Run(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Run() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if record, ok := store.failed[3]; !ok || record.Attempts != 1 {
+		t.Errorf("failed block record for block 3 = %+v, want one attempt", record)
+	}
+	for _, height := range []uint64{0, 1, 2, 4, 5} {
+		if _, ok := store.blocks[height]; !ok {
+			t.Errorf("block %d was not indexed", height)
+		}
+	}
+	if _, ok := store.blocks[3]; ok {
+		t.Error("poison block 3 was stored")
+	}
+	if store.latestHeight != 5 {
+		t.Errorf("latest height = %d, want 5", store.latestHeight)
+	}
+}
+
+func TestFillGaps_PoisonBlock(t *testing.T) {
+	tests := []struct {
+		name string
+		head uint64
+		gaps []GapRange
+	}{
+		// Gaps of up to 10 blocks are fetched sequentially
+		{"sequential", 6, []GapRange{{Start: 1, End: 4}, {Start: 6, End: 6}}},
+		// Larger gaps go through the concurrent fetch path
+		{"concurrent", 20, []GapRange{{Start: 1, End: 15}, {Start: 18, End: 20}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Run("skip", func(t *testing.T) {
+				store := newPoisonStorage(3)
+				fetcher := newPoisonTestFetcherWithHead(store, true, tt.head)
+
+				if err := fetcher.FillGaps(context.Background(), tt.gaps); err != nil {
+					t.Fatalf("FillGaps() error = %v", err)
+				}
+				if _, ok := store.failed[3]; !ok {
+					t.Error("poison block 3 was not dead-lettered")
+				}
+				if _, ok := store.blocks[3]; ok {
+					t.Error("poison block 3 was stored")
+				}
+				for _, gap := range tt.gaps {
+					for height := gap.Start; height <= gap.End; height++ {
+						if _, ok := store.blocks[height]; !ok && height != 3 {
+							t.Errorf("block %d was not filled", height)
+						}
+					}
+				}
+			})
+
+			t.Run("fail fast", func(t *testing.T) {
+				store := newPoisonStorage(3)
+				fetcher := newPoisonTestFetcherWithHead(store, false, tt.head)
+
+				err := fetcher.FillGaps(context.Background(), tt.gaps)
+				var poison *PoisonBlockError
+				if !errors.As(err, &poison) || poison.Height != 3 {
+					t.Fatalf("FillGaps() error = %v, want a PoisonBlockError for block 3", err)
+				}
+				last := tt.gaps[len(tt.gaps)-1]
+				if _, ok := store.blocks[last.End]; ok {
+					t.Errorf("block %d of a later gap was filled after the poison block", last.End)
+				}
+			})
+		})
+	}
+}
+
+func TestPoisonBlockError_Classification(t *testing.T) {
+	decodeErr := fmt.Errorf("%w receipt: bad", storagepkg.ErrDecodeFailed)
+	encodeErr := fmt.Errorf("%w block: bad", storagepkg.ErrEncodeFailed)
+	rpcErr := errors.New("connection refused")
+
+	for _, err := range []error{decodeErr, encodeErr} {
+		var poison *PoisonBlockError
+		if !errors.As(poisonBlockError(7, err), &poison) || poison.Height != 7 {
+			t.Errorf("poisonBlockError(%v) is not a PoisonBlockError for block 7", err)
+		}
+	}
+	if got := poisonBlockError(7, rpcErr); got != rpcErr {
+		t.Errorf("poisonBlockError(rpc error) = %v, want it unchanged", got)
+	}
+
+	// An already classified error keeps its height
+	wrapped := poisonBlockError(7, decodeErr)
+	var poison *PoisonBlockError
+	if !errors.As(poisonBlockError(9, fmt.Errorf("outer: %w", wrapped)), &poison) || poison.Height != 7 {
+		t.Errorf("re-classified error height = %v, want 7", poison)
+	}
+}
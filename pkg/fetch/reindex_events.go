@@ -0,0 +1,132 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+
+	"github.com/0xmhha/indexer-go/pkg/events"
+	storagepkg "github.com/0xmhha/indexer-go/pkg/storage"
+)
+
+// ReceiptReader is an optional interface for storages that can return stored receipts
+type ReceiptReader interface {
+	GetReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error)
+}
+
+// supplyPreservingWriter ignores total supply deltas. Supply is accumulated
+// from Mint/Burn deltas, so replaying a partial range would double-count it.
+type supplyPreservingWriter struct {
+	storagepkg.SystemContractWriter
+}
+
+func (w supplyPreservingWriter) UpdateTotalSupply(ctx context.Context, delta *big.Int) error {
+	return nil
+}
+
+// ReindexSystemEvents replays the logs of stored receipts in [fromBlock, toBlock]
+// through the system contract event parser, overwriting the derived event records.
+// When fromBlock is 0 the aggregate state (total supply, active minters, validators
+// and blacklist) and the mint and burn records are cleared first and rebuilt from
+// scratch; for partial ranges the total supply is left untouched.
+func (f *Fetcher) ReindexSystemEvents(ctx context.Context, fromBlock, toBlock uint64) error {
+	if fromBlock > toBlock {
+		return fmt.Errorf("invalid range: fromBlock %d > toBlock %d", fromBlock, toBlock)
+	}
+	writer, ok := f.storage.(storagepkg.SystemContractWriter)
+	if !ok {
+		return fmt.Errorf("storage does not implement SystemContractWriter")
+	}
+	receiptReader, ok := f.storage.(ReceiptReader)
+	if !ok {
+		return fmt.Errorf("storage does not implement ReceiptReader")
+	}
+
+	if fromBlock == 0 {
+		resetter, ok := f.storage.(storagepkg.SystemContractStateResetter)
+		if !ok {
+			return fmt.Errorf("storage does not implement SystemContractStateResetter")
+		}
+		if err := resetter.ResetSystemContractState(ctx); err != nil {
+			return fmt.Errorf("failed to reset system contract state: %w", err)
+		}
+	} else {
+		writer = supplyPreservingWriter{writer}
+		f.logger.Warn("Partial system event reindex does not rebuild total supply",
+			zap.Uint64("from", fromBlock),
+			zap.Uint64("to", toBlock),
+		)
+	}
+
+	// A dedicated parser without an event bus so historical events are not republished
+	parser := events.NewSystemContractEventParser(writer, f.logger)
+	parser.SetDisabledCategories(f.config.DisabledSystemEvents...)
+	parser.SetAllowedContracts(f.config.AllowedSystemContracts...)
+	parser.SetStrictProposalTransitions(f.config.StrictProposalTransitions)
+
+	f.logger.Info("Reindexing system contract events",
+		zap.Uint64("from", fromBlock),
+		zap.Uint64("to", toBlock),
+	)
+
+	var logCount int
+	for height := fromBlock; height <= toBlock; height++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		block, err := f.storage.GetBlock(ctx, height)
+		if err != nil {
+			if errors.Is(err, storagepkg.ErrNotFound) {
+				continue
+			}
+			return fmt.Errorf("failed to get block %d: %w", height, err)
+		}
+
+		var logIndex uint
+		for txIndex, tx := range block.Transactions() {
+			receipt, err := receiptReader.GetReceipt(ctx, tx.Hash())
+			if err != nil {
+				if errors.Is(err, storagepkg.ErrNotFound) {
+					continue
+				}
+				return fmt.Errorf("failed to get receipt for tx %s: %w", tx.Hash().Hex(), err)
+			}
+
+			// Stored receipts do not keep log metadata, restore it from the block
+			for _, log := range receipt.Logs {
+				log.BlockNumber = height
+				log.BlockHash = block.Hash()
+				log.TxHash = tx.Hash()
+				log.TxIndex = uint(txIndex)
+				log.Index = logIndex
+				logIndex++
+			}
+			if len(receipt.Logs) == 0 {
+				continue
+			}
+
+			if err := parser.ParseAndIndexLogs(ctx, receipt.Logs); err != nil {
+				return fmt.Errorf("failed to reindex events for tx %s: %w", tx.Hash().Hex(), err)
+			}
+			logCount += len(receipt.Logs)
+		}
+
+		if height == toBlock {
+			break // avoid overflow when toBlock is the max uint64
+		}
+	}
+
+	f.logger.Info("System contract event reindex completed",
+		zap.Uint64("from", fromBlock),
+		zap.Uint64("to", toBlock),
+		zap.Int("logs", logCount),
+	)
+
+	return nil
+}
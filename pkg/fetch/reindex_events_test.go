@@ -0,0 +1,154 @@
+package fetch
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/0xmhha/indexer-go/internal/constants"
+	storagepkg "github.com/0xmhha/indexer-go/pkg/storage"
+)
+
+var (
+	reindexTestUpdater     = common.HexToAddress("0x00000000000000000000000000000000000000d1")
+	reindexTestBlacklisted = common.HexToAddress("0x00000000000000000000000000000000000000d2")
+	reindexTestMinter      = common.HexToAddress("0x00000000000000000000000000000000000000d3")
+)
+
+// storeSystemEventBlock stores a block at height 1 whose single receipt carries
+// GasTipUpdated, AddressBlacklisted and Mint logs from the system contracts
+func storeSystemEventBlock(t *testing.T, store *storagepkg.PebbleStorage) {
+	t.Helper()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	signer := types.LatestSignerForChainID(balanceTestChainID)
+	tx, err := types.SignTx(types.NewTransaction(0, constants.GovValidatorAddress, big.NewInt(0), 100000, big.NewInt(1), nil), signer, key)
+	if err != nil {
+		t.Fatalf("SignTx() error = %v", err)
+	}
+
+	block := types.NewBlockWithHeader(&types.Header{
+		Number:     big.NewInt(1),
+		Difficulty: big.NewInt(1),
+		GasLimit:   8000000,
+		GasUsed:    50000,
+	}).WithBody(types.Body{Transactions: []*types.Transaction{tx}})
+
+	tipData := append(common.BigToHash(big.NewInt(100)).Bytes(), common.BigToHash(big.NewInt(200)).Bytes()...)
+	receipt := &types.Receipt{
+		Status:            types.ReceiptStatusSuccessful,
+		CumulativeGasUsed: 50000,
+		GasUsed:           50000,
+		TxHash:            tx.Hash(),
+		BlockNumber:       big.NewInt(1),
+		Logs: []*types.Log{
+			{
+				Address: constants.GovValidatorAddress,
+				Topics:  []common.Hash{constants.EventSigGasTipUpdated, common.BytesToHash(reindexTestUpdater.Bytes())},
+				Data:    tipData,
+			},
+			{
+				Address: constants.GovCouncilAddress,
+				Topics:  []common.Hash{constants.EventSigAddressBlacklisted, common.BytesToHash(reindexTestBlacklisted.Bytes()), common.BigToHash(big.NewInt(7))},
+			},
+			{
+				Address: constants.NativeCoinAdapterAddress,
+				Topics:  []common.Hash{constants.EventSigMint, common.BytesToHash(reindexTestMinter.Bytes()), common.BytesToHash(reindexTestUpdater.Bytes())},
+				Data:    common.BigToHash(big.NewInt(5000)).Bytes(),
+			},
+		},
+	}
+
+	if err := store.SetBlockWithReceipts(context.Background(), block, []*types.Receipt{receipt}); err != nil {
+		t.Fatalf("SetBlockWithReceipts() error = %v", err)
+	}
+	if err := store.SetLatestHeight(context.Background(), 1); err != nil {
+		t.Fatalf("SetLatestHeight() error = %v", err)
+	}
+}
+
+// assertReindexedEvents checks the queries served from the rebuilt indexes
+func assertReindexedEvents(t *testing.T, store *storagepkg.PebbleStorage, wantSupply int64) {
+	t.Helper()
+	ctx := context.Background()
+
+	tips, err := store.GetGasTipHistory(ctx, 0, 10)
+	if err != nil {
+		t.Fatalf("GetGasTipHistory() error = %v", err)
+	}
+	if len(tips) != 1 {
+		t.Fatalf("GetGasTipHistory() returned %d events, want 1", len(tips))
+	}
+	if tips[0].BlockNumber != 1 || tips[0].NewTip.Int64() != 200 || tips[0].Updater != reindexTestUpdater {
+		t.Errorf("gas tip event = block %d, newTip %s, updater %s", tips[0].BlockNumber, tips[0].NewTip, tips[0].Updater.Hex())
+	}
+
+	blacklisted, err := store.GetBlacklistedAddresses(ctx)
+	if err != nil {
+		t.Fatalf("GetBlacklistedAddresses() error = %v", err)
+	}
+	if len(blacklisted) != 1 || blacklisted[0] != reindexTestBlacklisted {
+		t.Errorf("GetBlacklistedAddresses() = %v, want [%s]", blacklisted, reindexTestBlacklisted.Hex())
+	}
+
+	supply, err := store.GetTotalSupply(ctx)
+	if err != nil {
+		t.Fatalf("GetTotalSupply() error = %v", err)
+	}
+	if supply.Int64() != wantSupply {
+		t.Errorf("GetTotalSupply() = %s, want %d", supply, wantSupply)
+	}
+}
+
+func TestReindexSystemEvents(t *testing.T) {
+	store := setupTestStorage(t)
+
+	ctx := context.Background()
+	storeSystemEventBlock(t, store)
+
+	// Wipe the event records and leave stale aggregate state behind
+	if _, err := store.DeleteByPrefix(storagepkg.GasTipUpdateEventKeyPrefix()); err != nil {
+		t.Fatalf("DeleteByPrefix() error = %v", err)
+	}
+	stale := common.HexToAddress("0x00000000000000000000000000000000000000ee")
+	if err := store.UpdateBlacklistStatus(ctx, stale, true); err != nil {
+		t.Fatalf("UpdateBlacklistStatus() error = %v", err)
+	}
+	if err := store.UpdateTotalSupply(ctx, big.NewInt(999)); err != nil {
+		t.Fatalf("UpdateTotalSupply() error = %v", err)
+	}
+
+	fetcher := newBalanceTestFetcher(newMockClient(), store)
+	if err := fetcher.ReindexSystemEvents(ctx, 0, 1); err != nil {
+		t.Fatalf("ReindexSystemEvents() error = %v", err)
+	}
+	assertReindexedEvents(t, store, 5000)
+
+	// Reindexing again is idempotent
+	if err := fetcher.ReindexSystemEvents(ctx, 0, 1); err != nil {
+		t.Fatalf("ReindexSystemEvents() second run error = %v", err)
+	}
+	assertReindexedEvents(t, store, 5000)
+
+	// A partial range does not replay supply deltas
+	if err := fetcher.ReindexSystemEvents(ctx, 1, 1); err != nil {
+		t.Fatalf("ReindexSystemEvents() partial error = %v", err)
+	}
+	assertReindexedEvents(t, store, 5000)
+}
+
+func TestReindexSystemEvents_InvalidRange(t *testing.T) {
+	store := setupTestStorage(t)
+
+	fetcher := newBalanceTestFetcher(newMockClient(), store)
+	if err := fetcher.ReindexSystemEvents(context.Background(), 5, 1); err == nil {
+		t.Error("ReindexSystemEvents() should fail when fromBlock > toBlock")
+	}
+}
@@ -0,0 +1,85 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	storagepkg "github.com/0xmhha/indexer-go/pkg/storage"
+	"go.uber.org/zap"
+)
+
+// DefaultMaxReorgDepth is the number of stored blocks checkReorg walks back
+// through when Config.MaxReorgDepth is 0
+const DefaultMaxReorgDepth = 128
+
+// ErrReorgTooDeep is returned when no stored block within MaxReorgDepth of the
+// head is on the node's canonical chain
+var ErrReorgTooDeep = errors.New("reorg deeper than max reorg depth")
+
+// maxReorgDepth returns the configured reorg depth limit
+func (f *Fetcher) maxReorgDepth() uint64 {
+	if f.config.MaxReorgDepth > 0 {
+		return f.config.MaxReorgDepth
+	}
+	return DefaultMaxReorgDepth
+}
+
+// checkReorg checks that the stored block below nextHeight is still on the
+// node's canonical chain. When it is not, it walks back to the newest stored
+// block the node agrees with, rolls storage back to it and returns the height
+// after it, so the replaced blocks are fetched again. Without
+// Config.DetectReorgs, or for a storage that cannot roll back, it returns
+// nextHeight unchanged.
+func (f *Fetcher) checkReorg(ctx context.Context, nextHeight uint64) (uint64, error) {
+	if !f.config.DetectReorgs || nextHeight == 0 {
+		return nextHeight, nil
+	}
+	rollbacker, ok := f.storage.(storagepkg.ChainRollbacker)
+	if !ok {
+		return nextHeight, nil
+	}
+
+	forkHeight, reorged, err := f.findForkHeight(ctx, nextHeight-1)
+	if err != nil || !reorged {
+		return nextHeight, err
+	}
+
+	f.logger.Warn("Chain reorganization detected, rolling back",
+		zap.Uint64("fork_height", forkHeight),
+		zap.Uint64("depth", nextHeight-1-forkHeight),
+	)
+	if err := rollbacker.RollbackToHeight(ctx, forkHeight); err != nil {
+		return nextHeight, fmt.Errorf("failed to roll back to fork height %d: %w", forkHeight, err)
+	}
+	return forkHeight + 1, nil
+}
+
+// findForkHeight compares stored blocks with the node's from height down and
+// returns the first height where they match. reorged is false when the block
+// at height already matches. A height with no stored block ends the walk, as
+// there is nothing indexed below it to compare.
+func (f *Fetcher) findForkHeight(ctx context.Context, height uint64) (uint64, bool, error) {
+	maxDepth := f.maxReorgDepth()
+	for depth := uint64(0); ; depth++ {
+		stored, err := f.storage.GetBlock(ctx, height)
+		if err != nil {
+			if errors.Is(err, storagepkg.ErrNotFound) {
+				return height, depth > 0, nil
+			}
+			return 0, false, fmt.Errorf("failed to get stored block %d: %w", height, err)
+		}
+		canonical, err := f.client.GetBlockByNumber(ctx, height)
+		if err != nil {
+			return 0, false, fmt.Errorf("failed to get block %d: %w", height, err)
+		}
+		if stored.Hash() == canonical.Hash() {
+			return height, depth > 0, nil
+		}
+
+		if height == 0 || depth+1 >= maxDepth {
+			return 0, false, fmt.Errorf("%w (%d) at block %d", ErrReorgTooDeep, maxDepth, height)
+		}
+		height--
+	}
+}
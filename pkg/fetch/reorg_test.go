@@ -0,0 +1,132 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+
+	storagepkg "github.com/0xmhha/indexer-go/pkg/storage"
+)
+
+// addReorgTestChain replaces client blocks from..to with a chain built on the
+// block before from, using extra to tell competing chains apart
+func addReorgTestChain(client *mockClient, from, to uint64, extra string) {
+	var parent common.Hash
+	if prev, ok := client.blocks[from-1]; ok && from > 0 {
+		parent = prev.Hash()
+	}
+	for i := from; i <= to; i++ {
+		block := types.NewBlockWithHeader(&types.Header{
+			Number:     new(big.Int).SetUint64(i),
+			ParentHash: parent,
+			Difficulty: big.NewInt(1),
+			GasLimit:   8000000,
+			Extra:      []byte(extra),
+		})
+		client.blocks[i] = block
+		client.receipts[block.Hash()] = types.Receipts{}
+		parent = block.Hash()
+	}
+	client.latestBlock = to
+}
+
+// newReorgTestFetcher indexes blocks 0..5 of a client chain into a storage
+// that keeps orphan blocks
+func newReorgTestFetcher(t *testing.T, maxDepth uint64) (*Fetcher, *mockClient, *storagepkg.PebbleStorage) {
+	t.Helper()
+
+	cfg := storagepkg.DefaultConfig(t.TempDir())
+	cfg.KeepOrphanBlocks = true
+	store, err := storagepkg.NewPebbleStorage(cfg)
+	if err != nil {
+		t.Fatalf("NewPebbleStorage() error = %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	client := newMockClient()
+	addReorgTestChain(client, 0, 5, "a")
+
+	fetcher := NewFetcher(client, store, &Config{
+		BatchSize:     10,
+		MaxRetries:    1,
+		RetryDelay:    time.Millisecond,
+		DetectReorgs:  true,
+		MaxReorgDepth: maxDepth,
+	}, zap.NewNop(), nil)
+	if err := fetcher.FetchRange(context.Background(), 0, 5); err != nil {
+		t.Fatalf("FetchRange() error = %v", err)
+	}
+	return fetcher, client, store
+}
+
+func TestCheckReorg(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("canonical chain", func(t *testing.T) {
+		fetcher, _, _ := newReorgTestFetcher(t, 0)
+		next, err := fetcher.checkReorg(ctx, 6)
+		if err != nil || next != 6 {
+			t.Errorf("checkReorg() = %d, %v; want 6", next, err)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		fetcher, client, _ := newReorgTestFetcher(t, 0)
+		addReorgTestChain(client, 3, 6, "b")
+		fetcher.config.DetectReorgs = false
+		next, err := fetcher.checkReorg(ctx, 6)
+		if err != nil || next != 6 {
+			t.Errorf("checkReorg() = %d, %v; want 6", next, err)
+		}
+	})
+
+	t.Run("rolls back to the fork point", func(t *testing.T) {
+		fetcher, client, store := newReorgTestFetcher(t, 0)
+		replaced, _ := store.GetBlock(ctx, 4)
+		addReorgTestChain(client, 3, 6, "b")
+
+		next, err := fetcher.checkReorg(ctx, 6)
+		if err != nil || next != 3 {
+			t.Fatalf("checkReorg() = %d, %v; want 3", next, err)
+		}
+		if latest, _ := store.GetLatestHeight(ctx); latest != 2 {
+			t.Errorf("latest height = %d, want 2", latest)
+		}
+		if _, err := store.GetBlock(ctx, 4); !errors.Is(err, storagepkg.ErrNotFound) {
+			t.Errorf("GetBlock(4) error = %v, want ErrNotFound", err)
+		}
+		if orphan, err := store.GetOrphanBlock(ctx, replaced.Hash()); err != nil || orphan.ForkHeight != 2 {
+			t.Errorf("GetOrphanBlock() = %v, %v; want fork height 2", orphan, err)
+		}
+
+		// Fetching again from the returned height indexes the new chain
+		if err := fetcher.FetchRange(ctx, next, 6); err != nil {
+			t.Fatalf("FetchRange() error = %v", err)
+		}
+		for height := uint64(3); height <= 6; height++ {
+			block, err := store.GetBlock(ctx, height)
+			if err != nil || block.Hash() != client.blocks[height].Hash() {
+				t.Errorf("block %d is not the new chain's: %v", height, err)
+			}
+		}
+	})
+
+	t.Run("deeper than max depth", func(t *testing.T) {
+		fetcher, client, store := newReorgTestFetcher(t, 2)
+		addReorgTestChain(client, 2, 6, "b")
+
+		_, err := fetcher.checkReorg(ctx, 6)
+		if !errors.Is(err, ErrReorgTooDeep) {
+			t.Fatalf("checkReorg() error = %v, want ErrReorgTooDeep", err)
+		}
+		if latest, _ := store.GetLatestHeight(ctx); latest != 5 {
+			t.Errorf("latest height = %d, want 5 (nothing rolled back)", latest)
+		}
+	})
+}
@@ -0,0 +1,205 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+
+	storagepkg "github.com/0xmhha/indexer-go/pkg/storage"
+)
+
+// ReceiptClient is an optional interface for clients that can fetch a single
+// receipt by transaction hash. RepairReceipts uses it to re-fetch only the
+// missing receipts instead of every receipt of the block.
+type ReceiptClient interface {
+	GetTransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error)
+}
+
+// receiptRepairProgressInterval is the number of scanned blocks between
+// RepairReceipts progress reports
+const receiptRepairProgressInterval = 1000
+
+// ReceiptRepairResult summarizes a RepairReceipts run
+type ReceiptRepairResult struct {
+	BlocksScanned    uint64
+	BlocksRepaired   int
+	ReceiptsRepaired int
+	// ReceiptsUnavailable counts missing receipts the RPC did not return
+	ReceiptsUnavailable int
+}
+
+// RepairReceipts scans stored blocks in [from, to] for transactions without
+// receipts, re-fetches those receipts from the RPC, stores them and indexes
+// them as the indexer would have. Blocks that are not stored are skipped. It
+// only writes receipts that are missing, so it is safe to run repeatedly, but
+// it should not run alongside the indexer.
+func (f *Fetcher) RepairReceipts(ctx context.Context, from, to uint64) (*ReceiptRepairResult, error) {
+	if from > to {
+		return nil, fmt.Errorf("invalid range: from %d > to %d", from, to)
+	}
+
+	f.logger.Info("Repairing missing receipts",
+		zap.Uint64("from", from),
+		zap.Uint64("to", to),
+	)
+
+	result := &ReceiptRepairResult{}
+	for height := from; height <= to; height++ {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		if err := f.repairBlockReceipts(ctx, height, result); err != nil {
+			return result, err
+		}
+		result.BlocksScanned++
+
+		if result.BlocksScanned%receiptRepairProgressInterval == 0 {
+			f.logger.Info("Receipt repair progress",
+				zap.Uint64("current", height),
+				zap.Uint64("to", to),
+				zap.Uint64("blocks_scanned", result.BlocksScanned),
+				zap.Int("blocks_repaired", result.BlocksRepaired),
+				zap.Int("receipts_repaired", result.ReceiptsRepaired),
+			)
+		}
+
+		// Avoid wrapping around when to is the maximum height
+		if height == to {
+			break
+		}
+	}
+
+	f.logger.Info("Receipt repair completed",
+		zap.Uint64("blocks_scanned", result.BlocksScanned),
+		zap.Int("blocks_repaired", result.BlocksRepaired),
+		zap.Int("receipts_repaired", result.ReceiptsRepaired),
+		zap.Int("receipts_unavailable", result.ReceiptsUnavailable),
+	)
+	return result, nil
+}
+
+// repairBlockReceipts stores the missing receipts of the block at height
+func (f *Fetcher) repairBlockReceipts(ctx context.Context, height uint64, result *ReceiptRepairResult) error {
+	exists, err := f.storage.HasBlock(ctx, height)
+	if err != nil {
+		return fmt.Errorf("failed to check block %d: %w", height, err)
+	}
+	if !exists {
+		return nil
+	}
+
+	missing, err := f.storage.GetMissingReceipts(ctx, height)
+	if err != nil {
+		return fmt.Errorf("failed to find missing receipts for block %d: %w", height, err)
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	receipts, err := f.fetchReceipts(ctx, height, missing)
+	if err != nil {
+		return err
+	}
+
+	repaired := make(types.Receipts, 0, len(missing))
+	for _, txHash := range missing {
+		receipt, ok := receipts[txHash]
+		if !ok {
+			f.logger.Warn("Receipt not available from RPC",
+				zap.String("tx_hash", txHash.Hex()),
+				zap.Uint64("block", height),
+			)
+			result.ReceiptsUnavailable++
+			continue
+		}
+		repaired = append(repaired, receipt)
+	}
+	if err := f.indexRepairedReceipts(ctx, height, repaired); err != nil {
+		return err
+	}
+	stored := len(repaired)
+
+	if stored > 0 {
+		result.BlocksRepaired++
+		result.ReceiptsRepaired += stored
+	}
+	f.logger.Debug("Repaired block receipts",
+		zap.Uint64("block", height),
+		zap.Int("missing", len(missing)),
+		zap.Int("stored", stored),
+	)
+	return nil
+}
+
+// indexRepairedReceipts stores the repaired receipts of the block at height
+// and indexes them like the receipts of a newly fetched block: their logs,
+// system contract events and address index entries, and their transactions in
+// the address aggregates, which skipped them while the receipts were missing
+func (f *Fetcher) indexRepairedReceipts(ctx context.Context, height uint64, receipts types.Receipts) error {
+	if len(receipts) == 0 {
+		return nil
+	}
+
+	block, err := f.storage.GetBlock(ctx, height)
+	if err != nil {
+		return fmt.Errorf("failed to get block %d: %w", height, err)
+	}
+
+	if err := f.storeAndProcessReceipts(ctx, block, receipts, height, false); err != nil {
+		return err
+	}
+
+	// Count the repaired transactions before address indexing, whose
+	// IndexAddressStats call then finds the block counted and skips it
+	if statsWriter, ok := f.storage.(storagepkg.AddressStatsWriter); ok {
+		if err := statsWriter.IndexRepairedAddressStats(ctx, block, receipts); err != nil {
+			f.logger.Warn("Failed to update address stats",
+				zap.Uint64("block", height),
+				zap.Error(err),
+			)
+		}
+	}
+	if err := f.processAddressIndexing(ctx, block, receipts, false); err != nil {
+		return fmt.Errorf("failed to process address indexing for block %d: %w", height, err)
+	}
+	return nil
+}
+
+// fetchReceipts fetches the receipts of the given transactions in the block at
+// height, one by one when the client supports it and from the block otherwise
+func (f *Fetcher) fetchReceipts(ctx context.Context, height uint64, txHashes []common.Hash) (map[common.Hash]*types.Receipt, error) {
+	receipts := make(map[common.Hash]*types.Receipt, len(txHashes))
+
+	if rc, ok := f.client.(ReceiptClient); ok {
+		for _, txHash := range txHashes {
+			receipt, err := rc.GetTransactionReceipt(ctx, txHash)
+			if errors.Is(err, ethereum.NotFound) {
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch receipt for tx %s: %w", txHash.Hex(), err)
+			}
+			if receipt != nil {
+				receipts[txHash] = receipt
+			}
+		}
+		return receipts, nil
+	}
+
+	blockReceipts, err := f.client.GetBlockReceipts(ctx, height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch receipts for block %d: %w", height, err)
+	}
+	for _, receipt := range blockReceipts {
+		if receipt != nil {
+			receipts[receipt.TxHash] = receipt
+		}
+	}
+	return receipts, nil
+}
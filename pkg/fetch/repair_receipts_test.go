@@ -0,0 +1,172 @@
+package fetch
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	storagepkg "github.com/0xmhha/indexer-go/pkg/storage"
+)
+
+// receiptMockClient is a mockClient that also serves single receipts and
+// records which transactions were requested
+type receiptMockClient struct {
+	*mockClient
+	requested []common.Hash
+}
+
+func (m *receiptMockClient) GetTransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	m.requested = append(m.requested, hash)
+	for _, receipts := range m.receipts {
+		for _, receipt := range receipts {
+			if receipt.TxHash == hash {
+				return receipt, nil
+			}
+		}
+	}
+	return nil, ethereum.NotFound
+}
+
+// storeBlockWithFirstReceipt stores the block at height with only the receipt
+// of its first transaction, leaving the others missing
+func storeBlockWithFirstReceipt(t *testing.T, store storagepkg.Storage, mockClient *mockClient, height uint64) *types.Block {
+	t.Helper()
+	_, block := setupBalanceBlock(t, mockClient, height)
+	ctx := context.Background()
+
+	if err := store.SetBlock(ctx, block); err != nil {
+		t.Fatalf("SetBlock() error = %v", err)
+	}
+	if err := store.SetReceipt(ctx, mockClient.receipts[block.Hash()][0]); err != nil {
+		t.Fatalf("SetReceipt() error = %v", err)
+	}
+	return block
+}
+
+// assertNoMissingReceipts checks that every transaction of the block has a receipt
+func assertNoMissingReceipts(t *testing.T, store storagepkg.Storage, height uint64) {
+	t.Helper()
+	missing, err := store.GetMissingReceipts(context.Background(), height)
+	if err != nil {
+		t.Fatalf("GetMissingReceipts() error = %v", err)
+	}
+	if len(missing) != 0 {
+		t.Errorf("block %d still misses %d receipts", height, len(missing))
+	}
+}
+
+func TestFetcher_RepairReceipts(t *testing.T) {
+	store := setupTestStorage(t)
+
+	mockClient := &receiptMockClient{mockClient: newMockClient()}
+	first := storeBlockWithFirstReceipt(t, store, mockClient.mockClient, 1)
+	second := storeBlockWithFirstReceipt(t, store, mockClient.mockClient, 2)
+
+	fetcher := newBalanceTestFetcher(mockClient.mockClient, store)
+	fetcher.client = mockClient
+
+	// Block 3 is not stored and must be skipped
+	result, err := fetcher.RepairReceipts(context.Background(), 1, 3)
+	if err != nil {
+		t.Fatalf("RepairReceipts() error = %v", err)
+	}
+	if result.BlocksScanned != 3 || result.BlocksRepaired != 2 || result.ReceiptsRepaired != 2 || result.ReceiptsUnavailable != 0 {
+		t.Errorf("RepairReceipts() = %+v, want 3 scanned, 2 blocks and 2 receipts repaired", result)
+	}
+
+	// Only the missing receipts are fetched
+	want := []common.Hash{first.Transactions()[1].Hash(), second.Transactions()[1].Hash()}
+	if len(mockClient.requested) != len(want) || mockClient.requested[0] != want[0] || mockClient.requested[1] != want[1] {
+		t.Errorf("requested receipts = %v, want %v", mockClient.requested, want)
+	}
+	assertNoMissingReceipts(t, store, 1)
+	assertNoMissingReceipts(t, store, 2)
+
+	// A second run finds nothing to repair
+	result, err = fetcher.RepairReceipts(context.Background(), 1, 2)
+	if err != nil {
+		t.Fatalf("RepairReceipts() second run error = %v", err)
+	}
+	if result.BlocksRepaired != 0 || result.ReceiptsRepaired != 0 {
+		t.Errorf("RepairReceipts() second run = %+v, want nothing repaired", result)
+	}
+}
+
+func TestFetcher_RepairReceipts_BlockReceiptsFallback(t *testing.T) {
+	store := setupTestStorage(t)
+
+	mockClient := newMockClient()
+	storeBlockWithFirstReceipt(t, store, mockClient, 5)
+
+	fetcher := newBalanceTestFetcher(mockClient, store)
+	result, err := fetcher.RepairReceipts(context.Background(), 5, 5)
+	if err != nil {
+		t.Fatalf("RepairReceipts() error = %v", err)
+	}
+	if result.BlocksRepaired != 1 || result.ReceiptsRepaired != 1 {
+		t.Errorf("RepairReceipts() = %+v, want 1 block and 1 receipt repaired", result)
+	}
+	assertNoMissingReceipts(t, store, 5)
+}
+
+func TestFetcher_RepairReceipts_Unavailable(t *testing.T) {
+	store := setupTestStorage(t)
+
+	mockClient := newMockClient()
+	block := storeBlockWithFirstReceipt(t, store, mockClient, 1)
+	// The RPC no longer serves the missing receipt
+	mockClient.receipts[block.Hash()] = mockClient.receipts[block.Hash()][:1]
+
+	fetcher := newBalanceTestFetcher(mockClient, store)
+	result, err := fetcher.RepairReceipts(context.Background(), 1, 1)
+	if err != nil {
+		t.Fatalf("RepairReceipts() error = %v", err)
+	}
+	if result.ReceiptsRepaired != 0 || result.ReceiptsUnavailable != 1 {
+		t.Errorf("RepairReceipts() = %+v, want 1 unavailable receipt", result)
+	}
+
+	missing, err := store.GetMissingReceipts(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("GetMissingReceipts() error = %v", err)
+	}
+	if len(missing) != 1 {
+		t.Errorf("missing receipts = %d, want 1", len(missing))
+	}
+}
+
+func TestFetcher_RepairReceipts_AddressStats(t *testing.T) {
+	store := setupTestStorage(t)
+
+	ctx := context.Background()
+	mockClient := newMockClient()
+	block := storeBlockWithFirstReceipt(t, store, mockClient, 1)
+	// The block was counted while only its first receipt was stored
+	if err := store.IndexAddressStats(ctx, block, mockClient.receipts[block.Hash()][:1]); err != nil {
+		t.Fatalf("IndexAddressStats() error = %v", err)
+	}
+
+	fetcher := newBalanceTestFetcher(mockClient, store)
+	if _, err := fetcher.RepairReceipts(ctx, 1, 1); err != nil {
+		t.Fatalf("RepairReceipts() error = %v", err)
+	}
+
+	stats, err := store.GetAddressStats(ctx, balanceTestRecipient)
+	if err != nil {
+		t.Fatalf("GetAddressStats() error = %v", err)
+	}
+	if stats.TotalTransactions != 2 || stats.TotalValueReceived.Cmp(big.NewInt(3000)) != 0 {
+		t.Errorf("stats = %d transactions, %s received, want 2 and 3000", stats.TotalTransactions, stats.TotalValueReceived)
+	}
+}
+
+func TestFetcher_RepairReceipts_InvalidRange(t *testing.T) {
+	fetcher := newBalanceTestFetcher(newMockClient(), newMockStorage())
+	if _, err := fetcher.RepairReceipts(context.Background(), 10, 5); err == nil {
+		t.Error("RepairReceipts() should fail when from > to")
+	}
+}
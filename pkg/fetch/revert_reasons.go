@@ -0,0 +1,99 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+
+	"github.com/0xmhha/indexer-go/pkg/client"
+	storagepkg "github.com/0xmhha/indexer-go/pkg/storage"
+)
+
+// TransactionTracer traces a single transaction with the callTracer
+type TransactionTracer interface {
+	TraceTransaction(ctx context.Context, hash common.Hash) (*client.CallFrame, error)
+}
+
+// RevertReasonProcessor traces failed transactions and stores why they reverted
+type RevertReasonProcessor struct {
+	logger  *zap.Logger
+	tracer  TransactionTracer
+	storage storagepkg.RevertReasonWriter
+}
+
+// NewRevertReasonProcessor creates a new revert reason processor
+func NewRevertReasonProcessor(logger *zap.Logger, tracer TransactionTracer, storage storagepkg.RevertReasonWriter) *RevertReasonProcessor {
+	return &RevertReasonProcessor{
+		logger:  logger.Named("revert-reasons"),
+		tracer:  tracer,
+		storage: storage,
+	}
+}
+
+// ProcessReceipts traces every failed transaction among receipts and stores
+// the revert output of its top-level call. Successful transactions cost no RPC.
+// A transaction that cannot be traced does not stop the others from being
+// processed; all failures are returned together.
+func (p *RevertReasonProcessor) ProcessReceipts(ctx context.Context, receipts types.Receipts) error {
+	var errs []error
+	stored := 0
+	for _, receipt := range receipts {
+		if receipt == nil || receipt.Status != types.ReceiptStatusFailed {
+			continue
+		}
+
+		frame, err := p.tracer.TraceTransaction(ctx, receipt.TxHash)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		reason := &storagepkg.RevertReason{
+			TxHash: receipt.TxHash,
+			Error:  frame.Error,
+			Data:   frame.Output,
+			Reason: decodeRevertReason(frame.Output),
+		}
+		if err := p.storage.SetRevertReason(ctx, reason); err != nil {
+			errs = append(errs, fmt.Errorf("failed to save revert reason for %s: %w", receipt.TxHash.Hex(), err))
+			continue
+		}
+		stored++
+	}
+
+	if stored > 0 {
+		p.logger.Debug("Indexed revert reasons", zap.Int("count", stored))
+	}
+	return errors.Join(errs...)
+}
+
+// decodeRevertReason decodes Error(string) and Panic(uint256) revert data
+// Custom errors and empty data decode to an empty string
+func decodeRevertReason(data []byte) string {
+	reason, err := abi.UnpackRevert(data)
+	if err != nil {
+		return ""
+	}
+	return reason
+}
+
+// processRevertReasons indexes the revert reasons of the block's failed
+// transactions when enabled. Like internal transfer tracing, failures are
+// logged and do not fail block indexing.
+func (f *Fetcher) processRevertReasons(ctx context.Context, block *types.Block, receipts types.Receipts) {
+	if f.revertReasonProcessor == nil {
+		return
+	}
+
+	if err := f.revertReasonProcessor.ProcessReceipts(ctx, receipts); err != nil {
+		f.logger.Warn("Failed to index revert reasons",
+			zap.Uint64("height", block.NumberU64()),
+			zap.Error(err),
+		)
+	}
+}
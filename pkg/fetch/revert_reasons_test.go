@@ -0,0 +1,177 @@
+package fetch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+
+	"github.com/0xmhha/indexer-go/pkg/client"
+	storagepkg "github.com/0xmhha/indexer-go/pkg/storage"
+)
+
+// insufficientBalanceRevert is the revert output of require(..., "insufficient balance")
+var insufficientBalanceRevert = hexutil.MustDecode("0x08c379a0" +
+	"0000000000000000000000000000000000000000000000000000000000000020" +
+	"0000000000000000000000000000000000000000000000000000000000000014" +
+	"696e73756666696369656e742062616c616e6365000000000000000000000000")
+
+// newFakeTraceNode starts a JSON-RPC node answering debug_traceTransaction
+// with a reverted top-level call for the given transactions, and returns a
+// client connected to it along with a counter of trace requests
+func newFakeTraceNode(t *testing.T, reverted map[common.Hash][]byte) (*client.Client, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage   `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if req.Method == "eth_chainId" {
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":"0x1"}`, req.ID)
+			return
+		}
+		var hash common.Hash
+		if req.Method != "debug_traceTransaction" || len(req.Params) == 0 || json.Unmarshal(req.Params[0], &hash) != nil {
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"error":{"code":-32601,"message":"method not found"}}`, req.ID)
+			return
+		}
+		calls.Add(1)
+		output, ok := reverted[hash]
+		if !ok {
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":{"type":"CALL","from":"%s","gas":"0x5208","gasUsed":"0x5208"}}`, req.ID, traceEOA.Hex())
+			return
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":{"type":"CALL","from":"%s","to":"%s","gas":"0x30d40","gasUsed":"0x1d4c0","output":"%s","error":"execution reverted"}}`,
+			req.ID, traceEOA.Hex(), traceRouter.Hex(), hexutil.Encode(output))
+	}))
+	t.Cleanup(server.Close)
+
+	node, err := client.NewClient(&client.Config{Endpoint: server.URL, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	t.Cleanup(node.Close)
+	return node, &calls
+}
+
+func TestFetchBlock_RevertReasons(t *testing.T) {
+	failedTx := types.NewTransaction(0, traceRouter, big.NewInt(1), 200000, big.NewInt(1), nil)
+	okTx := types.NewTransaction(1, traceRouter, big.NewInt(1), 200000, big.NewInt(1), nil)
+	block := types.NewBlockWithHeader(&types.Header{
+		Number:     big.NewInt(7),
+		Difficulty: big.NewInt(1),
+		GasLimit:   8000000,
+	}).WithBody(types.Body{Transactions: []*types.Transaction{failedTx, okTx}})
+
+	mockClient := newMockClient()
+	mockClient.blocks[7] = block
+	mockClient.receipts[block.Hash()] = types.Receipts{
+		{TxHash: failedTx.Hash(), Status: types.ReceiptStatusFailed, Logs: []*types.Log{}},
+		{TxHash: okTx.Hash(), Status: types.ReceiptStatusSuccessful, Logs: []*types.Log{}},
+	}
+
+	config := &Config{BatchSize: 10, MaxRetries: 3, RetryDelay: time.Millisecond}
+	f := NewFetcher(mockClient, newMockStorage(), config, zap.NewNop(), nil)
+
+	node, calls := newFakeTraceNode(t, map[common.Hash][]byte{failedTx.Hash(): insufficientBalanceRevert})
+	store := setupTestStorage(t)
+	f.SetRevertReasonProcessor(NewRevertReasonProcessor(zap.NewNop(), node, store))
+
+	ctx := context.Background()
+	if err := f.FetchBlock(ctx, 7); err != nil {
+		t.Fatalf("FetchBlock() error = %v", err)
+	}
+
+	// Only the failed transaction is traced
+	if got := calls.Load(); got != 1 {
+		t.Errorf("debug_traceTransaction called %d times, want 1", got)
+	}
+
+	reason, err := store.GetRevertReason(ctx, failedTx.Hash())
+	if err != nil {
+		t.Fatalf("GetRevertReason() error = %v", err)
+	}
+	if reason.Reason != "insufficient balance" || reason.Message() != "insufficient balance" {
+		t.Errorf("reason = %q (message %q), want insufficient balance", reason.Reason, reason.Message())
+	}
+	if reason.Error != "execution reverted" {
+		t.Errorf("error = %q, want execution reverted", reason.Error)
+	}
+	if hexutil.Encode(reason.Data) != hexutil.Encode(insufficientBalanceRevert) {
+		t.Errorf("data = %x, want %x", reason.Data, insufficientBalanceRevert)
+	}
+
+	if _, err := store.GetRevertReason(ctx, okTx.Hash()); err != storagepkg.ErrNotFound {
+		t.Errorf("GetRevertReason() for successful tx error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestFetchBlock_RevertReasonTraceErrorDoesNotFail(t *testing.T) {
+	tx := types.NewTransaction(0, traceRouter, big.NewInt(1), 200000, big.NewInt(1), nil)
+	block := createTracedBlock(5).WithBody(types.Body{Transactions: []*types.Transaction{tx}})
+
+	mockClient := newMockClient()
+	mockClient.blocks[5] = block
+	mockClient.receipts[block.Hash()] = types.Receipts{
+		{TxHash: tx.Hash(), Status: types.ReceiptStatusFailed, Logs: []*types.Log{}},
+	}
+
+	config := &Config{BatchSize: 10, MaxRetries: 3, RetryDelay: time.Millisecond}
+	f := NewFetcher(mockClient, newMockStorage(), config, zap.NewNop(), nil)
+	store := setupTestStorage(t)
+	f.SetRevertReasonProcessor(NewRevertReasonProcessor(zap.NewNop(), failingTransactionTracer{}, store))
+
+	if err := f.FetchBlock(context.Background(), 5); err != nil {
+		t.Fatalf("FetchBlock() should succeed when tracing fails, got %v", err)
+	}
+	if _, err := store.GetRevertReason(context.Background(), tx.Hash()); err != storagepkg.ErrNotFound {
+		t.Errorf("GetRevertReason() error = %v, want ErrNotFound", err)
+	}
+}
+
+// failingTransactionTracer is a node without debug_traceTransaction
+type failingTransactionTracer struct{}
+
+func (failingTransactionTracer) TraceTransaction(ctx context.Context, hash common.Hash) (*client.CallFrame, error) {
+	return nil, fmt.Errorf("the method debug_traceTransaction does not exist")
+}
+
+func TestDecodeRevertReason(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"error string", hexutil.Encode(insufficientBalanceRevert), "insufficient balance"},
+		{"panic overflow", "0x4e487b710000000000000000000000000000000000000000000000000000000000000011", "arithmetic underflow or overflow"},
+		{"custom error", "0xe450d38c", ""},
+		{"empty", "0x", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := decodeRevertReason(hexutil.MustDecode(tt.data)); got != tt.want {
+				t.Errorf("decodeRevertReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package fetch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+
+	storagepkg "github.com/0xmhha/indexer-go/pkg/storage"
+)
+
+// stateSnapshotFile is the on-disk form of a state snapshot. Amounts are
+// decimal or 0x-prefixed hex strings, since they exceed JSON number precision.
+type stateSnapshotFile struct {
+	Height        uint64            `json:"height"`
+	TotalSupply   string            `json:"total_supply"`
+	Balances      map[string]string `json:"balances"`
+	ActiveMinters map[string]string `json:"active_minters"`
+}
+
+// ReadStateSnapshot loads a state snapshot from a JSON file of the form
+//
+//	{
+//	  "height": 999,
+//	  "total_supply": "1000000",
+//	  "balances": {"0x...": "250"},
+//	  "active_minters": {"0x...": "5000"}
+//	}
+//
+// The snapshot holds the state as of the end of block height.
+func ReadStateSnapshot(path string) (*storagepkg.StateSnapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file stateSnapshotFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to decode state snapshot %s: %w", path, err)
+	}
+
+	snapshot := &storagepkg.StateSnapshot{
+		Height:        file.Height,
+		Balances:      make(map[common.Address]*big.Int, len(file.Balances)),
+		ActiveMinters: make(map[common.Address]*big.Int, len(file.ActiveMinters)),
+	}
+	if file.TotalSupply != "" {
+		if snapshot.TotalSupply, err = parseSnapshotAmount(file.TotalSupply); err != nil {
+			return nil, fmt.Errorf("invalid total_supply in state snapshot: %w", err)
+		}
+	}
+	if err := parseSnapshotAmounts(file.Balances, snapshot.Balances); err != nil {
+		return nil, fmt.Errorf("invalid balances in state snapshot: %w", err)
+	}
+	if err := parseSnapshotAmounts(file.ActiveMinters, snapshot.ActiveMinters); err != nil {
+		return nil, fmt.Errorf("invalid active_minters in state snapshot: %w", err)
+	}
+	return snapshot, nil
+}
+
+// parseSnapshotAmounts parses an address to amount map into dst
+func parseSnapshotAmounts(src map[string]string, dst map[common.Address]*big.Int) error {
+	for addr, value := range src {
+		if !common.IsHexAddress(addr) {
+			return fmt.Errorf("invalid address %q", addr)
+		}
+		amount, err := parseSnapshotAmount(value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", addr, err)
+		}
+		dst[common.HexToAddress(addr)] = amount
+	}
+	return nil
+}
+
+// parseSnapshotAmount parses a non-negative decimal or 0x-prefixed hex amount
+func parseSnapshotAmount(value string) (*big.Int, error) {
+	amount, ok := new(big.Int).SetString(value, 0)
+	if !ok || amount.Sign() < 0 {
+		return nil, fmt.Errorf("invalid amount %q", value)
+	}
+	return amount, nil
+}
+
+// ApplyStateSnapshot seeds storage from the configured state snapshot before
+// indexing starts. The snapshot must end at the block before StartHeight so no
+// block is counted both in the snapshot and by indexing. It is a no-op when no
+// snapshot is configured or the snapshot was already applied.
+func (f *Fetcher) ApplyStateSnapshot(ctx context.Context) error {
+	if f.config.SnapshotPath == "" {
+		return nil
+	}
+
+	snapshot, err := ReadStateSnapshot(f.config.SnapshotPath)
+	if err != nil {
+		return fmt.Errorf("failed to read state snapshot: %w", err)
+	}
+	if f.config.StartHeight != snapshot.Height+1 {
+		return fmt.Errorf("state snapshot at height %d requires start height %d, got %d",
+			snapshot.Height, snapshot.Height+1, f.config.StartHeight)
+	}
+
+	writer, ok := f.storage.(storagepkg.StateSnapshotWriter)
+	if !ok {
+		return fmt.Errorf("storage does not support state snapshots")
+	}
+	if err := writer.ApplyStateSnapshot(ctx, snapshot); err != nil {
+		return fmt.Errorf("failed to apply state snapshot: %w", err)
+	}
+
+	f.logger.Info("State snapshot applied",
+		zap.String("path", f.config.SnapshotPath),
+		zap.Uint64("height", snapshot.Height),
+		zap.Int("balances", len(snapshot.Balances)),
+		zap.Int("active_minters", len(snapshot.ActiveMinters)),
+	)
+	return nil
+}
@@ -0,0 +1,151 @@
+package fetch
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	storagepkg "github.com/0xmhha/indexer-go/pkg/storage"
+)
+
+// writeSnapshotFile writes content to a snapshot file in a temp directory
+func writeSnapshotFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	return path
+}
+
+func TestReadStateSnapshot(t *testing.T) {
+	path := writeSnapshotFile(t, `{
+		"height": 999,
+		"total_supply": "0x3e8",
+		"balances": {"0x00000000000000000000000000000000000000a1": "250"},
+		"active_minters": {"0x00000000000000000000000000000000000000b1": "5000"}
+	}`)
+
+	snapshot, err := ReadStateSnapshot(path)
+	if err != nil {
+		t.Fatalf("ReadStateSnapshot() error = %v", err)
+	}
+	if snapshot.Height != 999 {
+		t.Errorf("Height = %d, want 999", snapshot.Height)
+	}
+	if snapshot.TotalSupply.Int64() != 1000 {
+		t.Errorf("TotalSupply = %s, want 1000", snapshot.TotalSupply)
+	}
+	if got := snapshot.Balances[common.HexToAddress("0xa1")]; got == nil || got.Int64() != 250 {
+		t.Errorf("balance = %v, want 250", got)
+	}
+	if got := snapshot.ActiveMinters[common.HexToAddress("0xb1")]; got == nil || got.Int64() != 5000 {
+		t.Errorf("minter allowance = %v, want 5000", got)
+	}
+}
+
+func TestReadStateSnapshot_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"malformed json", `{"height":`, "failed to decode"},
+		{"invalid address", `{"height": 1, "balances": {"0xzz": "1"}}`, "invalid balances"},
+		{"negative balance", `{"height": 1, "balances": {"0x00000000000000000000000000000000000000a1": "-1"}}`, "invalid balances"},
+		{"invalid total supply", `{"height": 1, "total_supply": "lots"}`, "invalid total_supply"},
+		{"invalid allowance", `{"height": 1, "active_minters": {"0x00000000000000000000000000000000000000b1": "1.5"}}`, "invalid active_minters"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadStateSnapshot(writeSnapshotFile(t, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ReadStateSnapshot() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFetcher_ApplyStateSnapshot(t *testing.T) {
+	store := setupTestStorage(t)
+
+	ctx := context.Background()
+	mockClient := newMockClient()
+	sender, _ := setupBalanceBlock(t, mockClient, 1)
+	minter := common.HexToAddress("0x00000000000000000000000000000000000000b1")
+
+	path := writeSnapshotFile(t, `{
+		"height": 0,
+		"total_supply": "5000000",
+		"balances": {
+			"`+sender.Hex()+`": "1000000",
+			"`+balanceTestRecipient.Hex()+`": "500"
+		},
+		"active_minters": {"`+minter.Hex()+`": "7000"}
+	}`)
+
+	fetcher := newBalanceTestFetcher(mockClient, store)
+	fetcher.config.SnapshotPath = path
+	fetcher.config.StartHeight = 1
+
+	if err := fetcher.ApplyStateSnapshot(ctx); err != nil {
+		t.Fatalf("ApplyStateSnapshot() error = %v", err)
+	}
+	// Restarting with the same snapshot must not apply it again
+	if err := fetcher.ApplyStateSnapshot(ctx); err != nil {
+		t.Fatalf("ApplyStateSnapshot() second call error = %v", err)
+	}
+
+	// Indexing the first block builds on the seeded balances
+	if err := fetcher.FetchBlock(ctx, 1); err != nil {
+		t.Fatalf("FetchBlock() error = %v", err)
+	}
+	for addr, want := range map[common.Address]int64{
+		sender:               1_000_000 - 1000 - 2000 - 2*21000,
+		balanceTestRecipient: 500 + 3000,
+	} {
+		balance, err := store.GetAddressBalance(ctx, addr, 0)
+		if err != nil {
+			t.Fatalf("GetAddressBalance() error = %v", err)
+		}
+		if balance.Cmp(big.NewInt(want)) != 0 {
+			t.Errorf("balance of %s = %s, want %d", addr.Hex(), balance, want)
+		}
+	}
+
+	supply, err := store.GetTotalSupply(ctx)
+	if err != nil {
+		t.Fatalf("GetTotalSupply() error = %v", err)
+	}
+	if supply.Int64() != 5_000_000 {
+		t.Errorf("total supply = %s, want 5000000", supply)
+	}
+	minters, err := store.GetActiveMinters(ctx)
+	if err != nil {
+		t.Fatalf("GetActiveMinters() error = %v", err)
+	}
+	if len(minters) != 1 || minters[0].Address != minter || minters[0].Allowance.Int64() != 7000 {
+		t.Errorf("active minters = %+v, want %s with allowance 7000", minters, minter.Hex())
+	}
+}
+
+func TestFetcher_ApplyStateSnapshot_StartHeightMismatch(t *testing.T) {
+	store := setupTestStorage(t)
+
+	fetcher := newBalanceTestFetcher(newMockClient(), store)
+	fetcher.config.SnapshotPath = writeSnapshotFile(t, `{"height": 999}`)
+	fetcher.config.StartHeight = 500
+
+	if err := fetcher.ApplyStateSnapshot(context.Background()); err == nil {
+		t.Fatal("ApplyStateSnapshot() should fail when start height does not follow the snapshot")
+	}
+	if _, err := store.GetStateSnapshotHeight(context.Background()); err != storagepkg.ErrNotFound {
+		t.Errorf("GetStateSnapshotHeight() error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,45 @@
+package fetch
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// ErrRootMismatch is returned by VerifyOnIngest when a fetched block's
+// transactions or receipts do not hash to the roots in its header
+var ErrRootMismatch = errors.New("block root mismatch")
+
+// verifyIngest checks a fetched block against its header when VerifyOnIngest
+// is set. A rejected block is never stored; callers dead-letter it like any
+// other fetch failure.
+func (f *Fetcher) verifyIngest(block *types.Block, receipts types.Receipts) error {
+	if !f.config.VerifyOnIngest {
+		return nil
+	}
+	if err := verifyBlockRoots(block, receipts); err != nil {
+		f.metrics.RecordRejectedBlock()
+		return fmt.Errorf("block %d: %w", block.NumberU64(), err)
+	}
+	return nil
+}
+
+// verifyBlockRoots recomputes the transactions root and, when receipts are
+// present, the receipts root, and compares them with the header
+func verifyBlockRoots(block *types.Block, receipts types.Receipts) error {
+	txRoot := types.DeriveSha(block.Transactions(), trie.NewStackTrie(nil))
+	if txRoot != block.TxHash() {
+		return fmt.Errorf("%w: transactions root %s, header has %s", ErrRootMismatch, txRoot.Hex(), block.TxHash().Hex())
+	}
+
+	if len(receipts) == 0 {
+		return nil
+	}
+	receiptRoot := types.DeriveSha(receipts, trie.NewStackTrie(nil))
+	if receiptRoot != block.ReceiptHash() {
+		return fmt.Errorf("%w: receipts root %s, header has %s", ErrRootMismatch, receiptRoot.Hex(), block.ReceiptHash().Hex())
+	}
+	return nil
+}
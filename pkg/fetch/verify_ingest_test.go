@@ -0,0 +1,152 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/trie"
+	"go.uber.org/zap"
+
+	storagepkg "github.com/0xmhha/indexer-go/pkg/storage"
+)
+
+// setupVerifiedBlock registers a block whose header commits to its two signed
+// transfers and their receipts, and funds the sender in store
+func setupVerifiedBlock(t *testing.T, client *mockClient, store *storagepkg.PebbleStorage, height uint64) (*types.Block, types.Receipts) {
+	t.Helper()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	signer := types.LatestSignerForChainID(balanceTestChainID)
+
+	var txs []*types.Transaction
+	var receipts types.Receipts
+	for nonce, value := range []int64{1000, 2000} {
+		tx, err := types.SignTx(types.NewTransaction(uint64(nonce), balanceTestRecipient, big.NewInt(value), 21000, big.NewInt(1), nil), signer, key)
+		if err != nil {
+			t.Fatalf("SignTx() error = %v", err)
+		}
+		txs = append(txs, tx)
+		receipts = append(receipts, &types.Receipt{
+			Status:            types.ReceiptStatusSuccessful,
+			CumulativeGasUsed: uint64(21000 * (nonce + 1)),
+			GasUsed:           21000,
+			TxHash:            tx.Hash(),
+			BlockNumber:       new(big.Int).SetUint64(height),
+			Logs:              []*types.Log{},
+		})
+	}
+
+	header := &types.Header{
+		Number:     new(big.Int).SetUint64(height),
+		Difficulty: big.NewInt(1),
+		GasLimit:   8000000,
+		GasUsed:    42000,
+	}
+	block := types.NewBlock(header, &types.Body{Transactions: txs}, receipts, trie.NewStackTrie(nil))
+
+	client.blocks[height] = block
+	client.receipts[block.Hash()] = receipts
+	if err := store.SetBalance(context.Background(), crypto.PubkeyToAddress(key.PublicKey), 0, big.NewInt(1_000_000)); err != nil {
+		t.Fatalf("SetBalance() error = %v", err)
+	}
+	return block, receipts
+}
+
+func newVerifyingFetcher(t *testing.T, verify bool) (*Fetcher, *mockClient, *storagepkg.PebbleStorage) {
+	t.Helper()
+
+	store := setupTestStorage(t)
+
+	client := newMockClient()
+	config := &Config{BatchSize: 10, MaxRetries: 1, RetryDelay: time.Millisecond, NumWorkers: 2, VerifyOnIngest: verify}
+	return NewFetcher(client, store, config, zap.NewNop(), nil), client, store
+}
+
+// assertRejected checks that a block failed verification, was not stored and
+// was dead-lettered
+func assertRejected(t *testing.T, store *storagepkg.PebbleStorage, height uint64, err error) {
+	t.Helper()
+	ctx := context.Background()
+
+	if !errors.Is(err, ErrRootMismatch) {
+		t.Fatalf("error = %v, want ErrRootMismatch", err)
+	}
+	if has, err := store.HasBlock(ctx, height); err != nil || has {
+		t.Errorf("HasBlock(%d) = %v, %v; want the rejected block not stored", height, has, err)
+	}
+	record, err := store.GetFailedBlock(ctx, height)
+	if err != nil {
+		t.Fatalf("GetFailedBlock(%d) error = %v", height, err)
+	}
+	if !strings.Contains(record.Error, ErrRootMismatch.Error()) {
+		t.Errorf("failed block error = %q, want a root mismatch", record.Error)
+	}
+}
+
+func TestVerifyOnIngest_AcceptsConsistentBlock(t *testing.T) {
+	fetcher, client, store := newVerifyingFetcher(t, true)
+	setupVerifiedBlock(t, client, store, 1)
+
+	if err := fetcher.FetchBlock(context.Background(), 1); err != nil {
+		t.Fatalf("FetchBlock() error = %v", err)
+	}
+	if has, err := store.HasBlock(context.Background(), 1); err != nil || !has {
+		t.Errorf("HasBlock(1) = %v, %v; want true", has, err)
+	}
+}
+
+func TestVerifyOnIngest_RejectsTamperedTxRoot(t *testing.T) {
+	fetcher, client, store := newVerifyingFetcher(t, true)
+	block, _ := setupVerifiedBlock(t, client, store, 1)
+
+	// Drop a transaction from the body; the header still commits to both
+	tampered := types.NewBlockWithHeader(block.Header()).WithBody(types.Body{Transactions: block.Transactions()[:1]})
+	client.blocks[1] = tampered
+
+	err := fetcher.FetchBlock(context.Background(), 1)
+	assertRejected(t, store, 1, err)
+}
+
+func TestVerifyOnIngest_RejectsTamperedReceipts(t *testing.T) {
+	fetcher, client, store := newVerifyingFetcher(t, true)
+	block, receipts := setupVerifiedBlock(t, client, store, 1)
+
+	// Report the second transfer as failed, which the receipts root does not commit to
+	failed := *receipts[1]
+	failed.Status = types.ReceiptStatusFailed
+	client.receipts[block.Hash()] = types.Receipts{receipts[0], &failed}
+
+	err := fetcher.FetchBlock(context.Background(), 1)
+	assertRejected(t, store, 1, err)
+}
+
+func TestVerifyOnIngest_RejectsTamperedBlockInConcurrentRange(t *testing.T) {
+	fetcher, client, store := newVerifyingFetcher(t, true)
+	for height := uint64(1); height <= 3; height++ {
+		setupVerifiedBlock(t, client, store, height)
+	}
+	block := client.blocks[2]
+	client.blocks[2] = types.NewBlockWithHeader(block.Header()).WithBody(types.Body{Transactions: block.Transactions()[1:]})
+
+	err := fetcher.FetchRangeConcurrent(context.Background(), 1, 3)
+	assertRejected(t, store, 2, err)
+}
+
+func TestVerifyOnIngest_DisabledStoresTamperedBlock(t *testing.T) {
+	fetcher, client, store := newVerifyingFetcher(t, false)
+	block, _ := setupVerifiedBlock(t, client, store, 1)
+	client.blocks[1] = types.NewBlockWithHeader(block.Header()).WithBody(types.Body{Transactions: block.Transactions()[:1]})
+
+	if err := fetcher.FetchBlock(context.Background(), 1); err != nil {
+		t.Fatalf("FetchBlock() without verification error = %v", err)
+	}
+}
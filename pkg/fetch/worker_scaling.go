@@ -0,0 +1,135 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"go.uber.org/zap"
+
+	"github.com/0xmhha/indexer-go/internal/constants"
+	"github.com/0xmhha/indexer-go/pkg/client"
+)
+
+// workerLimiter gates a fixed pool of workers so that only the first `limit`
+// workers pull jobs. The limit can be changed while the pool is running.
+type workerLimiter struct {
+	mu      sync.Mutex
+	limit   int
+	changed chan struct{} // closed and replaced whenever the limit changes
+	metrics *RPCMetrics   // records the limit as the active worker count; may be nil
+}
+
+// newWorkerLimiter creates a limiter allowing `limit` active workers
+func newWorkerLimiter(limit int, metrics *RPCMetrics) *workerLimiter {
+	if metrics != nil {
+		metrics.SetActiveWorkers(limit)
+	}
+	return &workerLimiter{
+		limit:   limit,
+		changed: make(chan struct{}),
+		metrics: metrics,
+	}
+}
+
+// Limit returns the current number of active workers
+func (l *workerLimiter) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit
+}
+
+// SetLimit changes the number of active workers and wakes parked workers
+func (l *workerLimiter) SetLimit(limit int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if limit == l.limit {
+		return
+	}
+	l.limit = limit
+	close(l.changed)
+	l.changed = make(chan struct{})
+	if l.metrics != nil {
+		l.metrics.SetActiveWorkers(limit)
+	}
+}
+
+// Wait blocks until workerID is within the active limit or ctx is done
+func (l *workerLimiter) Wait(ctx context.Context, workerID int) error {
+	for {
+		l.mu.Lock()
+		limit, changed := l.limit, l.changed
+		l.mu.Unlock()
+
+		if workerID < limit {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// workerPoolSize returns the number of worker goroutines to start and the
+// number of them that should initially be active
+func (f *Fetcher) workerPoolSize() (poolSize, active int) {
+	numWorkers := f.config.NumWorkers
+	if numWorkers == 0 {
+		numWorkers = constants.DefaultNumWorkers
+	}
+
+	if f.optimizer == nil {
+		return numWorkers, numWorkers
+	}
+	return f.optimizer.maxWorkers, f.optimizer.GetRecommendedWorkers()
+}
+
+// scaleWorkers runs the adaptive optimizer and applies its recommended worker
+// count to the running pool. It is a no-op when adaptive optimization is disabled.
+func (f *Fetcher) scaleWorkers(limiter *workerLimiter) {
+	if f.optimizer == nil {
+		return
+	}
+
+	f.optimizer.Optimize()
+	recommended := f.optimizer.GetRecommendedWorkers()
+	if current := limiter.Limit(); recommended != current {
+		f.logger.Info("Scaling fetch workers",
+			zap.Int("from", current),
+			zap.Int("to", recommended),
+		)
+		limiter.SetLimit(recommended)
+	}
+}
+
+// isRateLimitError reports whether err signals that the node is overloaded:
+// HTTP 429, a JSON-RPC limit exceeded error, or a timeout
+func isRateLimitError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusTooManyRequests
+	}
+
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		return rpcErr.ErrorCode() == client.RPCCodeLimitExceeded
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
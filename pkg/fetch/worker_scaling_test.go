@@ -0,0 +1,246 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"go.uber.org/zap"
+)
+
+// newAdaptiveTestFetcher creates a fetcher with adaptive optimization that
+// re-evaluates the worker count on every call
+func newAdaptiveTestFetcher(numWorkers, minWorkers, maxWorkers int) *Fetcher {
+	optimizerConfig := DefaultOptimizerConfig()
+	optimizerConfig.MinWorkers = minWorkers
+	optimizerConfig.MaxWorkers = maxWorkers
+	optimizerConfig.AdjustmentInterval = 0
+
+	config := &Config{
+		BatchSize:                  10,
+		MaxRetries:                 3,
+		RetryDelay:                 time.Millisecond,
+		NumWorkers:                 numWorkers,
+		EnableAdaptiveOptimization: true,
+		OptimizerConfig:            optimizerConfig,
+	}
+	return NewFetcher(newMockClient(), newMockStorage(), config, zap.NewNop(), nil)
+}
+
+// recordSignals feeds n synthetic RPC results into the fetcher metrics
+func recordSignals(f *Fetcher, n int, latency time.Duration, isError, isRateLimit bool) {
+	for i := 0; i < n; i++ {
+		f.metrics.RecordRequest(latency, isError, isRateLimit)
+	}
+}
+
+func TestScaleWorkers_StartsFromConfiguredWorkers(t *testing.T) {
+	f := newAdaptiveTestFetcher(8, 2, 32)
+
+	poolSize, active := f.workerPoolSize()
+	if poolSize != 32 {
+		t.Errorf("pool size = %d, want max workers 32", poolSize)
+	}
+	if active != 8 {
+		t.Errorf("active workers = %d, want configured 8", active)
+	}
+
+	f = newAdaptiveTestFetcher(100, 2, 32)
+	if _, active := f.workerPoolSize(); active != 32 {
+		t.Errorf("active workers = %d, want clamped to 32", active)
+	}
+}
+
+func TestScaleWorkers_GrowsWhenResponsive(t *testing.T) {
+	f := newAdaptiveTestFetcher(8, 2, 32)
+	limiter := newWorkerLimiter(8, nil)
+
+	recordSignals(f, 50, 10*time.Millisecond, false, false)
+	f.scaleWorkers(limiter)
+
+	if got := limiter.Limit(); got <= 8 {
+		t.Errorf("active workers = %d, want > 8 after fast error-free responses", got)
+	}
+}
+
+func TestScaleWorkers_ShrinksOnRateLimit(t *testing.T) {
+	f := newAdaptiveTestFetcher(16, 2, 32)
+	limiter := newWorkerLimiter(16, nil)
+
+	recordSignals(f, 50, 10*time.Millisecond, false, false)
+	recordSignals(f, 1, 10*time.Millisecond, true, true)
+	f.scaleWorkers(limiter)
+
+	if got := limiter.Limit(); got != 8 {
+		t.Errorf("active workers = %d, want 8 (halved) after rate limit", got)
+	}
+}
+
+func TestScaleWorkers_ShrinksOnHighErrorRate(t *testing.T) {
+	f := newAdaptiveTestFetcher(16, 2, 32)
+	limiter := newWorkerLimiter(16, nil)
+
+	// 20% error rate without rate limit signals
+	for i := 0; i < 10; i++ {
+		recordSignals(f, 4, 10*time.Millisecond, false, false)
+		recordSignals(f, 1, 0, true, false)
+	}
+	f.scaleWorkers(limiter)
+
+	if got := limiter.Limit(); got >= 16 {
+		t.Errorf("active workers = %d, want < 16 after high error rate", got)
+	}
+}
+
+func TestScaleWorkers_ShrinksOnHighLatency(t *testing.T) {
+	f := newAdaptiveTestFetcher(16, 2, 32)
+	limiter := newWorkerLimiter(16, nil)
+
+	recordSignals(f, 50, 2*time.Second, false, false)
+	f.scaleWorkers(limiter)
+
+	if got := limiter.Limit(); got >= 16 {
+		t.Errorf("active workers = %d, want < 16 after slow responses", got)
+	}
+}
+
+func TestScaleWorkers_RespectsBounds(t *testing.T) {
+	f := newAdaptiveTestFetcher(4, 3, 6)
+	limiter := newWorkerLimiter(4, nil)
+
+	for i := 0; i < 10; i++ {
+		recordSignals(f, 10, 10*time.Millisecond, false, false)
+		f.scaleWorkers(limiter)
+	}
+	if got := limiter.Limit(); got != 6 {
+		t.Errorf("active workers = %d, want max 6", got)
+	}
+
+	for i := 0; i < 10; i++ {
+		recordSignals(f, 1, 0, true, true)
+		f.scaleWorkers(limiter)
+	}
+	if got := limiter.Limit(); got != 3 {
+		t.Errorf("active workers = %d, want min 3", got)
+	}
+}
+
+func TestScaleWorkers_DisabledIsNoop(t *testing.T) {
+	config := &Config{BatchSize: 10, MaxRetries: 3, RetryDelay: time.Millisecond, NumWorkers: 8}
+	f := NewFetcher(newMockClient(), newMockStorage(), config, zap.NewNop(), nil)
+	limiter := newWorkerLimiter(8, nil)
+
+	recordSignals(f, 10, 0, true, true)
+	f.scaleWorkers(limiter)
+
+	if got := limiter.Limit(); got != 8 {
+		t.Errorf("active workers = %d, want unchanged 8", got)
+	}
+	if poolSize, active := f.workerPoolSize(); poolSize != 8 || active != 8 {
+		t.Errorf("workerPoolSize() = (%d, %d), want (8, 8)", poolSize, active)
+	}
+}
+
+func TestWorkerLimiter_Wait(t *testing.T) {
+	limiter := newWorkerLimiter(1, nil)
+	ctx := context.Background()
+
+	if err := limiter.Wait(ctx, 0); err != nil {
+		t.Fatalf("Wait(0) error = %v", err)
+	}
+
+	released := make(chan error, 1)
+	go func() {
+		released <- limiter.Wait(ctx, 2)
+	}()
+
+	select {
+	case <-released:
+		t.Fatal("worker 2 should be parked while limit is 1")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	limiter.SetLimit(3)
+	select {
+	case err := <-released:
+		if err != nil {
+			t.Errorf("Wait(2) error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("worker 2 should be released after limit increase")
+	}
+}
+
+func TestWorkerLimiter_WaitCancelled(t *testing.T) {
+	limiter := newWorkerLimiter(1, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := limiter.Wait(ctx, 5); !errors.Is(err, context.Canceled) {
+		t.Errorf("Wait() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestIsRateLimitError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"http 429", rpc.HTTPError{StatusCode: http.StatusTooManyRequests}, true},
+		{"http 503", rpc.HTTPError{StatusCode: http.StatusServiceUnavailable}, false},
+		{"deadline", context.DeadlineExceeded, true},
+		{"wrapped deadline", fmt.Errorf("fetch: %w", context.DeadlineExceeded), true},
+		{"other", errors.New("boom"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRateLimitError(tt.err); got != tt.want {
+				t.Errorf("isRateLimitError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchRangeConcurrent_Adaptive(t *testing.T) {
+	f := newAdaptiveTestFetcher(4, 2, 16)
+	client := f.client.(*mockClient)
+	storage := f.storage.(*mockStorage)
+
+	numBlocks := uint64(50)
+	for i := uint64(0); i < numBlocks; i++ {
+		block := types.NewBlockWithHeader(&types.Header{
+			Number:     big.NewInt(int64(i)),
+			Difficulty: big.NewInt(1000),
+			GasLimit:   8000000,
+		})
+		client.blocks[i] = block
+		client.receipts[block.Hash()] = types.Receipts{}
+	}
+	client.latestBlock = numBlocks - 1
+
+	if err := f.FetchRangeConcurrent(context.Background(), 0, numBlocks-1); err != nil {
+		t.Fatalf("FetchRangeConcurrent() error = %v", err)
+	}
+
+	latestHeight, err := storage.GetLatestHeight(context.Background())
+	if err != nil {
+		t.Fatalf("GetLatestHeight() error = %v", err)
+	}
+	if latestHeight != numBlocks-1 {
+		t.Errorf("latest height = %d, want %d", latestHeight, numBlocks-1)
+	}
+
+	// Fast mock responses should have grown the worker count
+	if got := f.GetOptimalWorkerCount(); got <= 4 {
+		t.Errorf("optimal worker count = %d, want > 4", got)
+	}
+}
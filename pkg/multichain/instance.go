@@ -334,7 +334,7 @@ func (ci *ChainInstance) runFetcher() {
 			subID,
 			[]events.EventType{events.EventTypeBlock, events.EventTypeTransaction, events.EventTypeLog},
 			nil,
-			100,
+			0, // Use the bus's subscribe buffer size
 		)
 		defer ci.EventBus.Unsubscribe(subID)
 
@@ -113,7 +113,7 @@ func (s *PebbleSessionStore) Save(ctx context.Context, session *Session) error {
 func (s *PebbleSessionStore) Get(ctx context.Context, sessionID string) (*Session, error) {
 	data, err := s.kv.Get(ctx, sessionKey(sessionID))
 	if err != nil {
-		if err == storage.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			return nil, ErrSessionNotFound
 		}
 		return nil, fmt.Errorf("failed to get session: %w", err)
@@ -136,7 +136,7 @@ func (s *PebbleSessionStore) Get(ctx context.Context, sessionID string) (*Sessio
 func (s *PebbleSessionStore) GetByClientID(ctx context.Context, clientID string) (*Session, error) {
 	sessionIDBytes, err := s.kv.Get(ctx, sessionClientIndexKey(clientID))
 	if err != nil {
-		if err == storage.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			return nil, ErrSessionNotFound
 		}
 		return nil, fmt.Errorf("failed to lookup client session: %w", err)
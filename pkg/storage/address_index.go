@@ -193,3 +193,18 @@ const (
 	InternalTxTypeCreate2      = "CREATE2"
 	InternalTxTypeSelfDestruct = "SELFDESTRUCT"
 )
+
+// InternalTransferReader provides queries over internal ETH transfers discovered by tracing
+type InternalTransferReader interface {
+	// GetInternalTransfers retrieves internal ETH transfers where the address is the
+	// sender or the receiver, oldest first, with pagination.
+	// Returns empty slice if none were indexed or tracing is disabled.
+	GetInternalTransfers(ctx context.Context, addr common.Address, limit, offset int) ([]*InternalTransaction, error)
+}
+
+// InternalTransferWriter indexes internal ETH transfers discovered by tracing
+type InternalTransferWriter interface {
+	// SaveInternalTransfers indexes each transfer under both its sender and receiver.
+	// Transfers already stored (same transaction hash and index) are skipped.
+	SaveInternalTransfers(ctx context.Context, transfers []*InternalTransaction) error
+}
@@ -568,3 +568,94 @@ func TestInternalTransaction(t *testing.T) {
 		}
 	})
 }
+
+func TestInternalTransfers(t *testing.T) {
+	storage, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	writer, ok := storage.(InternalTransferWriter)
+	if !ok {
+		t.Fatal("storage does not support internal transfer indexing")
+	}
+	reader, ok := storage.(InternalTransferReader)
+	if !ok {
+		t.Fatal("storage does not support internal transfer indexing")
+	}
+
+	sender := common.BigToAddress(big.NewInt(9700))
+	txHash := common.BigToHash(big.NewInt(9701))
+
+	var transfers []*InternalTransaction
+	for i := 0; i < 3; i++ {
+		transfers = append(transfers, &InternalTransaction{
+			TransactionHash: txHash,
+			From:            sender,
+			To:              common.BigToAddress(big.NewInt(int64(9710 + i))),
+			Value:           big.NewInt(int64(100 * (i + 1))),
+			Type:            InternalTxTypeCall,
+			Index:           i,
+			BlockNumber:     300,
+			Depth:           1,
+		})
+	}
+
+	t.Run("IndexedForSenderAndReceiver", func(t *testing.T) {
+		if err := writer.SaveInternalTransfers(ctx, transfers); err != nil {
+			t.Fatalf("SaveInternalTransfers failed: %v", err)
+		}
+
+		sent, err := reader.GetInternalTransfers(ctx, sender, 10, 0)
+		if err != nil {
+			t.Fatalf("GetInternalTransfers failed: %v", err)
+		}
+		if len(sent) != 3 {
+			t.Fatalf("expected 3 transfers for sender, got %d", len(sent))
+		}
+
+		received, err := reader.GetInternalTransfers(ctx, transfers[1].To, 10, 0)
+		if err != nil {
+			t.Fatalf("GetInternalTransfers failed: %v", err)
+		}
+		if len(received) != 1 || received[0].Value.Cmp(big.NewInt(200)) != 0 {
+			t.Errorf("expected the 200 wei transfer for receiver, got %v", received)
+		}
+	})
+
+	t.Run("Pagination", func(t *testing.T) {
+		page, err := reader.GetInternalTransfers(ctx, sender, 2, 1)
+		if err != nil {
+			t.Fatalf("GetInternalTransfers failed: %v", err)
+		}
+		if len(page) != 2 {
+			t.Fatalf("expected 2 transfers, got %d", len(page))
+		}
+		if page[0].Index != 1 || page[1].Index != 2 {
+			t.Errorf("expected indexes 1 and 2, got %d and %d", page[0].Index, page[1].Index)
+		}
+	})
+
+	t.Run("SaveTwiceDoesNotDuplicate", func(t *testing.T) {
+		if err := writer.SaveInternalTransfers(ctx, transfers); err != nil {
+			t.Fatalf("SaveInternalTransfers failed: %v", err)
+		}
+
+		sent, err := reader.GetInternalTransfers(ctx, sender, 10, 0)
+		if err != nil {
+			t.Fatalf("GetInternalTransfers failed: %v", err)
+		}
+		if len(sent) != 3 {
+			t.Errorf("expected 3 transfers after re-saving, got %d", len(sent))
+		}
+	})
+
+	t.Run("UnknownAddress", func(t *testing.T) {
+		got, err := reader.GetInternalTransfers(ctx, common.HexToAddress("0xdead"), 10, 0)
+		if err != nil {
+			t.Fatalf("GetInternalTransfers failed: %v", err)
+		}
+		if len(got) != 0 {
+			t.Errorf("expected 0 transfers, got %d", len(got))
+		}
+	})
+}
@@ -157,3 +157,149 @@ func TestInterfaceAssertion(t *testing.T) {
 		t.Log("✅ PebbleStorage implements HistoricalStorage")
 	}
 }
+
+// TestBatchUpdateBalance verifies balance changes in a batch accumulate and commit together
+func TestBatchUpdateBalance(t *testing.T) {
+	storageInterface, cleanup := setupTestStorage(t)
+	defer cleanup()
+	storage := storageInterface.(*PebbleStorage)
+
+	ctx := context.Background()
+	addr := common.HexToAddress("0x1111111111111111111111111111111111111111")
+
+	batch := storage.NewBatch()
+	defer batch.Close()
+	balanceWriter, ok := batch.(BalanceWriter)
+	if !ok {
+		t.Fatal("pebble batch does not implement BalanceWriter")
+	}
+
+	if err := balanceWriter.SetBalance(ctx, addr, 1, big.NewInt(1000)); err != nil {
+		t.Fatalf("SetBalance() failed: %v", err)
+	}
+	if err := balanceWriter.UpdateBalance(ctx, addr, 2, big.NewInt(-300), common.HexToHash("0x02")); err != nil {
+		t.Fatalf("UpdateBalance() failed: %v", err)
+	}
+	if err := balanceWriter.UpdateBalance(ctx, addr, 2, big.NewInt(-800), common.HexToHash("0x03")); err == nil {
+		t.Error("UpdateBalance() should reject a change that makes the pending balance negative")
+	}
+
+	// Nothing is visible before commit
+	balance, err := storage.GetAddressBalance(ctx, addr, 0)
+	if err != nil {
+		t.Fatalf("GetAddressBalance() failed: %v", err)
+	}
+	if balance.Sign() != 0 {
+		t.Errorf("balance before commit = %s, want 0", balance)
+	}
+
+	if err := batch.Commit(); err != nil {
+		t.Fatalf("Commit() failed: %v", err)
+	}
+
+	balance, err = storage.GetAddressBalance(ctx, addr, 0)
+	if err != nil {
+		t.Fatalf("GetAddressBalance() failed: %v", err)
+	}
+	if balance.Cmp(big.NewInt(700)) != 0 {
+		t.Errorf("balance after commit = %s, want 700", balance)
+	}
+
+	history, err := storage.GetBalanceHistory(ctx, addr, 0, 10, 10, 0)
+	if err != nil {
+		t.Fatalf("GetBalanceHistory() failed: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("history length = %d, want 2", len(history))
+	}
+}
+
+// TestBatchUpdateBalance_CrashBeforeCommit simulates a crash after the balance
+// history entry was written but before the block batch committed, then reopens
+// the database and checks that latest balance and history still agree
+func TestBatchUpdateBalance_CrashBeforeCommit(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	addr := common.HexToAddress("0x2222222222222222222222222222222222222222")
+
+	storage, err := NewPebbleStorage(DefaultConfig(dir))
+	if err != nil {
+		t.Fatalf("NewPebbleStorage() failed: %v", err)
+	}
+	if err := storage.UpdateBalance(ctx, addr, 1, big.NewInt(100), common.HexToHash("0x01")); err != nil {
+		t.Fatalf("UpdateBalance() failed: %v", err)
+	}
+
+	// Block 2 and its balance change are staged, but the process dies before Commit
+	batch := storage.NewBatch()
+	if err := batch.SetBlock(ctx, createTestBlock(2)); err != nil {
+		t.Fatalf("SetBlock() failed: %v", err)
+	}
+	if err := batch.(BalanceWriter).UpdateBalance(ctx, addr, 2, big.NewInt(50), common.HexToHash("0x02")); err != nil {
+		t.Fatalf("UpdateBalance() failed: %v", err)
+	}
+	batch.Close()
+	storage.Close()
+
+	// Recovery: reopen and verify neither the block nor the balance change landed
+	storage, err = NewPebbleStorage(DefaultConfig(dir))
+	if err != nil {
+		t.Fatalf("reopen failed: %v", err)
+	}
+	defer storage.Close()
+
+	if _, err := storage.GetBlock(ctx, 2); err != ErrNotFound {
+		t.Errorf("GetBlock(2) error = %v, want ErrNotFound", err)
+	}
+	assertBalanceConsistent(t, storage, addr, big.NewInt(100), 1)
+
+	// Re-indexing the block after recovery appends to the existing history
+	batch = storage.NewBatch()
+	defer batch.Close()
+	if err := batch.SetBlock(ctx, createTestBlock(2)); err != nil {
+		t.Fatalf("SetBlock() failed: %v", err)
+	}
+	if err := batch.(BalanceWriter).UpdateBalance(ctx, addr, 2, big.NewInt(50), common.HexToHash("0x02")); err != nil {
+		t.Fatalf("UpdateBalance() failed: %v", err)
+	}
+	if err := batch.Commit(); err != nil {
+		t.Fatalf("Commit() failed: %v", err)
+	}
+
+	if _, err := storage.GetBlock(ctx, 2); err != nil {
+		t.Errorf("GetBlock(2) after commit failed: %v", err)
+	}
+	assertBalanceConsistent(t, storage, addr, big.NewInt(150), 2)
+}
+
+// assertBalanceConsistent checks the latest balance matches the newest history entry
+func assertBalanceConsistent(t *testing.T, storage *PebbleStorage, addr common.Address, want *big.Int, wantHistory int) {
+	t.Helper()
+	ctx := context.Background()
+
+	latest, err := storage.GetAddressBalance(ctx, addr, 0)
+	if err != nil {
+		t.Fatalf("GetAddressBalance() failed: %v", err)
+	}
+	if latest.Cmp(want) != 0 {
+		t.Errorf("latest balance = %s, want %s", latest, want)
+	}
+
+	history, err := storage.GetBalanceHistory(ctx, addr, 0, 100, 100, 0)
+	if err != nil {
+		t.Fatalf("GetBalanceHistory() failed: %v", err)
+	}
+	if len(history) != wantHistory {
+		t.Fatalf("history length = %d, want %d", len(history), wantHistory)
+	}
+
+	newest := history[0]
+	for _, snapshot := range history[1:] {
+		if snapshot.BlockNumber > newest.BlockNumber {
+			newest = snapshot
+		}
+	}
+	if newest.Balance.Cmp(latest) != 0 {
+		t.Errorf("newest history balance = %s, latest balance = %s", newest.Balance, latest)
+	}
+}
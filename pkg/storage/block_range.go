@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// BlocksWithMissing returns the blocks r holds between startHeight and
+// endHeight inclusive, ascending, along with the heights in that range that
+// have no block. Unlike GetBlocks it lets callers tell a complete range from
+// one with gaps. A range with startHeight > endHeight yields empty slices.
+func BlocksWithMissing(ctx context.Context, r Reader, startHeight, endHeight uint64) ([]*types.Block, []uint64, error) {
+	blocks := []*types.Block{}
+	missing := []uint64{}
+	if startHeight > endHeight {
+		return blocks, missing, nil
+	}
+
+	for height := startHeight; ; height++ {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+
+		block, err := r.GetBlock(ctx, height)
+		if err != nil {
+			if !errors.Is(err, ErrNotFound) {
+				return nil, nil, fmt.Errorf("failed to get block %d: %w", height, err)
+			}
+			missing = append(missing, height)
+		} else {
+			blocks = append(blocks, block)
+		}
+
+		// Checked here rather than in the loop condition so that an
+		// endHeight of math.MaxUint64 does not wrap around
+		if height == endHeight {
+			break
+		}
+	}
+
+	return blocks, missing, nil
+}
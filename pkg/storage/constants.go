@@ -3,4 +3,31 @@ package storage
 const (
 	// DefaultVerifiedContractsLimit is the default limit for listing verified contracts
 	DefaultVerifiedContractsLimit = 100
+
+	// DefaultCacheMB is the default block cache size in MB
+	DefaultCacheMB = 128
+
+	// DefaultWriteBufferMB is the default memtable size in MB
+	DefaultWriteBufferMB = 64
+
+	// DefaultWriteBufferCount is the default number of queued memtables
+	DefaultWriteBufferCount = 2
+
+	// DefaultBloomFilterBitsPerKey is the default SSTable bloom filter size,
+	// giving a false positive rate of about 1%
+	DefaultBloomFilterBitsPerKey = 10
+
+	// MaxWriteBufferMB is the exclusive upper bound pebble allows for a memtable
+	MaxWriteBufferMB = 4096
+
+	// ScanContextCheckInterval is the number of blocks processed between
+	// context cancellation checks in long-running chain scans
+	ScanContextCheckInterval = 100
+
+	// MaxReceiptBlockRange is the maximum number of blocks a receipt range query may span
+	MaxReceiptBlockRange = 10000
+
+	// MaxTransactionBlockRange is the maximum number of blocks a filtered
+	// transaction range query may span
+	MaxTransactionBlockRange = 10000
 )
@@ -16,6 +16,35 @@ type TxLocation struct {
 	BlockHeight uint64
 	TxIndex     uint64
 	BlockHash   common.Hash
+	// From is the sender recovered when the transaction was indexed, so reads
+	// do not repeat ecrecover. Locations stored before senders were cached
+	// decode with the zero address.
+	From common.Address `rlp:"optional"`
+}
+
+// Sender returns the sender of tx, using the cached From when it is set and
+// recovering it from the signature otherwise. l may be nil.
+func (l *TxLocation) Sender(tx *types.Transaction) (common.Address, error) {
+	if l != nil && l.From != (common.Address{}) {
+		return l.From, nil
+	}
+	return types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+}
+
+// withSender returns location with From set to the sender of tx. The location
+// is returned unchanged if From is already set or the sender cannot be
+// recovered, and is copied otherwise so the caller's value is not modified.
+func (l *TxLocation) withSender(tx *types.Transaction) *TxLocation {
+	if l.From != (common.Address{}) {
+		return l
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return l
+	}
+	withFrom := *l
+	withFrom.From = from
+	return &withFrom
 }
 
 // EncodeBlock encodes a block using RLP
@@ -26,7 +55,7 @@ func EncodeBlock(block *types.Block) ([]byte, error) {
 
 	var buf bytes.Buffer
 	if err := rlp.Encode(&buf, block); err != nil {
-		return nil, fmt.Errorf("failed to encode block: %w", err)
+		return nil, fmt.Errorf("%w block: %w", ErrEncodeFailed, err)
 	}
 
 	return buf.Bytes(), nil
@@ -35,26 +64,79 @@ func EncodeBlock(block *types.Block) ([]byte, error) {
 // DecodeBlock decodes a block from RLP
 func DecodeBlock(data []byte) (*types.Block, error) {
 	if len(data) == 0 {
-		return nil, fmt.Errorf("data cannot be empty")
+		return nil, fmt.Errorf("%w: data cannot be empty", ErrCorrupted)
 	}
 
 	var block types.Block
 	if err := rlp.DecodeBytes(data, &block); err != nil {
-		return nil, fmt.Errorf("failed to decode block: %w", err)
+		return nil, fmt.Errorf("%w block: %w", ErrDecodeFailed, err)
 	}
 
 	return &block, nil
 }
 
+// EncodeHeader encodes a block header using RLP
+func EncodeHeader(header *types.Header) ([]byte, error) {
+	if header == nil {
+		return nil, fmt.Errorf("header cannot be nil")
+	}
+
+	data, err := rlp.EncodeToBytes(header)
+	if err != nil {
+		return nil, fmt.Errorf("%w header: %w", ErrEncodeFailed, err)
+	}
+
+	return data, nil
+}
+
+// DecodeHeader decodes a block header from RLP
+func DecodeHeader(data []byte) (*types.Header, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: data cannot be empty", ErrCorrupted)
+	}
+
+	var header types.Header
+	if err := rlp.DecodeBytes(data, &header); err != nil {
+		return nil, fmt.Errorf("%w header: %w", ErrDecodeFailed, err)
+	}
+
+	return &header, nil
+}
+
+// DecodeBlockHeader decodes only the header of an RLP-encoded block, leaving
+// the transactions and uncles undecoded
+func DecodeBlockHeader(blockData []byte) (*types.Header, error) {
+	if len(blockData) == 0 {
+		return nil, fmt.Errorf("%w: data cannot be empty", ErrCorrupted)
+	}
+
+	// A block is the list [header, txs, uncles, ...]; take its first element
+	content, _, err := rlp.SplitList(blockData)
+	if err != nil {
+		return nil, fmt.Errorf("%w block: %w", ErrDecodeFailed, err)
+	}
+	kind, _, rest, err := rlp.Split(content)
+	if err != nil {
+		return nil, fmt.Errorf("%w block header: %w", ErrDecodeFailed, err)
+	}
+	if kind != rlp.List {
+		return nil, fmt.Errorf("%w block header: not a list", ErrDecodeFailed)
+	}
+
+	return DecodeHeader(content[:len(content)-len(rest)])
+}
+
 // EncodeTransaction encodes a transaction using RLP
 func EncodeTransaction(tx *types.Transaction) ([]byte, error) {
 	if tx == nil {
 		return nil, fmt.Errorf("transaction cannot be nil")
 	}
+	// Blob sidecars are not part of the block; keep only the blob hashes
+	tx = tx.WithoutBlobTxSidecar()
 
 	var buf bytes.Buffer
 	if err := rlp.Encode(&buf, tx); err != nil {
-		return nil, fmt.Errorf("failed to encode transaction: %w", err)
+		return nil, fmt.Errorf("%w transaction: %w", ErrEncodeFailed, err)
 	}
 
 	return buf.Bytes(), nil
@@ -63,12 +145,12 @@ func EncodeTransaction(tx *types.Transaction) ([]byte, error) {
 // DecodeTransaction decodes a transaction from RLP
 func DecodeTransaction(data []byte) (*types.Transaction, error) {
 	if len(data) == 0 {
-		return nil, fmt.Errorf("data cannot be empty")
+		return nil, fmt.Errorf("%w: data cannot be empty", ErrCorrupted)
 	}
 
 	var tx types.Transaction
 	if err := rlp.DecodeBytes(data, &tx); err != nil {
-		return nil, fmt.Errorf("failed to decode transaction: %w", err)
+		return nil, fmt.Errorf("%w transaction: %w", ErrDecodeFailed, err)
 	}
 
 	return &tx, nil
@@ -82,7 +164,7 @@ func EncodeReceipt(receipt *types.Receipt) ([]byte, error) {
 
 	var buf bytes.Buffer
 	if err := rlp.Encode(&buf, receipt); err != nil {
-		return nil, fmt.Errorf("failed to encode receipt: %w", err)
+		return nil, fmt.Errorf("%w receipt: %w", ErrEncodeFailed, err)
 	}
 
 	return buf.Bytes(), nil
@@ -91,12 +173,12 @@ func EncodeReceipt(receipt *types.Receipt) ([]byte, error) {
 // DecodeReceipt decodes a receipt from RLP
 func DecodeReceipt(data []byte) (*types.Receipt, error) {
 	if len(data) == 0 {
-		return nil, fmt.Errorf("data cannot be empty")
+		return nil, fmt.Errorf("%w: data cannot be empty", ErrCorrupted)
 	}
 
 	var receipt types.Receipt
 	if err := rlp.DecodeBytes(data, &receipt); err != nil {
-		return nil, fmt.Errorf("failed to decode receipt: %w", err)
+		return nil, fmt.Errorf("%w receipt: %w", ErrDecodeFailed, err)
 	}
 
 	return &receipt, nil
@@ -143,7 +225,7 @@ func EncodeLog(log *types.Log) ([]byte, error) {
 
 	var buf bytes.Buffer
 	if err := rlp.Encode(&buf, stored); err != nil {
-		return nil, fmt.Errorf("failed to encode log: %w", err)
+		return nil, fmt.Errorf("%w log: %w", ErrEncodeFailed, err)
 	}
 
 	return buf.Bytes(), nil
@@ -152,12 +234,12 @@ func EncodeLog(log *types.Log) ([]byte, error) {
 // DecodeLog decodes a log from RLP with all metadata fields restored
 func DecodeLog(data []byte) (*types.Log, error) {
 	if len(data) == 0 {
-		return nil, fmt.Errorf("data cannot be empty")
+		return nil, fmt.Errorf("%w: data cannot be empty", ErrCorrupted)
 	}
 
 	var stored storedLog
 	if err := rlp.DecodeBytes(data, &stored); err != nil {
-		return nil, fmt.Errorf("failed to decode log: %w", err)
+		return nil, fmt.Errorf("%w log: %w", ErrDecodeFailed, err)
 	}
 
 	// Convert back to types.Log
@@ -184,7 +266,7 @@ func EncodeTxLocation(loc *TxLocation) ([]byte, error) {
 
 	var buf bytes.Buffer
 	if err := rlp.Encode(&buf, loc); err != nil {
-		return nil, fmt.Errorf("failed to encode location: %w", err)
+		return nil, fmt.Errorf("%w location: %w", ErrEncodeFailed, err)
 	}
 
 	return buf.Bytes(), nil
@@ -193,12 +275,12 @@ func EncodeTxLocation(loc *TxLocation) ([]byte, error) {
 // DecodeTxLocation decodes a TxLocation from RLP
 func DecodeTxLocation(data []byte) (*TxLocation, error) {
 	if len(data) == 0 {
-		return nil, fmt.Errorf("data cannot be empty")
+		return nil, fmt.Errorf("%w: data cannot be empty", ErrCorrupted)
 	}
 
 	var loc TxLocation
 	if err := rlp.DecodeBytes(data, &loc); err != nil {
-		return nil, fmt.Errorf("failed to decode location: %w", err)
+		return nil, fmt.Errorf("%w location: %w", ErrDecodeFailed, err)
 	}
 
 	return &loc, nil
@@ -268,7 +350,7 @@ func EncodeBalanceSnapshot(snapshot *BalanceSnapshot) ([]byte, error) {
 // DecodeBalanceSnapshot decodes a BalanceSnapshot
 func DecodeBalanceSnapshot(data []byte) (*BalanceSnapshot, error) {
 	if len(data) < 8+8+1+8+32 {
-		return nil, fmt.Errorf("data too short: %d bytes", len(data))
+		return nil, fmt.Errorf("%w: data too short: %d bytes", ErrCorrupted, len(data))
 	}
 
 	offset := 0
@@ -285,7 +367,7 @@ func DecodeBalanceSnapshot(data []byte) (*BalanceSnapshot, error) {
 	var balance *big.Int
 	if balanceLen > 0 {
 		if offset+int(balanceLen) > len(data) {
-			return nil, fmt.Errorf("invalid balance length: %d", balanceLen)
+			return nil, fmt.Errorf("%w: invalid balance length: %d", ErrCorrupted, balanceLen)
 		}
 		balance = new(big.Int).SetBytes(data[offset : offset+int(balanceLen)])
 		offset += int(balanceLen)
@@ -295,14 +377,14 @@ func DecodeBalanceSnapshot(data []byte) (*BalanceSnapshot, error) {
 
 	// Read delta sign
 	if offset >= len(data) {
-		return nil, fmt.Errorf("data too short for delta sign")
+		return nil, fmt.Errorf("%w: data too short for delta sign", ErrCorrupted)
 	}
 	deltaSign := data[offset]
 	offset++
 
 	// Read delta length
 	if offset+8 > len(data) {
-		return nil, fmt.Errorf("data too short for delta length")
+		return nil, fmt.Errorf("%w: data too short for delta length", ErrCorrupted)
 	}
 	deltaLen := binary.BigEndian.Uint64(data[offset : offset+8])
 	offset += 8
@@ -311,7 +393,7 @@ func DecodeBalanceSnapshot(data []byte) (*BalanceSnapshot, error) {
 	var delta *big.Int
 	if deltaLen > 0 {
 		if offset+int(deltaLen) > len(data) {
-			return nil, fmt.Errorf("invalid delta length: %d", deltaLen)
+			return nil, fmt.Errorf("%w: invalid delta length: %d", ErrCorrupted, deltaLen)
 		}
 		delta = new(big.Int).SetBytes(data[offset : offset+int(deltaLen)])
 		offset += int(deltaLen)
@@ -324,7 +406,7 @@ func DecodeBalanceSnapshot(data []byte) (*BalanceSnapshot, error) {
 
 	// Read transaction hash
 	if offset+32 > len(data) {
-		return nil, fmt.Errorf("data too short for tx hash")
+		return nil, fmt.Errorf("%w: data too short for tx hash", ErrCorrupted)
 	}
 	var txHash common.Hash
 	copy(txHash[:], data[offset:offset+32])
@@ -353,87 +435,140 @@ func DecodeBigInt(data []byte) *big.Int {
 	return new(big.Int).SetBytes(data)
 }
 
-// System Contract Event Encoders
-
-// EncodeMintEvent encodes a MintEvent using RLP
-func EncodeMintEvent(event *MintEvent) ([]byte, error) {
-	if event == nil {
-		return nil, fmt.Errorf("event cannot be nil")
+// EncodeAddressStats encodes AddressStats using RLP
+func EncodeAddressStats(stats *AddressStats) ([]byte, error) {
+	if stats == nil {
+		return nil, fmt.Errorf("stats cannot be nil")
 	}
 
 	var buf bytes.Buffer
-	if err := rlp.Encode(&buf, event); err != nil {
-		return nil, fmt.Errorf("failed to encode mint event: %w", err)
+	if err := rlp.Encode(&buf, stats); err != nil {
+		return nil, fmt.Errorf("%w address stats: %w", ErrEncodeFailed, err)
 	}
 
 	return buf.Bytes(), nil
 }
 
-// DecodeMintEvent decodes a MintEvent from RLP
-func DecodeMintEvent(data []byte) (*MintEvent, error) {
+// DecodeAddressStats decodes AddressStats from RLP
+func DecodeAddressStats(data []byte) (*AddressStats, error) {
 	if len(data) == 0 {
-		return nil, fmt.Errorf("data cannot be empty")
+		return nil, fmt.Errorf("%w: data cannot be empty", ErrCorrupted)
 	}
 
-	var event MintEvent
-	if err := rlp.DecodeBytes(data, &event); err != nil {
-		return nil, fmt.Errorf("failed to decode mint event: %w", err)
+	var stats AddressStats
+	if err := rlp.DecodeBytes(data, &stats); err != nil {
+		return nil, fmt.Errorf("%w address stats: %w", ErrDecodeFailed, err)
+	}
+	for _, n := range []**big.Int{&stats.TotalGasCost, &stats.TotalValueSent, &stats.TotalValueReceived} {
+		if *n == nil {
+			*n = big.NewInt(0)
+		}
 	}
 
-	return &event, nil
+	return &stats, nil
 }
 
-// EncodeBurnEvent encodes a BurnEvent using RLP
-func EncodeBurnEvent(event *BurnEvent) ([]byte, error) {
-	if event == nil {
-		return nil, fmt.Errorf("event cannot be nil")
-	}
+// System Contract Event Encoders
+//
+// Encoded system contract events start with a one-byte schema version, so an
+// event layout can change without a full resync: decoders dispatch on the
+// version and keep reading older records. Records written before versioning
+// have no prefix and are decoded with the version 1 layout.
 
+// eventEncodingV1 is the first versioned system contract event encoding
+const eventEncodingV1 byte = 0x01
+
+// eventEncodingVersion is the version written by the Encode*Event functions
+const eventEncodingVersion = eventEncodingV1
+
+// encodeVersionedEventRLP encodes an event as its version byte followed by RLP
+func encodeVersionedEventRLP(event interface{}, name string) ([]byte, error) {
 	var buf bytes.Buffer
+	buf.WriteByte(eventEncodingVersion)
 	if err := rlp.Encode(&buf, event); err != nil {
-		return nil, fmt.Errorf("failed to encode burn event: %w", err)
+		return nil, fmt.Errorf("%w %s: %w", ErrEncodeFailed, name, err)
 	}
 
 	return buf.Bytes(), nil
 }
 
-// DecodeBurnEvent decodes a BurnEvent from RLP
-func DecodeBurnEvent(data []byte) (*BurnEvent, error) {
+// decodeVersionedEventRLP decodes an event written by encodeVersionedEventRLP.
+// Unversioned records start with an RLP list header (>= 0xc0), which no
+// version byte uses, and are decoded as version 1.
+func decodeVersionedEventRLP(data []byte, event interface{}, name string) error {
 	if len(data) == 0 {
-		return nil, fmt.Errorf("data cannot be empty")
+		return fmt.Errorf("%w: data cannot be empty", ErrCorrupted)
 	}
 
-	var event BurnEvent
-	if err := rlp.DecodeBytes(data, &event); err != nil {
-		return nil, fmt.Errorf("failed to decode burn event: %w", err)
+	payload := data
+	switch version := data[0]; {
+	case version >= 0xc0:
+		// Unversioned record in the version 1 layout
+	case version == eventEncodingV1:
+		payload = data[1:]
+	default:
+		return fmt.Errorf("%w: unsupported %s encoding version %d", ErrCorrupted, name, version)
+	}
+
+	if err := rlp.DecodeBytes(payload, event); err != nil {
+		return fmt.Errorf("%w %s: %w", ErrDecodeFailed, name, err)
+	}
+
+	return nil
+}
+
+// EncodeMintEvent encodes a MintEvent using versioned RLP
+func EncodeMintEvent(event *MintEvent) ([]byte, error) {
+	if event == nil {
+		return nil, fmt.Errorf("event cannot be nil")
+	}
+
+	return encodeVersionedEventRLP(event, "mint event")
+}
+
+// DecodeMintEvent decodes a MintEvent from versioned or unversioned RLP
+func DecodeMintEvent(data []byte) (*MintEvent, error) {
+	var event MintEvent
+	if err := decodeVersionedEventRLP(data, &event, "mint event"); err != nil {
+		return nil, err
 	}
 
 	return &event, nil
 }
 
-// EncodeMinterConfigEvent encodes a MinterConfigEvent using RLP
-func EncodeMinterConfigEvent(event *MinterConfigEvent) ([]byte, error) {
+// EncodeBurnEvent encodes a BurnEvent using versioned RLP
+func EncodeBurnEvent(event *BurnEvent) ([]byte, error) {
 	if event == nil {
 		return nil, fmt.Errorf("event cannot be nil")
 	}
 
-	var buf bytes.Buffer
-	if err := rlp.Encode(&buf, event); err != nil {
-		return nil, fmt.Errorf("failed to encode minter config event: %w", err)
+	return encodeVersionedEventRLP(event, "burn event")
+}
+
+// DecodeBurnEvent decodes a BurnEvent from versioned or unversioned RLP
+func DecodeBurnEvent(data []byte) (*BurnEvent, error) {
+	var event BurnEvent
+	if err := decodeVersionedEventRLP(data, &event, "burn event"); err != nil {
+		return nil, err
 	}
 
-	return buf.Bytes(), nil
+	return &event, nil
 }
 
-// DecodeMinterConfigEvent decodes a MinterConfigEvent from RLP
-func DecodeMinterConfigEvent(data []byte) (*MinterConfigEvent, error) {
-	if len(data) == 0 {
-		return nil, fmt.Errorf("data cannot be empty")
+// EncodeMinterConfigEvent encodes a MinterConfigEvent using versioned RLP
+func EncodeMinterConfigEvent(event *MinterConfigEvent) ([]byte, error) {
+	if event == nil {
+		return nil, fmt.Errorf("event cannot be nil")
 	}
 
+	return encodeVersionedEventRLP(event, "minter config event")
+}
+
+// DecodeMinterConfigEvent decodes a MinterConfigEvent from versioned or unversioned RLP
+func DecodeMinterConfigEvent(data []byte) (*MinterConfigEvent, error) {
 	var event MinterConfigEvent
-	if err := rlp.DecodeBytes(data, &event); err != nil {
-		return nil, fmt.Errorf("failed to decode minter config event: %w", err)
+	if err := decodeVersionedEventRLP(data, &event, "minter config event"); err != nil {
+		return nil, err
 	}
 
 	return &event, nil
@@ -544,7 +679,7 @@ func EncodeProposal(proposal *Proposal) ([]byte, error) {
 // DecodeProposal decodes a Proposal from custom binary format
 func DecodeProposal(data []byte) (*Proposal, error) {
 	if len(data) < 8+32+20+8+20+32+8+8+4+4+4+1+8+1 {
-		return nil, fmt.Errorf("data too short: %d bytes", len(data))
+		return nil, fmt.Errorf("%w: data too short: %d bytes", ErrCorrupted, len(data))
 	}
 
 	offset := 0
@@ -567,7 +702,7 @@ func DecodeProposal(data []byte) (*Proposal, error) {
 	offset += 8
 	if proposalIDLen > 0 {
 		if offset+int(proposalIDLen) > len(data) {
-			return nil, fmt.Errorf("invalid proposalID length: %d", proposalIDLen)
+			return nil, fmt.Errorf("%w: invalid proposalID length: %d", ErrCorrupted, proposalIDLen)
 		}
 		proposal.ProposalID = new(big.Int).SetBytes(data[offset : offset+int(proposalIDLen)])
 		offset += int(proposalIDLen)
@@ -588,7 +723,7 @@ func DecodeProposal(data []byte) (*Proposal, error) {
 	offset += 8
 	if callDataLen > 0 {
 		if offset+int(callDataLen) > len(data) {
-			return nil, fmt.Errorf("invalid callData length: %d", callDataLen)
+			return nil, fmt.Errorf("%w: invalid callData length: %d", ErrCorrupted, callDataLen)
 		}
 		proposal.CallData = make([]byte, callDataLen)
 		copy(proposal.CallData, data[offset:offset+int(callDataLen)])
@@ -600,7 +735,7 @@ func DecodeProposal(data []byte) (*Proposal, error) {
 	offset += 8
 	if memberVersionLen > 0 {
 		if offset+int(memberVersionLen) > len(data) {
-			return nil, fmt.Errorf("invalid memberVersion length: %d", memberVersionLen)
+			return nil, fmt.Errorf("%w: invalid memberVersion length: %d", ErrCorrupted, memberVersionLen)
 		}
 		proposal.MemberVersion = new(big.Int).SetBytes(data[offset : offset+int(memberVersionLen)])
 		offset += int(memberVersionLen)
@@ -633,7 +768,7 @@ func DecodeProposal(data []byte) (*Proposal, error) {
 	offset++
 	if hasExecutedAt == 1 {
 		if offset+8 > len(data) {
-			return nil, fmt.Errorf("data too short for executedAt")
+			return nil, fmt.Errorf("%w: data too short for executedAt", ErrCorrupted)
 		}
 		executedAt := binary.BigEndian.Uint64(data[offset : offset+8])
 		proposal.ExecutedAt = &executedAt
@@ -650,7 +785,7 @@ func EncodeProposalVote(vote *ProposalVote) ([]byte, error) {
 
 	var buf bytes.Buffer
 	if err := rlp.Encode(&buf, vote); err != nil {
-		return nil, fmt.Errorf("failed to encode proposal vote: %w", err)
+		return nil, fmt.Errorf("%w proposal vote: %w", ErrEncodeFailed, err)
 	}
 
 	return buf.Bytes(), nil
@@ -659,87 +794,70 @@ func EncodeProposalVote(vote *ProposalVote) ([]byte, error) {
 // DecodeProposalVote decodes a ProposalVote from RLP
 func DecodeProposalVote(data []byte) (*ProposalVote, error) {
 	if len(data) == 0 {
-		return nil, fmt.Errorf("data cannot be empty")
+		return nil, fmt.Errorf("%w: data cannot be empty", ErrCorrupted)
 	}
 
 	var vote ProposalVote
 	if err := rlp.DecodeBytes(data, &vote); err != nil {
-		return nil, fmt.Errorf("failed to decode proposal vote: %w", err)
+		return nil, fmt.Errorf("%w proposal vote: %w", ErrDecodeFailed, err)
 	}
 
 	return &vote, nil
 }
 
-// EncodeGasTipUpdateEvent encodes a GasTipUpdateEvent using RLP
+// EncodeGasTipUpdateEvent encodes a GasTipUpdateEvent using versioned RLP
 func EncodeGasTipUpdateEvent(event *GasTipUpdateEvent) ([]byte, error) {
 	if event == nil {
 		return nil, fmt.Errorf("event cannot be nil")
 	}
 
-	var buf bytes.Buffer
-	if err := rlp.Encode(&buf, event); err != nil {
-		return nil, fmt.Errorf("failed to encode gas tip update event: %w", err)
-	}
-
-	return buf.Bytes(), nil
+	return encodeVersionedEventRLP(event, "gas tip update event")
 }
 
-// DecodeGasTipUpdateEvent decodes a GasTipUpdateEvent from RLP
+// DecodeGasTipUpdateEvent decodes a GasTipUpdateEvent from versioned or unversioned RLP
 func DecodeGasTipUpdateEvent(data []byte) (*GasTipUpdateEvent, error) {
-	if len(data) == 0 {
-		return nil, fmt.Errorf("data cannot be empty")
-	}
-
 	var event GasTipUpdateEvent
-	if err := rlp.DecodeBytes(data, &event); err != nil {
-		return nil, fmt.Errorf("failed to decode gas tip update event: %w", err)
+	if err := decodeVersionedEventRLP(data, &event, "gas tip update event"); err != nil {
+		return nil, err
 	}
 
 	return &event, nil
 }
 
-// EncodeBlacklistEvent encodes a BlacklistEvent using RLP
+// EncodeBlacklistEvent encodes a BlacklistEvent using versioned RLP
 func EncodeBlacklistEvent(event *BlacklistEvent) ([]byte, error) {
 	if event == nil {
 		return nil, fmt.Errorf("event cannot be nil")
 	}
 
-	var buf bytes.Buffer
-	if err := rlp.Encode(&buf, event); err != nil {
-		return nil, fmt.Errorf("failed to encode blacklist event: %w", err)
-	}
-
-	return buf.Bytes(), nil
+	return encodeVersionedEventRLP(event, "blacklist event")
 }
 
-// DecodeBlacklistEvent decodes a BlacklistEvent from RLP
+// DecodeBlacklistEvent decodes a BlacklistEvent from versioned or unversioned RLP
 func DecodeBlacklistEvent(data []byte) (*BlacklistEvent, error) {
-	if len(data) == 0 {
-		return nil, fmt.Errorf("data cannot be empty")
-	}
-
 	var event BlacklistEvent
-	if err := rlp.DecodeBytes(data, &event); err != nil {
-		return nil, fmt.Errorf("failed to decode blacklist event: %w", err)
+	if err := decodeVersionedEventRLP(data, &event, "blacklist event"); err != nil {
+		return nil, err
 	}
 
 	return &event, nil
 }
 
 // EncodeValidatorChangeEvent encodes a ValidatorChangeEvent using custom binary format
-// Format: [blockNumber(8)] [txHash(32)] [validator(20)] [actionLen(8)] [action] [hasOldValidator(1)] [oldValidator(20)?] [timestamp(8)]
+// Format: [version(1)] [blockNumber(8)] [txHash(32)] [validator(20)] [actionLen(8)] [action] [hasOldValidator(1)] [oldValidator(20)?] [timestamp(8)]
 func EncodeValidatorChangeEvent(event *ValidatorChangeEvent) ([]byte, error) {
 	if event == nil {
 		return nil, fmt.Errorf("event cannot be nil")
 	}
 
-	totalSize := 8 + 32 + 20 + 8 + len(event.Action) + 1 + 8
+	totalSize := 1 + 8 + 32 + 20 + 8 + len(event.Action) + 1 + 8
 	if event.OldValidator != nil {
 		totalSize += 20
 	}
 
 	buf := make([]byte, totalSize)
-	offset := 0
+	buf[0] = eventEncodingVersion
+	offset := 1
 
 	// Write blockNumber
 	binary.BigEndian.PutUint64(buf[offset:offset+8], event.BlockNumber)
@@ -777,9 +895,23 @@ func EncodeValidatorChangeEvent(event *ValidatorChangeEvent) ([]byte, error) {
 }
 
 // DecodeValidatorChangeEvent decodes a ValidatorChangeEvent from custom binary format
+// Unversioned records start with the block number, whose high byte is zero
 func DecodeValidatorChangeEvent(data []byte) (*ValidatorChangeEvent, error) {
+	if len(data) > 0 && data[0] == eventEncodingV1 {
+		if event, n, err := decodeValidatorChangeEventV1(data[1:]); err == nil && n == len(data)-1 {
+			return event, nil
+		}
+	}
+
+	event, _, err := decodeValidatorChangeEventV1(data)
+	return event, err
+}
+
+// decodeValidatorChangeEventV1 decodes the version 1 layout without its
+// version byte and returns the number of bytes read
+func decodeValidatorChangeEventV1(data []byte) (*ValidatorChangeEvent, int, error) {
 	if len(data) < 8+32+20+8+1+8 {
-		return nil, fmt.Errorf("data too short: %d bytes", len(data))
+		return nil, 0, fmt.Errorf("%w: data too short: %d bytes", ErrCorrupted, len(data))
 	}
 
 	offset := 0
@@ -801,7 +933,7 @@ func DecodeValidatorChangeEvent(data []byte) (*ValidatorChangeEvent, error) {
 	actionLen := binary.BigEndian.Uint64(data[offset : offset+8])
 	offset += 8
 	if offset+int(actionLen) > len(data) {
-		return nil, fmt.Errorf("invalid action length: %d", actionLen)
+		return nil, 0, fmt.Errorf("%w: invalid action length: %d", ErrCorrupted, actionLen)
 	}
 	event.Action = string(data[offset : offset+int(actionLen)])
 	offset += int(actionLen)
@@ -811,7 +943,7 @@ func DecodeValidatorChangeEvent(data []byte) (*ValidatorChangeEvent, error) {
 	offset++
 	if hasOldValidator == 1 {
 		if offset+20 > len(data) {
-			return nil, fmt.Errorf("data too short for oldValidator")
+			return nil, 0, fmt.Errorf("%w: data too short for oldValidator", ErrCorrupted)
 		}
 		var oldValidator common.Address
 		copy(oldValidator[:], data[offset:offset+20])
@@ -821,15 +953,16 @@ func DecodeValidatorChangeEvent(data []byte) (*ValidatorChangeEvent, error) {
 
 	// Read timestamp
 	if offset+8 > len(data) {
-		return nil, fmt.Errorf("data too short for timestamp")
+		return nil, 0, fmt.Errorf("%w: data too short for timestamp", ErrCorrupted)
 	}
 	event.Timestamp = binary.BigEndian.Uint64(data[offset : offset+8])
+	offset += 8
 
-	return event, nil
+	return event, offset, nil
 }
 
 // EncodeMemberChangeEvent encodes a MemberChangeEvent using custom binary format
-// Format: [contract(20)] [blockNumber(8)] [txHash(32)] [member(20)] [actionLen(8)] [action]
+// Format: [version(1)] [contract(20)] [blockNumber(8)] [txHash(32)] [member(20)] [actionLen(8)] [action]
 //
 //	[hasOldMember(1)] [oldMember(20)?] [totalMembers(8)] [newQuorum(4)] [timestamp(8)]
 func EncodeMemberChangeEvent(event *MemberChangeEvent) ([]byte, error) {
@@ -837,13 +970,14 @@ func EncodeMemberChangeEvent(event *MemberChangeEvent) ([]byte, error) {
 		return nil, fmt.Errorf("event cannot be nil")
 	}
 
-	totalSize := 20 + 8 + 32 + 20 + 8 + len(event.Action) + 1 + 8 + 4 + 8
+	totalSize := 1 + 20 + 8 + 32 + 20 + 8 + len(event.Action) + 1 + 8 + 4 + 8
 	if event.OldMember != nil {
 		totalSize += 20
 	}
 
 	buf := make([]byte, totalSize)
-	offset := 0
+	buf[0] = eventEncodingVersion
+	offset := 1
 
 	// Write contract
 	copy(buf[offset:offset+20], event.Contract[:])
@@ -893,9 +1027,24 @@ func EncodeMemberChangeEvent(event *MemberChangeEvent) ([]byte, error) {
 }
 
 // DecodeMemberChangeEvent decodes a MemberChangeEvent from custom binary format
+// Unversioned records start with the contract address, so a leading version
+// byte is only accepted when the rest is exactly one version 1 record
 func DecodeMemberChangeEvent(data []byte) (*MemberChangeEvent, error) {
+	if len(data) > 0 && data[0] == eventEncodingV1 {
+		if event, n, err := decodeMemberChangeEventV1(data[1:]); err == nil && n == len(data)-1 {
+			return event, nil
+		}
+	}
+
+	event, _, err := decodeMemberChangeEventV1(data)
+	return event, err
+}
+
+// decodeMemberChangeEventV1 decodes the version 1 layout without its version
+// byte and returns the number of bytes read
+func decodeMemberChangeEventV1(data []byte) (*MemberChangeEvent, int, error) {
 	if len(data) < 20+8+32+20+8+1+8+4+8 {
-		return nil, fmt.Errorf("data too short: %d bytes", len(data))
+		return nil, 0, fmt.Errorf("%w: data too short: %d bytes", ErrCorrupted, len(data))
 	}
 
 	offset := 0
@@ -921,7 +1070,7 @@ func DecodeMemberChangeEvent(data []byte) (*MemberChangeEvent, error) {
 	actionLen := binary.BigEndian.Uint64(data[offset : offset+8])
 	offset += 8
 	if offset+int(actionLen) > len(data) {
-		return nil, fmt.Errorf("invalid action length: %d", actionLen)
+		return nil, 0, fmt.Errorf("%w: invalid action length: %d", ErrCorrupted, actionLen)
 	}
 	event.Action = string(data[offset : offset+int(actionLen)])
 	offset += int(actionLen)
@@ -931,7 +1080,7 @@ func DecodeMemberChangeEvent(data []byte) (*MemberChangeEvent, error) {
 	offset++
 	if hasOldMember == 1 {
 		if offset+20 > len(data) {
-			return nil, fmt.Errorf("data too short for oldMember")
+			return nil, 0, fmt.Errorf("%w: data too short for oldMember", ErrCorrupted)
 		}
 		var oldMember common.Address
 		copy(oldMember[:], data[offset:offset+20])
@@ -941,50 +1090,42 @@ func DecodeMemberChangeEvent(data []byte) (*MemberChangeEvent, error) {
 
 	// Read totalMembers
 	if offset+8 > len(data) {
-		return nil, fmt.Errorf("data too short for totalMembers")
+		return nil, 0, fmt.Errorf("%w: data too short for totalMembers", ErrCorrupted)
 	}
 	event.TotalMembers = binary.BigEndian.Uint64(data[offset : offset+8])
 	offset += 8
 
 	// Read newQuorum
 	if offset+4 > len(data) {
-		return nil, fmt.Errorf("data too short for newQuorum")
+		return nil, 0, fmt.Errorf("%w: data too short for newQuorum", ErrCorrupted)
 	}
 	event.NewQuorum = binary.BigEndian.Uint32(data[offset : offset+4])
 	offset += 4
 
 	// Read timestamp
 	if offset+8 > len(data) {
-		return nil, fmt.Errorf("data too short for timestamp")
+		return nil, 0, fmt.Errorf("%w: data too short for timestamp", ErrCorrupted)
 	}
 	event.Timestamp = binary.BigEndian.Uint64(data[offset : offset+8])
+	offset += 8
 
-	return event, nil
+	return event, offset, nil
 }
 
-// EncodeEmergencyPauseEvent encodes an EmergencyPauseEvent using RLP
+// EncodeEmergencyPauseEvent encodes an EmergencyPauseEvent using versioned RLP
 func EncodeEmergencyPauseEvent(event *EmergencyPauseEvent) ([]byte, error) {
 	if event == nil {
 		return nil, fmt.Errorf("event cannot be nil")
 	}
 
-	var buf bytes.Buffer
-	if err := rlp.Encode(&buf, event); err != nil {
-		return nil, fmt.Errorf("failed to encode emergency pause event: %w", err)
-	}
-
-	return buf.Bytes(), nil
+	return encodeVersionedEventRLP(event, "emergency pause event")
 }
 
-// DecodeEmergencyPauseEvent decodes an EmergencyPauseEvent from RLP
+// DecodeEmergencyPauseEvent decodes an EmergencyPauseEvent from versioned or unversioned RLP
 func DecodeEmergencyPauseEvent(data []byte) (*EmergencyPauseEvent, error) {
-	if len(data) == 0 {
-		return nil, fmt.Errorf("data cannot be empty")
-	}
-
 	var event EmergencyPauseEvent
-	if err := rlp.DecodeBytes(data, &event); err != nil {
-		return nil, fmt.Errorf("failed to decode emergency pause event: %w", err)
+	if err := decodeVersionedEventRLP(data, &event, "emergency pause event"); err != nil {
+		return nil, err
 	}
 
 	return &event, nil
@@ -998,7 +1139,7 @@ func EncodeDepositMintProposal(proposal *DepositMintProposal) ([]byte, error) {
 
 	var buf bytes.Buffer
 	if err := rlp.Encode(&buf, proposal); err != nil {
-		return nil, fmt.Errorf("failed to encode deposit mint proposal: %w", err)
+		return nil, fmt.Errorf("%w deposit mint proposal: %w", ErrEncodeFailed, err)
 	}
 
 	return buf.Bytes(), nil
@@ -1007,12 +1148,12 @@ func EncodeDepositMintProposal(proposal *DepositMintProposal) ([]byte, error) {
 // DecodeDepositMintProposal decodes a DepositMintProposal from RLP
 func DecodeDepositMintProposal(data []byte) (*DepositMintProposal, error) {
 	if len(data) == 0 {
-		return nil, fmt.Errorf("data cannot be empty")
+		return nil, fmt.Errorf("%w: data cannot be empty", ErrCorrupted)
 	}
 
 	var proposal DepositMintProposal
 	if err := rlp.DecodeBytes(data, &proposal); err != nil {
-		return nil, fmt.Errorf("failed to decode deposit mint proposal: %w", err)
+		return nil, fmt.Errorf("%w deposit mint proposal: %w", ErrDecodeFailed, err)
 	}
 
 	return &proposal, nil
@@ -496,3 +496,90 @@ func TestEncodeDecode_DepositMintProposal(t *testing.T) {
 	_, err = DecodeDepositMintProposal([]byte{})
 	assert.Error(t, err)
 }
+
+// Frozen encodings of the same MintEvent; they must keep decoding as the
+// event layout evolves
+const (
+	mintEventV1Fixture          = "01f85664a000000000000000000000000000000000000000000000000000000000000000aa940000000000000000000000000000000000000001940000000000000000000000000000000000000002821388846553f1000103"
+	mintEventUnversionedFixture = "f85664a000000000000000000000000000000000000000000000000000000000000000aa940000000000000000000000000000000000000001940000000000000000000000000000000000000002821388846553f1000103"
+)
+
+// mintEventV2 is MintEvent with a field added in a hypothetical version 2
+type mintEventV2 struct {
+	BlockNumber uint64
+	TxHash      common.Hash
+	Minter      common.Address
+	To          common.Address
+	Amount      *big.Int
+	Timestamp   uint64
+	TxIndex     uint64 `rlp:"optional"`
+	LogIndex    uint64 `rlp:"optional"`
+	Memo        string `rlp:"optional"`
+}
+
+func TestDecodeMintEvent_VersionedFixtures(t *testing.T) {
+	for name, fixture := range map[string]string{
+		"v1":          mintEventV1Fixture,
+		"unversioned": mintEventUnversionedFixture,
+	} {
+		t.Run(name, func(t *testing.T) {
+			decoded, err := DecodeMintEvent(common.FromHex(fixture))
+			require.NoError(t, err)
+			assert.Equal(t, uint64(100), decoded.BlockNumber)
+			assert.Equal(t, common.HexToHash("0xaa"), decoded.TxHash)
+			assert.Equal(t, common.HexToAddress("0x01"), decoded.Minter)
+			assert.Equal(t, common.HexToAddress("0x02"), decoded.To)
+			assert.Equal(t, big.NewInt(5000), decoded.Amount)
+			assert.Equal(t, uint64(1700000000), decoded.Timestamp)
+			assert.Equal(t, uint64(1), decoded.TxIndex)
+			assert.Equal(t, uint64(3), decoded.LogIndex)
+		})
+	}
+
+	t.Run("v1 fixture with v2 field", func(t *testing.T) {
+		var decoded mintEventV2
+		require.NoError(t, decodeVersionedEventRLP(common.FromHex(mintEventV1Fixture), &decoded, "mint event"))
+		assert.Equal(t, uint64(100), decoded.BlockNumber)
+		assert.Equal(t, big.NewInt(5000), decoded.Amount)
+		assert.Equal(t, uint64(3), decoded.LogIndex)
+		assert.Empty(t, decoded.Memo)
+	})
+
+	t.Run("encodes current version", func(t *testing.T) {
+		decoded, err := DecodeMintEvent(common.FromHex(mintEventV1Fixture))
+		require.NoError(t, err)
+		encoded, err := EncodeMintEvent(decoded)
+		require.NoError(t, err)
+		assert.Equal(t, common.FromHex(mintEventV1Fixture), encoded)
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		data := common.FromHex(mintEventV1Fixture)
+		data[0] = 0x7f
+		_, err := DecodeMintEvent(data)
+		assert.ErrorIs(t, err, ErrCorrupted)
+	})
+}
+
+func TestDecodeMemberChangeEvent_UnversionedFixture(t *testing.T) {
+	// The contract address starts with the v1 version byte
+	const unversioned = "0100000000000000000000000000000000000001000000000000006400000000000000000000000000000000000000000000000000000000000000bb000000000000000000000000000000000000000200000000000000056164646564010300000000000000000000000000000000000003000000000000000400000003000000006553f100"
+
+	for name, fixture := range map[string]string{
+		"v1":          "01" + unversioned,
+		"unversioned": unversioned,
+	} {
+		t.Run(name, func(t *testing.T) {
+			decoded, err := DecodeMemberChangeEvent(common.FromHex(fixture))
+			require.NoError(t, err)
+			assert.Equal(t, common.HexToAddress("0x0100000000000000000000000000000000000001"), decoded.Contract)
+			assert.Equal(t, uint64(100), decoded.BlockNumber)
+			assert.Equal(t, "added", decoded.Action)
+			require.NotNil(t, decoded.OldMember)
+			assert.Equal(t, common.HexToAddress("0x0300000000000000000000000000000000000003"), *decoded.OldMember)
+			assert.Equal(t, uint64(4), decoded.TotalMembers)
+			assert.Equal(t, uint32(3), decoded.NewQuorum)
+			assert.Equal(t, uint64(1700000000), decoded.Timestamp)
+		})
+	}
+}
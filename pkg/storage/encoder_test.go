@@ -2,6 +2,7 @@ package storage
 
 import (
 	"bytes"
+	"errors"
 	"math/big"
 	"testing"
 
@@ -203,6 +204,27 @@ func TestEncodeDecodeTransaction(t *testing.T) {
 	}
 }
 
+func TestEncodeDecodeTransaction_Blob(t *testing.T) {
+	tx := signedBlobTestTx(t)
+
+	encoded, err := EncodeTransaction(tx)
+	if err != nil {
+		t.Fatalf("EncodeTransaction() error = %v", err)
+	}
+	decoded, err := DecodeTransaction(encoded)
+	if err != nil {
+		t.Fatalf("DecodeTransaction() error = %v", err)
+	}
+
+	assertBlobTxEqual(t, decoded, tx)
+	if decoded.BlobTxSidecar() != nil {
+		t.Error("decoded transaction has a sidecar, want it dropped")
+	}
+	if tx.BlobTxSidecar() == nil {
+		t.Error("EncodeTransaction() removed the caller's sidecar")
+	}
+}
+
 func TestEncodeDecodeReceipt(t *testing.T) {
 	// Create a sample receipt
 	// Note: TxHash, BlockHash, BlockNumber, TransactionIndex are not RLP-encoded
@@ -439,3 +461,26 @@ func TestDecodeTxLocation_InvalidData(t *testing.T) {
 		})
 	}
 }
+
+func TestDecode_ErrorSentinels(t *testing.T) {
+	tests := []struct {
+		name string
+		call func() error
+		want error
+	}{
+		{"block garbage", func() error { _, err := DecodeBlock([]byte{0xff, 0xff}); return err }, ErrDecodeFailed},
+		{"block empty", func() error { _, err := DecodeBlock(nil); return err }, ErrCorrupted},
+		{"receipt garbage", func() error { _, err := DecodeReceipt([]byte("garbage")); return err }, ErrDecodeFailed},
+		{"location empty", func() error { _, err := DecodeTxLocation([]byte{}); return err }, ErrCorrupted},
+		{"uint64 short", func() error { _, err := DecodeUint64([]byte{0x01}); return err }, ErrCorrupted},
+		{"balance snapshot short", func() error { _, err := DecodeBalanceSnapshot([]byte{0x01}); return err }, ErrCorrupted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// EffectiveGasPrice returns the gas price a transaction actually paid: the
+// receipt's effective gas price when set, otherwise min(fee cap, base fee +
+// tip cap) for blocks with a base fee, and the gas price for older blocks.
+// Legacy and access-list transactions report their gas price as both caps,
+// so they resolve to the gas price either way.
+func EffectiveGasPrice(tx *types.Transaction, receipt *types.Receipt, baseFee *big.Int) *big.Int {
+	if receipt != nil && receipt.EffectiveGasPrice != nil && receipt.EffectiveGasPrice.Sign() > 0 {
+		return receipt.EffectiveGasPrice
+	}
+	if baseFee != nil && tx.GasFeeCap() != nil && tx.GasTipCap() != nil {
+		price := new(big.Int).Add(baseFee, tx.GasTipCap())
+		if price.Cmp(tx.GasFeeCap()) > 0 {
+			price.Set(tx.GasFeeCap())
+		}
+		return price
+	}
+	if gasPrice := tx.GasPrice(); gasPrice != nil {
+		return gasPrice
+	}
+	return big.NewInt(0)
+}
+
+// TransactionFee returns the fee paid for gasUsed at effectiveGasPrice
+func TransactionFee(effectiveGasPrice *big.Int, gasUsed uint64) *big.Int {
+	if effectiveGasPrice == nil {
+		return big.NewInt(0)
+	}
+	return new(big.Int).Mul(effectiveGasPrice, new(big.Int).SetUint64(gasUsed))
+}
+
+// FillReceiptFee restores the gas used and effective gas price of a stored
+// receipt, which its RLP encoding drops, and returns the transaction fee.
+// Gas used is derived from the cumulative gas of the previous receipt in the
+// block; the effective gas price uses the block's base fee.
+func FillReceiptFee(ctx context.Context, r Reader, receipt *types.Receipt) (*big.Int, error) {
+	tx, location, err := r.GetTransaction(ctx, receipt.TxHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+	block, err := r.GetBlock(ctx, location.BlockHeight)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block %d: %w", location.BlockHeight, err)
+	}
+
+	if receipt.GasUsed == 0 {
+		receipt.GasUsed = receipt.CumulativeGasUsed
+		txs := block.Transactions()
+		if location.TxIndex > 0 && location.TxIndex <= uint64(len(txs)) {
+			prev, err := r.GetReceipt(ctx, txs[location.TxIndex-1].Hash())
+			if err != nil {
+				return nil, fmt.Errorf("failed to get previous receipt: %w", err)
+			}
+			receipt.GasUsed = receipt.CumulativeGasUsed - prev.CumulativeGasUsed
+		}
+	}
+
+	receipt.EffectiveGasPrice = EffectiveGasPrice(tx, receipt, block.BaseFee())
+	return TransactionFee(receipt.EffectiveGasPrice, receipt.GasUsed), nil
+}
@@ -0,0 +1,156 @@
+package storage
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var feeTestRecipient = common.HexToAddress("0x3333333333333333333333333333333333333333")
+
+func feeTestTransactions() (legacy, accessList, dynamic *types.Transaction) {
+	legacy = types.NewTx(&types.LegacyTx{
+		Nonce:    0,
+		To:       &feeTestRecipient,
+		Gas:      21000,
+		GasPrice: big.NewInt(30),
+	})
+	accessList = types.NewTx(&types.AccessListTx{
+		ChainID:  big.NewInt(1337),
+		Nonce:    1,
+		To:       &feeTestRecipient,
+		Gas:      30000,
+		GasPrice: big.NewInt(25),
+	})
+	dynamic = types.NewTx(&types.DynamicFeeTx{
+		ChainID:   big.NewInt(1337),
+		Nonce:     2,
+		To:        &feeTestRecipient,
+		Gas:       50000,
+		GasTipCap: big.NewInt(2),
+		GasFeeCap: big.NewInt(40),
+	})
+	return legacy, accessList, dynamic
+}
+
+func TestEffectiveGasPrice(t *testing.T) {
+	legacy, accessList, dynamic := feeTestTransactions()
+	cappedDynamic := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   big.NewInt(1337),
+		To:        &feeTestRecipient,
+		Gas:       50000,
+		GasTipCap: big.NewInt(5),
+		GasFeeCap: big.NewInt(12),
+	})
+
+	tests := []struct {
+		name    string
+		tx      *types.Transaction
+		receipt *types.Receipt
+		baseFee *big.Int
+		want    int64
+	}{
+		{name: "legacy before London", tx: legacy, want: 30},
+		{name: "legacy with base fee", tx: legacy, baseFee: big.NewInt(10), want: 30},
+		{name: "access list with base fee", tx: accessList, baseFee: big.NewInt(10), want: 25},
+		{name: "dynamic fee pays base fee plus tip", tx: dynamic, baseFee: big.NewInt(10), want: 12},
+		{name: "dynamic fee capped at fee cap", tx: cappedDynamic, baseFee: big.NewInt(10), want: 12},
+		{
+			name:    "receipt value wins",
+			tx:      dynamic,
+			receipt: &types.Receipt{EffectiveGasPrice: big.NewInt(17)},
+			baseFee: big.NewInt(10),
+			want:    17,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EffectiveGasPrice(tt.tx, tt.receipt, tt.baseFee)
+			if got.Cmp(big.NewInt(tt.want)) != 0 {
+				t.Errorf("EffectiveGasPrice() = %s, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransactionFee(t *testing.T) {
+	if got := TransactionFee(big.NewInt(12), 21000); got.Cmp(big.NewInt(252000)) != 0 {
+		t.Errorf("TransactionFee(12, 21000) = %s, want 252000", got)
+	}
+	if got := TransactionFee(nil, 21000); got.Sign() != 0 {
+		t.Errorf("TransactionFee(nil, 21000) = %s, want 0", got)
+	}
+}
+
+func TestFillReceiptFee(t *testing.T) {
+	ctx := context.Background()
+	store := setupTestPebbleStorage(t)
+
+	legacy, accessList, dynamic := feeTestTransactions()
+	txs := []*types.Transaction{legacy, accessList, dynamic}
+	header := &types.Header{
+		Number:   big.NewInt(1),
+		GasLimit: 8000000,
+		GasUsed:  21000 + 26000 + 40000,
+		BaseFee:  big.NewInt(10),
+	}
+	block := types.NewBlockWithHeader(header).WithBody(types.Body{Transactions: txs})
+
+	cumulative := []uint64{21000, 47000, 87000}
+	receipts := make([]*types.Receipt, len(txs))
+	for i, tx := range txs {
+		receipts[i] = &types.Receipt{
+			Type:              tx.Type(),
+			Status:            types.ReceiptStatusSuccessful,
+			CumulativeGasUsed: cumulative[i],
+			TxHash:            tx.Hash(),
+			BlockHash:         block.Hash(),
+			BlockNumber:       block.Number(),
+			TransactionIndex:  uint(i),
+			Logs:              []*types.Log{},
+		}
+	}
+	if err := store.SetBlockWithReceipts(ctx, block, receipts); err != nil {
+		t.Fatalf("SetBlockWithReceipts() error = %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		tx          *types.Transaction
+		wantGasUsed uint64
+		wantPrice   int64
+		wantFee     int64
+	}{
+		{name: "legacy", tx: legacy, wantGasUsed: 21000, wantPrice: 30, wantFee: 630000},
+		{name: "access list", tx: accessList, wantGasUsed: 26000, wantPrice: 25, wantFee: 650000},
+		{name: "dynamic fee", tx: dynamic, wantGasUsed: 40000, wantPrice: 12, wantFee: 480000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Stored receipts come back without gas used or effective gas price
+			receipt, err := store.GetReceipt(ctx, tt.tx.Hash())
+			if err != nil {
+				t.Fatalf("GetReceipt() error = %v", err)
+			}
+
+			fee, err := FillReceiptFee(ctx, store, receipt)
+			if err != nil {
+				t.Fatalf("FillReceiptFee() error = %v", err)
+			}
+			if receipt.GasUsed != tt.wantGasUsed {
+				t.Errorf("GasUsed = %d, want %d", receipt.GasUsed, tt.wantGasUsed)
+			}
+			if receipt.EffectiveGasPrice.Cmp(big.NewInt(tt.wantPrice)) != 0 {
+				t.Errorf("EffectiveGasPrice = %s, want %d", receipt.EffectiveGasPrice, tt.wantPrice)
+			}
+			if fee.Cmp(big.NewInt(tt.wantFee)) != 0 {
+				t.Errorf("fee = %s, want %d", fee, tt.wantFee)
+			}
+		})
+	}
+}
@@ -177,12 +177,12 @@ func (g *GenesisInitializingStorage) SetBalance(ctx context.Context, addr common
 
 // Delegate all other HistoricalReader methods to underlying storage
 
-func (g *GenesisInitializingStorage) GetBlocksByTimeRange(ctx context.Context, fromTime, toTime uint64, limit, offset int) ([]*types.Block, error) {
+func (g *GenesisInitializingStorage) GetBlocksByTimeRange(ctx context.Context, fromTime, toTime uint64, limit, offset int, order SortOrder) ([]*types.Block, error) {
 	histReader, ok := g.Storage.(HistoricalReader)
 	if !ok {
 		return nil, fmt.Errorf("storage does not implement HistoricalReader")
 	}
-	return histReader.GetBlocksByTimeRange(ctx, fromTime, toTime, limit, offset)
+	return histReader.GetBlocksByTimeRange(ctx, fromTime, toTime, limit, offset, order)
 }
 
 func (g *GenesisInitializingStorage) GetBlockByTimestamp(ctx context.Context, timestamp uint64) (*types.Block, error) {
@@ -225,6 +225,14 @@ func (g *GenesisInitializingStorage) GetTopMiners(ctx context.Context, limit int
 	return histReader.GetTopMiners(ctx, limit, fromBlock, toBlock)
 }
 
+func (g *GenesisInitializingStorage) GetTopMinersWithOptions(ctx context.Context, limit int, fromBlock, toBlock uint64, opts MinerStatsOptions) ([]MinerStats, error) {
+	minerReader, ok := g.Storage.(MinerStatsReader)
+	if !ok {
+		return nil, fmt.Errorf("storage does not implement MinerStatsReader")
+	}
+	return minerReader.GetTopMinersWithOptions(ctx, limit, fromBlock, toBlock, opts)
+}
+
 func (g *GenesisInitializingStorage) GetTokenBalances(ctx context.Context, addr common.Address, tokenType string) ([]TokenBalance, error) {
 	histReader, ok := g.Storage.(HistoricalReader)
 	if !ok {
@@ -423,3 +431,493 @@ func (g *GenesisInitializingStorage) SaveERC721Transfer(ctx context.Context, tra
 	}
 	return fmt.Errorf("storage does not implement AddressIndexWriter")
 }
+
+// ============================================================================
+// InternalTransferReader / InternalTransferWriter interface delegation
+// ============================================================================
+
+func (g *GenesisInitializingStorage) GetInternalTransfers(ctx context.Context, addr common.Address, limit, offset int) ([]*InternalTransaction, error) {
+	if reader, ok := g.Storage.(InternalTransferReader); ok {
+		return reader.GetInternalTransfers(ctx, addr, limit, offset)
+	}
+	return nil, fmt.Errorf("storage does not implement InternalTransferReader")
+}
+
+func (g *GenesisInitializingStorage) SaveInternalTransfers(ctx context.Context, transfers []*InternalTransaction) error {
+	if writer, ok := g.Storage.(InternalTransferWriter); ok {
+		return writer.SaveInternalTransfers(ctx, transfers)
+	}
+	return fmt.Errorf("storage does not implement InternalTransferWriter")
+}
+
+// ============================================================================
+// ReceiptRangeReader interface delegation
+// ============================================================================
+
+func (g *GenesisInitializingStorage) GetReceiptsByBlockRange(ctx context.Context, from, to uint64, limit, offset int) ([]*types.Receipt, error) {
+	if reader, ok := g.Storage.(ReceiptRangeReader); ok {
+		return reader.GetReceiptsByBlockRange(ctx, from, to, limit, offset)
+	}
+	return nil, fmt.Errorf("storage does not implement ReceiptRangeReader")
+}
+
+// ============================================================================
+// ChainIDReader / ChainIDWriter interface delegation
+// These methods delegate to the underlying storage if it implements the chain ID interfaces.
+// ============================================================================
+
+func (g *GenesisInitializingStorage) GetChainID(ctx context.Context) (*big.Int, error) {
+	if reader, ok := g.Storage.(ChainIDReader); ok {
+		return reader.GetChainID(ctx)
+	}
+	return nil, fmt.Errorf("storage does not implement ChainIDReader")
+}
+
+func (g *GenesisInitializingStorage) SetChainID(ctx context.Context, chainID *big.Int) error {
+	if writer, ok := g.Storage.(ChainIDWriter); ok {
+		return writer.SetChainID(ctx, chainID)
+	}
+	return fmt.Errorf("storage does not implement ChainIDWriter")
+}
+
+// ============================================================================
+// ChainHeadReader / ChainHeadWriter interface delegation
+// ============================================================================
+
+func (g *GenesisInitializingStorage) GetChainHead(ctx context.Context) (uint64, error) {
+	if reader, ok := g.Storage.(ChainHeadReader); ok {
+		return reader.GetChainHead(ctx)
+	}
+	return 0, fmt.Errorf("storage does not implement ChainHeadReader")
+}
+
+func (g *GenesisInitializingStorage) SetChainHead(ctx context.Context, height uint64) error {
+	if writer, ok := g.Storage.(ChainHeadWriter); ok {
+		return writer.SetChainHead(ctx, height)
+	}
+	return fmt.Errorf("storage does not implement ChainHeadWriter")
+}
+
+// ============================================================================
+// FailedBlockReader / FailedBlockWriter interface delegation
+// ============================================================================
+
+func (g *GenesisInitializingStorage) GetFailedBlock(ctx context.Context, height uint64) (*FailedBlock, error) {
+	if reader, ok := g.Storage.(FailedBlockReader); ok {
+		return reader.GetFailedBlock(ctx, height)
+	}
+	return nil, fmt.Errorf("storage does not implement FailedBlockReader")
+}
+
+func (g *GenesisInitializingStorage) GetFailedBlocks(ctx context.Context, limit int) ([]*FailedBlock, error) {
+	if reader, ok := g.Storage.(FailedBlockReader); ok {
+		return reader.GetFailedBlocks(ctx, limit)
+	}
+	return nil, fmt.Errorf("storage does not implement FailedBlockReader")
+}
+
+func (g *GenesisInitializingStorage) SetFailedBlock(ctx context.Context, record *FailedBlock) error {
+	if writer, ok := g.Storage.(FailedBlockWriter); ok {
+		return writer.SetFailedBlock(ctx, record)
+	}
+	return fmt.Errorf("storage does not implement FailedBlockWriter")
+}
+
+func (g *GenesisInitializingStorage) DeleteFailedBlock(ctx context.Context, height uint64) error {
+	if writer, ok := g.Storage.(FailedBlockWriter); ok {
+		return writer.DeleteFailedBlock(ctx, height)
+	}
+	return fmt.Errorf("storage does not implement FailedBlockWriter")
+}
+
+// ============================================================================
+// ChainRollbacker / OrphanBlockReader interface delegation
+// ============================================================================
+
+func (g *GenesisInitializingStorage) RollbackToHeight(ctx context.Context, height uint64) error {
+	if rollbacker, ok := g.Storage.(ChainRollbacker); ok {
+		return rollbacker.RollbackToHeight(ctx, height)
+	}
+	return fmt.Errorf("storage does not implement ChainRollbacker")
+}
+
+func (g *GenesisInitializingStorage) GetOrphanBlock(ctx context.Context, hash common.Hash) (*OrphanBlock, error) {
+	if reader, ok := g.Storage.(OrphanBlockReader); ok {
+		return reader.GetOrphanBlock(ctx, hash)
+	}
+	return nil, fmt.Errorf("storage does not implement OrphanBlockReader")
+}
+
+func (g *GenesisInitializingStorage) GetOrphanBlocks(ctx context.Context, limit, offset int) ([]*OrphanBlock, error) {
+	if reader, ok := g.Storage.(OrphanBlockReader); ok {
+		return reader.GetOrphanBlocks(ctx, limit, offset)
+	}
+	return nil, fmt.Errorf("storage does not implement OrphanBlockReader")
+}
+
+// ============================================================================
+// RevertReasonReader / RevertReasonWriter interface delegation
+// ============================================================================
+
+func (g *GenesisInitializingStorage) SetRevertReason(ctx context.Context, reason *RevertReason) error {
+	if writer, ok := g.Storage.(RevertReasonWriter); ok {
+		return writer.SetRevertReason(ctx, reason)
+	}
+	return fmt.Errorf("storage does not implement RevertReasonWriter")
+}
+
+func (g *GenesisInitializingStorage) GetRevertReason(ctx context.Context, txHash common.Hash) (*RevertReason, error) {
+	if reader, ok := g.Storage.(RevertReasonReader); ok {
+		return reader.GetRevertReason(ctx, txHash)
+	}
+	return nil, fmt.Errorf("storage does not implement RevertReasonReader")
+}
+
+// ============================================================================
+// BulkStreamer interface delegation
+// ============================================================================
+
+func (g *GenesisInitializingStorage) StreamBlocks(ctx context.Context, from, to uint64, fn func(*types.Block) bool) error {
+	if streamer, ok := g.Storage.(BulkStreamer); ok {
+		return streamer.StreamBlocks(ctx, from, to, fn)
+	}
+	return fmt.Errorf("storage does not implement BulkStreamer")
+}
+
+func (g *GenesisInitializingStorage) StreamTransactions(ctx context.Context, from, to uint64, fn func(*types.Transaction, *TxLocation) bool) error {
+	if streamer, ok := g.Storage.(BulkStreamer); ok {
+		return streamer.StreamTransactions(ctx, from, to, fn)
+	}
+	return fmt.Errorf("storage does not implement BulkStreamer")
+}
+
+// ============================================================================
+// LatestBlocksReader interface delegation
+// ============================================================================
+
+func (g *GenesisInitializingStorage) GetLatestBlocks(ctx context.Context, n int) ([]*types.Block, error) {
+	if reader, ok := g.Storage.(LatestBlocksReader); ok {
+		return reader.GetLatestBlocks(ctx, n)
+	}
+	return LatestBlocks(ctx, g.Storage, n)
+}
+
+// ============================================================================
+// UncleReader interface delegation
+// ============================================================================
+
+func (g *GenesisInitializingStorage) GetUncles(ctx context.Context, height uint64) ([]*types.Header, error) {
+	if reader, ok := g.Storage.(UncleReader); ok {
+		return reader.GetUncles(ctx, height)
+	}
+	return nil, fmt.Errorf("storage does not implement UncleReader")
+}
+
+// ============================================================================
+// BlockRangeReader interface delegation
+// ============================================================================
+
+func (g *GenesisInitializingStorage) GetBlocksWithMissing(ctx context.Context, startHeight, endHeight uint64) ([]*types.Block, []uint64, error) {
+	if reader, ok := g.Storage.(BlockRangeReader); ok {
+		return reader.GetBlocksWithMissing(ctx, startHeight, endHeight)
+	}
+	return BlocksWithMissing(ctx, g.Storage, startHeight, endHeight)
+}
+
+// ============================================================================
+// TransactionContextReader interface delegation
+// ============================================================================
+
+func (g *GenesisInitializingStorage) GetTransactionContext(ctx context.Context, hash common.Hash) (*TransactionContext, error) {
+	if reader, ok := g.Storage.(TransactionContextReader); ok {
+		return reader.GetTransactionContext(ctx, hash)
+	}
+	return LookupTransactionContext(ctx, g.Storage, hash)
+}
+
+// ============================================================================
+// BlockHeaderReader interface delegation
+// ============================================================================
+
+func (g *GenesisInitializingStorage) GetBlockHeader(ctx context.Context, height uint64) (*types.Header, error) {
+	if reader, ok := g.Storage.(BlockHeaderReader); ok {
+		return reader.GetBlockHeader(ctx, height)
+	}
+	// Every storage can serve a header from the full block
+	block, err := g.Storage.GetBlock(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	return block.Header(), nil
+}
+
+// ============================================================================
+// AddressStatsWriter interface delegation
+// ============================================================================
+
+func (g *GenesisInitializingStorage) IndexAddressStats(ctx context.Context, block *types.Block, receipts types.Receipts) error {
+	if writer, ok := g.Storage.(AddressStatsWriter); ok {
+		return writer.IndexAddressStats(ctx, block, receipts)
+	}
+	return fmt.Errorf("storage does not implement AddressStatsWriter")
+}
+
+func (g *GenesisInitializingStorage) IndexRepairedAddressStats(ctx context.Context, block *types.Block, receipts types.Receipts) error {
+	if writer, ok := g.Storage.(AddressStatsWriter); ok {
+		return writer.IndexRepairedAddressStats(ctx, block, receipts)
+	}
+	return fmt.Errorf("storage does not implement AddressStatsWriter")
+}
+
+// ============================================================================
+// AddressTransactionCounter interface delegation
+// ============================================================================
+
+func (g *GenesisInitializingStorage) CountTransactionsByAddress(ctx context.Context, addr common.Address) (uint64, error) {
+	if counter, ok := g.Storage.(AddressTransactionCounter); ok {
+		return counter.CountTransactionsByAddress(ctx, addr)
+	}
+	return 0, fmt.Errorf("storage does not implement AddressTransactionCounter")
+}
+
+// ============================================================================
+// AddressActivityReader interface delegation
+// ============================================================================
+
+func (g *GenesisInitializingStorage) GetAddressActivityRange(ctx context.Context, addr common.Address) (uint64, uint64, error) {
+	if reader, ok := g.Storage.(AddressActivityReader); ok {
+		return reader.GetAddressActivityRange(ctx, addr)
+	}
+	return 0, 0, fmt.Errorf("storage does not implement AddressActivityReader")
+}
+
+// ============================================================================
+// TransactionRangeReader interface delegation
+// ============================================================================
+
+func (g *GenesisInitializingStorage) GetTransactionsByBlockRange(ctx context.Context, from, to uint64, filter *TransactionFilter, limit, offset int) ([]*TransactionWithReceipt, error) {
+	if reader, ok := g.Storage.(TransactionRangeReader); ok {
+		return reader.GetTransactionsByBlockRange(ctx, from, to, filter, limit, offset)
+	}
+	return nil, fmt.Errorf("storage does not implement TransactionRangeReader")
+}
+
+// ============================================================================
+// ProposalTallyReader interface delegation
+// ============================================================================
+
+func (g *GenesisInitializingStorage) GetProposalTally(ctx context.Context, contract common.Address, proposalID *big.Int) (*ProposalTally, error) {
+	if reader, ok := g.Storage.(ProposalTallyReader); ok {
+		return reader.GetProposalTally(ctx, contract, proposalID)
+	}
+	return nil, fmt.Errorf("storage does not implement ProposalTallyReader")
+}
+
+// ============================================================================
+// StateSnapshotWriter interface delegation
+// ============================================================================
+
+func (g *GenesisInitializingStorage) ApplyStateSnapshot(ctx context.Context, snapshot *StateSnapshot) error {
+	if writer, ok := g.Storage.(StateSnapshotWriter); ok {
+		return writer.ApplyStateSnapshot(ctx, snapshot)
+	}
+	return fmt.Errorf("storage does not implement StateSnapshotWriter")
+}
+
+func (g *GenesisInitializingStorage) GetStateSnapshotHeight(ctx context.Context) (uint64, error) {
+	if writer, ok := g.Storage.(StateSnapshotWriter); ok {
+		return writer.GetStateSnapshotHeight(ctx)
+	}
+	return 0, fmt.Errorf("storage does not implement StateSnapshotWriter")
+}
+
+// ============================================================================
+// Syncer interface delegation
+// ============================================================================
+
+func (g *GenesisInitializingStorage) Sync() error {
+	if syncer, ok := g.Storage.(Syncer); ok {
+		return syncer.Sync()
+	}
+	return fmt.Errorf("storage does not implement Syncer")
+}
+
+// ============================================================================
+// StatsReader interface delegation
+// ============================================================================
+
+func (g *GenesisInitializingStorage) GetStats(ctx context.Context) (*Stats, error) {
+	if reader, ok := g.Storage.(StatsReader); ok {
+		return reader.GetStats(ctx)
+	}
+	return nil, fmt.Errorf("storage does not implement StatsReader")
+}
+
+// ============================================================================
+// IndexedLogReader interface delegation
+// ============================================================================
+
+func (g *GenesisInitializingStorage) GetLogsIndexed(ctx context.Context, address common.Address, topic0 common.Hash, fromBlock, toBlock uint64, limit, offset int) ([]*types.Log, error) {
+	if reader, ok := g.Storage.(IndexedLogReader); ok {
+		return reader.GetLogsIndexed(ctx, address, topic0, fromBlock, toBlock, limit, offset)
+	}
+	return nil, fmt.Errorf("storage does not implement IndexedLogReader")
+}
+
+// ============================================================================
+// SystemContractWriter / SystemContractStateResetter interface delegation
+// ============================================================================
+
+func (g *GenesisInitializingStorage) IndexSystemContractEvent(ctx context.Context, log *types.Log) error {
+	if writer, ok := g.Storage.(SystemContractWriter); ok {
+		return writer.IndexSystemContractEvent(ctx, log)
+	}
+	return fmt.Errorf("storage does not implement SystemContractWriter")
+}
+
+func (g *GenesisInitializingStorage) IndexSystemContractEvents(ctx context.Context, logs []*types.Log) error {
+	if writer, ok := g.Storage.(SystemContractWriter); ok {
+		return writer.IndexSystemContractEvents(ctx, logs)
+	}
+	return fmt.Errorf("storage does not implement SystemContractWriter")
+}
+
+func (g *GenesisInitializingStorage) StoreMintEvent(ctx context.Context, event *MintEvent) error {
+	if writer, ok := g.Storage.(SystemContractWriter); ok {
+		return writer.StoreMintEvent(ctx, event)
+	}
+	return fmt.Errorf("storage does not implement SystemContractWriter")
+}
+
+func (g *GenesisInitializingStorage) StoreBurnEvent(ctx context.Context, event *BurnEvent) error {
+	if writer, ok := g.Storage.(SystemContractWriter); ok {
+		return writer.StoreBurnEvent(ctx, event)
+	}
+	return fmt.Errorf("storage does not implement SystemContractWriter")
+}
+
+func (g *GenesisInitializingStorage) StoreMinterConfigEvent(ctx context.Context, event *MinterConfigEvent) error {
+	if writer, ok := g.Storage.(SystemContractWriter); ok {
+		return writer.StoreMinterConfigEvent(ctx, event)
+	}
+	return fmt.Errorf("storage does not implement SystemContractWriter")
+}
+
+func (g *GenesisInitializingStorage) StoreProposal(ctx context.Context, proposal *Proposal) error {
+	if writer, ok := g.Storage.(SystemContractWriter); ok {
+		return writer.StoreProposal(ctx, proposal)
+	}
+	return fmt.Errorf("storage does not implement SystemContractWriter")
+}
+
+func (g *GenesisInitializingStorage) UpdateProposalStatus(ctx context.Context, contract common.Address, proposalID *big.Int, status ProposalStatus, executedAt uint64) error {
+	if writer, ok := g.Storage.(SystemContractWriter); ok {
+		return writer.UpdateProposalStatus(ctx, contract, proposalID, status, executedAt)
+	}
+	return fmt.Errorf("storage does not implement SystemContractWriter")
+}
+
+func (g *GenesisInitializingStorage) StoreProposalVote(ctx context.Context, vote *ProposalVote) error {
+	if writer, ok := g.Storage.(SystemContractWriter); ok {
+		return writer.StoreProposalVote(ctx, vote)
+	}
+	return fmt.Errorf("storage does not implement SystemContractWriter")
+}
+
+func (g *GenesisInitializingStorage) StoreGasTipUpdateEvent(ctx context.Context, event *GasTipUpdateEvent) error {
+	if writer, ok := g.Storage.(SystemContractWriter); ok {
+		return writer.StoreGasTipUpdateEvent(ctx, event)
+	}
+	return fmt.Errorf("storage does not implement SystemContractWriter")
+}
+
+func (g *GenesisInitializingStorage) StoreBlacklistEvent(ctx context.Context, event *BlacklistEvent) error {
+	if writer, ok := g.Storage.(SystemContractWriter); ok {
+		return writer.StoreBlacklistEvent(ctx, event)
+	}
+	return fmt.Errorf("storage does not implement SystemContractWriter")
+}
+
+func (g *GenesisInitializingStorage) StoreValidatorChangeEvent(ctx context.Context, event *ValidatorChangeEvent) error {
+	if writer, ok := g.Storage.(SystemContractWriter); ok {
+		return writer.StoreValidatorChangeEvent(ctx, event)
+	}
+	return fmt.Errorf("storage does not implement SystemContractWriter")
+}
+
+func (g *GenesisInitializingStorage) StoreMemberChangeEvent(ctx context.Context, event *MemberChangeEvent) error {
+	if writer, ok := g.Storage.(SystemContractWriter); ok {
+		return writer.StoreMemberChangeEvent(ctx, event)
+	}
+	return fmt.Errorf("storage does not implement SystemContractWriter")
+}
+
+func (g *GenesisInitializingStorage) StoreEmergencyPauseEvent(ctx context.Context, event *EmergencyPauseEvent) error {
+	if writer, ok := g.Storage.(SystemContractWriter); ok {
+		return writer.StoreEmergencyPauseEvent(ctx, event)
+	}
+	return fmt.Errorf("storage does not implement SystemContractWriter")
+}
+
+func (g *GenesisInitializingStorage) StoreDepositMintProposal(ctx context.Context, proposal *DepositMintProposal) error {
+	if writer, ok := g.Storage.(SystemContractWriter); ok {
+		return writer.StoreDepositMintProposal(ctx, proposal)
+	}
+	return fmt.Errorf("storage does not implement SystemContractWriter")
+}
+
+func (g *GenesisInitializingStorage) StoreMaxProposalsUpdateEvent(ctx context.Context, event *MaxProposalsUpdateEvent) error {
+	if writer, ok := g.Storage.(SystemContractWriter); ok {
+		return writer.StoreMaxProposalsUpdateEvent(ctx, event)
+	}
+	return fmt.Errorf("storage does not implement SystemContractWriter")
+}
+
+func (g *GenesisInitializingStorage) StoreProposalExecutionSkippedEvent(ctx context.Context, event *ProposalExecutionSkippedEvent) error {
+	if writer, ok := g.Storage.(SystemContractWriter); ok {
+		return writer.StoreProposalExecutionSkippedEvent(ctx, event)
+	}
+	return fmt.Errorf("storage does not implement SystemContractWriter")
+}
+
+func (g *GenesisInitializingStorage) StoreAuthorizedAccountEvent(ctx context.Context, event *AuthorizedAccountEvent) error {
+	if writer, ok := g.Storage.(SystemContractWriter); ok {
+		return writer.StoreAuthorizedAccountEvent(ctx, event)
+	}
+	return fmt.Errorf("storage does not implement SystemContractWriter")
+}
+
+func (g *GenesisInitializingStorage) UpdateTotalSupply(ctx context.Context, delta *big.Int) error {
+	if writer, ok := g.Storage.(SystemContractWriter); ok {
+		return writer.UpdateTotalSupply(ctx, delta)
+	}
+	return fmt.Errorf("storage does not implement SystemContractWriter")
+}
+
+func (g *GenesisInitializingStorage) UpdateActiveMinter(ctx context.Context, minter common.Address, allowance *big.Int, active bool) error {
+	if writer, ok := g.Storage.(SystemContractWriter); ok {
+		return writer.UpdateActiveMinter(ctx, minter, allowance, active)
+	}
+	return fmt.Errorf("storage does not implement SystemContractWriter")
+}
+
+func (g *GenesisInitializingStorage) UpdateActiveValidator(ctx context.Context, validator common.Address, active bool) error {
+	if writer, ok := g.Storage.(SystemContractWriter); ok {
+		return writer.UpdateActiveValidator(ctx, validator, active)
+	}
+	return fmt.Errorf("storage does not implement SystemContractWriter")
+}
+
+func (g *GenesisInitializingStorage) UpdateBlacklistStatus(ctx context.Context, address common.Address, blacklisted bool) error {
+	if writer, ok := g.Storage.(SystemContractWriter); ok {
+		return writer.UpdateBlacklistStatus(ctx, address, blacklisted)
+	}
+	return fmt.Errorf("storage does not implement SystemContractWriter")
+}
+
+func (g *GenesisInitializingStorage) ResetSystemContractState(ctx context.Context) error {
+	if writer, ok := g.Storage.(SystemContractStateResetter); ok {
+		return writer.ResetSystemContractState(ctx)
+	}
+	return fmt.Errorf("storage does not implement SystemContractStateResetter")
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"slices"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -22,6 +23,29 @@ const (
 	TxTypeReceived
 )
 
+// SortOrder is the order of results returned by range queries
+type SortOrder int
+
+const (
+	// SortAscending returns the oldest results first
+	SortAscending SortOrder = iota
+	// SortDescending returns the newest results first
+	SortDescending
+)
+
+// ParseSortOrder parses "asc" or "desc" (case-insensitive). An empty string
+// is ascending.
+func ParseSortOrder(s string) (SortOrder, error) {
+	switch strings.ToLower(s) {
+	case "", "asc":
+		return SortAscending, nil
+	case "desc":
+		return SortDescending, nil
+	default:
+		return SortAscending, fmt.Errorf("invalid sort order %q (expected asc or desc)", s)
+	}
+}
+
 // TransactionFilter provides filtering options for transaction queries
 type TransactionFilter struct {
 	// FromBlock is the starting block number (inclusive)
@@ -34,6 +58,9 @@ type TransactionFilter struct {
 	MaxValue *big.Int
 	// TxType filters by transaction direction
 	TxType TransactionType
+	// TransactionTypes filters by EIP-2718 transaction type, e.g.
+	// types.BlobTxType (empty = all types)
+	TransactionTypes []uint8
 	// SuccessOnly filters for successful transactions only
 	SuccessOnly bool
 	// IsFeeDelegated filters by fee delegation status (nil=ignore, true=fee delegated only, false=non-fee delegated only)
@@ -194,12 +221,27 @@ type AddressStats struct {
 	FirstTransactionTimestamp uint64
 	// LastTransactionTimestamp is the timestamp of the last transaction (0 if none)
 	LastTransactionTimestamp uint64
+	// FirstSeenBlock is the block of the first transaction (0 if none)
+	FirstSeenBlock uint64
+	// LastSeenBlock is the block of the last transaction (0 if none)
+	LastSeenBlock uint64
+}
+
+// TransactionRangeReader filters the transactions of a block range,
+// independently of any address
+type TransactionRangeReader interface {
+	// GetTransactionsByBlockRange returns the transactions in blocks [from, to]
+	// matching filter, in (block, index) order. The filter's TxType is relative
+	// to an address and is ignored. The range may span at most
+	// MaxTransactionBlockRange blocks.
+	GetTransactionsByBlockRange(ctx context.Context, from, to uint64, filter *TransactionFilter, limit, offset int) ([]*TransactionWithReceipt, error)
 }
 
 // HistoricalReader provides read-only access to historical blockchain data
 type HistoricalReader interface {
-	// GetBlocksByTimeRange returns blocks within a time range
-	GetBlocksByTimeRange(ctx context.Context, fromTime, toTime uint64, limit, offset int) ([]*types.Block, error)
+	// GetBlocksByTimeRange returns blocks within a time range in timestamp
+	// order, oldest first unless order is SortDescending
+	GetBlocksByTimeRange(ctx context.Context, fromTime, toTime uint64, limit, offset int, order SortOrder) ([]*types.Block, error)
 
 	// GetBlockByTimestamp returns the block closest to the given timestamp
 	GetBlockByTimestamp(ctx context.Context, timestamp uint64) (*types.Block, error)
@@ -247,6 +289,21 @@ type HistoricalReader interface {
 	GetAddressStats(ctx context.Context, addr common.Address) (*AddressStats, error)
 }
 
+// MinerStatsOptions controls how miner statistics are aggregated
+type MinerStatsOptions struct {
+	// SkipRewards skips transaction fee aggregation, leaving TotalRewards at zero.
+	// Block counts and percentages are still computed, but no receipts are read.
+	SkipRewards bool
+}
+
+// MinerStatsReader is an optional interface for storages that support
+// configurable miner statistics aggregation
+type MinerStatsReader interface {
+	// GetTopMinersWithOptions returns the top miners by block count using the given options
+	// If fromBlock and toBlock are both 0, returns all-time statistics
+	GetTopMinersWithOptions(ctx context.Context, limit int, fromBlock, toBlock uint64, opts MinerStatsOptions) ([]MinerStats, error)
+}
+
 // HistoricalWriter provides write access for historical blockchain data
 type HistoricalWriter interface {
 	// SetBlockTimestamp indexes a block by timestamp
@@ -259,6 +316,17 @@ type HistoricalWriter interface {
 	SetBalance(ctx context.Context, addr common.Address, blockNumber uint64, balance *big.Int) error
 }
 
+// BalanceWriter records native balance changes. HistoricalWriter implements it,
+// and so do batches that can commit balance changes together with the block
+// that caused them.
+type BalanceWriter interface {
+	// UpdateBalance updates the balance for an address at a specific block
+	UpdateBalance(ctx context.Context, addr common.Address, blockNumber uint64, delta *big.Int, txHash common.Hash) error
+
+	// SetBalance sets the balance for an address at a specific block
+	SetBalance(ctx context.Context, addr common.Address, blockNumber uint64, balance *big.Int) error
+}
+
 // HistoricalStorage combines historical read and write interfaces
 type HistoricalStorage interface {
 	HistoricalReader
@@ -291,18 +359,35 @@ func (f *TransactionFilter) Validate() error {
 		}
 	}
 
+	for _, txType := range f.TransactionTypes {
+		if !isKnownTxType(txType) {
+			return fmt.Errorf("unsupported transaction type %d", txType)
+		}
+	}
+
 	return nil
 }
 
+// isKnownTxType reports whether txType is a transaction type that can be
+// stored: legacy, access-list, dynamic-fee, blob or set-code
+func isKnownTxType(txType uint8) bool {
+	switch txType {
+	case types.LegacyTxType, types.AccessListTxType, types.DynamicFeeTxType, types.BlobTxType, types.SetCodeTxType:
+		return true
+	default:
+		return false
+	}
+}
+
 // MatchTransaction checks if a transaction matches the filter criteria
 func (f *TransactionFilter) MatchTransaction(tx *types.Transaction, receipt *types.Receipt, location *TxLocation, targetAddr common.Address) bool {
-	// Check block range
-	if location.BlockHeight < f.FromBlock || location.BlockHeight > f.ToBlock {
+	if !f.matchBlockRange(location) {
 		return false
 	}
 
-	// Check transaction type
-	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	// Check transaction direction, using the sender cached in the location
+	// when there is one
+	from, err := location.Sender(tx)
 	if err != nil {
 		return false
 	}
@@ -323,6 +408,22 @@ func (f *TransactionFilter) MatchTransaction(tx *types.Transaction, receipt *typ
 		}
 	}
 
+	return f.matchFields(tx, receipt)
+}
+
+// matchBlockRange reports whether the transaction's block is within the
+// filter's block range
+func (f *TransactionFilter) matchBlockRange(location *TxLocation) bool {
+	return location.BlockHeight >= f.FromBlock && location.BlockHeight <= f.ToBlock
+}
+
+// matchFields checks the criteria that do not depend on an address or the
+// transaction's location
+func (f *TransactionFilter) matchFields(tx *types.Transaction, receipt *types.Receipt) bool {
+	if len(f.TransactionTypes) > 0 && !slices.Contains(f.TransactionTypes, tx.Type()) {
+		return false
+	}
+
 	// Check value range
 	if f.MinValue != nil && tx.Value().Cmp(f.MinValue) < 0 {
 		return false
@@ -418,6 +519,10 @@ type FeeDelegationTxMeta struct {
 	FeePayerR *big.Int
 	// FeePayerS is the S value of fee payer signature
 	FeePayerS *big.Int
+	// Sender is the transaction sender reported by the node, as the sender
+	// signature of a fee delegation transaction does not recover with the
+	// standard signers
+	Sender common.Address
 }
 
 // FeeDelegationReader provides read access to fee delegation statistics
@@ -2,9 +2,12 @@ package storage
 
 import (
 	"context"
+	"math"
 	"math/big"
+	"reflect"
 	"testing"
 
+	"github.com/cockroachdb/pebble"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -65,6 +68,24 @@ func TestTransactionFilter_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "known transaction types",
+			filter: &TransactionFilter{
+				FromBlock:        0,
+				ToBlock:          1000,
+				TransactionTypes: []uint8{types.LegacyTxType, types.AccessListTxType, types.DynamicFeeTxType, types.BlobTxType, types.SetCodeTxType},
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown transaction type",
+			filter: &TransactionFilter{
+				FromBlock:        0,
+				ToBlock:          1000,
+				TransactionTypes: []uint8{types.DynamicFeeTxType, 0x16},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -278,7 +299,7 @@ func TestGetBlocksByTimeRange(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			results, err := storage.GetBlocksByTimeRange(ctx, tt.fromTime, tt.toTime, tt.limit, tt.offset)
+			results, err := storage.GetBlocksByTimeRange(ctx, tt.fromTime, tt.toTime, tt.limit, tt.offset, SortAscending)
 			if err != nil {
 				t.Fatalf("GetBlocksByTimeRange() error = %v", err)
 			}
@@ -290,6 +311,87 @@ func TestGetBlocksByTimeRange(t *testing.T) {
 	}
 }
 
+// TestGetBlocksByTimeRangeOrder tests ascending and descending ordering
+func TestGetBlocksByTimeRangeOrder(t *testing.T) {
+	s, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	storage := s.(*PebbleStorage)
+	ctx := context.Background()
+
+	for i := uint64(1); i <= 5; i++ {
+		block := createTestBlockWithTimestamp(t, i, i*1000)
+		if err := storage.SetBlock(ctx, block); err != nil {
+			t.Fatalf("SetBlock() error = %v", err)
+		}
+		if err := storage.SetBlockTimestamp(ctx, block.Time(), i); err != nil {
+			t.Fatalf("SetBlockTimestamp() error = %v", err)
+		}
+	}
+
+	heights := func(order SortOrder, limit, offset int) []uint64 {
+		t.Helper()
+		blocks, err := storage.GetBlocksByTimeRange(ctx, 1000, 5000, limit, offset, order)
+		if err != nil {
+			t.Fatalf("GetBlocksByTimeRange() error = %v", err)
+		}
+		result := make([]uint64, len(blocks))
+		for i, block := range blocks {
+			result[i] = block.NumberU64()
+		}
+		return result
+	}
+
+	asc := heights(SortAscending, 10, 0)
+	desc := heights(SortDescending, 10, 0)
+	if want := []uint64{1, 2, 3, 4, 5}; !reflect.DeepEqual(asc, want) {
+		t.Errorf("ascending = %v, want %v", asc, want)
+	}
+	if want := []uint64{5, 4, 3, 2, 1}; !reflect.DeepEqual(desc, want) {
+		t.Errorf("descending = %v, want %v", desc, want)
+	}
+
+	// Pages in each direction walk the same sequence
+	for _, tt := range []struct {
+		order SortOrder
+		all   []uint64
+	}{
+		{SortAscending, asc},
+		{SortDescending, desc},
+	} {
+		var paged []uint64
+		for offset := 0; offset < len(tt.all); offset += 2 {
+			paged = append(paged, heights(tt.order, 2, offset)...)
+		}
+		if !reflect.DeepEqual(paged, tt.all) {
+			t.Errorf("order %d: paged = %v, want %v", tt.order, paged, tt.all)
+		}
+	}
+
+	if got := heights(SortDescending, 2, 1); !reflect.DeepEqual(got, []uint64{4, 3}) {
+		t.Errorf("descending limit 2 offset 1 = %v, want [4 3]", got)
+	}
+}
+
+func TestParseSortOrder(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    SortOrder
+		wantErr bool
+	}{
+		{"", SortAscending, false},
+		{"asc", SortAscending, false},
+		{"DESC", SortDescending, false},
+		{"newest", SortAscending, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseSortOrder(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseSortOrder(%q) = %v, %v, want %v, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
 // TestGetBlockByTimestamp tests closest block lookup
 func TestGetBlockByTimestamp(t *testing.T) {
 	s, cleanup := setupTestStorage(t)
@@ -314,6 +416,11 @@ func TestGetBlockByTimestamp(t *testing.T) {
 			t.Fatalf("SetBlockTimestamp() error = %v", err)
 		}
 	}
+	// Metadata such as the transaction count sorts after the timestamp index
+	// and must not be read as part of it
+	if err := storage.db.Set(TransactionCountKey(), EncodeUint64(7), pebble.Sync); err != nil {
+		t.Fatalf("failed to set transaction count: %v", err)
+	}
 
 	tests := []struct {
 		name           string
@@ -778,6 +885,159 @@ func TestMatchTransaction(t *testing.T) {
 	}
 }
 
+func TestGetTransactionsByBlockRange(t *testing.T) {
+	storage, cleanup := setupTestStorage(t)
+	defer cleanup()
+	pebbleStorage := storage.(*PebbleStorage)
+	ctx := context.Background()
+
+	to := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	transfer := common.FromHex("0xa9059cbb")
+
+	// Blocks 1..5 with three transactions each: values 10*height, 10*height+1
+	// (a failed transfer call) and 10*height+2
+	var nonce uint64
+	for height := uint64(1); height <= 5; height++ {
+		var txs []*types.Transaction
+		var receipts []*types.Receipt
+		for i := uint64(0); i < 3; i++ {
+			var data []byte
+			status := types.ReceiptStatusSuccessful
+			if i == 1 {
+				data = transfer
+				status = types.ReceiptStatusFailed
+			}
+			tx := types.NewTx(&types.LegacyTx{
+				Nonce:    nonce,
+				To:       &to,
+				Value:    big.NewInt(int64(10*height + i)),
+				Gas:      50000,
+				GasPrice: big.NewInt(1),
+				Data:     data,
+			})
+			nonce++
+			txs = append(txs, tx)
+			receipts = append(receipts, &types.Receipt{
+				Status:            status,
+				CumulativeGasUsed: 21000 * (i + 1),
+				TxHash:            tx.Hash(),
+			})
+		}
+		block := types.NewBlockWithHeader(&types.Header{
+			Number:     new(big.Int).SetUint64(height),
+			Difficulty: big.NewInt(1),
+		}).WithBody(types.Body{Transactions: txs})
+		if err := pebbleStorage.SetBlockWithReceipts(ctx, block, receipts); err != nil {
+			t.Fatalf("SetBlockWithReceipts(%d) error = %v", height, err)
+		}
+	}
+
+	values := func(results []*TransactionWithReceipt) []int64 {
+		out := make([]int64, len(results))
+		for i, r := range results {
+			out[i] = r.Transaction.Value().Int64()
+		}
+		return out
+	}
+
+	tests := []struct {
+		name          string
+		from, to      uint64
+		filter        *TransactionFilter
+		limit, offset int
+		want          []int64
+	}{
+		{
+			name: "no filter",
+			from: 2, to: 3,
+			limit: 10,
+			want:  []int64{20, 21, 22, 30, 31, 32},
+		},
+		{
+			name: "min value",
+			from: 1, to: 5,
+			filter: &TransactionFilter{ToBlock: ^uint64(0), MinValue: big.NewInt(41)},
+			limit:  10,
+			want:   []int64{41, 42, 50, 51, 52},
+		},
+		{
+			name: "method ID",
+			from: 1, to: 5,
+			filter: &TransactionFilter{ToBlock: ^uint64(0), MethodID: "0xA9059CBB"},
+			limit:  10,
+			want:   []int64{11, 21, 31, 41, 51},
+		},
+		{
+			name: "success only with value range",
+			from: 1, to: 5,
+			filter: &TransactionFilter{ToBlock: ^uint64(0), SuccessOnly: true, MinValue: big.NewInt(20), MaxValue: big.NewInt(40)},
+			limit:  10,
+			want:   []int64{20, 22, 30, 32, 40},
+		},
+		{
+			name: "filter block range narrows the range",
+			from: 1, to: 5,
+			filter: &TransactionFilter{FromBlock: 4, ToBlock: 4},
+			limit:  10,
+			want:   []int64{40, 41, 42},
+		},
+		{
+			name: "paginated",
+			from: 1, to: 5,
+			filter: &TransactionFilter{ToBlock: ^uint64(0), SuccessOnly: true},
+			limit:  3, offset: 4,
+			want: []int64{30, 32, 40},
+		},
+		{
+			name: "offset past the results",
+			from: 1, to: 5,
+			filter: &TransactionFilter{ToBlock: ^uint64(0), MethodID: "0xa9059cbb"},
+			limit:  10, offset: 5,
+			want: []int64{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := pebbleStorage.GetTransactionsByBlockRange(ctx, tt.from, tt.to, tt.filter, tt.limit, tt.offset)
+			if err != nil {
+				t.Fatalf("GetTransactionsByBlockRange() error = %v", err)
+			}
+			if got := values(results); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetTransactionsByBlockRange() values = %v, want %v", got, tt.want)
+			}
+			for _, r := range results {
+				if r.Receipt == nil || r.Location == nil {
+					t.Fatal("result missing receipt or location")
+				}
+				if r.Receipt.TxHash != r.Transaction.Hash() || r.Location.BlockHeight != r.Receipt.BlockNumber.Uint64() {
+					t.Errorf("result for value %d has mismatched receipt or location", r.Transaction.Value())
+				}
+			}
+		})
+	}
+
+	t.Run("invalid range", func(t *testing.T) {
+		if _, err := pebbleStorage.GetTransactionsByBlockRange(ctx, 5, 1, nil, 10, 0); err == nil {
+			t.Error("expected error for from > to")
+		}
+		if _, err := pebbleStorage.GetTransactionsByBlockRange(ctx, 0, MaxTransactionBlockRange, nil, 10, 0); err == nil {
+			t.Error("expected error for a range spanning MaxTransactionBlockRange+1 blocks")
+		}
+		if _, err := pebbleStorage.GetTransactionsByBlockRange(ctx, 0, MaxTransactionBlockRange-1, nil, 10, 0); err != nil {
+			t.Errorf("range spanning MaxTransactionBlockRange blocks error = %v", err)
+		}
+		// A range ending at the last possible height terminates
+		if _, err := pebbleStorage.GetTransactionsByBlockRange(ctx, math.MaxUint64-2, math.MaxUint64, nil, 10, 0); err != nil {
+			t.Errorf("range ending at math.MaxUint64 error = %v", err)
+		}
+		invalid := &TransactionFilter{ToBlock: 10, MinValue: big.NewInt(5), MaxValue: big.NewInt(1)}
+		if _, err := pebbleStorage.GetTransactionsByBlockRange(ctx, 1, 5, invalid, 10, 0); err == nil {
+			t.Error("expected error for an invalid filter")
+		}
+	})
+}
+
 // Helper functions
 
 func createTestBlockWithTimestamp(t *testing.T, height uint64, timestamp uint64) *types.Block {
@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+)
+
+// iteratorTracker counts open iterators to catch code paths that forget to
+// close them. A nil tracker counts nothing, so call sites need no checks.
+type iteratorTracker struct {
+	open atomic.Int64
+	max  int64
+	// exceeded is called with the open count when it rises above max
+	exceeded func(open int64)
+}
+
+func newIteratorTracker(max int, exceeded func(open int64)) *iteratorTracker {
+	return &iteratorTracker{max: int64(max), exceeded: exceeded}
+}
+
+// opened counts a newly opened iterator
+func (t *iteratorTracker) opened() {
+	if t == nil {
+		return
+	}
+	if open := t.open.Add(1); open > t.max {
+		t.exceeded(open)
+	}
+}
+
+// closed counts a closed iterator
+func (t *iteratorTracker) closed() {
+	if t == nil {
+		return
+	}
+	t.open.Add(-1)
+}
+
+// count returns the number of open iterators
+func (t *iteratorTracker) count() int64 {
+	if t == nil {
+		return 0
+	}
+	return t.open.Load()
+}
+
+// OpenIterators returns the number of iterators currently open on the
+// database, or 0 when Config.MaxOpenIterators is not set
+func (s *PebbleStorage) OpenIterators() int64 {
+	return s.db.iterators.count()
+}
+
+// iteratorLimitExceeded reports more than MaxOpenIterators open iterators,
+// which usually means one is never closed. It panics when
+// PanicOnIteratorLeak is set and otherwise logs once each time the count
+// rises past the limit.
+func (s *PebbleStorage) iteratorLimitExceeded(open int64) {
+	if s.config.PanicOnIteratorLeak {
+		panic(fmt.Sprintf("storage: %d open iterators exceeds limit of %d, an iterator is probably not closed",
+			open, s.config.MaxOpenIterators))
+	}
+	if open == int64(s.config.MaxOpenIterators)+1 {
+		s.logger.Warn("Open iterators exceed limit, an iterator is probably not closed",
+			zap.Int64("open", open),
+			zap.Int("limit", s.config.MaxOpenIterators),
+			zap.Stack("stack"),
+		)
+	}
+}
@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func newIteratorTrackingStorage(t *testing.T, max int, panicOnLeak bool) *PebbleStorage {
+	t.Helper()
+	cfg := DefaultConfig(t.TempDir())
+	cfg.MaxOpenIterators = max
+	cfg.PanicOnIteratorLeak = panicOnLeak
+	storage, err := NewPebbleStorage(cfg)
+	if err != nil {
+		t.Fatalf("NewPebbleStorage() error = %v", err)
+	}
+	return storage
+}
+
+func TestIteratorTracker_CountsOpenIterators(t *testing.T) {
+	storage := newIteratorTrackingStorage(t, 10, true)
+	defer storage.Close()
+
+	ctx := context.Background()
+	if err := storage.SetBlock(ctx, createTestBlock(1)); err != nil {
+		t.Fatalf("SetBlock() error = %v", err)
+	}
+	// Query methods close their iterators
+	if _, err := storage.GetBlockCount(ctx); err != nil {
+		t.Fatalf("GetBlockCount() error = %v", err)
+	}
+	if open := storage.OpenIterators(); open != 0 {
+		t.Fatalf("OpenIterators() after a query = %d, want 0", open)
+	}
+
+	iter, err := storage.db.NewIter(nil)
+	if err != nil {
+		t.Fatalf("NewIter() error = %v", err)
+	}
+	if open := storage.OpenIterators(); open != 1 {
+		t.Errorf("OpenIterators() = %d, want 1", open)
+	}
+	iter.Close()
+	iter.Close() // a second Close is not counted again
+	if open := storage.OpenIterators(); open != 0 {
+		t.Errorf("OpenIterators() after Close = %d, want 0", open)
+	}
+}
+
+func TestIteratorTracker_PanicsOnLeak(t *testing.T) {
+	storage := newIteratorTrackingStorage(t, 2, true)
+
+	var leaked []*nsIterator
+	defer func() {
+		for _, iter := range leaked {
+			iter.Close()
+		}
+		storage.Close()
+	}()
+
+	for i := 0; i < 2; i++ {
+		iter, err := storage.db.NewIter(nil)
+		if err != nil {
+			t.Fatalf("NewIter() error = %v", err)
+		}
+		leaked = append(leaked, iter)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("opening an iterator past the limit did not panic")
+		}
+	}()
+	storage.db.NewIter(nil)
+	t.Fatal("NewIter() returned past the limit")
+}
+
+func TestIteratorTracker_LogsLeak(t *testing.T) {
+	storage := newIteratorTrackingStorage(t, 1, false)
+	defer storage.Close()
+
+	core, logs := observer.New(zap.WarnLevel)
+	storage.SetLogger(zap.New(core))
+
+	var iters []*nsIterator
+	for i := 0; i < 3; i++ {
+		iter, err := storage.db.NewIter(nil)
+		if err != nil {
+			t.Fatalf("NewIter() error = %v", err)
+		}
+		iters = append(iters, iter)
+	}
+
+	// Logged once when the count first rises past the limit
+	entries := logs.FilterMessageSnippet("Open iterators exceed limit").All()
+	if len(entries) != 1 {
+		t.Fatalf("got %d leak warnings, want 1", len(entries))
+	}
+	if open := entries[0].ContextMap()["open"]; open != int64(2) {
+		t.Errorf("warning open = %v, want 2", open)
+	}
+
+	for _, iter := range iters {
+		iter.Close()
+	}
+	if open := storage.OpenIterators(); open != 0 {
+		t.Errorf("OpenIterators() = %d, want 0", open)
+	}
+}
@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// LatestBlocks returns the blocks among the n heights ending at r's latest
+// height, newest first. It needs only the latest height pointer to find where
+// to start, so the cost is bounded by n point reads no matter how much history
+// is indexed. When r implements BlockHeaderReader only the headers are read,
+// and the returned blocks have no transactions or uncles. n is clamped to the
+// heights that exist; n <= 0 or an empty store yields an empty slice.
+func LatestBlocks(ctx context.Context, r Reader, n int) ([]*types.Block, error) {
+	if n <= 0 {
+		return []*types.Block{}, nil
+	}
+
+	latest, err := r.GetLatestHeight(ctx)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return []*types.Block{}, nil
+		}
+		return nil, fmt.Errorf("failed to get latest height: %w", err)
+	}
+	if uint64(n) > latest+1 {
+		n = int(latest + 1)
+	}
+
+	getBlock := r.GetBlock
+	if headers, ok := r.(BlockHeaderReader); ok {
+		getBlock = func(ctx context.Context, height uint64) (*types.Block, error) {
+			header, err := headers.GetBlockHeader(ctx, height)
+			if err != nil {
+				return nil, err
+			}
+			return types.NewBlockWithHeader(header), nil
+		}
+	}
+
+	blocks := make([]*types.Block, 0, n)
+	for i := 0; i < n; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		height := latest - uint64(i)
+		block, err := getBlock(ctx, height)
+		if err != nil {
+			if errors.Is(err, ErrNotFound) {
+				continue // Skip missing blocks
+			}
+			return nil, fmt.Errorf("failed to get block %d: %w", height, err)
+		}
+		blocks = append(blocks, block)
+	}
+
+	return blocks, nil
+}
@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// cacheShareOfMemory is the portion of the scaled memory share given to the
+// block cache; the rest is split across the memtables
+const cacheShareOfMemory = 0.75
+
+// effectiveWriteBufferCount returns the configured memtable count, falling back to the default
+func (c *Config) effectiveWriteBufferCount() int {
+	if c.WriteBufferCount <= 0 {
+		return DefaultWriteBufferCount
+	}
+	return c.WriteBufferCount
+}
+
+// ScaleToMemory derives Cache and WriteBuffer from a fraction of totalMemoryMB.
+// Three quarters of the share goes to the block cache and the remainder is split
+// across the memtables. Sizes never drop below the defaults, and the memtables are
+// capped by MemoryBudget when one is set. It is a no-op if either input is not positive.
+func (c *Config) ScaleToMemory(totalMemoryMB int, fraction float64) {
+	if totalMemoryMB <= 0 || fraction <= 0 {
+		return
+	}
+	if fraction > 1 {
+		fraction = 1
+	}
+
+	share := int(float64(totalMemoryMB) * fraction)
+	count := c.effectiveWriteBufferCount()
+
+	cache := int(float64(share) * cacheShareOfMemory)
+	writeBuffer := (share - cache) / count
+
+	if cache < DefaultCacheMB {
+		cache = DefaultCacheMB
+	}
+	if writeBuffer < DefaultWriteBufferMB {
+		writeBuffer = DefaultWriteBufferMB
+	}
+	if writeBuffer >= MaxWriteBufferMB {
+		writeBuffer = MaxWriteBufferMB - 1
+	}
+	if c.MemoryBudget > 0 && writeBuffer*count > c.MemoryBudget {
+		writeBuffer = c.MemoryBudget / count
+	}
+
+	c.Cache = cache
+	c.WriteBuffer = writeBuffer
+}
+
+// TotalMemoryMB returns the total system memory in MB, or 0 if it cannot be determined
+func TotalMemoryMB() int {
+	f, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+
+	return parseMemTotalMB(f)
+}
+
+// parseMemTotalMB extracts the MemTotal entry (in kB) from /proc/meminfo content
+func parseMemTotalMB(r io.Reader) int {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || fields[0] != "MemTotal:" {
+			continue
+		}
+		kb, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return 0
+		}
+		return kb / 1024
+	}
+	return 0
+}
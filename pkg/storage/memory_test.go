@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConfig_Validate_MemoryBudget(t *testing.T) {
+	tests := []struct {
+		name             string
+		writeBuffer      int
+		writeBufferCount int
+		budget           int
+		wantErr          bool
+	}{
+		{"unlimited budget", 512, 4, 0, false},
+		{"below budget", 64, 2, 256, false},
+		{"exactly at budget", 128, 2, 256, false},
+		{"one MB over budget", 129, 2, 256, true},
+		{"default count applied", 129, 0, 256, true},
+		{"negative budget", 64, 2, -1, true},
+		{"negative count", 64, -1, 0, true},
+		{"write buffer at pebble limit", MaxWriteBufferMB, 1, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig("/tmp/test")
+			cfg.WriteBuffer = tt.writeBuffer
+			cfg.WriteBufferCount = tt.writeBufferCount
+			cfg.MemoryBudget = tt.budget
+
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_ScaleToMemory(t *testing.T) {
+	tests := []struct {
+		name            string
+		totalMemoryMB   int
+		fraction        float64
+		budget          int
+		wantCache       int
+		wantWriteBuffer int
+	}{
+		// 16 GB * 0.25 = 4096 MB: 3072 MB cache, 1024 MB split over 2 memtables
+		{"scales with memory", 16384, 0.25, 0, 3072, 512},
+		// 1 GB * 0.25 = 256 MB: 192 MB cache, 32 MB memtables raised to the default
+		{"floors at defaults", 1024, 0.25, 0, 192, DefaultWriteBufferMB},
+		{"tiny machine", 256, 0.1, 0, DefaultCacheMB, DefaultWriteBufferMB},
+		{"capped by budget", 16384, 0.25, 400, 3072, 200},
+		{"capped by pebble limit", 1 << 20, 1, 0, 786432, MaxWriteBufferMB - 1},
+		{"fraction clamped to one", 8192, 2, 0, 6144, 1024},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig("/tmp/test")
+			cfg.MemoryBudget = tt.budget
+			cfg.ScaleToMemory(tt.totalMemoryMB, tt.fraction)
+
+			if cfg.Cache != tt.wantCache {
+				t.Errorf("Cache = %d, want %d", cfg.Cache, tt.wantCache)
+			}
+			if cfg.WriteBuffer != tt.wantWriteBuffer {
+				t.Errorf("WriteBuffer = %d, want %d", cfg.WriteBuffer, tt.wantWriteBuffer)
+			}
+			if err := cfg.Validate(); err != nil {
+				t.Errorf("scaled config should validate, got %v", err)
+			}
+		})
+	}
+}
+
+func TestConfig_ScaleToMemory_NoOp(t *testing.T) {
+	for _, tc := range []struct {
+		totalMemoryMB int
+		fraction      float64
+	}{{0, 0.25}, {16384, 0}, {-1, 0.5}} {
+		cfg := DefaultConfig("/tmp/test")
+		cfg.ScaleToMemory(tc.totalMemoryMB, tc.fraction)
+		if cfg.Cache != DefaultCacheMB || cfg.WriteBuffer != DefaultWriteBufferMB {
+			t.Errorf("ScaleToMemory(%d, %v) changed defaults to cache=%d writeBuffer=%d",
+				tc.totalMemoryMB, tc.fraction, cfg.Cache, cfg.WriteBuffer)
+		}
+	}
+}
+
+func TestParseMemTotalMB(t *testing.T) {
+	meminfo := "MemTotal:       16384000 kB\nMemFree:         1024000 kB\n"
+	if got := parseMemTotalMB(strings.NewReader(meminfo)); got != 16000 {
+		t.Errorf("parseMemTotalMB() = %d, want 16000", got)
+	}
+	if got := parseMemTotalMB(strings.NewReader("garbage\n")); got != 0 {
+		t.Errorf("parseMemTotalMB() = %d, want 0 for missing entry", got)
+	}
+}
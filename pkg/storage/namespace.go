@@ -0,0 +1,390 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/cockroachdb/pebble"
+)
+
+// prefixNamespace prefixes every key of a namespaced store, so several
+// chains can share one database: /ns/{namespace}/data/blocks/...
+const prefixNamespace = "/ns/"
+
+// validateKeyNamespace checks that a key namespace cannot overlap another one
+func validateKeyNamespace(ns string) error {
+	if strings.ContainsAny(ns, "/\x00") {
+		return errors.New("key namespace cannot contain '/' or NUL")
+	}
+	return nil
+}
+
+// namespacedDB wraps a pebble.DB and transparently prepends the key namespace
+// to every key read or written. With an empty namespace keys pass through
+// unchanged, so existing single-chain databases keep their layout.
+type namespacedDB struct {
+	// current is the open database, shared by all namespace views of it. A
+	// read replica swaps it when it refreshes.
+	current *atomic.Pointer[dbHandle]
+	// prefix is "/ns/{namespace}" (empty when namespacing is disabled). All
+	// schema keys start with '/', so namespaced keys read /ns/{namespace}/...
+	prefix []byte
+	// iterators counts open iterators when leak detection is enabled (nil otherwise)
+	iterators *iteratorTracker
+}
+
+// dbHandle is an open database together with the reads still using it. A
+// read replica retires the handle of a replaced snapshot, which waits for
+// those reads to be released before closing the database.
+type dbHandle struct {
+	db *pebble.DB
+	// refs counts the Get results, iterators and snapshots not yet closed
+	refs atomic.Int64
+	// retiring is set once the handle is swapped out
+	retiring atomic.Bool
+	// drained is closed when refs drops to zero after retiring is set
+	drained   chan struct{}
+	drainOnce sync.Once
+}
+
+func newDBHandle(db *pebble.DB) *dbHandle {
+	return &dbHandle{db: db, drained: make(chan struct{})}
+}
+
+func newNamespacedDB(db *pebble.DB, namespace string) *namespacedDB {
+	current := new(atomic.Pointer[dbHandle])
+	current.Store(newDBHandle(db))
+	return newNamespaceView(current, namespace)
+}
+
+func newNamespaceView(current *atomic.Pointer[dbHandle], namespace string) *namespacedDB {
+	n := &namespacedDB{current: current}
+	if namespace != "" {
+		n.prefix = []byte(prefixNamespace + namespace)
+	}
+	return n
+}
+
+// withNamespace returns a view of the same database scoped to namespace
+func (n *namespacedDB) withNamespace(namespace string) *namespacedDB {
+	view := newNamespaceView(n.current, namespace)
+	view.iterators = n.iterators
+	return view
+}
+
+// pebble returns the currently open database
+func (n *namespacedDB) pebble() *pebble.DB {
+	return n.current.Load().db
+}
+
+// acquire returns the currently open database handle, which is not closed
+// until release is called
+func (n *namespacedDB) acquire() *dbHandle {
+	for {
+		h := n.current.Load()
+		h.refs.Add(1)
+		if !h.retiring.Load() {
+			return h
+		}
+		// Swapped out between the load and the count; use the new one
+		h.release()
+	}
+}
+
+// release ends a read started by acquire
+func (h *dbHandle) release() {
+	if h.refs.Add(-1) == 0 && h.retiring.Load() {
+		h.drainOnce.Do(func() { close(h.drained) })
+	}
+}
+
+// swap makes db the open database and returns the handle it replaced
+func (n *namespacedDB) swap(db *pebble.DB) *dbHandle {
+	return n.current.Swap(newDBHandle(db))
+}
+
+// retire waits for the reads of a swapped out handle to be released and
+// closes its database
+func (h *dbHandle) retire() error {
+	h.retiring.Store(true)
+	if h.refs.Load() == 0 {
+		h.drainOnce.Do(func() { close(h.drained) })
+	}
+	<-h.drained
+	return h.db.Close()
+}
+
+// releaseCloser releases a database handle when the wrapped closer is closed
+type releaseCloser struct {
+	io.Closer
+	h    *dbHandle
+	once sync.Once
+}
+
+func (c *releaseCloser) Close() error {
+	err := c.Closer.Close()
+	c.once.Do(c.h.release)
+	return err
+}
+
+// key returns the physical key for a logical key
+func (n *namespacedDB) key(key []byte) []byte {
+	if len(n.prefix) == 0 {
+		return key
+	}
+	out := make([]byte, 0, len(n.prefix)+len(key))
+	out = append(out, n.prefix...)
+	return append(out, key...)
+}
+
+// keyspace returns the smallest physical key of the namespace
+func (n *namespacedDB) keyspace() []byte {
+	return n.key([]byte("/"))
+}
+
+// ownedRanges returns the logical key ranges [start, end) holding the keys of
+// the namespace, with nil for an open bound. The root namespace excludes the
+// keys of the namespaces stored under /ns/.
+func (n *namespacedDB) ownedRanges() [][2][]byte {
+	if len(n.prefix) > 0 {
+		return [][2][]byte{{nil, nil}}
+	}
+	namespaces := []byte(prefixNamespace)
+	return [][2][]byte{{nil, namespaces}, {prefixUpperBound(namespaces), nil}}
+}
+
+// iterOptions translates logical iterator bounds to physical ones, clamping
+// unbounded iterators to the namespace. Root iterators keep their bounds and
+// step over the namespaced keys instead (see nsIterator).
+func (n *namespacedDB) iterOptions(opts *pebble.IterOptions) *pebble.IterOptions {
+	if len(n.prefix) == 0 {
+		return opts
+	}
+	var out pebble.IterOptions
+	if opts != nil {
+		out = *opts
+	}
+	if out.LowerBound != nil {
+		out.LowerBound = n.key(out.LowerBound)
+	} else {
+		out.LowerBound = n.keyspace()
+	}
+	if out.UpperBound != nil {
+		out.UpperBound = n.key(out.UpperBound)
+	} else {
+		out.UpperBound = prefixUpperBound(n.keyspace())
+	}
+	return &out
+}
+
+// Get reads a value by logical key
+func (n *namespacedDB) Get(key []byte) ([]byte, io.Closer, error) {
+	h := n.acquire()
+	value, closer, err := h.db.Get(n.key(key))
+	if err != nil {
+		h.release()
+		return nil, nil, err
+	}
+	return value, &releaseCloser{Closer: closer, h: h}, nil
+}
+
+// Set writes a value by logical key
+func (n *namespacedDB) Set(key, value []byte, opts *pebble.WriteOptions) error {
+	return n.pebble().Set(n.key(key), value, opts)
+}
+
+// Delete removes a value by logical key
+func (n *namespacedDB) Delete(key []byte, opts *pebble.WriteOptions) error {
+	return n.pebble().Delete(n.key(key), opts)
+}
+
+// NewIter returns an iterator over the namespace
+func (n *namespacedDB) NewIter(opts *pebble.IterOptions) (*nsIterator, error) {
+	h := n.acquire()
+	iter, err := h.db.NewIter(n.iterOptions(opts))
+	if err != nil {
+		h.release()
+		return nil, err
+	}
+	it := newNSIterator(iter, n)
+	it.handle = h
+	return it, nil
+}
+
+// NewBatch returns a batch whose keys are written to the namespace
+func (n *namespacedDB) NewBatch() *nsBatch {
+	return &nsBatch{batch: n.pebble().NewBatch(), ns: n}
+}
+
+// NewSnapshot returns a point-in-time view of the namespace
+func (n *namespacedDB) NewSnapshot() *nsSnapshot {
+	h := n.acquire()
+	return &nsSnapshot{snapshot: h.db.NewSnapshot(), ns: n, handle: h}
+}
+
+// Compact compacts the logical key range [start, end)
+func (n *namespacedDB) Compact(start, end []byte, parallelize bool) error {
+	if len(n.prefix) == 0 {
+		return n.pebble().Compact(start, end, parallelize)
+	}
+	lower, upper := n.keyspace(), prefixUpperBound(n.keyspace())
+	if len(start) > 0 {
+		lower = n.key(start)
+	}
+	if len(end) > 0 {
+		upper = n.key(end)
+	}
+	return n.pebble().Compact(lower, upper, parallelize)
+}
+
+// Flush flushes the shared memtable
+func (n *namespacedDB) Flush() error {
+	return n.pebble().Flush()
+}
+
+// Metrics returns metrics of the whole underlying database
+func (n *namespacedDB) Metrics() *pebble.Metrics {
+	return n.pebble().Metrics()
+}
+
+// Close closes the underlying database
+func (n *namespacedDB) Close() error {
+	return n.pebble().Close()
+}
+
+// nsIterator is a pebble.Iterator that exposes logical keys
+type nsIterator struct {
+	iter *pebble.Iterator
+	ns   *namespacedDB
+	// handle is released on Close; nil for iterators of a snapshot, which
+	// holds the handle itself
+	handle *dbHandle
+	// closed guards against counting a closed iterator twice
+	closed bool
+	// skipNamespaces is set for root iterators, which step over the keys of
+	// the namespaces stored under /ns/ so root scans stay within root data
+	skipNamespaces bool
+}
+
+func newNSIterator(iter *pebble.Iterator, ns *namespacedDB) *nsIterator {
+	ns.iterators.opened()
+	return &nsIterator{iter: iter, ns: ns, skipNamespaces: len(ns.prefix) == 0}
+}
+
+func (it *nsIterator) First() bool  { return it.forward(it.iter.First()) }
+func (it *nsIterator) Last() bool   { return it.backward(it.iter.Last()) }
+func (it *nsIterator) Next() bool   { return it.forward(it.iter.Next()) }
+func (it *nsIterator) Prev() bool   { return it.backward(it.iter.Prev()) }
+func (it *nsIterator) Valid() bool  { return it.iter.Valid() }
+func (it *nsIterator) Error() error { return it.iter.Error() }
+
+func (it *nsIterator) Close() error {
+	if it.closed {
+		return it.iter.Close()
+	}
+	it.closed = true
+	it.ns.iterators.closed()
+	err := it.iter.Close()
+	if it.handle != nil {
+		it.handle.release()
+	}
+	return err
+}
+
+// Key returns the logical key at the current position
+func (it *nsIterator) Key() []byte {
+	return it.iter.Key()[len(it.ns.prefix):]
+}
+
+// Value returns the value at the current position
+func (it *nsIterator) Value() []byte {
+	return it.iter.Value()
+}
+
+// SeekGE moves to the first logical key >= key
+func (it *nsIterator) SeekGE(key []byte) bool {
+	return it.forward(it.iter.SeekGE(it.ns.key(key)))
+}
+
+// SeekLT moves to the last logical key < key
+func (it *nsIterator) SeekLT(key []byte) bool {
+	return it.backward(it.iter.SeekLT(it.ns.key(key)))
+}
+
+// inNamespaces reports whether a root iterator is positioned on a key of
+// another namespace
+func (it *nsIterator) inNamespaces() bool {
+	return it.skipNamespaces && bytes.HasPrefix(it.iter.Key(), []byte(prefixNamespace))
+}
+
+// forward moves a root iterator that stepped onto the namespaced keys past them
+func (it *nsIterator) forward(valid bool) bool {
+	if !valid || !it.inNamespaces() {
+		return valid
+	}
+	return it.iter.SeekGE(prefixUpperBound([]byte(prefixNamespace)))
+}
+
+// backward moves a root iterator that stepped onto the namespaced keys before them
+func (it *nsIterator) backward(valid bool) bool {
+	if !valid || !it.inNamespaces() {
+		return valid
+	}
+	return it.iter.SeekLT([]byte(prefixNamespace))
+}
+
+// nsBatch is a pebble.Batch that writes logical keys to the namespace
+type nsBatch struct {
+	batch *pebble.Batch
+	ns    *namespacedDB
+}
+
+func (b *nsBatch) Set(key, value []byte, opts *pebble.WriteOptions) error {
+	return b.batch.Set(b.ns.key(key), value, opts)
+}
+
+func (b *nsBatch) Delete(key []byte, opts *pebble.WriteOptions) error {
+	return b.batch.Delete(b.ns.key(key), opts)
+}
+
+func (b *nsBatch) DeleteRange(start, end []byte, opts *pebble.WriteOptions) error {
+	return b.batch.DeleteRange(b.ns.key(start), b.ns.key(end), opts)
+}
+
+func (b *nsBatch) Commit(opts *pebble.WriteOptions) error { return b.batch.Commit(opts) }
+func (b *nsBatch) Close() error                           { return b.batch.Close() }
+func (b *nsBatch) Count() uint32                          { return b.batch.Count() }
+func (b *nsBatch) Reset()                                 { b.batch.Reset() }
+
+// nsSnapshot is a pebble.Snapshot restricted to the namespace
+type nsSnapshot struct {
+	snapshot *pebble.Snapshot
+	ns       *namespacedDB
+	handle   *dbHandle
+	closed   bool
+}
+
+func (s *nsSnapshot) Get(key []byte) ([]byte, io.Closer, error) {
+	return s.snapshot.Get(s.ns.key(key))
+}
+
+func (s *nsSnapshot) NewIter(opts *pebble.IterOptions) (*nsIterator, error) {
+	iter, err := s.snapshot.NewIter(s.ns.iterOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	return newNSIterator(iter, s.ns), nil
+}
+
+func (s *nsSnapshot) Close() error {
+	err := s.snapshot.Close()
+	if !s.closed {
+		s.closed = true
+		s.handle.release()
+	}
+	return err
+}
@@ -0,0 +1,224 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// setupNamespacedStorages opens two namespaced stores backed by one database,
+// each holding its own blocks 1..3
+func setupNamespacedStorages(t *testing.T) (*PebbleStorage, *PebbleStorage, map[string]map[uint64]common.Hash) {
+	t.Helper()
+
+	cfg := DefaultConfig(t.TempDir())
+	cfg.KeyNamespace = "8283"
+	chainA, err := NewPebbleStorage(cfg)
+	if err != nil {
+		t.Fatalf("NewPebbleStorage() error = %v", err)
+	}
+	t.Cleanup(func() { chainA.Close() })
+
+	chainB, err := chainA.WithNamespace("1337")
+	if err != nil {
+		t.Fatalf("WithNamespace() error = %v", err)
+	}
+	t.Cleanup(func() { chainB.Close() })
+
+	ctx := context.Background()
+	hashes := map[string]map[uint64]common.Hash{"8283": {}, "1337": {}}
+	for ns, storage := range map[string]*PebbleStorage{"8283": chainA, "1337": chainB} {
+		// Different timestamps give each chain different blocks at the same heights
+		offset := uint64(1000)
+		if ns == "1337" {
+			offset = 2000
+		}
+		for height := uint64(1); height <= 3; height++ {
+			block := createTestBlockWithTimestamp(t, height, offset+height)
+			if err := storage.SetBlock(ctx, block); err != nil {
+				t.Fatalf("SetBlock(%s, %d) error = %v", ns, height, err)
+			}
+			hashes[ns][height] = block.Hash()
+		}
+		if err := storage.SetLatestHeight(ctx, 3); err != nil {
+			t.Fatalf("SetLatestHeight(%s) error = %v", ns, err)
+		}
+	}
+	return chainA, chainB, hashes
+}
+
+func TestPebbleStorage_KeyNamespace_Isolation(t *testing.T) {
+	chainA, chainB, hashes := setupNamespacedStorages(t)
+	ctx := context.Background()
+
+	for ns, storage := range map[string]*PebbleStorage{"8283": chainA, "1337": chainB} {
+		for height := uint64(1); height <= 3; height++ {
+			block, err := storage.GetBlock(ctx, height)
+			if err != nil {
+				t.Fatalf("GetBlock(%s, %d) error = %v", ns, height, err)
+			}
+			if block.Hash() != hashes[ns][height] {
+				t.Errorf("GetBlock(%s, %d) = %s, want %s", ns, height, block.Hash().Hex(), hashes[ns][height].Hex())
+			}
+		}
+	}
+
+	// Hash lookups resolve only within the owning namespace
+	if _, err := chainB.GetBlockByHash(ctx, hashes["8283"][1]); err != ErrNotFound {
+		t.Errorf("GetBlockByHash() across namespaces error = %v, want ErrNotFound", err)
+	}
+
+	// Rolling back one chain iterates and deletes only its own keys
+	if err := chainA.RollbackToHeight(ctx, 1); err != nil {
+		t.Fatalf("RollbackToHeight() error = %v", err)
+	}
+	if latest, err := chainA.GetLatestHeight(ctx); err != nil || latest != 1 {
+		t.Errorf("chain 8283 GetLatestHeight() = %d, %v; want 1", latest, err)
+	}
+	if latest, err := chainB.GetLatestHeight(ctx); err != nil || latest != 3 {
+		t.Errorf("chain 1337 GetLatestHeight() = %d, %v; want 3", latest, err)
+	}
+	for height := uint64(1); height <= 3; height++ {
+		if _, err := chainB.GetBlock(ctx, height); err != nil {
+			t.Errorf("chain 1337 GetBlock(%d) error = %v after rolling back chain 8283", height, err)
+		}
+	}
+}
+
+func TestPebbleStorage_KeyNamespace_ViewClose(t *testing.T) {
+	chainA, chainB, hashes := setupNamespacedStorages(t)
+	ctx := context.Background()
+
+	// Closing a view leaves the shared database open for its owner
+	if err := chainB.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if _, err := chainB.GetBlock(ctx, 1); err != ErrClosed {
+		t.Errorf("GetBlock() on closed view error = %v, want ErrClosed", err)
+	}
+	block, err := chainA.GetBlock(ctx, 1)
+	if err != nil || block.Hash() != hashes["8283"][1] {
+		t.Errorf("GetBlock() on owner after view Close = %v, %v", block, err)
+	}
+}
+
+func TestPebbleStorage_KeyNamespace_RootOwnedRanges(t *testing.T) {
+	root := setupTestPebbleStorage(t)
+
+	chain, err := root.WithNamespace("1337")
+	if err != nil {
+		t.Fatalf("WithNamespace() error = %v", err)
+	}
+	t.Cleanup(func() { chain.Close() })
+
+	ctx := context.Background()
+	if err := root.SetBlock(ctx, createTestBlock(1)); err != nil {
+		t.Fatalf("SetBlock(root) error = %v", err)
+	}
+	if err := chain.SetBlock(ctx, createTestBlock(1)); err != nil {
+		t.Fatalf("SetBlock(1337) error = %v", err)
+	}
+
+	// The ranges CompactAll visits for the root store skip the namespaced keys
+	var rootKeys int
+	for _, r := range root.db.ownedRanges() {
+		iter, err := root.db.NewIter(&pebble.IterOptions{LowerBound: r[0], UpperBound: r[1]})
+		if err != nil {
+			t.Fatalf("NewIter() error = %v", err)
+		}
+		for iter.First(); iter.Valid(); iter.Next() {
+			if bytes.HasPrefix(iter.Key(), []byte(prefixNamespace)) {
+				t.Errorf("root owned range includes namespaced key %q", iter.Key())
+			}
+			rootKeys++
+		}
+		iter.Close()
+	}
+	if rootKeys == 0 {
+		t.Error("root owned ranges hold none of the root keys")
+	}
+
+	if err := root.CompactAll(ctx); err != nil {
+		t.Fatalf("CompactAll() error = %v", err)
+	}
+	if _, err := chain.GetBlock(ctx, 1); err != nil {
+		t.Errorf("namespaced GetBlock() after root CompactAll error = %v", err)
+	}
+}
+
+func TestPebbleStorage_KeyNamespace_RootScan(t *testing.T) {
+	root := setupTestPebbleStorage(t)
+
+	chain, err := root.WithNamespace("1337")
+	if err != nil {
+		t.Fatalf("WithNamespace() error = %v", err)
+	}
+	t.Cleanup(func() { chain.Close() })
+
+	ctx := context.Background()
+	if err := root.SetBlock(ctx, createTestBlock(1)); err != nil {
+		t.Fatalf("SetBlock(root) error = %v", err)
+	}
+	if err := chain.SetBlock(ctx, createTestBlock(1)); err != nil {
+		t.Fatalf("SetBlock(1337) error = %v", err)
+	}
+	// A root key sorting after /ns/ is still reached past the namespaces
+	if err := root.db.Set([]byte("/zz"), []byte{1}, pebble.Sync); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	iter, err := root.db.NewIter(nil)
+	if err != nil {
+		t.Fatalf("NewIter() error = %v", err)
+	}
+	defer iter.Close()
+
+	var forward, backward [][]byte
+	for iter.First(); iter.Valid(); iter.Next() {
+		forward = append(forward, append([]byte(nil), iter.Key()...))
+	}
+	for iter.Last(); iter.Valid(); iter.Prev() {
+		backward = append(backward, append([]byte(nil), iter.Key()...))
+	}
+	for _, key := range append(forward, backward...) {
+		if bytes.HasPrefix(key, []byte(prefixNamespace)) {
+			t.Errorf("root scan returned namespaced key %q", key)
+		}
+	}
+	if len(forward) == 0 || len(forward) != len(backward) {
+		t.Fatalf("root scan returned %d keys forward and %d backward", len(forward), len(backward))
+	}
+	if !bytes.Equal(forward[len(forward)-1], []byte("/zz")) {
+		t.Errorf("last root key = %q, want /zz", forward[len(forward)-1])
+	}
+	if iter.SeekGE([]byte(prefixNamespace)); !iter.Valid() || !bytes.Equal(iter.Key(), []byte("/zz")) {
+		t.Errorf("SeekGE(/ns/) did not skip to the next root key")
+	}
+
+	// Prefix sweeps through the root store leave the namespace untouched
+	if _, err := root.DeleteByPrefix([]byte("/")); err != nil {
+		t.Fatalf("DeleteByPrefix() error = %v", err)
+	}
+	if _, err := root.GetBlock(ctx, 1); err != ErrNotFound {
+		t.Errorf("root GetBlock() after sweep error = %v, want ErrNotFound", err)
+	}
+	if _, err := chain.GetBlock(ctx, 1); err != nil {
+		t.Errorf("namespaced GetBlock() after root sweep error = %v", err)
+	}
+}
+
+func TestConfig_Validate_KeyNamespace(t *testing.T) {
+	cfg := DefaultConfig(t.TempDir())
+	cfg.KeyNamespace = "chain/8283"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() accepted a key namespace containing '/'")
+	}
+
+	cfg.KeyNamespace = "8283"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
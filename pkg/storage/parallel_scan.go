@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// scanChunkSize is the number of consecutive heights a scan worker claims at a
+// time. Workers claim chunks on demand, so a slow region of the chain does not
+// leave the other workers idle.
+const scanChunkSize = 1000
+
+// scanBlockRange calls visit for every height in [start, end] and returns the
+// shards the heights were aggregated into. Each worker creates one shard with
+// newShard and visits heights only into it, so visit needs no locking; the
+// caller merges the shards. With workers <= 1 the range is visited in order
+// on the calling goroutine into a single shard.
+// Returns ctx.Err() if the context is cancelled during the scan.
+func scanBlockRange[S any](ctx context.Context, workers int, start, end uint64, newShard func() S, visit func(shard S, height uint64)) ([]S, error) {
+	if workers <= 1 || end-start < scanChunkSize {
+		shard := newShard()
+		for height := start; ; height++ {
+			if (height-start)%ScanContextCheckInterval == 0 {
+				if err := ctx.Err(); err != nil {
+					return nil, err
+				}
+			}
+			visit(shard, height)
+			if height == end {
+				break
+			}
+		}
+		return []S{shard}, nil
+	}
+
+	var (
+		nextChunk atomic.Uint64
+		wg        sync.WaitGroup
+		errOnce   sync.Once
+		scanErr   error
+	)
+	chunks := (end-start)/scanChunkSize + 1
+	shards := make([]S, workers)
+	for i := range shards {
+		shards[i] = newShard()
+		wg.Add(1)
+		go func(shard S) {
+			defer wg.Done()
+			for {
+				chunk := nextChunk.Add(1) - 1
+				if chunk >= chunks {
+					return
+				}
+				chunkStart := start + chunk*scanChunkSize
+				chunkEnd := end
+				if end-chunkStart >= scanChunkSize {
+					chunkEnd = chunkStart + scanChunkSize - 1
+				}
+				for height := chunkStart; ; height++ {
+					if (height-chunkStart)%ScanContextCheckInterval == 0 {
+						if err := ctx.Err(); err != nil {
+							errOnce.Do(func() { scanErr = err })
+							return
+						}
+					}
+					visit(shard, height)
+					if height == chunkEnd {
+						break
+					}
+				}
+			}
+		}(shards[i])
+	}
+	wg.Wait()
+
+	if scanErr != nil {
+		return nil, scanErr
+	}
+	return shards, nil
+}
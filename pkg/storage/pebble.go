@@ -6,26 +6,63 @@ import (
 	"sync"
 	"sync/atomic"
 
+	"github.com/0xmhha/indexer-go/internal/logger"
 	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/bloom"
 	"github.com/ethereum/go-ethereum/common"
 	"go.uber.org/zap"
 )
 
 // PebbleStorage implements Storage interface using PebbleDB
 type PebbleStorage struct {
-	db     *pebble.DB
+	db     *namespacedDB
 	config *Config
 	logger *zap.Logger
 	closed atomic.Bool
 
+	// sharedDB marks a namespace view created by WithNamespace, whose Close
+	// leaves the underlying database open for its owner
+	sharedDB bool
+
 	// Address transaction sequence counters
 	// Maps address -> next sequence number
 	addrSeqMu sync.RWMutex
 	addrSeq   map[common.Address]uint64
 
+	// Balance history sequence counters, seeded from disk on first use per address
+	balanceSeqMu sync.Mutex
+	balanceSeq   map[common.Address]uint64
+
+	// addrStatsMu serializes read-modify-write updates of address aggregates
+	addrStatsMu sync.Mutex
+
+	// readCache holds decoded blocks and receipts for the read path (nil when disabled)
+	readCache *readCache
+
+	// internalTransferMu serializes internal transfer sequence allocation
+	internalTransferMu sync.Mutex
+
 	// Transaction count cache to avoid per-transaction reads
 	txCount      atomic.Uint64
 	txCountReady atomic.Bool
+	// txCountMissing is set when blocks are indexed but no transaction count
+	// was ever persisted, as in databases created before the count existed
+	txCountMissing atomic.Bool
+	// txCountMu serializes writes that persist the transaction count, so the
+	// last persisted value always matches txCount
+	txCountMu sync.Mutex
+
+	// compactionStop and compactionDone stop the compaction scheduler on Close
+	// (nil when scheduled compaction is disabled)
+	compactionStop chan struct{}
+	compactionDone chan struct{}
+
+	// closeMu keeps Close from closing the database while a metrics scrape
+	// reads from it
+	closeMu sync.RWMutex
+
+	// replica holds the snapshot state of a read replica (nil otherwise)
+	replica *replicaState
 
 	// Optional token metadata fetcher for on-demand fetching from chain
 	// When set, GetTokenBalances will fetch metadata from chain if not found in DB
@@ -44,18 +81,30 @@ func NewPebbleStorage(cfg *Config) (*PebbleStorage, error) {
 
 	// Configure PebbleDB options
 	opts := &pebble.Options{
-		Cache:                    pebble.NewCache(int64(cfg.Cache) << 20), // Convert MB to bytes
-		MaxOpenFiles:             cfg.MaxOpenFiles,
-		MemTableSize:             uint64(cfg.WriteBuffer) << 20,
-		DisableWAL:               cfg.DisableWAL,
-		MaxConcurrentCompactions: func() int { return cfg.CompactionConcurrency },
-		ErrorIfExists:            false,
-		ErrorIfNotExists:         false,
+		Cache:                       pebble.NewCache(int64(cfg.Cache) << 20), // Convert MB to bytes
+		MaxOpenFiles:                cfg.MaxOpenFiles,
+		MemTableSize:                uint64(cfg.WriteBuffer) << 20,
+		MemTableStopWritesThreshold: cfg.effectiveWriteBufferCount(),
+		DisableWAL:                  cfg.DisableWAL,
+		MaxConcurrentCompactions:    func() int { return cfg.CompactionConcurrency },
+		ErrorIfExists:               false,
+		ErrorIfNotExists:            false,
+	}
+	if cfg.BloomFilterBitsPerKey > 0 {
+		// Levels beyond the last configured one inherit its options, so a
+		// single entry applies the filter to every level
+		opts.Levels = []pebble.LevelOptions{{
+			FilterPolicy: bloom.FilterPolicy(cfg.BloomFilterBitsPerKey),
+			FilterType:   pebble.TableFilter,
+		}}
 	}
 
 	if cfg.ReadOnly {
 		opts.ReadOnly = true
 	}
+	if cfg.ReplicaRefreshInterval > 0 {
+		return openPebbleReplica(cfg, opts)
+	}
 
 	// Open database
 	db, err := pebble.Open(cfg.Path, opts)
@@ -63,35 +112,108 @@ func NewPebbleStorage(cfg *Config) (*PebbleStorage, error) {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
-	logger := zap.NewNop() // Use nop logger by default
+	storage, err := newPebbleStorageWithDB(newNamespacedDB(db, cfg.KeyNamespace), cfg, zap.NewNop()) // Use nop logger by default
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return storage, nil
+}
+
+// WithNamespace returns a storage over the same database whose keys live in
+// the given key namespace, so several chains can be indexed into one
+// database. Closing the returned storage leaves the database open; it must be
+// closed before the storage it was created from.
+func (s *PebbleStorage) WithNamespace(namespace string) (*PebbleStorage, error) {
+	if err := s.ensureNotClosed(); err != nil {
+		return nil, err
+	}
+	if namespace == "" {
+		return nil, fmt.Errorf("key namespace cannot be empty")
+	}
+	if s.replica != nil {
+		return nil, fmt.Errorf("namespace views of a read replica are not supported")
+	}
+
+	cfg := *s.config
+	cfg.KeyNamespace = namespace
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
 
+	view, err := newPebbleStorageWithDB(s.db.withNamespace(namespace), &cfg, s.logger)
+	if err != nil {
+		return nil, err
+	}
+	view.sharedDB = true
+	return view, nil
+}
+
+// newPebbleStorageWithDB builds a storage over an open database view
+func newPebbleStorageWithDB(db *namespacedDB, cfg *Config, logger *zap.Logger) (*PebbleStorage, error) {
 	storage := &PebbleStorage{
-		db:      db,
-		config:  cfg,
-		logger:  logger,
-		addrSeq: make(map[common.Address]uint64),
+		db:         db,
+		config:     cfg,
+		logger:     logger,
+		addrSeq:    make(map[common.Address]uint64),
+		balanceSeq: make(map[common.Address]uint64),
 	}
 
-	// Load address sequences from database
-	if err := storage.loadAddressSequences(); err != nil {
-		db.Close()
-		return nil, fmt.Errorf("failed to load address sequences: %w", err)
+	// Namespace views share the tracker of the database they were created from
+	if cfg.MaxOpenIterators > 0 && db.iterators == nil {
+		db.iterators = newIteratorTracker(cfg.MaxOpenIterators, storage.iteratorLimitExceeded)
+	}
+
+	// Read-only deployments serve ad-hoc analytics scans, which would only churn
+	// the cache. A read replica serves API reads like the writer and purges the
+	// cache on every refresh.
+	if !cfg.ReadOnly || cfg.ReplicaRefreshInterval > 0 {
+		storage.readCache = newReadCache(cfg.ReadCacheSize)
 	}
 
 	// Load transaction count into cache
 	if err := storage.loadTransactionCount(); err != nil {
-		db.Close()
 		return nil, fmt.Errorf("failed to load transaction count: %w", err)
 	}
+	if cfg.InitTransactionCount && !cfg.ReadOnly && storage.txCountMissing.Load() {
+		if err := storage.InitializeTransactionCount(context.Background()); err != nil {
+			return nil, fmt.Errorf("failed to initialize transaction count: %w", err)
+		}
+	}
+
+	if !cfg.ReadOnly {
+		if err := storage.migrateProposalStatusIndex(); err != nil {
+			return nil, fmt.Errorf("failed to migrate proposal status index: %w", err)
+		}
+		if err := storage.migrateMintBurnEvents(); err != nil {
+			return nil, fmt.Errorf("failed to migrate mint and burn events: %w", err)
+		}
+		if cfg.CompactionInterval > 0 {
+			storage.startCompactionScheduler()
+		}
+	}
 
 	return storage, nil
 }
 
 // loadTransactionCount loads the current transaction count into cache
+// A database with blocks but no stored count is flagged as missing its count.
+// An empty database gets a zero count stored right away, so blocks without
+// transactions cannot later be mistaken for a missing count.
 func (s *PebbleStorage) loadTransactionCount() error {
 	value, closer, err := s.db.Get(TransactionCountKey())
 	if err != nil {
 		if err == pebble.ErrNotFound {
+			hasBlocks, err := s.hasLatestHeight()
+			if err != nil {
+				return err
+			}
+			if !hasBlocks && !s.config.ReadOnly {
+				if err := s.db.Set(TransactionCountKey(), EncodeUint64(0), pebble.Sync); err != nil {
+					return fmt.Errorf("failed to set transaction count: %w", err)
+				}
+			}
+			s.txCountMissing.Store(hasBlocks)
 			s.txCount.Store(0)
 			s.txCountReady.Store(true)
 			return nil
@@ -102,19 +224,100 @@ func (s *PebbleStorage) loadTransactionCount() error {
 
 	count, err := DecodeUint64(value)
 	if err != nil {
-		return fmt.Errorf("failed to decode transaction count: %w", err)
+		return fmt.Errorf("%w transaction count: %w", ErrDecodeFailed, err)
 	}
 
+	s.txCountMissing.Store(false)
 	s.txCount.Store(count)
 	s.txCountReady.Store(true)
 	return nil
 }
 
+// hasLatestHeight reports whether a latest height is stored, i.e. whether
+// any block has been indexed
+func (s *PebbleStorage) hasLatestHeight() (bool, error) {
+	_, closer, err := s.db.Get(LatestHeightKey())
+	if err != nil {
+		if err == pebble.ErrNotFound {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get latest height: %w", err)
+	}
+	closer.Close()
+	return true, nil
+}
+
+// TransactionCountMissing reports whether the database has indexed blocks but
+// no stored transaction count, as databases created before the count was
+// maintained do. GetTransactionCount then counts only transactions indexed
+// since open until InitializeTransactionCount runs (see
+// Config.InitTransactionCount).
+func (s *PebbleStorage) TransactionCountMissing() bool {
+	return s.txCountMissing.Load()
+}
+
+// commitWithTxCount commits batch together with the transaction count advanced
+// by delta. Count-changing commits are serialized and txCount advances only
+// after the commit succeeds, so concurrent commits cannot persist a count older
+// than one already written.
+// While the count is missing only the in-memory count advances, so the
+// undercount is not persisted and the next open detects the missing count again.
+func (s *PebbleStorage) commitWithTxCount(batch *nsBatch, delta uint64, opts *pebble.WriteOptions) error {
+	if delta == 0 {
+		return batch.Commit(opts)
+	}
+
+	s.txCountMu.Lock()
+	defer s.txCountMu.Unlock()
+
+	newCount := s.txCount.Load() + delta
+	if !s.txCountMissing.Load() {
+		if err := batch.Set(TransactionCountKey(), EncodeUint64(newCount), nil); err != nil {
+			return fmt.Errorf("failed to update transaction count: %w", err)
+		}
+	}
+	if err := batch.Commit(opts); err != nil {
+		return err
+	}
+	s.txCount.Store(newCount)
+	return nil
+}
+
+// commitWithTxCountRemoved is commitWithTxCount for a batch that removes
+// transactions, lowering the count by removed without going below zero
+func (s *PebbleStorage) commitWithTxCountRemoved(batch *nsBatch, removed uint64, opts *pebble.WriteOptions) error {
+	if removed == 0 {
+		return batch.Commit(opts)
+	}
+
+	s.txCountMu.Lock()
+	defer s.txCountMu.Unlock()
+
+	newCount := s.txCount.Load()
+	decrement(&newCount, removed)
+	if !s.txCountMissing.Load() {
+		if err := batch.Set(TransactionCountKey(), EncodeUint64(newCount), nil); err != nil {
+			return fmt.Errorf("failed to update transaction count: %w", err)
+		}
+	}
+	if err := batch.Commit(opts); err != nil {
+		return err
+	}
+	s.txCount.Store(newCount)
+	return nil
+}
+
 // SetLogger sets the logger for the storage
 func (s *PebbleStorage) SetLogger(logger *zap.Logger) {
 	s.logger = logger
 }
 
+// loggerFor returns the storage logger tagged with the request ID carried by
+// ctx, if the call came from an API request
+func (s *PebbleStorage) loggerFor(ctx context.Context) *zap.Logger {
+	return logger.ForContext(ctx, s.logger)
+}
+
 // SetTokenMetadataFetcher sets the token metadata fetcher for on-demand fetching
 // When set, GetTokenBalances will fetch metadata from chain if not found in DB
 func (s *PebbleStorage) SetTokenMetadataFetcher(fetcher TokenMetadataFetcher) {
@@ -143,7 +346,19 @@ func (s *PebbleStorage) Close() error {
 		return nil // Already closed
 	}
 
-	if s.db != nil {
+	// Wait for a running scheduled compaction before closing the database
+	if s.compactionStop != nil {
+		close(s.compactionStop)
+		<-s.compactionDone
+	}
+
+	s.closeMu.Lock()
+	defer s.closeMu.Unlock()
+
+	if s.replica != nil {
+		return s.closeReplica()
+	}
+	if s.db != nil && !s.sharedDB {
 		return s.db.Close()
 	}
 	return nil
@@ -376,11 +591,3 @@ func (s *PebbleStorage) Compact(ctx context.Context, start, end []byte) error {
 
 	return s.db.Compact(start, end, true)
 }
-
-// loadAddressSequences loads address sequence counters from database
-func (s *PebbleStorage) loadAddressSequences() error {
-	// For now, we'll initialize sequences to 0
-	// In production, we should scan the database to find the max sequence for each address
-	// This is acceptable for initial implementation
-	return nil
-}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/big"
+	"strconv"
 
 	"github.com/cockroachdb/pebble"
 	"github.com/ethereum/go-ethereum/common"
@@ -16,6 +17,8 @@ import (
 // Compile-time check to ensure PebbleStorage implements AddressIndexReader and AddressIndexWriter
 var _ AddressIndexReader = (*PebbleStorage)(nil)
 var _ AddressIndexWriter = (*PebbleStorage)(nil)
+var _ InternalTransferReader = (*PebbleStorage)(nil)
+var _ InternalTransferWriter = (*PebbleStorage)(nil)
 
 // ========== Contract Creation Implementation ==========
 
@@ -224,7 +227,7 @@ func (s *PebbleStorage) ListContracts(ctx context.Context, limit, offset int) ([
 	for _, addr := range paginatedAddrs {
 		creation, err := s.GetContractCreation(ctx, addr)
 		if err != nil {
-			s.logger.Warn("failed to get contract creation details",
+			s.loggerFor(ctx).Warn("failed to get contract creation details",
 				zap.String("address", addr.Hex()),
 				zap.Error(err))
 			continue
@@ -350,7 +353,7 @@ func (s *PebbleStorage) GetERC20TransfersByToken(ctx context.Context, tokenAddre
 		keyStr := string(key)
 		var logIndex uint
 		if n, err := fmt.Sscanf(keyStr[len(keyStr)-6:], "%06d", &logIndex); n == 0 || err != nil {
-			s.logger.Warn("Failed to parse logIndex from key", zap.String("key", keyStr), zap.Error(err))
+			s.loggerFor(ctx).Warn("Failed to parse logIndex from key", zap.String("key", keyStr), zap.Error(err))
 			continue
 		}
 
@@ -358,7 +361,7 @@ func (s *PebbleStorage) GetERC20TransfersByToken(ctx context.Context, tokenAddre
 		transfer, err := s.GetERC20Transfer(ctx, txHash, logIndex)
 		if err != nil {
 			// Skip if not found, but log error
-			s.logger.Warn("Failed to get ERC20 transfer", zap.String("txHash", txHash.Hex()), zap.Uint("logIndex", logIndex), zap.Error(err))
+			s.loggerFor(ctx).Warn("Failed to get ERC20 transfer", zap.String("txHash", txHash.Hex()), zap.Uint("logIndex", logIndex), zap.Error(err))
 			continue
 		}
 
@@ -437,14 +440,14 @@ func (s *PebbleStorage) GetERC20TransfersByAddress(ctx context.Context, address
 		keyStr := string(key)
 		var logIndex uint
 		if n, err := fmt.Sscanf(keyStr[len(keyStr)-6:], "%06d", &logIndex); n == 0 || err != nil {
-			s.logger.Warn("Failed to parse logIndex from key", zap.String("key", keyStr), zap.Error(err))
+			s.loggerFor(ctx).Warn("Failed to parse logIndex from key", zap.String("key", keyStr), zap.Error(err))
 			continue
 		}
 
 		// Fetch the actual transfer data
 		transfer, err := s.GetERC20Transfer(ctx, txHash, logIndex)
 		if err != nil {
-			s.logger.Warn("Failed to get ERC20 transfer", zap.String("txHash", txHash.Hex()), zap.Uint("logIndex", logIndex), zap.Error(err))
+			s.loggerFor(ctx).Warn("Failed to get ERC20 transfer", zap.String("txHash", txHash.Hex()), zap.Uint("logIndex", logIndex), zap.Error(err))
 			continue
 		}
 
@@ -606,14 +609,14 @@ func (s *PebbleStorage) GetERC721TransfersByToken(ctx context.Context, tokenAddr
 		keyStr := string(key)
 		var logIndex uint
 		if n, err := fmt.Sscanf(keyStr[len(keyStr)-6:], "%06d", &logIndex); n == 0 || err != nil {
-			s.logger.Warn("Failed to parse logIndex from key", zap.String("key", keyStr), zap.Error(err))
+			s.loggerFor(ctx).Warn("Failed to parse logIndex from key", zap.String("key", keyStr), zap.Error(err))
 			continue
 		}
 
 		// Fetch the actual transfer data
 		transfer, err := s.GetERC721Transfer(ctx, txHash, logIndex)
 		if err != nil {
-			s.logger.Warn("Failed to get ERC721 transfer", zap.String("txHash", txHash.Hex()), zap.Uint("logIndex", logIndex), zap.Error(err))
+			s.loggerFor(ctx).Warn("Failed to get ERC721 transfer", zap.String("txHash", txHash.Hex()), zap.Uint("logIndex", logIndex), zap.Error(err))
 			continue
 		}
 
@@ -692,14 +695,14 @@ func (s *PebbleStorage) GetERC721TransfersByAddress(ctx context.Context, address
 		keyStr := string(key)
 		var logIndex uint
 		if n, err := fmt.Sscanf(keyStr[len(keyStr)-6:], "%06d", &logIndex); n == 0 || err != nil {
-			s.logger.Warn("Failed to parse logIndex from key", zap.String("key", keyStr), zap.Error(err))
+			s.loggerFor(ctx).Warn("Failed to parse logIndex from key", zap.String("key", keyStr), zap.Error(err))
 			continue
 		}
 
 		// Fetch the actual transfer data
 		transfer, err := s.GetERC721Transfer(ctx, txHash, logIndex)
 		if err != nil {
-			s.logger.Warn("Failed to get ERC721 transfer", zap.String("txHash", txHash.Hex()), zap.Uint("logIndex", logIndex), zap.Error(err))
+			s.loggerFor(ctx).Warn("Failed to get ERC721 transfer", zap.String("txHash", txHash.Hex()), zap.Uint("logIndex", logIndex), zap.Error(err))
 			continue
 		}
 
@@ -793,14 +796,14 @@ func (s *PebbleStorage) GetNFTsByOwner(ctx context.Context, owner common.Address
 		// Find the separator between contractAddress and tokenId
 		parts := splitNFTKey(remaining)
 		if len(parts) < 2 {
-			s.logger.Warn("Invalid NFT owner index key", zap.String("key", key))
+			s.loggerFor(ctx).Warn("Invalid NFT owner index key", zap.String("key", key))
 			continue
 		}
 
 		contractAddress := common.HexToAddress(parts[0])
 		tokenId, ok := new(big.Int).SetString(parts[1], 10)
 		if !ok {
-			s.logger.Warn("Invalid tokenId in NFT owner index key", zap.String("key", key), zap.String("tokenId", parts[1]))
+			s.loggerFor(ctx).Warn("Invalid tokenId in NFT owner index key", zap.String("key", key), zap.String("tokenId", parts[1]))
 			continue
 		}
 
@@ -959,7 +962,7 @@ func (s *PebbleStorage) GetInternalTransactions(ctx context.Context, txHash comm
 
 		var internal InternalTransaction
 		if err := json.Unmarshal(value, &internal); err != nil {
-			s.logger.Warn("Failed to unmarshal internal transaction", zap.String("txHash", txHash.Hex()), zap.Error(err))
+			s.loggerFor(ctx).Warn("Failed to unmarshal internal transaction", zap.String("txHash", txHash.Hex()), zap.Error(err))
 			continue
 		}
 
@@ -1045,7 +1048,7 @@ func (s *PebbleStorage) GetInternalTransactionsByAddress(ctx context.Context, ad
 		// Fetch all internal transactions for this tx
 		txInternals, err := s.GetInternalTransactions(ctx, txHash)
 		if err != nil {
-			s.logger.Warn("Failed to get internal transactions", zap.String("txHash", txHash.Hex()), zap.Error(err))
+			s.loggerFor(ctx).Warn("Failed to get internal transactions", zap.String("txHash", txHash.Hex()), zap.Error(err))
 			continue
 		}
 
@@ -1142,3 +1145,168 @@ func (s *PebbleStorage) SaveInternalTransactions(ctx context.Context, txHash com
 
 	return nil
 }
+
+// ========== Internal Transfer Implementation ==========
+
+// GetInternalTransfers retrieves internal ETH transfers where the address is the
+// sender or the receiver, oldest first, with pagination.
+func (s *PebbleStorage) GetInternalTransfers(ctx context.Context, addr common.Address, limit, offset int) ([]*InternalTransaction, error) {
+	if err := s.ensureNotClosed(); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = constants.DefaultPaginationLimit
+	}
+	if limit > constants.DefaultMaxPaginationLimit {
+		limit = constants.DefaultMaxPaginationLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	prefix := InternalTransferKeyPrefix(addr)
+	iter, err := s.db.NewIter(&pebble.IterOptions{
+		LowerBound: prefix,
+		UpperBound: append(prefix, 0xff),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create iterator: %w", err)
+	}
+	defer iter.Close()
+
+	transfers := make([]*InternalTransaction, 0, limit)
+	skipped := 0
+
+	for iter.First(); iter.Valid() && len(transfers) < limit; iter.Next() {
+		if skipped < offset {
+			skipped++
+			continue
+		}
+
+		// Index values point at the transfer data key
+		data, closer, err := s.db.Get(iter.Value())
+		if err != nil {
+			if err == pebble.ErrNotFound {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get internal transfer: %w", err)
+		}
+
+		var transfer InternalTransaction
+		err = json.Unmarshal(data, &transfer)
+		closer.Close()
+		if err != nil {
+			s.loggerFor(ctx).Warn("Failed to unmarshal internal transfer", zap.String("address", addr.Hex()), zap.Error(err))
+			continue
+		}
+		transfers = append(transfers, &transfer)
+	}
+
+	if err := iter.Error(); err != nil {
+		return nil, fmt.Errorf("iterator error: %w", err)
+	}
+
+	return transfers, nil
+}
+
+// SaveInternalTransfers indexes each transfer under both its sender and receiver.
+// Transfers already stored (same transaction hash and index) are skipped, so
+// re-indexing a block does not duplicate address history entries.
+func (s *PebbleStorage) SaveInternalTransfers(ctx context.Context, transfers []*InternalTransaction) error {
+	if err := s.ensureNotClosed(); err != nil {
+		return err
+	}
+	if err := s.ensureNotReadOnly(); err != nil {
+		return err
+	}
+
+	if len(transfers) == 0 {
+		return nil
+	}
+
+	s.internalTransferMu.Lock()
+	defer s.internalTransferMu.Unlock()
+
+	batch := s.db.NewBatch()
+	defer batch.Close()
+
+	nextSeq := make(map[common.Address]uint64)
+	allocSeq := func(addr common.Address) (uint64, error) {
+		seq, ok := nextSeq[addr]
+		if !ok {
+			var err error
+			if seq, err = s.nextInternalTransferSeq(addr); err != nil {
+				return 0, err
+			}
+		}
+		nextSeq[addr] = seq + 1
+		return seq, nil
+	}
+
+	for _, transfer := range transfers {
+		if transfer == nil {
+			continue
+		}
+
+		dataKey := InternalTransferDataKey(transfer.TransactionHash, transfer.Index)
+		if _, closer, err := s.db.Get(dataKey); err == nil {
+			closer.Close()
+			continue
+		} else if err != pebble.ErrNotFound {
+			return fmt.Errorf("failed to check internal transfer: %w", err)
+		}
+
+		data, err := json.Marshal(transfer)
+		if err != nil {
+			return fmt.Errorf("failed to marshal internal transfer: %w", err)
+		}
+		if err := batch.Set(dataKey, data, nil); err != nil {
+			return fmt.Errorf("failed to save internal transfer: %w", err)
+		}
+
+		addrs := []common.Address{transfer.From}
+		if transfer.To != transfer.From {
+			addrs = append(addrs, transfer.To)
+		}
+		for _, addr := range addrs {
+			seq, err := allocSeq(addr)
+			if err != nil {
+				return err
+			}
+			if err := batch.Set(InternalTransferKey(addr, seq), dataKey, nil); err != nil {
+				return fmt.Errorf("failed to save internal transfer index: %w", err)
+			}
+		}
+	}
+
+	if err := batch.Commit(pebble.Sync); err != nil {
+		return fmt.Errorf("failed to commit internal transfers batch: %w", err)
+	}
+
+	return nil
+}
+
+// nextInternalTransferSeq returns the next free sequence number in an address's
+// internal transfer index. Caller must hold internalTransferMu.
+func (s *PebbleStorage) nextInternalTransferSeq(addr common.Address) (uint64, error) {
+	prefix := InternalTransferKeyPrefix(addr)
+	iter, err := s.db.NewIter(&pebble.IterOptions{
+		LowerBound: prefix,
+		UpperBound: append(prefix, 0xff),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to create iterator: %w", err)
+	}
+	defer iter.Close()
+
+	if !iter.Last() {
+		return 0, iter.Error()
+	}
+
+	seq, err := strconv.ParseUint(string(iter.Key()[len(prefix):]), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid internal transfer key %q: %w", iter.Key(), err)
+	}
+	return seq + 1, nil
+}
@@ -0,0 +1,499 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Ensure PebbleStorage implements AddressStatsWriter, AddressTransactionCounter
+// and AddressActivityReader
+var _ AddressStatsWriter = (*PebbleStorage)(nil)
+var _ AddressTransactionCounter = (*PebbleStorage)(nil)
+var _ AddressActivityReader = (*PebbleStorage)(nil)
+
+// IndexAddressStats adds the block's transactions to the running aggregates of
+// their senders and recipients. A marker keyed by height records the hash of the
+// block that was counted, so re-indexing the same block does not count it twice.
+// What each transaction added is stored with the marker, so a block replaced
+// by a reorg is taken back out before the new block is counted.
+func (s *PebbleStorage) IndexAddressStats(ctx context.Context, block *types.Block, receipts types.Receipts) error {
+	if err := s.ensureNotClosed(); err != nil {
+		return err
+	}
+	if err := s.ensureNotReadOnly(); err != nil {
+		return err
+	}
+
+	return s.indexAddressStats(ctx, block, receipts, false)
+}
+
+// IndexRepairedAddressStats adds the transactions of receipts that were
+// missing when the block was counted by IndexAddressStats, so they are counted
+// even though the block's marker already matches. Transactions of the block
+// without a receipt in receipts are not touched.
+func (s *PebbleStorage) IndexRepairedAddressStats(ctx context.Context, block *types.Block, receipts types.Receipts) error {
+	if err := s.ensureNotClosed(); err != nil {
+		return err
+	}
+	if err := s.ensureNotReadOnly(); err != nil {
+		return err
+	}
+
+	return s.indexAddressStats(ctx, block, receipts, true)
+}
+
+// indexAddressStats counts the transactions of block that have a receipt in
+// receipts and marks the block as counted. A block counted under another hash
+// is taken out first. Unless repaired is set, a block already counted under
+// the same hash is skipped; with repaired its transactions are added to it.
+func (s *PebbleStorage) indexAddressStats(ctx context.Context, block *types.Block, receipts types.Receipts, repaired bool) error {
+	blockHash := block.Hash()
+	height := block.NumberU64()
+
+	s.addrStatsMu.Lock()
+	defer s.addrStatsMu.Unlock()
+
+	counted, closer, err := s.db.Get(AddressStatsBlockKey(height))
+	previous := false
+	same := false
+	switch {
+	case err == nil:
+		previous = true
+		same = bytes.Equal(counted, blockHash[:])
+		closer.Close()
+		if same && !repaired {
+			return nil
+		}
+	case err != pebble.ErrNotFound:
+		return fmt.Errorf("failed to get address stats marker: %w", err)
+	}
+
+	u := newAddressStatsUpdate(s)
+	var entries []addressStatsEntry
+	if previous {
+		prev, err := s.addressStatsEntries(height)
+		if err != nil {
+			return err
+		}
+		if same {
+			entries = prev
+		} else {
+			for i := range prev {
+				if err := u.removeEntry(&prev[i]); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	added, err := u.addBlock(ctx, block, receipts)
+	if err != nil {
+		return err
+	}
+	return u.commit(height, blockHash, append(entries, added...))
+}
+
+// newAddressStatsUpdate returns an empty update of the aggregates in s
+func newAddressStatsUpdate(s *PebbleStorage) *addressStatsUpdate {
+	return &addressStatsUpdate{
+		storage: s,
+		stats:   make(map[common.Address]*AddressStats),
+		peers:   make(map[string]bool),
+	}
+}
+
+// addressStatsEntry is what one transaction adds to the aggregates of its
+// sender and recipient, kept so it can be taken out again
+type addressStatsEntry struct {
+	From    common.Address
+	To      *common.Address `rlp:"nil"`
+	Value   *big.Int
+	Failed  bool
+	GasUsed uint64
+	GasCost *big.Int
+	// Interaction is set for a call with input data to a target address
+	Interaction bool
+}
+
+// newAddressStatsEntry returns what tx, sent by from, adds to the aggregates.
+// receipt must carry GasUsed and EffectiveGasPrice.
+func newAddressStatsEntry(tx *types.Transaction, receipt *types.Receipt, from common.Address) addressStatsEntry {
+	e := addressStatsEntry{
+		From:        from,
+		To:          tx.To(),
+		Value:       tx.Value(),
+		Failed:      receipt.Status != types.ReceiptStatusSuccessful,
+		GasUsed:     receipt.GasUsed,
+		GasCost:     new(big.Int),
+		Interaction: tx.To() != nil && len(tx.Data()) > 0,
+	}
+	if receipt.EffectiveGasPrice != nil {
+		e.GasCost.Mul(new(big.Int).SetUint64(receipt.GasUsed), receipt.EffectiveGasPrice)
+	}
+	return e
+}
+
+// addressStatsEntries reads what the transactions of the block counted at
+// height added. Blocks counted before the entries were kept have none.
+func (s *PebbleStorage) addressStatsEntries(height uint64) ([]addressStatsEntry, error) {
+	value, closer, err := s.db.Get(AddressStatsEntriesKey(height))
+	if err != nil {
+		if err == pebble.ErrNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get address stats entries: %w", err)
+	}
+	defer closer.Close()
+
+	var entries []addressStatsEntry
+	if err := rlp.DecodeBytes(value, &entries); err != nil {
+		return nil, fmt.Errorf("%w address stats entries: %w", ErrDecodeFailed, err)
+	}
+	return entries, nil
+}
+
+// addBlock counts the transactions of block that have a receipt in receipts
+// and returns what they added
+func (u *addressStatsUpdate) addBlock(ctx context.Context, block *types.Block, receipts types.Receipts) ([]addressStatsEntry, error) {
+	receiptMap := make(map[common.Hash]*types.Receipt, len(receipts))
+	for _, receipt := range receipts {
+		if receipt != nil {
+			receiptMap[receipt.TxHash] = receipt
+		}
+	}
+
+	var entries []addressStatsEntry
+	for _, tx := range block.Transactions() {
+		receipt := receiptMap[tx.Hash()]
+		if receipt == nil {
+			continue
+		}
+		from, err := u.storage.transactionSender(ctx, nil, tx)
+		if err != nil {
+			continue
+		}
+
+		e := newAddressStatsEntry(tx, receipt, from)
+		if err := u.addEntry(&e, block); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// commit writes the accumulated aggregates together with the marker and the
+// entries of the block counted at height
+func (u *addressStatsUpdate) commit(height uint64, blockHash common.Hash, entries []addressStatsEntry) error {
+	batch := u.storage.db.NewBatch()
+	defer batch.Close()
+
+	for addr, stats := range u.stats {
+		data, err := EncodeAddressStats(stats)
+		if err != nil {
+			return err
+		}
+		if err := batch.Set(AddressStatsKey(addr), data, nil); err != nil {
+			return err
+		}
+	}
+	for key := range u.peers {
+		if err := batch.Set([]byte(key), []byte{1}, nil); err != nil {
+			return err
+		}
+	}
+	encoded, err := rlp.EncodeToBytes(entries)
+	if err != nil {
+		return fmt.Errorf("%w address stats entries: %w", ErrEncodeFailed, err)
+	}
+	if err := batch.Set(AddressStatsEntriesKey(height), encoded, nil); err != nil {
+		return err
+	}
+	if err := batch.Set(AddressStatsBlockKey(height), blockHash[:], nil); err != nil {
+		return err
+	}
+
+	// Use NoSync for performance - caller should use Sync() or batch commit for durability
+	return batch.Commit(pebble.NoSync)
+}
+
+// addressStatsUpdate accumulates the aggregate changes of one block before
+// they are written together with the block's marker
+type addressStatsUpdate struct {
+	storage *PebbleStorage
+	stats   map[common.Address]*AddressStats
+	// peers holds counterparty keys first recorded by this block
+	peers map[string]bool
+}
+
+// addEntry counts e in the aggregates of its sender and recipient
+func (u *addressStatsUpdate) addEntry(e *addressStatsEntry, block *types.Block) error {
+	if err := u.add(e.From, e, block); err != nil {
+		return err
+	}
+	if e.To != nil && *e.To != e.From {
+		return u.add(*e.To, e, block)
+	}
+	return nil
+}
+
+// removeEntry takes e back out of the aggregates of its sender and recipient
+func (u *addressStatsUpdate) removeEntry(e *addressStatsEntry) error {
+	if err := u.remove(e.From, e); err != nil {
+		return err
+	}
+	if e.To != nil && *e.To != e.From {
+		return u.remove(*e.To, e)
+	}
+	return nil
+}
+
+// add counts e in the aggregates of addr
+func (u *addressStatsUpdate) add(addr common.Address, e *addressStatsEntry, block *types.Block) error {
+	stats, err := u.load(addr)
+	if err != nil {
+		return err
+	}
+
+	stats.TotalTransactions++
+
+	if e.From == addr {
+		stats.SentCount++
+		stats.TotalValueSent.Add(stats.TotalValueSent, e.Value)
+		if e.To != nil {
+			if err := u.addPeer(stats, addr, *e.To); err != nil {
+				return err
+			}
+		}
+	}
+	if e.To != nil && *e.To == addr {
+		stats.ReceivedCount++
+		stats.TotalValueReceived.Add(stats.TotalValueReceived, e.Value)
+		if err := u.addPeer(stats, addr, e.From); err != nil {
+			return err
+		}
+	}
+
+	if e.Failed {
+		stats.FailedCount++
+	} else {
+		stats.SuccessCount++
+	}
+	stats.TotalGasUsed += e.GasUsed
+	stats.TotalGasCost.Add(stats.TotalGasCost, e.GasCost)
+
+	if e.Interaction {
+		stats.ContractInteractionCount++
+	}
+
+	height := block.NumberU64()
+	ts := block.Time()
+	if stats.TotalTransactions == 1 || height < stats.FirstSeenBlock {
+		stats.FirstSeenBlock = height
+		stats.FirstTransactionTimestamp = ts
+	}
+	if height >= stats.LastSeenBlock {
+		stats.LastSeenBlock = height
+		stats.LastTransactionTimestamp = ts
+	}
+	return nil
+}
+
+// remove takes e back out of the aggregates of addr, undoing add for a block
+// that is rolled back or replaced. Unique counterparties and the first and
+// last seen blocks are kept, as they cannot be recomputed from the block alone.
+func (u *addressStatsUpdate) remove(addr common.Address, e *addressStatsEntry) error {
+	stats, err := u.load(addr)
+	if err != nil {
+		return err
+	}
+
+	decrement(&stats.TotalTransactions, 1)
+
+	if e.From == addr {
+		decrement(&stats.SentCount, 1)
+		subtractFloored(stats.TotalValueSent, e.Value)
+	}
+	if e.To != nil && *e.To == addr {
+		decrement(&stats.ReceivedCount, 1)
+		subtractFloored(stats.TotalValueReceived, e.Value)
+	}
+
+	if e.Failed {
+		decrement(&stats.FailedCount, 1)
+	} else {
+		decrement(&stats.SuccessCount, 1)
+	}
+	decrement(&stats.TotalGasUsed, e.GasUsed)
+	subtractFloored(stats.TotalGasCost, e.GasCost)
+
+	if e.Interaction {
+		decrement(&stats.ContractInteractionCount, 1)
+	}
+	return nil
+}
+
+// decrement subtracts delta from *n without wrapping below zero
+func decrement(n *uint64, delta uint64) {
+	if *n < delta {
+		*n = 0
+		return
+	}
+	*n -= delta
+}
+
+// subtractFloored subtracts y from x in place without going below zero
+func subtractFloored(x, y *big.Int) {
+	x.Sub(x, y)
+	if x.Sign() < 0 {
+		x.SetInt64(0)
+	}
+}
+
+// load returns the aggregates of addr being updated, reading them from the
+// database the first time addr is seen in the block
+func (u *addressStatsUpdate) load(addr common.Address) (*AddressStats, error) {
+	if stats, ok := u.stats[addr]; ok {
+		return stats, nil
+	}
+
+	stats, err := u.storage.storedAddressStats(addr)
+	if errors.Is(err, ErrNotFound) {
+		stats = &AddressStats{
+			Address:            addr,
+			TotalGasCost:       big.NewInt(0),
+			TotalValueSent:     big.NewInt(0),
+			TotalValueReceived: big.NewInt(0),
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	u.stats[addr] = stats
+	return stats, nil
+}
+
+// addPeer counts peer towards the unique addresses of addr the first time they transact
+func (u *addressStatsUpdate) addPeer(stats *AddressStats, addr, peer common.Address) error {
+	key := AddressCounterpartyKey(addr, peer)
+	if u.peers[string(key)] {
+		return nil
+	}
+
+	_, closer, err := u.storage.db.Get(key)
+	if err == nil {
+		closer.Close()
+		return nil
+	}
+	if err != pebble.ErrNotFound {
+		return fmt.Errorf("failed to get address counterparty: %w", err)
+	}
+
+	u.peers[string(key)] = true
+	stats.UniqueAddressCount++
+	return nil
+}
+
+// storedAddressStats reads the running aggregates of addr
+func (s *PebbleStorage) storedAddressStats(addr common.Address) (*AddressStats, error) {
+	value, closer, err := s.db.Get(AddressStatsKey(addr))
+	if err != nil {
+		if err == pebble.ErrNotFound {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	defer closer.Close()
+
+	return DecodeAddressStats(value)
+}
+
+// CountTransactionsByAddress returns the number of transactions of an address
+// by counting the distinct hashes in its address index. The running
+// aggregates are not used, as they only cover blocks IndexAddressStats ran for
+// and would disagree with the pages of GetTransactionsByAddress.
+func (s *PebbleStorage) CountTransactionsByAddress(ctx context.Context, addr common.Address) (uint64, error) {
+	if err := s.ensureNotClosed(); err != nil {
+		return 0, err
+	}
+
+	prefix := AddressTransactionKeyPrefix(addr)
+	iter, err := s.db.NewIter(&pebble.IterOptions{
+		LowerBound: prefix,
+		UpperBound: prefixUpperBound(prefix),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to create iterator: %w", err)
+	}
+	defer iter.Close()
+
+	// A re-indexed block appends its entries again
+	seen := make(map[common.Hash]bool)
+	for iter.First(); iter.Valid(); iter.Next() {
+		seen[common.BytesToHash(iter.Value())] = true
+	}
+	if err := iter.Error(); err != nil {
+		return 0, fmt.Errorf("iterator error: %w", err)
+	}
+
+	return uint64(len(seen)), nil
+}
+
+// GetAddressActivityRange returns the first and last block the address
+// transacted in, from the locations of the transactions in its address index.
+// Entries are appended in indexing order, which is not block order when blocks
+// are fetched concurrently, so every entry is looked up. Transactions whose
+// location is gone, as after a rollback, are skipped.
+func (s *PebbleStorage) GetAddressActivityRange(ctx context.Context, addr common.Address) (uint64, uint64, error) {
+	if err := s.ensureNotClosed(); err != nil {
+		return 0, 0, err
+	}
+
+	prefix := AddressTransactionKeyPrefix(addr)
+	iter, err := s.db.NewIter(&pebble.IterOptions{
+		LowerBound: prefix,
+		UpperBound: prefixUpperBound(prefix),
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to create iterator: %w", err)
+	}
+	defer iter.Close()
+
+	var first, last uint64
+	found := false
+	for iter.First(); iter.Valid(); iter.Next() {
+		if err := ctx.Err(); err != nil {
+			return 0, 0, err
+		}
+		location, err := s.getTxLocation(common.BytesToHash(iter.Value()))
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+		if !found || location.BlockHeight < first {
+			first = location.BlockHeight
+		}
+		if !found || location.BlockHeight > last {
+			last = location.BlockHeight
+		}
+		found = true
+	}
+	if err := iter.Error(); err != nil {
+		return 0, 0, fmt.Errorf("iterator error: %w", err)
+	}
+	if !found {
+		return 0, 0, ErrNotFound
+	}
+
+	return first, last, nil
+}
@@ -0,0 +1,431 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// createStatsBlock builds a block holding txs with a receipt per transaction,
+// marking the transactions in failed as reverted
+func createStatsBlock(height, timestamp uint64, txs []*types.Transaction, failed map[int]bool) (*types.Block, types.Receipts) {
+	receipts := make(types.Receipts, len(txs))
+	for i, tx := range txs {
+		receipt := createTestReceipt(tx.Hash(), 21000)
+		if failed[i] {
+			receipt.Status = types.ReceiptStatusFailed
+		}
+		receipt.EffectiveGasPrice = tx.GasPrice()
+		receipts[i] = receipt
+	}
+	header := &types.Header{Number: new(big.Int).SetUint64(height), Time: timestamp}
+	block := types.NewBlock(header, &types.Body{Transactions: txs}, receipts, trie.NewStackTrie(nil))
+	return block, receipts
+}
+
+func TestPebbleStorage_IndexAddressStats(t *testing.T) {
+	s, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	storage := s.(*PebbleStorage)
+	ctx := context.Background()
+
+	keyA, _ := crypto.GenerateKey()
+	keyB, _ := crypto.GenerateKey()
+	addrA := crypto.PubkeyToAddress(keyA.PublicKey)
+	addrB := crypto.PubkeyToAddress(keyB.PublicKey)
+	addrC := common.HexToAddress("0xcccccccccccccccccccccccccccccccccccccccc")
+	gasPrice := big.NewInt(2)
+
+	aToB, _ := createSignedTransaction(0, addrB, big.NewInt(100), gasPrice, keyA)
+	bToA, _ := createSignedTransaction(0, addrA, big.NewInt(30), gasPrice, keyB)
+	aToC, _ := createSignedTransaction(1, addrC, big.NewInt(5), gasPrice, keyA)
+	block10, receipts10 := createStatsBlock(10, 1000, []*types.Transaction{aToB, bToA}, nil)
+	block12, receipts12 := createStatsBlock(12, 1200, []*types.Transaction{aToC}, map[int]bool{0: true})
+
+	for _, b := range []struct {
+		block    *types.Block
+		receipts types.Receipts
+	}{{block10, receipts10}, {block12, receipts12}, {block10, receipts10}, {block12, receipts12}} {
+		// The repeated blocks simulate re-indexing and must not be counted again
+		if err := storage.IndexAddressStats(ctx, b.block, b.receipts); err != nil {
+			t.Fatalf("IndexAddressStats(%d) error = %v", b.block.NumberU64(), err)
+		}
+	}
+
+	tests := []struct {
+		addr                     common.Address
+		total, sent, received    uint64
+		success, failed, unique  uint64
+		valueSent, valueReceived int64
+		firstBlock, lastBlock    uint64
+		firstTime, lastTime      uint64
+	}{
+		{addrA, 3, 2, 1, 2, 1, 2, 105, 30, 10, 12, 1000, 1200},
+		{addrB, 2, 1, 1, 2, 0, 1, 30, 100, 10, 10, 1000, 1000},
+		{addrC, 1, 0, 1, 0, 1, 1, 0, 5, 12, 12, 1200, 1200},
+	}
+	for _, tt := range tests {
+		stats, err := storage.GetAddressStats(ctx, tt.addr)
+		if err != nil {
+			t.Fatalf("GetAddressStats(%s) error = %v", tt.addr.Hex(), err)
+		}
+		if stats.Address != tt.addr {
+			t.Errorf("%s: Address = %s", tt.addr.Hex(), stats.Address.Hex())
+		}
+		if stats.TotalTransactions != tt.total || stats.SentCount != tt.sent || stats.ReceivedCount != tt.received {
+			t.Errorf("%s: total/sent/received = %d/%d/%d, want %d/%d/%d", tt.addr.Hex(),
+				stats.TotalTransactions, stats.SentCount, stats.ReceivedCount, tt.total, tt.sent, tt.received)
+		}
+		if stats.SuccessCount != tt.success || stats.FailedCount != tt.failed {
+			t.Errorf("%s: success/failed = %d/%d, want %d/%d", tt.addr.Hex(),
+				stats.SuccessCount, stats.FailedCount, tt.success, tt.failed)
+		}
+		if stats.UniqueAddressCount != tt.unique {
+			t.Errorf("%s: UniqueAddressCount = %d, want %d", tt.addr.Hex(), stats.UniqueAddressCount, tt.unique)
+		}
+		if stats.TotalValueSent.Int64() != tt.valueSent || stats.TotalValueReceived.Int64() != tt.valueReceived {
+			t.Errorf("%s: value sent/received = %s/%s, want %d/%d", tt.addr.Hex(),
+				stats.TotalValueSent, stats.TotalValueReceived, tt.valueSent, tt.valueReceived)
+		}
+		if stats.FirstSeenBlock != tt.firstBlock || stats.LastSeenBlock != tt.lastBlock {
+			t.Errorf("%s: first/last block = %d/%d, want %d/%d", tt.addr.Hex(),
+				stats.FirstSeenBlock, stats.LastSeenBlock, tt.firstBlock, tt.lastBlock)
+		}
+		if stats.FirstTransactionTimestamp != tt.firstTime || stats.LastTransactionTimestamp != tt.lastTime {
+			t.Errorf("%s: first/last timestamp = %d/%d, want %d/%d", tt.addr.Hex(),
+				stats.FirstTransactionTimestamp, stats.LastTransactionTimestamp, tt.firstTime, tt.lastTime)
+		}
+	}
+
+	// Gas cost is gas used times the effective gas price of every transaction
+	stats, _ := storage.GetAddressStats(ctx, addrA)
+	if stats.TotalGasUsed != 3*21000 || stats.TotalGasCost.Int64() != 3*21000*2 {
+		t.Errorf("gas used/cost = %d/%s, want %d/%d", stats.TotalGasUsed, stats.TotalGasCost, 3*21000, 3*21000*2)
+	}
+}
+
+func TestPebbleStorage_IndexAddressStats_OutOfOrder(t *testing.T) {
+	s, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	storage := s.(*PebbleStorage)
+	ctx := context.Background()
+
+	key, _ := crypto.GenerateKey()
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+	recipient := common.HexToAddress("0x1234567890123456789012345678901234567890")
+
+	later, _ := createSignedTransaction(1, recipient, big.NewInt(7), big.NewInt(1), key)
+	earlier, _ := createSignedTransaction(0, recipient, big.NewInt(3), big.NewInt(1), key)
+	block20, receipts20 := createStatsBlock(20, 2000, []*types.Transaction{later}, nil)
+	block15, receipts15 := createStatsBlock(15, 1500, []*types.Transaction{earlier}, nil)
+
+	// Concurrent fetching can index a later block first
+	if err := storage.IndexAddressStats(ctx, block20, receipts20); err != nil {
+		t.Fatalf("IndexAddressStats(20) error = %v", err)
+	}
+	if err := storage.IndexAddressStats(ctx, block15, receipts15); err != nil {
+		t.Fatalf("IndexAddressStats(15) error = %v", err)
+	}
+
+	stats, err := storage.GetAddressStats(ctx, sender)
+	if err != nil {
+		t.Fatalf("GetAddressStats() error = %v", err)
+	}
+	if stats.FirstSeenBlock != 15 || stats.LastSeenBlock != 20 {
+		t.Errorf("first/last block = %d/%d, want 15/20", stats.FirstSeenBlock, stats.LastSeenBlock)
+	}
+	if stats.FirstTransactionTimestamp != 1500 || stats.LastTransactionTimestamp != 2000 {
+		t.Errorf("first/last timestamp = %d/%d, want 1500/2000", stats.FirstTransactionTimestamp, stats.LastTransactionTimestamp)
+	}
+	if stats.TotalValueSent.Int64() != 10 || stats.UniqueAddressCount != 1 {
+		t.Errorf("value sent/unique = %s/%d, want 10/1", stats.TotalValueSent, stats.UniqueAddressCount)
+	}
+}
+
+func TestPebbleStorage_IndexAddressStats_ReplacedBlock(t *testing.T) {
+	s, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	storage := s.(*PebbleStorage)
+	ctx := context.Background()
+
+	keyA, _ := crypto.GenerateKey()
+	addrA := crypto.PubkeyToAddress(keyA.PublicKey)
+	addrB := common.HexToAddress("0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb")
+	addrC := common.HexToAddress("0xcccccccccccccccccccccccccccccccccccccccc")
+	gasPrice := big.NewInt(2)
+
+	aToB, _ := createSignedTransaction(0, addrB, big.NewInt(100), gasPrice, keyA)
+	aToC, _ := createSignedTransaction(0, addrC, big.NewInt(40), gasPrice, keyA)
+	orphaned, orphanedReceipts := createStatsBlock(10, 1000, []*types.Transaction{aToB}, nil)
+	canonical, canonicalReceipts := createStatsBlock(10, 1001, []*types.Transaction{aToC}, nil)
+
+	// A reorg replaces block 10, which is counted again under its new hash
+	for _, b := range []struct {
+		block    *types.Block
+		receipts types.Receipts
+	}{{orphaned, orphanedReceipts}, {canonical, canonicalReceipts}} {
+		if err := storage.IndexAddressStats(ctx, b.block, b.receipts); err != nil {
+			t.Fatalf("IndexAddressStats(%s) error = %v", b.block.Hash().Hex(), err)
+		}
+	}
+
+	stats, err := storage.GetAddressStats(ctx, addrA)
+	if err != nil {
+		t.Fatalf("GetAddressStats() error = %v", err)
+	}
+	if stats.TotalTransactions != 1 || stats.TotalValueSent.Int64() != 40 || stats.TotalGasUsed != 21000 {
+		t.Errorf("sender total/sent value/gas = %d/%s/%d, want 1/40/21000",
+			stats.TotalTransactions, stats.TotalValueSent, stats.TotalGasUsed)
+	}
+
+	stats, err = storage.GetAddressStats(ctx, addrB)
+	if err != nil {
+		t.Fatalf("GetAddressStats() error = %v", err)
+	}
+	if stats.TotalTransactions != 0 || stats.TotalValueReceived.Sign() != 0 {
+		t.Errorf("orphaned recipient total/received = %d/%s, want 0/0", stats.TotalTransactions, stats.TotalValueReceived)
+	}
+}
+
+func TestPebbleStorage_IndexAddressStats_FeeDelegationSender(t *testing.T) {
+	s, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	storage := s.(*PebbleStorage)
+	ctx := context.Background()
+
+	key, _ := crypto.GenerateKey()
+	sender := common.HexToAddress("0xdddddddddddddddddddddddddddddddddddddddd")
+	to := common.HexToAddress("0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb")
+
+	tx, _ := createSignedTransaction(0, to, big.NewInt(7), big.NewInt(1), key)
+	block, receipts := createStatsBlock(3, 300, []*types.Transaction{tx}, nil)
+
+	// The node reported sender wins over the recovered signer
+	if err := storage.SetFeeDelegationTxMeta(ctx, &FeeDelegationTxMeta{
+		TxHash:       tx.Hash(),
+		BlockNumber:  3,
+		OriginalType: 0x16,
+		FeePayer:     common.HexToAddress("0xeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee"),
+		Sender:       sender,
+	}); err != nil {
+		t.Fatalf("SetFeeDelegationTxMeta() error = %v", err)
+	}
+	if err := storage.IndexAddressStats(ctx, block, receipts); err != nil {
+		t.Fatalf("IndexAddressStats() error = %v", err)
+	}
+
+	stats, err := storage.GetAddressStats(ctx, sender)
+	if err != nil {
+		t.Fatalf("GetAddressStats() error = %v", err)
+	}
+	if stats.SentCount != 1 || stats.TotalValueSent.Int64() != 7 {
+		t.Errorf("sender sent/value = %d/%s, want 1/7", stats.SentCount, stats.TotalValueSent)
+	}
+}
+
+func TestPebbleStorage_CountTransactionsByAddress(t *testing.T) {
+	s, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	storage := s.(*PebbleStorage)
+	ctx := context.Background()
+
+	keyA, _ := crypto.GenerateKey()
+	keyB, _ := crypto.GenerateKey()
+	addrA := crypto.PubkeyToAddress(keyA.PublicKey)
+	addrB := crypto.PubkeyToAddress(keyB.PublicKey)
+	addrC := common.HexToAddress("0xcccccccccccccccccccccccccccccccccccccccc")
+	gasPrice := big.NewInt(2)
+
+	aToB, _ := createSignedTransaction(0, addrB, big.NewInt(100), gasPrice, keyA)
+	bToA, _ := createSignedTransaction(0, addrA, big.NewInt(30), gasPrice, keyB)
+	aToC, _ := createSignedTransaction(1, addrC, big.NewInt(5), gasPrice, keyA)
+	block10, receipts10 := createStatsBlock(10, 1000, []*types.Transaction{aToB, bToA}, nil)
+	block12, _ := createStatsBlock(12, 1200, []*types.Transaction{aToC}, nil)
+
+	// indexBlock adds the sender and recipient entries the fetcher writes
+	indexBlock := func(block *types.Block) {
+		t.Helper()
+		for _, tx := range block.Transactions() {
+			from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+			if err != nil {
+				t.Fatalf("Sender() error = %v", err)
+			}
+			if err := storage.AddTransactionToAddressIndex(ctx, from, tx.Hash()); err != nil {
+				t.Fatalf("AddTransactionToAddressIndex() error = %v", err)
+			}
+			if err := storage.AddTransactionToAddressIndex(ctx, *tx.To(), tx.Hash()); err != nil {
+				t.Fatalf("AddTransactionToAddressIndex() error = %v", err)
+			}
+		}
+	}
+
+	assertCounts := func(want map[common.Address]uint64) {
+		t.Helper()
+		for addr, wantCount := range want {
+			count, err := storage.CountTransactionsByAddress(ctx, addr)
+			if err != nil {
+				t.Fatalf("CountTransactionsByAddress(%s) error = %v", addr.Hex(), err)
+			}
+			if count != wantCount {
+				t.Errorf("CountTransactionsByAddress(%s) = %d, want %d", addr.Hex(), count, wantCount)
+			}
+		}
+	}
+
+	indexBlock(block10)
+	indexBlock(block12)
+
+	// Without aggregates the address index entries are counted
+	want := map[common.Address]uint64{addrA: 3, addrB: 2, addrC: 1}
+	for addr, wantCount := range want {
+		hashes, err := storage.GetTransactionsByAddress(ctx, addr, 100, 0)
+		if err != nil {
+			t.Fatalf("GetTransactionsByAddress(%s) error = %v", addr.Hex(), err)
+		}
+		if uint64(len(hashes)) != wantCount {
+			t.Fatalf("%s has %d index entries, want %d", addr.Hex(), len(hashes), wantCount)
+		}
+	}
+	assertCounts(want)
+
+	// Re-indexing a block duplicates its index entries but not the count
+	indexBlock(block10)
+	hashes, err := storage.GetTransactionsByAddress(ctx, addrA, 100, 0)
+	if err != nil {
+		t.Fatalf("GetTransactionsByAddress() error = %v", err)
+	}
+	if len(hashes) != 5 {
+		t.Fatalf("addrA has %d index entries after re-indexing, want 5", len(hashes))
+	}
+	assertCounts(want)
+
+	// Aggregates covering only some blocks do not change the count
+	if err := storage.IndexAddressStats(ctx, block10, receipts10); err != nil {
+		t.Fatalf("IndexAddressStats(10) error = %v", err)
+	}
+	assertCounts(want)
+
+	count, err := storage.CountTransactionsByAddress(ctx, common.HexToAddress("0xdddddddddddddddddddddddddddddddddddddddd"))
+	if err != nil {
+		t.Fatalf("CountTransactionsByAddress() error = %v", err)
+	}
+	if count != 0 {
+		t.Errorf("CountTransactionsByAddress() of unknown address = %d, want 0", count)
+	}
+}
+
+func TestPebbleStorage_GetAddressActivityRange(t *testing.T) {
+	s, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	storage := s.(*PebbleStorage)
+	ctx := context.Background()
+
+	keyA, _ := crypto.GenerateKey()
+	keyB, _ := crypto.GenerateKey()
+	addrA := crypto.PubkeyToAddress(keyA.PublicKey)
+	addrB := crypto.PubkeyToAddress(keyB.PublicKey)
+	addrC := common.HexToAddress("0xcccccccccccccccccccccccccccccccccccccccc")
+	gasPrice := big.NewInt(2)
+
+	aToB, _ := createSignedTransaction(0, addrB, big.NewInt(100), gasPrice, keyA)
+	bToC, _ := createSignedTransaction(0, addrC, big.NewInt(30), gasPrice, keyB)
+	aToC, _ := createSignedTransaction(1, addrC, big.NewInt(5), gasPrice, keyA)
+	block10, receipts10 := createStatsBlock(10, 1000, []*types.Transaction{aToB}, nil)
+	block15, _ := createStatsBlock(15, 1500, []*types.Transaction{bToC}, nil)
+	block20, _ := createStatsBlock(20, 2000, []*types.Transaction{aToC}, map[int]bool{0: true})
+
+	// Blocks arrive out of order, and one is indexed twice
+	for _, block := range []*types.Block{block15, block20, block10, block15} {
+		if err := storage.SetBlock(ctx, block); err != nil {
+			t.Fatalf("SetBlock(%d) error = %v", block.NumberU64(), err)
+		}
+		for _, tx := range block.Transactions() {
+			from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+			if err != nil {
+				t.Fatalf("Sender() error = %v", err)
+			}
+			for _, addr := range []common.Address{from, *tx.To()} {
+				if err := storage.AddTransactionToAddressIndex(ctx, addr, tx.Hash()); err != nil {
+					t.Fatalf("AddTransactionToAddressIndex() error = %v", err)
+				}
+			}
+		}
+	}
+	// Aggregates covering only some blocks do not narrow the range
+	if err := storage.IndexAddressStats(ctx, block10, receipts10); err != nil {
+		t.Fatalf("IndexAddressStats(10) error = %v", err)
+	}
+
+	tests := []struct {
+		addr        common.Address
+		first, last uint64
+	}{
+		{addrA, 10, 20},
+		{addrB, 10, 15},
+		{addrC, 15, 20},
+	}
+	for _, tt := range tests {
+		first, last, err := storage.GetAddressActivityRange(ctx, tt.addr)
+		if err != nil {
+			t.Fatalf("GetAddressActivityRange(%s) error = %v", tt.addr.Hex(), err)
+		}
+		if first != tt.first || last != tt.last {
+			t.Errorf("GetAddressActivityRange(%s) = (%d, %d), want (%d, %d)", tt.addr.Hex(), first, last, tt.first, tt.last)
+		}
+	}
+
+	unknown := common.HexToAddress("0xdddddddddddddddddddddddddddddddddddddddd")
+	if _, _, err := storage.GetAddressActivityRange(ctx, unknown); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetAddressActivityRange(unknown) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestPebbleStorage_GetAddressActivityRange_WithoutAggregates(t *testing.T) {
+	s, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	storage := s.(*PebbleStorage)
+	ctx := context.Background()
+
+	keyA, _ := crypto.GenerateKey()
+	addrA := crypto.PubkeyToAddress(keyA.PublicKey)
+	addrB := common.HexToAddress("0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb")
+	gasPrice := big.NewInt(2)
+
+	first, _ := createSignedTransaction(0, addrB, big.NewInt(1), gasPrice, keyA)
+	second, _ := createSignedTransaction(1, addrB, big.NewInt(2), gasPrice, keyA)
+	block7, _ := createStatsBlock(7, 700, []*types.Transaction{first}, nil)
+	block9, _ := createStatsBlock(9, 900, []*types.Transaction{second}, nil)
+
+	// Only blocks and the address index, as in databases indexed before the aggregates
+	for _, block := range []*types.Block{block7, block9} {
+		if err := storage.SetBlock(ctx, block); err != nil {
+			t.Fatalf("SetBlock(%d) error = %v", block.NumberU64(), err)
+		}
+		for _, tx := range block.Transactions() {
+			if err := storage.AddTransactionToAddressIndex(ctx, addrA, tx.Hash()); err != nil {
+				t.Fatalf("AddTransactionToAddressIndex() error = %v", err)
+			}
+		}
+	}
+
+	from, to, err := storage.GetAddressActivityRange(ctx, addrA)
+	if err != nil {
+		t.Fatalf("GetAddressActivityRange() error = %v", err)
+	}
+	if from != 7 || to != 9 {
+		t.Errorf("GetAddressActivityRange() = (%d, %d), want (7, 9)", from, to)
+	}
+}
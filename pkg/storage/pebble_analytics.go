@@ -342,7 +342,7 @@ func (s *PebbleStorage) GetNetworkMetrics(ctx context.Context, fromTime, toTime
 	var firstBlockTime, lastBlockTime uint64
 
 	// Get blocks by time range
-	blocks, err := s.GetBlocksByTimeRange(ctx, fromTime, toTime, 10000, 0)
+	blocks, err := s.GetBlocksByTimeRange(ctx, fromTime, toTime, 10000, 0, SortAscending)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get blocks: %w", err)
 	}
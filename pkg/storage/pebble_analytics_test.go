@@ -3,8 +3,10 @@ package storage
 import (
 	"context"
 	"crypto/ecdsa"
+	"fmt"
 	"math/big"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -745,3 +747,338 @@ func TestPebbleStorage_GetTopAddressesByTxCount_InvalidRange(t *testing.T) {
 	_, err := storage.GetTopAddressesByTxCount(ctx, 10, 200, 100)
 	require.Error(t, err)
 }
+
+// runCancelledScan starts scan in the background, cancels the context shortly
+// after, and asserts the scan returns context.Canceled well before completion.
+func runCancelledScan(t *testing.T, scan func(ctx context.Context) error) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- scan(ctx)
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(2 * time.Second):
+		t.Fatal("scan did not return promptly after context cancellation")
+	}
+}
+
+func TestPebbleStorage_LongScans_ContextCancelled(t *testing.T) {
+	s, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	storage := s.(*PebbleStorage)
+
+	// A very high latest height makes a full scan run far longer than the test timeout
+	require.NoError(t, storage.SetLatestHeight(context.Background(), 1<<40))
+
+	t.Run("GetTopMiners", func(t *testing.T) {
+		runCancelledScan(t, func(ctx context.Context) error {
+			_, err := storage.GetTopMiners(ctx, 10, 0, 0)
+			return err
+		})
+	})
+
+	t.Run("GetTokenBalances", func(t *testing.T) {
+		runCancelledScan(t, func(ctx context.Context) error {
+			_, err := storage.GetTokenBalances(ctx, common.HexToAddress("0x1"), "")
+			return err
+		})
+	})
+
+	t.Run("InitializeTransactionCount", func(t *testing.T) {
+		runCancelledScan(t, storage.InitializeTransactionCount)
+	})
+}
+
+// indexMinedBlocksWithFees stores one block per miner entry, each carrying a single
+// transaction with a receipt, so miner rewards can be derived from stored fees
+func indexMinedBlocksWithFees(tb testing.TB, storage *PebbleStorage, miners []common.Address, gasPrice *big.Int) {
+	tb.Helper()
+	ctx := context.Background()
+
+	privateKey, err := crypto.GenerateKey()
+	require.NoError(tb, err)
+	to := common.HexToAddress("0x1234567890123456789012345678901234567890")
+
+	for i, miner := range miners {
+		tx, err := createSignedTransaction(uint64(i), to, big.NewInt(1), gasPrice, privateKey)
+		require.NoError(tb, err)
+
+		header := &types.Header{
+			Coinbase:    miner,
+			Number:      big.NewInt(int64(i)),
+			GasLimit:    5000000,
+			GasUsed:     21000,
+			Time:        uint64(1000 + i),
+			Difficulty:  big.NewInt(0),
+			UncleHash:   types.EmptyUncleHash,
+			TxHash:      types.EmptyTxsHash,
+			ReceiptHash: types.EmptyReceiptsHash,
+			Extra:       []byte{},
+		}
+		block := types.NewBlock(header, &types.Body{Transactions: []*types.Transaction{tx}}, nil, trie.NewStackTrie(nil))
+		require.NoError(tb, storage.SetBlock(ctx, block))
+
+		require.NoError(tb, storage.SetReceipt(ctx, &types.Receipt{
+			Type:              types.LegacyTxType,
+			Status:            types.ReceiptStatusSuccessful,
+			CumulativeGasUsed: 21000,
+			GasUsed:           21000,
+			TxHash:            tx.Hash(),
+			BlockNumber:       big.NewInt(int64(i)),
+		}))
+	}
+	require.NoError(tb, storage.SetLatestHeight(ctx, uint64(len(miners)-1)))
+}
+
+func TestPebbleStorage_GetTopMinersWithOptions_SkipRewards(t *testing.T) {
+	s, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	storage := s.(*PebbleStorage)
+	ctx := context.Background()
+
+	miner1 := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	miner2 := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	miner3 := common.HexToAddress("0x3333333333333333333333333333333333333333")
+
+	// Miner1: 4 blocks, Miner2: 2 blocks, Miner3: 1 block
+	gasPrice := big.NewInt(1000000000)
+	indexMinedBlocksWithFees(t, storage, []common.Address{
+		miner1, miner2, miner1, miner3, miner1, miner2, miner1,
+	}, gasPrice)
+
+	withRewards, err := storage.GetTopMinersWithOptions(ctx, 10, 0, 0, MinerStatsOptions{})
+	require.NoError(t, err)
+	withoutRewards, err := storage.GetTopMinersWithOptions(ctx, 10, 0, 0, MinerStatsOptions{SkipRewards: true})
+	require.NoError(t, err)
+
+	require.Len(t, withRewards, 3)
+	require.Len(t, withoutRewards, 3)
+
+	fee := new(big.Int).Mul(gasPrice, big.NewInt(21000))
+	for i, want := range []struct {
+		addr   common.Address
+		blocks uint64
+	}{
+		{miner1, 4},
+		{miner2, 2},
+		{miner3, 1},
+	} {
+		assert.Equal(t, want.addr, withoutRewards[i].Address, "rank %d", i)
+		assert.Equal(t, want.blocks, withoutRewards[i].BlockCount, "rank %d", i)
+		assert.Equal(t, withRewards[i].Address, withoutRewards[i].Address, "rank %d", i)
+		assert.Equal(t, withRewards[i].Percentage, withoutRewards[i].Percentage, "rank %d", i)
+		assert.Equal(t, withRewards[i].LastBlockNumber, withoutRewards[i].LastBlockNumber, "rank %d", i)
+
+		wantRewards := new(big.Int).Mul(fee, new(big.Int).SetUint64(want.blocks))
+		assert.Equal(t, 0, withRewards[i].TotalRewards.Cmp(wantRewards), "rank %d rewards = %s, want %s", i, withRewards[i].TotalRewards, wantRewards)
+		assert.Equal(t, 0, withoutRewards[i].TotalRewards.Sign(), "rank %d rewards should be skipped", i)
+	}
+}
+
+func BenchmarkPebbleStorage_GetTopMiners(b *testing.B) {
+	storage := setupTestPebbleStorage(b)
+
+	miners := make([]common.Address, 500)
+	for i := range miners {
+		miners[i] = common.BigToAddress(big.NewInt(int64(i%20 + 1)))
+	}
+	indexMinedBlocksWithFees(b, storage, miners, big.NewInt(1000000000))
+
+	ctx := context.Background()
+	for _, bc := range []struct {
+		name string
+		opts MinerStatsOptions
+	}{
+		{"WithRewards", MinerStatsOptions{}},
+		{"SkipRewards", MinerStatsOptions{SkipRewards: true}},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, _ = storage.GetTopMinersWithOptions(ctx, 10, 0, 0, bc.opts)
+			}
+		})
+	}
+}
+
+// indexBlocksWithTransfers stores n blocks mined round-robin by miners, each
+// with one transaction whose receipt logs an ERC20 Transfer of height+1 tokens
+// between holder and another address, alternating direction and token contract
+func indexBlocksWithTransfers(tb testing.TB, storage *PebbleStorage, n int, miners []common.Address, holder common.Address) {
+	tb.Helper()
+	ctx := context.Background()
+
+	privateKey, err := crypto.GenerateKey()
+	require.NoError(tb, err)
+	to := common.HexToAddress("0x1234567890123456789012345678901234567890")
+	tokens := []common.Address{
+		common.HexToAddress("0xBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB"),
+		common.HexToAddress("0xCCCCCCCCCCCCCCCCCCCCCCCCCCCCCCCCCCCCCCCC"),
+		common.HexToAddress("0xDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDD"),
+	}
+	counterparty := common.BytesToHash(common.LeftPadBytes(to.Bytes(), 32))
+	holderTopic := common.BytesToHash(common.LeftPadBytes(holder.Bytes(), 32))
+
+	for i := 0; i < n; i++ {
+		tx, err := createSignedTransaction(uint64(i), to, big.NewInt(1), big.NewInt(int64(i%7+1)), privateKey)
+		require.NoError(tb, err)
+
+		header := &types.Header{
+			Coinbase:    miners[i%len(miners)],
+			Number:      big.NewInt(int64(i)),
+			GasLimit:    5000000,
+			GasUsed:     21000,
+			Time:        uint64(1000 + i),
+			Difficulty:  big.NewInt(0),
+			UncleHash:   types.EmptyUncleHash,
+			TxHash:      types.EmptyTxsHash,
+			ReceiptHash: types.EmptyReceiptsHash,
+			Extra:       []byte{},
+		}
+		block := types.NewBlock(header, &types.Body{Transactions: []*types.Transaction{tx}}, nil, trie.NewStackTrie(nil))
+
+		from, recipient := counterparty, holderTopic
+		if i%3 == 0 {
+			from, recipient = holderTopic, counterparty
+		}
+		receipt := &types.Receipt{
+			Type:              types.LegacyTxType,
+			Status:            types.ReceiptStatusSuccessful,
+			CumulativeGasUsed: 21000,
+			GasUsed:           21000,
+			TxHash:            tx.Hash(),
+			BlockNumber:       big.NewInt(int64(i)),
+			Logs: []*types.Log{{
+				Address: tokens[i%len(tokens)],
+				Topics:  []common.Hash{transferEventTopic, from, recipient},
+				Data:    common.LeftPadBytes(big.NewInt(int64(i+1)).Bytes(), 32),
+			}},
+		}
+		require.NoError(tb, storage.SetBlockWithReceipts(ctx, block, []*types.Receipt{receipt}))
+	}
+	require.NoError(tb, storage.SetLatestHeight(ctx, uint64(n-1)))
+}
+
+func TestPebbleStorage_ParallelScans_MatchSerial(t *testing.T) {
+	s, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	storage := s.(*PebbleStorage)
+	ctx := context.Background()
+
+	miners := make([]common.Address, 7)
+	for i := range miners {
+		miners[i] = common.BigToAddress(big.NewInt(int64(i + 1)))
+	}
+	holder := common.HexToAddress("0xAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA")
+	// Enough blocks for several scan chunks, with a partial last chunk
+	indexBlocksWithTransfers(t, storage, 2*scanChunkSize+345, miners, holder)
+
+	scan := func(t *testing.T, workers int) (map[common.Address]MinerStats, map[common.Address]string) {
+		t.Helper()
+		storage.config.ScanWorkers = workers
+
+		minerStats, err := storage.GetTopMiners(ctx, len(miners), 0, 0)
+		require.NoError(t, err)
+		byMiner := make(map[common.Address]MinerStats, len(minerStats))
+		for _, stats := range minerStats {
+			byMiner[stats.Address] = stats
+		}
+
+		balances, err := storage.GetTokenBalances(ctx, holder, "")
+		require.NoError(t, err)
+		byToken := make(map[common.Address]string, len(balances))
+		for _, balance := range balances {
+			byToken[balance.ContractAddress] = balance.Balance.String()
+		}
+		return byMiner, byToken
+	}
+
+	serialMiners, serialBalances := scan(t, 1)
+	require.Len(t, serialMiners, len(miners))
+	require.NotEmpty(t, serialBalances)
+
+	for _, workers := range []int{2, 4, 16} {
+		parallelMiners, parallelBalances := scan(t, workers)
+		assert.Equal(t, serialMiners, parallelMiners, "workers=%d", workers)
+		assert.Equal(t, serialBalances, parallelBalances, "workers=%d", workers)
+	}
+}
+
+func TestScanBlockRange(t *testing.T) {
+	ctx := context.Background()
+	newShard := func() map[uint64]int { return make(map[uint64]int) }
+	visit := func(shard map[uint64]int, height uint64) { shard[height]++ }
+
+	for _, tc := range []struct {
+		name       string
+		workers    int
+		start, end uint64
+	}{
+		{"serial", 1, 3, 3*scanChunkSize + 17},
+		{"parallel", 4, 3, 3*scanChunkSize + 17},
+		{"single chunk", 4, 10, 10 + scanChunkSize/2},
+		{"single height", 4, 7, 7},
+		{"more workers than chunks", 16, 0, 2 * scanChunkSize},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			shards, err := scanBlockRange(ctx, tc.workers, tc.start, tc.end, newShard, visit)
+			require.NoError(t, err)
+
+			visited := make(map[uint64]int)
+			for _, shard := range shards {
+				for height, n := range shard {
+					visited[height] += n
+				}
+			}
+			require.Len(t, visited, int(tc.end-tc.start+1))
+			for height := tc.start; height <= tc.end; height++ {
+				require.Equal(t, 1, visited[height], "height %d", height)
+			}
+		})
+	}
+
+	t.Run("cancelled", func(t *testing.T) {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		for _, workers := range []int{1, 4} {
+			_, err := scanBlockRange(cancelled, workers, 0, 10*scanChunkSize, newShard, visit)
+			assert.ErrorIs(t, err, context.Canceled, "workers=%d", workers)
+		}
+	})
+}
+
+func BenchmarkPebbleStorage_ParallelScans(b *testing.B) {
+	storage := setupTestPebbleStorage(b)
+
+	miners := make([]common.Address, 20)
+	for i := range miners {
+		miners[i] = common.BigToAddress(big.NewInt(int64(i + 1)))
+	}
+	holder := common.HexToAddress("0xAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA")
+	indexBlocksWithTransfers(b, storage, 10000, miners, holder)
+
+	ctx := context.Background()
+	for _, workers := range []int{1, 2, 4, 8} {
+		storage.config.ScanWorkers = workers
+		b.Run(fmt.Sprintf("GetTopMiners/Workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, _ = storage.GetTopMiners(ctx, 10, 0, 0)
+			}
+		})
+		b.Run(fmt.Sprintf("GetTokenBalances/Workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, _ = storage.GetTokenBalances(ctx, holder, "")
+			}
+		})
+	}
+}
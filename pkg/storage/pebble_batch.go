@@ -2,7 +2,9 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/big"
 	"sync"
 
 	"github.com/cockroachdb/pebble"
@@ -10,17 +12,26 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 )
 
-// Ensure pebbleBatch implements Batch interface
-var _ Batch = (*pebbleBatch)(nil)
+// Ensure pebbleBatch implements Batch, BalanceWriter and AddressIndexBatchWriter interfaces
+var (
+	_ Batch                   = (*pebbleBatch)(nil)
+	_ BalanceWriter           = (*pebbleBatch)(nil)
+	_ AddressIndexBatchWriter = (*pebbleBatch)(nil)
+)
 
 // pebbleBatch implements Batch interface
 type pebbleBatch struct {
 	storage *PebbleStorage
-	batch   *pebble.Batch
+	batch   *nsBatch
 	count   int
 	txCount uint64 // Number of transactions added in this batch
-	closed  bool
-	mu      sync.Mutex
+	// balances holds the latest balance written in this batch per address,
+	// since uncommitted writes are not visible to storage reads
+	balances map[common.Address]*big.Int
+	// invalidated lists read cache keys to drop once the batch commits
+	invalidated []string
+	closed      bool
+	mu          sync.Mutex
 }
 
 // SetLatestHeight adds set latest height operation to batch
@@ -51,15 +62,27 @@ func (b *pebbleBatch) SetBlock(ctx context.Context, block *types.Block) error {
 
 	encoded, err := EncodeBlock(block)
 	if err != nil {
-		return fmt.Errorf("failed to encode block: %w", err)
+		return fmt.Errorf("%w block: %w", ErrEncodeFailed, err)
 	}
 
 	height := block.Number().Uint64()
 
+	// Drop the hash index and transactions of a block this one replaces
+	replaced, err := b.storage.deleteReplacedBlockKeys(ctx, b.batch, block)
+	if err != nil {
+		return err
+	}
+
 	// Add block data to batch
 	if err := b.batch.Set(BlockKey(height), encoded, nil); err != nil {
 		return err
 	}
+	if err := setHeaderInBatch(b.batch, block); err != nil {
+		return err
+	}
+	if err := b.storage.setUnclesInBatch(b.batch, block); err != nil {
+		return err
+	}
 
 	// Add block hash index to batch
 	heightBytes := EncodeUint64(height)
@@ -68,6 +91,7 @@ func (b *pebbleBatch) SetBlock(ctx context.Context, block *types.Block) error {
 	}
 
 	b.count += 2
+	b.invalidated = append(b.invalidated, blockCacheKey(height), blockHashCacheKey(replaced))
 
 	// Store all transactions in the block
 	transactions := block.Transactions()
@@ -100,12 +124,12 @@ func (b *pebbleBatch) SetTransaction(ctx context.Context, tx *types.Transaction,
 
 	encoded, err := EncodeTransaction(tx)
 	if err != nil {
-		return fmt.Errorf("failed to encode transaction: %w", err)
+		return fmt.Errorf("%w transaction: %w", ErrEncodeFailed, err)
 	}
 
-	locEncoded, err := EncodeTxLocation(location)
+	locEncoded, err := EncodeTxLocation(location.withSender(tx))
 	if err != nil {
-		return fmt.Errorf("failed to encode location: %w", err)
+		return fmt.Errorf("%w location: %w", ErrEncodeFailed, err)
 	}
 
 	if err := b.batch.Set(TransactionKey(location.BlockHeight, location.TxIndex), encoded, nil); err != nil {
@@ -116,6 +140,7 @@ func (b *pebbleBatch) SetTransaction(ctx context.Context, tx *types.Transaction,
 	}
 	b.count += 2
 	b.txCount++ // Increment transaction count
+	b.invalidated = append(b.invalidated, receiptCacheKey(tx.Hash()))
 	return nil
 }
 
@@ -135,7 +160,7 @@ func (b *pebbleBatch) SetReceipt(ctx context.Context, receipt *types.Receipt) er
 
 	encoded, err := EncodeReceipt(receipt)
 	if err != nil {
-		return fmt.Errorf("failed to encode receipt: %w", err)
+		return fmt.Errorf("%w receipt: %w", ErrEncodeFailed, err)
 	}
 
 	if err := b.batch.Set(ReceiptKey(receipt.TxHash), encoded, nil); err != nil {
@@ -150,6 +175,7 @@ func (b *pebbleBatch) SetReceipt(ctx context.Context, receipt *types.Receipt) er
 	}
 
 	b.count++
+	b.invalidated = append(b.invalidated, receiptCacheKey(receipt.TxHash))
 	return nil
 }
 
@@ -173,9 +199,11 @@ func (b *pebbleBatch) AddTransactionToAddressIndex(ctx context.Context, addr com
 	}
 
 	b.storage.addrSeqMu.Lock()
-	seq := b.storage.addrSeq[addr]
-	b.storage.addrSeq[addr]++
+	seq, err := b.storage.nextAddressSeqLocked(addr)
 	b.storage.addrSeqMu.Unlock()
+	if err != nil {
+		return err
+	}
 
 	key := AddressTransactionKey(addr, seq)
 	if err := b.batch.Set(key, txHash[:], nil); err != nil {
@@ -185,6 +213,107 @@ func (b *pebbleBatch) AddTransactionToAddressIndex(ctx context.Context, addr com
 	return nil
 }
 
+// AddTransactionsToAddressIndex adds many transactions to their address indexes in
+// batch, reserving sequence numbers once for all entries
+func (b *pebbleBatch) AddTransactionsToAddressIndex(ctx context.Context, entries []AddressTransactionEntry) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return ErrClosed
+	}
+
+	seqs, err := b.storage.reserveAddressSequences(entries)
+	if err != nil {
+		return err
+	}
+	for i, seq := range seqs {
+		entry := entries[i]
+		if err := b.batch.Set(AddressTransactionKey(entry.Address, seq), entry.TxHash[:], nil); err != nil {
+			return err
+		}
+		b.count++
+	}
+	return nil
+}
+
+// UpdateBalance adds a balance history entry and the new latest balance to the batch
+// Both keys are written by the same commit, so they cannot diverge after a crash
+func (b *pebbleBatch) UpdateBalance(ctx context.Context, addr common.Address, blockNumber uint64, delta *big.Int, txHash common.Hash) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return ErrClosed
+	}
+
+	currentBalance, err := b.currentBalance(ctx, addr)
+	if err != nil {
+		return err
+	}
+
+	newBalance := new(big.Int).Add(currentBalance, delta)
+	if newBalance.Sign() < 0 {
+		return fmt.Errorf("balance cannot be negative")
+	}
+
+	encoded, err := EncodeBalanceSnapshot(&BalanceSnapshot{
+		BlockNumber: blockNumber,
+		Balance:     newBalance,
+		Delta:       delta,
+		TxHash:      txHash,
+	})
+	if err != nil {
+		return fmt.Errorf("%w snapshot: %w", ErrEncodeFailed, err)
+	}
+
+	seq, err := b.storage.nextBalanceSeq(addr)
+	if err != nil {
+		return err
+	}
+
+	if err := b.batch.Set(AddressBalanceKey(addr, seq), encoded, nil); err != nil {
+		return fmt.Errorf("failed to set balance history: %w", err)
+	}
+	if err := b.batch.Set(AddressBalanceLatestKey(addr), EncodeBigInt(newBalance), nil); err != nil {
+		return fmt.Errorf("failed to set latest balance: %w", err)
+	}
+
+	if b.balances == nil {
+		b.balances = make(map[common.Address]*big.Int)
+	}
+	b.balances[addr] = newBalance
+	b.count += 2
+	return nil
+}
+
+// SetBalance adds a balance change that brings the address to the given balance
+func (b *pebbleBatch) SetBalance(ctx context.Context, addr common.Address, blockNumber uint64, balance *big.Int) error {
+	b.mu.Lock()
+	currentBalance, err := b.currentBalance(ctx, addr)
+	b.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	delta := new(big.Int).Sub(balance, currentBalance)
+	return b.UpdateBalance(ctx, addr, blockNumber, delta, common.Hash{})
+}
+
+// currentBalance returns the latest balance including changes pending in this batch
+// Caller must hold b.mu
+func (b *pebbleBatch) currentBalance(ctx context.Context, addr common.Address) (*big.Int, error) {
+	if balance, ok := b.balances[addr]; ok {
+		return balance, nil
+	}
+
+	balance, err := b.storage.GetAddressBalance(ctx, addr, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current balance: %w", err)
+	}
+	return balance, nil
+}
+
 // SetBlocks adds multiple set block operations to batch
 func (b *pebbleBatch) SetBlocks(ctx context.Context, blocks []*types.Block) error {
 	for _, block := range blocks {
@@ -210,7 +339,7 @@ func (b *pebbleBatch) DeleteBlock(ctx context.Context, height uint64) error {
 	b.mu.Lock()
 
 	if err != nil {
-		if err == ErrNotFound {
+		if errors.Is(err, ErrNotFound) {
 			return nil // Already deleted
 		}
 		return fmt.Errorf("failed to get block for deletion: %w", err)
@@ -222,11 +351,18 @@ func (b *pebbleBatch) DeleteBlock(ctx context.Context, height uint64) error {
 	}
 
 	// Delete block data
+	if err := b.batch.Delete(HeaderKey(height), nil); err != nil {
+		return err
+	}
+	if err := deleteUnclesInBatch(b.batch, height); err != nil {
+		return err
+	}
 	if err := b.batch.Delete(BlockKey(height), nil); err != nil {
 		return err
 	}
 
 	b.count += 2
+	b.invalidated = append(b.invalidated, blockCacheKey(height), blockHashCacheKey(block.Hash()))
 	return nil
 }
 
@@ -239,18 +375,12 @@ func (b *pebbleBatch) Commit() error {
 		return ErrClosed
 	}
 
-	// Update transaction count using atomic counter for performance
-	if b.txCount > 0 {
-		// Use atomic Add for lock-free counter update
-		newCount := b.storage.txCount.Add(b.txCount)
-		if err := b.batch.Set(TransactionCountKey(), EncodeUint64(newCount), nil); err != nil {
-			// Rollback atomic counter on error
-			b.storage.txCount.Add(^(b.txCount - 1)) // Subtract txCount
-			return fmt.Errorf("failed to update transaction count: %w", err)
-		}
+	if err := b.storage.commitWithTxCount(b.batch, b.txCount, pebble.Sync); err != nil {
+		return err
 	}
-
-	return b.batch.Commit(pebble.Sync)
+	b.storage.readCache.remove(b.invalidated...)
+	b.invalidated = nil
+	return nil
 }
 
 // Reset clears all operations in the batch
@@ -261,6 +391,8 @@ func (b *pebbleBatch) Reset() {
 	b.batch.Reset()
 	b.count = 0
 	b.txCount = 0
+	b.balances = nil
+	b.invalidated = nil
 }
 
 // Count returns the number of operations in the batch
@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var _ BlockHeaderReader = (*PebbleStorage)(nil)
+
+// GetBlockHeader returns the header of the block at height without decoding
+// its transactions. Blocks stored before headers were kept separately fall
+// back to decoding just the header from the full block encoding.
+func (s *PebbleStorage) GetBlockHeader(ctx context.Context, height uint64) (*types.Header, error) {
+	if err := s.ensureNotClosed(); err != nil {
+		return nil, err
+	}
+
+	// A cached full block already has its header decoded
+	if cached, _, ok := s.readCache.get(blockCacheKey(height)); ok {
+		return cached.(*types.Block).Header(), nil
+	}
+
+	value, closer, err := s.db.Get(HeaderKey(height))
+	if err == nil {
+		defer closer.Close()
+		return DecodeHeader(value)
+	}
+	if !errors.Is(err, pebble.ErrNotFound) {
+		return nil, fmt.Errorf("failed to get block header: %w", err)
+	}
+
+	value, closer, err = s.db.Get(BlockKey(height))
+	if err != nil {
+		if errors.Is(err, pebble.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get block: %w", err)
+	}
+	defer closer.Close()
+
+	return DecodeBlockHeader(value)
+}
+
+// setHeaderInBatch stores the header of block under its own key, next to the
+// full block
+func setHeaderInBatch(batch *nsBatch, block *types.Block) error {
+	encoded, err := EncodeHeader(block.Header())
+	if err != nil {
+		return err
+	}
+	if err := batch.Set(HeaderKey(block.NumberU64()), encoded, nil); err != nil {
+		return fmt.Errorf("failed to set block header: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/pebble"
+)
+
+func TestPebbleStorage_GetBlockHeader(t *testing.T) {
+	storage := setupTestPebbleStorage(t)
+
+	ctx := context.Background()
+	block := createTestBlockWithTransactions(7, 5)
+	if err := storage.SetBlock(ctx, block); err != nil {
+		t.Fatalf("SetBlock() error = %v", err)
+	}
+
+	t.Run("stored header", func(t *testing.T) {
+		header, err := storage.GetBlockHeader(ctx, 7)
+		if err != nil {
+			t.Fatalf("GetBlockHeader() error = %v", err)
+		}
+		if header.Hash() != block.Hash() || header.Time != block.Time() {
+			t.Errorf("GetBlockHeader() hash %s, want %s", header.Hash().Hex(), block.Hash().Hex())
+		}
+	})
+
+	t.Run("legacy block without header key", func(t *testing.T) {
+		if err := storage.db.Delete(HeaderKey(7), pebble.Sync); err != nil {
+			t.Fatalf("Delete(HeaderKey) error = %v", err)
+		}
+		header, err := storage.GetBlockHeader(ctx, 7)
+		if err != nil {
+			t.Fatalf("GetBlockHeader() error = %v", err)
+		}
+		if header.Hash() != block.Hash() {
+			t.Errorf("GetBlockHeader() hash %s, want %s", header.Hash().Hex(), block.Hash().Hex())
+		}
+	})
+
+	t.Run("missing block", func(t *testing.T) {
+		if _, err := storage.GetBlockHeader(ctx, 8); !errors.Is(err, ErrNotFound) {
+			t.Errorf("GetBlockHeader(8) error = %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("deleted block", func(t *testing.T) {
+		if err := storage.SetBlock(ctx, block); err != nil {
+			t.Fatalf("SetBlock() error = %v", err)
+		}
+		if err := storage.DeleteBlock(ctx, 7); err != nil {
+			t.Fatalf("DeleteBlock() error = %v", err)
+		}
+		if _, err := storage.GetBlockHeader(ctx, 7); !errors.Is(err, ErrNotFound) {
+			t.Errorf("GetBlockHeader() after DeleteBlock error = %v, want ErrNotFound", err)
+		}
+	})
+}
+
+func TestDecodeBlockHeader(t *testing.T) {
+	block := createTestBlockWithTransactions(3, 4)
+	data, err := EncodeBlock(block)
+	if err != nil {
+		t.Fatalf("EncodeBlock() error = %v", err)
+	}
+
+	header, err := DecodeBlockHeader(data)
+	if err != nil {
+		t.Fatalf("DecodeBlockHeader() error = %v", err)
+	}
+	if header.Hash() != block.Hash() {
+		t.Errorf("DecodeBlockHeader() hash %s, want %s", header.Hash().Hex(), block.Hash().Hex())
+	}
+
+	if _, err := DecodeBlockHeader([]byte{0x01}); !errors.Is(err, ErrDecodeFailed) {
+		t.Errorf("DecodeBlockHeader(garbage) error = %v, want ErrDecodeFailed", err)
+	}
+}
+
+// BenchmarkPebbleStorage_HeaderOnlyRead compares reading a block timestamp
+// through GetBlock and GetBlockHeader for blocks of growing size
+func BenchmarkPebbleStorage_HeaderOnlyRead(b *testing.B) {
+	for _, txCount := range []int{0, 50, 500} {
+		storage := setupTestPebbleStorage(b)
+		ctx := context.Background()
+		if err := storage.SetBlock(ctx, createTestBlockWithTransactions(1, txCount)); err != nil {
+			b.Fatalf("SetBlock() error = %v", err)
+		}
+
+		b.Run(fmt.Sprintf("GetBlock/%d", txCount), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				block, _ := storage.GetBlock(ctx, 1)
+				_ = block.Time()
+			}
+		})
+
+		b.Run(fmt.Sprintf("GetBlockHeader/%d", txCount), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				header, _ := storage.GetBlockHeader(ctx, 1)
+				_ = header.Time
+			}
+		})
+
+		storage.Close()
+	}
+}
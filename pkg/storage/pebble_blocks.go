@@ -2,7 +2,9 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/big"
 
 	"github.com/cockroachdb/pebble"
 	"github.com/ethereum/go-ethereum/common"
@@ -30,7 +32,7 @@ func (s *PebbleStorage) GetLatestHeight(ctx context.Context) (uint64, error) {
 
 	height, err := DecodeUint64(value)
 	if err != nil {
-		return 0, fmt.Errorf("failed to decode height: %w", err)
+		return 0, fmt.Errorf("%w height: %w", ErrDecodeFailed, err)
 	}
 
 	return height, nil
@@ -50,11 +52,87 @@ func (s *PebbleStorage) SetLatestHeight(ctx context.Context, height uint64) erro
 	return s.db.Set(LatestHeightKey(), value, pebble.NoSync)
 }
 
-// Sync forces a sync of all pending writes to disk
+// GetChainID returns the stored chain ID of the indexed chain
+func (s *PebbleStorage) GetChainID(ctx context.Context) (*big.Int, error) {
+	if err := s.ensureNotClosed(); err != nil {
+		return nil, err
+	}
+
+	value, closer, err := s.db.Get(ChainIDKey())
+	if err != nil {
+		if err == pebble.ErrNotFound {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get chain ID: %w", err)
+	}
+	defer closer.Close()
+
+	return new(big.Int).SetBytes(value), nil
+}
+
+// SetChainID stores the chain ID of the indexed chain
+func (s *PebbleStorage) SetChainID(ctx context.Context, chainID *big.Int) error {
+	if err := s.ensureNotClosed(); err != nil {
+		return err
+	}
+	if err := s.ensureNotReadOnly(); err != nil {
+		return err
+	}
+	if chainID == nil || chainID.Sign() < 0 {
+		return fmt.Errorf("invalid chain ID: %v", chainID)
+	}
+
+	return s.db.Set(ChainIDKey(), chainID.Bytes(), pebble.Sync)
+}
+
+// GetChainHead returns the last observed chain head height
+func (s *PebbleStorage) GetChainHead(ctx context.Context) (uint64, error) {
+	if err := s.ensureNotClosed(); err != nil {
+		return 0, err
+	}
+
+	value, closer, err := s.db.Get(ChainHeadKey())
+	if err != nil {
+		if err == pebble.ErrNotFound {
+			return 0, ErrNotFound
+		}
+		return 0, fmt.Errorf("failed to get chain head: %w", err)
+	}
+	defer closer.Close()
+
+	height, err := DecodeUint64(value)
+	if err != nil {
+		return 0, fmt.Errorf("%w chain head: %w", ErrDecodeFailed, err)
+	}
+
+	return height, nil
+}
+
+// SetChainHead records the last observed chain head height
+func (s *PebbleStorage) SetChainHead(ctx context.Context, height uint64) error {
+	if err := s.ensureNotClosed(); err != nil {
+		return err
+	}
+	if err := s.ensureNotReadOnly(); err != nil {
+		return err
+	}
+
+	// Refreshed on every poll, so NoSync like the latest indexed height
+	return s.db.Set(ChainHeadKey(), EncodeUint64(height), pebble.NoSync)
+}
+
+// Ensure PebbleStorage implements Syncer
+var _ Syncer = (*PebbleStorage)(nil)
+
+// Sync forces a sync of all pending writes to disk. It is a no-op in
+// read-only mode, where there are no writes to flush.
 func (s *PebbleStorage) Sync() error {
 	if err := s.ensureNotClosed(); err != nil {
 		return err
 	}
+	if s.config.ReadOnly {
+		return nil
+	}
 	return s.db.Flush()
 }
 
@@ -64,6 +142,12 @@ func (s *PebbleStorage) GetBlock(ctx context.Context, height uint64) (*types.Blo
 		return nil, err
 	}
 
+	cacheKey := blockCacheKey(height)
+	cached, generation, ok := s.readCache.get(cacheKey)
+	if ok {
+		return cached.(*types.Block), nil
+	}
+
 	value, closer, err := s.db.Get(BlockKey(height))
 	if err != nil {
 		if err == pebble.ErrNotFound {
@@ -75,9 +159,10 @@ func (s *PebbleStorage) GetBlock(ctx context.Context, height uint64) (*types.Blo
 
 	block, err := DecodeBlock(value)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode block: %w", err)
+		return nil, fmt.Errorf("%w block: %w", ErrDecodeFailed, err)
 	}
 
+	s.readCache.add(cacheKey, block, generation)
 	return block, nil
 }
 
@@ -87,6 +172,13 @@ func (s *PebbleStorage) GetBlockByHash(ctx context.Context, hash common.Hash) (*
 		return nil, err
 	}
 
+	// Cache the hash -> height lookup; the block itself is cached by height
+	cacheKey := blockHashCacheKey(hash)
+	cached, generation, ok := s.readCache.get(cacheKey)
+	if ok {
+		return s.getBlockWithHash(ctx, cached.(uint64), hash)
+	}
+
 	// Get block height from hash index
 	value, closer, err := s.db.Get(BlockHashIndexKey(hash))
 	if err != nil {
@@ -99,14 +191,64 @@ func (s *PebbleStorage) GetBlockByHash(ctx context.Context, hash common.Hash) (*
 
 	height, err := DecodeUint64(value)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode block height: %w", err)
+		return nil, fmt.Errorf("%w block height: %w", ErrDecodeFailed, err)
 	}
+	s.readCache.add(cacheKey, height, generation)
 
 	// Get block by height
-	return s.GetBlock(ctx, height)
+	return s.getBlockWithHash(ctx, height, hash)
 }
 
-// SetBlock stores a block
+// getBlockWithHash returns the block at height only if it still has hash. The
+// hash index and the block are separate reads, so the block may have been
+// deleted or replaced by a reorg in between; that is reported as ErrNotFound
+// rather than returning a different block.
+func (s *PebbleStorage) getBlockWithHash(ctx context.Context, height uint64, hash common.Hash) (*types.Block, error) {
+	block, err := s.GetBlock(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	if block.Hash() != hash {
+		return nil, ErrNotFound
+	}
+	return block, nil
+}
+
+// deleteReplacedBlockKeys removes the hash index entry, transaction keys and
+// uncles of the block currently stored at block's height when block replaces
+// it with a different hash, as happens on a reorg. Deleting the transaction
+// keys keeps a replaced block with more transactions from leaving its surplus
+// keys behind; the caller writes block's own keys after this in the same
+// batch. It returns the replaced hash, or the zero hash if the height was
+// empty or held the same block.
+func (s *PebbleStorage) deleteReplacedBlockKeys(ctx context.Context, batch *nsBatch, block *types.Block) (common.Hash, error) {
+	header, err := s.GetBlockHeader(ctx, block.NumberU64())
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return common.Hash{}, nil
+		}
+		return common.Hash{}, fmt.Errorf("failed to get replaced block header: %w", err)
+	}
+
+	replaced := header.Hash()
+	if replaced == block.Hash() {
+		return common.Hash{}, nil
+	}
+	if err := batch.Delete(BlockHashIndexKey(replaced), nil); err != nil {
+		return common.Hash{}, fmt.Errorf("failed to delete replaced block hash index: %w", err)
+	}
+	txPrefix := TransactionKeyPrefix(block.NumberU64())
+	if err := batch.DeleteRange(txPrefix, prefixUpperBound(txPrefix), nil); err != nil {
+		return common.Hash{}, fmt.Errorf("failed to delete replaced block transactions: %w", err)
+	}
+	if err := s.deleteReplacedUnclesInBatch(batch, block.NumberU64()); err != nil {
+		return common.Hash{}, err
+	}
+	return replaced, nil
+}
+
+// SetBlock stores a block with its hash index and transactions in a single
+// batch, so a failure partway through leaves no partially indexed block
 func (s *PebbleStorage) SetBlock(ctx context.Context, block *types.Block) error {
 	if err := s.ensureNotClosed(); err != nil {
 		return err
@@ -121,35 +263,57 @@ func (s *PebbleStorage) SetBlock(ctx context.Context, block *types.Block) error
 
 	encoded, err := EncodeBlock(block)
 	if err != nil {
-		return fmt.Errorf("failed to encode block: %w", err)
+		return fmt.Errorf("%w block: %w", ErrEncodeFailed, err)
 	}
 
 	height := block.Number().Uint64()
 
-	// Store block data - use NoSync for performance
-	if err := s.db.Set(BlockKey(height), encoded, pebble.NoSync); err != nil {
+	batch := s.db.NewBatch()
+	defer batch.Close()
+
+	replaced, err := s.deleteReplacedBlockKeys(ctx, batch, block)
+	if err != nil {
+		return err
+	}
+	if err := batch.Set(BlockKey(height), encoded, nil); err != nil {
 		return fmt.Errorf("failed to set block: %w", err)
 	}
+	if err := setHeaderInBatch(batch, block); err != nil {
+		return err
+	}
+	if err := s.setUnclesInBatch(batch, block); err != nil {
+		return err
+	}
 
-	// Store block hash index
 	heightBytes := EncodeUint64(height)
-	if err := s.db.Set(BlockHashIndexKey(block.Hash()), heightBytes, pebble.NoSync); err != nil {
+	if err := batch.Set(BlockHashIndexKey(block.Hash()), heightBytes, nil); err != nil {
 		return fmt.Errorf("failed to set block hash index: %w", err)
 	}
 
-	// Store all transactions in the block
+	// Add all transactions in the block
 	transactions := block.Transactions()
 	for txIndex, tx := range transactions {
-		location := &TxLocation{
+		if err := s.setTransactionInBatch(batch, tx, &TxLocation{
 			BlockHeight: height,
 			TxIndex:     uint64(txIndex),
 			BlockHash:   block.Hash(),
-		}
-		if err := s.SetTransaction(ctx, tx, location); err != nil {
+		}); err != nil {
 			return fmt.Errorf("failed to store transaction %d in block %d: %w", txIndex, height, err)
 		}
 	}
 
+	// Use NoSync for performance, with the transaction count
+	if err := s.commitWithTxCount(batch, uint64(len(transactions)), pebble.NoSync); err != nil {
+		return err
+	}
+
+	// Cached receipts of the block's transactions lack their new location
+	invalidated := make([]string, 0, len(transactions)+2)
+	invalidated = append(invalidated, blockCacheKey(height), blockHashCacheKey(replaced))
+	for _, tx := range transactions {
+		invalidated = append(invalidated, receiptCacheKey(tx.Hash()))
+	}
+	s.readCache.remove(invalidated...)
 	return nil
 }
 
@@ -181,13 +345,23 @@ func (s *PebbleStorage) SetBlockWithReceipts(ctx context.Context, block *types.B
 	// Encode and add block
 	encoded, err := EncodeBlock(block)
 	if err != nil {
-		return fmt.Errorf("failed to encode block: %w", err)
+		return fmt.Errorf("%w block: %w", ErrEncodeFailed, err)
 	}
 
 	height := block.Number().Uint64()
+	replaced, err := s.deleteReplacedBlockKeys(ctx, batch, block)
+	if err != nil {
+		return err
+	}
 	if err := batch.Set(BlockKey(height), encoded, nil); err != nil {
 		return fmt.Errorf("failed to set block: %w", err)
 	}
+	if err := setHeaderInBatch(batch, block); err != nil {
+		return err
+	}
+	if err := s.setUnclesInBatch(batch, block); err != nil {
+		return err
+	}
 
 	heightBytes := EncodeUint64(height)
 	if err := batch.Set(BlockHashIndexKey(block.Hash()), heightBytes, nil); err != nil {
@@ -202,7 +376,7 @@ func (s *PebbleStorage) SetBlockWithReceipts(ctx context.Context, block *types.B
 		// Encode transaction
 		txEncoded, err := EncodeTransaction(tx)
 		if err != nil {
-			return fmt.Errorf("failed to encode transaction: %w", err)
+			return fmt.Errorf("%w transaction: %w", ErrEncodeFailed, err)
 		}
 
 		location := &TxLocation{
@@ -210,9 +384,9 @@ func (s *PebbleStorage) SetBlockWithReceipts(ctx context.Context, block *types.B
 			TxIndex:     uint64(txIndex),
 			BlockHash:   block.Hash(),
 		}
-		locEncoded, err := EncodeTxLocation(location)
+		locEncoded, err := EncodeTxLocation(location.withSender(tx))
 		if err != nil {
-			return fmt.Errorf("failed to encode location: %w", err)
+			return fmt.Errorf("%w location: %w", ErrEncodeFailed, err)
 		}
 
 		if err := batch.Set(TransactionKey(height, uint64(txIndex)), txEncoded, nil); err != nil {
@@ -230,7 +404,7 @@ func (s *PebbleStorage) SetBlockWithReceipts(ctx context.Context, block *types.B
 
 			receiptEncoded, err := EncodeReceipt(receipt)
 			if err != nil {
-				return fmt.Errorf("failed to encode receipt: %w", err)
+				return fmt.Errorf("%w receipt: %w", ErrEncodeFailed, err)
 			}
 			if err := batch.Set(ReceiptKey(tx.Hash()), receiptEncoded, nil); err != nil {
 				return fmt.Errorf("failed to set receipt: %w", err)
@@ -245,19 +419,23 @@ func (s *PebbleStorage) SetBlockWithReceipts(ctx context.Context, block *types.B
 		}
 	}
 
-	// Update transaction count atomically
-	newCount := s.txCount.Add(txCountDelta)
-	if err := batch.Set(TransactionCountKey(), EncodeUint64(newCount), nil); err != nil {
-		return fmt.Errorf("failed to update transaction count: %w", err)
-	}
-
 	// Update latest height
 	if err := batch.Set(LatestHeightKey(), heightBytes, nil); err != nil {
 		return fmt.Errorf("failed to set latest height: %w", err)
 	}
 
-	// Single Sync at the end
-	return batch.Commit(pebble.Sync)
+	// Single Sync at the end, with the transaction count
+	if err := s.commitWithTxCount(batch, txCountDelta, pebble.Sync); err != nil {
+		return err
+	}
+
+	invalidated := make([]string, 0, len(block.Transactions())+2)
+	invalidated = append(invalidated, blockCacheKey(height), blockHashCacheKey(replaced))
+	for _, tx := range block.Transactions() {
+		invalidated = append(invalidated, receiptCacheKey(tx.Hash()))
+	}
+	s.readCache.remove(invalidated...)
+	return nil
 }
 
 // GetBlocks returns multiple blocks by height range
@@ -271,7 +449,7 @@ func (s *PebbleStorage) GetBlocks(ctx context.Context, startHeight, endHeight ui
 	for height := startHeight; height <= endHeight; height++ {
 		block, err := s.GetBlock(ctx, height)
 		if err != nil {
-			if err == ErrNotFound {
+			if errors.Is(err, ErrNotFound) {
 				continue // Skip missing blocks
 			}
 			return nil, fmt.Errorf("failed to get block %d: %w", height, err)
@@ -282,6 +460,28 @@ func (s *PebbleStorage) GetBlocks(ctx context.Context, startHeight, endHeight ui
 	return blocks, nil
 }
 
+// Ensure PebbleStorage implements BlockRangeReader
+var _ BlockRangeReader = (*PebbleStorage)(nil)
+
+// GetBlocksWithMissing returns the blocks in a height range along with the
+// heights that have no block stored
+func (s *PebbleStorage) GetBlocksWithMissing(ctx context.Context, startHeight, endHeight uint64) ([]*types.Block, []uint64, error) {
+	if err := s.ensureNotClosed(); err != nil {
+		return nil, nil, err
+	}
+	return BlocksWithMissing(ctx, s, startHeight, endHeight)
+}
+
+// GetLatestBlocks returns the headers of up to n of the most recent blocks as
+// header-only blocks, newest first. Recently indexed blocks are usually still
+// in the read cache.
+func (s *PebbleStorage) GetLatestBlocks(ctx context.Context, n int) ([]*types.Block, error) {
+	if err := s.ensureNotClosed(); err != nil {
+		return nil, err
+	}
+	return LatestBlocks(ctx, s, n)
+}
+
 // SetBlocks stores multiple blocks atomically
 func (s *PebbleStorage) SetBlocks(ctx context.Context, blocks []*types.Block) error {
 	if err := s.ensureNotClosed(); err != nil {
@@ -303,7 +503,9 @@ func (s *PebbleStorage) SetBlocks(ctx context.Context, blocks []*types.Block) er
 	return batch.Commit()
 }
 
-// DeleteBlock removes a block
+// DeleteBlock removes a block, its header and its hash index in a single
+// batch. Readers never see the block half-deleted: a concurrent GetBlock or
+// GetBlockByHash returns either the whole block or ErrNotFound.
 func (s *PebbleStorage) DeleteBlock(ctx context.Context, height uint64) error {
 	if err := s.ensureNotClosed(); err != nil {
 		return err
@@ -315,19 +517,33 @@ func (s *PebbleStorage) DeleteBlock(ctx context.Context, height uint64) error {
 	// Get block to find its hash
 	block, err := s.GetBlock(ctx, height)
 	if err != nil {
-		if err == ErrNotFound {
+		if errors.Is(err, ErrNotFound) {
 			return nil // Already deleted
 		}
 		return fmt.Errorf("failed to get block for deletion: %w", err)
 	}
 
-	// Delete block hash index
-	if err := s.db.Delete(BlockHashIndexKey(block.Hash()), pebble.Sync); err != nil {
+	batch := s.db.NewBatch()
+	defer batch.Close()
+
+	if err := batch.Delete(BlockHashIndexKey(block.Hash()), nil); err != nil {
 		return fmt.Errorf("failed to delete block hash index: %w", err)
 	}
+	if err := batch.Delete(HeaderKey(height), nil); err != nil {
+		return fmt.Errorf("failed to delete block header: %w", err)
+	}
+	if err := deleteUnclesInBatch(batch, height); err != nil {
+		return err
+	}
+	if err := batch.Delete(BlockKey(height), nil); err != nil {
+		return fmt.Errorf("failed to delete block: %w", err)
+	}
+	if err := batch.Commit(pebble.Sync); err != nil {
+		return fmt.Errorf("failed to commit block deletion: %w", err)
+	}
 
-	// Delete block data
-	return s.db.Delete(BlockKey(height), pebble.Sync)
+	s.readCache.remove(blockCacheKey(height), blockHashCacheKey(block.Hash()))
+	return nil
 }
 
 // HasBlock checks if a block exists at given height
@@ -0,0 +1,324 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// TestPebbleStorage_DeleteBlock_ConcurrentReads deletes and replaces blocks
+// while readers look them up by height and hash, and checks that no reader
+// observes a half-deleted block or a block other than the one it asked for
+func TestPebbleStorage_DeleteBlock_ConcurrentReads(t *testing.T) {
+	for _, cacheSize := range []int{0, 100} {
+		cfg := DefaultConfig(t.TempDir())
+		cfg.ReadCacheSize = cacheSize
+		storage, err := NewPebbleStorage(cfg)
+		if err != nil {
+			t.Fatalf("NewPebbleStorage() error = %v", err)
+		}
+
+		const heights = 8
+		ctx := context.Background()
+		// Two competing blocks per height, as after a reorg
+		var variants [heights][2]*types.Block
+		for h := uint64(0); h < heights; h++ {
+			variants[h][0] = createTestBlockWithTimestamp(t, h, 1000+h)
+			variants[h][1] = createTestBlockWithTimestamp(t, h, 2000+h)
+			if err := storage.SetBlock(ctx, variants[h][0]); err != nil {
+				t.Fatalf("SetBlock(%d) error = %v", h, err)
+			}
+		}
+
+		var stop atomic.Bool
+		var wg sync.WaitGroup
+		var failures atomic.Int64
+		fail := func(format string, args ...interface{}) {
+			if failures.Add(1) <= 5 {
+				t.Errorf(format, args...)
+			}
+		}
+
+		for r := 0; r < 4; r++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; !stop.Load(); i++ {
+					h := uint64(i % heights)
+					want := variants[h][i%2]
+
+					block, err := storage.GetBlockByHash(ctx, want.Hash())
+					switch {
+					case errors.Is(err, ErrNotFound):
+					case err != nil:
+						fail("GetBlockByHash(%d) error = %v", h, err)
+					case block.Hash() != want.Hash():
+						fail("GetBlockByHash(%s) returned block %s", want.Hash().Hex(), block.Hash().Hex())
+					}
+
+					block, err = storage.GetBlock(ctx, h)
+					switch {
+					case errors.Is(err, ErrNotFound):
+					case err != nil:
+						fail("GetBlock(%d) error = %v", h, err)
+					case block.Hash() != variants[h][0].Hash() && block.Hash() != variants[h][1].Hash():
+						fail("GetBlock(%d) returned unknown block %s", h, block.Hash().Hex())
+					}
+
+					header, err := storage.GetBlockHeader(ctx, h)
+					if err != nil && !errors.Is(err, ErrNotFound) {
+						fail("GetBlockHeader(%d) error = %v", h, err)
+					} else if err == nil && header.Number.Uint64() != h {
+						fail("GetBlockHeader(%d) returned height %d", h, header.Number.Uint64())
+					}
+				}
+			}()
+		}
+
+		for round := 0; round < 200; round++ {
+			h := uint64(round % heights)
+			if err := storage.DeleteBlock(ctx, h); err != nil {
+				t.Fatalf("DeleteBlock(%d) error = %v", h, err)
+			}
+			if err := storage.SetBlock(ctx, variants[h][(round/heights+1)%2]); err != nil {
+				t.Fatalf("SetBlock(%d) error = %v", h, err)
+			}
+		}
+		stop.Store(true)
+		wg.Wait()
+
+		// After deletion every key of the block is gone together
+		block := variants[0][0]
+		if existing, err := storage.GetBlock(ctx, 0); err == nil {
+			block = existing
+		}
+		if err := storage.DeleteBlock(ctx, 0); err != nil {
+			t.Fatalf("DeleteBlock(0) error = %v", err)
+		}
+		for _, key := range [][]byte{BlockKey(0), HeaderKey(0), BlockHashIndexKey(block.Hash())} {
+			if _, closer, err := storage.db.Get(key); err == nil {
+				closer.Close()
+				t.Errorf("key %s still present after DeleteBlock", key)
+			}
+		}
+		if _, err := storage.GetBlockByHash(ctx, block.Hash()); !errors.Is(err, ErrNotFound) {
+			t.Errorf("GetBlockByHash() after DeleteBlock error = %v, want ErrNotFound", err)
+		}
+
+		storage.Close()
+	}
+}
+
+func TestPebbleStorage_GetBlockByHash_StaleIndex(t *testing.T) {
+	storage := setupTestPebbleStorage(t)
+
+	ctx := context.Background()
+	original := createTestBlockWithTimestamp(t, 5, 1000)
+	replacement := createTestBlockWithTimestamp(t, 5, 2000)
+	if err := storage.SetBlock(ctx, original); err != nil {
+		t.Fatalf("SetBlock() error = %v", err)
+	}
+	if err := storage.SetBlock(ctx, replacement); err != nil {
+		t.Fatalf("SetBlock() error = %v", err)
+	}
+	// Restore the old hash index entry, as left behind by earlier versions
+	if err := storage.db.Set(BlockHashIndexKey(original.Hash()), EncodeUint64(5), nil); err != nil {
+		t.Fatalf("db.Set() error = %v", err)
+	}
+
+	if _, err := storage.GetBlockByHash(ctx, original.Hash()); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetBlockByHash(original) error = %v, want ErrNotFound", err)
+	}
+	block, err := storage.GetBlockByHash(ctx, replacement.Hash())
+	if err != nil || block.Hash() != replacement.Hash() {
+		t.Errorf("GetBlockByHash(replacement) = %v, %v; want the replacement", block, err)
+	}
+	if _, err := storage.GetBlockByHash(ctx, common.Hash{1}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetBlockByHash(unknown) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestPebbleStorage_SetBlock_ReplacesHashIndex(t *testing.T) {
+	writers := map[string]func(*PebbleStorage, *types.Block) error{
+		"SetBlock": func(s *PebbleStorage, b *types.Block) error {
+			return s.SetBlock(context.Background(), b)
+		},
+		"SetBlockWithReceipts": func(s *PebbleStorage, b *types.Block) error {
+			return s.SetBlockWithReceipts(context.Background(), b, nil)
+		},
+		"Batch": func(s *PebbleStorage, b *types.Block) error {
+			batch := s.NewBatch()
+			defer batch.Close()
+			if err := batch.SetBlock(context.Background(), b); err != nil {
+				return err
+			}
+			return batch.Commit()
+		},
+	}
+
+	for name, write := range writers {
+		t.Run(name, func(t *testing.T) {
+			storage := setupTestPebbleStorage(t)
+
+			ctx := context.Background()
+			original := createTestBlockWithTimestamp(t, 5, 1000)
+			replacement := createTestBlockWithTimestamp(t, 5, 2000)
+			if err := write(storage, original); err != nil {
+				t.Fatalf("write(original) error = %v", err)
+			}
+			// Resolve once so the hash lookup is cached
+			if _, err := storage.GetBlockByHash(ctx, original.Hash()); err != nil {
+				t.Fatalf("GetBlockByHash(original) error = %v", err)
+			}
+			if err := write(storage, replacement); err != nil {
+				t.Fatalf("write(replacement) error = %v", err)
+			}
+
+			if _, closer, err := storage.db.Get(BlockHashIndexKey(original.Hash())); err == nil {
+				closer.Close()
+				t.Error("hash index of the replaced block still present")
+			}
+			if _, err := storage.GetBlockByHash(ctx, original.Hash()); !errors.Is(err, ErrNotFound) {
+				t.Errorf("GetBlockByHash(original) error = %v, want ErrNotFound", err)
+			}
+			block, err := storage.GetBlockByHash(ctx, replacement.Hash())
+			if err != nil || block.Hash() != replacement.Hash() {
+				t.Errorf("GetBlockByHash(replacement) = %v, %v; want the replacement", block, err)
+			}
+
+			// Re-storing the same block keeps its hash index
+			if err := write(storage, replacement); err != nil {
+				t.Fatalf("write(replacement) again error = %v", err)
+			}
+			if _, err := storage.GetBlockByHash(ctx, replacement.Hash()); err != nil {
+				t.Errorf("GetBlockByHash(replacement) after re-store error = %v", err)
+			}
+		})
+	}
+}
+
+func TestPebbleStorage_GetLatestBlocks(t *testing.T) {
+	storage := setupTestPebbleStorage(t)
+
+	ctx := context.Background()
+	blocks, err := storage.GetLatestBlocks(ctx, 5)
+	if err != nil {
+		t.Fatalf("GetLatestBlocks() on empty storage error = %v", err)
+	}
+	if len(blocks) != 0 {
+		t.Fatalf("GetLatestBlocks() on empty storage returned %d blocks, want 0", len(blocks))
+	}
+
+	for height := uint64(0); height < 10; height++ {
+		if err := storage.SetBlock(ctx, createTestBlockWithTimestamp(t, height, 1000+height)); err != nil {
+			t.Fatalf("SetBlock(%d) error = %v", height, err)
+		}
+	}
+	if err := storage.SetLatestHeight(ctx, 9); err != nil {
+		t.Fatalf("SetLatestHeight() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		n    int
+		want []uint64
+	}{
+		{name: "latest three", n: 3, want: []uint64{9, 8, 7}},
+		{name: "exactly the indexed range", n: 10, want: []uint64{9, 8, 7, 6, 5, 4, 3, 2, 1, 0}},
+		{name: "clamped to indexed range", n: 100, want: []uint64{9, 8, 7, 6, 5, 4, 3, 2, 1, 0}},
+		{name: "zero", n: 0, want: []uint64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blocks, err := storage.GetLatestBlocks(ctx, tt.n)
+			if err != nil {
+				t.Fatalf("GetLatestBlocks(%d) error = %v", tt.n, err)
+			}
+			if len(blocks) != len(tt.want) {
+				t.Fatalf("GetLatestBlocks(%d) returned %d blocks, want %d", tt.n, len(blocks), len(tt.want))
+			}
+			for i, block := range blocks {
+				if block.NumberU64() != tt.want[i] {
+					t.Errorf("blocks[%d] = %d, want %d", i, block.NumberU64(), tt.want[i])
+				}
+				// Only headers are read, but the hash matches the stored block
+				stored, err := storage.GetBlock(ctx, tt.want[i])
+				if err != nil {
+					t.Fatalf("GetBlock(%d) error = %v", tt.want[i], err)
+				}
+				if block.Hash() != stored.Hash() {
+					t.Errorf("blocks[%d] hash = %s, want %s", i, block.Hash().Hex(), stored.Hash().Hex())
+				}
+			}
+		})
+	}
+
+	// Missing heights inside the window are skipped, not backfilled from below
+	if err := storage.DeleteBlock(ctx, 8); err != nil {
+		t.Fatalf("DeleteBlock() error = %v", err)
+	}
+	blocks, err = storage.GetLatestBlocks(ctx, 3)
+	if err != nil {
+		t.Fatalf("GetLatestBlocks() error = %v", err)
+	}
+	if len(blocks) != 2 || blocks[0].NumberU64() != 9 || blocks[1].NumberU64() != 7 {
+		t.Errorf("GetLatestBlocks(3) with a gap returned %d blocks, want heights 9 and 7", len(blocks))
+	}
+}
+
+func TestPebbleStorage_GetBlocksWithMissing(t *testing.T) {
+	storage := setupTestPebbleStorage(t)
+
+	ctx := context.Background()
+	// Heights 2, 5, 6 and 9 are holes
+	for _, height := range []uint64{0, 1, 3, 4, 7, 8} {
+		if err := storage.SetBlock(ctx, createTestBlockWithTimestamp(t, height, 1000+height)); err != nil {
+			t.Fatalf("SetBlock(%d) error = %v", height, err)
+		}
+	}
+
+	tests := []struct {
+		name        string
+		start, end  uint64
+		wantBlocks  []uint64
+		wantMissing []uint64
+	}{
+		{name: "holed range", start: 0, end: 9, wantBlocks: []uint64{0, 1, 3, 4, 7, 8}, wantMissing: []uint64{2, 5, 6, 9}},
+		{name: "complete range", start: 3, end: 4, wantBlocks: []uint64{3, 4}, wantMissing: []uint64{}},
+		{name: "only holes", start: 5, end: 6, wantBlocks: []uint64{}, wantMissing: []uint64{5, 6}},
+		{name: "single height", start: 2, end: 2, wantBlocks: []uint64{}, wantMissing: []uint64{2}},
+		{name: "inverted range", start: 4, end: 3, wantBlocks: []uint64{}, wantMissing: []uint64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blocks, missing, err := storage.GetBlocksWithMissing(ctx, tt.start, tt.end)
+			if err != nil {
+				t.Fatalf("GetBlocksWithMissing(%d, %d) error = %v", tt.start, tt.end, err)
+			}
+			got := make([]uint64, len(blocks))
+			for i, block := range blocks {
+				got[i] = block.NumberU64()
+			}
+			if !reflect.DeepEqual(got, tt.wantBlocks) {
+				t.Errorf("blocks = %v, want %v", got, tt.wantBlocks)
+			}
+			if !reflect.DeepEqual(missing, tt.wantMissing) {
+				t.Errorf("missing = %v, want %v", missing, tt.wantMissing)
+			}
+		})
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, _, err := storage.GetBlocksWithMissing(cancelled, 0, 9); !errors.Is(err, context.Canceled) {
+		t.Errorf("GetBlocksWithMissing() with cancelled context error = %v, want context.Canceled", err)
+	}
+}
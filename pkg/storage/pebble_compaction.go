@@ -0,0 +1,177 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/pebble"
+	"go.uber.org/zap"
+)
+
+// Ensure PebbleStorage implements StatsReader
+var _ StatsReader = (*PebbleStorage)(nil)
+
+// compactionStartLayout is the time-of-day layout of Config.CompactionStartTime
+const compactionStartLayout = "15:04"
+
+// CompactAll compacts the keyspace of the storage's key namespace and records
+// when it finished. The keys of other namespaces in the same database are left
+// to their own storage.
+func (s *PebbleStorage) CompactAll(ctx context.Context) error {
+	if err := s.ensureNotClosed(); err != nil {
+		return err
+	}
+	if err := s.ensureNotReadOnly(); err != nil {
+		return err
+	}
+
+	began := time.Now()
+	var compacted bool
+	for _, r := range s.db.ownedRanges() {
+		ok, err := s.compactKeys(ctx, r[0], r[1])
+		if err != nil {
+			return err
+		}
+		compacted = compacted || ok
+	}
+	if !compacted {
+		return nil // Empty database
+	}
+	finished := time.Now()
+
+	if err := s.db.Set(LastCompactionKey(), EncodeUint64(uint64(finished.Unix())), pebble.Sync); err != nil {
+		return fmt.Errorf("failed to store last compaction time: %w", err)
+	}
+
+	s.logger.Info("Full compaction completed", zap.Duration("duration", finished.Sub(began)))
+	return nil
+}
+
+// compactKeys compacts the stored keys within [lower, upper), where nil is an
+// open bound. It reports false when the range holds no keys.
+func (s *PebbleStorage) compactKeys(ctx context.Context, lower, upper []byte) (bool, error) {
+	iter, err := s.db.NewIter(&pebble.IterOptions{LowerBound: lower, UpperBound: upper})
+	if err != nil {
+		return false, fmt.Errorf("failed to create iterator: %w", err)
+	}
+	if !iter.First() {
+		iter.Close()
+		return false, nil
+	}
+	start := append([]byte(nil), iter.Key()...)
+	iter.Last()
+	// Extend past the last key so it is inside the compacted range
+	end := append(append([]byte(nil), iter.Key()...), 0x00)
+	if err := iter.Close(); err != nil {
+		return false, fmt.Errorf("failed to close iterator: %w", err)
+	}
+
+	if err := s.Compact(ctx, start, end); err != nil {
+		return false, fmt.Errorf("failed to compact: %w", err)
+	}
+	return true, nil
+}
+
+// GetLastCompactionTime returns when the last full compaction finished, or
+// ErrNotFound if none has run
+func (s *PebbleStorage) GetLastCompactionTime(ctx context.Context) (time.Time, error) {
+	if err := s.ensureNotClosed(); err != nil {
+		return time.Time{}, err
+	}
+
+	value, closer, err := s.db.Get(LastCompactionKey())
+	if err != nil {
+		if err == pebble.ErrNotFound {
+			return time.Time{}, ErrNotFound
+		}
+		return time.Time{}, fmt.Errorf("failed to get last compaction time: %w", err)
+	}
+	defer closer.Close()
+
+	unix, err := DecodeUint64(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w last compaction time: %w", ErrDecodeFailed, err)
+	}
+	return time.Unix(int64(unix), 0), nil
+}
+
+// GetStats returns storage statistics
+func (s *PebbleStorage) GetStats(ctx context.Context) (*Stats, error) {
+	if err := s.ensureNotClosed(); err != nil {
+		return nil, err
+	}
+
+	stats := &Stats{}
+
+	latest, err := s.GetLatestHeight(ctx)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	stats.LatestHeight = latest
+
+	if stats.BlockCount, err = s.GetBlockCount(ctx); err != nil {
+		return nil, err
+	}
+	if stats.TransactionCount, err = s.GetTransactionCount(ctx); err != nil {
+		return nil, err
+	}
+
+	metrics := s.db.Metrics()
+	stats.DiskUsage = metrics.DiskSpaceUsage()
+	stats.CompactionCount = uint64(metrics.Compact.Count)
+	stats.Pebble = newPebbleMetrics(metrics)
+
+	lastCompaction, err := s.GetLastCompactionTime(ctx)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	stats.LastCompaction = lastCompaction
+
+	return stats, nil
+}
+
+// startCompactionScheduler runs CompactAll every CompactionInterval, starting
+// at CompactionStartTime when set, until the storage is closed
+func (s *PebbleStorage) startCompactionScheduler() {
+	s.compactionStop = make(chan struct{})
+	s.compactionDone = make(chan struct{})
+
+	go func() {
+		defer close(s.compactionDone)
+
+		timer := time.NewTimer(nextCompactionDelay(time.Now(), s.config.CompactionStartTime, s.config.CompactionInterval))
+		defer timer.Stop()
+
+		for {
+			select {
+			case <-s.compactionStop:
+				return
+			case <-timer.C:
+				if err := s.CompactAll(context.Background()); err != nil {
+					s.logger.Error("Scheduled compaction failed", zap.Error(err))
+				}
+				timer.Reset(s.config.CompactionInterval)
+			}
+		}
+	}()
+}
+
+// nextCompactionDelay returns the delay until the first scheduled compaction:
+// the next occurrence of startTime ("HH:MM", local), or one interval when unset
+func nextCompactionDelay(now time.Time, startTime string, interval time.Duration) time.Duration {
+	if startTime == "" {
+		return interval
+	}
+	at, err := time.Parse(compactionStartLayout, startTime)
+	if err != nil {
+		return interval
+	}
+
+	next := time.Date(now.Year(), now.Month(), now.Day(), at.Hour(), at.Minute(), 0, 0, now.Location())
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next.Sub(now)
+}
@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPebbleStorage_ScheduledCompaction(t *testing.T) {
+	cfg := DefaultConfig(t.TempDir())
+	cfg.CompactionInterval = 20 * time.Millisecond
+	storage, err := NewPebbleStorage(cfg)
+	if err != nil {
+		t.Fatalf("NewPebbleStorage() error = %v", err)
+	}
+	defer storage.Close()
+
+	ctx := context.Background()
+	for height := uint64(0); height < 20; height++ {
+		if err := storage.SetBlock(ctx, createTestBlock(height)); err != nil {
+			t.Fatalf("SetBlock(%d) error = %v", height, err)
+		}
+	}
+	if err := storage.SetLatestHeight(ctx, 19); err != nil {
+		t.Fatalf("SetLatestHeight() error = %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err := storage.GetLastCompactionTime(ctx); err == nil {
+			break
+		} else if err != ErrNotFound {
+			t.Fatalf("GetLastCompactionTime() error = %v", err)
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("scheduled compaction did not run")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Data is intact after compaction
+	for height := uint64(0); height < 20; height++ {
+		block, err := storage.GetBlock(ctx, height)
+		if err != nil {
+			t.Fatalf("GetBlock(%d) after compaction error = %v", height, err)
+		}
+		if block.NumberU64() != height {
+			t.Errorf("GetBlock(%d) returned block %d", height, block.NumberU64())
+		}
+	}
+
+	stats, err := storage.GetStats(ctx)
+	if err != nil {
+		t.Fatalf("GetStats() error = %v", err)
+	}
+	if stats.LatestHeight != 19 {
+		t.Errorf("Stats.LatestHeight = %d, want 19", stats.LatestHeight)
+	}
+	if stats.LastCompaction.IsZero() {
+		t.Error("Stats.LastCompaction should be set after a scheduled compaction")
+	}
+}
+
+func TestPebbleStorage_CompactAll_ReadOnly(t *testing.T) {
+	cfg := DefaultConfig(t.TempDir())
+	storage, err := NewPebbleStorage(cfg)
+	if err != nil {
+		t.Fatalf("NewPebbleStorage() error = %v", err)
+	}
+	storage.Close()
+
+	// The schedule is ignored in read-only mode
+	cfg.ReadOnly = true
+	cfg.CompactionInterval = time.Millisecond
+	roStorage, err := NewPebbleStorage(cfg)
+	if err != nil {
+		t.Fatalf("NewPebbleStorage() read-only error = %v", err)
+	}
+	defer roStorage.Close()
+
+	if err := roStorage.CompactAll(context.Background()); err != ErrReadOnly {
+		t.Errorf("CompactAll() on read-only storage error = %v, want ErrReadOnly", err)
+	}
+	stats, err := roStorage.GetStats(context.Background())
+	if err != nil {
+		t.Fatalf("GetStats() error = %v", err)
+	}
+	if !stats.LastCompaction.IsZero() {
+		t.Errorf("Stats.LastCompaction = %v, want zero", stats.LastCompaction)
+	}
+}
+
+func TestNextCompactionDelay(t *testing.T) {
+	now := time.Date(2024, 5, 1, 14, 30, 0, 0, time.Local)
+
+	tests := []struct {
+		name      string
+		startTime string
+		want      time.Duration
+	}{
+		{"no start time", "", 6 * time.Hour},
+		{"later today", "22:00", 7*time.Hour + 30*time.Minute},
+		{"tomorrow", "03:00", 12*time.Hour + 30*time.Minute},
+		{"now rolls to tomorrow", "14:30", 24 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextCompactionDelay(now, tt.startTime, 6*time.Hour); got != tt.want {
+				t.Errorf("nextCompactionDelay() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package storage
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/cockroachdb/pebble"
@@ -33,7 +34,7 @@ func (s *PebbleStorage) GetContractVerification(ctx context.Context, address com
 
 	var verification ContractVerification
 	if err := json.Unmarshal(data, &verification); err != nil {
-		return nil, fmt.Errorf("failed to decode contract verification: %w", err)
+		return nil, fmt.Errorf("%w contract verification: %w", ErrDecodeFailed, err)
 	}
 
 	return &verification, nil
@@ -173,7 +174,7 @@ func (s *PebbleStorage) SetContractVerification(ctx context.Context, verificatio
 	// Encode verification data as JSON
 	data, err := json.Marshal(verification)
 	if err != nil {
-		return fmt.Errorf("failed to encode contract verification: %w", err)
+		return fmt.Errorf("%w contract verification: %w", ErrEncodeFailed, err)
 	}
 
 	// Store verification data
@@ -203,7 +204,7 @@ func (s *PebbleStorage) DeleteContractVerification(ctx context.Context, address
 	// Get verification data to find the index key
 	verification, err := s.GetContractVerification(ctx, address)
 	if err != nil {
-		if err == ErrNotFound {
+		if errors.Is(err, ErrNotFound) {
 			return nil // Already deleted
 		}
 		return fmt.Errorf("failed to get verification for deletion: %w", err)
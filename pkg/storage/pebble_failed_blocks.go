@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cockroachdb/pebble"
+)
+
+// GetFailedBlock returns the dead-letter record of a block
+func (s *PebbleStorage) GetFailedBlock(ctx context.Context, height uint64) (*FailedBlock, error) {
+	if err := s.ensureNotClosed(); err != nil {
+		return nil, err
+	}
+
+	value, closer, err := s.db.Get(FailedBlockKey(height))
+	if err != nil {
+		if err == pebble.ErrNotFound {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get failed block %d: %w", height, err)
+	}
+	defer closer.Close()
+
+	var record FailedBlock
+	if err := json.Unmarshal(value, &record); err != nil {
+		return nil, fmt.Errorf("%w failed block %d: %w", ErrDecodeFailed, height, err)
+	}
+	return &record, nil
+}
+
+// GetFailedBlocks returns up to limit dead-letter records ordered by height
+func (s *PebbleStorage) GetFailedBlocks(ctx context.Context, limit int) ([]*FailedBlock, error) {
+	if err := s.ensureNotClosed(); err != nil {
+		return nil, err
+	}
+
+	prefix := FailedBlockKeyPrefix()
+	iter, err := s.db.NewIter(&pebble.IterOptions{
+		LowerBound: prefix,
+		UpperBound: prefixUpperBound(prefix),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create iterator: %w", err)
+	}
+	defer iter.Close()
+
+	records := make([]*FailedBlock, 0)
+	for iter.First(); iter.Valid(); iter.Next() {
+		if limit > 0 && len(records) >= limit {
+			break
+		}
+
+		var record FailedBlock
+		if err := json.Unmarshal(iter.Value(), &record); err != nil {
+			return nil, fmt.Errorf("%w failed block record %s: %w", ErrDecodeFailed, iter.Key(), err)
+		}
+		records = append(records, &record)
+	}
+
+	if err := iter.Error(); err != nil {
+		return nil, fmt.Errorf("iterator error: %w", err)
+	}
+
+	return records, nil
+}
+
+// SetFailedBlock records a block that failed to index
+func (s *PebbleStorage) SetFailedBlock(ctx context.Context, record *FailedBlock) error {
+	if err := s.ensureNotClosed(); err != nil {
+		return err
+	}
+	if err := s.ensureNotReadOnly(); err != nil {
+		return err
+	}
+	if record == nil {
+		return fmt.Errorf("failed block record cannot be nil")
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal failed block %d: %w", record.Height, err)
+	}
+
+	// Synced so the record survives the crash that may follow the failure
+	if err := s.db.Set(FailedBlockKey(record.Height), data, pebble.Sync); err != nil {
+		return fmt.Errorf("failed to set failed block %d: %w", record.Height, err)
+	}
+	return nil
+}
+
+// DeleteFailedBlock removes the dead-letter record of a block
+func (s *PebbleStorage) DeleteFailedBlock(ctx context.Context, height uint64) error {
+	if err := s.ensureNotClosed(); err != nil {
+		return err
+	}
+	if err := s.ensureNotReadOnly(); err != nil {
+		return err
+	}
+
+	if err := s.db.Delete(FailedBlockKey(height), pebble.Sync); err != nil {
+		return fmt.Errorf("failed to delete failed block %d: %w", height, err)
+	}
+	return nil
+}
+
+// Ensure PebbleStorage implements the dead-letter interfaces
+var _ FailedBlockReader = (*PebbleStorage)(nil)
+var _ FailedBlockWriter = (*PebbleStorage)(nil)
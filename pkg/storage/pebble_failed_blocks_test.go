@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPebbleStorage_FailedBlocks(t *testing.T) {
+	storage := setupTestPebbleStorage(t)
+
+	ctx := context.Background()
+
+	if _, err := storage.GetFailedBlock(ctx, 5); err != ErrNotFound {
+		t.Fatalf("GetFailedBlock() on empty store error = %v, want ErrNotFound", err)
+	}
+
+	failedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	// Heights that sort differently as unpadded strings
+	for _, height := range []uint64{100, 9, 20} {
+		record := &FailedBlock{Height: height, Error: "rpc timeout", Attempts: 1, FailedAt: failedAt}
+		if err := storage.SetFailedBlock(ctx, record); err != nil {
+			t.Fatalf("SetFailedBlock(%d) error = %v", height, err)
+		}
+	}
+
+	records, err := storage.GetFailedBlocks(ctx, 0)
+	if err != nil {
+		t.Fatalf("GetFailedBlocks() error = %v", err)
+	}
+	want := []uint64{9, 20, 100}
+	if len(records) != len(want) {
+		t.Fatalf("GetFailedBlocks() returned %d records, want %d", len(records), len(want))
+	}
+	for i, record := range records {
+		if record.Height != want[i] {
+			t.Errorf("record %d height = %d, want %d", i, record.Height, want[i])
+		}
+	}
+
+	limited, err := storage.GetFailedBlocks(ctx, 2)
+	if err != nil {
+		t.Fatalf("GetFailedBlocks(limit) error = %v", err)
+	}
+	if len(limited) != 2 || limited[1].Height != 20 {
+		t.Errorf("GetFailedBlocks(2) = %v, want heights 9 and 20", limited)
+	}
+
+	// A later failure replaces the record
+	if err := storage.SetFailedBlock(ctx, &FailedBlock{Height: 20, Error: "bad receipts", Attempts: 2, FailedAt: failedAt}); err != nil {
+		t.Fatalf("SetFailedBlock() error = %v", err)
+	}
+	record, err := storage.GetFailedBlock(ctx, 20)
+	if err != nil {
+		t.Fatalf("GetFailedBlock() error = %v", err)
+	}
+	if record.Error != "bad receipts" || record.Attempts != 2 || !record.FailedAt.Equal(failedAt) {
+		t.Errorf("GetFailedBlock() = %+v", record)
+	}
+
+	if err := storage.DeleteFailedBlock(ctx, 20); err != nil {
+		t.Fatalf("DeleteFailedBlock() error = %v", err)
+	}
+	if _, err := storage.GetFailedBlock(ctx, 20); err != ErrNotFound {
+		t.Errorf("GetFailedBlock() after delete error = %v, want ErrNotFound", err)
+	}
+	if err := storage.DeleteFailedBlock(ctx, 20); err != nil {
+		t.Errorf("DeleteFailedBlock() of missing record error = %v", err)
+	}
+}
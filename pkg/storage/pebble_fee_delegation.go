@@ -10,6 +10,7 @@ import (
 	"github.com/0xmhha/indexer-go/internal/constants"
 	"github.com/cockroachdb/pebble"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 )
 
 // Ensure PebbleStorage implements FeeDelegationReader and FeeDelegationWriter
@@ -378,6 +379,17 @@ func (s *PebbleStorage) GetFeeDelegationTxMeta(ctx context.Context, txHash commo
 	return &meta, nil
 }
 
+// transactionSender returns the sender of tx, preferring the sender the node
+// reported for a fee delegation transaction over location's cached sender and
+// the signature, which do not recover it
+func (s *PebbleStorage) transactionSender(ctx context.Context, location *TxLocation, tx *types.Transaction) (common.Address, error) {
+	meta, err := s.GetFeeDelegationTxMeta(ctx, tx.Hash())
+	if err == nil && meta != nil && meta.Sender != (common.Address{}) {
+		return meta.Sender, nil
+	}
+	return location.Sender(tx)
+}
+
 // GetFeeDelegationTxsByFeePayer returns transaction hashes of fee delegation txs by fee payer
 func (s *PebbleStorage) GetFeeDelegationTxsByFeePayer(ctx context.Context, feePayer common.Address, limit, offset int) ([]common.Hash, error) {
 	if err := s.ensureNotClosed(); err != nil {
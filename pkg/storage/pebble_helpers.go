@@ -20,17 +20,33 @@ var transferEventTopic = common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952
 
 // scanTransferEvents scans receipts for Transfer events involving the address
 // and returns a map of contract addresses to balances
+// Blocks are scanned by Config.ScanWorkers workers into per-worker maps that
+// are summed once the scan completes
 func (s *PebbleStorage) scanTransferEvents(ctx context.Context, addr common.Address, latestHeight uint64) (map[common.Address]*big.Int, error) {
-	balanceMap := make(map[common.Address]*big.Int)
+	shards, err := scanBlockRange(ctx, s.config.ScanWorkers, 0, latestHeight,
+		func() map[common.Address]*big.Int { return make(map[common.Address]*big.Int) },
+		func(balanceMap map[common.Address]*big.Int, height uint64) {
+			receipts, err := s.GetReceiptsByBlockNumber(ctx, height)
+			if err != nil {
+				return
+			}
 
-	for height := uint64(0); height <= latestHeight; height++ {
-		receipts, err := s.GetReceiptsByBlockNumber(ctx, height)
-		if err != nil {
-			continue
-		}
+			for _, receipt := range receipts {
+				s.processReceiptTransfers(receipt, addr, balanceMap)
+			}
+		})
+	if err != nil {
+		return nil, err
+	}
 
-		for _, receipt := range receipts {
-			s.processReceiptTransfers(receipt, addr, balanceMap)
+	balanceMap := shards[0]
+	for _, shard := range shards[1:] {
+		for contract, balance := range shard {
+			if existing, ok := balanceMap[contract]; ok {
+				existing.Add(existing, balance)
+			} else {
+				balanceMap[contract] = balance
+			}
 		}
 	}
 
@@ -124,12 +140,12 @@ func (s *PebbleStorage) fetchAndCacheTokenMetadata(ctx context.Context, tb *Toke
 
 	// Cache the fetched metadata
 	if saveErr := s.SaveTokenMetadata(ctx, fetchedMetadata); saveErr != nil {
-		s.logger.Warn("Failed to cache fetched token metadata",
+		s.loggerFor(ctx).Warn("Failed to cache fetched token metadata",
 			zap.String("contract", contract.Hex()),
 			zap.Error(saveErr),
 		)
 	} else {
-		s.logger.Info("Cached on-demand fetched token metadata",
+		s.loggerFor(ctx).Info("Cached on-demand fetched token metadata",
 			zap.String("contract", contract.Hex()),
 			zap.String("name", fetchedMetadata.Name),
 			zap.String("symbol", fetchedMetadata.Symbol),
@@ -189,32 +205,65 @@ func determineBlockRange(fromBlock, toBlock, latestHeight uint64) (start, end ui
 	return start, end, true
 }
 
+// minerStatsShard holds the miner statistics aggregated by one scan worker
+type minerStatsShard struct {
+	miners      map[common.Address]*MinerStats
+	totalBlocks uint64
+}
+
 // aggregateMinerStats scans blocks and aggregates miner statistics
-func (s *PebbleStorage) aggregateMinerStats(ctx context.Context, startBlock, endBlock uint64) (map[common.Address]*MinerStats, uint64) {
-	minerMap := make(map[common.Address]*MinerStats)
-	totalBlocks := uint64(0)
+// Blocks are scanned by Config.ScanWorkers workers into per-worker shards that
+// are merged once the scan completes
+// Returns ctx.Err() if the context is cancelled during the scan
+func (s *PebbleStorage) aggregateMinerStats(ctx context.Context, startBlock, endBlock uint64, opts MinerStatsOptions) (map[common.Address]*MinerStats, uint64, error) {
+	shards, err := scanBlockRange(ctx, s.config.ScanWorkers, startBlock, endBlock,
+		func() *minerStatsShard {
+			return &minerStatsShard{miners: make(map[common.Address]*MinerStats)}
+		},
+		func(shard *minerStatsShard, height uint64) {
+			block, err := s.GetBlock(ctx, height)
+			if err != nil {
+				return
+			}
 
-	for height := startBlock; height <= endBlock; height++ {
-		block, err := s.GetBlock(ctx, height)
-		if err != nil {
-			continue
-		}
+			shard.totalBlocks++
+			miner := block.Coinbase()
 
-		totalBlocks++
-		miner := block.Coinbase()
+			stats := s.getOrCreateMinerStats(shard.miners, miner)
+			stats.BlockCount++
 
-		stats := s.getOrCreateMinerStats(minerMap, miner)
-		stats.BlockCount++
+			if height > stats.LastBlockNumber {
+				stats.LastBlockNumber = height
+				stats.LastBlockTime = block.Time()
+			}
 
-		if height > stats.LastBlockNumber {
-			stats.LastBlockNumber = height
-			stats.LastBlockTime = block.Time()
-		}
+			if !opts.SkipRewards {
+				s.addBlockRewardsToStats(ctx, block, stats)
+			}
+		})
+	if err != nil {
+		return nil, 0, err
+	}
 
-		s.addBlockRewardsToStats(ctx, block, stats)
+	minerMap, totalBlocks := shards[0].miners, shards[0].totalBlocks
+	for _, shard := range shards[1:] {
+		totalBlocks += shard.totalBlocks
+		for miner, stats := range shard.miners {
+			merged, exists := minerMap[miner]
+			if !exists {
+				minerMap[miner] = stats
+				continue
+			}
+			merged.BlockCount += stats.BlockCount
+			merged.TotalRewards.Add(merged.TotalRewards, stats.TotalRewards)
+			if stats.LastBlockNumber > merged.LastBlockNumber {
+				merged.LastBlockNumber = stats.LastBlockNumber
+				merged.LastBlockTime = stats.LastBlockTime
+			}
+		}
 	}
 
-	return minerMap, totalBlocks
+	return minerMap, totalBlocks, nil
 }
 
 // getOrCreateMinerStats gets or creates a MinerStats entry in the map
@@ -235,26 +284,31 @@ func (s *PebbleStorage) getOrCreateMinerStats(minerMap map[common.Address]*Miner
 }
 
 // addBlockRewardsToStats calculates and adds transaction fees to miner stats
+// Receipts are looked up directly from the already-decoded block's transactions,
+// so the block is not decoded a second time to resolve its receipts.
+// GasUsed is not part of the stored receipt encoding, so it is derived from
+// the difference in CumulativeGasUsed between consecutive receipts when absent
 func (s *PebbleStorage) addBlockRewardsToStats(ctx context.Context, block *types.Block, stats *MinerStats) {
-	// Create transaction map for O(1) lookup
-	txMap := make(map[common.Hash]*types.Transaction)
+	gasUsed := new(big.Int)
+	prevCumulative, prevKnown := uint64(0), true
 	for _, tx := range block.Transactions() {
-		txMap[tx.Hash()] = tx
-	}
+		receipt, err := s.GetReceipt(ctx, tx.Hash())
+		if err != nil {
+			prevKnown = false
+			continue
+		}
 
-	// Get receipts and calculate fees
-	receipts, err := s.GetReceiptsByBlockNumber(ctx, block.NumberU64())
-	if err != nil {
-		return
-	}
+		used := receipt.GasUsed
+		if used == 0 && prevKnown && receipt.CumulativeGasUsed >= prevCumulative {
+			used = receipt.CumulativeGasUsed - prevCumulative
+		}
+		prevCumulative, prevKnown = receipt.CumulativeGasUsed, true
 
-	for _, receipt := range receipts {
-		if receipt.GasUsed > 0 {
-			if tx, found := txMap[receipt.TxHash]; found {
-				fee := new(big.Int).Mul(tx.GasPrice(), big.NewInt(int64(receipt.GasUsed)))
-				stats.TotalRewards.Add(stats.TotalRewards, fee)
-			}
+		if used == 0 {
+			continue
 		}
+		gasUsed.SetUint64(used)
+		stats.TotalRewards.Add(stats.TotalRewards, new(big.Int).Mul(tx.GasPrice(), gasUsed))
 	}
 }
 
@@ -2,9 +2,12 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
+	"strconv"
 
+	"github.com/0xmhha/indexer-go/internal/constants"
 	"github.com/cockroachdb/pebble"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -13,13 +16,14 @@ import (
 // Ensure PebbleStorage implements HistoricalReader and HistoricalWriter
 var _ HistoricalReader = (*PebbleStorage)(nil)
 var _ HistoricalWriter = (*PebbleStorage)(nil)
+var _ TransactionRangeReader = (*PebbleStorage)(nil)
 
 // ============================================================================
 // Historical Data Methods
 // ============================================================================
 
-// GetBlocksByTimeRange returns blocks within a time range
-func (s *PebbleStorage) GetBlocksByTimeRange(ctx context.Context, fromTime, toTime uint64, limit, offset int) ([]*types.Block, error) {
+// GetBlocksByTimeRange returns blocks within a time range in the given order
+func (s *PebbleStorage) GetBlocksByTimeRange(ctx context.Context, fromTime, toTime uint64, limit, offset int, order SortOrder) ([]*types.Block, error) {
 	if err := s.ensureNotClosed(); err != nil {
 		return nil, err
 	}
@@ -38,10 +42,16 @@ func (s *PebbleStorage) GetBlocksByTimeRange(ctx context.Context, fromTime, toTi
 	}
 	defer iter.Close()
 
+	// Descending order walks the timestamp index backwards
+	first, next := iter.First, iter.Next
+	if order == SortDescending {
+		first, next = iter.Last, iter.Prev
+	}
+
 	var blocks []*types.Block
 	count := 0
 
-	for iter.First(); iter.Valid(); iter.Next() {
+	for first(); iter.Valid(); next() {
 		// Skip offset items
 		if count < offset {
 			count++
@@ -56,13 +66,13 @@ func (s *PebbleStorage) GetBlocksByTimeRange(ctx context.Context, fromTime, toTi
 		// Extract height from value
 		height, err := DecodeUint64(iter.Value())
 		if err != nil {
-			return nil, fmt.Errorf("failed to decode height: %w", err)
+			return nil, fmt.Errorf("%w height: %w", ErrDecodeFailed, err)
 		}
 
 		// Get block by height
 		block, err := s.GetBlock(ctx, height)
 		if err != nil {
-			if err == ErrNotFound {
+			if errors.Is(err, ErrNotFound) {
 				continue // Skip missing blocks
 			}
 			return nil, fmt.Errorf("failed to get block %d: %w", height, err)
@@ -88,6 +98,7 @@ func (s *PebbleStorage) GetBlockByTimestamp(ctx context.Context, timestamp uint6
 	// Binary search for closest timestamp
 	iter, err := s.db.NewIter(&pebble.IterOptions{
 		LowerBound: BlockTimestampKeyPrefix(),
+		UpperBound: prefixUpperBound(BlockTimestampKeyPrefix()),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create iterator: %w", err)
@@ -104,7 +115,7 @@ func (s *PebbleStorage) GetBlockByTimestamp(ctx context.Context, timestamp uint6
 		// Found exact or later timestamp
 		height, err := DecodeUint64(iter.Value())
 		if err != nil {
-			return nil, fmt.Errorf("failed to decode height: %w", err)
+			return nil, fmt.Errorf("%w height: %w", ErrDecodeFailed, err)
 		}
 		closestHeight = height
 		found = true
@@ -114,7 +125,7 @@ func (s *PebbleStorage) GetBlockByTimestamp(ctx context.Context, timestamp uint6
 		if iter.Valid() {
 			height, err := DecodeUint64(iter.Value())
 			if err != nil {
-				return nil, fmt.Errorf("failed to decode height: %w", err)
+				return nil, fmt.Errorf("%w height: %w", ErrDecodeFailed, err)
 			}
 			closestHeight = height
 			found = true
@@ -172,7 +183,7 @@ func (s *PebbleStorage) GetTransactionsByAddressFiltered(ctx context.Context, ad
 		// Get transaction and location
 		tx, location, err := s.GetTransaction(ctx, txHash)
 		if err != nil {
-			if err == ErrNotFound {
+			if errors.Is(err, ErrNotFound) {
 				continue
 			}
 			return nil, fmt.Errorf("failed to get transaction: %w", err)
@@ -181,7 +192,7 @@ func (s *PebbleStorage) GetTransactionsByAddressFiltered(ctx context.Context, ad
 		// Get receipt
 		receipt, err := s.GetReceipt(ctx, txHash)
 		if err != nil {
-			if err == ErrNotFound {
+			if errors.Is(err, ErrNotFound) {
 				// Continue without receipt (optional)
 				receipt = nil
 			} else {
@@ -191,13 +202,8 @@ func (s *PebbleStorage) GetTransactionsByAddressFiltered(ctx context.Context, ad
 
 		// Apply filter
 		if filter.MatchTransaction(tx, receipt, location, addr) {
-			// Check fee delegation filter
-			if filter.IsFeeDelegated != nil {
-				meta, _ := s.GetFeeDelegationTxMeta(ctx, txHash)
-				isFD := (meta != nil)
-				if *filter.IsFeeDelegated != isFD {
-					continue
-				}
+			if !s.matchFeeDelegation(ctx, filter, txHash) {
+				continue
 			}
 
 			if count < offset {
@@ -221,6 +227,102 @@ func (s *PebbleStorage) GetTransactionsByAddressFiltered(ctx context.Context, ad
 	return results, nil
 }
 
+// GetTransactionsByBlockRange returns the transactions in blocks [from, to]
+// matching filter, walking the blocks rather than an address index
+func (s *PebbleStorage) GetTransactionsByBlockRange(ctx context.Context, from, to uint64, filter *TransactionFilter, limit, offset int) ([]*TransactionWithReceipt, error) {
+	if err := s.ensureNotClosed(); err != nil {
+		return nil, err
+	}
+
+	if from > to {
+		return nil, fmt.Errorf("fromBlock (%d) cannot be greater than toBlock (%d)", from, to)
+	}
+	// [from, to] spans to-from+1 blocks
+	if to-from >= MaxTransactionBlockRange {
+		return nil, fmt.Errorf("block range too large: [%d, %d] spans more than %d blocks", from, to, MaxTransactionBlockRange)
+	}
+
+	if filter == nil {
+		filter = DefaultTransactionFilter()
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid filter: %w", err)
+	}
+	if limit <= 0 {
+		limit = constants.DefaultPaginationLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	var results []*TransactionWithReceipt
+	skipped := 0
+
+	// Count up to the span rather than the height, so to == math.MaxUint64
+	// cannot wrap the loop around
+	for i := uint64(0); i <= to-from; i++ {
+		if i%ScanContextCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+
+		height := from + i
+		block, err := s.GetBlock(ctx, height)
+		if err != nil {
+			if errors.Is(err, ErrNotFound) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get block %d: %w", height, err)
+		}
+
+		for i, tx := range block.Transactions() {
+			location := &TxLocation{BlockHeight: height, TxIndex: uint64(i), BlockHash: block.Hash()}
+			if !filter.matchBlockRange(location) {
+				break
+			}
+
+			receipt, err := s.GetReceipt(ctx, tx.Hash())
+			if err != nil {
+				if !errors.Is(err, ErrNotFound) {
+					return nil, fmt.Errorf("failed to get receipt: %w", err)
+				}
+				// Continue without receipt (optional)
+				receipt = nil
+			}
+
+			if !filter.matchFields(tx, receipt) || !s.matchFeeDelegation(ctx, filter, tx.Hash()) {
+				continue
+			}
+
+			if skipped < offset {
+				skipped++
+				continue
+			}
+			results = append(results, &TransactionWithReceipt{
+				Transaction: tx,
+				Receipt:     receipt,
+				Location:    location,
+			})
+			if len(results) >= limit {
+				return results, nil
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// matchFeeDelegation checks the filter's fee delegation criterion, which
+// needs the metadata stored for fee delegated transactions
+func (s *PebbleStorage) matchFeeDelegation(ctx context.Context, filter *TransactionFilter, txHash common.Hash) bool {
+	if filter.IsFeeDelegated == nil {
+		return true
+	}
+	meta, _ := s.GetFeeDelegationTxMeta(ctx, txHash)
+	return *filter.IsFeeDelegated == (meta != nil)
+}
+
 // GetAddressBalance returns the balance of an address at a specific block
 func (s *PebbleStorage) GetAddressBalance(ctx context.Context, addr common.Address, blockNumber uint64) (*big.Int, error) {
 	if err := s.ensureNotClosed(); err != nil {
@@ -262,7 +364,7 @@ func (s *PebbleStorage) GetAddressBalance(ctx context.Context, addr common.Addre
 	for iter.First(); iter.Valid(); iter.Next() {
 		snapshot, err := DecodeBalanceSnapshot(iter.Value())
 		if err != nil {
-			return nil, fmt.Errorf("failed to decode snapshot: %w", err)
+			return nil, fmt.Errorf("%w snapshot: %w", ErrDecodeFailed, err)
 		}
 
 		if snapshot.BlockNumber > blockNumber {
@@ -313,7 +415,7 @@ func (s *PebbleStorage) GetBalanceHistory(ctx context.Context, addr common.Addre
 
 		snapshot, err := DecodeBalanceSnapshot(iter.Value())
 		if err != nil {
-			return nil, fmt.Errorf("failed to decode snapshot: %w", err)
+			return nil, fmt.Errorf("%w snapshot: %w", ErrDecodeFailed, err)
 		}
 
 		// Filter by block range
@@ -346,7 +448,7 @@ func (s *PebbleStorage) GetBlockCount(ctx context.Context) (uint64, error) {
 	// Get latest block height
 	height, err := s.GetLatestHeight(ctx)
 	if err != nil {
-		if err == ErrNotFound {
+		if errors.Is(err, ErrNotFound) {
 			return 0, nil // No blocks indexed yet
 		}
 		return 0, fmt.Errorf("failed to get latest height: %w", err)
@@ -380,7 +482,7 @@ func (s *PebbleStorage) GetTransactionCount(ctx context.Context) (uint64, error)
 
 	count, err := DecodeUint64(value)
 	if err != nil {
-		return 0, fmt.Errorf("failed to decode transaction count: %w", err)
+		return 0, fmt.Errorf("%w transaction count: %w", ErrDecodeFailed, err)
 	}
 
 	return count, nil
@@ -405,9 +507,15 @@ func (s *PebbleStorage) InitializeTransactionCount(ctx context.Context) error {
 	// Count all transactions by iterating through blocks
 	totalTxCount := uint64(0)
 	for height := uint64(0); height <= latestHeight; height++ {
+		if height%ScanContextCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+
 		block, err := s.GetBlock(ctx, height)
 		if err != nil {
-			if err == ErrNotFound {
+			if errors.Is(err, ErrNotFound) {
 				continue // Skip missing blocks
 			}
 			return fmt.Errorf("failed to get block %d: %w", height, err)
@@ -417,6 +525,8 @@ func (s *PebbleStorage) InitializeTransactionCount(ctx context.Context) error {
 	}
 
 	// Set the transaction count
+	s.txCountMu.Lock()
+	defer s.txCountMu.Unlock()
 	if err := s.db.Set(TransactionCountKey(), EncodeUint64(totalTxCount), pebble.Sync); err != nil {
 		return fmt.Errorf("failed to set transaction count: %w", err)
 	}
@@ -424,12 +534,18 @@ func (s *PebbleStorage) InitializeTransactionCount(ctx context.Context) error {
 	// Update atomic counter
 	s.txCount.Store(totalTxCount)
 	s.txCountReady.Store(true)
+	s.txCountMissing.Store(false)
 
 	return nil
 }
 
 // GetTopMiners returns the top miners by block count
 func (s *PebbleStorage) GetTopMiners(ctx context.Context, limit int, fromBlock, toBlock uint64) ([]MinerStats, error) {
+	return s.GetTopMinersWithOptions(ctx, limit, fromBlock, toBlock, MinerStatsOptions{})
+}
+
+// GetTopMinersWithOptions returns the top miners by block count using the given options
+func (s *PebbleStorage) GetTopMinersWithOptions(ctx context.Context, limit int, fromBlock, toBlock uint64, opts MinerStatsOptions) ([]MinerStats, error) {
 	if err := s.ensureNotClosed(); err != nil {
 		return nil, err
 	}
@@ -437,7 +553,7 @@ func (s *PebbleStorage) GetTopMiners(ctx context.Context, limit int, fromBlock,
 	// Get the latest height
 	latestHeight, err := s.GetLatestHeight(ctx)
 	if err != nil {
-		if err == ErrNotFound {
+		if errors.Is(err, ErrNotFound) {
 			return []MinerStats{}, nil
 		}
 		return nil, fmt.Errorf("failed to get latest height: %w", err)
@@ -450,7 +566,10 @@ func (s *PebbleStorage) GetTopMiners(ctx context.Context, limit int, fromBlock,
 	}
 
 	// Aggregate miner stats
-	minerMap, totalBlocks := s.aggregateMinerStats(ctx, startBlock, endBlock)
+	minerMap, totalBlocks, err := s.aggregateMinerStats(ctx, startBlock, endBlock, opts)
+	if err != nil {
+		return nil, err
+	}
 
 	// Calculate percentages
 	calculateMinerPercentages(minerMap, totalBlocks)
@@ -464,6 +583,8 @@ func (s *PebbleStorage) GetTopMiners(ctx context.Context, limit int, fromBlock,
 // ============================================================================
 
 // SetBlockTimestamp indexes a block by timestamp
+// If the height was previously indexed with a different timestamp (e.g. after a reorg),
+// the stale timestamp entry is removed so only the new mapping remains queryable
 func (s *PebbleStorage) SetBlockTimestamp(ctx context.Context, timestamp uint64, height uint64) error {
 	if err := s.ensureNotClosed(); err != nil {
 		return err
@@ -472,11 +593,38 @@ func (s *PebbleStorage) SetBlockTimestamp(ctx context.Context, timestamp uint64,
 		return err
 	}
 
-	value := EncodeUint64(height)
-	return s.db.Set(BlockTimestampKey(timestamp, height), value, pebble.Sync)
+	batch := s.db.NewBatch()
+	defer batch.Close()
+
+	// Look up the previous timestamp for this height via the reverse index
+	value, closer, err := s.db.Get(HeightTimestampKey(height))
+	if err == nil {
+		prevTimestamp, decodeErr := DecodeUint64(value)
+		closer.Close()
+		if decodeErr != nil {
+			return fmt.Errorf("%w previous timestamp: %w", ErrDecodeFailed, decodeErr)
+		}
+		if prevTimestamp != timestamp {
+			if err := batch.Delete(BlockTimestampKey(prevTimestamp, height), nil); err != nil {
+				return fmt.Errorf("failed to delete stale timestamp index: %w", err)
+			}
+		}
+	} else if err != pebble.ErrNotFound {
+		return fmt.Errorf("failed to get previous timestamp: %w", err)
+	}
+
+	if err := batch.Set(BlockTimestampKey(timestamp, height), EncodeUint64(height), nil); err != nil {
+		return fmt.Errorf("failed to set timestamp index: %w", err)
+	}
+	if err := batch.Set(HeightTimestampKey(height), EncodeUint64(timestamp), nil); err != nil {
+		return fmt.Errorf("failed to set height timestamp index: %w", err)
+	}
+
+	return batch.Commit(pebble.Sync)
 }
 
 // UpdateBalance updates the balance for an address at a specific block
+// The history entry and the latest balance are committed in one batch
 func (s *PebbleStorage) UpdateBalance(ctx context.Context, addr common.Address, blockNumber uint64, delta *big.Int, txHash common.Hash) error {
 	if err := s.ensureNotClosed(); err != nil {
 		return err
@@ -485,50 +633,48 @@ func (s *PebbleStorage) UpdateBalance(ctx context.Context, addr common.Address,
 		return err
 	}
 
-	// Get current balance
-	currentBalance, err := s.GetAddressBalance(ctx, addr, 0) // Get latest
-	if err != nil {
-		return fmt.Errorf("failed to get current balance: %w", err)
-	}
-
-	// Calculate new balance
-	newBalance := new(big.Int).Add(currentBalance, delta)
-	if newBalance.Sign() < 0 {
-		return fmt.Errorf("balance cannot be negative")
-	}
-
-	// Create snapshot
-	snapshot := &BalanceSnapshot{
-		BlockNumber: blockNumber,
-		Balance:     newBalance,
-		Delta:       delta,
-		TxHash:      txHash,
-	}
+	batch := s.NewBatch().(*pebbleBatch)
+	defer batch.Close()
 
-	// Encode snapshot
-	encoded, err := EncodeBalanceSnapshot(snapshot)
-	if err != nil {
-		return fmt.Errorf("failed to encode snapshot: %w", err)
+	if err := batch.UpdateBalance(ctx, addr, blockNumber, delta, txHash); err != nil {
+		return err
 	}
 
-	// Get next sequence number (simple counter, could be optimized)
-	s.addrSeqMu.Lock()
-	seq := s.addrSeq[addr]
-	s.addrSeq[addr]++
-	s.addrSeqMu.Unlock()
-
-	// Store history entry
-	if err := s.db.Set(AddressBalanceKey(addr, seq), encoded, pebble.Sync); err != nil {
-		return fmt.Errorf("failed to set balance history: %w", err)
-	}
+	return batch.Commit()
+}
 
-	// Update latest balance
-	balanceBytes := EncodeBigInt(newBalance)
-	if err := s.db.Set(AddressBalanceLatestKey(addr), balanceBytes, pebble.Sync); err != nil {
-		return fmt.Errorf("failed to set latest balance: %w", err)
+// nextBalanceSeq allocates the next balance history sequence for an address
+// The counter is seeded from the last stored entry, so history written after a
+// restart is appended instead of overwriting earlier entries
+func (s *PebbleStorage) nextBalanceSeq(addr common.Address) (uint64, error) {
+	s.balanceSeqMu.Lock()
+	defer s.balanceSeqMu.Unlock()
+
+	seq, ok := s.balanceSeq[addr]
+	if !ok {
+		prefix := AddressBalanceKeyPrefix(addr)
+		iter, err := s.db.NewIter(&pebble.IterOptions{
+			LowerBound: prefix,
+			UpperBound: prefixUpperBound(prefix),
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to create iterator: %w", err)
+		}
+		if iter.Last() {
+			last, parseErr := strconv.ParseUint(string(iter.Key()[len(prefix):]), 10, 64)
+			if parseErr != nil {
+				iter.Close()
+				return 0, fmt.Errorf("invalid balance history key %q: %w", iter.Key(), parseErr)
+			}
+			seq = last + 1
+		}
+		if err := iter.Close(); err != nil {
+			return 0, fmt.Errorf("failed to close iterator: %w", err)
+		}
 	}
 
-	return nil
+	s.balanceSeq[addr] = seq + 1
+	return seq, nil
 }
 
 // SetBalance sets the balance for an address at a specific block
@@ -553,12 +699,27 @@ func (s *PebbleStorage) SetBalance(ctx context.Context, addr common.Address, blo
 	return s.UpdateBalance(ctx, addr, blockNumber, delta, common.Hash{})
 }
 
-// GetAddressStats returns aggregated statistics for an address
+// GetAddressStats returns aggregated statistics for an address.
+// Running aggregates maintained by IndexAddressStats are returned directly;
+// addresses without them are computed by scanning their transactions.
 func (s *PebbleStorage) GetAddressStats(ctx context.Context, addr common.Address) (*AddressStats, error) {
 	if err := s.ensureNotClosed(); err != nil {
 		return nil, err
 	}
 
+	stats, err := s.storedAddressStats(addr)
+	if err == nil {
+		return stats, nil
+	}
+	if !errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("failed to get address stats: %w", err)
+	}
+
+	return s.scanAddressStats(ctx, addr)
+}
+
+// scanAddressStats computes statistics for an address from its indexed transactions
+func (s *PebbleStorage) scanAddressStats(ctx context.Context, addr common.Address) (*AddressStats, error) {
 	stats := &AddressStats{
 		Address:            addr,
 		TotalGasCost:       big.NewInt(0),
@@ -647,6 +808,12 @@ func (s *PebbleStorage) GetAddressStats(ctx context.Context, addr common.Address
 				if ts > stats.LastTransactionTimestamp {
 					stats.LastTransactionTimestamp = ts
 				}
+				if stats.FirstSeenBlock == 0 || location.BlockHeight < stats.FirstSeenBlock {
+					stats.FirstSeenBlock = location.BlockHeight
+				}
+				if location.BlockHeight > stats.LastSeenBlock {
+					stats.LastSeenBlock = location.BlockHeight
+				}
 			}
 		}
 	}
@@ -2,11 +2,15 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
 
 	"github.com/cockroachdb/pebble"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/0xmhha/indexer-go/internal/constants"
 )
 
 // ========== Log Reader Methods ==========
@@ -30,7 +34,7 @@ func (s *PebbleStorage) GetLogs(ctx context.Context, filter *LogFilter) ([]*type
 	toBlock := filter.ToBlock
 	if toBlock == 0 {
 		latestHeight, err := s.GetLatestHeight(ctx)
-		if err != nil && err != ErrNotFound {
+		if err != nil && !errors.Is(err, ErrNotFound) {
 			return nil, fmt.Errorf("failed to get latest height: %w", err)
 		}
 		toBlock = latestHeight
@@ -66,7 +70,7 @@ func (s *PebbleStorage) GetLogs(ctx context.Context, filter *LogFilter) ([]*type
 		// Strategy 3: Scan all logs in block range
 		for blockNum := filter.FromBlock; blockNum <= toBlock; blockNum++ {
 			blockLogs, err := s.GetLogsByBlock(ctx, blockNum)
-			if err != nil && err != ErrNotFound {
+			if err != nil && !errors.Is(err, ErrNotFound) {
 				return nil, err
 			}
 			logs = append(logs, blockLogs...)
@@ -161,6 +165,88 @@ func (s *PebbleStorage) GetLogsByTopic(ctx context.Context, topic common.Hash, t
 	return s.getLogsByTopicRange(ctx, topic, topicIndex, fromBlock, toBlock)
 }
 
+// GetLogsIndexed returns logs emitted by address with the given topic0 by seeking
+// directly into the (address, topic0) index instead of scanning the address index
+func (s *PebbleStorage) GetLogsIndexed(ctx context.Context, address common.Address, topic0 common.Hash, fromBlock, toBlock uint64, limit, offset int) ([]*types.Log, error) {
+	if err := s.ensureNotClosed(); err != nil {
+		return nil, err
+	}
+	if !s.config.LogAddressTopicIndex {
+		return nil, ErrLogIndexDisabled
+	}
+	if fromBlock > toBlock {
+		return nil, fmt.Errorf("fromBlock (%d) cannot be greater than toBlock (%d)", fromBlock, toBlock)
+	}
+
+	// Validate pagination parameters
+	if limit <= 0 {
+		limit = constants.DefaultPaginationLimit
+	}
+	if limit > constants.DefaultMaxPaginationLimit {
+		limit = constants.DefaultMaxPaginationLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	prefix := LogAddressTopicIndexKeyPrefix(address, topic0)
+	// toBlock+1 would wrap around at the last height, so bound by the prefix
+	upper := prefixUpperBound(prefix)
+	if toBlock < math.MaxUint64 {
+		upper = LogAddressTopicIndexKey(address, topic0, toBlock+1, 0, 0)
+	}
+	iter, err := s.db.NewIter(&pebble.IterOptions{
+		LowerBound: LogAddressTopicIndexKey(address, topic0, fromBlock, 0, 0),
+		UpperBound: upper,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create iterator: %w", err)
+	}
+	defer iter.Close()
+
+	logs := make([]*types.Log, 0, limit)
+	skipped := 0
+
+	for iter.First(); iter.Valid() && len(logs) < limit; iter.Next() {
+		// Skip offset items
+		if skipped < offset {
+			skipped++
+			continue
+		}
+
+		// Parse key to extract block, tx, log indexes
+		key := string(iter.Key())
+		var blockNum uint64
+		var txIndex, logIndex uint
+		if _, err := fmt.Sscanf(key[len(string(prefix)):], "%020d/%06d/%06d", &blockNum, &txIndex, &logIndex); err != nil {
+			return nil, fmt.Errorf("%w log index key %q: %w", ErrDecodeFailed, key, err)
+		}
+
+		// Get log data
+		logData, closer, err := s.db.Get(LogKey(blockNum, txIndex, logIndex))
+		if err != nil {
+			if errors.Is(err, pebble.ErrNotFound) {
+				err = ErrNotFound
+			}
+			return nil, fmt.Errorf("failed to get log %d/%d/%d: %w", blockNum, txIndex, logIndex, err)
+		}
+
+		log, err := DecodeLog(logData)
+		closer.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%w log %d/%d/%d: %w", ErrDecodeFailed, blockNum, txIndex, logIndex, err)
+		}
+
+		logs = append(logs, log)
+	}
+
+	if err := iter.Error(); err != nil {
+		return nil, fmt.Errorf("iterator error: %w", err)
+	}
+
+	return logs, nil
+}
+
 // ========== Log Writer Methods ==========
 
 // IndexLogs indexes logs from receipts
@@ -214,7 +300,7 @@ func (s *PebbleStorage) indexLogToBatch(batch *pebbleBatch, log *types.Log) erro
 	// Encode log data
 	encoded, err := EncodeLog(log)
 	if err != nil {
-		return fmt.Errorf("failed to encode log: %w", err)
+		return fmt.Errorf("%w log: %w", ErrEncodeFailed, err)
 	}
 
 	// Store log data
@@ -255,6 +341,15 @@ func (s *PebbleStorage) indexLogToBatch(batch *pebbleBatch, log *types.Log) erro
 		}
 	}
 
+	// Index by address and topic0 when enabled
+	if s.config.LogAddressTopicIndex && len(log.Topics) > 0 {
+		addrTopicKey := LogAddressTopicIndexKey(log.Address, log.Topics[0], log.BlockNumber, log.TxIndex, log.Index)
+		if err := batch.batch.Set(addrTopicKey, []byte{1}, nil); err != nil {
+			return fmt.Errorf("failed to store address/topic0 index: %w", err)
+		}
+		batch.count++
+	}
+
 	// Index by block
 	blockKey := LogBlockIndexKey(log.BlockNumber, log.TxIndex, log.Index)
 	if err := batch.batch.Set(blockKey, []byte{1}, nil); err != nil {
@@ -2,9 +2,12 @@ package storage
 
 import (
 	"context"
+	"errors"
+	"math"
 	"os"
 	"testing"
 
+	"github.com/cockroachdb/pebble"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 )
@@ -600,3 +603,133 @@ func TestPebbleStorage_Logs_ReadOnly(t *testing.T) {
 		t.Errorf("IndexLog() on read-only storage error = %v, want ErrReadOnly", err)
 	}
 }
+
+func TestPebbleStorage_GetLogsIndexed(t *testing.T) {
+	cfg := DefaultConfig(t.TempDir())
+	cfg.LogAddressTopicIndex = true
+	storage, err := NewPebbleStorage(cfg)
+	if err != nil {
+		t.Fatalf("NewPebbleStorage() error = %v", err)
+	}
+	defer storage.Close()
+
+	ctx := context.Background()
+
+	addr1 := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	addr2 := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	transfer := common.HexToHash("0xaaaa")
+	approval := common.HexToHash("0xbbbb")
+
+	// Interleave addresses and topics across blocks, plus a log without topics
+	var logs []*types.Log
+	for block := uint64(100); block < 110; block++ {
+		logs = append(logs,
+			createTestLog(block, 0, 0, addr1, []common.Hash{transfer}, nil),
+			createTestLog(block, 0, 1, addr1, []common.Hash{approval}, nil),
+			createTestLog(block, 1, 2, addr2, []common.Hash{transfer}, nil),
+			createTestLog(block, 1, 3, addr1, nil, nil),
+		)
+	}
+	if err := storage.IndexLogs(ctx, logs); err != nil {
+		t.Fatalf("IndexLogs() error = %v", err)
+	}
+
+	for _, tc := range []struct {
+		address common.Address
+		topic0  common.Hash
+		from    uint64
+		to      uint64
+	}{
+		{addr1, transfer, 100, 109},
+		{addr1, approval, 102, 105},
+		{addr2, transfer, 0, 200},
+		{addr2, approval, 100, 109},
+	} {
+		scanned, err := storage.GetLogs(ctx, &LogFilter{
+			FromBlock: tc.from,
+			ToBlock:   tc.to,
+			Addresses: []common.Address{tc.address},
+			Topics:    [][]common.Hash{{tc.topic0}},
+		})
+		if err != nil {
+			t.Fatalf("GetLogs() error = %v", err)
+		}
+
+		indexed, err := storage.GetLogsIndexed(ctx, tc.address, tc.topic0, tc.from, tc.to, 1000, 0)
+		if err != nil {
+			t.Fatalf("GetLogsIndexed() error = %v", err)
+		}
+
+		if len(indexed) != len(scanned) {
+			t.Fatalf("GetLogsIndexed(%s, %s) returned %d logs, scan returned %d", tc.address.Hex(), tc.topic0.Hex(), len(indexed), len(scanned))
+		}
+		for i := range scanned {
+			if indexed[i].BlockNumber != scanned[i].BlockNumber || indexed[i].Index != scanned[i].Index || indexed[i].Address != scanned[i].Address {
+				t.Errorf("log %d: indexed block %d index %d, scanned block %d index %d",
+					i, indexed[i].BlockNumber, indexed[i].Index, scanned[i].BlockNumber, scanned[i].Index)
+			}
+		}
+	}
+
+	// Pagination walks the same ordered results
+	page, err := storage.GetLogsIndexed(ctx, addr1, transfer, 100, 109, 3, 2)
+	if err != nil {
+		t.Fatalf("GetLogsIndexed() error = %v", err)
+	}
+	if len(page) != 3 || page[0].BlockNumber != 102 || page[2].BlockNumber != 104 {
+		t.Errorf("GetLogsIndexed() page = %d logs starting at block %d, want 3 starting at 102", len(page), page[0].BlockNumber)
+	}
+
+	if _, err := storage.GetLogsIndexed(ctx, addr1, transfer, 109, 100, 10, 0); err == nil {
+		t.Error("GetLogsIndexed() should fail when fromBlock > toBlock")
+	}
+
+	// A range ending at the last height still finds the indexed logs
+	tail, err := storage.GetLogsIndexed(ctx, addr1, transfer, 105, math.MaxUint64, 1000, 0)
+	if err != nil {
+		t.Fatalf("GetLogsIndexed() error = %v", err)
+	}
+	if len(tail) != 5 || tail[0].BlockNumber != 105 {
+		t.Errorf("GetLogsIndexed(105, MaxUint64) returned %d logs, want 5 starting at block 105", len(tail))
+	}
+
+	// An index entry whose log is gone is reported rather than skipped
+	if err := storage.db.Delete(LogKey(103, 0, 0), pebble.Sync); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := storage.GetLogsIndexed(ctx, addr1, transfer, 100, 109, 10, 0); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetLogsIndexed() with a missing log error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestPebbleStorage_GetLogsIndexed_Disabled(t *testing.T) {
+	storage, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	addr := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	topic0 := common.HexToHash("0xaaaa")
+
+	if err := storage.IndexLog(ctx, createTestLog(100, 0, 0, addr, []common.Hash{topic0}, nil)); err != nil {
+		t.Fatalf("IndexLog() error = %v", err)
+	}
+
+	reader := storage.(IndexedLogReader)
+	if _, err := reader.GetLogsIndexed(ctx, addr, topic0, 0, 200, 10, 0); err != ErrLogIndexDisabled {
+		t.Errorf("GetLogsIndexed() error = %v, want ErrLogIndexDisabled", err)
+	}
+
+	// No index entries are written while the index is disabled
+	pebbleStorage := storage.(*PebbleStorage)
+	iter, err := pebbleStorage.db.NewIter(&pebble.IterOptions{
+		LowerBound: []byte(prefixIdxLogsAddrTopic0),
+		UpperBound: []byte(prefixIdxLogsAddrTopic0 + "\xff"),
+	})
+	if err != nil {
+		t.Fatalf("NewIter() error = %v", err)
+	}
+	defer iter.Close()
+	if iter.First() {
+		t.Errorf("found index key %q with the index disabled", iter.Key())
+	}
+}
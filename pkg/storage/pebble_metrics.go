@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"strconv"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// newPebbleMetrics copies the fields worth reporting out of pebble.Metrics
+func newPebbleMetrics(m *pebble.Metrics) *PebbleMetrics {
+	pm := &PebbleMetrics{
+		Levels:                make([]LevelMetrics, len(m.Levels)),
+		BlockCacheSize:        m.BlockCache.Size,
+		BlockCacheHits:        m.BlockCache.Hits,
+		BlockCacheMisses:      m.BlockCache.Misses,
+		CompactionCount:       m.Compact.Count,
+		CompactionDebt:        m.Compact.EstimatedDebt,
+		CompactionsInProgress: m.Compact.NumInProgress,
+		MemTableSize:          m.MemTable.Size,
+		WALFiles:              m.WAL.Files,
+		WALSize:               m.WAL.Size,
+		WALPhysicalSize:       m.WAL.PhysicalSize,
+		ReadAmplification:     m.ReadAmp(),
+	}
+	if lookups := m.BlockCache.Hits + m.BlockCache.Misses; lookups > 0 {
+		pm.BlockCacheHitRate = float64(m.BlockCache.Hits) / float64(lookups)
+	}
+	for i, level := range m.Levels {
+		pm.Levels[i] = LevelMetrics{
+			Level:     i,
+			NumFiles:  level.NumFiles,
+			Size:      level.Size,
+			Sublevels: level.Sublevels,
+			Score:     level.Score,
+		}
+	}
+	return pm
+}
+
+// metricsCollector exports PebbleMetrics as Prometheus gauges and counters,
+// reading them from the database on every scrape
+type metricsCollector struct {
+	storage *PebbleStorage
+
+	levelFiles        *prometheus.Desc
+	levelSize         *prometheus.Desc
+	blockCacheSize    *prometheus.Desc
+	blockCacheHits    *prometheus.Desc
+	blockCacheMisses  *prometheus.Desc
+	blockCacheHitRate *prometheus.Desc
+	compactions       *prometheus.Desc
+	compactionDebt    *prometheus.Desc
+	memTableSize      *prometheus.Desc
+	walFiles          *prometheus.Desc
+	walSize           *prometheus.Desc
+	readAmp           *prometheus.Desc
+	diskUsage         *prometheus.Desc
+}
+
+// NewMetricsCollector returns a Prometheus collector for the engine metrics
+// of storage. Register it once per database, e.g. with prometheus.Register.
+func NewMetricsCollector(storage *PebbleStorage) prometheus.Collector {
+	desc := func(name, help string, labels ...string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName("indexer", "pebble", name), help, labels, nil)
+	}
+	return &metricsCollector{
+		storage:           storage,
+		levelFiles:        desc("level_files", "Number of sstables in each LSM level", "level"),
+		levelSize:         desc("level_size_bytes", "Total size of the sstables in each LSM level", "level"),
+		blockCacheSize:    desc("block_cache_size_bytes", "Bytes held by the block cache"),
+		blockCacheHits:    desc("block_cache_hits_total", "Block cache hits since the database was opened"),
+		blockCacheMisses:  desc("block_cache_misses_total", "Block cache misses since the database was opened"),
+		blockCacheHitRate: desc("block_cache_hit_rate", "Block cache hits divided by lookups"),
+		compactions:       desc("compactions_total", "Compactions run since the database was opened"),
+		compactionDebt:    desc("compaction_debt_bytes", "Estimated bytes still to be compacted"),
+		memTableSize:      desc("memtable_size_bytes", "Bytes allocated by memtables"),
+		walFiles:          desc("wal_files", "Number of live WAL files"),
+		walSize:           desc("wal_size_bytes", "Physical size of the WAL files on disk"),
+		readAmp:           desc("read_amplification", "Number of sublevels a point read may visit"),
+		diskUsage:         desc("disk_usage_bytes", "Disk space used by the database"),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *metricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{
+		c.levelFiles, c.levelSize, c.blockCacheSize, c.blockCacheHits, c.blockCacheMisses,
+		c.blockCacheHitRate, c.compactions, c.compactionDebt, c.memTableSize, c.walFiles,
+		c.walSize, c.readAmp, c.diskUsage,
+	} {
+		ch <- d
+	}
+}
+
+// Collect implements prometheus.Collector. A closed storage reports nothing.
+func (c *metricsCollector) Collect(ch chan<- prometheus.Metric) {
+	c.storage.closeMu.RLock()
+	if c.storage.ensureNotClosed() != nil {
+		c.storage.closeMu.RUnlock()
+		return
+	}
+	m := c.storage.db.Metrics()
+	c.storage.closeMu.RUnlock()
+	pm := newPebbleMetrics(m)
+
+	gauge := func(d *prometheus.Desc, value float64, labels ...string) {
+		ch <- prometheus.MustNewConstMetric(d, prometheus.GaugeValue, value, labels...)
+	}
+	counter := func(d *prometheus.Desc, value float64) {
+		ch <- prometheus.MustNewConstMetric(d, prometheus.CounterValue, value)
+	}
+	for _, level := range pm.Levels {
+		label := strconv.Itoa(level.Level)
+		gauge(c.levelFiles, float64(level.NumFiles), label)
+		gauge(c.levelSize, float64(level.Size), label)
+	}
+	gauge(c.blockCacheSize, float64(pm.BlockCacheSize))
+	counter(c.blockCacheHits, float64(pm.BlockCacheHits))
+	counter(c.blockCacheMisses, float64(pm.BlockCacheMisses))
+	gauge(c.blockCacheHitRate, pm.BlockCacheHitRate)
+	counter(c.compactions, float64(pm.CompactionCount))
+	gauge(c.compactionDebt, float64(pm.CompactionDebt))
+	gauge(c.memTableSize, float64(pm.MemTableSize))
+	gauge(c.walFiles, float64(pm.WALFiles))
+	gauge(c.walSize, float64(pm.WALPhysicalSize))
+	gauge(c.readAmp, float64(pm.ReadAmplification))
+	gauge(c.diskUsage, float64(m.DiskSpaceUsage()))
+}
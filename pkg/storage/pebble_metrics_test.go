@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// setupMetricsStorage opens a storage with flushed blocks so sstables exist
+// and a second read has gone through the block cache
+func setupMetricsStorage(t *testing.T) *PebbleStorage {
+	t.Helper()
+
+	storage := setupTestPebbleStorage(t)
+
+	ctx := context.Background()
+	for height := uint64(0); height < 50; height++ {
+		if err := storage.SetBlock(ctx, createTestBlockWithTransactions(height, 3)); err != nil {
+			t.Fatalf("SetBlock(%d) error = %v", height, err)
+		}
+	}
+	if err := storage.db.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	for height := uint64(0); height < 50; height++ {
+		if _, err := storage.GetBlock(ctx, height); err != nil {
+			t.Fatalf("GetBlock(%d) error = %v", height, err)
+		}
+	}
+	return storage
+}
+
+func TestPebbleStorage_GetStats_PebbleMetrics(t *testing.T) {
+	storage := setupMetricsStorage(t)
+
+	stats, err := storage.GetStats(context.Background())
+	if err != nil {
+		t.Fatalf("GetStats() error = %v", err)
+	}
+	pm := stats.Pebble
+	if pm == nil {
+		t.Fatal("Stats.Pebble is nil")
+	}
+
+	if len(pm.Levels) != 7 {
+		t.Fatalf("len(Levels) = %d, want 7", len(pm.Levels))
+	}
+	var files int64
+	for i, level := range pm.Levels {
+		if level.Level != i {
+			t.Errorf("Levels[%d].Level = %d", i, level.Level)
+		}
+		files += level.NumFiles
+	}
+	if files == 0 {
+		t.Error("no sstables reported after a flush")
+	}
+	if pm.BlockCacheHits+pm.BlockCacheMisses == 0 {
+		t.Error("no block cache lookups reported after reads")
+	}
+	if pm.BlockCacheHitRate < 0 || pm.BlockCacheHitRate > 1 {
+		t.Errorf("BlockCacheHitRate = %v, want within [0, 1]", pm.BlockCacheHitRate)
+	}
+	if pm.WALFiles == 0 {
+		t.Error("WALFiles = 0, want a live WAL")
+	}
+}
+
+func TestNewMetricsCollector(t *testing.T) {
+	storage := setupMetricsStorage(t)
+
+	registry := prometheus.NewRegistry()
+	if err := registry.Register(NewMetricsCollector(storage)); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	found := make(map[string]int)
+	for _, family := range families {
+		found[family.GetName()] = len(family.GetMetric())
+		switch family.GetName() {
+		case "indexer_pebble_block_cache_hits_total", "indexer_pebble_block_cache_misses_total", "indexer_pebble_compactions_total":
+			if family.GetType().String() != "COUNTER" {
+				t.Errorf("%s has type %s, want COUNTER", family.GetName(), family.GetType())
+			}
+		}
+	}
+	if found["indexer_pebble_level_files"] != 7 {
+		t.Errorf("indexer_pebble_level_files has %d series, want one per level", found["indexer_pebble_level_files"])
+	}
+	for _, name := range []string{"indexer_pebble_block_cache_hit_rate", "indexer_pebble_compactions_total", "indexer_pebble_wal_size_bytes"} {
+		if found[name] != 1 {
+			t.Errorf("metric %s not exported", name)
+		}
+	}
+
+	// A closed storage is skipped instead of panicking the scrape, also when
+	// Close runs during scrapes
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 50; i++ {
+			if _, err := registry.Gather(); err != nil {
+				t.Errorf("Gather() during Close error = %v", err)
+				return
+			}
+		}
+	}()
+	storage.Close()
+	<-done
+	if _, err := registry.Gather(); err != nil {
+		t.Errorf("Gather() after Close error = %v", err)
+	}
+}
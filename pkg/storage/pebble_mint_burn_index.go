@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/cockroachdb/pebble"
+	"go.uber.org/zap"
+)
+
+// migrateMintBurnEvents moves mint and burn records stored under the legacy
+// {block}/{txIndex}/{logIndex} keys with unpadded indexes to the fixed-width
+// keys, and adds the minter and burner index entries, which were not written
+// before. A marker records the migration as done, so later opens skip the scan.
+func (s *PebbleStorage) migrateMintBurnEvents() error {
+	_, closer, err := s.db.Get(MintBurnMigratedKey())
+	if err == nil {
+		closer.Close()
+		return nil
+	}
+	if err != pebble.ErrNotFound {
+		return fmt.Errorf("failed to get mint and burn migration marker: %w", err)
+	}
+
+	batch := s.db.NewBatch()
+	defer batch.Close()
+
+	mints, err := s.migrateEventRecords(batch, MintEventKeyPrefix(), func(data []byte) ([]byte, []byte, error) {
+		event, err := DecodeMintEvent(data)
+		if err != nil {
+			return nil, nil, err
+		}
+		return MintEventKey(event.BlockNumber, event.TxIndex, event.LogIndex),
+			MintMinterIndexKey(event.Minter, event.BlockNumber, event.TxIndex, event.LogIndex), nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to migrate mint events: %w", err)
+	}
+	burns, err := s.migrateEventRecords(batch, BurnEventKeyPrefix(), func(data []byte) ([]byte, []byte, error) {
+		event, err := DecodeBurnEvent(data)
+		if err != nil {
+			return nil, nil, err
+		}
+		return BurnEventKey(event.BlockNumber, event.TxIndex, event.LogIndex),
+			BurnBurnerIndexKey(event.Burner, event.BlockNumber, event.TxIndex, event.LogIndex), nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to migrate burn events: %w", err)
+	}
+
+	if err := batch.Set(MintBurnMigratedKey(), []byte{1}, nil); err != nil {
+		return err
+	}
+	if err := batch.Commit(pebble.Sync); err != nil {
+		return fmt.Errorf("failed to commit mint and burn migration: %w", err)
+	}
+	if mints > 0 || burns > 0 {
+		s.logger.Info("Migrated mint and burn events to log position keys",
+			zap.Int("mints", mints),
+			zap.Int("burns", burns),
+		)
+	}
+	return nil
+}
+
+// migrateEventRecords rewrites the records under prefix to the keys returned
+// by keys and adds their index entries to batch. It returns the number of
+// records seen.
+func (s *PebbleStorage) migrateEventRecords(batch *nsBatch, prefix []byte, keys func(data []byte) (key, indexKey []byte, err error)) (int, error) {
+	iter, err := s.db.NewIter(&pebble.IterOptions{
+		LowerBound: prefix,
+		UpperBound: incrementPrefix(prefix),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to create iterator: %w", err)
+	}
+	defer iter.Close()
+
+	var migrated int
+	for iter.First(); iter.Valid(); iter.Next() {
+		key, indexKey, err := keys(iter.Value())
+		if err != nil {
+			s.logger.Warn("Skipping undecodable event record", zap.ByteString("key", iter.Key()))
+			continue
+		}
+		if !bytes.Equal(key, iter.Key()) {
+			if err := batch.Set(key, iter.Value(), nil); err != nil {
+				return 0, err
+			}
+			if err := batch.Delete(iter.Key(), nil); err != nil {
+				return 0, err
+			}
+		}
+		if err := batch.Set(indexKey, key, nil); err != nil {
+			return 0, err
+		}
+		migrated++
+	}
+	if err := iter.Error(); err != nil {
+		return 0, fmt.Errorf("iterator error: %w", err)
+	}
+	return migrated, nil
+}
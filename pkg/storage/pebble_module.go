@@ -105,7 +105,7 @@ func (s *PebbleStorage) GetModulesByAccount(ctx context.Context, account common.
 	for _, ref := range refs[start:end] {
 		record, err := s.GetInstalledModule(ctx, ref.account, ref.module)
 		if err != nil {
-			s.logger.Warn("failed to get installed module",
+			s.loggerFor(ctx).Warn("failed to get installed module",
 				zap.String("account", ref.account.Hex()),
 				zap.String("module", ref.module.Hex()),
 				zap.Error(err))
@@ -180,7 +180,7 @@ func (s *PebbleStorage) GetModulesByType(ctx context.Context, moduleType ModuleT
 	for _, ref := range refs[start:end] {
 		record, err := s.GetInstalledModule(ctx, ref.account, ref.module)
 		if err != nil {
-			s.logger.Warn("failed to get installed module",
+			s.loggerFor(ctx).Warn("failed to get installed module",
 				zap.String("account", ref.account.Hex()),
 				zap.String("module", ref.module.Hex()),
 				zap.Error(err))
@@ -248,7 +248,7 @@ func (s *PebbleStorage) GetAccountModules(ctx context.Context, account common.Ad
 	for iter.First(); iter.Valid(); iter.Next() {
 		var record InstalledModule
 		if err := json.Unmarshal(iter.Value(), &record); err != nil {
-			s.logger.Warn("failed to unmarshal installed module",
+			s.loggerFor(ctx).Warn("failed to unmarshal installed module",
 				zap.String("key", string(iter.Key())),
 				zap.Error(err))
 			continue
@@ -309,7 +309,7 @@ func (s *PebbleStorage) GetRecentModuleEvents(ctx context.Context, limit int) ([
 
 			record, err := s.GetInstalledModule(ctx, account, module)
 			if err != nil {
-				s.logger.Warn("failed to get installed module",
+				s.loggerFor(ctx).Warn("failed to get installed module",
 					zap.String("account", account.Hex()),
 					zap.String("module", module.Hex()),
 					zap.Error(err))
@@ -387,7 +387,7 @@ func (s *PebbleStorage) ListModuleStats(ctx context.Context, limit, offset int)
 	for iter.First(); iter.Valid(); iter.Next() {
 		var stats ModuleStats
 		if err := json.Unmarshal(iter.Value(), &stats); err != nil {
-			s.logger.Warn("failed to unmarshal module stats",
+			s.loggerFor(ctx).Warn("failed to unmarshal module stats",
 				zap.String("key", string(iter.Key())),
 				zap.Error(err))
 			continue
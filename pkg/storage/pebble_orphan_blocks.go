@@ -0,0 +1,429 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// orphanBlockRecord is the stored form of an OrphanBlock
+type orphanBlockRecord struct {
+	// Block is the RLP-encoded block as it was stored under BlockKey
+	Block      []byte    `json:"block"`
+	ForkHeight uint64    `json:"fork_height"`
+	OrphanedAt time.Time `json:"orphaned_at"`
+}
+
+// RollbackToHeight removes the blocks above height and sets the latest height
+// to height in one synced batch. With Config.KeepOrphanBlocks each removed block
+// is moved to OrphanBlockKey instead of being deleted. The batch also removes
+// what was indexed from the removed blocks: their transactions and hash
+// indexes, receipts, logs and log indexes, timestamp indexes and address index
+// entries, and takes their transactions back out of the address statistics and
+// the transaction count.
+func (s *PebbleStorage) RollbackToHeight(ctx context.Context, height uint64) error {
+	if err := s.ensureNotClosed(); err != nil {
+		return err
+	}
+	if err := s.ensureNotReadOnly(); err != nil {
+		return err
+	}
+
+	latest, err := s.GetLatestHeight(ctx)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	if height >= latest {
+		return nil
+	}
+
+	// Hold the address statistics lock until commit so IndexAddressStats
+	// cannot interleave with the reverted aggregates
+	s.addrStatsMu.Lock()
+	defer s.addrStatsMu.Unlock()
+
+	batch := s.db.NewBatch()
+	defer batch.Close()
+
+	r := &rollback{
+		txHashes:  make(map[common.Hash]bool),
+		addresses: make(map[common.Address]bool),
+		stats: &addressStatsUpdate{
+			storage: s,
+			stats:   make(map[common.Address]*AddressStats),
+			peers:   make(map[string]bool),
+		},
+	}
+
+	orphanedAt := time.Now().UTC()
+	for h := height + 1; h <= latest; h++ {
+		value, closer, err := s.db.Get(BlockKey(h))
+		if err != nil {
+			if err == pebble.ErrNotFound {
+				continue
+			}
+			return fmt.Errorf("failed to get block %d: %w", h, err)
+		}
+		data := append([]byte(nil), value...)
+		closer.Close()
+
+		block, err := DecodeBlock(data)
+		if err != nil {
+			return fmt.Errorf("failed to decode block %d: %w", h, err)
+		}
+		hash := block.Hash()
+
+		if s.config.KeepOrphanBlocks {
+			record, err := json.Marshal(&orphanBlockRecord{Block: data, ForkHeight: height, OrphanedAt: orphanedAt})
+			if err != nil {
+				return fmt.Errorf("failed to marshal orphan block %d: %w", h, err)
+			}
+			if err := batch.Set(OrphanBlockKey(hash), record, nil); err != nil {
+				return fmt.Errorf("failed to orphan block %d: %w", h, err)
+			}
+		}
+		if err := s.rollbackBlockIndexes(ctx, batch, block, r); err != nil {
+			return err
+		}
+		if err := batch.Delete(BlockHashIndexKey(hash), nil); err != nil {
+			return fmt.Errorf("failed to delete block hash index %d: %w", h, err)
+		}
+		if err := batch.Delete(HeaderKey(h), nil); err != nil {
+			return fmt.Errorf("failed to delete block header %d: %w", h, err)
+		}
+		if err := deleteUnclesInBatch(batch, h); err != nil {
+			return err
+		}
+		if err := batch.Delete(BlockKey(h), nil); err != nil {
+			return fmt.Errorf("failed to delete block %d: %w", h, err)
+		}
+		r.invalidated = append(r.invalidated, blockCacheKey(h), blockHashCacheKey(hash))
+	}
+
+	if err := s.rollbackAddressIndexes(batch, r); err != nil {
+		return err
+	}
+	for addr, stats := range r.stats.stats {
+		data, err := EncodeAddressStats(stats)
+		if err != nil {
+			return err
+		}
+		if err := batch.Set(AddressStatsKey(addr), data, nil); err != nil {
+			return fmt.Errorf("failed to revert address stats: %w", err)
+		}
+	}
+
+	if err := batch.Set(LatestHeightKey(), EncodeUint64(height), nil); err != nil {
+		return fmt.Errorf("failed to set latest height: %w", err)
+	}
+	if err := s.commitWithTxCountRemoved(batch, r.txCount, pebble.Sync); err != nil {
+		return fmt.Errorf("failed to commit rollback to %d: %w", height, err)
+	}
+	s.readCache.remove(r.invalidated...)
+	return nil
+}
+
+// rollback collects the changes of a RollbackToHeight that span its blocks
+type rollback struct {
+	// txHashes are the transactions whose indexes were removed
+	txHashes map[common.Hash]bool
+	// addresses may have address index entries for txHashes
+	addresses map[common.Address]bool
+	// stats holds the address statistics with the removed transactions taken out
+	stats *addressStatsUpdate
+	// txCount is the number of removed transactions
+	txCount uint64
+	// invalidated are the read cache keys of the removed data
+	invalidated []string
+}
+
+// rollbackBlockIndexes adds the removal of everything indexed from block,
+// other than the block itself, to batch
+func (s *PebbleStorage) rollbackBlockIndexes(ctx context.Context, batch *nsBatch, block *types.Block, r *rollback) error {
+	height := block.NumberU64()
+
+	// Only revert address statistics that counted this block
+	counted := false
+	marker, closer, err := s.db.Get(AddressStatsBlockKey(height))
+	switch {
+	case err == nil:
+		blockHash := block.Hash()
+		counted = bytes.Equal(marker, blockHash[:])
+		closer.Close()
+	case err != pebble.ErrNotFound:
+		return fmt.Errorf("failed to get address stats marker: %w", err)
+	}
+	if counted {
+		if err := batch.Delete(AddressStatsBlockKey(height), nil); err != nil {
+			return fmt.Errorf("failed to delete address stats marker %d: %w", height, err)
+		}
+		if err := batch.Delete(AddressStatsEntriesKey(height), nil); err != nil {
+			return fmt.Errorf("failed to delete address stats entries %d: %w", height, err)
+		}
+	}
+
+	var prevCumulativeGas uint64
+	for _, tx := range block.Transactions() {
+		txHash := tx.Hash()
+		r.txCount++
+
+		location, err := s.getTxLocation(txHash)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return err
+		}
+		// A transaction included again at another height keeps its indexes
+		if location != nil && location.BlockHeight != height {
+			continue
+		}
+
+		receipt, err := s.getStoredReceipt(txHash)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return err
+		}
+
+		for _, key := range [][]byte{TransactionHashIndexKey(txHash), ReceiptKey(txHash), ContractAddressKey(txHash), RevertReasonKey(txHash)} {
+			if err := batch.Delete(key, nil); err != nil {
+				return fmt.Errorf("failed to delete indexes of transaction %s: %w", txHash.Hex(), err)
+			}
+		}
+		r.txHashes[txHash] = true
+		r.invalidated = append(r.invalidated, receiptCacheKey(txHash))
+
+		if receipt != nil {
+			// The stored receipt does not keep these, so restore them as
+			// IndexAddressStats saw them
+			receipt.GasUsed = receipt.CumulativeGasUsed - prevCumulativeGas
+			receipt.EffectiveGasPrice = EffectiveGasPrice(tx, receipt, block.BaseFee())
+			prevCumulativeGas = receipt.CumulativeGasUsed
+		}
+
+		from, err := s.transactionSender(ctx, location, tx)
+		if err != nil {
+			continue
+		}
+		r.addresses[from] = true
+		if to := tx.To(); to != nil {
+			r.addresses[*to] = true
+		}
+		if meta, err := s.GetFeeDelegationTxMeta(ctx, txHash); err == nil && meta != nil {
+			r.addresses[meta.FeePayer] = true
+		}
+		if receipt == nil {
+			continue
+		}
+		if receipt.ContractAddress != (common.Address{}) {
+			r.addresses[receipt.ContractAddress] = true
+		}
+		for _, log := range receipt.Logs {
+			r.addresses[log.Address] = true
+			if len(log.Topics) >= 3 && log.Topics[0] == transferEventTopic {
+				r.addresses[common.BytesToAddress(log.Topics[1].Bytes())] = true
+				r.addresses[common.BytesToAddress(log.Topics[2].Bytes())] = true
+			}
+		}
+
+		if counted {
+			e := newAddressStatsEntry(tx, receipt, from)
+			if err := r.stats.removeEntry(&e); err != nil {
+				return err
+			}
+		}
+	}
+
+	txPrefix := TransactionKeyPrefix(height)
+	if err := batch.DeleteRange(txPrefix, prefixUpperBound(txPrefix), nil); err != nil {
+		return fmt.Errorf("failed to delete transactions of block %d: %w", height, err)
+	}
+	if err := s.rollbackLogIndexes(batch, height); err != nil {
+		return err
+	}
+
+	value, closer, err := s.db.Get(HeightTimestampKey(height))
+	switch {
+	case err == nil:
+		timestamp, decodeErr := DecodeUint64(value)
+		closer.Close()
+		if decodeErr == nil {
+			if err := batch.Delete(BlockTimestampKey(timestamp, height), nil); err != nil {
+				return fmt.Errorf("failed to delete timestamp index %d: %w", height, err)
+			}
+		}
+		if err := batch.Delete(HeightTimestampKey(height), nil); err != nil {
+			return fmt.Errorf("failed to delete timestamp index %d: %w", height, err)
+		}
+	case err != pebble.ErrNotFound:
+		return fmt.Errorf("failed to get block timestamp %d: %w", height, err)
+	}
+	return nil
+}
+
+// rollbackLogIndexes adds the removal of the logs stored for height, and of
+// their address and topic index entries, to batch
+func (s *PebbleStorage) rollbackLogIndexes(batch *nsBatch, height uint64) error {
+	prefix := LogBlockKeyPrefix(height)
+	iter, err := s.db.NewIter(&pebble.IterOptions{
+		LowerBound: prefix,
+		UpperBound: prefixUpperBound(prefix),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create iterator: %w", err)
+	}
+	defer iter.Close()
+
+	for iter.First(); iter.Valid(); iter.Next() {
+		log, err := DecodeLog(iter.Value())
+		if err != nil {
+			return fmt.Errorf("failed to decode log of block %d: %w", height, err)
+		}
+		keys := [][]byte{LogAddressIndexKey(log.Address, height, log.TxIndex, log.Index)}
+		topicKeys := []func(common.Hash, uint64, uint, uint) []byte{LogTopic0IndexKey, LogTopic1IndexKey, LogTopic2IndexKey, LogTopic3IndexKey}
+		for i, topic := range log.Topics {
+			if i >= len(topicKeys) {
+				break
+			}
+			keys = append(keys, topicKeys[i](topic, height, log.TxIndex, log.Index))
+		}
+		if len(log.Topics) > 0 {
+			keys = append(keys, LogAddressTopicIndexKey(log.Address, log.Topics[0], height, log.TxIndex, log.Index))
+		}
+		for _, key := range keys {
+			if err := batch.Delete(key, nil); err != nil {
+				return fmt.Errorf("failed to delete log index of block %d: %w", height, err)
+			}
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return fmt.Errorf("iterator error: %w", err)
+	}
+
+	if err := batch.DeleteRange(prefix, prefixUpperBound(prefix), nil); err != nil {
+		return fmt.Errorf("failed to delete logs of block %d: %w", height, err)
+	}
+	blockPrefix := LogBlockIndexKeyPrefix(height)
+	if err := batch.DeleteRange(blockPrefix, prefixUpperBound(blockPrefix), nil); err != nil {
+		return fmt.Errorf("failed to delete log block index %d: %w", height, err)
+	}
+	return nil
+}
+
+// rollbackAddressIndexes adds the removal of the address index entries of the
+// rolled back transactions to batch. Entries are appended in indexing order,
+// so each address is scanned from its newest entry back to the first entry of
+// a transaction that was kept.
+func (s *PebbleStorage) rollbackAddressIndexes(batch *nsBatch, r *rollback) error {
+	for addr := range r.addresses {
+		prefix := AddressTransactionKeyPrefix(addr)
+		iter, err := s.db.NewIter(&pebble.IterOptions{
+			LowerBound: prefix,
+			UpperBound: prefixUpperBound(prefix),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create iterator: %w", err)
+		}
+		for iter.Last(); iter.Valid(); iter.Prev() {
+			if !r.txHashes[common.BytesToHash(iter.Value())] {
+				break
+			}
+			if err := batch.Delete(iter.Key(), nil); err != nil {
+				iter.Close()
+				return fmt.Errorf("failed to delete address index entry: %w", err)
+			}
+		}
+		if err := iter.Close(); err != nil {
+			return fmt.Errorf("failed to close iterator: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetOrphanBlock returns a block orphaned by RollbackToHeight by hash
+func (s *PebbleStorage) GetOrphanBlock(ctx context.Context, hash common.Hash) (*OrphanBlock, error) {
+	if err := s.ensureNotClosed(); err != nil {
+		return nil, err
+	}
+
+	value, closer, err := s.db.Get(OrphanBlockKey(hash))
+	if err != nil {
+		if err == pebble.ErrNotFound {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get orphan block %s: %w", hash.Hex(), err)
+	}
+	defer closer.Close()
+
+	return decodeOrphanBlock(value)
+}
+
+// GetOrphanBlocks returns orphaned blocks ordered by block number, then hash.
+// Orphans are rare, so all are loaded before the page is cut.
+func (s *PebbleStorage) GetOrphanBlocks(ctx context.Context, limit, offset int) ([]*OrphanBlock, error) {
+	if err := s.ensureNotClosed(); err != nil {
+		return nil, err
+	}
+
+	prefix := OrphanBlockKeyPrefix()
+	iter, err := s.db.NewIter(&pebble.IterOptions{
+		LowerBound: prefix,
+		UpperBound: prefixUpperBound(prefix),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create iterator: %w", err)
+	}
+	defer iter.Close()
+
+	orphans := make([]*OrphanBlock, 0)
+	for iter.First(); iter.Valid(); iter.Next() {
+		orphan, err := decodeOrphanBlock(iter.Value())
+		if err != nil {
+			return nil, err
+		}
+		orphans = append(orphans, orphan)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, fmt.Errorf("iterator error: %w", err)
+	}
+
+	sort.Slice(orphans, func(i, j int) bool {
+		if ni, nj := orphans[i].Block.NumberU64(), orphans[j].Block.NumberU64(); ni != nj {
+			return ni < nj
+		}
+		return orphans[i].Block.Hash().Cmp(orphans[j].Block.Hash()) < 0
+	})
+
+	if offset >= len(orphans) {
+		return []*OrphanBlock{}, nil
+	}
+	orphans = orphans[offset:]
+	if limit > 0 && len(orphans) > limit {
+		orphans = orphans[:limit]
+	}
+	return orphans, nil
+}
+
+// decodeOrphanBlock decodes a stored orphanBlockRecord
+func decodeOrphanBlock(value []byte) (*OrphanBlock, error) {
+	var record orphanBlockRecord
+	if err := json.Unmarshal(value, &record); err != nil {
+		return nil, fmt.Errorf("%w orphan block: %w", ErrDecodeFailed, err)
+	}
+	block, err := DecodeBlock(record.Block)
+	if err != nil {
+		return nil, err
+	}
+	return &OrphanBlock{Block: block, ForkHeight: record.ForkHeight, OrphanedAt: record.OrphanedAt}, nil
+}
+
+// Ensure PebbleStorage implements the rollback and orphan block interfaces
+var _ ChainRollbacker = (*PebbleStorage)(nil)
+var _ OrphanBlockReader = (*PebbleStorage)(nil)
@@ -0,0 +1,253 @@
+package storage
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// setupRollbackStorage opens a storage holding blocks 1..5
+func setupRollbackStorage(t *testing.T, keepOrphans bool) (*PebbleStorage, map[uint64]common.Hash) {
+	t.Helper()
+
+	cfg := DefaultConfig(t.TempDir())
+	cfg.KeepOrphanBlocks = keepOrphans
+	cfg.ReadCacheSize = 16
+	storage, err := NewPebbleStorage(cfg)
+	if err != nil {
+		t.Fatalf("NewPebbleStorage() error = %v", err)
+	}
+	t.Cleanup(func() { storage.Close() })
+
+	ctx := context.Background()
+	hashes := make(map[uint64]common.Hash)
+	for height := uint64(1); height <= 5; height++ {
+		block := createTestBlockWithTimestamp(t, height, 1000+height)
+		if err := storage.SetBlock(ctx, block); err != nil {
+			t.Fatalf("SetBlock(%d) error = %v", height, err)
+		}
+		hashes[height] = block.Hash()
+		// Warm the read cache so the rollback has to invalidate it
+		if _, err := storage.GetBlock(ctx, height); err != nil {
+			t.Fatalf("GetBlock(%d) error = %v", height, err)
+		}
+	}
+	if err := storage.SetLatestHeight(ctx, 5); err != nil {
+		t.Fatalf("SetLatestHeight() error = %v", err)
+	}
+	return storage, hashes
+}
+
+// assertRolledBack checks that blocks above 2 are gone from the block store
+func assertRolledBack(t *testing.T, storage *PebbleStorage, hashes map[uint64]common.Hash) {
+	t.Helper()
+
+	ctx := context.Background()
+	latest, err := storage.GetLatestHeight(ctx)
+	if err != nil || latest != 2 {
+		t.Errorf("GetLatestHeight() = %d, %v; want 2", latest, err)
+	}
+	for height := uint64(1); height <= 5; height++ {
+		_, err := storage.GetBlock(ctx, height)
+		_, hashErr := storage.GetBlockByHash(ctx, hashes[height])
+		if height <= 2 {
+			if err != nil || hashErr != nil {
+				t.Errorf("block %d lookups error = %v, %v; want kept", height, err, hashErr)
+			}
+			continue
+		}
+		if err != ErrNotFound || hashErr != ErrNotFound {
+			t.Errorf("block %d lookups error = %v, %v; want ErrNotFound", height, err, hashErr)
+		}
+	}
+}
+
+func TestPebbleStorage_RollbackToHeight_HardDelete(t *testing.T) {
+	storage, hashes := setupRollbackStorage(t, false)
+	ctx := context.Background()
+
+	if err := storage.RollbackToHeight(ctx, 2); err != nil {
+		t.Fatalf("RollbackToHeight() error = %v", err)
+	}
+	assertRolledBack(t, storage, hashes)
+
+	orphans, err := storage.GetOrphanBlocks(ctx, 0, 0)
+	if err != nil {
+		t.Fatalf("GetOrphanBlocks() error = %v", err)
+	}
+	if len(orphans) != 0 {
+		t.Errorf("GetOrphanBlocks() returned %d blocks, want none by default", len(orphans))
+	}
+	if _, err := storage.GetOrphanBlock(ctx, hashes[3]); err != ErrNotFound {
+		t.Errorf("GetOrphanBlock() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestPebbleStorage_RollbackToHeight_KeepOrphans(t *testing.T) {
+	storage, hashes := setupRollbackStorage(t, true)
+	ctx := context.Background()
+
+	if err := storage.RollbackToHeight(ctx, 2); err != nil {
+		t.Fatalf("RollbackToHeight() error = %v", err)
+	}
+	assertRolledBack(t, storage, hashes)
+
+	orphans, err := storage.GetOrphanBlocks(ctx, 0, 0)
+	if err != nil {
+		t.Fatalf("GetOrphanBlocks() error = %v", err)
+	}
+	if len(orphans) != 3 {
+		t.Fatalf("GetOrphanBlocks() returned %d blocks, want 3", len(orphans))
+	}
+	for i, orphan := range orphans {
+		height := uint64(i + 3)
+		if orphan.Block.NumberU64() != height || orphan.Block.Hash() != hashes[height] {
+			t.Errorf("orphan %d = block %d %s, want block %d %s", i, orphan.Block.NumberU64(), orphan.Block.Hash().Hex(), height, hashes[height].Hex())
+		}
+		if orphan.ForkHeight != 2 {
+			t.Errorf("orphan %d fork height = %d, want 2", i, orphan.ForkHeight)
+		}
+		if orphan.OrphanedAt.IsZero() {
+			t.Errorf("orphan %d has no orphaned time", i)
+		}
+	}
+
+	orphan, err := storage.GetOrphanBlock(ctx, hashes[4])
+	if err != nil {
+		t.Fatalf("GetOrphanBlock() error = %v", err)
+	}
+	if orphan.Block.NumberU64() != 4 || orphan.Block.Time() != 1004 {
+		t.Errorf("GetOrphanBlock() = block %d at %d, want block 4 at 1004", orphan.Block.NumberU64(), orphan.Block.Time())
+	}
+
+	page, err := storage.GetOrphanBlocks(ctx, 1, 1)
+	if err != nil {
+		t.Fatalf("GetOrphanBlocks(1, 1) error = %v", err)
+	}
+	if len(page) != 1 || page[0].Block.NumberU64() != 4 {
+		t.Errorf("GetOrphanBlocks(1, 1) = %v, want block 4", page)
+	}
+
+	// Rolling back to or above the latest height changes nothing
+	if err := storage.RollbackToHeight(ctx, 2); err != nil {
+		t.Fatalf("RollbackToHeight() again error = %v", err)
+	}
+	if orphans, _ := storage.GetOrphanBlocks(ctx, 0, 0); len(orphans) != 3 {
+		t.Errorf("GetOrphanBlocks() after no-op rollback returned %d blocks, want 3", len(orphans))
+	}
+}
+
+func TestPebbleStorage_RollbackToHeight_SecondaryIndexes(t *testing.T) {
+	s, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	storage := s.(*PebbleStorage)
+	ctx := context.Background()
+
+	keyA, _ := crypto.GenerateKey()
+	keyB, _ := crypto.GenerateKey()
+	addrA := crypto.PubkeyToAddress(keyA.PublicKey)
+	addrB := crypto.PubkeyToAddress(keyB.PublicKey)
+	token := common.HexToAddress("0xcccccccccccccccccccccccccccccccccccccccc")
+	topic := common.HexToHash("0x01")
+
+	aToB, _ := createSignedTransaction(0, addrB, big.NewInt(100), big.NewInt(2), keyA)
+	bToA, _ := createSignedTransaction(0, addrA, big.NewInt(30), big.NewInt(2), keyB)
+	block1, receipts1 := createStatsBlock(1, 1001, []*types.Transaction{aToB}, nil)
+	block2, receipts2 := createStatsBlock(2, 1002, []*types.Transaction{bToA}, nil)
+	log := &types.Log{Address: token, Topics: []common.Hash{topic}, BlockNumber: 2, TxHash: bToA.Hash(), BlockHash: block2.Hash()}
+	receipts2[0].Logs = []*types.Log{log}
+
+	for _, b := range []struct {
+		block    *types.Block
+		receipts types.Receipts
+	}{{block1, receipts1}, {block2, receipts2}} {
+		if err := storage.SetBlock(ctx, b.block); err != nil {
+			t.Fatalf("SetBlock(%d) error = %v", b.block.NumberU64(), err)
+		}
+		if err := storage.SetBlockTimestamp(ctx, b.block.Time(), b.block.NumberU64()); err != nil {
+			t.Fatalf("SetBlockTimestamp(%d) error = %v", b.block.NumberU64(), err)
+		}
+		for _, receipt := range b.receipts {
+			if err := storage.SetReceipt(ctx, receipt); err != nil {
+				t.Fatalf("SetReceipt() error = %v", err)
+			}
+			if err := storage.IndexLogs(ctx, receipt.Logs); err != nil {
+				t.Fatalf("IndexLogs() error = %v", err)
+			}
+		}
+		for _, addr := range []common.Address{addrA, addrB} {
+			if err := storage.AddTransactionToAddressIndex(ctx, addr, b.block.Transactions()[0].Hash()); err != nil {
+				t.Fatalf("AddTransactionToAddressIndex() error = %v", err)
+			}
+		}
+		if err := storage.IndexAddressStats(ctx, b.block, b.receipts); err != nil {
+			t.Fatalf("IndexAddressStats(%d) error = %v", b.block.NumberU64(), err)
+		}
+	}
+	if err := storage.SetLatestHeight(ctx, 2); err != nil {
+		t.Fatalf("SetLatestHeight() error = %v", err)
+	}
+
+	if err := storage.RollbackToHeight(ctx, 1); err != nil {
+		t.Fatalf("RollbackToHeight() error = %v", err)
+	}
+
+	if _, _, err := storage.GetTransaction(ctx, bToA.Hash()); err != ErrNotFound {
+		t.Errorf("GetTransaction(rolled back) error = %v, want ErrNotFound", err)
+	}
+	if _, err := storage.GetReceipt(ctx, bToA.Hash()); err != ErrNotFound {
+		t.Errorf("GetReceipt(rolled back) error = %v, want ErrNotFound", err)
+	}
+	if _, _, err := storage.GetTransaction(ctx, aToB.Hash()); err != nil {
+		t.Errorf("GetTransaction(kept) error = %v", err)
+	}
+	if _, _, err := storage.GetTransactionsByBlock(ctx, 2); err != ErrNotFound {
+		t.Errorf("GetTransactionsByBlock(2) error = %v, want ErrNotFound", err)
+	}
+	if logs, err := storage.GetLogsByBlock(ctx, 2); err != nil || len(logs) != 0 {
+		t.Errorf("GetLogsByBlock(2) = %d logs, %v; want none", len(logs), err)
+	}
+	if _, closer, err := storage.db.Get(LogAddressIndexKey(token, 2, 0, 0)); err == nil {
+		closer.Close()
+		t.Error("log address index entry of rolled back block was kept")
+	}
+	blocks, err := storage.GetBlocksByTimeRange(ctx, 0, 2000, 10, 0, SortAscending)
+	if err != nil || len(blocks) != 1 {
+		t.Errorf("GetBlocksByTimeRange() = %d blocks, %v; want 1", len(blocks), err)
+	}
+	if count, err := storage.GetTransactionCount(ctx); err != nil || count != 1 {
+		t.Errorf("GetTransactionCount() = %d, %v; want 1", count, err)
+	}
+
+	for _, addr := range []common.Address{addrA, addrB} {
+		hashes, err := storage.GetTransactionsByAddress(ctx, addr, 10, 0)
+		if err != nil {
+			t.Fatalf("GetTransactionsByAddress(%s) error = %v", addr.Hex(), err)
+		}
+		if len(hashes) != 1 || hashes[0] != aToB.Hash() {
+			t.Errorf("GetTransactionsByAddress(%s) = %v, want only the kept transaction", addr.Hex(), hashes)
+		}
+	}
+
+	stats, err := storage.GetAddressStats(ctx, addrA)
+	if err != nil {
+		t.Fatalf("GetAddressStats() error = %v", err)
+	}
+	if stats.TotalTransactions != 1 || stats.SentCount != 1 || stats.ReceivedCount != 0 ||
+		stats.TotalValueReceived.Sign() != 0 || stats.TotalGasUsed != 21000 {
+		t.Errorf("GetAddressStats(A) = %+v, want only the kept transaction", stats)
+	}
+
+	// Re-indexing the height counts the new block again
+	if err := storage.IndexAddressStats(ctx, block2, receipts2); err != nil {
+		t.Fatalf("IndexAddressStats() after rollback error = %v", err)
+	}
+	if stats, _ := storage.GetAddressStats(ctx, addrA); stats.TotalTransactions != 2 {
+		t.Errorf("TotalTransactions after re-indexing = %d, want 2", stats.TotalTransactions)
+	}
+}
@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+)
+
+// migrateProposalStatusIndex rewrites proposal status index entries keyed by
+// decimal proposal ID into the fixed-width format and removes the legacy
+// entries. It is a no-op once no legacy entries remain.
+func (s *PebbleStorage) migrateProposalStatusIndex() error {
+	prefix := []byte(prefixIdxProposalStatusLegacy)
+	iter, err := s.db.NewIter(&pebble.IterOptions{
+		LowerBound: prefix,
+		UpperBound: incrementPrefix(prefix),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create iterator: %w", err)
+	}
+	defer iter.Close()
+
+	batch := s.db.NewBatch()
+	defer batch.Close()
+
+	var migrated int
+	for iter.First(); iter.Valid(); iter.Next() {
+		contract, status, proposalID, err := parseLegacyProposalStatusIndexKey(iter.Key())
+		if err != nil {
+			s.logger.Warn("Dropping malformed proposal status index entry", zap.ByteString("key", iter.Key()))
+		} else if err := batch.Set(ProposalStatusIndexKey(contract, status, proposalID), []byte{1}, nil); err != nil {
+			return err
+		}
+		if err := batch.Delete(iter.Key(), nil); err != nil {
+			return err
+		}
+		migrated++
+	}
+	if err := iter.Error(); err != nil {
+		return fmt.Errorf("iterator error: %w", err)
+	}
+	if migrated == 0 {
+		return nil
+	}
+
+	if err := batch.Commit(pebble.Sync); err != nil {
+		return fmt.Errorf("failed to commit proposal status index migration: %w", err)
+	}
+	s.logger.Info("Migrated proposal status index to numeric order", zap.Int("entries", migrated))
+	return nil
+}
+
+// legacyProposalIDs returns the proposal IDs in the legacy status index of
+// contract and status in numeric order
+func (s *PebbleStorage) legacyProposalIDs(contract common.Address, status uint8) ([]*big.Int, error) {
+	prefix := LegacyProposalStatusIndexKeyPrefix(contract, status)
+	iter, err := s.db.NewIter(&pebble.IterOptions{
+		LowerBound: prefix,
+		UpperBound: incrementPrefix(prefix),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create iterator: %w", err)
+	}
+	defer iter.Close()
+
+	var ids []*big.Int
+	for iter.First(); iter.Valid(); iter.Next() {
+		if _, _, proposalID, err := parseLegacyProposalStatusIndexKey(iter.Key()); err == nil {
+			ids = append(ids, proposalID)
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return nil, fmt.Errorf("iterator error: %w", err)
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i].Cmp(ids[j]) < 0 })
+	return ids, nil
+}
+
+// parseLegacyProposalStatusIndexKey parses a legacy status index key of the
+// form {prefix}{contract}/{status}/{decimal proposalId}
+func parseLegacyProposalStatusIndexKey(key []byte) (common.Address, uint8, *big.Int, error) {
+	parts := bytes.Split(bytes.TrimPrefix(key, []byte(prefixIdxProposalStatusLegacy)), []byte("/"))
+	if len(parts) != 3 || !common.IsHexAddress(string(parts[0])) {
+		return common.Address{}, 0, nil, fmt.Errorf("invalid legacy proposal status index key: %q", key)
+	}
+	status, err := strconv.ParseUint(string(parts[1]), 10, 8)
+	if err != nil {
+		return common.Address{}, 0, nil, fmt.Errorf("invalid status in legacy proposal status index key: %q", key)
+	}
+	proposalID, ok := new(big.Int).SetString(string(parts[2]), 10)
+	if !ok {
+		return common.Address{}, 0, nil, fmt.Errorf("invalid proposal ID in legacy proposal status index key: %q", key)
+	}
+	return common.HexToAddress(string(parts[0])), uint8(status), proposalID, nil
+}
@@ -1,9 +1,14 @@
 package storage
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"math/big"
+	"sort"
 
+	"github.com/0xmhha/indexer-go/internal/constants"
 	"github.com/cockroachdb/pebble"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -13,12 +18,87 @@ import (
 // Receipt Methods
 // ============================================================================
 
-// GetReceipt returns a transaction receipt by hash
+// GetReceipt returns a transaction receipt by hash. The positional fields
+// that RLP drops (block number and hash, transaction index, and the block
+// context and indices of each log) are restored from the transaction's
+// location when the transaction is indexed.
 func (s *PebbleStorage) GetReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
 	if err := s.ensureNotClosed(); err != nil {
 		return nil, err
 	}
 
+	cacheKey := receiptCacheKey(hash)
+	cached, generation, ok := s.readCache.get(cacheKey)
+	if ok {
+		return copyReceipt(cached.(*types.Receipt)), nil
+	}
+
+	receipt, err := s.getStoredReceipt(hash)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.setIndexedReceiptLocation(ctx, receipt); err != nil {
+		return nil, err
+	}
+
+	if s.readCache != nil {
+		s.readCache.add(cacheKey, copyReceipt(receipt), generation)
+	}
+	return receipt, nil
+}
+
+// getReceiptAt returns the receipt of the transaction indexed at location,
+// like GetReceipt but without looking the location up again
+func (s *PebbleStorage) getReceiptAt(ctx context.Context, hash common.Hash, location *TxLocation) (*types.Receipt, error) {
+	cacheKey := receiptCacheKey(hash)
+	cached, generation, ok := s.readCache.get(cacheKey)
+	if ok {
+		return copyReceipt(cached.(*types.Receipt)), nil
+	}
+
+	receipt, err := s.getStoredReceipt(hash)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.setReceiptLocation(ctx, receipt, location); err != nil {
+		return nil, err
+	}
+
+	if s.readCache != nil {
+		s.readCache.add(cacheKey, copyReceipt(receipt), generation)
+	}
+	return receipt, nil
+}
+
+// setIndexedReceiptLocation fills in the block context of receipt when its
+// transaction is indexed. A transaction not indexed yet has no block context;
+// storing it drops the cached receipt so the context is filled in on the next
+// read.
+func (s *PebbleStorage) setIndexedReceiptLocation(ctx context.Context, receipt *types.Receipt) error {
+	location, err := s.getTxLocation(receipt.TxHash)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	return s.setReceiptLocation(ctx, receipt, location)
+}
+
+// setReceiptLocation fills in the block context of receipt from the location
+// of its transaction
+func (s *PebbleStorage) setReceiptLocation(ctx context.Context, receipt *types.Receipt, location *TxLocation) error {
+	firstLogIndex, err := s.firstLogIndex(ctx, location, len(receipt.Logs))
+	if err != nil {
+		return err
+	}
+	setReceiptContext(receipt, location.BlockHeight, location.BlockHash, uint(location.TxIndex), firstLogIndex)
+	return nil
+}
+
+// getStoredReceipt reads and decodes a receipt, restoring only the fields
+// kept outside its RLP encoding (TxHash and ContractAddress)
+func (s *PebbleStorage) getStoredReceipt(hash common.Hash) (*types.Receipt, error) {
 	value, closer, err := s.db.Get(ReceiptKey(hash))
 	if err != nil {
 		if err == pebble.ErrNotFound {
@@ -30,7 +110,7 @@ func (s *PebbleStorage) GetReceipt(ctx context.Context, hash common.Hash) (*type
 
 	receipt, err := DecodeReceipt(value)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode receipt: %w", err)
+		return nil, fmt.Errorf("%w receipt: %w", ErrDecodeFailed, err)
 	}
 
 	// TxHash is not part of RLP encoding, so we need to restore it
@@ -50,6 +130,95 @@ func (s *PebbleStorage) GetReceipt(ctx context.Context, hash common.Hash) (*type
 	return receipt, nil
 }
 
+// getTxLocation returns the stored location of a transaction
+func (s *PebbleStorage) getTxLocation(hash common.Hash) (*TxLocation, error) {
+	value, closer, err := s.db.Get(TransactionHashIndexKey(hash))
+	if err != nil {
+		if err == pebble.ErrNotFound {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get transaction location: %w", err)
+	}
+	defer closer.Close()
+
+	location, err := DecodeTxLocation(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w location: %w", ErrDecodeFailed, err)
+	}
+	return location, nil
+}
+
+// firstLogIndex returns the block-wide index of the first log of the
+// transaction at location. The indexed logs record it directly; for blocks
+// whose logs were not indexed it is the number of logs in the receipts of
+// the transactions before it, read from their transaction keys so the block
+// body is not decoded.
+func (s *PebbleStorage) firstLogIndex(ctx context.Context, location *TxLocation, logCount int) (uint, error) {
+	if logCount == 0 {
+		return 0, nil
+	}
+
+	prefix := LogTxKeyPrefix(location.BlockHeight, uint(location.TxIndex))
+	iter, err := s.db.NewIter(&pebble.IterOptions{
+		LowerBound: prefix,
+		UpperBound: append(prefix, 0xff),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to create iterator: %w", err)
+	}
+	found := iter.First()
+	var index uint
+	if found {
+		if _, err := fmt.Sscanf(string(iter.Key()[len(prefix):]), "%06d", &index); err != nil {
+			found = false
+		}
+	}
+	iter.Close()
+	if found {
+		return index, nil
+	}
+
+	for i := uint64(0); i < location.TxIndex; i++ {
+		value, closer, err := s.db.Get(TransactionKey(location.BlockHeight, i))
+		if err != nil {
+			if err == pebble.ErrNotFound {
+				continue
+			}
+			return 0, fmt.Errorf("failed to get transaction: %w", err)
+		}
+		tx, err := DecodeTransaction(value)
+		closer.Close()
+		if err != nil {
+			return 0, fmt.Errorf("%w transaction: %w", ErrDecodeFailed, err)
+		}
+		prev, err := s.getStoredReceipt(tx.Hash())
+		if err != nil {
+			if errors.Is(err, ErrNotFound) {
+				continue
+			}
+			return 0, err
+		}
+		index += uint(len(prev.Logs))
+	}
+	return index, nil
+}
+
+// setReceiptContext fills in the positional fields of a stored receipt and
+// its logs, numbering the logs from firstLogIndex
+func setReceiptContext(receipt *types.Receipt, height uint64, blockHash common.Hash, txIndex uint, firstLogIndex uint) {
+	receipt.BlockNumber = new(big.Int).SetUint64(height)
+	receipt.BlockHash = blockHash
+	receipt.TransactionIndex = txIndex
+
+	for i, log := range receipt.Logs {
+		log.BlockNumber = height
+		log.BlockHash = blockHash
+		log.TxHash = receipt.TxHash
+		log.TxIndex = txIndex
+		log.Index = firstLogIndex + uint(i)
+	}
+}
+
 // validateReceipt validates a receipt before storage
 func validateReceipt(receipt *types.Receipt) error {
 	if receipt == nil {
@@ -92,7 +261,7 @@ func (s *PebbleStorage) SetReceipt(ctx context.Context, receipt *types.Receipt)
 
 	encoded, err := EncodeReceipt(receipt)
 	if err != nil {
-		return fmt.Errorf("failed to encode receipt: %w", err)
+		return fmt.Errorf("%w receipt: %w", ErrEncodeFailed, err)
 	}
 
 	txHash := receipt.TxHash
@@ -108,32 +277,111 @@ func (s *PebbleStorage) SetReceipt(ctx context.Context, receipt *types.Receipt)
 		}
 	}
 
+	s.readCache.remove(receiptCacheKey(txHash))
 	return nil
 }
 
-// GetReceipts returns multiple receipts by transaction hashes (batch operation)
+// GetReceipts returns multiple receipts by transaction hashes (batch operation).
+// Receipts in the read cache are served from it; the rest are read from a
+// single snapshot, seeking iterators in key order instead of issuing one Get
+// per hash, and get their block context restored like GetReceipt. Results are
+// positional: entries that are missing or fail to decode are nil, and the
+// error for the first such position is returned alongside the partial results.
 func (s *PebbleStorage) GetReceipts(ctx context.Context, hashes []common.Hash) ([]*types.Receipt, error) {
 	if err := s.ensureNotClosed(); err != nil {
 		return nil, err
 	}
 
 	receipts := make([]*types.Receipt, len(hashes))
-	var firstError error
+	if len(hashes) == 0 {
+		return receipts, nil
+	}
+
+	snapshot := s.db.NewSnapshot()
+	defer snapshot.Close()
+
+	receiptIter, err := snapshot.NewIter(&pebble.IterOptions{
+		LowerBound: []byte(prefixReceipts),
+		UpperBound: append([]byte(prefixReceipts), 0xff),
+	})
+	if err != nil {
+		return receipts, fmt.Errorf("failed to create iterator: %w", err)
+	}
+	defer receiptIter.Close()
+
+	contractAddrIter, err := snapshot.NewIter(&pebble.IterOptions{
+		LowerBound: []byte(prefixContractAddr),
+		UpperBound: append([]byte(prefixContractAddr), 0xff),
+	})
+	if err != nil {
+		return receipts, fmt.Errorf("failed to create iterator: %w", err)
+	}
+	defer contractAddrIter.Close()
 
-	for i, hash := range hashes {
-		receipt, err := s.GetReceipt(ctx, hash)
+	// Visit hashes in key order so each seek moves the iterators forward
+	order := make([]int, len(hashes))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return bytes.Compare(hashes[order[a]].Bytes(), hashes[order[b]].Bytes()) < 0
+	})
+
+	errs := make([]error, len(hashes))
+	for _, i := range order {
+		hash := hashes[i]
+
+		cacheKey := receiptCacheKey(hash)
+		cached, generation, ok := s.readCache.get(cacheKey)
+		if ok {
+			receipts[i] = copyReceipt(cached.(*types.Receipt))
+			continue
+		}
+
+		key := ReceiptKey(hash)
+		if !receiptIter.SeekGE(key) || !bytes.Equal(receiptIter.Key(), key) {
+			errs[i] = ErrNotFound
+			continue
+		}
+
+		receipt, err := DecodeReceipt(receiptIter.Value())
 		if err != nil {
-			if firstError == nil {
-				firstError = err
+			errs[i] = fmt.Errorf("%w receipt: %w", ErrDecodeFailed, err)
+			continue
+		}
+
+		// TxHash is not part of RLP encoding, restore it from the key
+		receipt.TxHash = hash
+
+		// ContractAddress is not part of RLP encoding and only exists for contract creation txs
+		addrKey := ContractAddressKey(hash)
+		if contractAddrIter.SeekGE(addrKey) && bytes.Equal(contractAddrIter.Key(), addrKey) {
+			if value := contractAddrIter.Value(); len(value) == common.AddressLength {
+				receipt.ContractAddress = common.BytesToAddress(value)
 			}
-			receipts[i] = nil
+		}
+
+		if err := s.setIndexedReceiptLocation(ctx, receipt); err != nil {
+			errs[i] = err
 			continue
 		}
+		if s.readCache != nil {
+			s.readCache.add(cacheKey, copyReceipt(receipt), generation)
+		}
 		receipts[i] = receipt
 	}
 
-	if firstError != nil {
-		return receipts, firstError
+	if err := receiptIter.Error(); err != nil {
+		return receipts, fmt.Errorf("iterator error: %w", err)
+	}
+	if err := contractAddrIter.Error(); err != nil {
+		return receipts, fmt.Errorf("iterator error: %w", err)
+	}
+
+	for _, err := range errs {
+		if err != nil {
+			return receipts, err
+		}
 	}
 
 	return receipts, nil
@@ -169,22 +417,83 @@ func (s *PebbleStorage) GetReceiptsByBlockNumber(ctx context.Context, blockNumbe
 	txs := block.Transactions()
 	receipts := make([]*types.Receipt, 0, len(txs))
 
-	// Get receipt for each transaction
-	for _, tx := range txs {
-		receipt, err := s.GetReceipt(ctx, tx.Hash())
+	// Get receipt for each transaction, numbering logs across the block
+	var logIndex uint
+	for i, tx := range txs {
+		receipt, err := s.getStoredReceipt(tx.Hash())
 		if err != nil {
-			if err == ErrNotFound {
+			if errors.Is(err, ErrNotFound) {
 				// Skip missing receipts
 				continue
 			}
 			return nil, fmt.Errorf("failed to get receipt for tx %s: %w", tx.Hash().Hex(), err)
 		}
+		setReceiptContext(receipt, blockNumber, block.Hash(), uint(i), logIndex)
+		logIndex += uint(len(receipt.Logs))
 		receipts = append(receipts, receipt)
 	}
 
 	return receipts, nil
 }
 
+// GetReceiptsByBlockRange returns receipts for blocks in [from, to] in (block, txIndex) order
+// Blocks that are missing or have no stored receipts are skipped. The walk stops as soon
+// as limit receipts past offset have been collected.
+func (s *PebbleStorage) GetReceiptsByBlockRange(ctx context.Context, from, to uint64, limit, offset int) ([]*types.Receipt, error) {
+	if err := s.ensureNotClosed(); err != nil {
+		return nil, err
+	}
+
+	if from > to {
+		return nil, fmt.Errorf("fromBlock (%d) cannot be greater than toBlock (%d)", from, to)
+	}
+	// [from, to] spans to-from+1 blocks
+	if to-from >= MaxReceiptBlockRange {
+		return nil, fmt.Errorf("block range too large: [%d, %d] spans more than %d blocks", from, to, MaxReceiptBlockRange)
+	}
+	if limit <= 0 {
+		limit = constants.DefaultPaginationLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	receipts := make([]*types.Receipt, 0, limit)
+	skipped := 0
+
+	// Count up to the span rather than the height, so to == math.MaxUint64
+	// cannot wrap the loop around
+	for i := uint64(0); i <= to-from; i++ {
+		if i%ScanContextCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+
+		height := from + i
+		blockReceipts, err := s.GetReceiptsByBlockNumber(ctx, height)
+		if err != nil {
+			if errors.Is(err, ErrNotFound) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get receipts for block %d: %w", height, err)
+		}
+
+		for _, receipt := range blockReceipts {
+			if skipped < offset {
+				skipped++
+				continue
+			}
+			receipts = append(receipts, receipt)
+			if len(receipts) >= limit {
+				return receipts, nil
+			}
+		}
+	}
+
+	return receipts, nil
+}
+
 // SetReceipts stores multiple receipts atomically (batch operation)
 func (s *PebbleStorage) SetReceipts(ctx context.Context, receipts []*types.Receipt) error {
 	if err := s.ensureNotClosed(); err != nil {
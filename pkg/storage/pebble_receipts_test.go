@@ -0,0 +1,428 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// storeTestReceipts stores count receipts and returns their hashes in insertion order
+func storeTestReceipts(tb testing.TB, storage Storage, count int) []common.Hash {
+	tb.Helper()
+
+	ctx := context.Background()
+	hashes := make([]common.Hash, count)
+	for i := range hashes {
+		hashes[i] = common.BigToHash(big.NewInt(int64(i) + 1))
+		receipt := createTestReceipt(hashes[i], uint64(21000*(i+1)))
+		if err := storage.SetReceipt(ctx, receipt); err != nil {
+			tb.Fatalf("SetReceipt() error = %v", err)
+		}
+	}
+	return hashes
+}
+
+func TestPebbleStorage_GetReceipts_MultiGet(t *testing.T) {
+	t.Run("AllFound", func(t *testing.T) {
+		storage, cleanup := setupTestStorage(t)
+		defer cleanup()
+
+		ctx := context.Background()
+		hashes := storeTestReceipts(t, storage, 5)
+
+		creation := createTestReceipt(common.HexToHash("0xc0de"), 50000)
+		creation.ContractAddress = common.HexToAddress("0x1234567890123456789012345678901234567890")
+		if err := storage.SetReceipt(ctx, creation); err != nil {
+			t.Fatalf("SetReceipt() error = %v", err)
+		}
+
+		// Out of key order, with a duplicate, to check results stay positional
+		query := []common.Hash{hashes[3], creation.TxHash, hashes[0], hashes[4], hashes[0]}
+		receipts, err := storage.GetReceipts(ctx, query)
+		if err != nil {
+			t.Fatalf("GetReceipts() error = %v", err)
+		}
+		if len(receipts) != len(query) {
+			t.Fatalf("GetReceipts() returned %d receipts, want %d", len(receipts), len(query))
+		}
+		for i, receipt := range receipts {
+			if receipt == nil {
+				t.Fatalf("receipt %d is nil", i)
+			}
+			if receipt.TxHash != query[i] {
+				t.Errorf("receipt %d hash = %s, want %s", i, receipt.TxHash.Hex(), query[i].Hex())
+			}
+		}
+		if receipts[2].CumulativeGasUsed != 21000 || receipts[0].CumulativeGasUsed != 84000 {
+			t.Errorf("unexpected gas values %d, %d", receipts[2].CumulativeGasUsed, receipts[0].CumulativeGasUsed)
+		}
+		if receipts[1].ContractAddress != creation.ContractAddress {
+			t.Errorf("contract address = %s, want %s", receipts[1].ContractAddress.Hex(), creation.ContractAddress.Hex())
+		}
+		if receipts[0].ContractAddress != (common.Address{}) {
+			t.Errorf("unexpected contract address %s", receipts[0].ContractAddress.Hex())
+		}
+	})
+
+	t.Run("Partial", func(t *testing.T) {
+		storage, cleanup := setupTestStorage(t)
+		defer cleanup()
+
+		hashes := storeTestReceipts(t, storage, 3)
+		missing := common.HexToHash("0xdead")
+
+		query := []common.Hash{hashes[2], missing, hashes[0]}
+		receipts, err := storage.GetReceipts(context.Background(), query)
+		if !errors.Is(err, ErrNotFound) {
+			t.Fatalf("GetReceipts() error = %v, want ErrNotFound", err)
+		}
+		if len(receipts) != len(query) {
+			t.Fatalf("GetReceipts() returned %d receipts, want %d", len(receipts), len(query))
+		}
+		if receipts[0] == nil || receipts[0].TxHash != hashes[2] {
+			t.Errorf("receipt 0 = %v, want %s", receipts[0], hashes[2].Hex())
+		}
+		if receipts[1] != nil {
+			t.Errorf("receipt 1 = %v, want nil", receipts[1])
+		}
+		if receipts[2] == nil || receipts[2].TxHash != hashes[0] {
+			t.Errorf("receipt 2 = %v, want %s", receipts[2], hashes[0].Hex())
+		}
+	})
+
+	t.Run("NoneFound", func(t *testing.T) {
+		storage, cleanup := setupTestStorage(t)
+		defer cleanup()
+
+		query := []common.Hash{common.HexToHash("0x01"), common.HexToHash("0x02")}
+		receipts, err := storage.GetReceipts(context.Background(), query)
+		if !errors.Is(err, ErrNotFound) {
+			t.Fatalf("GetReceipts() error = %v, want ErrNotFound", err)
+		}
+		if len(receipts) != len(query) || receipts[0] != nil || receipts[1] != nil {
+			t.Errorf("GetReceipts() = %v, want %d nil entries", receipts, len(query))
+		}
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		storage, cleanup := setupTestStorage(t)
+		defer cleanup()
+
+		receipts, err := storage.GetReceipts(context.Background(), nil)
+		if err != nil {
+			t.Fatalf("GetReceipts() error = %v", err)
+		}
+		if len(receipts) != 0 {
+			t.Errorf("GetReceipts() returned %d receipts, want 0", len(receipts))
+		}
+	})
+}
+
+func BenchmarkPebbleStorage_GetReceipts(b *testing.B) {
+	for _, count := range []int{10, 500} {
+		storage := setupTestPebbleStorage(b)
+		hashes := storeTestReceipts(b, storage, count)
+		ctx := context.Background()
+
+		b.Run(fmt.Sprintf("Loop/%d", count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for _, hash := range hashes {
+					_, _ = storage.GetReceipt(ctx, hash)
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("MultiGet/%d", count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, _ = storage.GetReceipts(ctx, hashes)
+			}
+		})
+
+		storage.Close()
+	}
+}
+
+// openFilterTestStorage opens a storage with the given bloom filter size and
+// stores receipts in overlapping L0 sstables, returning the stored hashes.
+// Pebble does not read filters of the bottom level, so the table count stays
+// below the L0 compaction threshold.
+func openFilterTestStorage(tb testing.TB, bitsPerKey int) (*PebbleStorage, []common.Hash) {
+	tb.Helper()
+
+	cfg := DefaultConfig(tb.TempDir())
+	cfg.BloomFilterBitsPerKey = bitsPerKey
+	storage, err := NewPebbleStorage(cfg)
+	if err != nil {
+		tb.Fatalf("NewPebbleStorage() error = %v", err)
+	}
+	tb.Cleanup(func() { storage.Close() })
+
+	ctx := context.Background()
+	var hashes []common.Hash
+	for table := 0; table < 3; table++ {
+		for i := 0; i < 2000; i++ {
+			hash := crypto.Keccak256Hash(big.NewInt(int64(len(hashes))).Bytes())
+			if err := storage.SetReceipt(ctx, createTestReceipt(hash, 21000)); err != nil {
+				tb.Fatalf("SetReceipt() error = %v", err)
+			}
+			hashes = append(hashes, hash)
+		}
+		if err := storage.db.Flush(); err != nil {
+			tb.Fatalf("Flush() error = %v", err)
+		}
+	}
+	return storage, hashes
+}
+
+func TestPebbleStorage_BloomFilter(t *testing.T) {
+	ctx := context.Background()
+	missing := common.HexToHash("0xdead")
+
+	t.Run("enabled", func(t *testing.T) {
+		storage, hashes := openFilterTestStorage(t, DefaultBloomFilterBitsPerKey)
+
+		for _, hash := range hashes {
+			receipt, err := storage.GetReceipt(ctx, hash)
+			if err != nil {
+				t.Fatalf("GetReceipt(%s) error = %v", hash.Hex(), err)
+			}
+			if receipt.TxHash != hash {
+				t.Fatalf("GetReceipt(%s).TxHash = %s", hash.Hex(), receipt.TxHash.Hex())
+			}
+		}
+		if _, err := storage.GetReceipt(ctx, missing); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("GetReceipt(missing) error = %v, want ErrNotFound", err)
+		}
+
+		if hits := storage.db.Metrics().Filter.Hits; hits == 0 {
+			t.Error("bloom filter was never consulted, want lookups to skip sstables")
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		storage, hashes := openFilterTestStorage(t, 0)
+
+		if _, err := storage.GetReceipt(ctx, hashes[0]); err != nil {
+			t.Fatalf("GetReceipt() error = %v", err)
+		}
+		if _, err := storage.GetReceipt(ctx, missing); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("GetReceipt(missing) error = %v, want ErrNotFound", err)
+		}
+
+		m := storage.db.Metrics()
+		if m.Filter.Hits != 0 || m.Filter.Misses != 0 {
+			t.Errorf("filter metrics = %+v, want none without a filter", m.Filter)
+		}
+	})
+}
+
+// BenchmarkPebbleStorage_GetReceipt_BloomFilter compares point lookups with
+// and without sstable bloom filters. Besides time it reports the sstable
+// blocks loaded per lookup, which become disk reads once the working set
+// outgrows the block cache. Lookups of absent keys gain the most, since
+// without a filter every overlapping sstable has to be searched.
+func BenchmarkPebbleStorage_GetReceipt_BloomFilter(b *testing.B) {
+	ctx := context.Background()
+	for _, bits := range []int{0, DefaultBloomFilterBitsPerKey} {
+		storage, hashes := openFilterTestStorage(b, bits)
+
+		lookups := []struct {
+			name   string
+			hashAt func(i int) common.Hash
+		}{
+			{"Hit", func(i int) common.Hash { return hashes[i%len(hashes)] }},
+			{"Miss", func(i int) common.Hash { return crypto.Keccak256Hash(big.NewInt(int64(-i - 1)).Bytes()) }},
+		}
+		for _, lookup := range lookups {
+			b.Run(fmt.Sprintf("%s/bits=%d", lookup.name, bits), func(b *testing.B) {
+				before := storage.db.Metrics().BlockCache
+				for i := 0; i < b.N; i++ {
+					_, _ = storage.GetReceipt(ctx, lookup.hashAt(i))
+				}
+				after := storage.db.Metrics().BlockCache
+				blocks := (after.Hits + after.Misses) - (before.Hits + before.Misses)
+				b.ReportMetric(float64(blocks)/float64(b.N), "blocks/op")
+			})
+		}
+	}
+}
+
+func TestPebbleStorage_GetReceiptsByBlock_NotFound(t *testing.T) {
+	storage, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	if err := storage.SetBlock(ctx, createTestBlock(1)); err != nil {
+		t.Fatalf("SetBlock() error = %v", err)
+	}
+
+	// The block lookup error is wrapped with context, so callers must use errors.Is
+	if _, err := storage.GetReceiptsByBlockNumber(ctx, 2); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetReceiptsByBlockNumber() error = %v, want errors.Is(err, ErrNotFound)", err)
+	}
+	if _, err := storage.GetReceiptsByBlockHash(ctx, common.HexToHash("0xdead")); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetReceiptsByBlockHash() error = %v, want errors.Is(err, ErrNotFound)", err)
+	}
+
+	// A stored block without receipts is not an error
+	receipts, err := storage.GetReceiptsByBlockNumber(ctx, 1)
+	if err != nil || len(receipts) != 0 {
+		t.Errorf("GetReceiptsByBlockNumber(1) = %d receipts, %v; want 0, nil", len(receipts), err)
+	}
+}
+
+// storeBlockWithLogReceipts stores a block whose transactions emit
+// logsPerTx[i] logs each, and returns the block
+func storeBlockWithLogReceipts(t *testing.T, storage *PebbleStorage, height uint64, logsPerTx []int, indexLogs bool) *types.Block {
+	t.Helper()
+
+	ctx := context.Background()
+	block := createTestBlockWithTransactions(height, len(logsPerTx))
+	receipts := make([]*types.Receipt, len(logsPerTx))
+	var logIndex uint
+	for i, tx := range block.Transactions() {
+		receipt := createTestReceipt(tx.Hash(), uint64(21000*(i+1)))
+		for j := 0; j < logsPerTx[i]; j++ {
+			receipt.Logs = append(receipt.Logs, &types.Log{
+				Address:     common.HexToAddress("0x1000"),
+				Topics:      []common.Hash{common.HexToHash("0xfeed")},
+				BlockNumber: height,
+				BlockHash:   block.Hash(),
+				TxHash:      tx.Hash(),
+				TxIndex:     uint(i),
+				Index:       logIndex,
+			})
+			logIndex++
+		}
+		receipts[i] = receipt
+	}
+
+	if err := storage.SetBlockWithReceipts(ctx, block, receipts); err != nil {
+		t.Fatalf("SetBlockWithReceipts() error = %v", err)
+	}
+	if indexLogs {
+		for _, receipt := range receipts {
+			if err := storage.IndexLogs(ctx, receipt.Logs); err != nil {
+				t.Fatalf("IndexLogs() error = %v", err)
+			}
+		}
+	}
+	return block
+}
+
+// checkReceiptContext verifies the positional fields of a receipt read back
+// for the transaction at txIndex
+func checkReceiptContext(t *testing.T, receipt *types.Receipt, block *types.Block, txIndex uint, firstLogIndex uint) {
+	t.Helper()
+
+	if receipt.BlockNumber == nil || receipt.BlockNumber.Uint64() != block.NumberU64() {
+		t.Errorf("tx %d: BlockNumber = %v, want %d", txIndex, receipt.BlockNumber, block.NumberU64())
+	}
+	if receipt.BlockHash != block.Hash() {
+		t.Errorf("tx %d: BlockHash = %s, want %s", txIndex, receipt.BlockHash.Hex(), block.Hash().Hex())
+	}
+	if receipt.TransactionIndex != txIndex {
+		t.Errorf("tx %d: TransactionIndex = %d", txIndex, receipt.TransactionIndex)
+	}
+	for j, log := range receipt.Logs {
+		if log.BlockNumber != block.NumberU64() || log.BlockHash != block.Hash() {
+			t.Errorf("tx %d log %d: block = %d/%s, want %d/%s", txIndex, j,
+				log.BlockNumber, log.BlockHash.Hex(), block.NumberU64(), block.Hash().Hex())
+		}
+		if log.TxHash != receipt.TxHash || log.TxIndex != txIndex {
+			t.Errorf("tx %d log %d: tx = %s/%d, want %s/%d", txIndex, j,
+				log.TxHash.Hex(), log.TxIndex, receipt.TxHash.Hex(), txIndex)
+		}
+		if want := firstLogIndex + uint(j); log.Index != want {
+			t.Errorf("tx %d log %d: Index = %d, want %d", txIndex, j, log.Index, want)
+		}
+	}
+}
+
+func TestPebbleStorage_GetReceipt_BlockContext(t *testing.T) {
+	logsPerTx := []int{2, 0, 3, 1}
+	firstLogIndex := []uint{0, 2, 2, 5}
+
+	for _, indexLogs := range []bool{true, false} {
+		t.Run(fmt.Sprintf("IndexedLogs=%v", indexLogs), func(t *testing.T) {
+			storage, cleanup := setupTestStorage(t)
+			defer cleanup()
+			pebbleStorage := storage.(*PebbleStorage)
+			ctx := context.Background()
+
+			block := storeBlockWithLogReceipts(t, pebbleStorage, 7, logsPerTx, indexLogs)
+
+			for i, tx := range block.Transactions() {
+				receipt, err := storage.GetReceipt(ctx, tx.Hash())
+				if err != nil {
+					t.Fatalf("GetReceipt() error = %v", err)
+				}
+				if len(receipt.Logs) != logsPerTx[i] {
+					t.Fatalf("tx %d: got %d logs, want %d", i, len(receipt.Logs), logsPerTx[i])
+				}
+				checkReceiptContext(t, receipt, block, uint(i), firstLogIndex[i])
+			}
+
+			receipts, err := storage.GetReceiptsByBlockNumber(ctx, 7)
+			if err != nil {
+				t.Fatalf("GetReceiptsByBlockNumber() error = %v", err)
+			}
+			if len(receipts) != len(logsPerTx) {
+				t.Fatalf("GetReceiptsByBlockNumber() = %d receipts, want %d", len(receipts), len(logsPerTx))
+			}
+			for i, receipt := range receipts {
+				checkReceiptContext(t, receipt, block, uint(i), firstLogIndex[i])
+			}
+
+			// The batch lookup, in reverse so the snapshot is read out of order
+			hashes := make([]common.Hash, 0, len(logsPerTx))
+			for i := len(logsPerTx) - 1; i >= 0; i-- {
+				hashes = append(hashes, block.Transactions()[i].Hash())
+			}
+			receipts, err = storage.GetReceipts(ctx, hashes)
+			if err != nil {
+				t.Fatalf("GetReceipts() error = %v", err)
+			}
+			for j, receipt := range receipts {
+				i := len(logsPerTx) - 1 - j
+				checkReceiptContext(t, receipt, block, uint(i), firstLogIndex[i])
+			}
+		})
+	}
+}
+
+func TestPebbleStorage_GetReceipt_ContextAfterIndexing(t *testing.T) {
+	storage := setupCachedStorage(t, 16)
+	ctx := context.Background()
+
+	block := createTestBlockWithTransactions(3, 1)
+	tx := block.Transactions()[0]
+	if err := storage.SetReceipt(ctx, createTestReceipt(tx.Hash(), 21000)); err != nil {
+		t.Fatalf("SetReceipt() error = %v", err)
+	}
+
+	// Without its transaction indexed the receipt has no block context
+	receipt, err := storage.GetReceipt(ctx, tx.Hash())
+	if err != nil {
+		t.Fatalf("GetReceipt() error = %v", err)
+	}
+	if receipt.BlockNumber != nil {
+		t.Errorf("BlockNumber = %v before the block is stored, want nil", receipt.BlockNumber)
+	}
+
+	// Storing the block drops the cached receipt, so the next read has it
+	if err := storage.SetBlock(ctx, block); err != nil {
+		t.Fatalf("SetBlock() error = %v", err)
+	}
+	receipt, err = storage.GetReceipt(ctx, tx.Hash())
+	if err != nil {
+		t.Fatalf("GetReceipt() error = %v", err)
+	}
+	checkReceiptContext(t, receipt, block, 0, 0)
+}
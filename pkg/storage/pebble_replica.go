@@ -0,0 +1,250 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/pebble"
+	"go.uber.org/zap"
+)
+
+// replicaSnapshotAttempts bounds how often a snapshot is retried when the
+// writer compacts away or rotates a file while it is being taken
+const replicaSnapshotAttempts = 3
+
+// replicaState tracks the private snapshot a read replica serves from
+type replicaState struct {
+	// source is the writer's database directory
+	source string
+	// snapshotDir holds the snapshot directories
+	snapshotDir string
+	// opts opens each snapshot; its block cache is shared between them
+	opts *pebble.Options
+
+	// mu serializes refreshes
+	mu sync.Mutex
+	// dir is the snapshot directory of the open database
+	dir string
+	// retiring tracks replaced snapshots waiting for their reads to finish
+	// before they are closed and removed
+	retiring sync.WaitGroup
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// openPebbleReplica opens a read replica of the database at cfg.Path, which
+// another process may be writing to, and starts refreshing it every
+// cfg.ReplicaRefreshInterval
+func openPebbleReplica(cfg *Config, opts *pebble.Options) (*PebbleStorage, error) {
+	replica := &replicaState{source: cfg.Path, snapshotDir: cfg.ReplicaSnapshotDir, opts: opts}
+	if replica.snapshotDir == "" {
+		replica.snapshotDir = filepath.Dir(cfg.Path)
+	}
+	db, dir, err := replica.openSnapshot("")
+	if err != nil {
+		return nil, err
+	}
+
+	storage, err := newPebbleStorageWithDB(newNamespacedDB(db, cfg.KeyNamespace), cfg, zap.NewNop())
+	if err != nil {
+		db.Close()
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	replica.dir = dir
+	storage.replica = replica
+	storage.startReplicaRefresher()
+	return storage, nil
+}
+
+// Refresh re-snapshots the writer's database so the replica serves everything
+// the writer had written when the snapshot was taken, and empties the read
+// cache. Reads already running finish against the previous snapshot, which is
+// closed once they are done. It returns an error if the storage was not
+// opened with ReplicaRefreshInterval set.
+func (s *PebbleStorage) Refresh() error {
+	if err := s.ensureNotClosed(); err != nil {
+		return err
+	}
+	if s.replica == nil {
+		return errors.New("storage is not a read replica")
+	}
+
+	r := s.replica
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	db, dir, err := r.openSnapshot(r.dir)
+	if err != nil {
+		return err
+	}
+
+	retired, retiredDir := s.db.swap(db), r.dir
+	r.dir = dir
+	s.readCache.purge()
+
+	r.retiring.Add(1)
+	go func() {
+		defer r.retiring.Done()
+		if err := retired.retire(); err != nil {
+			s.logger.Warn("Failed to close retired replica snapshot", zap.String("dir", retiredDir), zap.Error(err))
+		}
+		os.RemoveAll(retiredDir)
+	}()
+
+	return s.loadTransactionCount()
+}
+
+// startReplicaRefresher runs Refresh every ReplicaRefreshInterval until the
+// storage is closed
+func (s *PebbleStorage) startReplicaRefresher() {
+	s.replica.stop = make(chan struct{})
+	s.replica.done = make(chan struct{})
+
+	go func() {
+		defer close(s.replica.done)
+
+		ticker := time.NewTicker(s.config.ReplicaRefreshInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.replica.stop:
+				return
+			case <-ticker.C:
+				if err := s.Refresh(); err != nil && !errors.Is(err, ErrClosed) {
+					s.logger.Warn("Replica refresh failed", zap.String("source", s.replica.source), zap.Error(err))
+				}
+			}
+		}
+	}()
+}
+
+// closeReplica stops refreshing and closes and removes every snapshot, waiting
+// for reads of replaced snapshots to finish
+func (s *PebbleStorage) closeReplica() error {
+	r := s.replica
+	close(r.stop)
+	<-r.done
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	err := s.db.Close()
+	os.RemoveAll(r.dir)
+	r.retiring.Wait()
+	return err
+}
+
+// openSnapshot snapshots the source directory and opens the snapshot
+// read-only, retrying when the writer changed files underneath it. Tables
+// that cannot be linked from the source are linked from prev, the previous
+// snapshot, when it holds them.
+func (r *replicaState) openSnapshot(prev string) (*pebble.DB, string, error) {
+	var lastErr error
+	for attempt := 0; attempt < replicaSnapshotAttempts; attempt++ {
+		dir, err := os.MkdirTemp(r.snapshotDir, filepath.Base(r.source)+".replica-")
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to create replica snapshot directory: %w", err)
+		}
+
+		if err := snapshotPebbleDir(r.source, dir, prev); err != nil {
+			os.RemoveAll(dir)
+			return nil, "", err
+		}
+
+		db, err := pebble.Open(dir, r.opts)
+		if err == nil {
+			return db, dir, nil
+		}
+		os.RemoveAll(dir)
+		lastErr = err
+	}
+	return nil, "", fmt.Errorf("failed to open replica snapshot of %s: %w", r.source, lastErr)
+}
+
+// snapshotPebbleDir captures a live pebble directory into dst without the
+// writer's cooperation. WAL files are copied first and the manifest after
+// them, so any WAL flushed in between is already covered by the copied
+// manifest. Table files are immutable and hard-linked; one compacted away
+// meanwhile makes the snapshot fail to open, and it is taken again. When dst
+// is on another filesystem than src, a table already in prev is linked from
+// there, so each table is copied only once.
+func snapshotPebbleDir(src, dst, prev string) error {
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return fmt.Errorf("failed to read database directory: %w", err)
+	}
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".log") {
+			if err := copySnapshotFile(src, dst, entry.Name()); err != nil {
+				return err
+			}
+		}
+	}
+
+	entries, err = os.ReadDir(src)
+	if err != nil {
+		return fmt.Errorf("failed to read database directory: %w", err)
+	}
+	var tables []string
+	for _, entry := range entries {
+		name := entry.Name()
+		switch {
+		case entry.IsDir(), name == "LOCK", strings.HasSuffix(name, ".log"):
+			continue
+		case strings.HasSuffix(name, ".sst"):
+			tables = append(tables, name)
+		default:
+			if err := copySnapshotFile(src, dst, name); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, name := range tables {
+		err := os.Link(filepath.Join(src, name), filepath.Join(dst, name))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) && prev != "" {
+			// Across filesystems, reuse the previous snapshot's copy
+			err = os.Link(filepath.Join(prev, name), filepath.Join(dst, name))
+		}
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			err = copySnapshotFile(src, dst, name)
+		}
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to link table %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// copySnapshotFile copies src/name to dst/name. A file the writer removed
+// in the meantime is skipped.
+func copySnapshotFile(src, dst, name string) error {
+	in, err := os.Open(filepath.Join(src, name))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("failed to open %s: %w", name, err)
+	}
+	defer in.Close()
+
+	out, err := os.Create(filepath.Join(dst, name))
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", name, err)
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to copy %s: %w", name, err)
+	}
+	return out.Close()
+}
@@ -0,0 +1,202 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPebbleStorage_Replica(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db")
+	writer, err := NewPebbleStorage(DefaultConfig(path))
+	if err != nil {
+		t.Fatalf("NewPebbleStorage(writer) error = %v", err)
+	}
+	defer writer.Close()
+
+	ctx := context.Background()
+	// SetBlockWithReceipts syncs the WAL, which makes its writes visible to
+	// snapshots; it also advances the latest height
+	writeBlocks := func(from, to uint64) {
+		t.Helper()
+		for height := from; height <= to; height++ {
+			if err := writer.SetBlockWithReceipts(ctx, createTestBlock(height), nil); err != nil {
+				t.Fatalf("SetBlockWithReceipts(%d) error = %v", height, err)
+			}
+		}
+	}
+
+	writeBlocks(0, 4)
+	// Flush part of the history into tables so both tables and WAL are copied
+	if err := writer.Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	writeBlocks(5, 9)
+
+	cfg := DefaultConfig(path)
+	cfg.ReadOnly = true
+	cfg.ReplicaRefreshInterval = time.Hour
+	replica, err := NewPebbleStorage(cfg)
+	if err != nil {
+		t.Fatalf("NewPebbleStorage(replica) error = %v", err)
+	}
+
+	if height, err := replica.GetLatestHeight(ctx); err != nil || height != 9 {
+		t.Fatalf("replica GetLatestHeight() = %d, %v; want 9", height, err)
+	}
+	if err := replica.SetBlock(ctx, createTestBlock(10)); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("replica SetBlock() error = %v, want ErrReadOnly", err)
+	}
+
+	writeBlocks(10, 14)
+	if _, err := replica.GetBlock(ctx, 14); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("replica GetBlock(14) before refresh error = %v, want ErrNotFound", err)
+	}
+
+	if err := replica.Refresh(); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	block, err := replica.GetBlock(ctx, 14)
+	if err != nil || block.NumberU64() != 14 {
+		t.Fatalf("replica GetBlock(14) after refresh = %v, %v", block, err)
+	}
+	if height, err := replica.GetLatestHeight(ctx); err != nil || height != 14 {
+		t.Errorf("replica GetLatestHeight() after refresh = %d, %v; want 14", height, err)
+	}
+
+	if err := replica.Close(); err != nil {
+		t.Fatalf("replica Close() error = %v", err)
+	}
+	// Snapshots are removed on close, leaving only the writer's directory
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("directory holds %d entries after replica Close, want only the database", len(entries))
+	}
+}
+
+func TestPebbleStorage_ReplicaPeriodicRefresh(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db")
+	writer, err := NewPebbleStorage(DefaultConfig(path))
+	if err != nil {
+		t.Fatalf("NewPebbleStorage(writer) error = %v", err)
+	}
+	defer writer.Close()
+
+	ctx := context.Background()
+	if err := writer.SetBlockWithReceipts(ctx, createTestBlock(0), nil); err != nil {
+		t.Fatalf("SetBlockWithReceipts(0) error = %v", err)
+	}
+
+	cfg := DefaultConfig(path)
+	cfg.ReadOnly = true
+	cfg.ReplicaRefreshInterval = 20 * time.Millisecond
+	replica, err := NewPebbleStorage(cfg)
+	if err != nil {
+		t.Fatalf("NewPebbleStorage(replica) error = %v", err)
+	}
+	defer replica.Close()
+
+	for height := uint64(1); height <= 20; height++ {
+		if err := writer.SetBlockWithReceipts(ctx, createTestBlock(height), nil); err != nil {
+			t.Fatalf("SetBlockWithReceipts(%d) error = %v", height, err)
+		}
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err := replica.GetBlock(ctx, 20); err == nil {
+			break
+		} else if !errors.Is(err, ErrNotFound) {
+			t.Fatalf("replica GetBlock(20) error = %v", err)
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("replica did not observe block 20 after refreshing")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestPebbleStorage_RefreshNotReplica(t *testing.T) {
+	storage := setupTestPebbleStorage(t)
+
+	if err := storage.Refresh(); err == nil {
+		t.Error("Refresh() on a writable storage should fail")
+	}
+}
+
+func TestPebbleStorage_ReplicaRefreshKeepsOpenReads(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db")
+	writer, err := NewPebbleStorage(DefaultConfig(path))
+	if err != nil {
+		t.Fatalf("NewPebbleStorage(writer) error = %v", err)
+	}
+	defer writer.Close()
+
+	ctx := context.Background()
+	for height := uint64(0); height <= 4; height++ {
+		if err := writer.SetBlockWithReceipts(ctx, createTestBlock(height), nil); err != nil {
+			t.Fatalf("SetBlockWithReceipts(%d) error = %v", height, err)
+		}
+	}
+
+	snapshotDir := t.TempDir()
+	cfg := DefaultConfig(path)
+	cfg.ReadOnly = true
+	cfg.ReplicaRefreshInterval = time.Hour
+	cfg.ReplicaSnapshotDir = snapshotDir
+	cfg.ReadCacheSize = 16
+	replica, err := NewPebbleStorage(cfg)
+	if err != nil {
+		t.Fatalf("NewPebbleStorage(replica) error = %v", err)
+	}
+
+	if _, err := replica.GetBlock(ctx, 4); err != nil {
+		t.Fatalf("replica GetBlock(4) error = %v", err)
+	}
+	if _, _, ok := replica.readCache.get(blockCacheKey(4)); !ok {
+		t.Fatal("replica did not cache block 4")
+	}
+
+	iter, err := replica.db.NewIter(nil)
+	if err != nil {
+		t.Fatalf("NewIter() error = %v", err)
+	}
+	if err := replica.Refresh(); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if _, _, ok := replica.readCache.get(blockCacheKey(4)); ok {
+		t.Error("Refresh() kept block 4 in the read cache")
+	}
+
+	// The iterator keeps reading the snapshot it was opened on
+	count := 0
+	for iter.First(); iter.Valid(); iter.Next() {
+		count++
+	}
+	if err := iter.Error(); err != nil {
+		t.Fatalf("iterator error after refresh = %v", err)
+	}
+	if count == 0 {
+		t.Error("iterator opened before refresh saw no keys")
+	}
+	if err := iter.Close(); err != nil {
+		t.Fatalf("iterator Close() error = %v", err)
+	}
+
+	if err := replica.Close(); err != nil {
+		t.Fatalf("replica Close() error = %v", err)
+	}
+	entries, err := os.ReadDir(snapshotDir)
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("snapshot directory holds %d entries after replica Close, want none", len(entries))
+	}
+}
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// SetRevertReason stores the revert reason of a failed transaction
+func (s *PebbleStorage) SetRevertReason(ctx context.Context, reason *RevertReason) error {
+	if err := s.ensureNotClosed(); err != nil {
+		return err
+	}
+	if err := s.ensureNotReadOnly(); err != nil {
+		return err
+	}
+	if reason == nil {
+		return fmt.Errorf("revert reason cannot be nil")
+	}
+
+	data, err := json.Marshal(reason)
+	if err != nil {
+		return fmt.Errorf("failed to marshal revert reason: %w", err)
+	}
+	if err := s.db.Set(RevertReasonKey(reason.TxHash), data, pebble.Sync); err != nil {
+		return fmt.Errorf("failed to store revert reason: %w", err)
+	}
+	return nil
+}
+
+// GetRevertReason returns the revert reason of a failed transaction
+func (s *PebbleStorage) GetRevertReason(ctx context.Context, txHash common.Hash) (*RevertReason, error) {
+	if err := s.ensureNotClosed(); err != nil {
+		return nil, err
+	}
+
+	value, closer, err := s.db.Get(RevertReasonKey(txHash))
+	if err != nil {
+		if err == pebble.ErrNotFound {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get revert reason: %w", err)
+	}
+	defer closer.Close()
+
+	var reason RevertReason
+	if err := json.Unmarshal(value, &reason); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal revert reason: %w", err)
+	}
+	return &reason, nil
+}
+
+// Ensure PebbleStorage implements the revert reason interfaces
+var _ RevertReasonWriter = (*PebbleStorage)(nil)
+var _ RevertReasonReader = (*PebbleStorage)(nil)
@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"encoding/hex"
 	"fmt"
 	"strconv"
 	"strings"
@@ -189,26 +190,27 @@ func (s *PebbleStorage) Search(ctx context.Context, query string, resultTypes []
 	return results, nil
 }
 
-// detectQueryType determines the type of search query
+// detectQueryType determines the type of search query: "blockNumber" for a
+// decimal number, "hash" for 32 bytes of hex (a block or transaction hash),
+// "address" for 20 bytes of hex, and "" for anything else, which matches nothing
 func detectQueryType(query string) string {
-	// Remove 0x prefix if present
-	query = strings.TrimPrefix(query, "0x")
-
 	// Check if it's a number (block number)
 	if _, err := strconv.ParseUint(query, 10, 64); err == nil {
 		return "blockNumber"
 	}
 
-	// Check if it's a valid hex hash (64 characters for block/tx hash, 40 for address)
-	if len(query) == 64 {
+	// Remove 0x prefix if present
+	query = strings.TrimPrefix(query, "0x")
+	if _, err := hex.DecodeString(query); err != nil {
+		return ""
+	}
+
+	switch len(query) {
+	case 2 * common.HashLength:
 		// Could be block hash or transaction hash
 		return "hash"
-	} else if len(query) == 40 {
-		// Address
+	case 2 * common.AddressLength:
 		return "address"
 	}
-
-	// Default to address for shorter queries (partial address search could be implemented)
-	return "address"
+	return ""
 }
-
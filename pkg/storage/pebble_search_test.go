@@ -20,8 +20,10 @@ func TestDetectQueryType(t *testing.T) {
 		{"block hash without 0x", "1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef", "hash"},
 		{"address with 0x", "0x1234567890123456789012345678901234567890", "address"},
 		{"address without 0x", "1234567890123456789012345678901234567890", "address"},
-		{"short query", "abc", "address"},
-		{"empty after trim", "", "address"},
+		{"short query", "abc", ""},
+		{"empty after trim", "", ""},
+		{"non-hex of address length", "0x123456789012345678901234567890123456789z", ""},
+		{"hex of neither length", "0x1234", ""},
 	}
 
 	for _, tt := range tests {
@@ -95,6 +97,14 @@ func TestPebbleStorage_Search(t *testing.T) {
 		}
 	})
 
+	t.Run("UnrecognizedQuery", func(t *testing.T) {
+		for _, query := range []string{"0x1234", "not-a-hash", "0xzz34567890123456789012345678901234567890"} {
+			results, err := storage.Search(ctx, query, nil, 10)
+			require.NoError(t, err)
+			assert.Empty(t, results, "query %q", query)
+		}
+	})
+
 	t.Run("DefaultLimit", func(t *testing.T) {
 		// Negative limit should use default
 		results, err := storage.Search(ctx, "42", nil, -1)
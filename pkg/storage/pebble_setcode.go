@@ -64,7 +64,7 @@ func (s *PebbleStorage) GetSetCodeAuthorizationsByTx(ctx context.Context, txHash
 	for iter.First(); iter.Valid(); iter.Next() {
 		var record SetCodeAuthorizationRecord
 		if err := json.Unmarshal(iter.Value(), &record); err != nil {
-			s.logger.Warn("failed to unmarshal setcode authorization",
+			s.loggerFor(ctx).Warn("failed to unmarshal setcode authorization",
 				zap.String("key", string(iter.Key())),
 				zap.Error(err))
 			continue
@@ -146,7 +146,7 @@ func (s *PebbleStorage) GetSetCodeAuthorizationsByTarget(ctx context.Context, ta
 	for _, ref := range txRefs[start:end] {
 		record, err := s.GetSetCodeAuthorization(ctx, ref.txHash, ref.authIndex)
 		if err != nil {
-			s.logger.Warn("failed to get setcode authorization",
+			s.loggerFor(ctx).Warn("failed to get setcode authorization",
 				zap.String("txHash", ref.txHash.Hex()),
 				zap.Int("authIndex", ref.authIndex),
 				zap.Error(err))
@@ -224,7 +224,7 @@ func (s *PebbleStorage) GetSetCodeAuthorizationsByAuthority(ctx context.Context,
 	for _, ref := range txRefs[start:end] {
 		record, err := s.GetSetCodeAuthorization(ctx, ref.txHash, ref.authIndex)
 		if err != nil {
-			s.logger.Warn("failed to get setcode authorization",
+			s.loggerFor(ctx).Warn("failed to get setcode authorization",
 				zap.String("txHash", ref.txHash.Hex()),
 				zap.Int("authIndex", ref.authIndex),
 				zap.Error(err))
@@ -265,7 +265,7 @@ func (s *PebbleStorage) GetSetCodeAuthorizationsByBlock(ctx context.Context, blo
 
 			record, err := s.GetSetCodeAuthorization(ctx, txHash, authIndex)
 			if err != nil {
-				s.logger.Warn("failed to get setcode authorization",
+				s.loggerFor(ctx).Warn("failed to get setcode authorization",
 					zap.String("txHash", txHash.Hex()),
 					zap.Int("authIndex", authIndex),
 					zap.Error(err))
@@ -463,7 +463,7 @@ func (s *PebbleStorage) GetRecentSetCodeAuthorizations(ctx context.Context, limi
 
 			record, err := s.GetSetCodeAuthorization(ctx, txHash, authIndex)
 			if err != nil {
-				s.logger.Warn("failed to get setcode authorization",
+				s.loggerFor(ctx).Warn("failed to get setcode authorization",
 					zap.String("txHash", txHash.Hex()),
 					zap.Int("authIndex", authIndex),
 					zap.Error(err))
@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cockroachdb/pebble"
+)
+
+// Ensure PebbleStorage implements StateSnapshotWriter
+var _ StateSnapshotWriter = (*PebbleStorage)(nil)
+
+// ApplyStateSnapshot seeds balances, total supply and active minters from the
+// snapshot. Balances are recorded at the snapshot height, so balance history
+// starts there. The snapshot height is stored with the state in the same batch.
+func (s *PebbleStorage) ApplyStateSnapshot(ctx context.Context, snapshot *StateSnapshot) error {
+	if err := s.ensureNotClosed(); err != nil {
+		return err
+	}
+	if err := s.ensureNotReadOnly(); err != nil {
+		return err
+	}
+
+	applied, err := s.GetStateSnapshotHeight(ctx)
+	switch {
+	case err == nil && applied == snapshot.Height:
+		return nil
+	case err == nil:
+		return fmt.Errorf("state snapshot at height %d already applied, cannot apply snapshot at height %d", applied, snapshot.Height)
+	case !errors.Is(err, ErrNotFound):
+		return err
+	}
+
+	batch := s.NewBatch().(*pebbleBatch)
+	defer batch.Close()
+
+	for addr, balance := range snapshot.Balances {
+		if err := batch.SetBalance(ctx, addr, snapshot.Height, balance); err != nil {
+			return fmt.Errorf("failed to set balance of %s: %w", addr.Hex(), err)
+		}
+	}
+	if snapshot.TotalSupply != nil {
+		if err := batch.batch.Set(TotalSupplyKey(), EncodeBigInt(snapshot.TotalSupply), nil); err != nil {
+			return fmt.Errorf("failed to set total supply: %w", err)
+		}
+	}
+	for minter, allowance := range snapshot.ActiveMinters {
+		if err := batch.batch.Set(MinterActiveIndexKey(minter), EncodeBigInt(allowance), nil); err != nil {
+			return fmt.Errorf("failed to set active minter %s: %w", minter.Hex(), err)
+		}
+	}
+	if err := batch.batch.Set(StateSnapshotKey(), EncodeUint64(snapshot.Height), nil); err != nil {
+		return fmt.Errorf("failed to set state snapshot height: %w", err)
+	}
+
+	return batch.Commit()
+}
+
+// GetStateSnapshotHeight returns the height of the applied state snapshot
+func (s *PebbleStorage) GetStateSnapshotHeight(ctx context.Context) (uint64, error) {
+	if err := s.ensureNotClosed(); err != nil {
+		return 0, err
+	}
+
+	value, closer, err := s.db.Get(StateSnapshotKey())
+	if err != nil {
+		if err == pebble.ErrNotFound {
+			return 0, ErrNotFound
+		}
+		return 0, fmt.Errorf("failed to get state snapshot height: %w", err)
+	}
+	defer closer.Close()
+
+	height, err := DecodeUint64(value)
+	if err != nil {
+		return 0, fmt.Errorf("%w state snapshot height: %w", ErrDecodeFailed, err)
+	}
+	return height, nil
+}
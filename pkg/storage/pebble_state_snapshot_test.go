@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestPebbleStorage_ApplyStateSnapshot(t *testing.T) {
+	s, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	storage := s.(*PebbleStorage)
+	ctx := context.Background()
+
+	holder := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	minter := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	snapshot := &StateSnapshot{
+		Height:        99,
+		Balances:      map[common.Address]*big.Int{holder: big.NewInt(250)},
+		TotalSupply:   big.NewInt(1000),
+		ActiveMinters: map[common.Address]*big.Int{minter: big.NewInt(40)},
+	}
+
+	if _, err := storage.GetStateSnapshotHeight(ctx); err != ErrNotFound {
+		t.Fatalf("GetStateSnapshotHeight() before apply error = %v, want ErrNotFound", err)
+	}
+	if err := storage.ApplyStateSnapshot(ctx, snapshot); err != nil {
+		t.Fatalf("ApplyStateSnapshot() error = %v", err)
+	}
+	if height, err := storage.GetStateSnapshotHeight(ctx); err != nil || height != 99 {
+		t.Errorf("GetStateSnapshotHeight() = %d, %v, want 99", height, err)
+	}
+
+	// Later changes build on the seeded state
+	if err := storage.UpdateBalance(ctx, holder, 100, big.NewInt(-50), common.HexToHash("0x1")); err != nil {
+		t.Fatalf("UpdateBalance() error = %v", err)
+	}
+	if err := storage.UpdateTotalSupply(ctx, big.NewInt(10)); err != nil {
+		t.Fatalf("UpdateTotalSupply() error = %v", err)
+	}
+
+	// Re-applying the same snapshot is a no-op and does not reset that progress
+	if err := storage.ApplyStateSnapshot(ctx, snapshot); err != nil {
+		t.Fatalf("ApplyStateSnapshot() again error = %v", err)
+	}
+
+	if balance, _ := storage.GetAddressBalance(ctx, holder, 0); balance.Int64() != 200 {
+		t.Errorf("latest balance = %s, want 200", balance)
+	}
+	if balance, _ := storage.GetAddressBalance(ctx, holder, 99); balance.Int64() != 250 {
+		t.Errorf("balance at snapshot height = %s, want 250", balance)
+	}
+	if supply, _ := storage.GetTotalSupply(ctx); supply.Int64() != 1010 {
+		t.Errorf("total supply = %s, want 1010", supply)
+	}
+	minters, err := storage.GetActiveMinters(ctx)
+	if err != nil {
+		t.Fatalf("GetActiveMinters() error = %v", err)
+	}
+	if len(minters) != 1 || minters[0].Address != minter || minters[0].Allowance.Int64() != 40 {
+		t.Errorf("active minters = %+v, want %s with allowance 40", minters, minter.Hex())
+	}
+
+	// A snapshot at another height cannot replace the applied one
+	if err := storage.ApplyStateSnapshot(ctx, &StateSnapshot{Height: 50}); err == nil {
+		t.Error("ApplyStateSnapshot() with a different height should fail")
+	}
+}
@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// setupStreamStorage opens a storage holding blocks 1..12 with two
+// transactions each, crossing a digit boundary in the block keys
+func setupStreamStorage(t *testing.T) *PebbleStorage {
+	t.Helper()
+
+	storage := setupTestPebbleStorage(t)
+
+	ctx := context.Background()
+	for height := uint64(1); height <= 12; height++ {
+		if err := storage.SetBlock(ctx, createTestBlockWithTransactions(height, 2)); err != nil {
+			t.Fatalf("SetBlock(%d) error = %v", height, err)
+		}
+	}
+	if err := storage.SetLatestHeight(ctx, 12); err != nil {
+		t.Fatalf("SetLatestHeight() error = %v", err)
+	}
+	return storage
+}
+
+func TestPebbleStorage_StreamBlocks(t *testing.T) {
+	storage := setupStreamStorage(t)
+	ctx := context.Background()
+
+	t.Run("range in height order", func(t *testing.T) {
+		var heights []uint64
+		err := storage.StreamBlocks(ctx, 2, 11, func(block *types.Block) bool {
+			heights = append(heights, block.NumberU64())
+			return true
+		})
+		if err != nil {
+			t.Fatalf("StreamBlocks() error = %v", err)
+		}
+		if len(heights) != 10 {
+			t.Fatalf("streamed %d blocks, want 10", len(heights))
+		}
+		for i, height := range heights {
+			if height != uint64(i+2) {
+				t.Fatalf("block %d has height %d, want %d (heights %v)", i, height, i+2, heights)
+			}
+		}
+	})
+
+	t.Run("range past latest height", func(t *testing.T) {
+		count := 0
+		err := storage.StreamBlocks(ctx, 10, 1000, func(block *types.Block) bool {
+			count++
+			return true
+		})
+		if err != nil || count != 3 {
+			t.Errorf("StreamBlocks() = %d blocks, %v; want 3", count, err)
+		}
+	})
+
+	t.Run("early stop", func(t *testing.T) {
+		var heights []uint64
+		err := storage.StreamBlocks(ctx, 1, 12, func(block *types.Block) bool {
+			heights = append(heights, block.NumberU64())
+			return len(heights) < 3
+		})
+		if err != nil {
+			t.Fatalf("StreamBlocks() error = %v", err)
+		}
+		if len(heights) != 3 || heights[2] != 3 {
+			t.Errorf("streamed heights %v, want [1 2 3]", heights)
+		}
+	})
+
+	t.Run("context canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		count := 0
+		err := storage.StreamBlocks(ctx, 1, 12, func(block *types.Block) bool {
+			count++
+			if count == 2 {
+				cancel()
+			}
+			return true
+		})
+		if err != context.Canceled {
+			t.Errorf("StreamBlocks() error = %v, want context.Canceled", err)
+		}
+		if count != 2 {
+			t.Errorf("streamed %d blocks after cancel, want 2", count)
+		}
+	})
+}
+
+func TestPebbleStorage_StreamTransactions(t *testing.T) {
+	storage := setupStreamStorage(t)
+	ctx := context.Background()
+
+	var locations []*TxLocation
+	err := storage.StreamTransactions(ctx, 9, 12, func(tx *types.Transaction, location *TxLocation) bool {
+		if tx.Nonce() != location.TxIndex {
+			t.Errorf("tx at %d/%d has nonce %d", location.BlockHeight, location.TxIndex, tx.Nonce())
+		}
+		locations = append(locations, location)
+		return true
+	})
+	if err != nil {
+		t.Fatalf("StreamTransactions() error = %v", err)
+	}
+	if len(locations) != 8 {
+		t.Fatalf("streamed %d transactions, want 8", len(locations))
+	}
+	for i, location := range locations {
+		wantHeight, wantIndex := uint64(9+i/2), uint64(i%2)
+		if location.BlockHeight != wantHeight || location.TxIndex != wantIndex {
+			t.Errorf("transaction %d at %d/%d, want %d/%d", i, location.BlockHeight, location.TxIndex, wantHeight, wantIndex)
+		}
+	}
+
+	// Early stop mid-block
+	count := 0
+	err = storage.StreamTransactions(ctx, 1, 12, func(tx *types.Transaction, location *TxLocation) bool {
+		count++
+		return count < 3
+	})
+	if err != nil || count != 3 {
+		t.Errorf("StreamTransactions() with early stop = %d transactions, %v; want 3", count, err)
+	}
+}
@@ -1,10 +1,13 @@
 package storage
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"math/big"
+	"sort"
 
 	"github.com/cockroachdb/pebble"
 	"github.com/ethereum/go-ethereum/common"
@@ -17,6 +20,12 @@ var _ SystemContractReader = (*PebbleStorage)(nil)
 // Ensure PebbleStorage implements SystemContractWriter
 var _ SystemContractWriter = (*PebbleStorage)(nil)
 
+// Ensure PebbleStorage implements SystemContractStateResetter
+var _ SystemContractStateResetter = (*PebbleStorage)(nil)
+
+// Ensure PebbleStorage implements ProposalTallyReader
+var _ ProposalTallyReader = (*PebbleStorage)(nil)
+
 // ============================================================================
 // System Contract Writer Methods
 // ============================================================================
@@ -30,19 +39,24 @@ func (s *PebbleStorage) StoreMintEvent(ctx context.Context, event *MintEvent) er
 		return err
 	}
 
-	// Find the transaction and log index
-	// For now, use a simple counter approach
-	// In production, this should be derived from the actual log index
-	txIndex := uint64(0)
-	logIndex := uint64(0)
-
-	key := MintEventKey(event.BlockNumber, txIndex, logIndex)
+	key := MintEventKey(event.BlockNumber, event.TxIndex, event.LogIndex)
 	data, err := EncodeMintEvent(event)
 	if err != nil {
-		return fmt.Errorf("failed to encode mint event: %w", err)
+		return fmt.Errorf("%w mint event: %w", ErrEncodeFailed, err)
 	}
 
-	if err := s.db.Set(key, data, pebble.Sync); err != nil {
+	batch := s.db.NewBatch()
+	defer batch.Close()
+
+	if err := batch.Set(key, data, nil); err != nil {
+		return fmt.Errorf("failed to store mint event: %w", err)
+	}
+	// The minter index value is the event key
+	if err := batch.Set(MintMinterIndexKey(event.Minter, event.BlockNumber, event.TxIndex, event.LogIndex), key, nil); err != nil {
+		return fmt.Errorf("failed to index mint event: %w", err)
+	}
+
+	if err := batch.Commit(pebble.Sync); err != nil {
 		return fmt.Errorf("failed to store mint event: %w", err)
 	}
 
@@ -58,16 +72,24 @@ func (s *PebbleStorage) StoreBurnEvent(ctx context.Context, event *BurnEvent) er
 		return err
 	}
 
-	txIndex := uint64(0)
-	logIndex := uint64(0)
-
-	key := BurnEventKey(event.BlockNumber, txIndex, logIndex)
+	key := BurnEventKey(event.BlockNumber, event.TxIndex, event.LogIndex)
 	data, err := EncodeBurnEvent(event)
 	if err != nil {
-		return fmt.Errorf("failed to encode burn event: %w", err)
+		return fmt.Errorf("%w burn event: %w", ErrEncodeFailed, err)
 	}
 
-	if err := s.db.Set(key, data, pebble.Sync); err != nil {
+	batch := s.db.NewBatch()
+	defer batch.Close()
+
+	if err := batch.Set(key, data, nil); err != nil {
+		return fmt.Errorf("failed to store burn event: %w", err)
+	}
+	// The burner index value is the event key
+	if err := batch.Set(BurnBurnerIndexKey(event.Burner, event.BlockNumber, event.TxIndex, event.LogIndex), key, nil); err != nil {
+		return fmt.Errorf("failed to index burn event: %w", err)
+	}
+
+	if err := batch.Commit(pebble.Sync); err != nil {
 		return fmt.Errorf("failed to store burn event: %w", err)
 	}
 
@@ -86,7 +108,7 @@ func (s *PebbleStorage) StoreMinterConfigEvent(ctx context.Context, event *Minte
 	key := MinterConfigEventKey(event.Minter, event.BlockNumber)
 	data, err := EncodeMinterConfigEvent(event)
 	if err != nil {
-		return fmt.Errorf("failed to encode minter config event: %w", err)
+		return fmt.Errorf("%w minter config event: %w", ErrEncodeFailed, err)
 	}
 
 	if err := s.db.Set(key, data, pebble.Sync); err != nil {
@@ -96,7 +118,9 @@ func (s *PebbleStorage) StoreMinterConfigEvent(ctx context.Context, event *Minte
 	return nil
 }
 
-// StoreProposal stores a governance proposal
+// StoreProposal stores a governance proposal. Re-storing a proposal whose
+// status changed, as happens on re-index, moves it out of its old status
+// index so it is only listed under its current status.
 func (s *PebbleStorage) StoreProposal(ctx context.Context, proposal *Proposal) error {
 	if err := s.ensureNotClosed(); err != nil {
 		return err
@@ -108,23 +132,60 @@ func (s *PebbleStorage) StoreProposal(ctx context.Context, proposal *Proposal) e
 	key := ProposalKey(proposal.Contract, proposal.ProposalID.String())
 	data, err := EncodeProposal(proposal)
 	if err != nil {
-		return fmt.Errorf("failed to encode proposal: %w", err)
+		return fmt.Errorf("%w proposal: %w", ErrEncodeFailed, err)
 	}
 
-	if err := s.db.Set(key, data, pebble.Sync); err != nil {
-		return fmt.Errorf("failed to store proposal: %w", err)
+	existing, err := s.storedProposalStatus(key)
+	if err != nil {
+		return err
 	}
 
-	// Store in status index
-	statusKey := ProposalStatusIndexKey(proposal.Contract, uint8(proposal.Status), proposal.ProposalID.String())
-	if err := s.db.Set(statusKey, []byte{1}, pebble.Sync); err != nil {
+	// Write the proposal and swap its status index entry in one batch
+	batch := s.db.NewBatch()
+	defer batch.Close()
+
+	if existing != nil && *existing != proposal.Status {
+		if err := batch.Delete(ProposalStatusIndexKey(proposal.Contract, uint8(*existing), proposal.ProposalID), nil); err != nil {
+			return fmt.Errorf("failed to delete old status index: %w", err)
+		}
+	}
+	if err := batch.Set(key, data, nil); err != nil {
+		return fmt.Errorf("failed to store proposal: %w", err)
+	}
+	statusKey := ProposalStatusIndexKey(proposal.Contract, uint8(proposal.Status), proposal.ProposalID)
+	if err := batch.Set(statusKey, []byte{1}, nil); err != nil {
 		return fmt.Errorf("failed to store proposal status index: %w", err)
 	}
 
+	if err := batch.Commit(pebble.Sync); err != nil {
+		return fmt.Errorf("failed to commit proposal: %w", err)
+	}
+
 	return nil
 }
 
-// UpdateProposalStatus updates the status of a proposal
+// storedProposalStatus returns the status of the proposal stored at key, or
+// nil if none is stored
+func (s *PebbleStorage) storedProposalStatus(key []byte) (*ProposalStatus, error) {
+	data, closer, err := s.db.Get(key)
+	if err != nil {
+		if err == pebble.ErrNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get existing proposal: %w", err)
+	}
+	defer closer.Close()
+
+	existing, err := DecodeProposal(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w existing proposal: %w", ErrDecodeFailed, err)
+	}
+	return &existing.Status, nil
+}
+
+// UpdateProposalStatus updates the status of a proposal. It returns
+// ErrInvalidProposalTransition if the proposal cannot move to status from its
+// current status.
 func (s *PebbleStorage) UpdateProposalStatus(ctx context.Context, contract common.Address, proposalID *big.Int, status ProposalStatus, executedAt uint64) error {
 	if err := s.ensureNotClosed(); err != nil {
 		return err
@@ -143,14 +204,13 @@ func (s *PebbleStorage) UpdateProposalStatus(ctx context.Context, contract commo
 
 	proposal, err := DecodeProposal(data)
 	if err != nil {
-		return fmt.Errorf("failed to decode proposal: %w", err)
+		return fmt.Errorf("%w proposal: %w", ErrDecodeFailed, err)
 	}
 
-	// Remove old status index
-	oldStatusKey := ProposalStatusIndexKey(contract, uint8(proposal.Status), proposalID.String())
-	if err := s.db.Delete(oldStatusKey, pebble.Sync); err != nil {
-		return fmt.Errorf("failed to delete old status index: %w", err)
+	if !proposal.Status.CanTransitionTo(status) {
+		return fmt.Errorf("%w: proposal %s from %s to %s", ErrInvalidProposalTransition, proposalID, proposal.Status, status)
 	}
+	oldStatus := proposal.Status
 
 	// Update proposal
 	proposal.Status = status
@@ -158,22 +218,30 @@ func (s *PebbleStorage) UpdateProposalStatus(ctx context.Context, contract commo
 		proposal.ExecutedAt = &executedAt
 	}
 
-	// Store updated proposal
 	updatedData, err := EncodeProposal(proposal)
 	if err != nil {
-		return fmt.Errorf("failed to encode updated proposal: %w", err)
+		return fmt.Errorf("%w updated proposal: %w", ErrEncodeFailed, err)
 	}
 
-	if err := s.db.Set(key, updatedData, pebble.Sync); err != nil {
+	// Move the proposal between status indexes in one batch so it never
+	// appears under both statuses
+	batch := s.db.NewBatch()
+	defer batch.Close()
+
+	if err := batch.Delete(ProposalStatusIndexKey(contract, uint8(oldStatus), proposalID), nil); err != nil {
+		return fmt.Errorf("failed to delete old status index: %w", err)
+	}
+	if err := batch.Set(key, updatedData, nil); err != nil {
 		return fmt.Errorf("failed to store updated proposal: %w", err)
 	}
-
-	// Add new status index
-	newStatusKey := ProposalStatusIndexKey(contract, uint8(status), proposalID.String())
-	if err := s.db.Set(newStatusKey, []byte{1}, pebble.Sync); err != nil {
+	if err := batch.Set(ProposalStatusIndexKey(contract, uint8(status), proposalID), []byte{1}, nil); err != nil {
 		return fmt.Errorf("failed to store new status index: %w", err)
 	}
 
+	if err := batch.Commit(pebble.Sync); err != nil {
+		return fmt.Errorf("failed to commit proposal status update: %w", err)
+	}
+
 	return nil
 }
 
@@ -189,7 +257,7 @@ func (s *PebbleStorage) StoreProposalVote(ctx context.Context, vote *ProposalVot
 	key := ProposalVoteKey(vote.Contract, vote.ProposalID.String(), vote.Voter)
 	data, err := EncodeProposalVote(vote)
 	if err != nil {
-		return fmt.Errorf("failed to encode vote: %w", err)
+		return fmt.Errorf("%w vote: %w", ErrEncodeFailed, err)
 	}
 
 	if err := s.db.Set(key, data, pebble.Sync); err != nil {
@@ -213,7 +281,7 @@ func (s *PebbleStorage) StoreGasTipUpdateEvent(ctx context.Context, event *GasTi
 	key := GasTipUpdateEventKey(event.BlockNumber, txIndex)
 	data, err := EncodeGasTipUpdateEvent(event)
 	if err != nil {
-		return fmt.Errorf("failed to encode gas tip update event: %w", err)
+		return fmt.Errorf("%w gas tip update event: %w", ErrEncodeFailed, err)
 	}
 
 	if err := s.db.Set(key, data, pebble.Sync); err != nil {
@@ -235,7 +303,7 @@ func (s *PebbleStorage) StoreBlacklistEvent(ctx context.Context, event *Blacklis
 	key := BlacklistEventKey(event.Account, event.BlockNumber)
 	data, err := EncodeBlacklistEvent(event)
 	if err != nil {
-		return fmt.Errorf("failed to encode blacklist event: %w", err)
+		return fmt.Errorf("%w blacklist event: %w", ErrEncodeFailed, err)
 	}
 
 	if err := s.db.Set(key, data, pebble.Sync); err != nil {
@@ -257,7 +325,7 @@ func (s *PebbleStorage) StoreValidatorChangeEvent(ctx context.Context, event *Va
 	key := ValidatorChangeEventKey(event.Validator, event.BlockNumber)
 	data, err := EncodeValidatorChangeEvent(event)
 	if err != nil {
-		return fmt.Errorf("failed to encode validator change event: %w", err)
+		return fmt.Errorf("%w validator change event: %w", ErrEncodeFailed, err)
 	}
 
 	if err := s.db.Set(key, data, pebble.Sync); err != nil {
@@ -281,7 +349,7 @@ func (s *PebbleStorage) StoreMemberChangeEvent(ctx context.Context, event *Membe
 	key := MemberChangeEventKey(event.Contract, event.BlockNumber, txIndex)
 	data, err := EncodeMemberChangeEvent(event)
 	if err != nil {
-		return fmt.Errorf("failed to encode member change event: %w", err)
+		return fmt.Errorf("%w member change event: %w", ErrEncodeFailed, err)
 	}
 
 	if err := s.db.Set(key, data, pebble.Sync); err != nil {
@@ -305,7 +373,7 @@ func (s *PebbleStorage) StoreEmergencyPauseEvent(ctx context.Context, event *Eme
 	key := EmergencyPauseEventKey(event.Contract, event.BlockNumber, txIndex)
 	data, err := EncodeEmergencyPauseEvent(event)
 	if err != nil {
-		return fmt.Errorf("failed to encode emergency pause event: %w", err)
+		return fmt.Errorf("%w emergency pause event: %w", ErrEncodeFailed, err)
 	}
 
 	if err := s.db.Set(key, data, pebble.Sync); err != nil {
@@ -327,7 +395,7 @@ func (s *PebbleStorage) StoreDepositMintProposal(ctx context.Context, proposal *
 	key := DepositMintProposalKey(proposal.ProposalID.String())
 	data, err := EncodeDepositMintProposal(proposal)
 	if err != nil {
-		return fmt.Errorf("failed to encode deposit mint proposal: %w", err)
+		return fmt.Errorf("%w deposit mint proposal: %w", ErrEncodeFailed, err)
 	}
 
 	if err := s.db.Set(key, data, pebble.Sync); err != nil {
@@ -451,6 +519,39 @@ func (s *PebbleStorage) UpdateBlacklistStatus(ctx context.Context, address commo
 	return nil
 }
 
+// ResetSystemContractState removes the total supply, the active minter,
+// validator and blacklist indexes, and the mint and burn records with their
+// minter and burner indexes. Other event records are keyed by block and are
+// overwritten on replay, so they are left in place.
+func (s *PebbleStorage) ResetSystemContractState(ctx context.Context) error {
+	if err := s.ensureNotClosed(); err != nil {
+		return err
+	}
+	if err := s.ensureNotReadOnly(); err != nil {
+		return err
+	}
+
+	for _, prefix := range [][]byte{
+		MintEventKeyPrefix(),
+		BurnEventKeyPrefix(),
+		[]byte(prefixIdxMintMinter),
+		[]byte(prefixIdxBurnBurner),
+		MinterActiveIndexKeyPrefix(),
+		ValidatorActiveIndexKeyPrefix(),
+		BlacklistActiveIndexKeyPrefix(),
+	} {
+		if _, err := s.DeleteByPrefix(prefix); err != nil {
+			return fmt.Errorf("failed to clear %s: %w", prefix, err)
+		}
+	}
+
+	if err := s.db.Delete(TotalSupplyKey(), pebble.Sync); err != nil {
+		return fmt.Errorf("failed to clear total supply: %w", err)
+	}
+
+	return nil
+}
+
 // IndexSystemContractEvent indexes a single system contract event from a log
 // This is a placeholder implementation - actual parsing logic should be handled by events package
 func (s *PebbleStorage) IndexSystemContractEvent(ctx context.Context, log *types.Log) error {
@@ -508,25 +609,35 @@ func (s *PebbleStorage) GetTotalSupply(ctx context.Context) (*big.Int, error) {
 	return DecodeBigInt(data), nil
 }
 
-// GetMintEvents returns mint events within a block range
+// blockRangeBounds returns the iterator bounds covering the block-keyed event
+// records under prefix from fromBlock through toBlock inclusive
+func blockRangeBounds(prefix []byte, fromBlock, toBlock uint64) ([]byte, []byte) {
+	lowerBound := []byte(fmt.Sprintf("%s%020d/", prefix, fromBlock))
+	if toBlock == math.MaxUint64 {
+		return lowerBound, prefixUpperBound(prefix)
+	}
+	return lowerBound, []byte(fmt.Sprintf("%s%020d/", prefix, toBlock+1))
+}
+
+// GetMintEvents returns mint events within a block range. With a zero minter
+// address it returns every mint in the range in (block, tx, log) order.
 func (s *PebbleStorage) GetMintEvents(ctx context.Context, fromBlock, toBlock uint64, minter common.Address, limit, offset int) ([]*MintEvent, error) {
 	if err := s.ensureNotClosed(); err != nil {
 		return nil, err
 	}
+	if fromBlock > toBlock {
+		return nil, nil
+	}
 
 	// Use minter-specific index if minter is specified, otherwise scan all mint events
-	var keyPrefix []byte
 	var lowerBound, upperBound []byte
 
 	if minter != (common.Address{}) {
 		// Use minter index for efficient filtering
-		lowerBound = MintMinterIndexKey(minter, fromBlock)
-		upperBound = MintMinterIndexKey(minter, toBlock+1)
+		lowerBound, upperBound = blockRangeBounds(MintMinterIndexKeyPrefix(minter), fromBlock, toBlock)
 	} else {
 		// Scan all mint events in block range
-		keyPrefix = MintEventKeyPrefix()
-		lowerBound = []byte(fmt.Sprintf("%s%020d/", string(keyPrefix), fromBlock))
-		upperBound = []byte(fmt.Sprintf("%s%020d/", string(keyPrefix), toBlock+1))
+		lowerBound, upperBound = blockRangeBounds(MintEventKeyPrefix(), fromBlock, toBlock)
 	}
 
 	iter, err := s.db.NewIter(&pebble.IterOptions{
@@ -573,9 +684,9 @@ func (s *PebbleStorage) GetMintEvents(ctx context.Context, fromBlock, toBlock ui
 		}
 
 		// Decode event
-		event := &MintEvent{}
-		if err := json.Unmarshal(eventData, event); err != nil {
-			return nil, fmt.Errorf("failed to decode mint event: %w", err)
+		event, err := DecodeMintEvent(eventData)
+		if err != nil {
+			return nil, fmt.Errorf("%w mint event: %w", ErrDecodeFailed, err)
 		}
 
 		events = append(events, event)
@@ -589,24 +700,25 @@ func (s *PebbleStorage) GetMintEvents(ctx context.Context, fromBlock, toBlock ui
 	return events, nil
 }
 
-// GetBurnEvents returns burn events within a block range
+// GetBurnEvents returns burn events within a block range. With a zero burner
+// address it returns every burn in the range in (block, tx, log) order.
 func (s *PebbleStorage) GetBurnEvents(ctx context.Context, fromBlock, toBlock uint64, burner common.Address, limit, offset int) ([]*BurnEvent, error) {
 	if err := s.ensureNotClosed(); err != nil {
 		return nil, err
 	}
+	if fromBlock > toBlock {
+		return nil, nil
+	}
 
 	// Use burner-specific index if burner is specified, otherwise scan all burn events
 	var lowerBound, upperBound []byte
 
 	if burner != (common.Address{}) {
 		// Use burner index for efficient filtering
-		lowerBound = BurnBurnerIndexKey(burner, fromBlock)
-		upperBound = BurnBurnerIndexKey(burner, toBlock+1)
+		lowerBound, upperBound = blockRangeBounds(BurnBurnerIndexKeyPrefix(burner), fromBlock, toBlock)
 	} else {
 		// Scan all burn events in block range
-		keyPrefix := BurnEventKeyPrefix()
-		lowerBound = []byte(fmt.Sprintf("%s%020d/", string(keyPrefix), fromBlock))
-		upperBound = []byte(fmt.Sprintf("%s%020d/", string(keyPrefix), toBlock+1))
+		lowerBound, upperBound = blockRangeBounds(BurnEventKeyPrefix(), fromBlock, toBlock)
 	}
 
 	iter, err := s.db.NewIter(&pebble.IterOptions{
@@ -653,9 +765,9 @@ func (s *PebbleStorage) GetBurnEvents(ctx context.Context, fromBlock, toBlock ui
 		}
 
 		// Decode event
-		event := &BurnEvent{}
-		if err := json.Unmarshal(eventData, event); err != nil {
-			return nil, fmt.Errorf("failed to decode burn event: %w", err)
+		event, err := DecodeBurnEvent(eventData)
+		if err != nil {
+			return nil, fmt.Errorf("%w burn event: %w", ErrDecodeFailed, err)
 		}
 
 		events = append(events, event)
@@ -669,8 +781,10 @@ func (s *PebbleStorage) GetBurnEvents(ctx context.Context, fromBlock, toBlock ui
 	return events, nil
 }
 
-// GetActiveMinters returns list of active minters
-func (s *PebbleStorage) GetActiveMinters(ctx context.Context) ([]common.Address, error) {
+// GetActiveMinters returns active minters with their allowances.
+// Index keys use checksummed hex, so the result is sorted by address bytes
+// rather than returned in key order.
+func (s *PebbleStorage) GetActiveMinters(ctx context.Context) ([]*ActiveMinter, error) {
 	if err := s.ensureNotClosed(); err != nil {
 		return nil, err
 	}
@@ -685,19 +799,25 @@ func (s *PebbleStorage) GetActiveMinters(ctx context.Context) ([]common.Address,
 	}
 	defer iter.Close()
 
-	var minters []common.Address
+	var minters []*ActiveMinter
 	for iter.First(); iter.Valid(); iter.Next() {
-		// Extract address from key
+		// Extract address from key; the value holds the allowance
 		key := string(iter.Key())
 		addrHex := key[len(string(keyPrefix)):]
-		addr := common.HexToAddress(addrHex)
-		minters = append(minters, addr)
+		minters = append(minters, &ActiveMinter{
+			Address:   common.HexToAddress(addrHex),
+			Allowance: DecodeBigInt(iter.Value()),
+		})
 	}
 
 	if err := iter.Error(); err != nil {
 		return nil, fmt.Errorf("iterator error: %w", err)
 	}
 
+	sort.Slice(minters, func(i, j int) bool {
+		return bytes.Compare(minters[i].Address.Bytes(), minters[j].Address.Bytes()) < 0
+	})
+
 	return minters, nil
 }
 
@@ -740,7 +860,7 @@ func (s *PebbleStorage) GetMinterHistory(ctx context.Context, minter common.Addr
 	for iter.First(); iter.Valid(); iter.Next() {
 		event := &MinterConfigEvent{}
 		if err := json.Unmarshal(iter.Value(), event); err != nil {
-			return nil, fmt.Errorf("failed to decode minter config event: %w", err)
+			return nil, fmt.Errorf("%w minter config event: %w", ErrDecodeFailed, err)
 		}
 		events = append(events, event)
 	}
@@ -752,7 +872,7 @@ func (s *PebbleStorage) GetMinterHistory(ctx context.Context, minter common.Addr
 	return events, nil
 }
 
-// GetActiveValidators returns list of active validators
+// GetActiveValidators returns active validators sorted by address bytes
 func (s *PebbleStorage) GetActiveValidators(ctx context.Context) ([]common.Address, error) {
 	if err := s.ensureNotClosed(); err != nil {
 		return nil, err
@@ -781,6 +901,10 @@ func (s *PebbleStorage) GetActiveValidators(ctx context.Context) ([]common.Addre
 		return nil, fmt.Errorf("iterator error: %w", err)
 	}
 
+	sort.Slice(validators, func(i, j int) bool {
+		return bytes.Compare(validators[i].Bytes(), validators[j].Bytes()) < 0
+	})
+
 	return validators, nil
 }
 
@@ -805,9 +929,10 @@ func (s *PebbleStorage) GetGasTipHistory(ctx context.Context, fromBlock, toBlock
 
 	var events []*GasTipUpdateEvent
 	for iter.First(); iter.Valid(); iter.Next() {
-		event := &GasTipUpdateEvent{}
-		if err := json.Unmarshal(iter.Value(), event); err != nil {
-			return nil, fmt.Errorf("failed to decode gas tip event: %w", err)
+		// Events are written RLP-encoded by StoreGasTipUpdateEvent
+		event, err := DecodeGasTipUpdateEvent(iter.Value())
+		if err != nil {
+			return nil, fmt.Errorf("%w gas tip event: %w", ErrDecodeFailed, err)
 		}
 		events = append(events, event)
 	}
@@ -839,7 +964,7 @@ func (s *PebbleStorage) GetValidatorHistory(ctx context.Context, validator commo
 	for iter.First(); iter.Valid(); iter.Next() {
 		event := &ValidatorChangeEvent{}
 		if err := json.Unmarshal(iter.Value(), event); err != nil {
-			return nil, fmt.Errorf("failed to decode validator change event: %w", err)
+			return nil, fmt.Errorf("%w validator change event: %w", ErrDecodeFailed, err)
 		}
 		events = append(events, event)
 	}
@@ -873,7 +998,7 @@ func (s *PebbleStorage) GetMinterConfigHistory(ctx context.Context, fromBlock, t
 	for iter.First(); iter.Valid(); iter.Next() {
 		event := &MinterConfigEvent{}
 		if err := json.Unmarshal(iter.Value(), event); err != nil {
-			return nil, fmt.Errorf("failed to decode minter config event: %w", err)
+			return nil, fmt.Errorf("%w minter config event: %w", ErrDecodeFailed, err)
 		}
 
 		// Filter by block range
@@ -909,7 +1034,7 @@ func (s *PebbleStorage) GetEmergencyPauseHistory(ctx context.Context, contract c
 	for iter.First(); iter.Valid(); iter.Next() {
 		event := &EmergencyPauseEvent{}
 		if err := json.Unmarshal(iter.Value(), event); err != nil {
-			return nil, fmt.Errorf("failed to decode emergency pause event: %w", err)
+			return nil, fmt.Errorf("%w emergency pause event: %w", ErrDecodeFailed, err)
 		}
 		events = append(events, event)
 	}
@@ -942,7 +1067,7 @@ func (s *PebbleStorage) GetDepositMintProposals(ctx context.Context, fromBlock,
 	for iter.First(); iter.Valid(); iter.Next() {
 		proposal := &DepositMintProposal{}
 		if err := json.Unmarshal(iter.Value(), proposal); err != nil {
-			return nil, fmt.Errorf("failed to decode deposit mint proposal: %w", err)
+			return nil, fmt.Errorf("%w deposit mint proposal: %w", ErrDecodeFailed, err)
 		}
 
 		// Filter by block range and status
@@ -1022,7 +1147,7 @@ func (s *PebbleStorage) GetBlacklistHistory(ctx context.Context, address common.
 	for iter.First(); iter.Valid(); iter.Next() {
 		event := &BlacklistEvent{}
 		if err := json.Unmarshal(iter.Value(), event); err != nil {
-			return nil, fmt.Errorf("failed to decode blacklist event: %w", err)
+			return nil, fmt.Errorf("%w blacklist event: %w", ErrDecodeFailed, err)
 		}
 		events = append(events, event)
 	}
@@ -1075,7 +1200,7 @@ func (s *PebbleStorage) GetAuthorizedAccounts(ctx context.Context) ([]common.Add
 	for iter.First(); iter.Valid(); iter.Next() {
 		event := &AuthorizedAccountEvent{}
 		if err := json.Unmarshal(iter.Value(), event); err != nil {
-			return nil, fmt.Errorf("failed to decode authorized account event: %w", err)
+			return nil, fmt.Errorf("%w authorized account event: %w", ErrDecodeFailed, err)
 		}
 		if event.Action == "added" {
 			accountSet[event.Account] = true
@@ -1102,6 +1227,18 @@ func (s *PebbleStorage) GetProposals(ctx context.Context, contract common.Addres
 		return nil, err
 	}
 
+	// Writable storage migrates the legacy index on open; a read-only view of
+	// an unmigrated database orders the legacy index in memory instead
+	if s.config.ReadOnly {
+		ids, err := s.legacyProposalIDs(contract, uint8(status))
+		if err != nil {
+			return nil, err
+		}
+		if len(ids) > 0 {
+			return s.proposalsByID(contract, ids, limit, offset), nil
+		}
+	}
+
 	keyPrefix := ProposalStatusIndexKeyPrefix(contract, uint8(status))
 	iter, err := s.db.NewIter(&pebble.IterOptions{
 		LowerBound: keyPrefix,
@@ -1112,8 +1249,7 @@ func (s *PebbleStorage) GetProposals(ctx context.Context, contract common.Addres
 	}
 	defer iter.Close()
 
-	var proposals []*Proposal
-	count := 0
+	var ids []*big.Int
 	skipped := 0
 
 	for iter.First(); iter.Valid(); iter.Next() {
@@ -1124,16 +1260,38 @@ func (s *PebbleStorage) GetProposals(ctx context.Context, contract common.Addres
 		}
 
 		// Check limit
-		if limit > 0 && count >= limit {
+		if limit > 0 && len(ids) >= limit {
 			break
 		}
 
-		// Extract proposal ID from index key and get proposal
-		key := string(iter.Key())
-		proposalID := key[len(string(keyPrefix)):]
+		proposalID, err := ParseProposalStatusIndexKey(iter.Key())
+		if err != nil {
+			continue // Skip malformed index keys
+		}
+		ids = append(ids, proposalID)
+	}
+
+	if err := iter.Error(); err != nil {
+		return nil, fmt.Errorf("iterator error: %w", err)
+	}
+
+	return s.proposalsByID(contract, ids, 0, 0), nil
+}
 
-		proposalKey := ProposalKey(contract, proposalID)
-		data, closer, err := s.db.Get(proposalKey)
+// proposalsByID loads the page of proposals selected by limit and offset from
+// ids, skipping proposals that are missing or cannot be decoded
+func (s *PebbleStorage) proposalsByID(contract common.Address, ids []*big.Int, limit, offset int) []*Proposal {
+	if offset >= len(ids) {
+		return nil
+	}
+	ids = ids[offset:]
+	if limit > 0 && len(ids) > limit {
+		ids = ids[:limit]
+	}
+
+	var proposals []*Proposal
+	for _, proposalID := range ids {
+		data, closer, err := s.db.Get(ProposalKey(contract, proposalID.String()))
 		if err != nil {
 			continue // Skip if proposal not found
 		}
@@ -1145,14 +1303,8 @@ func (s *PebbleStorage) GetProposals(ctx context.Context, contract common.Addres
 		}
 
 		proposals = append(proposals, proposal)
-		count++
-	}
-
-	if err := iter.Error(); err != nil {
-		return nil, fmt.Errorf("iterator error: %w", err)
 	}
-
-	return proposals, nil
+	return proposals
 }
 
 // GetProposalById returns a specific proposal by ID
@@ -1173,7 +1325,7 @@ func (s *PebbleStorage) GetProposalById(ctx context.Context, contract common.Add
 
 	proposal, err := DecodeProposal(data)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode proposal: %w", err)
+		return nil, fmt.Errorf("%w proposal: %w", ErrDecodeFailed, err)
 	}
 
 	return proposal, nil
@@ -1211,6 +1363,42 @@ func (s *PebbleStorage) GetProposalVotes(ctx context.Context, contract common.Ad
 	return votes, nil
 }
 
+// GetProposalTally returns the vote counts of a proposal. Votes are keyed by
+// voter, so a member who voted again is counted once with their latest vote.
+func (s *PebbleStorage) GetProposalTally(ctx context.Context, contract common.Address, proposalID *big.Int) (*ProposalTally, error) {
+	if err := s.ensureNotClosed(); err != nil {
+		return nil, err
+	}
+
+	votes, err := s.GetProposalVotes(ctx, contract, proposalID)
+	if err != nil {
+		return nil, err
+	}
+
+	tally := &ProposalTally{
+		Contract:   contract,
+		ProposalID: new(big.Int).Set(proposalID),
+		Voters:     uint32(len(votes)),
+	}
+	for _, vote := range votes {
+		if vote.Approval {
+			tally.Approvals++
+		} else {
+			tally.Rejections++
+		}
+	}
+
+	proposal, err := s.GetProposalById(ctx, contract, proposalID)
+	if err != nil {
+		return nil, err
+	}
+	if proposal != nil {
+		tally.RequiredApprovals = proposal.RequiredApprovals
+	}
+
+	return tally, nil
+}
+
 // GetMemberHistory returns member change history for a contract
 func (s *PebbleStorage) GetMemberHistory(ctx context.Context, contract common.Address) ([]*MemberChangeEvent, error) {
 	if err := s.ensureNotClosed(); err != nil {
@@ -1231,7 +1419,7 @@ func (s *PebbleStorage) GetMemberHistory(ctx context.Context, contract common.Ad
 	for iter.First(); iter.Valid(); iter.Next() {
 		event := &MemberChangeEvent{}
 		if err := json.Unmarshal(iter.Value(), event); err != nil {
-			return nil, fmt.Errorf("failed to decode member change event: %w", err)
+			return nil, fmt.Errorf("%w member change event: %w", ErrDecodeFailed, err)
 		}
 		events = append(events, event)
 	}
@@ -1256,7 +1444,7 @@ func (s *PebbleStorage) StoreMaxProposalsUpdateEvent(ctx context.Context, event
 	key := MaxProposalsUpdateEventKey(event.Contract, event.BlockNumber, txIndex)
 	data, err := json.Marshal(event)
 	if err != nil {
-		return fmt.Errorf("failed to encode max proposals update event: %w", err)
+		return fmt.Errorf("%w max proposals update event: %w", ErrEncodeFailed, err)
 	}
 
 	if err := s.db.Set(key, data, pebble.Sync); err != nil {
@@ -1279,7 +1467,7 @@ func (s *PebbleStorage) StoreProposalExecutionSkippedEvent(ctx context.Context,
 	key := ProposalExecutionSkippedEventKey(event.Contract, event.BlockNumber, txIndex)
 	data, err := json.Marshal(event)
 	if err != nil {
-		return fmt.Errorf("failed to encode proposal execution skipped event: %w", err)
+		return fmt.Errorf("%w proposal execution skipped event: %w", ErrEncodeFailed, err)
 	}
 
 	if err := s.db.Set(key, data, pebble.Sync); err != nil {
@@ -1309,7 +1497,7 @@ func (s *PebbleStorage) GetMaxProposalsUpdateHistory(ctx context.Context, contra
 	for iter.First(); iter.Valid(); iter.Next() {
 		event := &MaxProposalsUpdateEvent{}
 		if err := json.Unmarshal(iter.Value(), event); err != nil {
-			return nil, fmt.Errorf("failed to decode max proposals update event: %w", err)
+			return nil, fmt.Errorf("%w max proposals update event: %w", ErrDecodeFailed, err)
 		}
 		results = append(results, event)
 	}
@@ -1341,7 +1529,7 @@ func (s *PebbleStorage) GetProposalExecutionSkippedEvents(ctx context.Context, c
 	for iter.First(); iter.Valid(); iter.Next() {
 		event := &ProposalExecutionSkippedEvent{}
 		if err := json.Unmarshal(iter.Value(), event); err != nil {
-			return nil, fmt.Errorf("failed to decode proposal execution skipped event: %w", err)
+			return nil, fmt.Errorf("%w proposal execution skipped event: %w", ErrDecodeFailed, err)
 		}
 		// Filter by proposalID if specified
 		if proposalID != nil && event.ProposalID != nil && event.ProposalID.Cmp(proposalID) != 0 {
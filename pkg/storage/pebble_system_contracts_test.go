@@ -2,10 +2,13 @@ package storage
 
 import (
 	"context"
+	"fmt"
+	"math"
 	"math/big"
 	"os"
 	"testing"
 
+	"github.com/cockroachdb/pebble"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -237,6 +240,324 @@ func TestPebbleStorage_UpdateProposalStatus(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestPebbleStorage_UpdateProposalStatus_Transitions(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pebble_proposal_transition_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	cfg := DefaultConfig(tempDir)
+	storage, err := NewPebbleStorage(cfg)
+	require.NoError(t, err)
+	defer storage.Close()
+
+	ctx := context.Background()
+	contract := common.HexToAddress("0x5555")
+
+	// statusIDs returns the IDs of proposals indexed under status
+	statusIDs := func(status ProposalStatus) []string {
+		proposals, err := storage.GetProposals(ctx, contract, status, 0, 0)
+		require.NoError(t, err)
+		ids := make([]string, 0, len(proposals))
+		for _, p := range proposals {
+			ids = append(ids, p.ProposalID.String())
+		}
+		return ids
+	}
+
+	proposalID := big.NewInt(11)
+	err = storage.StoreProposal(ctx, &Proposal{
+		Contract:      contract,
+		ProposalID:    proposalID,
+		Proposer:      common.HexToAddress("0x6666"),
+		MemberVersion: big.NewInt(1),
+		Status:        ProposalStatusVoting,
+		BlockNumber:   200,
+	})
+	require.NoError(t, err)
+
+	// Legal transitions move the proposal between status buckets
+	require.NoError(t, storage.UpdateProposalStatus(ctx, contract, proposalID, ProposalStatusApproved, 0))
+	assert.Empty(t, statusIDs(ProposalStatusVoting))
+	assert.Equal(t, []string{"11"}, statusIDs(ProposalStatusApproved))
+
+	require.NoError(t, storage.UpdateProposalStatus(ctx, contract, proposalID, ProposalStatusExecuted, 300))
+	assert.Empty(t, statusIDs(ProposalStatusApproved))
+	assert.Equal(t, []string{"11"}, statusIDs(ProposalStatusExecuted))
+
+	// Repeating the current status is allowed for re-indexing
+	require.NoError(t, storage.UpdateProposalStatus(ctx, contract, proposalID, ProposalStatusExecuted, 300))
+
+	// Executed is final; an out-of-order event must not move it back
+	err = storage.UpdateProposalStatus(ctx, contract, proposalID, ProposalStatusVoting, 0)
+	assert.ErrorIs(t, err, ErrInvalidProposalTransition)
+	err = storage.UpdateProposalStatus(ctx, contract, proposalID, ProposalStatusCancelled, 0)
+	assert.ErrorIs(t, err, ErrInvalidProposalTransition)
+
+	// A rejected update leaves the proposal and its index untouched
+	proposal, err := storage.GetProposalById(ctx, contract, proposalID)
+	require.NoError(t, err)
+	assert.Equal(t, ProposalStatusExecuted, proposal.Status)
+	require.NotNil(t, proposal.ExecutedAt)
+	assert.Equal(t, uint64(300), *proposal.ExecutedAt)
+	assert.Empty(t, statusIDs(ProposalStatusVoting))
+	assert.Empty(t, statusIDs(ProposalStatusCancelled))
+	assert.Equal(t, []string{"11"}, statusIDs(ProposalStatusExecuted))
+}
+
+func TestPebbleStorage_StoreProposal_Restore(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pebble_proposal_restore_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	cfg := DefaultConfig(tempDir)
+	storage, err := NewPebbleStorage(cfg)
+	require.NoError(t, err)
+	defer storage.Close()
+
+	ctx := context.Background()
+	contract := common.HexToAddress("0x5555")
+	proposalID := big.NewInt(12)
+
+	// statusesOf returns every status the proposal is indexed under
+	statusesOf := func() []ProposalStatus {
+		var found []ProposalStatus
+		for _, status := range []ProposalStatus{
+			ProposalStatusVoting, ProposalStatusApproved, ProposalStatusExecuted,
+			ProposalStatusCancelled, ProposalStatusExpired, ProposalStatusFailed, ProposalStatusRejected,
+		} {
+			proposals, err := storage.GetProposals(ctx, contract, status, 0, 0)
+			require.NoError(t, err)
+			for _, p := range proposals {
+				if p.ProposalID.Cmp(proposalID) == 0 {
+					found = append(found, status)
+				}
+			}
+		}
+		return found
+	}
+
+	proposal := &Proposal{
+		Contract:      contract,
+		ProposalID:    proposalID,
+		Proposer:      common.HexToAddress("0x6666"),
+		MemberVersion: big.NewInt(1),
+		Status:        ProposalStatusVoting,
+		BlockNumber:   200,
+	}
+	require.NoError(t, storage.StoreProposal(ctx, proposal))
+	assert.Equal(t, []ProposalStatus{ProposalStatusVoting}, statusesOf())
+
+	// Re-indexing stores the proposal again with its later status
+	proposal.Status = ProposalStatusExecuted
+	require.NoError(t, storage.StoreProposal(ctx, proposal))
+	assert.Equal(t, []ProposalStatus{ProposalStatusExecuted}, statusesOf())
+
+	// Storing the same status again is a no-op for the index
+	require.NoError(t, storage.StoreProposal(ctx, proposal))
+	assert.Equal(t, []ProposalStatus{ProposalStatusExecuted}, statusesOf())
+
+	stored, err := storage.GetProposalById(ctx, contract, proposalID)
+	require.NoError(t, err)
+	assert.Equal(t, ProposalStatusExecuted, stored.Status)
+}
+
+func TestPebbleStorage_GetProposals_NumericOrder(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pebble_proposal_order_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	cfg := DefaultConfig(tempDir)
+	storage, err := NewPebbleStorage(cfg)
+	require.NoError(t, err)
+	defer storage.Close()
+
+	ctx := context.Background()
+	contract := common.HexToAddress("0x5555")
+
+	for _, id := range []int64{100, 2, 10} {
+		err := storage.StoreProposal(ctx, &Proposal{
+			Contract:      contract,
+			ProposalID:    big.NewInt(id),
+			Proposer:      common.HexToAddress("0x6666"),
+			MemberVersion: big.NewInt(1),
+			Status:        ProposalStatusVoting,
+		})
+		require.NoError(t, err)
+	}
+
+	proposals, err := storage.GetProposals(ctx, contract, ProposalStatusVoting, 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2", "10", "100"}, proposalIDs(proposals))
+
+	// Pagination follows the same order
+	proposals, err = storage.GetProposals(ctx, contract, ProposalStatusVoting, 1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10"}, proposalIDs(proposals))
+}
+
+func TestPebbleStorage_GetProposalTally(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pebble_proposal_tally_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	cfg := DefaultConfig(tempDir)
+	storage, err := NewPebbleStorage(cfg)
+	require.NoError(t, err)
+	defer storage.Close()
+
+	ctx := context.Background()
+	contract := common.HexToAddress("0x7777")
+	proposalID := big.NewInt(3)
+
+	err = storage.StoreProposal(ctx, &Proposal{
+		Contract:          contract,
+		ProposalID:        proposalID,
+		Proposer:          common.HexToAddress("0x8888"),
+		MemberVersion:     big.NewInt(1),
+		RequiredApprovals: 3,
+		Status:            ProposalStatusVoting,
+	})
+	require.NoError(t, err)
+
+	// No votes yet
+	tally, err := storage.GetProposalTally(ctx, contract, proposalID)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(0), tally.Voters)
+	assert.Equal(t, uint32(3), tally.RequiredApprovals)
+
+	votes := []struct {
+		voter    string
+		approval bool
+	}{
+		{"0x01", true},
+		{"0x02", true},
+		{"0x03", false},
+		{"0x04", true},
+		// 0x04 changes its vote, only the latest one counts
+		{"0x04", false},
+	}
+	for i, v := range votes {
+		err := storage.StoreProposalVote(ctx, &ProposalVote{
+			Contract:    contract,
+			ProposalID:  proposalID,
+			Voter:       common.HexToAddress(v.voter),
+			Approval:    v.approval,
+			BlockNumber: uint64(100 + i),
+		})
+		require.NoError(t, err)
+	}
+
+	tally, err = storage.GetProposalTally(ctx, contract, proposalID)
+	require.NoError(t, err)
+	assert.Equal(t, contract, tally.Contract)
+	assert.Equal(t, "3", tally.ProposalID.String())
+	assert.Equal(t, uint32(2), tally.Approvals)
+	assert.Equal(t, uint32(2), tally.Rejections)
+	assert.Equal(t, uint32(4), tally.Voters)
+	assert.Equal(t, uint32(3), tally.RequiredApprovals)
+
+	// Unknown proposal has a zero tally
+	tally, err = storage.GetProposalTally(ctx, contract, big.NewInt(99))
+	require.NoError(t, err)
+	assert.Equal(t, uint32(0), tally.Approvals)
+	assert.Equal(t, uint32(0), tally.Rejections)
+	assert.Equal(t, uint32(0), tally.Voters)
+	assert.Equal(t, uint32(0), tally.RequiredApprovals)
+}
+
+func TestPebbleStorage_GetProposals_LegacyIndex(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pebble_proposal_legacy_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	ctx := context.Background()
+	contract := common.HexToAddress("0x5555")
+
+	// Write proposals indexed by decimal ID as older versions did
+	storage, err := NewPebbleStorage(DefaultConfig(tempDir))
+	require.NoError(t, err)
+	for _, id := range []int64{100, 2, 10} {
+		proposal := &Proposal{
+			Contract:      contract,
+			ProposalID:    big.NewInt(id),
+			Proposer:      common.HexToAddress("0x6666"),
+			MemberVersion: big.NewInt(1),
+			Status:        ProposalStatusVoting,
+		}
+		data, err := EncodeProposal(proposal)
+		require.NoError(t, err)
+		require.NoError(t, storage.db.Set(ProposalKey(contract, proposal.ProposalID.String()), data, nil))
+		legacyKey := append(LegacyProposalStatusIndexKeyPrefix(contract, uint8(ProposalStatusVoting)), proposal.ProposalID.String()...)
+		require.NoError(t, storage.db.Set(legacyKey, []byte{1}, nil))
+	}
+	require.NoError(t, storage.Close())
+
+	// A read-only view orders the legacy index without migrating it
+	readCfg := DefaultConfig(tempDir)
+	readCfg.ReadOnly = true
+	reader, err := NewPebbleStorage(readCfg)
+	require.NoError(t, err)
+	proposals, err := reader.GetProposals(ctx, contract, ProposalStatusVoting, 2, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2", "10"}, proposalIDs(proposals))
+	require.NoError(t, reader.Close())
+
+	// Opening for writing migrates the index
+	storage, err = NewPebbleStorage(DefaultConfig(tempDir))
+	require.NoError(t, err)
+	defer storage.Close()
+
+	proposals, err = storage.GetProposals(ctx, contract, ProposalStatusVoting, 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2", "10", "100"}, proposalIDs(proposals))
+
+	legacy, err := storage.legacyProposalIDs(contract, uint8(ProposalStatusVoting))
+	require.NoError(t, err)
+	assert.Empty(t, legacy)
+
+	// Migrated proposals keep moving between statuses
+	require.NoError(t, storage.UpdateProposalStatus(ctx, contract, big.NewInt(10), ProposalStatusApproved, 0))
+	proposals, err = storage.GetProposals(ctx, contract, ProposalStatusVoting, 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2", "100"}, proposalIDs(proposals))
+}
+
+// proposalIDs returns the decimal IDs of proposals in order
+func proposalIDs(proposals []*Proposal) []string {
+	ids := make([]string, 0, len(proposals))
+	for _, p := range proposals {
+		ids = append(ids, p.ProposalID.String())
+	}
+	return ids
+}
+
+func TestProposalStatus_CanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from, to ProposalStatus
+		want     bool
+	}{
+		{ProposalStatusVoting, ProposalStatusApproved, true},
+		{ProposalStatusVoting, ProposalStatusRejected, true},
+		{ProposalStatusVoting, ProposalStatusExecuted, true},
+		{ProposalStatusApproved, ProposalStatusExecuted, true},
+		{ProposalStatusApproved, ProposalStatusFailed, true},
+		{ProposalStatusFailed, ProposalStatusExecuted, true},
+		{ProposalStatusExecuted, ProposalStatusExecuted, true},
+		{ProposalStatusApproved, ProposalStatusVoting, false},
+		{ProposalStatusApproved, ProposalStatusRejected, false},
+		{ProposalStatusExecuted, ProposalStatusVoting, false},
+		{ProposalStatusExecuted, ProposalStatusFailed, false},
+		{ProposalStatusRejected, ProposalStatusApproved, false},
+		{ProposalStatusCancelled, ProposalStatusExecuted, false},
+		{ProposalStatusExpired, ProposalStatusExecuted, false},
+		{ProposalStatusVoting, ProposalStatusAll, false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, tt.from.CanTransitionTo(tt.to), "%s -> %s", tt.from, tt.to)
+	}
+}
+
 func TestPebbleStorage_TotalSupply(t *testing.T) {
 	tempDir, err := os.MkdirTemp("", "pebble_supply_test")
 	require.NoError(t, err)
@@ -332,6 +653,64 @@ func TestPebbleStorage_BlacklistStatus(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestPebbleStorage_ResetSystemContractState(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pebble_reset_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	cfg := DefaultConfig(tempDir)
+	storage, err := NewPebbleStorage(cfg)
+	require.NoError(t, err)
+	defer storage.Close()
+
+	ctx := context.Background()
+	minter := common.HexToAddress("0x0000000000000000000000000000000000000a01")
+	validator := common.HexToAddress("0x0000000000000000000000000000000000000a02")
+	account := common.HexToAddress("0x0000000000000000000000000000000000000a03")
+
+	require.NoError(t, storage.UpdateTotalSupply(ctx, big.NewInt(1000)))
+	require.NoError(t, storage.UpdateActiveMinter(ctx, minter, big.NewInt(500), true))
+	require.NoError(t, storage.UpdateActiveValidator(ctx, validator, true))
+	require.NoError(t, storage.UpdateBlacklistStatus(ctx, account, true))
+	require.NoError(t, storage.StoreGasTipUpdateEvent(ctx, &GasTipUpdateEvent{
+		BlockNumber: 1,
+		OldTip:      big.NewInt(1),
+		NewTip:      big.NewInt(2),
+	}))
+	require.NoError(t, storage.StoreMintEvent(ctx, &MintEvent{
+		BlockNumber: 1,
+		Minter:      minter,
+		Amount:      big.NewInt(1000),
+	}))
+
+	require.NoError(t, storage.ResetSystemContractState(ctx))
+
+	supply, err := storage.GetTotalSupply(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "0", supply.String())
+
+	minters, err := storage.GetActiveMinters(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, minters)
+
+	validators, err := storage.GetActiveValidators(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, validators)
+
+	blacklisted, err := storage.GetBlacklistedAddresses(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, blacklisted)
+
+	// Mint and burn records are rebuilt by the replay, other event records are kept
+	mints, err := storage.GetMintEvents(ctx, 0, 10, common.Address{}, 0, 0)
+	require.NoError(t, err)
+	assert.Empty(t, mints)
+
+	tips, err := storage.GetGasTipHistory(ctx, 0, 10)
+	require.NoError(t, err)
+	assert.Len(t, tips, 1)
+}
+
 func TestPebbleStorage_GetMintEvents(t *testing.T) {
 	tempDir, err := os.MkdirTemp("", "pebble_get_mints_test")
 	require.NoError(t, err)
@@ -406,6 +785,190 @@ func TestPebbleStorage_GetBurnEvents(t *testing.T) {
 	}
 }
 
+func TestPebbleStorage_MintBurnEvents_BlockRange(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pebble_mint_burn_range_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	cfg := DefaultConfig(tempDir)
+	storage, err := NewPebbleStorage(cfg)
+	require.NoError(t, err)
+	defer storage.Close()
+
+	ctx := context.Background()
+
+	// Two events per block in blocks 10-14, from different actors
+	for block := uint64(10); block < 15; block++ {
+		for logIndex := uint64(0); logIndex < 2; logIndex++ {
+			actor := common.BigToAddress(new(big.Int).SetUint64(block*10 + logIndex))
+			require.NoError(t, storage.StoreMintEvent(ctx, &MintEvent{
+				BlockNumber: block,
+				Minter:      actor,
+				To:          actor,
+				Amount:      big.NewInt(1),
+				LogIndex:    logIndex,
+			}))
+			require.NoError(t, storage.StoreBurnEvent(ctx, &BurnEvent{
+				BlockNumber: block,
+				Burner:      actor,
+				Amount:      big.NewInt(1),
+				LogIndex:    logIndex,
+			}))
+		}
+	}
+
+	mintPositions := func(events []*MintEvent) [][2]uint64 {
+		positions := make([][2]uint64, 0, len(events))
+		for _, e := range events {
+			positions = append(positions, [2]uint64{e.BlockNumber, e.LogIndex})
+		}
+		return positions
+	}
+
+	t.Run("Range", func(t *testing.T) {
+		mints, err := storage.GetMintEvents(ctx, 11, 12, common.Address{}, 0, 0)
+		require.NoError(t, err)
+		assert.Equal(t, [][2]uint64{{11, 0}, {11, 1}, {12, 0}, {12, 1}}, mintPositions(mints))
+
+		burns, err := storage.GetBurnEvents(ctx, 14, 20, common.Address{}, 0, 0)
+		require.NoError(t, err)
+		require.Len(t, burns, 2)
+		assert.Equal(t, uint64(14), burns[0].BlockNumber)
+		assert.Equal(t, uint64(14), burns[1].BlockNumber)
+	})
+
+	t.Run("Pagination", func(t *testing.T) {
+		mints, err := storage.GetMintEvents(ctx, 10, 14, common.Address{}, 3, 2)
+		require.NoError(t, err)
+		assert.Equal(t, [][2]uint64{{11, 0}, {11, 1}, {12, 0}}, mintPositions(mints))
+
+		burns, err := storage.GetBurnEvents(ctx, 10, 14, common.Address{}, 5, 8)
+		require.NoError(t, err)
+		require.Len(t, burns, 2)
+		assert.Equal(t, uint64(14), burns[0].BlockNumber)
+		assert.Equal(t, uint64(1), burns[1].LogIndex)
+	})
+
+	t.Run("OpenEnded", func(t *testing.T) {
+		mints, err := storage.GetMintEvents(ctx, 13, math.MaxUint64, common.Address{}, 0, 0)
+		require.NoError(t, err)
+		assert.Len(t, mints, 4)
+	})
+
+	t.Run("EmptyRange", func(t *testing.T) {
+		mints, err := storage.GetMintEvents(ctx, 12, 11, common.Address{}, 0, 0)
+		require.NoError(t, err)
+		assert.Empty(t, mints)
+
+		burns, err := storage.GetBurnEvents(ctx, 20, 30, common.Address{}, 0, 0)
+		require.NoError(t, err)
+		assert.Empty(t, burns)
+	})
+}
+
+func TestPebbleStorage_MintBurnEvents_SameBlock(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pebble_same_block_mints_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	cfg := DefaultConfig(tempDir)
+	storage, err := NewPebbleStorage(cfg)
+	require.NoError(t, err)
+	defer storage.Close()
+
+	ctx := context.Background()
+	minter := common.HexToAddress("0x0000000000000000000000000000000000000b01")
+	burner := common.HexToAddress("0x0000000000000000000000000000000000000b02")
+
+	// Two mints and two burns in block 500; log 12 must sort after log 3
+	for _, logIndex := range []uint64{12, 3} {
+		require.NoError(t, storage.StoreMintEvent(ctx, &MintEvent{
+			BlockNumber: 500,
+			Minter:      minter,
+			To:          minter,
+			Amount:      big.NewInt(int64(logIndex)),
+			TxIndex:     1,
+			LogIndex:    logIndex,
+		}))
+		require.NoError(t, storage.StoreBurnEvent(ctx, &BurnEvent{
+			BlockNumber: 500,
+			Burner:      burner,
+			Amount:      big.NewInt(int64(logIndex)),
+			TxIndex:     1,
+			LogIndex:    logIndex + 1,
+		}))
+	}
+
+	mints, err := storage.GetMintEvents(ctx, 500, 500, common.Address{}, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, mints, 2)
+	assert.Equal(t, uint64(3), mints[0].LogIndex)
+	assert.Equal(t, "3", mints[0].Amount.String())
+	assert.Equal(t, uint64(12), mints[1].LogIndex)
+	assert.Equal(t, "12", mints[1].Amount.String())
+
+	burns, err := storage.GetBurnHistory(ctx, 500, 500, common.Address{})
+	require.NoError(t, err)
+	require.Len(t, burns, 2)
+	assert.Equal(t, uint64(4), burns[0].LogIndex)
+	assert.Equal(t, uint64(13), burns[1].LogIndex)
+
+	// The minter and burner indexes keep both events of the block
+	mints, err = storage.GetMintEvents(ctx, 500, 500, minter, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, mints, 2)
+	assert.Equal(t, uint64(3), mints[0].LogIndex)
+	assert.Equal(t, uint64(12), mints[1].LogIndex)
+
+	burns, err = storage.GetBurnEvents(ctx, 0, math.MaxUint64, burner, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, burns, 2)
+	assert.Equal(t, uint64(4), burns[0].LogIndex)
+	assert.Equal(t, uint64(13), burns[1].LogIndex)
+}
+
+func TestPebbleStorage_MintBurnEvents_Migration(t *testing.T) {
+	tempDir := t.TempDir()
+	storage, err := NewPebbleStorage(DefaultConfig(tempDir))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	minter := common.HexToAddress("0x0000000000000000000000000000000000000b01")
+	burner := common.HexToAddress("0x0000000000000000000000000000000000000b02")
+
+	// Records written before the migration use unpadded keys and no index
+	mintData, err := EncodeMintEvent(&MintEvent{BlockNumber: 7, Minter: minter, To: minter, Amount: big.NewInt(5)})
+	require.NoError(t, err)
+	legacyMintKey := []byte(fmt.Sprintf("%s%020d/0/0", prefixSysMint, 7))
+	require.NoError(t, storage.db.Set(legacyMintKey, mintData, nil))
+	burnData, err := EncodeBurnEvent(&BurnEvent{BlockNumber: 8, Burner: burner, Amount: big.NewInt(6)})
+	require.NoError(t, err)
+	legacyBurnKey := []byte(fmt.Sprintf("%s%020d/0/0", prefixSysBurn, 8))
+	require.NoError(t, storage.db.Set(legacyBurnKey, burnData, nil))
+	require.NoError(t, storage.db.Delete(MintBurnMigratedKey(), nil))
+	require.NoError(t, storage.Close())
+
+	// Opening for writing migrates the records
+	storage, err = NewPebbleStorage(DefaultConfig(tempDir))
+	require.NoError(t, err)
+	defer storage.Close()
+
+	for _, key := range [][]byte{legacyMintKey, legacyBurnKey} {
+		_, _, err := storage.db.Get(key)
+		assert.ErrorIs(t, err, pebble.ErrNotFound)
+	}
+
+	mints, err := storage.GetMintEvents(ctx, 0, 10, minter, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, mints, 1)
+	assert.Equal(t, "5", mints[0].Amount.String())
+
+	burns, err := storage.GetBurnEvents(ctx, 0, 10, burner, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, burns, 1)
+	assert.Equal(t, "6", burns[0].Amount.String())
+}
+
 func TestPebbleStorage_MaxProposalsUpdateEvent(t *testing.T) {
 	tempDir, err := os.MkdirTemp("", "pebble_maxproposals_test")
 	require.NoError(t, err)
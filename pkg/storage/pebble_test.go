@@ -2,21 +2,28 @@ package storage
 
 import (
 	"context"
+	"crypto/ecdsa"
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
 	"os"
+	"reflect"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/trie"
+	"github.com/holiman/uint256"
 	"go.uber.org/zap"
 )
 
 // setupTestStorage creates a temporary PebbleDB storage for testing
-func setupTestStorage(t *testing.T) (Storage, func()) {
+func setupTestStorage(t testing.TB) (Storage, func()) {
 	t.Helper()
 
 	tmpDir, err := os.MkdirTemp("", "pebble-test-*")
@@ -39,6 +46,16 @@ func setupTestStorage(t *testing.T) (Storage, func()) {
 	return storage, cleanup
 }
 
+// setupTestPebbleStorage is setupTestStorage for tests that use the
+// *PebbleStorage directly; the storage is closed when the test ends
+func setupTestPebbleStorage(t testing.TB) *PebbleStorage {
+	t.Helper()
+
+	storage, cleanup := setupTestStorage(t)
+	t.Cleanup(cleanup)
+	return storage.(*PebbleStorage)
+}
+
 // Test helper to create a test block
 func createTestBlock(height uint64) *types.Block {
 	header := &types.Header{
@@ -125,11 +142,46 @@ func TestConfig_Validate(t *testing.T) {
 			&Config{Path: "/tmp", MaxOpenFiles: -1},
 			true,
 		},
+		{
+			"negative bloom filter bits per key",
+			&Config{Path: "/tmp", CompactionConcurrency: 1, BloomFilterBitsPerKey: -1},
+			true,
+		},
+		{
+			"negative scan workers",
+			&Config{Path: "/tmp", CompactionConcurrency: 1, ScanWorkers: -1},
+			true,
+		},
 		{
 			"zero compaction concurrency",
 			&Config{Path: "/tmp", CompactionConcurrency: 0},
 			true,
 		},
+		{
+			"negative compaction interval",
+			&Config{Path: "/tmp", CompactionConcurrency: 1, CompactionInterval: -time.Hour},
+			true,
+		},
+		{
+			"invalid compaction start time",
+			&Config{Path: "/tmp", CompactionConcurrency: 1, CompactionStartTime: "25:00"},
+			true,
+		},
+		{
+			"replica without read-only",
+			&Config{Path: "/tmp", CompactionConcurrency: 1, ReplicaRefreshInterval: time.Second},
+			true,
+		},
+		{
+			"replica",
+			&Config{Path: "/tmp", CompactionConcurrency: 1, ReadOnly: true, ReplicaRefreshInterval: time.Second},
+			false,
+		},
+		{
+			"scheduled compaction",
+			&Config{Path: "/tmp", CompactionConcurrency: 1, CompactionInterval: 24 * time.Hour, CompactionStartTime: "03:00"},
+			false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -272,6 +324,131 @@ func TestPebbleStorage_GetBlockByHash(t *testing.T) {
 	}
 }
 
+func TestPebbleStorage_SetBlock_Atomic(t *testing.T) {
+	storage, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	first, second := createTestTransaction(0), createTestTransaction(1)
+
+	// The nil second transaction fails after the block and first transaction were added
+	broken := createTestBlock(100).WithBody(types.Body{Transactions: []*types.Transaction{first, nil}})
+	if err := storage.SetBlock(ctx, broken); err == nil {
+		t.Fatal("SetBlock() should fail for a nil transaction")
+	}
+
+	if exists, err := storage.HasBlock(ctx, 100); err != nil || exists {
+		t.Errorf("HasBlock() = %v, %v, want false after failed SetBlock", exists, err)
+	}
+	if _, err := storage.GetBlockByHash(ctx, broken.Hash()); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetBlockByHash() error = %v, want ErrNotFound", err)
+	}
+	if _, _, err := storage.GetTransaction(ctx, first.Hash()); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetTransaction() error = %v, want ErrNotFound", err)
+	}
+	if count := storage.(*PebbleStorage).txCount.Load(); count != 0 {
+		t.Errorf("transaction count = %d, want 0", count)
+	}
+
+	// A valid block is stored completely
+	block := createTestBlock(100).WithBody(types.Body{Transactions: []*types.Transaction{first, second}})
+	if err := storage.SetBlock(ctx, block); err != nil {
+		t.Fatalf("SetBlock() error = %v", err)
+	}
+	if _, err := storage.GetBlockByHash(ctx, block.Hash()); err != nil {
+		t.Errorf("GetBlockByHash() error = %v", err)
+	}
+	for i, tx := range block.Transactions() {
+		_, location, err := storage.GetTransaction(ctx, tx.Hash())
+		if err != nil {
+			t.Fatalf("GetTransaction(%d) error = %v", i, err)
+		}
+		if location.BlockHeight != 100 || location.TxIndex != uint64(i) {
+			t.Errorf("GetTransaction(%d) location = %+v, want block 100 index %d", i, location, i)
+		}
+	}
+	if count := storage.(*PebbleStorage).txCount.Load(); count != 2 {
+		t.Errorf("transaction count = %d, want 2", count)
+	}
+}
+
+func TestPebbleStorage_GetTransactionsByBlock(t *testing.T) {
+	storage, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	// More than 10 transactions, so key order differs from index order
+	block := createTestBlockWithTxs(t, 10, 12)
+	if err := storage.SetBlock(ctx, block); err != nil {
+		t.Fatalf("SetBlock() error = %v", err)
+	}
+	// Block 100 shares the "10" key prefix digits and must not leak into block 10
+	if err := storage.SetBlock(ctx, createTestBlock(100).WithBody(types.Body{
+		Transactions: []*types.Transaction{createTestTransaction(100), createTestTransaction(101)},
+	})); err != nil {
+		t.Fatalf("SetBlock(100) error = %v", err)
+	}
+
+	txs, locations, err := storage.GetTransactionsByBlock(ctx, 10)
+	if err != nil {
+		t.Fatalf("GetTransactionsByBlock() error = %v", err)
+	}
+	want := block.Transactions()
+	if len(txs) != len(want) || len(locations) != len(want) {
+		t.Fatalf("GetTransactionsByBlock() returned %d txs and %d locations, want %d", len(txs), len(locations), len(want))
+	}
+	for i := range want {
+		if txs[i].Hash() != want[i].Hash() {
+			t.Errorf("tx %d hash = %s, want %s", i, txs[i].Hash().Hex(), want[i].Hash().Hex())
+		}
+		if locations[i].BlockHeight != 10 || locations[i].TxIndex != uint64(i) || locations[i].BlockHash != block.Hash() {
+			t.Errorf("tx %d location = %+v, want block 10 index %d", i, locations[i], i)
+		}
+	}
+
+	t.Run("empty block", func(t *testing.T) {
+		if err := storage.SetBlock(ctx, createTestBlock(20)); err != nil {
+			t.Fatalf("SetBlock() error = %v", err)
+		}
+		txs, locations, err := storage.GetTransactionsByBlock(ctx, 20)
+		if err != nil {
+			t.Fatalf("GetTransactionsByBlock() error = %v", err)
+		}
+		if len(txs) != 0 || len(locations) != 0 {
+			t.Errorf("GetTransactionsByBlock() returned %d txs, want 0", len(txs))
+		}
+	})
+
+	t.Run("missing block", func(t *testing.T) {
+		if _, _, err := storage.GetTransactionsByBlock(ctx, 999); !errors.Is(err, ErrNotFound) {
+			t.Errorf("GetTransactionsByBlock() error = %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("replaced by a block with fewer transactions", func(t *testing.T) {
+		// A different header, as after a reorg, with 2 of the 12 transactions
+		replacement := createTestBlock(10).WithBody(types.Body{
+			Transactions: []*types.Transaction{createTestTransaction(200), createTestTransaction(201)},
+		})
+		if err := storage.SetBlock(ctx, replacement); err != nil {
+			t.Fatalf("SetBlock() error = %v", err)
+		}
+		txs, _, err := storage.GetTransactionsByBlock(ctx, 10)
+		if err != nil {
+			t.Fatalf("GetTransactionsByBlock() error = %v", err)
+		}
+		if len(txs) != 2 {
+			t.Fatalf("GetTransactionsByBlock() returned %d txs, want 2", len(txs))
+		}
+		for i, tx := range replacement.Transactions() {
+			if txs[i].Hash() != tx.Hash() {
+				t.Errorf("tx %d hash = %s, want %s", i, txs[i].Hash().Hex(), tx.Hash().Hex())
+			}
+		}
+	})
+}
+
 func TestPebbleStorage_Transaction(t *testing.T) {
 	storage, cleanup := setupTestStorage(t)
 	defer cleanup()
@@ -1076,6 +1253,151 @@ func TestPebbleStorage_GetReceiptsByBlockNumber_NotFound(t *testing.T) {
 	}
 }
 
+// setupReceiptRange stores blocks 1..5 with txsPerBlock transactions each and their
+// receipts, except block 3 which has no stored receipts and block 4 which is missing.
+// It returns the expected receipt tx hashes in (block, txIndex) order.
+func setupReceiptRange(t *testing.T, storage Storage, txsPerBlock int) []common.Hash {
+	t.Helper()
+	ctx := context.Background()
+
+	var expected []common.Hash
+	for height := uint64(1); height <= 5; height++ {
+		if height == 4 {
+			continue
+		}
+
+		txs := make([]*types.Transaction, txsPerBlock)
+		for i := range txs {
+			txs[i] = createTestTransaction(height*100 + uint64(i))
+		}
+		block := types.NewBlockWithHeader(&types.Header{
+			Number:   big.NewInt(int64(height)),
+			GasLimit: 5000000,
+			Time:     1234567890 + height,
+		}).WithBody(types.Body{Transactions: txs})
+		if err := storage.SetBlock(ctx, block); err != nil {
+			t.Fatalf("SetBlock(%d) error = %v", height, err)
+		}
+
+		if height == 3 {
+			continue
+		}
+		for i, tx := range txs {
+			if err := storage.SetReceipt(ctx, createTestReceipt(tx.Hash(), uint64(21000*(i+1)))); err != nil {
+				t.Fatalf("SetReceipt() error = %v", err)
+			}
+			expected = append(expected, tx.Hash())
+		}
+	}
+	return expected
+}
+
+// TestPebbleStorage_GetReceiptsByBlockRange tests ordering across a multi-block range
+func TestPebbleStorage_GetReceiptsByBlockRange(t *testing.T) {
+	storage, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	expected := setupReceiptRange(t, storage, 3)
+	reader := storage.(ReceiptRangeReader)
+
+	receipts, err := reader.GetReceiptsByBlockRange(ctx, 1, 5, 100, 0)
+	if err != nil {
+		t.Fatalf("GetReceiptsByBlockRange() error = %v", err)
+	}
+	if len(receipts) != len(expected) {
+		t.Fatalf("GetReceiptsByBlockRange() returned %d receipts, want %d", len(receipts), len(expected))
+	}
+	for i, receipt := range receipts {
+		if receipt.TxHash != expected[i] {
+			t.Errorf("receipt %d = %s, want %s", i, receipt.TxHash.Hex(), expected[i].Hex())
+		}
+	}
+
+	// Sub-range only covers block 2
+	receipts, err = reader.GetReceiptsByBlockRange(ctx, 2, 2, 100, 0)
+	if err != nil {
+		t.Fatalf("GetReceiptsByBlockRange() error = %v", err)
+	}
+	if len(receipts) != 3 || receipts[0].TxHash != expected[3] {
+		t.Errorf("GetReceiptsByBlockRange(2, 2) returned unexpected receipts")
+	}
+}
+
+// TestPebbleStorage_GetReceiptsByBlockRange_Pagination tests limit and offset across block boundaries
+func TestPebbleStorage_GetReceiptsByBlockRange_Pagination(t *testing.T) {
+	storage, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	expected := setupReceiptRange(t, storage, 3)
+	reader := storage.(ReceiptRangeReader)
+
+	// Walk the range in pages of 2 and reassemble it
+	var collected []common.Hash
+	for offset := 0; ; offset += 2 {
+		page, err := reader.GetReceiptsByBlockRange(ctx, 1, 5, 2, offset)
+		if err != nil {
+			t.Fatalf("GetReceiptsByBlockRange(offset=%d) error = %v", offset, err)
+		}
+		if len(page) > 2 {
+			t.Fatalf("page at offset %d has %d receipts, want <= 2", offset, len(page))
+		}
+		for _, receipt := range page {
+			collected = append(collected, receipt.TxHash)
+		}
+		if len(page) < 2 {
+			break
+		}
+	}
+
+	if len(collected) != len(expected) {
+		t.Fatalf("paginated walk returned %d receipts, want %d", len(collected), len(expected))
+	}
+	for i := range expected {
+		if collected[i] != expected[i] {
+			t.Errorf("receipt %d = %s, want %s", i, collected[i].Hex(), expected[i].Hex())
+		}
+	}
+
+	// Offset past the end returns an empty page
+	page, err := reader.GetReceiptsByBlockRange(ctx, 1, 5, 10, 100)
+	if err != nil {
+		t.Fatalf("GetReceiptsByBlockRange() error = %v", err)
+	}
+	if len(page) != 0 {
+		t.Errorf("expected empty page past the end, got %d receipts", len(page))
+	}
+}
+
+// TestPebbleStorage_GetReceiptsByBlockRange_InvalidRange tests range validation
+func TestPebbleStorage_GetReceiptsByBlockRange_InvalidRange(t *testing.T) {
+	storage, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	reader := storage.(ReceiptRangeReader)
+
+	if _, err := reader.GetReceiptsByBlockRange(ctx, 10, 5, 10, 0); err == nil {
+		t.Error("expected error when from > to")
+	}
+	if _, err := reader.GetReceiptsByBlockRange(ctx, 0, MaxReceiptBlockRange, 10, 0); err == nil {
+		t.Error("expected error when range spans MaxReceiptBlockRange+1 blocks")
+	}
+	if _, err := reader.GetReceiptsByBlockRange(ctx, 0, MaxReceiptBlockRange-1, 10, 0); err != nil {
+		t.Errorf("range spanning MaxReceiptBlockRange blocks error = %v", err)
+	}
+
+	// A range ending at the last possible height terminates
+	receipts, err := reader.GetReceiptsByBlockRange(ctx, math.MaxUint64-2, math.MaxUint64, 10, 0)
+	if err != nil {
+		t.Fatalf("range ending at math.MaxUint64 error = %v", err)
+	}
+	if len(receipts) != 0 {
+		t.Errorf("range ending at math.MaxUint64 returned %d receipts, want 0", len(receipts))
+	}
+}
+
 // TestPebbleStorage_GetReceiptsByBlockNumber_MissingReceipts tests with missing receipts
 func TestPebbleStorage_GetReceiptsByBlockNumber_MissingReceipts(t *testing.T) {
 	storage, cleanup := setupTestStorage(t)
@@ -1979,6 +2301,81 @@ func TestPebbleStorage_GetTransactionCount(t *testing.T) {
 	}
 }
 
+func TestPebbleStorage_TransactionCount_ConcurrentCommits(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pebble-txcount-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	storage, err := NewPebbleStorage(DefaultConfig(tmpDir))
+	if err != nil {
+		t.Fatalf("NewPebbleStorage() error = %v", err)
+	}
+
+	ctx := context.Background()
+	const batches = 64
+
+	var wg sync.WaitGroup
+	var expected atomic.Uint64
+	errs := make(chan error, batches)
+	for i := 0; i < batches; i++ {
+		wg.Add(1)
+		go func(height uint64) {
+			defer wg.Done()
+
+			// Batches of different sizes finish in varying order
+			txs := int(height%5) + 1
+			batch := storage.NewBatch()
+			defer batch.Close()
+			for j := 0; j < txs; j++ {
+				tx := createTestTransaction(height*10 + uint64(j))
+				location := &TxLocation{BlockHeight: height, TxIndex: uint64(j)}
+				if err := batch.SetTransaction(ctx, tx, location); err != nil {
+					errs <- err
+					return
+				}
+			}
+			if err := batch.Commit(); err != nil {
+				errs <- err
+				return
+			}
+			expected.Add(uint64(txs))
+		}(uint64(i + 1))
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("batch commit error = %v", err)
+	}
+
+	count, err := storage.GetTransactionCount(ctx)
+	if err != nil {
+		t.Fatalf("GetTransactionCount() error = %v", err)
+	}
+	if count != expected.Load() {
+		t.Errorf("GetTransactionCount() = %d, want %d", count, expected.Load())
+	}
+	if err := storage.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// The persisted count is what a restart loads
+	reopened, err := NewPebbleStorage(DefaultConfig(tmpDir))
+	if err != nil {
+		t.Fatalf("NewPebbleStorage() reopen error = %v", err)
+	}
+	defer reopened.Close()
+
+	persisted, err := reopened.GetTransactionCount(ctx)
+	if err != nil {
+		t.Fatalf("GetTransactionCount() after reopen error = %v", err)
+	}
+	if persisted != count {
+		t.Errorf("persisted transaction count = %d, want %d", persisted, count)
+	}
+}
+
 func TestPebbleStorage_SetBalance(t *testing.T) {
 	storage, cleanup := setupTestStorage(t)
 	defer cleanup()
@@ -2353,6 +2750,147 @@ func TestPebbleStorage_GetTransactionsByAddressFiltered(t *testing.T) {
 	})
 }
 
+// signedTypedTestTxs returns one signed transaction of each stored type
+// (legacy, access-list, dynamic-fee and blob) from key to to, in that order
+func signedTypedTestTxs(t *testing.T, key *ecdsa.PrivateKey, to common.Address) []*types.Transaction {
+	t.Helper()
+
+	chainID := big.NewInt(1337)
+	unsigned := []types.TxData{
+		&types.LegacyTx{Nonce: 0, GasPrice: big.NewInt(1000000000), Gas: 21000, To: &to, Value: big.NewInt(1)},
+		&types.AccessListTx{ChainID: chainID, Nonce: 1, GasPrice: big.NewInt(1000000000), Gas: 21000, To: &to, Value: big.NewInt(2)},
+		&types.DynamicFeeTx{ChainID: chainID, Nonce: 2, GasTipCap: big.NewInt(1000000000), GasFeeCap: big.NewInt(2000000000), Gas: 21000, To: &to, Value: big.NewInt(3)},
+		&types.BlobTx{
+			ChainID:    uint256.MustFromBig(chainID),
+			Nonce:      3,
+			GasTipCap:  uint256.NewInt(1000000000),
+			GasFeeCap:  uint256.NewInt(2000000000),
+			Gas:        21000,
+			To:         to,
+			Value:      uint256.NewInt(4),
+			BlobFeeCap: uint256.NewInt(3000000000),
+			BlobHashes: []common.Hash{common.HexToHash("0x0100000000000000000000000000000000000000000000000000000000000001")},
+		},
+	}
+
+	signer := types.LatestSignerForChainID(chainID)
+	txs := make([]*types.Transaction, len(unsigned))
+	for i, data := range unsigned {
+		tx, err := types.SignNewTx(key, signer, data)
+		if err != nil {
+			t.Fatalf("Failed to sign transaction: %v", err)
+		}
+		txs[i] = tx
+	}
+	return txs
+}
+
+func TestPebbleStorage_GetTransactionsByAddressFiltered_TransactionTypes(t *testing.T) {
+	storage, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	pebbleStorage := storage.(*PebbleStorage)
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	fromAddr := crypto.PubkeyToAddress(key.PublicKey)
+	toAddr := common.HexToAddress("0x2222222222222222222222222222222222222222")
+
+	txs := signedTypedTestTxs(t, key, toAddr)
+	header := &types.Header{
+		Number:     big.NewInt(1),
+		Time:       1000,
+		Difficulty: big.NewInt(1),
+		GasLimit:   1000000,
+	}
+	block := types.NewBlock(header, &types.Body{Transactions: txs}, nil, trie.NewStackTrie(nil))
+	if err := pebbleStorage.SetBlock(ctx, block); err != nil {
+		t.Fatalf("SetBlock() error = %v", err)
+	}
+	for _, tx := range txs {
+		for _, addr := range []common.Address{fromAddr, toAddr} {
+			if err := pebbleStorage.AddTransactionToAddressIndex(ctx, addr, tx.Hash()); err != nil {
+				t.Fatalf("AddTransactionToAddressIndex() error = %v", err)
+			}
+		}
+	}
+
+	query := func(t *testing.T, addr common.Address, direction TransactionType, txTypes ...uint8) []uint8 {
+		t.Helper()
+		filter := DefaultTransactionFilter()
+		filter.TxType = direction
+		filter.TransactionTypes = txTypes
+		results, err := pebbleStorage.GetTransactionsByAddressFiltered(ctx, addr, filter, 10, 0)
+		if err != nil {
+			t.Fatalf("GetTransactionsByAddressFiltered() error = %v", err)
+		}
+		got := make([]uint8, len(results))
+		for i, r := range results {
+			got[i] = r.Transaction.Type()
+		}
+		return got
+	}
+	allTypes := []uint8{types.LegacyTxType, types.AccessListTxType, types.DynamicFeeTxType, types.BlobTxType}
+
+	t.Run("direction matches every type", func(t *testing.T) {
+		for _, tc := range []struct {
+			name      string
+			addr      common.Address
+			direction TransactionType
+			want      []uint8
+		}{
+			{"sent by sender", fromAddr, TxTypeSent, allTypes},
+			{"received by recipient", toAddr, TxTypeReceived, allTypes},
+			{"all for sender", fromAddr, TxTypeAll, allTypes},
+			{"all for recipient", toAddr, TxTypeAll, allTypes},
+			{"received by sender", fromAddr, TxTypeReceived, []uint8{}},
+			{"sent by recipient", toAddr, TxTypeSent, []uint8{}},
+		} {
+			if got := query(t, tc.addr, tc.direction); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("%s: types = %v, want %v", tc.name, got, tc.want)
+			}
+		}
+	})
+
+	t.Run("single type", func(t *testing.T) {
+		for _, txType := range allTypes {
+			for _, direction := range []TransactionType{TxTypeSent, TxTypeAll} {
+				if got := query(t, fromAddr, direction, txType); !reflect.DeepEqual(got, []uint8{txType}) {
+					t.Errorf("type %d, direction %d: types = %v, want [%d]", txType, direction, got, txType)
+				}
+			}
+			if got := query(t, toAddr, TxTypeReceived, txType); !reflect.DeepEqual(got, []uint8{txType}) {
+				t.Errorf("type %d received: types = %v, want [%d]", txType, got, txType)
+			}
+		}
+	})
+
+	t.Run("several types", func(t *testing.T) {
+		got := query(t, fromAddr, TxTypeAll, types.BlobTxType, types.LegacyTxType)
+		want := []uint8{types.LegacyTxType, types.BlobTxType}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("types = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("type with no transactions", func(t *testing.T) {
+		if got := query(t, fromAddr, TxTypeAll, types.SetCodeTxType); len(got) != 0 {
+			t.Errorf("types = %v, want none", got)
+		}
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		filter := DefaultTransactionFilter()
+		filter.TransactionTypes = []uint8{0x7f}
+		if _, err := pebbleStorage.GetTransactionsByAddressFiltered(ctx, fromAddr, filter, 10, 0); err == nil {
+			t.Error("GetTransactionsByAddressFiltered() with an unknown type should fail")
+		}
+	})
+}
+
 func TestPebbleStorage_GetTransactionsByAddressFiltered_ClosedStorage(t *testing.T) {
 	storage, cleanup := setupTestStorage(t)
 	ctx := context.Background()
@@ -2608,57 +3146,171 @@ func TestPebbleStorage_GetTransaction_ClosedStorage(t *testing.T) {
 	// Close storage
 	cleanup()
 
-	_, _, err := pebbleStorage.GetTransaction(ctx, common.Hash{})
+	_, _, err := pebbleStorage.GetTransaction(ctx, common.Hash{})
+	if err == nil {
+		t.Error("GetTransaction() should fail on closed storage")
+	}
+}
+
+func TestPebbleStorage_SetBlockTimestamp(t *testing.T) {
+	storage, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	pebbleStorage := storage.(*PebbleStorage)
+
+	t.Run("set timestamp", func(t *testing.T) {
+		err := pebbleStorage.SetBlockTimestamp(ctx, 1000, 1)
+		if err != nil {
+			t.Fatalf("SetBlockTimestamp() error = %v", err)
+		}
+
+		err = pebbleStorage.SetBlockTimestamp(ctx, 2000, 2)
+		if err != nil {
+			t.Fatalf("SetBlockTimestamp() error = %v", err)
+		}
+	})
+}
+
+func TestPebbleStorage_SetBlockTimestamp_ClosedStorage(t *testing.T) {
+	storage, cleanup := setupTestStorage(t)
+	ctx := context.Background()
+	pebbleStorage := storage.(*PebbleStorage)
+
+	// Close storage
+	cleanup()
+
+	err := pebbleStorage.SetBlockTimestamp(ctx, 1000, 1)
+	if err == nil {
+		t.Error("SetBlockTimestamp() should fail on closed storage")
+	}
+}
+
+func TestPebbleStorage_SetBlockTimestamp_ReplacesStaleEntry(t *testing.T) {
+	storage, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	pebbleStorage := storage.(*PebbleStorage)
+
+	for _, height := range []uint64{1, 2} {
+		if err := pebbleStorage.SetBlock(ctx, createTestBlock(height)); err != nil {
+			t.Fatalf("SetBlock(%d) error = %v", height, err)
+		}
+	}
+	if err := pebbleStorage.SetBlockTimestamp(ctx, 1000, 1); err != nil {
+		t.Fatalf("SetBlockTimestamp() error = %v", err)
+	}
+	if err := pebbleStorage.SetBlockTimestamp(ctx, 2000, 2); err != nil {
+		t.Fatalf("SetBlockTimestamp() error = %v", err)
+	}
+
+	// Re-index height 2 with the same timestamp (no-op) and then with a new one (reorg)
+	if err := pebbleStorage.SetBlockTimestamp(ctx, 2000, 2); err != nil {
+		t.Fatalf("SetBlockTimestamp() error = %v", err)
+	}
+	if err := pebbleStorage.SetBlockTimestamp(ctx, 3000, 2); err != nil {
+		t.Fatalf("SetBlockTimestamp() error = %v", err)
+	}
+
+	// The stale mapping must no longer be queryable
+	stale, err := pebbleStorage.GetBlocksByTimeRange(ctx, 1500, 2500, 10, 0, SortAscending)
+	if err != nil {
+		t.Fatalf("GetBlocksByTimeRange() error = %v", err)
+	}
+	if len(stale) != 0 {
+		t.Errorf("GetBlocksByTimeRange(stale) returned %d blocks, want 0", len(stale))
+	}
+
+	// Only the new mapping is returned
+	current, err := pebbleStorage.GetBlocksByTimeRange(ctx, 2500, 3500, 10, 0, SortAscending)
+	if err != nil {
+		t.Fatalf("GetBlocksByTimeRange() error = %v", err)
+	}
+	if len(current) != 1 || current[0].NumberU64() != 2 {
+		t.Fatalf("GetBlocksByTimeRange(new) returned %d blocks, want block 2", len(current))
+	}
+
+	// No duplicate entries across the whole range
+	all, err := pebbleStorage.GetBlocksByTimeRange(ctx, 0, 10000, 10, 0, SortAscending)
+	if err != nil {
+		t.Fatalf("GetBlocksByTimeRange() error = %v", err)
+	}
+	if len(all) != 2 {
+		t.Errorf("GetBlocksByTimeRange(all) returned %d blocks, want 2", len(all))
+	}
+}
+
+func TestPebbleStorage_SetLatestHeight_ClosedStorage(t *testing.T) {
+	storage, cleanup := setupTestStorage(t)
+	ctx := context.Background()
+	pebbleStorage := storage.(*PebbleStorage)
+
+	// Close storage
+	cleanup()
+
+	err := pebbleStorage.SetLatestHeight(ctx, 100)
 	if err == nil {
-		t.Error("GetTransaction() should fail on closed storage")
+		t.Error("SetLatestHeight() should fail on closed storage")
 	}
 }
 
-func TestPebbleStorage_SetBlockTimestamp(t *testing.T) {
+func TestPebbleStorage_ChainID(t *testing.T) {
 	storage, cleanup := setupTestStorage(t)
 	defer cleanup()
 
 	ctx := context.Background()
 	pebbleStorage := storage.(*PebbleStorage)
 
-	t.Run("set timestamp", func(t *testing.T) {
-		err := pebbleStorage.SetBlockTimestamp(ctx, 1000, 1)
-		if err != nil {
-			t.Fatalf("SetBlockTimestamp() error = %v", err)
-		}
+	if _, err := pebbleStorage.GetChainID(ctx); err != ErrNotFound {
+		t.Fatalf("GetChainID() error = %v, want ErrNotFound", err)
+	}
 
-		err = pebbleStorage.SetBlockTimestamp(ctx, 2000, 2)
+	for _, id := range []int64{1, 8283} {
+		if err := pebbleStorage.SetChainID(ctx, big.NewInt(id)); err != nil {
+			t.Fatalf("SetChainID(%d) error = %v", id, err)
+		}
+		chainID, err := pebbleStorage.GetChainID(ctx)
 		if err != nil {
-			t.Fatalf("SetBlockTimestamp() error = %v", err)
+			t.Fatalf("GetChainID() error = %v", err)
 		}
-	})
-}
-
-func TestPebbleStorage_SetBlockTimestamp_ClosedStorage(t *testing.T) {
-	storage, cleanup := setupTestStorage(t)
-	ctx := context.Background()
-	pebbleStorage := storage.(*PebbleStorage)
-
-	// Close storage
-	cleanup()
+		if chainID.Int64() != id {
+			t.Errorf("GetChainID() = %s, want %d", chainID, id)
+		}
+	}
 
-	err := pebbleStorage.SetBlockTimestamp(ctx, 1000, 1)
-	if err == nil {
-		t.Error("SetBlockTimestamp() should fail on closed storage")
+	if err := pebbleStorage.SetChainID(ctx, nil); err == nil {
+		t.Error("SetChainID(nil) should fail")
 	}
 }
 
-func TestPebbleStorage_SetLatestHeight_ClosedStorage(t *testing.T) {
+func TestPebbleStorage_ChainHead(t *testing.T) {
 	storage, cleanup := setupTestStorage(t)
+	defer cleanup()
+
 	ctx := context.Background()
 	pebbleStorage := storage.(*PebbleStorage)
 
-	// Close storage
-	cleanup()
+	if _, err := pebbleStorage.GetChainHead(ctx); err != ErrNotFound {
+		t.Fatalf("GetChainHead() error = %v, want ErrNotFound", err)
+	}
 
-	err := pebbleStorage.SetLatestHeight(ctx, 100)
-	if err == nil {
-		t.Error("SetLatestHeight() should fail on closed storage")
+	for _, height := range []uint64{100, 105} {
+		if err := pebbleStorage.SetChainHead(ctx, height); err != nil {
+			t.Fatalf("SetChainHead(%d) error = %v", height, err)
+		}
+		head, err := pebbleStorage.GetChainHead(ctx)
+		if err != nil {
+			t.Fatalf("GetChainHead() error = %v", err)
+		}
+		if head != height {
+			t.Errorf("GetChainHead() = %d, want %d", head, height)
+		}
+	}
+
+	// The chain head is independent of the indexed height
+	if _, err := pebbleStorage.GetLatestHeight(ctx); err != ErrNotFound {
+		t.Errorf("GetLatestHeight() error = %v, want ErrNotFound", err)
 	}
 }
 
@@ -2891,7 +3543,7 @@ func TestPebbleStorage_GetBlocksByTimeRange_ClosedStorage(t *testing.T) {
 	// Close storage
 	cleanup()
 
-	_, err := pebbleStorage.GetBlocksByTimeRange(ctx, 0, 1000, 10, 0)
+	_, err := pebbleStorage.GetBlocksByTimeRange(ctx, 0, 1000, 10, 0, SortAscending)
 	if err == nil {
 		t.Error("GetBlocksByTimeRange() should fail on closed storage")
 	}
@@ -3316,7 +3968,7 @@ func TestPebbleStorage_GetBlocksByTimeRange_Extended(t *testing.T) {
 	}
 
 	t.Run("get blocks in time range", func(t *testing.T) {
-		blocks, err := pebbleStorage.GetBlocksByTimeRange(ctx, 2000, 5000, 10, 0)
+		blocks, err := pebbleStorage.GetBlocksByTimeRange(ctx, 2000, 5000, 10, 0, SortAscending)
 		if err != nil {
 			t.Fatalf("GetBlocksByTimeRange() error = %v", err)
 		}
@@ -3326,7 +3978,7 @@ func TestPebbleStorage_GetBlocksByTimeRange_Extended(t *testing.T) {
 	})
 
 	t.Run("get with limit", func(t *testing.T) {
-		blocks, err := pebbleStorage.GetBlocksByTimeRange(ctx, 1000, 10000, 3, 0)
+		blocks, err := pebbleStorage.GetBlocksByTimeRange(ctx, 1000, 10000, 3, 0, SortAscending)
 		if err != nil {
 			t.Fatalf("GetBlocksByTimeRange() error = %v", err)
 		}
@@ -3336,7 +3988,7 @@ func TestPebbleStorage_GetBlocksByTimeRange_Extended(t *testing.T) {
 	})
 
 	t.Run("get with offset", func(t *testing.T) {
-		blocks, err := pebbleStorage.GetBlocksByTimeRange(ctx, 1000, 10000, 10, 5)
+		blocks, err := pebbleStorage.GetBlocksByTimeRange(ctx, 1000, 10000, 10, 5, SortAscending)
 		if err != nil {
 			t.Fatalf("GetBlocksByTimeRange() error = %v", err)
 		}
@@ -3346,7 +3998,7 @@ func TestPebbleStorage_GetBlocksByTimeRange_Extended(t *testing.T) {
 	})
 
 	t.Run("empty range", func(t *testing.T) {
-		blocks, err := pebbleStorage.GetBlocksByTimeRange(ctx, 100000, 200000, 10, 0)
+		blocks, err := pebbleStorage.GetBlocksByTimeRange(ctx, 100000, 200000, 10, 0, SortAscending)
 		if err != nil {
 			t.Fatalf("GetBlocksByTimeRange() error = %v", err)
 		}
@@ -3480,7 +4132,7 @@ func TestPebbleStorage_GetBlocksByTimeRange_InvalidRange(t *testing.T) {
 	pebbleStorage := storage.(*PebbleStorage)
 
 	// Test with fromTime > toTime
-	_, err := pebbleStorage.GetBlocksByTimeRange(ctx, 10000, 1000, 10, 0)
+	_, err := pebbleStorage.GetBlocksByTimeRange(ctx, 10000, 1000, 10, 0, SortAscending)
 	if err == nil {
 		t.Error("GetBlocksByTimeRange() should fail when fromTime > toTime")
 	}
@@ -4534,6 +5186,27 @@ func TestPebbleStorage_Sync(t *testing.T) {
 	_ = ctx // ctx is used in Put
 }
 
+func TestPebbleStorage_Sync_ReadOnly(t *testing.T) {
+	cfg := DefaultConfig(t.TempDir())
+	storage, err := NewPebbleStorage(cfg)
+	if err != nil {
+		t.Fatalf("NewPebbleStorage() error = %v", err)
+	}
+	storage.Close()
+
+	cfg.ReadOnly = true
+	roStorage, err := NewPebbleStorage(cfg)
+	if err != nil {
+		t.Fatalf("NewPebbleStorage() read-only error = %v", err)
+	}
+	defer roStorage.Close()
+
+	// Nothing to flush, so syncing a read-only store succeeds
+	if err := roStorage.Sync(); err != nil {
+		t.Errorf("Sync() on read-only storage error = %v", err)
+	}
+}
+
 func TestPebbleStorage_Sync_ClosedStorage(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("", "pebble-test-*")
 	if err != nil {
@@ -4954,6 +5627,56 @@ func TestPebbleStorage_GetActiveMinters(t *testing.T) {
 			t.Errorf("GetActiveMinters() = %d minters, want 2", len(minters))
 		}
 	})
+
+	t.Run("allowances sorted by address bytes", func(t *testing.T) {
+		// Checksummed keys sort 0xCc.. before 0xaA.., so key order differs from byte order
+		minterA := common.HexToAddress("0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")
+		minterB := common.HexToAddress("0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb")
+		minterC := common.HexToAddress("0xcccccccccccccccccccccccccccccccccccccccc")
+		removed := common.HexToAddress("0xdddddddddddddddddddddddddddddddddddddddd")
+
+		allowances := map[common.Address]*big.Int{
+			minterC: big.NewInt(300),
+			minterA: big.NewInt(100),
+			minterB: new(big.Int).Exp(big.NewInt(10), big.NewInt(30), nil),
+			removed: big.NewInt(400),
+		}
+		for minter, allowance := range allowances {
+			if err := pebbleStorage.UpdateActiveMinter(ctx, minter, allowance, true); err != nil {
+				t.Fatalf("UpdateActiveMinter() error = %v", err)
+			}
+		}
+		if err := pebbleStorage.UpdateActiveMinter(ctx, removed, nil, false); err != nil {
+			t.Fatalf("UpdateActiveMinter() remove error = %v", err)
+		}
+
+		minters, err := pebbleStorage.GetActiveMinters(ctx)
+		if err != nil {
+			t.Fatalf("GetActiveMinters() error = %v", err)
+		}
+
+		want := []struct {
+			addr      common.Address
+			allowance *big.Int
+		}{
+			{common.HexToAddress("0x1111111111111111111111111111111111111111"), big.NewInt(1000000)},
+			{common.HexToAddress("0x2222222222222222222222222222222222222222"), big.NewInt(2000000)},
+			{minterA, allowances[minterA]},
+			{minterB, allowances[minterB]},
+			{minterC, allowances[minterC]},
+		}
+		if len(minters) != len(want) {
+			t.Fatalf("GetActiveMinters() = %d minters, want %d", len(minters), len(want))
+		}
+		for i, w := range want {
+			if minters[i].Address != w.addr {
+				t.Errorf("minters[%d].Address = %s, want %s", i, minters[i].Address.Hex(), w.addr.Hex())
+			}
+			if minters[i].Allowance.Cmp(w.allowance) != 0 {
+				t.Errorf("minters[%d].Allowance = %s, want %s", i, minters[i].Allowance, w.allowance)
+			}
+		}
+	})
 }
 
 func TestPebbleStorage_GetActiveMinters_ClosedStorage(t *testing.T) {
@@ -5120,6 +5843,37 @@ func TestPebbleStorage_GetActiveValidators(t *testing.T) {
 			t.Errorf("GetActiveValidators() = %d validators, want 2", len(validators))
 		}
 	})
+
+	t.Run("sorted by address bytes", func(t *testing.T) {
+		for _, hex := range []string{
+			"0xcccccccccccccccccccccccccccccccccccccccc",
+			"0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+		} {
+			if err := pebbleStorage.UpdateActiveValidator(ctx, common.HexToAddress(hex), true); err != nil {
+				t.Fatalf("UpdateActiveValidator() error = %v", err)
+			}
+		}
+
+		validators, err := pebbleStorage.GetActiveValidators(ctx)
+		if err != nil {
+			t.Fatalf("GetActiveValidators() error = %v", err)
+		}
+
+		want := []common.Address{
+			common.HexToAddress("0x5555555555555555555555555555555555555555"),
+			common.HexToAddress("0x6666666666666666666666666666666666666666"),
+			common.HexToAddress("0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"),
+			common.HexToAddress("0xcccccccccccccccccccccccccccccccccccccccc"),
+		}
+		if len(validators) != len(want) {
+			t.Fatalf("GetActiveValidators() = %d validators, want %d", len(validators), len(want))
+		}
+		for i := range want {
+			if validators[i] != want[i] {
+				t.Errorf("validators[%d] = %s, want %s", i, validators[i].Hex(), want[i].Hex())
+			}
+		}
+	})
 }
 
 func TestPebbleStorage_GetActiveValidators_ClosedStorage(t *testing.T) {
@@ -5160,6 +5914,37 @@ func TestPebbleStorage_GetGasTipHistory(t *testing.T) {
 			t.Errorf("GetGasTipHistory() = %d events, want 0", len(history))
 		}
 	})
+
+	t.Run("stored events in range", func(t *testing.T) {
+		updater := common.HexToAddress("0x5555555555555555555555555555555555555555")
+		for i, block := range []uint64{100, 200, 300} {
+			event := &GasTipUpdateEvent{
+				BlockNumber: block,
+				TxHash:      common.BigToHash(big.NewInt(int64(block))),
+				OldTip:      big.NewInt(int64(i)),
+				NewTip:      big.NewInt(int64(i + 1)),
+				Updater:     updater,
+				Timestamp:   1234567890 + block,
+			}
+			if err := pebbleStorage.StoreGasTipUpdateEvent(ctx, event); err != nil {
+				t.Fatalf("StoreGasTipUpdateEvent() error = %v", err)
+			}
+		}
+
+		history, err := pebbleStorage.GetGasTipHistory(ctx, 150, 300)
+		if err != nil {
+			t.Fatalf("GetGasTipHistory() error = %v", err)
+		}
+		if len(history) != 2 {
+			t.Fatalf("GetGasTipHistory() = %d events, want 2", len(history))
+		}
+		if history[0].BlockNumber != 200 || history[0].NewTip.Cmp(big.NewInt(2)) != 0 || history[0].Updater != updater {
+			t.Errorf("GetGasTipHistory()[0] = %+v, want block 200 tip 2 from %s", history[0], updater.Hex())
+		}
+		if history[1].BlockNumber != 300 {
+			t.Errorf("GetGasTipHistory()[1].BlockNumber = %d, want 300", history[1].BlockNumber)
+		}
+	})
 }
 
 func TestPebbleStorage_GetGasTipHistory_ClosedStorage(t *testing.T) {
@@ -5932,6 +6717,143 @@ func TestPebbleStorage_InitializeTransactionCount_ClosedStorage(t *testing.T) {
 	}
 }
 
+// indexBlockWithTxs stores a block at height carrying txCount signed transactions
+func indexBlockWithTxs(t *testing.T, storage *PebbleStorage, height uint64, txCount int) {
+	t.Helper()
+	ctx := context.Background()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	to := common.HexToAddress("0x1234567890123456789012345678901234567890")
+	txs := make([]*types.Transaction, txCount)
+	for i := range txs {
+		txs[i], err = createSignedTransaction(uint64(i), to, big.NewInt(1), big.NewInt(1000000000), key)
+		if err != nil {
+			t.Fatalf("Failed to create transaction: %v", err)
+		}
+	}
+
+	header := &types.Header{
+		Number:     new(big.Int).SetUint64(height),
+		Time:       1000 + height,
+		Difficulty: big.NewInt(1),
+		GasLimit:   1000000,
+	}
+	block := types.NewBlock(header, &types.Body{Transactions: txs}, nil, trie.NewStackTrie(nil))
+	if err := storage.SetBlock(ctx, block); err != nil {
+		t.Fatalf("SetBlock() error = %v", err)
+	}
+	if err := storage.SetLatestHeight(ctx, height); err != nil {
+		t.Fatalf("SetLatestHeight() error = %v", err)
+	}
+}
+
+func TestPebbleStorage_MissingTransactionCountOnOpen(t *testing.T) {
+	ctx := context.Background()
+
+	// newLegacyDB creates a database with 5 indexed transactions but no
+	// stored transaction count, like one written before the count existed
+	newLegacyDB := func(t *testing.T) string {
+		t.Helper()
+		dir := t.TempDir()
+		storage, err := NewPebbleStorage(DefaultConfig(dir))
+		if err != nil {
+			t.Fatalf("Failed to create storage: %v", err)
+		}
+		indexBlockWithTxs(t, storage, 0, 3)
+		indexBlockWithTxs(t, storage, 1, 2)
+		if err := storage.db.Delete(TransactionCountKey(), nil); err != nil {
+			t.Fatalf("Failed to delete transaction count: %v", err)
+		}
+		if err := storage.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+		return dir
+	}
+	open := func(t *testing.T, cfg *Config) *PebbleStorage {
+		t.Helper()
+		storage, err := NewPebbleStorage(cfg)
+		if err != nil {
+			t.Fatalf("Failed to open storage: %v", err)
+		}
+		return storage
+	}
+	assertCount := func(t *testing.T, storage *PebbleStorage, want uint64, wantMissing bool) {
+		t.Helper()
+		count, err := storage.GetTransactionCount(ctx)
+		if err != nil {
+			t.Fatalf("GetTransactionCount() error = %v", err)
+		}
+		if count != want {
+			t.Errorf("GetTransactionCount() = %d, want %d", count, want)
+		}
+		if got := storage.TransactionCountMissing(); got != wantMissing {
+			t.Errorf("TransactionCountMissing() = %v, want %v", got, wantMissing)
+		}
+	}
+
+	t.Run("fresh database", func(t *testing.T) {
+		storage := open(t, DefaultConfig(t.TempDir()))
+		defer storage.Close()
+		assertCount(t, storage, 0, false)
+	})
+
+	t.Run("initialized on open", func(t *testing.T) {
+		dir := newLegacyDB(t)
+		cfg := DefaultConfig(dir)
+		cfg.InitTransactionCount = true
+		storage := open(t, cfg)
+		assertCount(t, storage, 5, false)
+
+		indexBlockWithTxs(t, storage, 2, 1)
+		assertCount(t, storage, 6, false)
+		storage.Close()
+
+		// The corrected count is persisted
+		storage = open(t, DefaultConfig(dir))
+		defer storage.Close()
+		assertCount(t, storage, 6, false)
+	})
+
+	t.Run("detected without initialization", func(t *testing.T) {
+		dir := newLegacyDB(t)
+		storage := open(t, DefaultConfig(dir))
+		assertCount(t, storage, 0, true)
+
+		// New transactions are counted in memory, but the undercount is not
+		// persisted, so the next open still detects the missing count
+		indexBlockWithTxs(t, storage, 2, 1)
+		assertCount(t, storage, 1, true)
+		storage.Close()
+
+		cfg := DefaultConfig(dir)
+		cfg.InitTransactionCount = true
+		storage = open(t, cfg)
+		defer storage.Close()
+		assertCount(t, storage, 6, false)
+	})
+
+	t.Run("manual initialization", func(t *testing.T) {
+		storage := open(t, DefaultConfig(newLegacyDB(t)))
+		defer storage.Close()
+		if err := storage.InitializeTransactionCount(ctx); err != nil {
+			t.Fatalf("InitializeTransactionCount() error = %v", err)
+		}
+		assertCount(t, storage, 5, false)
+	})
+
+	t.Run("read-only skips initialization", func(t *testing.T) {
+		cfg := DefaultConfig(newLegacyDB(t))
+		cfg.ReadOnly = true
+		cfg.InitTransactionCount = true
+		storage := open(t, cfg)
+		defer storage.Close()
+		assertCount(t, storage, 0, true)
+	})
+}
+
 func TestPebbleStorage_InitializeTransactionCount_ReadOnly(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("", "pebble-inittxcount-readonly-test-*")
 	if err != nil {
@@ -6158,3 +7080,231 @@ func TestPebbleStorage_CountByPrefix_ClosedStorage(t *testing.T) {
 		t.Errorf("CountByPrefix() on closed = %v, want ErrClosed", err)
 	}
 }
+
+func TestPebbleStorage_AddTransactionsToAddressIndex(t *testing.T) {
+	addr1 := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	addr2 := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	entries := []AddressTransactionEntry{
+		{Address: addr1, TxHash: common.HexToHash("0x01")},
+		{Address: addr2, TxHash: common.HexToHash("0x01")},
+		{Address: addr1, TxHash: common.HexToHash("0x02")},
+		{Address: addr1, TxHash: common.HexToHash("0x03")},
+		{Address: addr2, TxHash: common.HexToHash("0x04")},
+	}
+
+	ctx := context.Background()
+
+	// Existing entries must keep their positions ahead of bulk-added ones
+	seed := func(t *testing.T, storage Storage) {
+		if err := storage.AddTransactionToAddressIndex(ctx, addr1, common.HexToHash("0xff")); err != nil {
+			t.Fatalf("AddTransactionToAddressIndex() error = %v", err)
+		}
+	}
+
+	perEntry, cleanupPerEntry := setupTestStorage(t)
+	defer cleanupPerEntry()
+	seed(t, perEntry)
+	for _, entry := range entries {
+		if err := perEntry.AddTransactionToAddressIndex(ctx, entry.Address, entry.TxHash); err != nil {
+			t.Fatalf("AddTransactionToAddressIndex() error = %v", err)
+		}
+	}
+
+	direct, cleanupDirect := setupTestStorage(t)
+	defer cleanupDirect()
+	seed(t, direct)
+	if err := direct.(AddressIndexBatchWriter).AddTransactionsToAddressIndex(ctx, entries); err != nil {
+		t.Fatalf("AddTransactionsToAddressIndex() error = %v", err)
+	}
+
+	batched, cleanupBatched := setupTestStorage(t)
+	defer cleanupBatched()
+	seed(t, batched)
+	batch := batched.NewBatch()
+	if err := batch.(AddressIndexBatchWriter).AddTransactionsToAddressIndex(ctx, entries); err != nil {
+		t.Fatalf("batch AddTransactionsToAddressIndex() error = %v", err)
+	}
+	if batch.Count() != len(entries) {
+		t.Errorf("batch Count() = %d, want %d", batch.Count(), len(entries))
+	}
+	if err := batch.Commit(); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	batch.Close()
+
+	for _, addr := range []common.Address{addr1, addr2} {
+		want, err := perEntry.GetTransactionsByAddress(ctx, addr, 100, 0)
+		if err != nil {
+			t.Fatalf("GetTransactionsByAddress() error = %v", err)
+		}
+		for name, storage := range map[string]Storage{"direct": direct, "batch": batched} {
+			got, err := storage.GetTransactionsByAddress(ctx, addr, 100, 0)
+			if err != nil {
+				t.Fatalf("%s GetTransactionsByAddress() error = %v", name, err)
+			}
+			if len(got) != len(want) {
+				t.Fatalf("%s index for %s has %d entries, want %d", name, addr.Hex(), len(got), len(want))
+			}
+			for i := range want {
+				if got[i] != want[i] {
+					t.Errorf("%s index for %s entry %d = %s, want %s", name, addr.Hex(), i, got[i].Hex(), want[i].Hex())
+				}
+			}
+		}
+	}
+
+	// Sequence counters continue after the bulk write
+	if err := batched.AddTransactionToAddressIndex(ctx, addr2, common.HexToHash("0x05")); err != nil {
+		t.Fatalf("AddTransactionToAddressIndex() error = %v", err)
+	}
+	hashes, err := batched.GetTransactionsByAddress(ctx, addr2, 100, 0)
+	if err != nil {
+		t.Fatalf("GetTransactionsByAddress() error = %v", err)
+	}
+	if len(hashes) != 3 || hashes[2] != common.HexToHash("0x05") {
+		t.Errorf("addr2 index = %v, want 3 entries ending with 0x05", hashes)
+	}
+}
+
+// TestPebbleStorage_AddressIndex_SurvivesReopen indexes transactions, reopens the
+// database and indexes more through each write path, checking that sequence
+// counters resume after the stored entries instead of overwriting them
+func TestPebbleStorage_AddressIndex_SurvivesReopen(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	addr1 := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	addr2 := common.HexToAddress("0x2222222222222222222222222222222222222222")
+
+	storage, err := NewPebbleStorage(DefaultConfig(dir))
+	if err != nil {
+		t.Fatalf("NewPebbleStorage() error = %v", err)
+	}
+	for i := 1; i <= 3; i++ {
+		if err := storage.AddTransactionToAddressIndex(ctx, addr1, common.BigToHash(big.NewInt(int64(i)))); err != nil {
+			t.Fatalf("AddTransactionToAddressIndex() error = %v", err)
+		}
+	}
+	if err := storage.AddTransactionToAddressIndex(ctx, addr2, common.HexToHash("0x21")); err != nil {
+		t.Fatalf("AddTransactionToAddressIndex() error = %v", err)
+	}
+	storage.Close()
+
+	reopen := func() *PebbleStorage {
+		storage, err := NewPebbleStorage(DefaultConfig(dir))
+		if err != nil {
+			t.Fatalf("reopen error = %v", err)
+		}
+		return storage
+	}
+
+	// Direct write after a restart
+	storage = reopen()
+	if err := storage.AddTransactionToAddressIndex(ctx, addr1, common.HexToHash("0x04")); err != nil {
+		t.Fatalf("AddTransactionToAddressIndex() error = %v", err)
+	}
+	storage.Close()
+
+	// Bulk write after a restart
+	storage = reopen()
+	if err := storage.AddTransactionsToAddressIndex(ctx, []AddressTransactionEntry{
+		{Address: addr1, TxHash: common.HexToHash("0x05")},
+		{Address: addr2, TxHash: common.HexToHash("0x22")},
+	}); err != nil {
+		t.Fatalf("AddTransactionsToAddressIndex() error = %v", err)
+	}
+	storage.Close()
+
+	// Batch write after a restart
+	storage = reopen()
+	defer storage.Close()
+	batch := storage.NewBatch()
+	if err := batch.AddTransactionToAddressIndex(ctx, addr1, common.HexToHash("0x06")); err != nil {
+		t.Fatalf("batch AddTransactionToAddressIndex() error = %v", err)
+	}
+	if err := batch.Commit(); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	batch.Close()
+
+	want := map[common.Address][]common.Hash{
+		addr1: {
+			common.HexToHash("0x01"), common.HexToHash("0x02"), common.HexToHash("0x03"),
+			common.HexToHash("0x04"), common.HexToHash("0x05"), common.HexToHash("0x06"),
+		},
+		addr2: {common.HexToHash("0x21"), common.HexToHash("0x22")},
+	}
+	for addr, wantHashes := range want {
+		hashes, err := storage.GetTransactionsByAddress(ctx, addr, 100, 0)
+		if err != nil {
+			t.Fatalf("GetTransactionsByAddress() error = %v", err)
+		}
+		if len(hashes) != len(wantHashes) {
+			t.Fatalf("address %s has %d indexed transactions, want %d", addr.Hex(), len(hashes), len(wantHashes))
+		}
+		for i := range wantHashes {
+			if hashes[i] != wantHashes[i] {
+				t.Errorf("address %s entry %d = %s, want %s", addr.Hex(), i, hashes[i].Hex(), wantHashes[i].Hex())
+			}
+		}
+	}
+}
+
+func TestPebbleStorage_CorruptedValues(t *testing.T) {
+	storage, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	s := storage.(*PebbleStorage)
+	ctx := context.Background()
+	receiptHash := common.HexToHash("0xbad")
+
+	// Garbage that is not valid RLP, and an 8-byte counter truncated to 3 bytes
+	if err := s.db.Set(BlockKey(1), []byte{0xff, 0xff, 0xff}, nil); err != nil {
+		t.Fatalf("db.Set() error = %v", err)
+	}
+	if err := s.db.Set(ReceiptKey(receiptHash), []byte("not rlp"), nil); err != nil {
+		t.Fatalf("db.Set() error = %v", err)
+	}
+	if err := s.db.Set(LatestHeightKey(), []byte{0x01, 0x02, 0x03}, nil); err != nil {
+		t.Fatalf("db.Set() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		call func() error
+		want []error
+	}{
+		{"GetBlock", func() error {
+			_, err := s.GetBlock(ctx, 1)
+			return err
+		}, []error{ErrDecodeFailed}},
+		{"GetReceipt", func() error {
+			_, err := s.GetReceipt(ctx, receiptHash)
+			return err
+		}, []error{ErrDecodeFailed}},
+		{"GetReceipts", func() error {
+			_, err := s.GetReceipts(ctx, []common.Hash{receiptHash})
+			return err
+		}, []error{ErrDecodeFailed}},
+		{"GetLatestHeight", func() error {
+			_, err := s.GetLatestHeight(ctx)
+			return err
+		}, []error{ErrDecodeFailed, ErrCorrupted}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			if err == nil {
+				t.Fatal("expected an error for a corrupted value")
+			}
+			for _, want := range tt.want {
+				if !errors.Is(err, want) {
+					t.Errorf("error %q does not match %v", err, want)
+				}
+			}
+			if errors.Is(err, ErrNotFound) {
+				t.Errorf("error %q matches ErrNotFound", err)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package storage
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -22,7 +23,7 @@ func (s *PebbleStorage) GetTokenBalances(ctx context.Context, addr common.Addres
 	// Get the latest height
 	latestHeight, err := s.GetLatestHeight(ctx)
 	if err != nil {
-		if err == ErrNotFound {
+		if errors.Is(err, ErrNotFound) {
 			return []TokenBalance{}, nil
 		}
 		return nil, fmt.Errorf("failed to get latest height: %w", err)
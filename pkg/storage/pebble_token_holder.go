@@ -466,7 +466,7 @@ func (s *PebbleStorage) getTokenHolder(ctx context.Context, token, holder common
 }
 
 // updateHolderCountInBatch updates the holder count in a batch
-func (s *PebbleStorage) updateHolderCountInBatch(batch *pebble.Batch, token common.Address, delta int) error {
+func (s *PebbleStorage) updateHolderCountInBatch(batch *nsBatch, token common.Address, delta int) error {
 	// Get current stats
 	key := TokenHolderStatsKey(token)
 	value, closer, err := s.db.Get(key)
@@ -3,6 +3,7 @@ package storage
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
 	"strings"
@@ -184,7 +185,7 @@ func (s *PebbleStorage) DeleteTokenMetadata(ctx context.Context, address common.
 	// Get existing metadata to delete indexes
 	metadata, err := s.GetTokenMetadata(ctx, address)
 	if err != nil {
-		if err == ErrNotFound {
+		if errors.Is(err, ErrNotFound) {
 			return nil // Already deleted
 		}
 		return err
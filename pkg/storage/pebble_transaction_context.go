@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var _ TransactionContextReader = (*PebbleStorage)(nil)
+
+// GetTransactionContext returns the transaction with the given hash along with
+// its location, block header and receipt. The location is read once and used
+// to address the transaction, header and receipt, so the header is read on its
+// own rather than by decoding the full block.
+func (s *PebbleStorage) GetTransactionContext(ctx context.Context, hash common.Hash) (*TransactionContext, error) {
+	if err := s.ensureNotClosed(); err != nil {
+		return nil, err
+	}
+
+	location, err := s.getTxLocation(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.getTransactionAt(location)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := s.GetBlockHeader(ctx, location.BlockHeight)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block header %d: %w", location.BlockHeight, err)
+	}
+
+	receipt, err := s.getReceiptAt(ctx, hash, location)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("failed to get receipt: %w", err)
+	}
+
+	return &TransactionContext{
+		Transaction: tx,
+		Location:    location,
+		Header:      header,
+		Receipt:     receipt,
+	}, nil
+}
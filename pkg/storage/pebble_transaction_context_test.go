@@ -0,0 +1,119 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+func TestPebbleStorage_GetTransactionContext(t *testing.T) {
+	s, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	storage := s.(*PebbleStorage)
+	ctx := context.Background()
+
+	txs := signedSenderTestTxs(t)
+	header := &types.Header{
+		Number:     big.NewInt(7),
+		Time:       1000,
+		Difficulty: big.NewInt(1),
+		GasLimit:   1000000,
+		GasUsed:    42000,
+	}
+	block := types.NewBlock(header, &types.Body{Transactions: txs}, nil, trie.NewStackTrie(nil))
+	if err := storage.SetBlock(ctx, block); err != nil {
+		t.Fatalf("SetBlock() error = %v", err)
+	}
+
+	// The last transaction is left without a receipt
+	for i, tx := range txs[:2] {
+		receipt := &types.Receipt{
+			Type:              tx.Type(),
+			Status:            types.ReceiptStatusSuccessful,
+			CumulativeGasUsed: uint64(21000 * (i + 1)),
+			TxHash:            tx.Hash(),
+			Logs: []*types.Log{{
+				Address: common.HexToAddress("0x3333333333333333333333333333333333333333"),
+				Topics:  []common.Hash{common.HexToHash("0x01")},
+			}},
+		}
+		if err := storage.SetReceipt(ctx, receipt); err != nil {
+			t.Fatalf("SetReceipt() error = %v", err)
+		}
+	}
+
+	lookups := map[string]func(common.Hash) (*TransactionContext, error){
+		"PebbleStorage": func(hash common.Hash) (*TransactionContext, error) {
+			return storage.GetTransactionContext(ctx, hash)
+		},
+		"LookupTransactionContext": func(hash common.Hash) (*TransactionContext, error) {
+			return LookupTransactionContext(ctx, storage, hash)
+		},
+	}
+	for name, lookup := range lookups {
+		t.Run(name, func(t *testing.T) {
+			for i, tx := range txs {
+				txCtx, err := lookup(tx.Hash())
+				if err != nil {
+					t.Fatalf("tx %d: error = %v", i, err)
+				}
+
+				if txCtx.Transaction.Hash() != tx.Hash() {
+					t.Errorf("tx %d: Transaction = %s, want %s", i, txCtx.Transaction.Hash().Hex(), tx.Hash().Hex())
+				}
+
+				loc := txCtx.Location
+				if loc.BlockHeight != block.NumberU64() || loc.BlockHash != block.Hash() || loc.TxIndex != uint64(i) {
+					t.Errorf("tx %d: Location = %+v, want block %d (%s) index %d", i, loc, block.NumberU64(), block.Hash().Hex(), i)
+				}
+				sender, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+				if err != nil {
+					t.Fatalf("types.Sender() error = %v", err)
+				}
+				if loc.From != sender {
+					t.Errorf("tx %d: Location.From = %s, want %s", i, loc.From.Hex(), sender.Hex())
+				}
+
+				if txCtx.Header.Hash() != loc.BlockHash {
+					t.Errorf("tx %d: Header hash = %s, want location block hash %s", i, txCtx.Header.Hash().Hex(), loc.BlockHash.Hex())
+				}
+				if txCtx.Header.Number.Uint64() != loc.BlockHeight {
+					t.Errorf("tx %d: Header number = %d, want %d", i, txCtx.Header.Number.Uint64(), loc.BlockHeight)
+				}
+
+				if i == len(txs)-1 {
+					if txCtx.Receipt != nil {
+						t.Errorf("tx %d: Receipt = %+v, want nil for a transaction without a receipt", i, txCtx.Receipt)
+					}
+					continue
+				}
+				receipt := txCtx.Receipt
+				if receipt == nil {
+					t.Fatalf("tx %d: Receipt is nil", i)
+				}
+				if receipt.TxHash != tx.Hash() || receipt.BlockHash != loc.BlockHash ||
+					receipt.BlockNumber.Uint64() != loc.BlockHeight || uint64(receipt.TransactionIndex) != loc.TxIndex {
+					t.Errorf("tx %d: Receipt context = (%s, %s, %d, %d), want (%s, %s, %d, %d)", i,
+						receipt.TxHash.Hex(), receipt.BlockHash.Hex(), receipt.BlockNumber.Uint64(), receipt.TransactionIndex,
+						tx.Hash().Hex(), loc.BlockHash.Hex(), loc.BlockHeight, loc.TxIndex)
+				}
+				for _, log := range receipt.Logs {
+					if log.TxHash != tx.Hash() || log.BlockHash != loc.BlockHash || uint64(log.TxIndex) != loc.TxIndex {
+						t.Errorf("tx %d: log context = (%s, %s, %d), want (%s, %s, %d)", i,
+							log.TxHash.Hex(), log.BlockHash.Hex(), log.TxIndex, tx.Hash().Hex(), loc.BlockHash.Hex(), loc.TxIndex)
+					}
+				}
+			}
+
+			if _, err := lookup(common.HexToHash("0xdead")); !errors.Is(err, ErrNotFound) {
+				t.Errorf("unknown hash: error = %v, want ErrNotFound", err)
+			}
+		})
+	}
+}
@@ -3,6 +3,8 @@ package storage
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strconv"
 
 	"github.com/cockroachdb/pebble"
 	"github.com/ethereum/go-ethereum/common"
@@ -19,37 +21,35 @@ func (s *PebbleStorage) GetTransaction(ctx context.Context, hash common.Hash) (*
 		return nil, nil, err
 	}
 
-	// Get transaction location
-	locValue, closer, err := s.db.Get(TransactionHashIndexKey(hash))
+	location, err := s.getTxLocation(hash)
 	if err != nil {
-		if err == pebble.ErrNotFound {
-			return nil, nil, ErrNotFound
-		}
-		return nil, nil, fmt.Errorf("failed to get transaction location: %w", err)
+		return nil, nil, err
 	}
-	defer closer.Close()
 
-	location, err := DecodeTxLocation(locValue)
+	tx, err := s.getTransactionAt(location)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to decode location: %w", err)
+		return nil, nil, err
 	}
 
-	// Get transaction data
-	txValue, closer, err := s.db.Get(TransactionKey(location.BlockHeight, location.TxIndex))
+	return tx, location, nil
+}
+
+// getTransactionAt reads and decodes the transaction stored at location
+func (s *PebbleStorage) getTransactionAt(location *TxLocation) (*types.Transaction, error) {
+	value, closer, err := s.db.Get(TransactionKey(location.BlockHeight, location.TxIndex))
 	if err != nil {
 		if err == pebble.ErrNotFound {
-			return nil, nil, ErrNotFound
+			return nil, ErrNotFound
 		}
-		return nil, nil, fmt.Errorf("failed to get transaction: %w", err)
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
 	}
 	defer closer.Close()
 
-	tx, err := DecodeTransaction(txValue)
+	tx, err := DecodeTransaction(value)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to decode transaction: %w", err)
+		return nil, fmt.Errorf("%w transaction: %w", ErrDecodeFailed, err)
 	}
-
-	return tx, location, nil
+	return tx, nil
 }
 
 // GetTransactions returns multiple transactions and their locations by hash (batch operation)
@@ -81,6 +81,83 @@ func (s *PebbleStorage) GetTransactions(ctx context.Context, hashes []common.Has
 	return txs, locations, nil
 }
 
+// GetTransactionsByBlock returns the transactions of a block and their
+// locations in index order by scanning the block's transaction keys, without
+// decoding the block body. Returns ErrNotFound if the block is not stored.
+func (s *PebbleStorage) GetTransactionsByBlock(ctx context.Context, height uint64) ([]*types.Transaction, []*TxLocation, error) {
+	if err := s.ensureNotClosed(); err != nil {
+		return nil, nil, err
+	}
+
+	prefix := TransactionKeyPrefix(height)
+	iter, err := s.db.NewIter(&pebble.IterOptions{
+		LowerBound: prefix,
+		UpperBound: prefixUpperBound(prefix),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create iterator: %w", err)
+	}
+	defer iter.Close()
+
+	var txs []*types.Transaction
+	var indices []uint64
+	for iter.First(); iter.Valid(); iter.Next() {
+		_, txIndex, err := ParseTransactionKey(iter.Key())
+		if err != nil {
+			return nil, nil, err
+		}
+		tx, err := DecodeTransaction(iter.Value())
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w transaction %d: %w", ErrDecodeFailed, txIndex, err)
+		}
+		txs = append(txs, tx)
+		indices = append(indices, txIndex)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, nil, fmt.Errorf("iterator error: %w", err)
+	}
+
+	if len(txs) == 0 {
+		exists, err := s.HasBlock(ctx, height)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !exists {
+			return nil, nil, ErrNotFound
+		}
+		return []*types.Transaction{}, []*TxLocation{}, nil
+	}
+
+	// Indices are not zero-padded, so keys iterate as 0, 1, 10, 11, ..., 2
+	order := make([]int, len(txs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool { return indices[order[a]] < indices[order[b]] })
+
+	sortedTxs := make([]*types.Transaction, len(txs))
+	locations := make([]*TxLocation, len(txs))
+	for i, j := range order {
+		tx := txs[j]
+		locValue, closer, err := s.db.Get(TransactionHashIndexKey(tx.Hash()))
+		if err != nil {
+			if err == pebble.ErrNotFound {
+				return nil, nil, ErrNotFound
+			}
+			return nil, nil, fmt.Errorf("failed to get transaction location: %w", err)
+		}
+		location, err := DecodeTxLocation(locValue)
+		closer.Close()
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w location: %w", ErrDecodeFailed, err)
+		}
+		sortedTxs[i] = tx
+		locations[i] = location
+	}
+
+	return sortedTxs, locations, nil
+}
+
 // SetTransaction stores a transaction with its location
 func (s *PebbleStorage) SetTransaction(ctx context.Context, tx *types.Transaction, location *TxLocation) error {
 	if err := s.ensureNotClosed(); err != nil {
@@ -97,34 +174,47 @@ func (s *PebbleStorage) SetTransaction(ctx context.Context, tx *types.Transactio
 		return fmt.Errorf("location cannot be nil")
 	}
 
+	batch := s.db.NewBatch()
+	defer batch.Close()
+
+	if err := s.setTransactionInBatch(batch, tx, location); err != nil {
+		return err
+	}
+
+	// Update transaction count using the cached counter (avoid DB read)
+	// Use NoSync for performance
+	if err := s.commitWithTxCount(batch, 1, pebble.NoSync); err != nil {
+		return err
+	}
+
+	s.readCache.remove(receiptCacheKey(tx.Hash()))
+	return nil
+}
+
+// setTransactionInBatch adds a transaction and its hash index to the batch
+func (s *PebbleStorage) setTransactionInBatch(batch *nsBatch, tx *types.Transaction, location *TxLocation) error {
+	if tx == nil {
+		return fmt.Errorf("transaction cannot be nil")
+	}
+
 	// Encode transaction
 	encoded, err := EncodeTransaction(tx)
 	if err != nil {
-		return fmt.Errorf("failed to encode transaction: %w", err)
+		return fmt.Errorf("%w transaction: %w", ErrEncodeFailed, err)
 	}
 
-	// Encode location
-	locEncoded, err := EncodeTxLocation(location)
+	// Encode location with the recovered sender
+	locEncoded, err := EncodeTxLocation(location.withSender(tx))
 	if err != nil {
-		return fmt.Errorf("failed to encode location: %w", err)
+		return fmt.Errorf("%w location: %w", ErrEncodeFailed, err)
 	}
 
-	// Write transaction data - use NoSync for performance
-	if err := s.db.Set(TransactionKey(location.BlockHeight, location.TxIndex), encoded, pebble.NoSync); err != nil {
+	if err := batch.Set(TransactionKey(location.BlockHeight, location.TxIndex), encoded, nil); err != nil {
 		return fmt.Errorf("failed to set transaction: %w", err)
 	}
-
-	// Write transaction hash index
-	if err := s.db.Set(TransactionHashIndexKey(tx.Hash()), locEncoded, pebble.NoSync); err != nil {
+	if err := batch.Set(TransactionHashIndexKey(tx.Hash()), locEncoded, nil); err != nil {
 		return fmt.Errorf("failed to set transaction index: %w", err)
 	}
-
-	// Update transaction count using atomic counter (avoid DB read)
-	newCount := s.txCount.Add(1)
-	if err := s.db.Set(TransactionCountKey(), EncodeUint64(newCount), pebble.NoSync); err != nil {
-		return fmt.Errorf("failed to update transaction count: %w", err)
-	}
-
 	return nil
 }
 
@@ -187,15 +277,94 @@ func (s *PebbleStorage) AddTransactionToAddressIndex(ctx context.Context, addr c
 
 	// Get next sequence number for this address
 	s.addrSeqMu.Lock()
-	seq := s.addrSeq[addr]
-	s.addrSeq[addr]++
+	seq, err := s.nextAddressSeqLocked(addr)
 	s.addrSeqMu.Unlock()
+	if err != nil {
+		return err
+	}
 
 	key := AddressTransactionKey(addr, seq)
 	// Use NoSync for performance - caller should use Sync() or batch commit for durability
 	return s.db.Set(key, txHash[:], pebble.NoSync)
 }
 
+// AddTransactionsToAddressIndex adds many transactions to their address indexes
+// in a single batch, reserving sequence numbers once for all entries
+func (s *PebbleStorage) AddTransactionsToAddressIndex(ctx context.Context, entries []AddressTransactionEntry) error {
+	if err := s.ensureNotClosed(); err != nil {
+		return err
+	}
+	if err := s.ensureNotReadOnly(); err != nil {
+		return err
+	}
+
+	batch := s.db.NewBatch()
+	defer batch.Close()
+
+	seqs, err := s.reserveAddressSequences(entries)
+	if err != nil {
+		return err
+	}
+	for i, seq := range seqs {
+		entry := entries[i]
+		if err := batch.Set(AddressTransactionKey(entry.Address, seq), entry.TxHash[:], nil); err != nil {
+			return err
+		}
+	}
+
+	// Use NoSync for performance - caller should use Sync() or batch commit for durability
+	return batch.Commit(pebble.NoSync)
+}
+
+// reserveAddressSequences assigns the next sequence number of each entry's address
+// in entry order, taking the sequence lock once for the whole slice
+func (s *PebbleStorage) reserveAddressSequences(entries []AddressTransactionEntry) ([]uint64, error) {
+	seqs := make([]uint64, len(entries))
+
+	s.addrSeqMu.Lock()
+	defer s.addrSeqMu.Unlock()
+	for i, entry := range entries {
+		seq, err := s.nextAddressSeqLocked(entry.Address)
+		if err != nil {
+			return nil, err
+		}
+		seqs[i] = seq
+	}
+	return seqs, nil
+}
+
+// nextAddressSeqLocked allocates the next address index sequence for an address.
+// The counter is seeded from the last stored entry the first time an address is
+// seen, so entries written after a restart are appended instead of overwriting
+// earlier ones. The caller must hold addrSeqMu.
+func (s *PebbleStorage) nextAddressSeqLocked(addr common.Address) (uint64, error) {
+	seq, ok := s.addrSeq[addr]
+	if !ok {
+		prefix := AddressTransactionKeyPrefix(addr)
+		iter, err := s.db.NewIter(&pebble.IterOptions{
+			LowerBound: prefix,
+			UpperBound: prefixUpperBound(prefix),
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to create iterator: %w", err)
+		}
+		if iter.Last() {
+			last, parseErr := strconv.ParseUint(string(iter.Key()[len(prefix):]), 10, 64)
+			if parseErr != nil {
+				iter.Close()
+				return 0, fmt.Errorf("invalid address index key %q: %w", iter.Key(), parseErr)
+			}
+			seq = last + 1
+		}
+		if err := iter.Close(); err != nil {
+			return 0, fmt.Errorf("failed to close iterator: %w", err)
+		}
+	}
+
+	s.addrSeq[addr] = seq + 1
+	return seq, nil
+}
+
 // HasTransaction checks if a transaction exists
 func (s *PebbleStorage) HasTransaction(ctx context.Context, hash common.Hash) (bool, error) {
 	if err := s.ensureNotClosed(); err != nil {
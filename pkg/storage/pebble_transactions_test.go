@@ -0,0 +1,312 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/holiman/uint256"
+)
+
+// signedSenderTestTxs returns a signed legacy, access-list and dynamic-fee
+// transaction from the same key.
+func signedSenderTestTxs(t *testing.T) []*types.Transaction {
+	t.Helper()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	chainID := big.NewInt(1337)
+	to := common.HexToAddress("0x2222222222222222222222222222222222222222")
+
+	unsigned := []types.TxData{
+		&types.LegacyTx{
+			Nonce:    0,
+			GasPrice: big.NewInt(1000000000),
+			Gas:      21000,
+			To:       &to,
+			Value:    big.NewInt(1),
+		},
+		&types.AccessListTx{
+			ChainID:  chainID,
+			Nonce:    1,
+			GasPrice: big.NewInt(1000000000),
+			Gas:      21000,
+			To:       &to,
+			Value:    big.NewInt(2),
+		},
+		&types.DynamicFeeTx{
+			ChainID:   chainID,
+			Nonce:     2,
+			GasTipCap: big.NewInt(1000000000),
+			GasFeeCap: big.NewInt(2000000000),
+			Gas:       21000,
+			To:        &to,
+			Value:     big.NewInt(3),
+		},
+	}
+
+	signer := types.LatestSignerForChainID(chainID)
+	txs := make([]*types.Transaction, 0, len(unsigned))
+	for _, data := range unsigned {
+		tx, err := types.SignNewTx(key, signer, data)
+		if err != nil {
+			t.Fatalf("Failed to sign transaction: %v", err)
+		}
+		txs = append(txs, tx)
+	}
+	return txs
+}
+
+func assertCachedSender(t *testing.T, s Storage, tx *types.Transaction) {
+	t.Helper()
+
+	want, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		t.Fatalf("types.Sender() error = %v", err)
+	}
+
+	_, location, err := s.GetTransaction(context.Background(), tx.Hash())
+	if err != nil {
+		t.Fatalf("GetTransaction() error = %v", err)
+	}
+	if location.From != want {
+		t.Errorf("type %d: cached From = %s, want %s", tx.Type(), location.From.Hex(), want.Hex())
+	}
+}
+
+func TestPebbleStorage_SetBlockCachesSender(t *testing.T) {
+	storage, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	txs := signedSenderTestTxs(t)
+	header := &types.Header{
+		Number:     big.NewInt(1),
+		Time:       1000,
+		Difficulty: big.NewInt(1),
+		GasLimit:   1000000,
+	}
+	block := types.NewBlock(header, &types.Body{Transactions: txs}, nil, trie.NewStackTrie(nil))
+
+	if err := storage.SetBlock(context.Background(), block); err != nil {
+		t.Fatalf("SetBlock() error = %v", err)
+	}
+
+	for _, tx := range txs {
+		assertCachedSender(t, storage, tx)
+	}
+}
+
+func TestPebbleStorage_SetTransactionCachesSender(t *testing.T) {
+	storage, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	for i, tx := range signedSenderTestTxs(t) {
+		location := &TxLocation{BlockHeight: 1, TxIndex: uint64(i)}
+		if err := storage.SetTransaction(ctx, tx, location); err != nil {
+			t.Fatalf("SetTransaction() error = %v", err)
+		}
+		if location.From != (common.Address{}) {
+			t.Error("SetTransaction() modified the caller's location")
+		}
+		assertCachedSender(t, storage, tx)
+	}
+}
+
+func TestPebbleBatch_SetTransactionCachesSender(t *testing.T) {
+	storage, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	txs := signedSenderTestTxs(t)
+	batch := storage.NewBatch()
+	for i, tx := range txs {
+		if err := batch.SetTransaction(ctx, tx, &TxLocation{BlockHeight: 1, TxIndex: uint64(i)}); err != nil {
+			t.Fatalf("SetTransaction() error = %v", err)
+		}
+	}
+	if err := batch.Commit(); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+
+	for _, tx := range txs {
+		assertCachedSender(t, storage, tx)
+	}
+}
+
+func TestTxLocation_Sender(t *testing.T) {
+	tx := signedSenderTestTxs(t)[2]
+	recovered, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		t.Fatalf("types.Sender() error = %v", err)
+	}
+
+	t.Run("nil location recovers sender", func(t *testing.T) {
+		var location *TxLocation
+		got, err := location.Sender(tx)
+		if err != nil {
+			t.Fatalf("Sender() error = %v", err)
+		}
+		if got != recovered {
+			t.Errorf("Sender() = %s, want %s", got.Hex(), recovered.Hex())
+		}
+	})
+
+	t.Run("cached sender is returned as is", func(t *testing.T) {
+		cached := common.HexToAddress("0x3333333333333333333333333333333333333333")
+		got, err := (&TxLocation{From: cached}).Sender(tx)
+		if err != nil {
+			t.Fatalf("Sender() error = %v", err)
+		}
+		if got != cached {
+			t.Errorf("Sender() = %s, want %s", got.Hex(), cached.Hex())
+		}
+	})
+}
+
+func TestDecodeTxLocation_WithoutSender(t *testing.T) {
+	// Locations written before senders were cached have three fields
+	legacy := struct {
+		BlockHeight uint64
+		TxIndex     uint64
+		BlockHash   common.Hash
+	}{
+		BlockHeight: 42,
+		TxIndex:     3,
+		BlockHash:   common.HexToHash("0xabcd"),
+	}
+	encoded, err := rlp.EncodeToBytes(&legacy)
+	if err != nil {
+		t.Fatalf("EncodeToBytes() error = %v", err)
+	}
+
+	decoded, err := DecodeTxLocation(encoded)
+	if err != nil {
+		t.Fatalf("DecodeTxLocation() error = %v", err)
+	}
+	if decoded.BlockHeight != 42 || decoded.TxIndex != 3 || decoded.BlockHash != legacy.BlockHash {
+		t.Errorf("DecodeTxLocation() = %+v, want %+v", decoded, legacy)
+	}
+	if decoded.From != (common.Address{}) {
+		t.Errorf("From = %s, want zero address", decoded.From.Hex())
+	}
+}
+
+// signedBlobTestTx returns a signed EIP-4844 blob transaction carrying a
+// sidecar, as submitted to a node
+func signedBlobTestTx(t *testing.T) *types.Transaction {
+	t.Helper()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	chainID := big.NewInt(1337)
+
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(chainID), &types.BlobTx{
+		ChainID:   uint256.MustFromBig(chainID),
+		Nonce:     7,
+		GasTipCap: uint256.NewInt(1000000000),
+		GasFeeCap: uint256.NewInt(2000000000),
+		Gas:       21000,
+		To:        common.HexToAddress("0x2222222222222222222222222222222222222222"),
+		Value:     uint256.NewInt(5),
+		Data:      []byte{0xca, 0xfe},
+		AccessList: types.AccessList{{
+			Address:     common.HexToAddress("0x4444444444444444444444444444444444444444"),
+			StorageKeys: []common.Hash{common.HexToHash("0x01")},
+		}},
+		BlobFeeCap: uint256.NewInt(3000000000),
+		BlobHashes: []common.Hash{
+			common.HexToHash("0x0100000000000000000000000000000000000000000000000000000000000001"),
+			common.HexToHash("0x0100000000000000000000000000000000000000000000000000000000000002"),
+		},
+		Sidecar: types.NewBlobTxSidecar(types.BlobSidecarVersion0,
+			make([]kzg4844.Blob, 2), make([]kzg4844.Commitment, 2), make([]kzg4844.Proof, 2)),
+	})
+	if err != nil {
+		t.Fatalf("Failed to sign transaction: %v", err)
+	}
+	return tx
+}
+
+// assertBlobTxEqual checks that every field of a blob transaction survived storage
+func assertBlobTxEqual(t *testing.T, got, want *types.Transaction) {
+	t.Helper()
+
+	if got.Type() != types.BlobTxType {
+		t.Fatalf("Type() = %d, want %d", got.Type(), types.BlobTxType)
+	}
+	if got.Hash() != want.Hash() {
+		t.Errorf("Hash() = %s, want %s", got.Hash().Hex(), want.Hash().Hex())
+	}
+	if got.ChainId().Cmp(want.ChainId()) != 0 || got.Nonce() != want.Nonce() || got.Gas() != want.Gas() {
+		t.Errorf("chainId/nonce/gas = %s/%d/%d, want %s/%d/%d",
+			got.ChainId(), got.Nonce(), got.Gas(), want.ChainId(), want.Nonce(), want.Gas())
+	}
+	if got.GasTipCap().Cmp(want.GasTipCap()) != 0 || got.GasFeeCap().Cmp(want.GasFeeCap()) != 0 {
+		t.Errorf("fee caps = %s/%s, want %s/%s", got.GasTipCap(), got.GasFeeCap(), want.GasTipCap(), want.GasFeeCap())
+	}
+	if got.BlobGasFeeCap().Cmp(want.BlobGasFeeCap()) != 0 {
+		t.Errorf("BlobGasFeeCap() = %s, want %s", got.BlobGasFeeCap(), want.BlobGasFeeCap())
+	}
+	if !reflect.DeepEqual(got.BlobHashes(), want.BlobHashes()) {
+		t.Errorf("BlobHashes() = %v, want %v", got.BlobHashes(), want.BlobHashes())
+	}
+	if *got.To() != *want.To() || got.Value().Cmp(want.Value()) != 0 || !bytes.Equal(got.Data(), want.Data()) {
+		t.Errorf("to/value/data = %s/%s/%x, want %s/%s/%x",
+			got.To().Hex(), got.Value(), got.Data(), want.To().Hex(), want.Value(), want.Data())
+	}
+	if !reflect.DeepEqual(got.AccessList(), want.AccessList()) {
+		t.Errorf("AccessList() = %v, want %v", got.AccessList(), want.AccessList())
+	}
+	gotV, gotR, gotS := got.RawSignatureValues()
+	wantV, wantR, wantS := want.RawSignatureValues()
+	if gotV.Cmp(wantV) != 0 || gotR.Cmp(wantR) != 0 || gotS.Cmp(wantS) != 0 {
+		t.Error("signature values differ")
+	}
+}
+
+func TestPebbleStorage_BlobTransaction(t *testing.T) {
+	storage, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	tx := signedBlobTestTx(t)
+	header := &types.Header{
+		Number:     big.NewInt(1),
+		Time:       1000,
+		Difficulty: big.NewInt(1),
+		GasLimit:   1000000,
+	}
+	block := types.NewBlock(header, &types.Body{Transactions: []*types.Transaction{tx.WithoutBlobTxSidecar()}}, nil, trie.NewStackTrie(nil))
+	if err := storage.SetBlock(ctx, block); err != nil {
+		t.Fatalf("SetBlock() error = %v", err)
+	}
+
+	got, location, err := storage.GetTransaction(ctx, tx.Hash())
+	if err != nil {
+		t.Fatalf("GetTransaction() error = %v", err)
+	}
+	assertBlobTxEqual(t, got, tx)
+	if location.BlockHeight != 1 || location.TxIndex != 0 {
+		t.Errorf("location = %d/%d, want 1/0", location.BlockHeight, location.TxIndex)
+	}
+	assertCachedSender(t, storage, tx)
+
+	storedBlock, err := storage.GetBlock(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetBlock() error = %v", err)
+	}
+	assertBlobTxEqual(t, storedBlock.Transactions()[0], tx)
+}
@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var _ UncleReader = (*PebbleStorage)(nil)
+
+// GetUncles returns the uncle headers indexed with the block at height
+func (s *PebbleStorage) GetUncles(ctx context.Context, height uint64) ([]*types.Header, error) {
+	if err := s.ensureNotClosed(); err != nil {
+		return nil, err
+	}
+	if !s.config.IndexUncles {
+		return nil, ErrUncleIndexDisabled
+	}
+
+	prefix := UncleKeyPrefix(height)
+	iter, err := s.db.NewIter(&pebble.IterOptions{
+		LowerBound: prefix,
+		UpperBound: prefixUpperBound(prefix),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create iterator: %w", err)
+	}
+	defer iter.Close()
+
+	uncles := []*types.Header{}
+	for iter.First(); iter.Valid(); iter.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		uncle, err := DecodeHeader(iter.Value())
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode uncle of block %d: %w", height, err)
+		}
+		uncles = append(uncles, uncle)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, fmt.Errorf("iterator error: %w", err)
+	}
+
+	// A block without uncles has no entries either, so tell it apart from a
+	// missing block
+	if len(uncles) == 0 {
+		exists, err := s.HasBlock(ctx, height)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, ErrNotFound
+		}
+	}
+	return uncles, nil
+}
+
+// setUnclesInBatch stores the uncle headers of block, when uncle indexing is
+// enabled. The uncles of a block it replaces are removed by
+// deleteReplacedUnclesInBatch.
+func (s *PebbleStorage) setUnclesInBatch(batch *nsBatch, block *types.Block) error {
+	if !s.config.IndexUncles {
+		return nil
+	}
+
+	height := block.NumberU64()
+	for i, uncle := range block.Uncles() {
+		encoded, err := EncodeHeader(uncle)
+		if err != nil {
+			return fmt.Errorf("failed to encode uncle %d of block %d: %w", i, height, err)
+		}
+		if err := batch.Set(UncleKey(height, i), encoded, nil); err != nil {
+			return fmt.Errorf("failed to set uncle %d of block %d: %w", i, height, err)
+		}
+	}
+	return nil
+}
+
+// deleteReplacedUnclesInBatch removes the uncle headers stored for the block
+// at height, before a different block replaces it. It deletes each stored key
+// rather than the whole range, so only a replacement leaves tombstones, one
+// per uncle.
+func (s *PebbleStorage) deleteReplacedUnclesInBatch(batch *nsBatch, height uint64) error {
+	prefix := UncleKeyPrefix(height)
+	iter, err := s.db.NewIter(&pebble.IterOptions{
+		LowerBound: prefix,
+		UpperBound: prefixUpperBound(prefix),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create iterator: %w", err)
+	}
+	defer iter.Close()
+
+	for iter.First(); iter.Valid(); iter.Next() {
+		if err := batch.Delete(iter.Key(), nil); err != nil {
+			return fmt.Errorf("failed to delete uncle of block %d: %w", height, err)
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return fmt.Errorf("iterator error: %w", err)
+	}
+	return nil
+}
+
+// deleteUnclesInBatch removes the uncle headers stored for the block at height
+func deleteUnclesInBatch(batch *nsBatch, height uint64) error {
+	prefix := UncleKeyPrefix(height)
+	if err := batch.DeleteRange(prefix, prefixUpperBound(prefix), nil); err != nil {
+		return fmt.Errorf("failed to delete uncles of block %d: %w", height, err)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// createTestBlockWithUncles creates a block at height carrying one uncle per
+// given miner address
+func createTestBlockWithUncles(height uint64, uncleMiners ...common.Address) *types.Block {
+	uncles := make([]*types.Header, len(uncleMiners))
+	for i, miner := range uncleMiners {
+		uncles[i] = &types.Header{
+			ParentHash: common.BigToHash(big.NewInt(int64(height))),
+			Number:     new(big.Int).SetUint64(height - 1),
+			Coinbase:   miner,
+			Difficulty: big.NewInt(1),
+			GasLimit:   8000000,
+			Time:       1000 + height,
+		}
+	}
+	header := &types.Header{
+		Number:     new(big.Int).SetUint64(height),
+		Difficulty: big.NewInt(1),
+		GasLimit:   8000000,
+		Time:       1000 + height,
+	}
+	return types.NewBlock(header, &types.Body{Uncles: uncles}, nil, trie.NewStackTrie(nil))
+}
+
+func openUncleTestStorage(t *testing.T, indexUncles bool) *PebbleStorage {
+	t.Helper()
+
+	cfg := DefaultConfig(t.TempDir())
+	cfg.IndexUncles = indexUncles
+	storage, err := NewPebbleStorage(cfg)
+	if err != nil {
+		t.Fatalf("NewPebbleStorage() error = %v", err)
+	}
+	t.Cleanup(func() { storage.Close() })
+	return storage
+}
+
+func TestPebbleStorage_GetUncles(t *testing.T) {
+	storage := openUncleTestStorage(t, true)
+	ctx := context.Background()
+
+	minerA := common.HexToAddress("0xa")
+	minerB := common.HexToAddress("0xb")
+	block := createTestBlockWithUncles(10, minerA, minerB)
+	if err := storage.SetBlock(ctx, block); err != nil {
+		t.Fatalf("SetBlock() error = %v", err)
+	}
+	if err := storage.SetBlock(ctx, createTestBlockWithUncles(11)); err != nil {
+		t.Fatalf("SetBlock() error = %v", err)
+	}
+
+	// Blocks written through a batch are indexed the same way
+	batch := storage.NewBatch()
+	defer batch.Close()
+	if err := batch.SetBlock(ctx, createTestBlockWithUncles(20, minerA)); err != nil {
+		t.Fatalf("batch.SetBlock() error = %v", err)
+	}
+	if err := batch.Commit(); err != nil {
+		t.Fatalf("batch.Commit() error = %v", err)
+	}
+	if uncles, err := storage.GetUncles(ctx, 20); err != nil || len(uncles) != 1 {
+		t.Fatalf("GetUncles() for batched block = %d uncles, error %v, want 1", len(uncles), err)
+	}
+
+	uncles, err := storage.GetUncles(ctx, 10)
+	if err != nil {
+		t.Fatalf("GetUncles() error = %v", err)
+	}
+	if len(uncles) != 2 {
+		t.Fatalf("GetUncles() returned %d uncles, want 2", len(uncles))
+	}
+	for i, want := range block.Uncles() {
+		if uncles[i].Hash() != want.Hash() {
+			t.Errorf("uncles[%d] hash = %s, want %s", i, uncles[i].Hash().Hex(), want.Hash().Hex())
+		}
+	}
+	if uncles[0].Coinbase != minerA || uncles[1].Coinbase != minerB {
+		t.Errorf("uncle miners = %s, %s, want %s, %s", uncles[0].Coinbase.Hex(), uncles[1].Coinbase.Hex(), minerA.Hex(), minerB.Hex())
+	}
+
+	// A block without uncles has an empty list, a missing block is not found
+	uncles, err = storage.GetUncles(ctx, 11)
+	if err != nil {
+		t.Fatalf("GetUncles() for block without uncles error = %v", err)
+	}
+	if len(uncles) != 0 {
+		t.Errorf("GetUncles() for block without uncles returned %d, want 0", len(uncles))
+	}
+	if _, err := storage.GetUncles(ctx, 12); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetUncles() for missing block error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestPebbleStorage_GetUncles_ReplacedAndDeleted(t *testing.T) {
+	storage := openUncleTestStorage(t, true)
+	ctx := context.Background()
+
+	if err := storage.SetBlock(ctx, createTestBlockWithUncles(5, common.HexToAddress("0xa"), common.HexToAddress("0xb"))); err != nil {
+		t.Fatalf("SetBlock() error = %v", err)
+	}
+
+	// A reorg replaces the block with one that has a single uncle
+	replacement := createTestBlockWithUncles(5, common.HexToAddress("0xc"))
+	if err := storage.SetBlock(ctx, replacement); err != nil {
+		t.Fatalf("SetBlock() replacement error = %v", err)
+	}
+	uncles, err := storage.GetUncles(ctx, 5)
+	if err != nil {
+		t.Fatalf("GetUncles() error = %v", err)
+	}
+	if len(uncles) != 1 || uncles[0].Hash() != replacement.Uncles()[0].Hash() {
+		t.Fatalf("GetUncles() after replacement returned %d uncles, want the replacement's one", len(uncles))
+	}
+
+	if err := storage.DeleteBlock(ctx, 5); err != nil {
+		t.Fatalf("DeleteBlock() error = %v", err)
+	}
+	if _, err := storage.GetUncles(ctx, 5); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetUncles() after DeleteBlock error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestPebbleStorage_GetUncles_Disabled(t *testing.T) {
+	storage := openUncleTestStorage(t, false)
+	ctx := context.Background()
+
+	if err := storage.SetBlock(ctx, createTestBlockWithUncles(1, common.HexToAddress("0xa"))); err != nil {
+		t.Fatalf("SetBlock() error = %v", err)
+	}
+	if _, err := storage.GetUncles(ctx, 1); !errors.Is(err, ErrUncleIndexDisabled) {
+		t.Errorf("GetUncles() error = %v, want ErrUncleIndexDisabled", err)
+	}
+	if _, closer, err := storage.db.Get(UncleKey(1, 0)); err == nil {
+		closer.Close()
+		t.Error("uncle header stored while uncle indexing is disabled")
+	}
+}
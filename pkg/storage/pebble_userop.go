@@ -237,7 +237,7 @@ func (s *PebbleStorage) GetRecentUserOps(ctx context.Context, limit int) ([]*use
 			opHash := common.BytesToHash(value[:32])
 			op, err := s.GetUserOp(ctx, opHash)
 			if err != nil {
-				s.logger.Warn("failed to get userop from index",
+				s.loggerFor(ctx).Warn("failed to get userop from index",
 					zap.String("opHash", opHash.Hex()),
 					zap.Error(err))
 				continue
@@ -305,7 +305,7 @@ func (s *PebbleStorage) ListBundlers(ctx context.Context, limit, offset int) ([]
 	for iter.First(); iter.Valid(); iter.Next() {
 		var stats userop.BundlerStats
 		if err := json.Unmarshal(iter.Value(), &stats); err != nil {
-			s.logger.Warn("failed to unmarshal bundler stats",
+			s.loggerFor(ctx).Warn("failed to unmarshal bundler stats",
 				zap.String("key", string(iter.Key())),
 				zap.Error(err))
 			continue
@@ -352,7 +352,7 @@ func (s *PebbleStorage) ListFactories(ctx context.Context, limit, offset int) ([
 	for iter.First(); iter.Valid(); iter.Next() {
 		var stats userop.FactoryStats
 		if err := json.Unmarshal(iter.Value(), &stats); err != nil {
-			s.logger.Warn("failed to unmarshal factory stats",
+			s.loggerFor(ctx).Warn("failed to unmarshal factory stats",
 				zap.String("key", string(iter.Key())),
 				zap.Error(err))
 			continue
@@ -398,7 +398,7 @@ func (s *PebbleStorage) ListPaymasters(ctx context.Context, limit, offset int) (
 	for iter.First(); iter.Valid(); iter.Next() {
 		var stats userop.PaymasterStats
 		if err := json.Unmarshal(iter.Value(), &stats); err != nil {
-			s.logger.Warn("failed to unmarshal paymaster stats",
+			s.loggerFor(ctx).Warn("failed to unmarshal paymaster stats",
 				zap.String("key", string(iter.Key())),
 				zap.Error(err))
 			continue
@@ -444,7 +444,7 @@ func (s *PebbleStorage) ListSmartAccounts(ctx context.Context, limit, offset int
 	for iter.First(); iter.Valid(); iter.Next() {
 		var account userop.SmartAccount
 		if err := json.Unmarshal(iter.Value(), &account); err != nil {
-			s.logger.Warn("failed to unmarshal smart account",
+			s.loggerFor(ctx).Warn("failed to unmarshal smart account",
 				zap.String("key", string(iter.Key())),
 				zap.Error(err))
 			continue
@@ -753,7 +753,7 @@ func (s *PebbleStorage) getUserOpsByIndex(ctx context.Context, prefix []byte) ([
 			opHash := common.BytesToHash(value[:32])
 			op, err := s.GetUserOp(ctx, opHash)
 			if err != nil {
-				s.logger.Warn("failed to get userop from index",
+				s.loggerFor(ctx).Warn("failed to get userop from index",
 					zap.String("opHash", opHash.Hex()),
 					zap.Error(err))
 				continue
@@ -808,7 +808,7 @@ func (s *PebbleStorage) getUserOpsByIndexPaginated(ctx context.Context, prefix [
 	for _, opHash := range opHashes[start:end] {
 		op, err := s.GetUserOp(ctx, opHash)
 		if err != nil {
-			s.logger.Warn("failed to get userop",
+			s.loggerFor(ctx).Warn("failed to get userop",
 				zap.String("opHash", opHash.Hex()),
 				zap.Error(err))
 			continue
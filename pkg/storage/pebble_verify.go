@@ -0,0 +1,211 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// VerifyIssueKind identifies the kind of inconsistency found by Verify
+type VerifyIssueKind string
+
+const (
+	// VerifyBlockDecode means the stored block does not decode or is stored
+	// under a height other than its number
+	VerifyBlockDecode VerifyIssueKind = "block_decode"
+	// VerifyBlockHashIndex means the block hash index is missing or points to
+	// another height
+	VerifyBlockHashIndex VerifyIssueKind = "block_hash_index"
+	// VerifyTxData means a transaction of the block is missing from its
+	// position or does not decode to it
+	VerifyTxData VerifyIssueKind = "tx_data"
+	// VerifyTxHashIndex means a transaction hash index is missing or points to
+	// another location
+	VerifyTxHashIndex VerifyIssueKind = "tx_hash_index"
+	// VerifyLatestHeight means the latest height is missing or below the
+	// highest stored block
+	VerifyLatestHeight VerifyIssueKind = "latest_height"
+)
+
+// VerifyIssue is a single inconsistency found by Verify
+type VerifyIssue struct {
+	Kind   VerifyIssueKind
+	Height uint64
+	// TxHash is set for transaction issues
+	TxHash common.Hash
+	Detail string
+}
+
+// VerifyReport is the result of Verify
+type VerifyReport struct {
+	From uint64
+	To   uint64
+
+	BlocksChecked       int
+	TransactionsChecked int
+	// MissingBlocks counts heights in the range with no stored block. They are
+	// not issues, since indexing may not have reached them.
+	MissingBlocks int
+	// MaxHeight is the highest block found in the range
+	MaxHeight uint64
+
+	Issues []VerifyIssue
+}
+
+// OK reports whether no inconsistency was found
+func (r *VerifyReport) OK() bool {
+	return len(r.Issues) == 0
+}
+
+func (r *VerifyReport) addIssue(kind VerifyIssueKind, height uint64, txHash common.Hash, format string, args ...interface{}) {
+	r.Issues = append(r.Issues, VerifyIssue{
+		Kind:   kind,
+		Height: height,
+		TxHash: txHash,
+		Detail: fmt.Sprintf(format, args...),
+	})
+}
+
+// Verify checks the stored blocks in [from, to]: each block decodes, its hash
+// index points back to it, each of its transactions is stored at its position
+// with a hash index pointing there, and the latest height is at least the
+// highest block found. Data is read from disk, bypassing the read cache.
+// Inconsistencies are listed in the report; an error is returned only when
+// the check cannot run.
+func (s *PebbleStorage) Verify(ctx context.Context, from, to uint64) (*VerifyReport, error) {
+	if err := s.ensureNotClosed(); err != nil {
+		return nil, err
+	}
+	if from > to {
+		return nil, fmt.Errorf("invalid range: from %d is after to %d", from, to)
+	}
+
+	report := &VerifyReport{From: from, To: to}
+	found := false
+	for height := from; ; height++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		stored, err := s.verifyBlock(report, height)
+		if err != nil {
+			return nil, err
+		}
+		if stored {
+			found = true
+			report.MaxHeight = height
+		}
+
+		if height == to {
+			break
+		}
+	}
+
+	if found {
+		latest, err := s.GetLatestHeight(ctx)
+		switch {
+		case errors.Is(err, ErrNotFound):
+			report.addIssue(VerifyLatestHeight, report.MaxHeight, common.Hash{}, "latest height is not set")
+		case err != nil:
+			report.addIssue(VerifyLatestHeight, report.MaxHeight, common.Hash{}, "latest height: %v", err)
+		case latest < report.MaxHeight:
+			report.addIssue(VerifyLatestHeight, report.MaxHeight, common.Hash{}, "latest height %d is below stored block %d", latest, report.MaxHeight)
+		}
+	}
+
+	return report, nil
+}
+
+// verifyBlock checks the block at height and its transactions, returning
+// whether a block is stored there
+func (s *PebbleStorage) verifyBlock(report *VerifyReport, height uint64) (bool, error) {
+	value, ok, err := s.getVerifyValue(BlockKey(height))
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		report.MissingBlocks++
+		return false, nil
+	}
+	report.BlocksChecked++
+
+	block, err := DecodeBlock(value)
+	if err != nil {
+		report.addIssue(VerifyBlockDecode, height, common.Hash{}, "block does not decode: %v", err)
+		return true, nil
+	}
+	if block.NumberU64() != height {
+		report.addIssue(VerifyBlockDecode, height, common.Hash{}, "block number is %d", block.NumberU64())
+		return true, nil
+	}
+
+	value, ok, err = s.getVerifyValue(BlockHashIndexKey(block.Hash()))
+	if err != nil {
+		return true, err
+	}
+	if !ok {
+		report.addIssue(VerifyBlockHashIndex, height, common.Hash{}, "hash index for %s is missing", block.Hash().Hex())
+	} else if indexed, err := DecodeUint64(value); err != nil {
+		report.addIssue(VerifyBlockHashIndex, height, common.Hash{}, "hash index for %s does not decode: %v", block.Hash().Hex(), err)
+	} else if indexed != height {
+		report.addIssue(VerifyBlockHashIndex, height, common.Hash{}, "hash index for %s points to height %d", block.Hash().Hex(), indexed)
+	}
+
+	for txIndex, tx := range block.Transactions() {
+		report.TransactionsChecked++
+		if err := s.verifyTransaction(report, height, uint64(txIndex), block.Hash(), tx.Hash()); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+// verifyTransaction checks that the transaction at txIndex of a block is stored
+// and that its hash index points to that position
+func (s *PebbleStorage) verifyTransaction(report *VerifyReport, height, txIndex uint64, blockHash, txHash common.Hash) error {
+	value, ok, err := s.getVerifyValue(TransactionKey(height, txIndex))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		report.addIssue(VerifyTxData, height, txHash, "transaction %d is missing", txIndex)
+	} else if tx, err := DecodeTransaction(value); err != nil {
+		report.addIssue(VerifyTxData, height, txHash, "transaction %d does not decode: %v", txIndex, err)
+	} else if tx.Hash() != txHash {
+		report.addIssue(VerifyTxData, height, txHash, "transaction %d has hash %s", txIndex, tx.Hash().Hex())
+	}
+
+	value, ok, err = s.getVerifyValue(TransactionHashIndexKey(txHash))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		report.addIssue(VerifyTxHashIndex, height, txHash, "hash index is missing")
+		return nil
+	}
+	location, err := DecodeTxLocation(value)
+	if err != nil {
+		report.addIssue(VerifyTxHashIndex, height, txHash, "hash index does not decode: %v", err)
+		return nil
+	}
+	if location.BlockHeight != height || location.TxIndex != txIndex || location.BlockHash != blockHash {
+		report.addIssue(VerifyTxHashIndex, height, txHash, "hash index points to block %d index %d", location.BlockHeight, location.TxIndex)
+	}
+	return nil
+}
+
+// getVerifyValue returns a copy of the value stored at key and whether it exists
+func (s *PebbleStorage) getVerifyValue(key []byte) ([]byte, bool, error) {
+	value, closer, err := s.db.Get(key)
+	if err != nil {
+		if err == pebble.ErrNotFound {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to read %s: %w", key, err)
+	}
+	defer closer.Close()
+	return append([]byte(nil), value...), true, nil
+}
@@ -0,0 +1,225 @@
+package storage
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const verifyTestBlocks = 5
+
+// setupVerifyStorage stores blocks 1..verifyTestBlocks with two transactions each
+func setupVerifyStorage(t *testing.T) (*PebbleStorage, []*types.Block) {
+	t.Helper()
+
+	s := setupTestPebbleStorage(t)
+
+	ctx := context.Background()
+	blocks := make([]*types.Block, verifyTestBlocks+1)
+	for height := uint64(1); height <= verifyTestBlocks; height++ {
+		txs := []*types.Transaction{
+			createTestTransaction(height * 10),
+			createTestTransaction(height*10 + 1),
+		}
+		block := types.NewBlockWithHeader(createTestBlock(height).Header()).WithBody(types.Body{Transactions: txs})
+		if err := s.SetBlock(ctx, block); err != nil {
+			t.Fatalf("SetBlock(%d) error = %v", height, err)
+		}
+		blocks[height] = block
+	}
+	if err := s.SetLatestHeight(ctx, verifyTestBlocks); err != nil {
+		t.Fatalf("SetLatestHeight() error = %v", err)
+	}
+	return s, blocks
+}
+
+// verifyIssueKey identifies an issue without its detail text
+type verifyIssueKey struct {
+	Kind   VerifyIssueKind
+	Height uint64
+	TxHash common.Hash
+}
+
+func verifyIssueKeys(issues []VerifyIssue) []verifyIssueKey {
+	var keys []verifyIssueKey
+	for _, issue := range issues {
+		keys = append(keys, verifyIssueKey{Kind: issue.Kind, Height: issue.Height, TxHash: issue.TxHash})
+	}
+	return keys
+}
+
+func TestPebbleStorage_Verify(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt func(t *testing.T, s *PebbleStorage, blocks []*types.Block)
+		want    func(blocks []*types.Block) []verifyIssueKey
+	}{
+		{
+			name:    "consistent",
+			corrupt: func(t *testing.T, s *PebbleStorage, blocks []*types.Block) {},
+			want:    func(blocks []*types.Block) []verifyIssueKey { return nil },
+		},
+		{
+			name: "block does not decode",
+			corrupt: func(t *testing.T, s *PebbleStorage, blocks []*types.Block) {
+				setVerifyKey(t, s, BlockKey(2), []byte("not rlp"))
+			},
+			want: func(blocks []*types.Block) []verifyIssueKey {
+				return []verifyIssueKey{{Kind: VerifyBlockDecode, Height: 2}}
+			},
+		},
+		{
+			name: "block stored under another height",
+			corrupt: func(t *testing.T, s *PebbleStorage, blocks []*types.Block) {
+				encoded, err := EncodeBlock(blocks[4])
+				if err != nil {
+					t.Fatalf("EncodeBlock() error = %v", err)
+				}
+				setVerifyKey(t, s, BlockKey(3), encoded)
+			},
+			want: func(blocks []*types.Block) []verifyIssueKey {
+				return []verifyIssueKey{{Kind: VerifyBlockDecode, Height: 3}}
+			},
+		},
+		{
+			name: "missing block hash index",
+			corrupt: func(t *testing.T, s *PebbleStorage, blocks []*types.Block) {
+				deleteVerifyKey(t, s, BlockHashIndexKey(blocks[3].Hash()))
+			},
+			want: func(blocks []*types.Block) []verifyIssueKey {
+				return []verifyIssueKey{{Kind: VerifyBlockHashIndex, Height: 3}}
+			},
+		},
+		{
+			name: "block hash index points to another height",
+			corrupt: func(t *testing.T, s *PebbleStorage, blocks []*types.Block) {
+				setVerifyKey(t, s, BlockHashIndexKey(blocks[3].Hash()), EncodeUint64(4))
+			},
+			want: func(blocks []*types.Block) []verifyIssueKey {
+				return []verifyIssueKey{{Kind: VerifyBlockHashIndex, Height: 3}}
+			},
+		},
+		{
+			name: "missing transaction",
+			corrupt: func(t *testing.T, s *PebbleStorage, blocks []*types.Block) {
+				deleteVerifyKey(t, s, TransactionKey(5, 0))
+			},
+			want: func(blocks []*types.Block) []verifyIssueKey {
+				return []verifyIssueKey{{Kind: VerifyTxData, Height: 5, TxHash: blocks[5].Transactions()[0].Hash()}}
+			},
+		},
+		{
+			name: "missing transaction hash index",
+			corrupt: func(t *testing.T, s *PebbleStorage, blocks []*types.Block) {
+				deleteVerifyKey(t, s, TransactionHashIndexKey(blocks[4].Transactions()[1].Hash()))
+			},
+			want: func(blocks []*types.Block) []verifyIssueKey {
+				return []verifyIssueKey{{Kind: VerifyTxHashIndex, Height: 4, TxHash: blocks[4].Transactions()[1].Hash()}}
+			},
+		},
+		{
+			name: "transaction hash index points to another location",
+			corrupt: func(t *testing.T, s *PebbleStorage, blocks []*types.Block) {
+				encoded, err := EncodeTxLocation(&TxLocation{BlockHeight: 1, TxIndex: 0, BlockHash: blocks[1].Hash()})
+				if err != nil {
+					t.Fatalf("EncodeTxLocation() error = %v", err)
+				}
+				setVerifyKey(t, s, TransactionHashIndexKey(blocks[2].Transactions()[1].Hash()), encoded)
+			},
+			want: func(blocks []*types.Block) []verifyIssueKey {
+				return []verifyIssueKey{{Kind: VerifyTxHashIndex, Height: 2, TxHash: blocks[2].Transactions()[1].Hash()}}
+			},
+		},
+		{
+			name: "latest height below stored blocks",
+			corrupt: func(t *testing.T, s *PebbleStorage, blocks []*types.Block) {
+				setVerifyKey(t, s, LatestHeightKey(), EncodeUint64(3))
+			},
+			want: func(blocks []*types.Block) []verifyIssueKey {
+				return []verifyIssueKey{{Kind: VerifyLatestHeight, Height: verifyTestBlocks}}
+			},
+		},
+		{
+			name: "several problems",
+			corrupt: func(t *testing.T, s *PebbleStorage, blocks []*types.Block) {
+				deleteVerifyKey(t, s, BlockHashIndexKey(blocks[1].Hash()))
+				deleteVerifyKey(t, s, TransactionHashIndexKey(blocks[5].Transactions()[0].Hash()))
+				deleteVerifyKey(t, s, LatestHeightKey())
+			},
+			want: func(blocks []*types.Block) []verifyIssueKey {
+				return []verifyIssueKey{
+					{Kind: VerifyBlockHashIndex, Height: 1},
+					{Kind: VerifyTxHashIndex, Height: 5, TxHash: blocks[5].Transactions()[0].Hash()},
+					{Kind: VerifyLatestHeight, Height: verifyTestBlocks},
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, blocks := setupVerifyStorage(t)
+			tt.corrupt(t, s, blocks)
+
+			report, err := s.Verify(context.Background(), 1, verifyTestBlocks)
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+
+			want := tt.want(blocks)
+			if got := verifyIssueKeys(report.Issues); !reflect.DeepEqual(got, want) {
+				t.Errorf("Verify() issues = %+v, want %+v", report.Issues, want)
+			}
+			if report.OK() != (len(want) == 0) {
+				t.Errorf("OK() = %v, want %v", report.OK(), len(want) == 0)
+			}
+			if report.BlocksChecked != verifyTestBlocks {
+				t.Errorf("BlocksChecked = %d, want %d", report.BlocksChecked, verifyTestBlocks)
+			}
+			if report.MaxHeight != verifyTestBlocks {
+				t.Errorf("MaxHeight = %d, want %d", report.MaxHeight, verifyTestBlocks)
+			}
+		})
+	}
+}
+
+func TestPebbleStorage_VerifyMissingBlocks(t *testing.T) {
+	s, _ := setupVerifyStorage(t)
+
+	// Heights that were never indexed are counted but are not inconsistencies
+	report, err := s.Verify(context.Background(), 0, verifyTestBlocks+2)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if !report.OK() {
+		t.Errorf("Verify() issues = %+v, want none", report.Issues)
+	}
+	if report.MissingBlocks != 3 {
+		t.Errorf("MissingBlocks = %d, want 3", report.MissingBlocks)
+	}
+	if report.TransactionsChecked != 2*verifyTestBlocks {
+		t.Errorf("TransactionsChecked = %d, want %d", report.TransactionsChecked, 2*verifyTestBlocks)
+	}
+
+	if _, err := s.Verify(context.Background(), 3, 2); err == nil {
+		t.Error("Verify() with from > to should fail")
+	}
+}
+
+func setVerifyKey(t *testing.T, s *PebbleStorage, key, value []byte) {
+	t.Helper()
+	if err := s.db.Set(key, value, pebble.Sync); err != nil {
+		t.Fatalf("Set(%s) error = %v", key, err)
+	}
+}
+
+func deleteVerifyKey(t *testing.T, s *PebbleStorage, key []byte) {
+	t.Helper()
+	if err := s.db.Delete(key, pebble.Sync); err != nil {
+		t.Fatalf("Delete(%s) error = %v", key, err)
+	}
+}
@@ -35,7 +35,7 @@ func (s *PebbleStorage) GetWBFTBlockExtra(ctx context.Context, blockNumber uint6
 
 	var extra WBFTBlockExtra
 	if err := json.Unmarshal(value, &extra); err != nil {
-		return nil, fmt.Errorf("failed to decode WBFT block extra: %w", err)
+		return nil, fmt.Errorf("%w WBFT block extra: %w", ErrDecodeFailed, err)
 	}
 
 	return &extra, nil
@@ -60,7 +60,7 @@ func (s *PebbleStorage) GetWBFTBlockExtraByHash(ctx context.Context, blockHash c
 
 	blockNumber, err := DecodeUint64(blockNumValue)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode block number: %w", err)
+		return nil, fmt.Errorf("%w block number: %w", ErrDecodeFailed, err)
 	}
 
 	return s.GetWBFTBlockExtra(ctx, blockNumber)
@@ -84,7 +84,7 @@ func (s *PebbleStorage) GetEpochInfo(ctx context.Context, epochNumber uint64) (*
 
 	var epochInfo EpochInfo
 	if err := json.Unmarshal(value, &epochInfo); err != nil {
-		return nil, fmt.Errorf("failed to decode epoch info: %w", err)
+		return nil, fmt.Errorf("%w epoch info: %w", ErrDecodeFailed, err)
 	}
 
 	return &epochInfo, nil
@@ -108,7 +108,7 @@ func (s *PebbleStorage) GetLatestEpochInfo(ctx context.Context) (*EpochInfo, err
 
 	epochNumber, err := DecodeUint64(value)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode epoch number: %w", err)
+		return nil, fmt.Errorf("%w epoch number: %w", ErrDecodeFailed, err)
 	}
 
 	return s.GetEpochInfo(ctx, epochNumber)
@@ -140,7 +140,7 @@ func (s *PebbleStorage) GetValidatorSigningStats(ctx context.Context, validatorA
 
 	var stats ValidatorSigningStats
 	if err := json.Unmarshal(value, &stats); err != nil {
-		return nil, fmt.Errorf("failed to decode validator signing stats: %w", err)
+		return nil, fmt.Errorf("%w validator signing stats: %w", ErrDecodeFailed, err)
 	}
 
 	// Compute proposer stats from block headers
@@ -194,7 +194,7 @@ func (s *PebbleStorage) GetAllValidatorsSigningStats(ctx context.Context, fromBl
 	for iter.First(); iter.Valid(); iter.Next() {
 		var activity ValidatorSigningActivity
 		if err := json.Unmarshal(iter.Value(), &activity); err != nil {
-			s.logger.Warn("failed to decode validator activity", zap.Error(err))
+			s.loggerFor(ctx).Warn("failed to decode validator activity", zap.Error(err))
 			continue
 		}
 
@@ -305,7 +305,7 @@ func (s *PebbleStorage) GetValidatorSigningActivity(ctx context.Context, validat
 	for iter.First(); iter.Valid(); iter.Next() {
 		var activity ValidatorSigningActivity
 		if err := json.Unmarshal(iter.Value(), &activity); err != nil {
-			s.logger.Warn("failed to decode validator activity", zap.Error(err))
+			s.loggerFor(ctx).Warn("failed to decode validator activity", zap.Error(err))
 			continue
 		}
 
@@ -430,7 +430,7 @@ func (s *PebbleStorage) GetEpochsList(ctx context.Context, limit, offset int) ([
 	for iter.Last(); iter.Valid(); iter.Prev() {
 		var epochInfo EpochInfo
 		if err := json.Unmarshal(iter.Value(), &epochInfo); err != nil {
-			s.logger.Warn("failed to decode epoch info", zap.Error(err))
+			s.loggerFor(ctx).Warn("failed to decode epoch info", zap.Error(err))
 			continue
 		}
 		allEpochs = append(allEpochs, &epochInfo)
@@ -468,7 +468,7 @@ func (s *PebbleStorage) SaveWBFTBlockExtra(ctx context.Context, extra *WBFTBlock
 	// Encode to JSON
 	value, err := json.Marshal(extra)
 	if err != nil {
-		return fmt.Errorf("failed to encode WBFT block extra: %w", err)
+		return fmt.Errorf("%w WBFT block extra: %w", ErrEncodeFailed, err)
 	}
 
 	key := WBFTBlockExtraKey(extra.BlockNumber)
@@ -492,7 +492,7 @@ func (s *PebbleStorage) SaveEpochInfo(ctx context.Context, epochInfo *EpochInfo)
 	// Encode to JSON
 	value, err := json.Marshal(epochInfo)
 	if err != nil {
-		return fmt.Errorf("failed to encode epoch info: %w", err)
+		return fmt.Errorf("%w epoch info: %w", ErrEncodeFailed, err)
 	}
 
 	// Save epoch info
@@ -528,7 +528,7 @@ func (s *PebbleStorage) UpdateValidatorSigningStats(ctx context.Context, blockNu
 		activityKey := WBFTValidatorActivityKey(activity.ValidatorAddress, activity.BlockNumber)
 		activityValue, err := json.Marshal(activity)
 		if err != nil {
-			return fmt.Errorf("failed to encode validator activity: %w", err)
+			return fmt.Errorf("%w validator activity: %w", ErrEncodeFailed, err)
 		}
 		if err := batch.Set(activityKey, activityValue, pebble.Sync); err != nil {
 			return fmt.Errorf("failed to save validator activity: %w", err)
@@ -0,0 +1,161 @@
+package storage
+
+import (
+	"container/list"
+	"math/big"
+	"strconv"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// readCache is a size-bounded LRU of decoded objects served by the read path.
+// Writers remove the entries they change after the write lands. A nil
+// *readCache is valid and caches nothing.
+type readCache struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[string]*list.Element
+	order    *list.List
+
+	// generation changes on every invalidation, so a read that raced a write
+	// does not cache the value it read before the write
+	generation uint64
+
+	hits   uint64
+	misses uint64
+}
+
+type readCacheEntry struct {
+	key   string
+	value interface{}
+}
+
+// newReadCache returns a cache holding up to capacity entries, or nil if
+// capacity is not positive
+func newReadCache(capacity int) *readCache {
+	if capacity <= 0 {
+		return nil
+	}
+	return &readCache{
+		capacity: capacity,
+		items:    make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// get returns the cached value for key and the current generation, which the
+// caller passes to add after reading the value from the database on a miss
+func (c *readCache) get(key string) (interface{}, uint64, bool) {
+	if c == nil {
+		return nil, 0, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		c.order.MoveToFront(elem)
+		c.hits++
+		return elem.Value.(*readCacheEntry).value, c.generation, true
+	}
+	c.misses++
+	return nil, c.generation, false
+}
+
+// add stores value under key unless an invalidation happened since generation
+func (c *readCache) add(key string, value interface{}, generation uint64) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+	if elem, ok := c.items[key]; ok {
+		elem.Value.(*readCacheEntry).value = value
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.items[key] = c.order.PushFront(&readCacheEntry{key: key, value: value})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*readCacheEntry).key)
+	}
+}
+
+// remove drops the given keys
+func (c *readCache) remove(keys ...string) {
+	if c == nil || len(keys) == 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	for _, key := range keys {
+		if elem, ok := c.items[key]; ok {
+			c.order.Remove(elem)
+			delete(c.items, key)
+		}
+	}
+}
+
+// purge drops every entry, as when the database under the cache is replaced
+func (c *readCache) purge() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	c.items = make(map[string]*list.Element)
+	c.order.Init()
+}
+
+// stats returns the hit and miss counts
+func (c *readCache) stats() (hits, misses uint64) {
+	if c == nil {
+		return 0, 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.misses
+}
+
+func blockCacheKey(height uint64) string {
+	return "block/" + strconv.FormatUint(height, 10)
+}
+
+func blockHashCacheKey(hash common.Hash) string {
+	return "blockhash/" + string(hash[:])
+}
+
+func receiptCacheKey(hash common.Hash) string {
+	return "receipt/" + string(hash[:])
+}
+
+// copyReceipt returns a copy of a cached receipt that callers may modify
+// without affecting the cache
+func copyReceipt(receipt *types.Receipt) *types.Receipt {
+	cpy := *receipt
+	for _, n := range []**big.Int{&cpy.EffectiveGasPrice, &cpy.BlobGasPrice, &cpy.BlockNumber} {
+		if *n != nil {
+			*n = new(big.Int).Set(*n)
+		}
+	}
+	if receipt.Logs != nil {
+		cpy.Logs = make([]*types.Log, len(receipt.Logs))
+		for i, log := range receipt.Logs {
+			if log != nil {
+				logCopy := *log
+				cpy.Logs[i] = &logCopy
+			}
+		}
+	}
+	return &cpy
+}
@@ -0,0 +1,281 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func setupCachedStorage(t *testing.T, size int) *PebbleStorage {
+	t.Helper()
+
+	cfg := DefaultConfig(t.TempDir())
+	cfg.ReadCacheSize = size
+	storage, err := NewPebbleStorage(cfg)
+	if err != nil {
+		t.Fatalf("NewPebbleStorage() error = %v", err)
+	}
+	t.Cleanup(func() { storage.Close() })
+	return storage
+}
+
+// dbReads reports how many cache misses, and therefore database reads, the
+// read path has performed
+func dbReads(s *PebbleStorage) uint64 {
+	_, misses := s.readCache.stats()
+	return misses
+}
+
+func TestReadCache_BlockHitsSkipDatabase(t *testing.T) {
+	s := setupCachedStorage(t, 16)
+	ctx := context.Background()
+
+	block := createTestBlock(7)
+	if err := s.SetBlock(ctx, block); err != nil {
+		t.Fatalf("SetBlock() error = %v", err)
+	}
+
+	if _, err := s.GetBlock(ctx, 7); err != nil {
+		t.Fatalf("GetBlock() error = %v", err)
+	}
+	if _, err := s.GetBlockByHash(ctx, block.Hash()); err != nil {
+		t.Fatalf("GetBlockByHash() error = %v", err)
+	}
+	reads := dbReads(s)
+
+	// Remove the underlying keys; cached entries must still be served
+	if err := s.db.Delete(BlockKey(7), pebble.Sync); err != nil {
+		t.Fatalf("db.Delete() error = %v", err)
+	}
+	if err := s.db.Delete(BlockHashIndexKey(block.Hash()), pebble.Sync); err != nil {
+		t.Fatalf("db.Delete() error = %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		got, err := s.GetBlock(ctx, 7)
+		if err != nil {
+			t.Fatalf("GetBlock() error = %v", err)
+		}
+		if got.Hash() != block.Hash() {
+			t.Errorf("GetBlock() hash = %s, want %s", got.Hash().Hex(), block.Hash().Hex())
+		}
+		got, err = s.GetBlockByHash(ctx, block.Hash())
+		if err != nil {
+			t.Fatalf("GetBlockByHash() error = %v", err)
+		}
+		if got.NumberU64() != 7 {
+			t.Errorf("GetBlockByHash() number = %d, want 7", got.NumberU64())
+		}
+	}
+	if got := dbReads(s); got != reads {
+		t.Errorf("database reads = %d, want %d", got, reads)
+	}
+}
+
+func TestReadCache_ReceiptHitsReturnCopies(t *testing.T) {
+	s := setupCachedStorage(t, 16)
+	ctx := context.Background()
+
+	receipt := createTestReceipt(common.HexToHash("0xabc"), 21000)
+	receipt.ContractAddress = common.HexToAddress("0x1234567890123456789012345678901234567890")
+	receipt.EffectiveGasPrice = big.NewInt(100)
+	if err := s.SetReceipt(ctx, receipt); err != nil {
+		t.Fatalf("SetReceipt() error = %v", err)
+	}
+
+	first, err := s.GetReceipt(ctx, receipt.TxHash)
+	if err != nil {
+		t.Fatalf("GetReceipt() error = %v", err)
+	}
+	reads := dbReads(s)
+
+	// Mutating a returned receipt must not leak into the cache
+	first.CumulativeGasUsed = 1
+	if first.EffectiveGasPrice != nil {
+		first.EffectiveGasPrice.SetInt64(1)
+	}
+
+	second, err := s.GetReceipt(ctx, receipt.TxHash)
+	if err != nil {
+		t.Fatalf("GetReceipt() error = %v", err)
+	}
+	if got := dbReads(s); got != reads {
+		t.Errorf("database reads = %d, want %d", got, reads)
+	}
+	if second.CumulativeGasUsed != 21000 {
+		t.Errorf("CumulativeGasUsed = %d, want 21000", second.CumulativeGasUsed)
+	}
+	if second.EffectiveGasPrice != nil && second.EffectiveGasPrice.Int64() == 1 {
+		t.Error("EffectiveGasPrice shared with an earlier caller")
+	}
+	if second.TxHash != receipt.TxHash || second.ContractAddress != receipt.ContractAddress {
+		t.Errorf("GetReceipt() = %s/%s, want %s/%s", second.TxHash.Hex(), second.ContractAddress.Hex(),
+			receipt.TxHash.Hex(), receipt.ContractAddress.Hex())
+	}
+}
+
+func TestReadCache_Invalidation(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("DeleteBlock", func(t *testing.T) {
+		s := setupCachedStorage(t, 16)
+		block := createTestBlock(3)
+		if err := s.SetBlock(ctx, block); err != nil {
+			t.Fatalf("SetBlock() error = %v", err)
+		}
+		if _, err := s.GetBlockByHash(ctx, block.Hash()); err != nil {
+			t.Fatalf("GetBlockByHash() error = %v", err)
+		}
+
+		if err := s.DeleteBlock(ctx, 3); err != nil {
+			t.Fatalf("DeleteBlock() error = %v", err)
+		}
+		if _, err := s.GetBlock(ctx, 3); !errors.Is(err, ErrNotFound) {
+			t.Errorf("GetBlock() error = %v, want ErrNotFound", err)
+		}
+		if _, err := s.GetBlockByHash(ctx, block.Hash()); !errors.Is(err, ErrNotFound) {
+			t.Errorf("GetBlockByHash() error = %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("BatchDeleteBlock", func(t *testing.T) {
+		s := setupCachedStorage(t, 16)
+		if err := s.SetBlock(ctx, createTestBlock(4)); err != nil {
+			t.Fatalf("SetBlock() error = %v", err)
+		}
+		if _, err := s.GetBlock(ctx, 4); err != nil {
+			t.Fatalf("GetBlock() error = %v", err)
+		}
+
+		batch := s.NewBatch()
+		defer batch.Close()
+		if err := batch.DeleteBlock(ctx, 4); err != nil {
+			t.Fatalf("batch.DeleteBlock() error = %v", err)
+		}
+		// Not committed yet, so the cached block is still current
+		if _, err := s.GetBlock(ctx, 4); err != nil {
+			t.Fatalf("GetBlock() before commit error = %v", err)
+		}
+		if err := batch.Commit(); err != nil {
+			t.Fatalf("batch.Commit() error = %v", err)
+		}
+		if _, err := s.GetBlock(ctx, 4); !errors.Is(err, ErrNotFound) {
+			t.Errorf("GetBlock() error = %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("SetBlockOverwrite", func(t *testing.T) {
+		s := setupCachedStorage(t, 16)
+		if err := s.SetBlock(ctx, createTestBlockWithMiner(5, common.HexToAddress("0x01"), 0, 100)); err != nil {
+			t.Fatalf("SetBlock() error = %v", err)
+		}
+		if _, err := s.GetBlock(ctx, 5); err != nil {
+			t.Fatalf("GetBlock() error = %v", err)
+		}
+
+		replacement := createTestBlockWithMiner(5, common.HexToAddress("0x02"), 0, 200)
+		if err := s.SetBlock(ctx, replacement); err != nil {
+			t.Fatalf("SetBlock() error = %v", err)
+		}
+		got, err := s.GetBlock(ctx, 5)
+		if err != nil {
+			t.Fatalf("GetBlock() error = %v", err)
+		}
+		if got.Hash() != replacement.Hash() {
+			t.Errorf("GetBlock() hash = %s, want %s", got.Hash().Hex(), replacement.Hash().Hex())
+		}
+	})
+
+	t.Run("SetReceiptOverwrite", func(t *testing.T) {
+		s := setupCachedStorage(t, 16)
+		hash := common.HexToHash("0xdef")
+		if err := s.SetReceipt(ctx, createTestReceipt(hash, 21000)); err != nil {
+			t.Fatalf("SetReceipt() error = %v", err)
+		}
+		if _, err := s.GetReceipt(ctx, hash); err != nil {
+			t.Fatalf("GetReceipt() error = %v", err)
+		}
+
+		batch := s.NewBatch()
+		defer batch.Close()
+		if err := batch.SetReceipt(ctx, createTestReceipt(hash, 42000)); err != nil {
+			t.Fatalf("batch.SetReceipt() error = %v", err)
+		}
+		if err := batch.Commit(); err != nil {
+			t.Fatalf("batch.Commit() error = %v", err)
+		}
+		got, err := s.GetReceipt(ctx, hash)
+		if err != nil {
+			t.Fatalf("GetReceipt() error = %v", err)
+		}
+		if got.CumulativeGasUsed != 42000 {
+			t.Errorf("CumulativeGasUsed = %d, want 42000", got.CumulativeGasUsed)
+		}
+	})
+}
+
+func TestReadCache_Eviction(t *testing.T) {
+	c := newReadCache(2)
+	c.add("a", 1, 0)
+	c.add("b", 2, 0)
+	c.get("a")
+	c.add("c", 3, 0)
+
+	if _, _, ok := c.get("b"); ok {
+		t.Error("least recently used entry was not evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, _, ok := c.get(key); !ok {
+			t.Errorf("entry %q evicted", key)
+		}
+	}
+
+	// A value read before an invalidation must not be cached after it
+	_, generation, _ := c.get("d")
+	c.remove("a")
+	c.add("d", 4, generation)
+	if _, _, ok := c.get("d"); ok {
+		t.Error("stale value cached across invalidation")
+	}
+}
+
+func TestReadCache_Disabled(t *testing.T) {
+	t.Run("ZeroSize", func(t *testing.T) {
+		s := setupCachedStorage(t, 0)
+		if s.readCache != nil {
+			t.Fatal("read cache enabled with size 0")
+		}
+		if err := s.SetBlock(context.Background(), createTestBlock(1)); err != nil {
+			t.Fatalf("SetBlock() error = %v", err)
+		}
+		if _, err := s.GetBlock(context.Background(), 1); err != nil {
+			t.Fatalf("GetBlock() error = %v", err)
+		}
+	})
+
+	t.Run("ReadOnly", func(t *testing.T) {
+		dir := t.TempDir()
+		cfg := DefaultConfig(dir)
+		s, err := NewPebbleStorage(cfg)
+		if err != nil {
+			t.Fatalf("NewPebbleStorage() error = %v", err)
+		}
+		s.Close()
+
+		cfg = DefaultConfig(dir)
+		cfg.ReadOnly = true
+		cfg.ReadCacheSize = 16
+		s, err = NewPebbleStorage(cfg)
+		if err != nil {
+			t.Fatalf("NewPebbleStorage() read-only error = %v", err)
+		}
+		defer s.Close()
+		if s.readCache != nil {
+			t.Error("read cache enabled in read-only mode")
+		}
+	})
+}
@@ -0,0 +1,26 @@
+package storage
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ReceiptRevertReason returns the indexed revert reason of a failed receipt.
+// It returns nil without an error when the transaction succeeded, r does not
+// index revert reasons, or none was indexed for the transaction.
+func ReceiptRevertReason(ctx context.Context, r Reader, receipt *types.Receipt) (*RevertReason, error) {
+	if receipt.Status != types.ReceiptStatusFailed {
+		return nil, nil
+	}
+	reader, ok := r.(RevertReasonReader)
+	if !ok {
+		return nil, nil
+	}
+	reason, err := reader.GetRevertReason(ctx, receipt.TxHash)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	return reason, err
+}
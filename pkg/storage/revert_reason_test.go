@@ -0,0 +1,39 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestReceiptRevertReason(t *testing.T) {
+	store, cleanup := setupTestStorage(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	failed := &types.Receipt{Status: types.ReceiptStatusFailed, TxHash: common.HexToHash("0x01")}
+	reason := &RevertReason{TxHash: failed.TxHash, Error: "execution reverted", Reason: "insufficient balance"}
+	if err := store.(RevertReasonWriter).SetRevertReason(ctx, reason); err != nil {
+		t.Fatalf("SetRevertReason() error = %v", err)
+	}
+
+	got, err := ReceiptRevertReason(ctx, store, failed)
+	if err != nil {
+		t.Fatalf("ReceiptRevertReason() error = %v", err)
+	}
+	if got == nil || got.Message() != "insufficient balance" {
+		t.Errorf("ReceiptRevertReason() = %+v, want the stored reason", got)
+	}
+
+	succeeded := &types.Receipt{Status: types.ReceiptStatusSuccessful, TxHash: failed.TxHash}
+	if got, err := ReceiptRevertReason(ctx, store, succeeded); got != nil || err != nil {
+		t.Errorf("ReceiptRevertReason(successful) = %+v, %v, want nil, nil", got, err)
+	}
+
+	unindexed := &types.Receipt{Status: types.ReceiptStatusFailed, TxHash: common.HexToHash("0x02")}
+	if got, err := ReceiptRevertReason(ctx, store, unindexed); got != nil || err != nil {
+		t.Errorf("ReceiptRevertReason(unindexed) = %+v, %v, want nil, nil", got, err)
+	}
+}
@@ -23,6 +23,14 @@ const (
 	prefixAddr         = "/index/addr/"
 	prefixBlockHash    = "/index/blockh/"
 	prefixContractAddr = "/data/contractaddr/"
+	// prefixOrphanBlocks holds blocks superseded by a reorg, kept for forensic analysis
+	prefixOrphanBlocks = "/data/orphan/blocks/"
+	// prefixRevertReason holds the revert output of failed transactions
+	prefixRevertReason = "/data/revert/"
+	// prefixHeaders holds each block's header apart from its body, for header-only reads
+	prefixHeaders = "/data/headers/"
+	// prefixUncles holds the uncle (ommer) headers of each block, when uncle indexing is enabled
+	prefixUncles = "/data/uncles/"
 
 	// System contracts data prefixes
 	prefixSysContracts    = "/data/syscontracts/"
@@ -57,7 +65,10 @@ const (
 	prefixIdxSysContracts    = "/index/syscontracts/"
 	prefixIdxMintMinter      = "/index/syscontracts/mint_minter/"
 	prefixIdxBurnBurner      = "/index/syscontracts/burn_burner/"
-	prefixIdxProposalStatus  = "/index/syscontracts/proposal_status/"
+	prefixIdxProposalStatus  = "/index/syscontracts/proposal_status_v2/"
+	// prefixIdxProposalStatusLegacy keyed proposals by their decimal ID, which
+	// iterates in lexicographic rather than numeric order
+	prefixIdxProposalStatusLegacy = "/index/syscontracts/proposal_status/"
 	prefixIdxBlacklistActive = "/index/syscontracts/blacklist_active/"
 	prefixIdxMinterActive    = "/index/syscontracts/minter_active/"
 	prefixIdxValidatorActive = "/index/syscontracts/validator_active/"
@@ -66,6 +77,7 @@ const (
 	// Address indexing data prefixes
 	prefixContractCreation = "/data/contract/creation/"
 	prefixInternalTx       = "/data/internal/"
+	prefixInternalTransfer = "/data/internal/transfer/"
 	prefixERC20Transfer    = "/data/erc20/transfer/"
 	prefixERC721Transfer   = "/data/erc721/transfer/"
 
@@ -75,6 +87,7 @@ const (
 	prefixIdxInternalFrom     = "/index/internal/from/"
 	prefixIdxInternalTo       = "/index/internal/to/"
 	prefixIdxInternalBlock    = "/index/internal/block/"
+	prefixIdxInternalXfer     = "/index/internal/transfer/"
 	prefixIdxERC20Token       = "/index/erc20/token/"
 	prefixIdxERC20From        = "/index/erc20/from/"
 	prefixIdxERC20To          = "/index/erc20/to/"
@@ -84,6 +97,12 @@ const (
 	prefixIdxERC721TokenOwner = "/index/erc721/tokenowner/"
 	prefixIdxERC721Owner      = "/index/erc721/owner/" // Reverse index: owner -> NFTs
 
+	// Address transaction aggregates
+	prefixAddrStats           = "/data/addrstats/"
+	prefixIdxAddrStatsBlock   = "/index/addrstats/block/"
+	prefixIdxAddrStatsEntries = "/index/addrstats/entries/"
+	prefixIdxAddrStatsPeer    = "/index/addrstats/peer/"
+
 	// Event log data prefixes
 	prefixLogs = "/data/logs/"
 
@@ -95,6 +114,9 @@ const (
 	prefixIdxLogsTopic3 = "/index/logs/topic3/"
 	prefixIdxLogsBlock  = "/index/logs/block/"
 
+	// Opt-in event log index by (address, topic0)
+	prefixIdxLogsAddrTopic0 = "/index/logs/addrtopic0/"
+
 	// ABI data prefixes
 	prefixABI = "/data/abi/"
 
@@ -202,6 +224,16 @@ const (
 	keyBlockCount       = "/meta/bc"
 	keyTransactionCount = "/meta/tc"
 	keyLatestEpoch      = "/meta/wbft/latest_epoch"
+	keyChainID          = "/meta/chainid"
+	keyChainHead        = "/meta/chainhead"
+	keyStateSnapshot    = "/meta/statesnapshot"
+	keyLastCompaction   = "/meta/lastcompaction"
+	// keyMintBurnMigrated marks mint and burn records as keyed by their log
+	// position and indexed by minter and burner
+	keyMintBurnMigrated = "/meta/mintburnv2"
+
+	// prefixFailedBlock holds dead-letter records of blocks that failed to index
+	prefixFailedBlock = "/meta/failed/"
 )
 
 // LatestHeightKey returns the key for storing latest indexed height
@@ -209,18 +241,89 @@ func LatestHeightKey() []byte {
 	return []byte(keyLatestHeight)
 }
 
+// ChainIDKey returns the key for storing the indexed chain's ID
+func ChainIDKey() []byte {
+	return []byte(keyChainID)
+}
+
+// ChainHeadKey returns the key for storing the last observed chain head height
+func ChainHeadKey() []byte {
+	return []byte(keyChainHead)
+}
+
+// StateSnapshotKey returns the key for storing the height of the applied state snapshot
+func StateSnapshotKey() []byte {
+	return []byte(keyStateSnapshot)
+}
+
+// LastCompactionKey returns the key for storing when the last full compaction finished
+func LastCompactionKey() []byte {
+	return []byte(keyLastCompaction)
+}
+
+// FailedBlockKey returns the key for the dead-letter record of a block
+// Format: /meta/failed/{height:020d}
+func FailedBlockKey(height uint64) []byte {
+	return []byte(fmt.Sprintf("%s%020d", prefixFailedBlock, height))
+}
+
+// FailedBlockKeyPrefix returns the prefix for all dead-letter records
+func FailedBlockKeyPrefix() []byte {
+	return []byte(prefixFailedBlock)
+}
+
+// OrphanBlockKey returns the key for a block orphaned by RollbackToHeight
+// Format: /data/orphan/blocks/{hash}
+func OrphanBlockKey(hash common.Hash) []byte {
+	return []byte(fmt.Sprintf("%s%s", prefixOrphanBlocks, hash.Hex()))
+}
+
+// OrphanBlockKeyPrefix returns the prefix for all orphaned blocks
+func OrphanBlockKeyPrefix() []byte {
+	return []byte(prefixOrphanBlocks)
+}
+
+// RevertReasonKey returns the key for the revert reason of a failed transaction
+// Format: /data/revert/{txHash}
+func RevertReasonKey(txHash common.Hash) []byte {
+	return []byte(fmt.Sprintf("%s%s", prefixRevertReason, txHash.Hex()))
+}
+
 // BlockKey returns the key for storing a block at given height
 // Format: /data/blocks/{height}
 func BlockKey(height uint64) []byte {
 	return []byte(fmt.Sprintf("%s%d", prefixBlocks, height))
 }
 
+// HeaderKey returns the key for storing the header of the block at given height
+// Format: /data/headers/{height}
+func HeaderKey(height uint64) []byte {
+	return []byte(fmt.Sprintf("%s%d", prefixHeaders, height))
+}
+
+// UncleKey returns the key for the index-th uncle header of the block at given height
+// Format: /data/uncles/{height}/{index}
+func UncleKey(blockHeight uint64, index int) []byte {
+	return []byte(fmt.Sprintf("%s%020d/%d", prefixUncles, blockHeight, index))
+}
+
+// UncleKeyPrefix returns the prefix for the uncle headers of the block at given height
+func UncleKeyPrefix(blockHeight uint64) []byte {
+	return []byte(fmt.Sprintf("%s%020d/", prefixUncles, blockHeight))
+}
+
 // TransactionKey returns the key for storing a transaction
 // Format: /data/txs/{height}/{index}
 func TransactionKey(height uint64, txIndex uint64) []byte {
 	return []byte(fmt.Sprintf("%s%d/%d", prefixTxs, height, txIndex))
 }
 
+// TransactionKeyPrefix returns the key prefix for all transactions of a block
+// Format: /data/txs/{height}/
+func TransactionKeyPrefix(height uint64) []byte {
+	return []byte(fmt.Sprintf("%s%d/", prefixTxs, height))
+}
+
 // ReceiptKey returns the key for storing a transaction receipt
 // Format: /data/receipts/{txhash}
 func ReceiptKey(txHash common.Hash) []byte {
@@ -308,7 +411,7 @@ func EncodeUint64(n uint64) []byte {
 // DecodeUint64 decodes bytes to uint64 in big-endian format
 func DecodeUint64(data []byte) (uint64, error) {
 	if len(data) != 8 {
-		return 0, fmt.Errorf("invalid uint64 data length: %d", len(data))
+		return 0, fmt.Errorf("%w: invalid uint64 data length: %d", ErrCorrupted, len(data))
 	}
 	return binary.BigEndian.Uint64(data), nil
 }
@@ -339,6 +442,13 @@ func BlockTimestampKeyPrefix() []byte {
 	return []byte("/index/time/")
 }
 
+// HeightTimestampKey returns the reverse timestamp index key for a block height
+// Format: /index/blocktime/{height}
+// Used to locate and remove a stale timestamp entry when a height is re-indexed
+func HeightTimestampKey(height uint64) []byte {
+	return []byte(fmt.Sprintf("/index/blocktime/%020d", height))
+}
+
 // AddressBalanceKey returns the key for an address balance at a specific block
 // Format: /index/balance/{address}/history/{seq}
 // Uses zero-padded fixed-width format for proper lexicographic sorting
@@ -357,6 +467,31 @@ func AddressBalanceKeyPrefix(addr common.Address) []byte {
 	return []byte(fmt.Sprintf("/index/balance/%s/history/", addr.Hex()))
 }
 
+// AddressStatsKey returns the key for the running transaction aggregates of an address
+// Format: /data/addrstats/{address}
+func AddressStatsKey(addr common.Address) []byte {
+	return []byte(fmt.Sprintf("%s%s", prefixAddrStats, addr.Hex()))
+}
+
+// AddressStatsBlockKey returns the key marking a block as counted in address aggregates
+// Format: /index/addrstats/block/{height}
+func AddressStatsBlockKey(height uint64) []byte {
+	return []byte(fmt.Sprintf("%s%020d", prefixIdxAddrStatsBlock, height))
+}
+
+// AddressStatsEntriesKey returns the key for what the transactions of the block
+// counted at height added to address aggregates
+// Format: /index/addrstats/entries/{height}
+func AddressStatsEntriesKey(height uint64) []byte {
+	return []byte(fmt.Sprintf("%s%020d", prefixIdxAddrStatsEntries, height))
+}
+
+// AddressCounterpartyKey returns the key recording that addr has transacted with peer
+// Format: /index/addrstats/peer/{address}/{peer}
+func AddressCounterpartyKey(addr, peer common.Address) []byte {
+	return []byte(fmt.Sprintf("%s%s/%s", prefixIdxAddrStatsPeer, addr.Hex(), peer.Hex()))
+}
+
 // BlockCountKey returns the key for total block count
 func BlockCountKey() []byte {
 	return []byte(keyBlockCount)
@@ -367,6 +502,12 @@ func TransactionCountKey() []byte {
 	return []byte(keyTransactionCount)
 }
 
+// MintBurnMigratedKey returns the key marking the mint and burn record
+// migration as done
+func MintBurnMigratedKey() []byte {
+	return []byte(keyMintBurnMigrated)
+}
+
 // HasPrefix checks if key has the given prefix
 func HasPrefix(key, prefix []byte) bool {
 	return bytes.HasPrefix(key, prefix)
@@ -392,13 +533,13 @@ func IsIndexKey(key []byte) bool {
 // MintEventKey returns the key for storing a mint event
 // Format: /data/syscontracts/mint/{blockNumber}/{txIndex}/{logIndex}
 func MintEventKey(blockNumber, txIndex, logIndex uint64) []byte {
-	return []byte(fmt.Sprintf("%s%020d/%d/%d", prefixSysMint, blockNumber, txIndex, logIndex))
+	return []byte(fmt.Sprintf("%s%020d/%010d/%010d", prefixSysMint, blockNumber, txIndex, logIndex))
 }
 
 // BurnEventKey returns the key for storing a burn event
 // Format: /data/syscontracts/burn/{blockNumber}/{txIndex}/{logIndex}
 func BurnEventKey(blockNumber, txIndex, logIndex uint64) []byte {
-	return []byte(fmt.Sprintf("%s%020d/%d/%d", prefixSysBurn, blockNumber, txIndex, logIndex))
+	return []byte(fmt.Sprintf("%s%020d/%010d/%010d", prefixSysBurn, blockNumber, txIndex, logIndex))
 }
 
 // MinterConfigEventKey returns the key for storing a minter config event
@@ -491,21 +632,35 @@ func AuthorizedAccountEventKeyPrefix(contract common.Address) []byte {
 // System contract index key functions
 
 // MintMinterIndexKey returns the index key for mints by minter
-// Format: /index/syscontracts/mint_minter/{minter}/{blockNumber}
-func MintMinterIndexKey(minter common.Address, blockNumber uint64) []byte {
-	return []byte(fmt.Sprintf("%s%s/%020d", prefixIdxMintMinter, minter.Hex(), blockNumber))
+// Format: /index/syscontracts/mint_minter/{minter}/{blockNumber}/{txIndex}/{logIndex}
+func MintMinterIndexKey(minter common.Address, blockNumber, txIndex, logIndex uint64) []byte {
+	return []byte(fmt.Sprintf("%s%s/%020d/%010d/%010d", prefixIdxMintMinter, minter.Hex(), blockNumber, txIndex, logIndex))
 }
 
 // BurnBurnerIndexKey returns the index key for burns by burner
-// Format: /index/syscontracts/burn_burner/{burner}/{blockNumber}
-func BurnBurnerIndexKey(burner common.Address, blockNumber uint64) []byte {
-	return []byte(fmt.Sprintf("%s%s/%020d", prefixIdxBurnBurner, burner.Hex(), blockNumber))
+// Format: /index/syscontracts/burn_burner/{burner}/{blockNumber}/{txIndex}/{logIndex}
+func BurnBurnerIndexKey(burner common.Address, blockNumber, txIndex, logIndex uint64) []byte {
+	return []byte(fmt.Sprintf("%s%s/%020d/%010d/%010d", prefixIdxBurnBurner, burner.Hex(), blockNumber, txIndex, logIndex))
 }
 
-// ProposalStatusIndexKey returns the index key for proposals by status
-// Format: /index/syscontracts/proposal_status/{contract}/{status}/{proposalId}
-func ProposalStatusIndexKey(contract common.Address, status uint8, proposalId string) []byte {
-	return []byte(fmt.Sprintf("%s%s/%d/%s", prefixIdxProposalStatus, contract.Hex(), status, proposalId))
+// ProposalStatusIndexKey returns the index key for proposals by status. The
+// proposal ID is encoded as 32-byte big-endian hex so keys sort numerically.
+// Format: /index/syscontracts/proposal_status_v2/{contract}/{status}/{proposalId:064x}
+func ProposalStatusIndexKey(contract common.Address, status uint8, proposalId *big.Int) []byte {
+	return []byte(fmt.Sprintf("%s%s/%d/%064x", prefixIdxProposalStatus, contract.Hex(), status, proposalId))
+}
+
+// ParseProposalStatusIndexKey extracts the proposal ID from a proposal status index key
+func ParseProposalStatusIndexKey(key []byte) (*big.Int, error) {
+	idx := bytes.LastIndexByte(key, '/')
+	if !bytes.HasPrefix(key, []byte(prefixIdxProposalStatus)) || idx < 0 || len(key)-idx-1 != 64 {
+		return nil, fmt.Errorf("invalid proposal status index key: %q", key)
+	}
+	proposalID, ok := new(big.Int).SetString(string(key[idx+1:]), 16)
+	if !ok {
+		return nil, fmt.Errorf("invalid proposal ID in status index key: %q", key)
+	}
+	return proposalID, nil
 }
 
 // BlacklistActiveIndexKey returns the index key for active blacklist
@@ -598,6 +753,12 @@ func ProposalStatusIndexKeyPrefix(contract common.Address, status uint8) []byte
 	return []byte(fmt.Sprintf("%s%s/%d/", prefixIdxProposalStatus, contract.Hex(), status))
 }
 
+// LegacyProposalStatusIndexKeyPrefix returns the prefix of the decimal-keyed
+// proposal status index by contract and status
+func LegacyProposalStatusIndexKeyPrefix(contract common.Address, status uint8) []byte {
+	return []byte(fmt.Sprintf("%s%s/%d/", prefixIdxProposalStatusLegacy, contract.Hex(), status))
+}
+
 // BlacklistActiveIndexKeyPrefix returns the prefix for all active blacklist indexes
 func BlacklistActiveIndexKeyPrefix() []byte {
 	return []byte(prefixIdxBlacklistActive)
@@ -761,6 +922,25 @@ func InternalTxToIndexKeyPrefix(to common.Address) []byte {
 	return []byte(fmt.Sprintf("%s%s/", prefixIdxInternalTo, to.Hex()))
 }
 
+// Internal Transfer Keys
+
+// InternalTransferDataKey returns the key for storing an internal ETH transfer
+// Format: /data/internal/transfer/{txHash}/{index}
+func InternalTransferDataKey(txHash common.Hash, index int) []byte {
+	return []byte(fmt.Sprintf("%s%s/%06d", prefixInternalTransfer, txHash.Hex(), index))
+}
+
+// InternalTransferKey returns the per-address index key for internal ETH transfers
+// Format: /index/internal/transfer/{address}/{seq}
+func InternalTransferKey(addr common.Address, seq uint64) []byte {
+	return []byte(fmt.Sprintf("%s%s/%020d", prefixIdxInternalXfer, addr.Hex(), seq))
+}
+
+// InternalTransferKeyPrefix returns the prefix for internal ETH transfers of an address
+func InternalTransferKeyPrefix(addr common.Address) []byte {
+	return []byte(fmt.Sprintf("%s%s/", prefixIdxInternalXfer, addr.Hex()))
+}
+
 // ERC20 Transfer Keys
 
 // ERC20TransferKey returns the key for storing ERC20 transfer data
@@ -873,6 +1053,11 @@ func LogKeyPrefix() []byte {
 	return []byte(prefixLogs)
 }
 
+// LogTxKeyPrefix returns the prefix for log data of one transaction in a block
+func LogTxKeyPrefix(blockNumber uint64, txIndex uint) []byte {
+	return []byte(fmt.Sprintf("%s%020d/%06d/", prefixLogs, blockNumber, txIndex))
+}
+
 // LogBlockKeyPrefix returns the prefix for logs in a specific block
 func LogBlockKeyPrefix(blockNumber uint64) []byte {
 	return []byte(fmt.Sprintf("%s%020d/", prefixLogs, blockNumber))
@@ -889,6 +1074,17 @@ func LogAddressIndexKeyPrefix(address common.Address) []byte {
 	return []byte(fmt.Sprintf("%s%s/", prefixIdxLogsAddr, address.Hex()))
 }
 
+// LogAddressTopicIndexKey returns the index key for logs by contract address and topic 0
+// Format: /index/logs/addrtopic0/{address}/{topic0}/{blockNumber}/{txIndex}/{logIndex}
+func LogAddressTopicIndexKey(address common.Address, topic0 common.Hash, blockNumber uint64, txIndex uint, logIndex uint) []byte {
+	return []byte(fmt.Sprintf("%s%s/%s/%020d/%06d/%06d", prefixIdxLogsAddrTopic0, address.Hex(), topic0.Hex(), blockNumber, txIndex, logIndex))
+}
+
+// LogAddressTopicIndexKeyPrefix returns the prefix for logs by contract address and topic 0
+func LogAddressTopicIndexKeyPrefix(address common.Address, topic0 common.Hash) []byte {
+	return []byte(fmt.Sprintf("%s%s/%s/", prefixIdxLogsAddrTopic0, address.Hex(), topic0.Hex()))
+}
+
 // LogTopic0IndexKey returns the index key for logs by topic 0
 // Format: /index/logs/topic0/{topic}/{blockNumber}/{txIndex}/{logIndex}
 func LogTopic0IndexKey(topic common.Hash, blockNumber uint64, txIndex uint, logIndex uint) []byte {
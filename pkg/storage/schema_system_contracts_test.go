@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"math/big"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -91,7 +92,7 @@ func TestDepositMintProposalKey(t *testing.T) {
 func TestMintMinterIndexKey(t *testing.T) {
 	minter := common.HexToAddress("0xMINTER1234567890123456789012345678901234")
 
-	key := MintMinterIndexKey(minter, 12345)
+	key := MintMinterIndexKey(minter, 12345, 1, 2)
 	assert.NotNil(t, key)
 	assert.True(t, len(key) > 0)
 }
@@ -99,7 +100,7 @@ func TestMintMinterIndexKey(t *testing.T) {
 func TestBurnBurnerIndexKey(t *testing.T) {
 	burner := common.HexToAddress("0xBURNER1234567890123456789012345678901234")
 
-	key := BurnBurnerIndexKey(burner, 12345)
+	key := BurnBurnerIndexKey(burner, 12345, 1, 2)
 	assert.NotNil(t, key)
 	assert.True(t, len(key) > 0)
 }
@@ -107,9 +108,16 @@ func TestBurnBurnerIndexKey(t *testing.T) {
 func TestProposalStatusIndexKey(t *testing.T) {
 	contract := common.HexToAddress("0xCONTRACT123456789012345678901234567890")
 
-	key := ProposalStatusIndexKey(contract, uint8(ProposalStatusVoting), "42")
+	key := ProposalStatusIndexKey(contract, uint8(ProposalStatusVoting), big.NewInt(42))
 	assert.NotNil(t, key)
 	assert.True(t, len(key) > 0)
+
+	proposalID, err := ParseProposalStatusIndexKey(key)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), proposalID.Int64())
+
+	// Fixed-width IDs keep keys in numeric order
+	assert.Less(t, string(ProposalStatusIndexKey(contract, 1, big.NewInt(2))), string(ProposalStatusIndexKey(contract, 1, big.NewInt(10))))
 }
 
 func TestBlacklistActiveIndexKey(t *testing.T) {
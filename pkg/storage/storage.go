@@ -3,6 +3,9 @@ package storage
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math/big"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -30,6 +33,29 @@ var (
 
 	// ErrInvalidReceipt is returned when a receipt fails validation
 	ErrInvalidReceipt = errors.New("invalid receipt")
+
+	// ErrEncodeFailed is returned when a value cannot be encoded for storage
+	ErrEncodeFailed = errors.New("failed to encode")
+
+	// ErrDecodeFailed is returned when a stored value cannot be decoded
+	ErrDecodeFailed = errors.New("failed to decode")
+
+	// ErrCorrupted is returned when a stored value is structurally invalid, such
+	// as empty or of the wrong length; it is reported together with ErrDecodeFailed
+	// wherever the read path wraps the failure
+	ErrCorrupted = errors.New("corrupted data")
+
+	// ErrLogIndexDisabled is returned when querying the (address, topic0) log index
+	// while it is not enabled
+	ErrLogIndexDisabled = errors.New("address/topic0 log index is disabled")
+
+	// ErrUncleIndexDisabled is returned when querying uncle headers while uncle
+	// indexing is not enabled
+	ErrUncleIndexDisabled = errors.New("uncle index is disabled")
+
+	// ErrInvalidProposalTransition is returned when a proposal status update is
+	// not allowed from the proposal's current status
+	ErrInvalidProposalTransition = errors.New("invalid proposal status transition")
 )
 
 // Reader provides read-only access to blockchain data
@@ -50,6 +76,10 @@ type Reader interface {
 	// GetTransactions returns multiple transactions and their locations by hash (batch operation)
 	GetTransactions(ctx context.Context, hashes []common.Hash) ([]*types.Transaction, []*TxLocation, error)
 
+	// GetTransactionsByBlock returns the transactions of a block and their
+	// locations in index order, without decoding the block body
+	GetTransactionsByBlock(ctx context.Context, height uint64) ([]*types.Transaction, []*TxLocation, error)
+
 	// GetTransactionsByAddress returns transactions for an address with pagination
 	GetTransactionsByAddress(ctx context.Context, addr common.Address, limit, offset int) ([]common.Hash, error)
 
@@ -171,6 +201,19 @@ type Storage interface {
 	Compact(ctx context.Context, start, end []byte) error
 }
 
+// AddressTransactionEntry is a single (address, transaction) address index entry
+type AddressTransactionEntry struct {
+	Address common.Address
+	TxHash  common.Hash
+}
+
+// AddressIndexBatchWriter adds many address index entries in one call
+type AddressIndexBatchWriter interface {
+	// AddTransactionsToAddressIndex appends entries to their address indexes in
+	// order, reserving sequence numbers for all of them in one locked step
+	AddTransactionsToAddressIndex(ctx context.Context, entries []AddressTransactionEntry) error
+}
+
 // Batch provides atomic batch write operations
 type Batch interface {
 	Writer
@@ -202,23 +245,106 @@ type Config struct {
 	// WriteBuffer size in MB (default: 64)
 	WriteBuffer int
 
+	// WriteBufferCount is the number of memtables that may be queued before
+	// writes stall (default: 2)
+	WriteBufferCount int
+
+	// MemoryBudget in MB caps WriteBuffer*WriteBufferCount (0 = unlimited)
+	MemoryBudget int
+
+	// BloomFilterBitsPerKey sizes the bloom filter kept in every SSTable, which
+	// lets point lookups such as GetReceipt skip tables that cannot hold the
+	// key. More bits lower the false positive rate at the cost of memory and
+	// disk (default: 10; 0 disables filters)
+	BloomFilterBitsPerKey int
+
 	// DisableWAL disables write-ahead log (not recommended)
 	DisableWAL bool
 
 	// ReadOnly opens the database in read-only mode
 	ReadOnly bool
 
+	// ReplicaRefreshInterval makes a ReadOnly storage a read replica of a
+	// database another process is writing to. Path is snapshotted into a
+	// private directory under ReplicaSnapshotDir and re-snapshotted every
+	// interval, so reads lag the writer by at most one interval plus the time
+	// to take a snapshot.
+	// Writes the writer commits without syncing only become visible once its
+	// WAL is synced or flushed. (0 disables; requires ReadOnly)
+	ReplicaRefreshInterval time.Duration
+
+	// ReplicaSnapshotDir is where a read replica keeps its snapshots, and the
+	// only place it writes to (default: the parent directory of Path). Table
+	// files are hard-linked when it is on the same filesystem as Path;
+	// otherwise each table is copied once and linked from the previous
+	// snapshot on later refreshes.
+	ReplicaSnapshotDir string
+
 	// CompactionConcurrency for background compaction (default: 1)
 	CompactionConcurrency int
+
+	// LogAddressTopicIndex maintains an additional log index keyed by
+	// (address, topic0), roughly doubling log index storage (default: false)
+	LogAddressTopicIndex bool
+
+	// IndexUncles stores the uncle (ommer) headers of each block under
+	// UncleKey so GetUncles can list them. Most chains, including every
+	// post-merge Ethereum chain, have no uncles (default: false)
+	IndexUncles bool
+
+	// ReadCacheSize is the number of decoded blocks, block hash lookups and
+	// receipts kept in memory for reads (0 disables; ignored when ReadOnly,
+	// except for read replicas)
+	ReadCacheSize int
+
+	// InitTransactionCount runs InitializeTransactionCount on open when blocks
+	// are indexed but no transaction count is stored, as in databases created
+	// before the count was maintained. This is a full scan of every block, so
+	// it can make open slow on large databases (ignored when ReadOnly)
+	InitTransactionCount bool
+
+	// ScanWorkers is the number of goroutines used by full-chain scans such
+	// as GetTopMiners and GetTokenBalances. Each worker aggregates its share
+	// of the blocks separately and the results are merged (0 or 1 = serial)
+	ScanWorkers int
+
+	// CompactionInterval is the time between scheduled full-keyspace
+	// compactions (0 disables; ignored when ReadOnly)
+	CompactionInterval time.Duration
+
+	// CompactionStartTime is the local "HH:MM" time of the first scheduled
+	// compaction, to place runs in a low-traffic window (empty = one interval after open)
+	CompactionStartTime string
+
+	// KeepOrphanBlocks makes RollbackToHeight move superseded blocks to the
+	// orphan store instead of deleting them (default: false)
+	KeepOrphanBlocks bool
+
+	// KeyNamespace prefixes every key with /ns/{KeyNamespace}, so several
+	// chains can keep their data in one database (empty = no prefix). A
+	// storage reads and writes only its own namespace; WithNamespace opens
+	// the others in the same process.
+	KeyNamespace string
+
+	// MaxOpenIterators enables counting open iterators and reports when more
+	// than this many are open at once, which usually means one is never
+	// closed. Meant for tests and staging (0 disables tracking).
+	MaxOpenIterators int
+
+	// PanicOnIteratorLeak panics instead of logging a warning when
+	// MaxOpenIterators is exceeded
+	PanicOnIteratorLeak bool
 }
 
 // DefaultConfig returns a default configuration
 func DefaultConfig(path string) *Config {
 	return &Config{
 		Path:                  path,
-		Cache:                 128, // 128 MB
+		Cache:                 DefaultCacheMB,
 		MaxOpenFiles:          1000,
-		WriteBuffer:           64, // 64 MB
+		WriteBuffer:           DefaultWriteBufferMB,
+		WriteBufferCount:      DefaultWriteBufferCount,
+		BloomFilterBitsPerKey: DefaultBloomFilterBitsPerKey,
 		DisableWAL:            false,
 		ReadOnly:              false,
 		CompactionConcurrency: 1,
@@ -239,9 +365,53 @@ func (c *Config) Validate() error {
 	if c.WriteBuffer < 0 {
 		return errors.New("write buffer size cannot be negative")
 	}
+	if c.WriteBuffer >= MaxWriteBufferMB {
+		return fmt.Errorf("write buffer size must be less than %d MB", MaxWriteBufferMB)
+	}
+	if c.WriteBufferCount < 0 {
+		return errors.New("write buffer count cannot be negative")
+	}
+	if c.MemoryBudget < 0 {
+		return errors.New("memory budget cannot be negative")
+	}
+	if c.ReadCacheSize < 0 {
+		return errors.New("read cache size cannot be negative")
+	}
+	if c.BloomFilterBitsPerKey < 0 {
+		return errors.New("bloom filter bits per key cannot be negative")
+	}
+	if c.ScanWorkers < 0 {
+		return errors.New("scan workers cannot be negative")
+	}
+	if c.MemoryBudget > 0 {
+		if memtables := c.WriteBuffer * c.effectiveWriteBufferCount(); memtables > c.MemoryBudget {
+			return fmt.Errorf("memtable memory %d MB (write buffer %d MB x %d) exceeds memory budget %d MB",
+				memtables, c.WriteBuffer, c.effectiveWriteBufferCount(), c.MemoryBudget)
+		}
+	}
 	if c.CompactionConcurrency < 1 {
 		return errors.New("compaction concurrency must be at least 1")
 	}
+	if c.CompactionInterval < 0 {
+		return errors.New("compaction interval cannot be negative")
+	}
+	if c.MaxOpenIterators < 0 {
+		return errors.New("max open iterators cannot be negative")
+	}
+	if c.ReplicaRefreshInterval < 0 {
+		return errors.New("replica refresh interval cannot be negative")
+	}
+	if c.ReplicaRefreshInterval > 0 && !c.ReadOnly {
+		return errors.New("replica refresh interval requires read-only mode")
+	}
+	if c.CompactionStartTime != "" {
+		if _, err := time.Parse(compactionStartLayout, c.CompactionStartTime); err != nil {
+			return fmt.Errorf("invalid compaction start time %q: expected HH:MM", c.CompactionStartTime)
+		}
+	}
+	if err := validateKeyNamespace(c.KeyNamespace); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -261,6 +431,61 @@ type Stats struct {
 
 	// CompactionCount is the number of compactions performed
 	CompactionCount uint64
+
+	// LastCompaction is when the last full-keyspace compaction finished
+	// (zero if none has run)
+	LastCompaction time.Time
+
+	// Pebble holds the database engine's internal metrics
+	Pebble *PebbleMetrics
+}
+
+// PebbleMetrics is a snapshot of Pebble's internal metrics, for diagnosing
+// read amplification, cache efficiency and compaction backlog
+type PebbleMetrics struct {
+	// Levels holds per-level LSM metrics, L0 first
+	Levels []LevelMetrics
+
+	// BlockCacheSize is the bytes held by the block cache
+	BlockCacheSize int64
+	// BlockCacheHits and BlockCacheMisses count block cache lookups
+	BlockCacheHits   int64
+	BlockCacheMisses int64
+	// BlockCacheHitRate is hits / (hits + misses), 0 before any lookup
+	BlockCacheHitRate float64
+
+	// CompactionCount is the total number of compactions run since open
+	CompactionCount int64
+	// CompactionDebt is the estimated bytes still to compact
+	CompactionDebt uint64
+	// CompactionsInProgress is the number of running compactions
+	CompactionsInProgress int64
+
+	// MemTableSize is the bytes allocated by memtables
+	MemTableSize uint64
+	// WALFiles is the number of live WAL files
+	WALFiles int64
+	// WALSize is the live data in the WAL; WALPhysicalSize its size on disk
+	WALSize         uint64
+	WALPhysicalSize uint64
+
+	// ReadAmplification is the number of sublevels a point read may visit
+	ReadAmplification int
+}
+
+// LevelMetrics describes one level of the LSM tree
+type LevelMetrics struct {
+	Level     int
+	NumFiles  int64
+	Size      int64
+	Sublevels int32
+	// Score is the level's compaction score; a level is compacted above 1
+	Score float64
+}
+
+// StatsReader reports storage statistics
+type StatsReader interface {
+	GetStats(ctx context.Context) (*Stats, error)
 }
 
 // LogFilter represents criteria for filtering event logs
@@ -307,6 +532,15 @@ type LogWriter interface {
 	IndexLog(ctx context.Context, log *types.Log) error
 }
 
+// IndexedLogReader provides direct lookups into the (address, topic0) log index.
+// Only logs indexed while the index is enabled are returned.
+type IndexedLogReader interface {
+	// GetLogsIndexed returns logs emitted by address with the given topic0 within
+	// the block range, ordered by position, with pagination.
+	// Returns ErrLogIndexDisabled if the index is not enabled.
+	GetLogsIndexed(ctx context.Context, address common.Address, topic0 common.Hash, fromBlock, toBlock uint64, limit, offset int) ([]*types.Log, error)
+}
+
 // ABIReader provides read access to contract ABIs
 type ABIReader interface {
 	// GetABI returns the ABI for a contract
@@ -327,3 +561,263 @@ type ABIWriter interface {
 	// DeleteABI removes an ABI for a contract
 	DeleteABI(ctx context.Context, address common.Address) error
 }
+
+// ReceiptRangeReader provides receipt queries spanning multiple blocks
+type ReceiptRangeReader interface {
+	// GetReceiptsByBlockRange returns receipts for blocks in [from, to] ordered by
+	// block number and transaction index, skipping blocks without stored receipts.
+	// The range may span at most MaxReceiptBlockRange blocks.
+	GetReceiptsByBlockRange(ctx context.Context, from, to uint64, limit, offset int) ([]*types.Receipt, error)
+}
+
+// ChainIDReader provides read access to the stored chain ID
+type ChainIDReader interface {
+	// GetChainID returns the chain ID of the indexed chain
+	// Returns ErrNotFound if the chain ID has not been stored yet
+	GetChainID(ctx context.Context) (*big.Int, error)
+}
+
+// ChainIDWriter provides write access to the stored chain ID
+type ChainIDWriter interface {
+	// SetChainID stores the chain ID of the indexed chain
+	SetChainID(ctx context.Context, chainID *big.Int) error
+}
+
+// ChainHeadReader provides read access to the last observed chain head
+type ChainHeadReader interface {
+	// GetChainHead returns the latest block height reported by the node,
+	// independent of how far indexing has progressed
+	// Returns ErrNotFound if no chain head has been recorded yet
+	GetChainHead(ctx context.Context) (uint64, error)
+}
+
+// ChainHeadWriter provides write access to the last observed chain head
+type ChainHeadWriter interface {
+	// SetChainHead records the latest block height reported by the node
+	SetChainHead(ctx context.Context, height uint64) error
+}
+
+// FailedBlock is a dead-letter record for a block that could not be indexed
+type FailedBlock struct {
+	Height uint64 `json:"height"`
+	// Error is the error of the most recent failed attempt
+	Error string `json:"error"`
+	// Attempts counts failed indexing attempts, each of which already
+	// includes the fetcher's own retries
+	Attempts int       `json:"attempts"`
+	FailedAt time.Time `json:"failed_at"`
+}
+
+// FailedBlockReader provides read access to the dead-letter store
+type FailedBlockReader interface {
+	// GetFailedBlock returns the dead-letter record of a block
+	// Returns ErrNotFound if the block has no record
+	GetFailedBlock(ctx context.Context, height uint64) (*FailedBlock, error)
+
+	// GetFailedBlocks returns up to limit dead-letter records ordered by height
+	// A limit of 0 or less returns all records
+	GetFailedBlocks(ctx context.Context, limit int) ([]*FailedBlock, error)
+}
+
+// FailedBlockWriter provides write access to the dead-letter store
+type FailedBlockWriter interface {
+	// SetFailedBlock records a block that failed to index, replacing any
+	// previous record for the same height
+	SetFailedBlock(ctx context.Context, record *FailedBlock) error
+
+	// DeleteFailedBlock removes the dead-letter record of a block
+	// Deleting a missing record is not an error
+	DeleteFailedBlock(ctx context.Context, height uint64) error
+}
+
+// OrphanBlock is a block removed by RollbackToHeight while
+// Config.KeepOrphanBlocks was set
+type OrphanBlock struct {
+	Block *types.Block
+	// ForkHeight is the height rolled back to, the last height whose block
+	// was kept
+	ForkHeight uint64
+	OrphanedAt time.Time
+}
+
+// ChainRollbacker removes indexed blocks after a chain reorganization
+type ChainRollbacker interface {
+	// RollbackToHeight removes the blocks above height and sets the latest
+	// height to height. The blocks are deleted, or moved to the orphan store
+	// with Config.KeepOrphanBlocks, together with the transactions, receipts,
+	// logs and address index entries indexed from them. Rolling back to or
+	// above the latest height is a no-op.
+	RollbackToHeight(ctx context.Context, height uint64) error
+}
+
+// OrphanBlockReader provides read access to blocks orphaned by RollbackToHeight
+type OrphanBlockReader interface {
+	// GetOrphanBlock returns an orphaned block by hash
+	// Returns ErrNotFound if no block with the hash was orphaned
+	GetOrphanBlock(ctx context.Context, hash common.Hash) (*OrphanBlock, error)
+
+	// GetOrphanBlocks returns orphaned blocks ordered by block number, then hash
+	GetOrphanBlocks(ctx context.Context, limit, offset int) ([]*OrphanBlock, error)
+}
+
+// RevertReason is why a failed transaction reverted, as reported by tracing it
+type RevertReason struct {
+	TxHash common.Hash `json:"txHash"`
+	// Error is the EVM error of the top-level call, e.g. "execution reverted"
+	// or "out of gas"
+	Error string `json:"error"`
+	// Data is the raw revert output, empty when the call failed without one
+	Data []byte `json:"data,omitempty"`
+	// Reason is the decoded Error(string) message or Panic(uint256) code,
+	// empty for custom errors, which are only available as Data
+	Reason string `json:"reason,omitempty"`
+}
+
+// Message returns the decoded reason, or the EVM error when the revert data
+// could not be decoded
+func (r *RevertReason) Message() string {
+	if r.Reason != "" {
+		return r.Reason
+	}
+	return r.Error
+}
+
+// RevertReasonWriter stores the revert reasons of failed transactions
+type RevertReasonWriter interface {
+	SetRevertReason(ctx context.Context, reason *RevertReason) error
+}
+
+// RevertReasonReader provides read access to revert reasons
+type RevertReasonReader interface {
+	// GetRevertReason returns the revert reason of a failed transaction
+	// Returns ErrNotFound if none was indexed for the transaction
+	GetRevertReason(ctx context.Context, txHash common.Hash) (*RevertReason, error)
+}
+
+// BulkStreamer streams stored history in height order through a single
+// iterator, for export jobs that would otherwise page by offset
+type BulkStreamer interface {
+	// StreamBlocks calls fn for each stored block in [from, to], in height
+	// order, until fn returns false. Heights above the latest height are not
+	// visited. Returns ctx.Err() if the context is canceled mid-stream.
+	StreamBlocks(ctx context.Context, from, to uint64, fn func(*types.Block) bool) error
+
+	// StreamTransactions calls fn for each transaction of the stored blocks in
+	// [from, to], ordered by height and then index, until fn returns false.
+	// The location carries no cached sender; use TxLocation.Sender.
+	StreamTransactions(ctx context.Context, from, to uint64, fn func(*types.Transaction, *TxLocation) bool) error
+}
+
+// BlockHeaderReader reads block headers without decoding block bodies, for
+// queries that only need header fields such as timestamp, miner or gas used
+type BlockHeaderReader interface {
+	// GetBlockHeader returns the header of the block at height
+	// Returns ErrNotFound if no block is stored at height
+	GetBlockHeader(ctx context.Context, height uint64) (*types.Header, error)
+}
+
+// LatestBlocksReader serves the most recently indexed blocks, newest first,
+// for views such as an explorer homepage
+type LatestBlocksReader interface {
+	// GetLatestBlocks returns up to n blocks from the n heights ending at the
+	// latest height, in descending height order. Missing heights are skipped
+	// and n is clamped to the indexed range, so it returns an empty slice
+	// rather than ErrNotFound when nothing is indexed yet. The blocks may
+	// carry only their headers, without transactions or uncles.
+	GetLatestBlocks(ctx context.Context, n int) ([]*types.Block, error)
+}
+
+// UncleReader serves the uncle (ommer) headers indexed with each block
+type UncleReader interface {
+	// GetUncles returns the uncle headers of the block at height in the order
+	// the block lists them, or an empty slice if it has none. Returns
+	// ErrNotFound if no block is stored at height and ErrUncleIndexDisabled
+	// when uncle indexing is off.
+	GetUncles(ctx context.Context, height uint64) ([]*types.Header, error)
+}
+
+// BlockRangeReader serves a range of blocks together with the heights that
+// have no block stored, so APIs can signal an incomplete range to clients
+type BlockRangeReader interface {
+	// GetBlocksWithMissing returns the blocks between startHeight and
+	// endHeight inclusive and the heights in that range without a block,
+	// both in ascending height order
+	GetBlocksWithMissing(ctx context.Context, startHeight, endHeight uint64) ([]*types.Block, []uint64, error)
+}
+
+// TransactionContext is an indexed transaction together with what is needed
+// to display it: where it sits in the chain, the enclosing block header and
+// its receipt
+type TransactionContext struct {
+	Transaction *types.Transaction
+	Location    *TxLocation
+	Header      *types.Header
+	// Receipt is nil when no receipt is stored for the transaction
+	Receipt *types.Receipt
+}
+
+// TransactionContextReader loads a transaction with its location, block
+// header and receipt in one call, reading each stored piece once
+type TransactionContextReader interface {
+	// GetTransactionContext returns the transaction with the given hash and
+	// its context. Returns ErrNotFound if the transaction is not indexed.
+	GetTransactionContext(ctx context.Context, hash common.Hash) (*TransactionContext, error)
+}
+
+// AddressStatsWriter maintains running per-address transaction aggregates,
+// which GetAddressStats serves without scanning the address's transactions
+type AddressStatsWriter interface {
+	// IndexAddressStats adds the block's transactions to the aggregates of their
+	// senders and recipients. Indexing a block with the same hash again is a no-op.
+	IndexAddressStats(ctx context.Context, block *types.Block, receipts types.Receipts) error
+	// IndexRepairedAddressStats adds the transactions of receipts that were
+	// missing when the block was counted, such as receipts stored by a repair
+	IndexRepairedAddressStats(ctx context.Context, block *types.Block, receipts types.Receipts) error
+}
+
+// AddressTransactionCounter returns how many transactions an address has, so
+// pagers can show the total alongside a page of GetTransactionsByAddress
+type AddressTransactionCounter interface {
+	// CountTransactionsByAddress returns the number of transactions the address
+	// sent or received, counted from the address index that
+	// GetTransactionsByAddress pages through. A transaction indexed again by a
+	// re-indexed block is counted once.
+	CountTransactionsByAddress(ctx context.Context, addr common.Address) (uint64, error)
+}
+
+// AddressActivityReader returns the span of blocks an address transacted in,
+// e.g. to show an account's age
+type AddressActivityReader interface {
+	// GetAddressActivityRange returns the first and last block in which the
+	// address sent or received a transaction, from the blocks of the
+	// transactions in its address index. Returns ErrNotFound if the address
+	// has no transactions.
+	GetAddressActivityRange(ctx context.Context, addr common.Address) (first, last uint64, err error)
+}
+
+// StateSnapshot is chain state as of the end of block Height, provided
+// externally for deployments that do not index the blocks up to it
+type StateSnapshot struct {
+	Height        uint64
+	Balances      map[common.Address]*big.Int
+	TotalSupply   *big.Int
+	ActiveMinters map[common.Address]*big.Int // minter -> allowance
+}
+
+// StateSnapshotWriter seeds balances and system contract state from a snapshot,
+// so indexing from the block after it builds on that state
+type StateSnapshotWriter interface {
+	// ApplyStateSnapshot writes the snapshot state atomically. Applying the
+	// snapshot that was already applied is a no-op; applying a different one fails.
+	ApplyStateSnapshot(ctx context.Context, snapshot *StateSnapshot) error
+
+	// GetStateSnapshotHeight returns the height of the applied snapshot, or
+	// ErrNotFound if none was applied
+	GetStateSnapshotHeight(ctx context.Context) (uint64, error)
+}
+
+// Syncer makes buffered writes durable. Indexing writes with NoSync, so
+// Sync should be called before Close to keep recently indexed blocks.
+type Syncer interface {
+	Sync() error
+}
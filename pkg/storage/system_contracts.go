@@ -48,6 +48,35 @@ func (s ProposalStatus) String() string {
 	}
 }
 
+// CanTransitionTo reports whether a proposal in status s may move to next.
+// Repeating the current status is allowed so re-indexed events are idempotent;
+// executed, cancelled, expired and rejected proposals are final.
+func (s ProposalStatus) CanTransitionTo(next ProposalStatus) bool {
+	if s == next {
+		return true
+	}
+	switch s {
+	case ProposalStatusNone, ProposalStatusVoting:
+		switch next {
+		case ProposalStatusVoting, ProposalStatusApproved, ProposalStatusExecuted, ProposalStatusCancelled,
+			ProposalStatusExpired, ProposalStatusFailed, ProposalStatusRejected:
+			return true
+		}
+	case ProposalStatusApproved:
+		switch next {
+		case ProposalStatusExecuted, ProposalStatusCancelled, ProposalStatusExpired, ProposalStatusFailed:
+			return true
+		}
+	case ProposalStatusFailed:
+		// A failed execution can be retried until the proposal expires
+		switch next {
+		case ProposalStatusExecuted, ProposalStatusExpired:
+			return true
+		}
+	}
+	return false
+}
+
 // MintEvent represents a Mint event from NativeCoinAdapter
 type MintEvent struct {
 	BlockNumber uint64
@@ -56,6 +85,9 @@ type MintEvent struct {
 	To          common.Address
 	Amount      *big.Int
 	Timestamp   uint64
+	// TxIndex and LogIndex locate the source log within the block
+	TxIndex  uint64 `rlp:"optional"`
+	LogIndex uint64 `rlp:"optional"`
 }
 
 // BurnEvent represents a Burn event
@@ -67,6 +99,9 @@ type BurnEvent struct {
 	Timestamp   uint64
 	// WithdrawalID is used for GovMinter burn events
 	WithdrawalID string
+	// TxIndex and LogIndex locate the source log within the block
+	TxIndex  uint64 `rlp:"optional"`
+	LogIndex uint64 `rlp:"optional"`
 }
 
 // MinterConfigEvent represents Minter configuration changes
@@ -108,6 +143,28 @@ type ProposalVote struct {
 	Timestamp   uint64
 }
 
+// ProposalTally aggregates the votes cast on a proposal. Members approve or
+// disapprove with equal weight, so the counts are also the weighted sums and
+// there is no abstain option.
+type ProposalTally struct {
+	Contract   common.Address
+	ProposalID *big.Int
+	// Approvals and Rejections count the members whose latest vote approves
+	// or rejects the proposal
+	Approvals  uint32
+	Rejections uint32
+	// Voters is the number of distinct members who voted
+	Voters uint32
+	// RequiredApprovals is the proposal's quorum, 0 if the proposal is not stored
+	RequiredApprovals uint32
+}
+
+// ActiveMinter is a currently active minter and its configured allowance
+type ActiveMinter struct {
+	Address   common.Address
+	Allowance *big.Int
+}
+
 // GasTipUpdateEvent represents a gas tip update from GovValidator
 type GasTipUpdateEvent struct {
 	BlockNumber uint64
@@ -211,13 +268,17 @@ type AuthorizedAccountEvent struct {
 type SystemContractReader interface {
 	// NativeCoinAdapter queries
 	GetTotalSupply(ctx context.Context) (*big.Int, error)
+	// GetMintEvents and GetBurnEvents return the events in [fromBlock, toBlock];
+	// a zero minter or burner address returns all events in the range
 	GetMintEvents(ctx context.Context, fromBlock, toBlock uint64, minter common.Address, limit, offset int) ([]*MintEvent, error)
 	GetBurnEvents(ctx context.Context, fromBlock, toBlock uint64, burner common.Address, limit, offset int) ([]*BurnEvent, error)
-	GetActiveMinters(ctx context.Context) ([]common.Address, error)
+	// GetActiveMinters returns active minters with their allowances, sorted by address bytes
+	GetActiveMinters(ctx context.Context) ([]*ActiveMinter, error)
 	GetMinterAllowance(ctx context.Context, minter common.Address) (*big.Int, error)
 	GetMinterHistory(ctx context.Context, minter common.Address) ([]*MinterConfigEvent, error)
 
 	// GovValidator queries
+	// GetActiveValidators returns active validators sorted by address bytes
 	GetActiveValidators(ctx context.Context) ([]common.Address, error)
 	GetGasTipHistory(ctx context.Context, fromBlock, toBlock uint64) ([]*GasTipUpdateEvent, error)
 	GetValidatorHistory(ctx context.Context, validator common.Address) ([]*ValidatorChangeEvent, error)
@@ -276,6 +337,22 @@ type SystemContractWriter interface {
 	UpdateBlacklistStatus(ctx context.Context, address common.Address, blacklisted bool) error
 }
 
+// ProposalTallyReader aggregates proposal votes
+type ProposalTallyReader interface {
+	// GetProposalTally returns the vote counts of a proposal, computed from the
+	// votes stored by StoreProposalVote. A proposal without votes has a zero
+	// tally.
+	GetProposalTally(ctx context.Context, contract common.Address, proposalID *big.Int) (*ProposalTally, error)
+}
+
+// SystemContractStateResetter clears the aggregate system contract state that is
+// built incrementally from events (total supply, active minters, active validators
+// and blacklist) along with the mint and burn event records, so it can be rebuilt
+// by replaying events from genesis
+type SystemContractStateResetter interface {
+	ResetSystemContractState(ctx context.Context) error
+}
+
 // SystemContractStorage combines system contract read and write interfaces
 type SystemContractStorage interface {
 	SystemContractReader
@@ -0,0 +1,339 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+)
+
+var _ Storage = (*TieredStorage)(nil)
+var _ BlockHeaderReader = (*TieredStorage)(nil)
+var _ LatestBlocksReader = (*TieredStorage)(nil)
+var _ BlockRangeReader = (*TieredStorage)(nil)
+var _ TransactionContextReader = (*TieredStorage)(nil)
+var _ Syncer = (*TieredStorage)(nil)
+
+// migrateChunkSize is the number of blocks MigrateToCold archives before
+// syncing the cold store and deleting their hot copies
+const migrateChunkSize = 1000
+
+// ColdStore is the read side of an archive tier holding old blocks. A
+// PebbleStorage satisfies it, opened with ReadOnly set when another process
+// migrates into it.
+type ColdStore interface {
+	GetBlock(ctx context.Context, height uint64) (*types.Block, error)
+	GetBlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error)
+	HasBlock(ctx context.Context, height uint64) (bool, error)
+	GetLatestHeight(ctx context.Context) (uint64, error)
+}
+
+// ColdWriter receives blocks migrated out of the hot tier. Its latest height
+// records the highest archived block, from which OpenTieredStorage restores
+// the boundary.
+type ColdWriter interface {
+	SetBlock(ctx context.Context, block *types.Block) error
+	SetLatestHeight(ctx context.Context, height uint64) error
+	Sync() error
+}
+
+// TieredStorage serves blocks from a hot PebbleStorage and falls back to a
+// cold archive for heights below the boundary. Every write goes to the hot
+// tier; only block bodies are tiered, so transactions, receipts and indexes
+// stay in hot storage.
+type TieredStorage struct {
+	*PebbleStorage
+
+	cold     ColdStore
+	boundary atomic.Uint64
+
+	// migrateMu serializes MigrateToCold calls
+	migrateMu     sync.Mutex
+	migrationStop chan struct{}
+	migrationDone chan struct{}
+}
+
+// NewTieredStorage wraps hot with a cold archive that holds every block
+// below boundary.
+func NewTieredStorage(hot *PebbleStorage, cold ColdStore, boundary uint64) *TieredStorage {
+	t := &TieredStorage{
+		PebbleStorage: hot,
+		cold:          cold,
+	}
+	t.boundary.Store(boundary)
+	return t
+}
+
+// OpenTieredStorage wraps hot with a cold archive, resuming at the boundary
+// left by earlier migrations into it: one past its latest height, or 0 for an
+// empty archive.
+func OpenTieredStorage(ctx context.Context, hot *PebbleStorage, cold ColdStore) (*TieredStorage, error) {
+	var boundary uint64
+	latest, err := cold.GetLatestHeight(ctx)
+	if err == nil {
+		boundary = latest + 1
+	} else if !errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("failed to get cold storage latest height: %w", err)
+	}
+	return NewTieredStorage(hot, cold, boundary), nil
+}
+
+// Boundary returns the lowest height still served from the hot tier.
+func (t *TieredStorage) Boundary() uint64 {
+	return t.boundary.Load()
+}
+
+// GetBlock returns a block by height from whichever tier holds it
+func (t *TieredStorage) GetBlock(ctx context.Context, height uint64) (*types.Block, error) {
+	first, second := t.tiersFor(height)
+
+	block, err := first.GetBlock(ctx, height)
+	if !errors.Is(err, ErrNotFound) {
+		return block, err
+	}
+	return second.GetBlock(ctx, height)
+}
+
+// GetBlockByHash returns a block by hash, checking the hot tier first
+func (t *TieredStorage) GetBlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	block, err := t.PebbleStorage.GetBlockByHash(ctx, hash)
+	if !errors.Is(err, ErrNotFound) {
+		return block, err
+	}
+	return t.cold.GetBlockByHash(ctx, hash)
+}
+
+// GetBlocks returns the blocks in a height range, which may span both tiers.
+// Missing heights are skipped.
+func (t *TieredStorage) GetBlocks(ctx context.Context, startHeight, endHeight uint64) ([]*types.Block, error) {
+	blocks, _, err := t.GetBlocksWithMissing(ctx, startHeight, endHeight)
+	return blocks, err
+}
+
+// GetBlocksWithMissing returns the blocks in a height range, which may span
+// both tiers, along with the heights neither tier holds
+func (t *TieredStorage) GetBlocksWithMissing(ctx context.Context, startHeight, endHeight uint64) ([]*types.Block, []uint64, error) {
+	return BlocksWithMissing(ctx, t, startHeight, endHeight)
+}
+
+// GetLatestBlocks returns up to n of the most recent blocks, newest first,
+// reading each from whichever tier holds it
+func (t *TieredStorage) GetLatestBlocks(ctx context.Context, n int) ([]*types.Block, error) {
+	return LatestBlocks(ctx, t, n)
+}
+
+// GetTransactionContext returns a transaction with its location, receipt and
+// the header of its block, read from whichever tier holds the block
+func (t *TieredStorage) GetTransactionContext(ctx context.Context, hash common.Hash) (*TransactionContext, error) {
+	return LookupTransactionContext(ctx, t, hash)
+}
+
+// HasBlock reports whether either tier holds the block at height
+func (t *TieredStorage) HasBlock(ctx context.Context, height uint64) (bool, error) {
+	first, second := t.tiersFor(height)
+
+	ok, err := first.HasBlock(ctx, height)
+	if err != nil || ok {
+		return ok, err
+	}
+	return second.HasBlock(ctx, height)
+}
+
+// GetBlockHeader returns the header at height, using the tier's header
+// reader when it has one.
+func (t *TieredStorage) GetBlockHeader(ctx context.Context, height uint64) (*types.Header, error) {
+	first, second := t.tiersFor(height)
+
+	header, err := tierBlockHeader(ctx, first, height)
+	if !errors.Is(err, ErrNotFound) {
+		return header, err
+	}
+	return tierBlockHeader(ctx, second, height)
+}
+
+// GetMissingReceipts returns the hashes of the block's transactions that have
+// no receipt in the hot tier, reading the block from whichever tier holds it
+func (t *TieredStorage) GetMissingReceipts(ctx context.Context, blockNumber uint64) ([]common.Hash, error) {
+	block, err := t.GetBlock(ctx, blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block: %w", err)
+	}
+
+	var missing []common.Hash
+	for _, tx := range block.Transactions() {
+		exists, err := t.HasReceipt(ctx, tx.Hash())
+		if err != nil {
+			return nil, fmt.Errorf("failed to check receipt for tx %s: %w", tx.Hash().Hex(), err)
+		}
+		if !exists {
+			missing = append(missing, tx.Hash())
+		}
+	}
+
+	return missing, nil
+}
+
+// MigrateToCold moves blocks that are more than retain blocks behind the
+// latest height from the hot tier into dst, a chunk of heights at a time.
+// Each chunk is synced to dst and the boundary moved past it before the hot
+// copies are deleted, so readers never miss a block mid-migration and a crash
+// cannot lose one. It returns the number of blocks moved.
+func (t *TieredStorage) MigrateToCold(ctx context.Context, dst ColdWriter, retain uint64) (int, error) {
+	t.migrateMu.Lock()
+	defer t.migrateMu.Unlock()
+
+	latest, err := t.PebbleStorage.GetLatestHeight(ctx)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to get latest height: %w", err)
+	}
+	if latest < retain {
+		return 0, nil
+	}
+	before := latest - retain
+
+	moved := 0
+	for start := t.boundary.Load(); start < before; {
+		end := min(start+migrateChunkSize, before)
+
+		archived, err := t.archiveRange(ctx, dst, start, end)
+		if err != nil {
+			return moved, err
+		}
+		t.boundary.Store(end)
+
+		for _, height := range archived {
+			if err := t.PebbleStorage.DeleteBlock(ctx, height); err != nil {
+				return moved, fmt.Errorf("failed to delete archived block %d: %w", height, err)
+			}
+			moved++
+		}
+		start = end
+	}
+
+	return moved, nil
+}
+
+// archiveRange copies the hot blocks in [start, end) to dst and syncs it,
+// returning the heights copied
+func (t *TieredStorage) archiveRange(ctx context.Context, dst ColdWriter, start, end uint64) ([]uint64, error) {
+	var archived []uint64
+	for height := start; height < end; height++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		block, err := t.PebbleStorage.GetBlock(ctx, height)
+		if err != nil {
+			if errors.Is(err, ErrNotFound) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read block %d: %w", height, err)
+		}
+
+		if err := dst.SetBlock(ctx, block); err != nil {
+			return nil, fmt.Errorf("failed to archive block %d: %w", height, err)
+		}
+		archived = append(archived, height)
+	}
+
+	if err := dst.SetLatestHeight(ctx, end-1); err != nil {
+		return nil, fmt.Errorf("failed to record archived height %d: %w", end-1, err)
+	}
+	if err := dst.Sync(); err != nil {
+		return nil, fmt.Errorf("failed to sync cold storage: %w", err)
+	}
+	return archived, nil
+}
+
+// StartMigration runs MigrateToCold into dst every interval until the storage
+// is closed
+func (t *TieredStorage) StartMigration(dst ColdWriter, retain uint64, interval time.Duration) {
+	t.migrationStop = make(chan struct{})
+	t.migrationDone = make(chan struct{})
+
+	go func() {
+		defer close(t.migrationDone)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-t.migrationStop:
+				return
+			case <-ticker.C:
+				moved, err := t.MigrateToCold(context.Background(), dst, retain)
+				if err != nil {
+					t.logger.Error("Cold storage migration failed", zap.Int("moved", moved), zap.Error(err))
+					continue
+				}
+				if moved > 0 {
+					t.logger.Info("Migrated blocks to cold storage",
+						zap.Int("moved", moved),
+						zap.Uint64("boundary", t.Boundary()),
+					)
+				}
+			}
+		}
+	}()
+}
+
+// Sync flushes both tiers
+func (t *TieredStorage) Sync() error {
+	if syncer, ok := t.cold.(Syncer); ok {
+		if err := syncer.Sync(); err != nil {
+			return fmt.Errorf("failed to sync cold storage: %w", err)
+		}
+	}
+	return t.PebbleStorage.Sync()
+}
+
+// Close waits for a running migration, then closes both tiers
+func (t *TieredStorage) Close() error {
+	if t.migrationStop != nil {
+		close(t.migrationStop)
+		<-t.migrationDone
+		t.migrationStop = nil
+	}
+
+	var coldErr error
+	if closer, ok := t.cold.(io.Closer); ok {
+		coldErr = closer.Close()
+	}
+	if err := t.PebbleStorage.Close(); err != nil {
+		return err
+	}
+	if coldErr != nil {
+		return fmt.Errorf("failed to close cold storage: %w", coldErr)
+	}
+	return nil
+}
+
+// tiersFor returns the tiers to consult for height, most likely first
+func (t *TieredStorage) tiersFor(height uint64) (first, second ColdStore) {
+	if height < t.boundary.Load() {
+		return t.cold, t.PebbleStorage
+	}
+	return t.PebbleStorage, t.cold
+}
+
+func tierBlockHeader(ctx context.Context, tier ColdStore, height uint64) (*types.Header, error) {
+	if hr, ok := tier.(BlockHeaderReader); ok {
+		return hr.GetBlockHeader(ctx, height)
+	}
+	block, err := tier.GetBlock(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	return block.Header(), nil
+}
@@ -0,0 +1,295 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func newTieredTestStores(t *testing.T) (hot, cold *PebbleStorage) {
+	t.Helper()
+
+	hot, err := NewPebbleStorage(DefaultConfig(t.TempDir()))
+	if err != nil {
+		t.Fatalf("NewPebbleStorage(hot) error = %v", err)
+	}
+	t.Cleanup(func() { hot.Close() })
+
+	cold, err = NewPebbleStorage(DefaultConfig(t.TempDir()))
+	if err != nil {
+		t.Fatalf("NewPebbleStorage(cold) error = %v", err)
+	}
+	t.Cleanup(func() { cold.Close() })
+
+	return hot, cold
+}
+
+func TestTieredStorage_ReadsSpanTiers(t *testing.T) {
+	ctx := context.Background()
+	hot, _ := newTieredTestStores(t)
+
+	// Archive heights 0-4 and reopen the archive read-only, as it is served
+	coldDir := t.TempDir()
+	writer, err := NewPebbleStorage(DefaultConfig(coldDir))
+	if err != nil {
+		t.Fatalf("NewPebbleStorage(cold) error = %v", err)
+	}
+	for h := uint64(0); h < 5; h++ {
+		if err := writer.SetBlock(ctx, createTestBlock(h)); err != nil {
+			t.Fatalf("cold SetBlock(%d) error = %v", h, err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("cold Close() error = %v", err)
+	}
+	coldCfg := DefaultConfig(coldDir)
+	coldCfg.ReadOnly = true
+	cold, err := NewPebbleStorage(coldCfg)
+	if err != nil {
+		t.Fatalf("NewPebbleStorage(read-only cold) error = %v", err)
+	}
+	defer cold.Close()
+
+	for h := uint64(5); h < 10; h++ {
+		if err := hot.SetBlock(ctx, createTestBlock(h)); err != nil {
+			t.Fatalf("hot SetBlock(%d) error = %v", h, err)
+		}
+	}
+
+	tiered := NewTieredStorage(hot, cold, 5)
+
+	for h := uint64(0); h < 10; h++ {
+		block, err := tiered.GetBlock(ctx, h)
+		if err != nil {
+			t.Fatalf("GetBlock(%d) error = %v", h, err)
+		}
+		if block.NumberU64() != h {
+			t.Errorf("GetBlock(%d) returned block %d", h, block.NumberU64())
+		}
+
+		byHash, err := tiered.GetBlockByHash(ctx, block.Hash())
+		if err != nil {
+			t.Fatalf("GetBlockByHash(block %d) error = %v", h, err)
+		}
+		if byHash.NumberU64() != h {
+			t.Errorf("GetBlockByHash(block %d) returned block %d", h, byHash.NumberU64())
+		}
+
+		header, err := tiered.GetBlockHeader(ctx, h)
+		if err != nil {
+			t.Fatalf("GetBlockHeader(%d) error = %v", h, err)
+		}
+		if header.Hash() != block.Hash() {
+			t.Errorf("GetBlockHeader(%d) hash = %s, want %s", h, header.Hash(), block.Hash())
+		}
+
+		ok, err := tiered.HasBlock(ctx, h)
+		if err != nil || !ok {
+			t.Errorf("HasBlock(%d) = %v, %v, want true", h, ok, err)
+		}
+	}
+
+	blocks, err := tiered.GetBlocks(ctx, 2, 12)
+	if err != nil {
+		t.Fatalf("GetBlocks() error = %v", err)
+	}
+	if len(blocks) != 8 {
+		t.Fatalf("GetBlocks() returned %d blocks, want 8", len(blocks))
+	}
+	for i, block := range blocks {
+		if want := uint64(2 + i); block.NumberU64() != want {
+			t.Errorf("blocks[%d] = %d, want %d", i, block.NumberU64(), want)
+		}
+	}
+
+	if _, err := tiered.GetBlock(ctx, 20); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetBlock(20) error = %v, want ErrNotFound", err)
+	}
+	if ok, err := tiered.HasBlock(ctx, 20); err != nil || ok {
+		t.Errorf("HasBlock(20) = %v, %v, want false", ok, err)
+	}
+
+	// Writes land in the hot tier
+	if err := tiered.SetBlock(ctx, createTestBlock(10)); err != nil {
+		t.Fatalf("SetBlock(10) error = %v", err)
+	}
+	if ok, _ := hot.HasBlock(ctx, 10); !ok {
+		t.Error("SetBlock(10) did not write to the hot tier")
+	}
+}
+
+func TestTieredStorage_MigrateToCold(t *testing.T) {
+	ctx := context.Background()
+	hot, cold := newTieredTestStores(t)
+
+	for h := uint64(0); h < 10; h++ {
+		if err := hot.SetBlock(ctx, createTestBlock(h)); err != nil {
+			t.Fatalf("SetBlock(%d) error = %v", h, err)
+		}
+	}
+	if err := hot.SetLatestHeight(ctx, 9); err != nil {
+		t.Fatalf("SetLatestHeight() error = %v", err)
+	}
+
+	tiered := NewTieredStorage(hot, cold, 0)
+
+	moved, err := tiered.MigrateToCold(ctx, cold, 3)
+	if err != nil {
+		t.Fatalf("MigrateToCold() error = %v", err)
+	}
+	if moved != 6 {
+		t.Errorf("MigrateToCold() moved %d blocks, want 6", moved)
+	}
+	if got := tiered.Boundary(); got != 6 {
+		t.Errorf("Boundary() = %d, want 6", got)
+	}
+
+	for h := uint64(0); h < 10; h++ {
+		inHot, _ := hot.HasBlock(ctx, h)
+		inCold, _ := cold.HasBlock(ctx, h)
+		if wantCold := h < 6; inHot == wantCold || inCold != wantCold {
+			t.Errorf("block %d: in hot = %v, in cold = %v", h, inHot, inCold)
+		}
+
+		block, err := tiered.GetBlock(ctx, h)
+		if err != nil {
+			t.Fatalf("GetBlock(%d) error = %v", h, err)
+		}
+		if block.NumberU64() != h {
+			t.Errorf("GetBlock(%d) returned block %d", h, block.NumberU64())
+		}
+	}
+
+	// Nothing new has aged out, so a second pass is a no-op
+	moved, err = tiered.MigrateToCold(ctx, cold, 3)
+	if err != nil {
+		t.Fatalf("second MigrateToCold() error = %v", err)
+	}
+	if moved != 0 {
+		t.Errorf("second MigrateToCold() moved %d blocks, want 0", moved)
+	}
+}
+
+func TestTieredStorage_OpenResumesBoundary(t *testing.T) {
+	ctx := context.Background()
+	hotDir, coldDir := t.TempDir(), t.TempDir()
+
+	open := func() (*PebbleStorage, *PebbleStorage) {
+		t.Helper()
+		hot, err := NewPebbleStorage(DefaultConfig(hotDir))
+		if err != nil {
+			t.Fatalf("NewPebbleStorage(hot) error = %v", err)
+		}
+		cold, err := NewPebbleStorage(DefaultConfig(coldDir))
+		if err != nil {
+			hot.Close()
+			t.Fatalf("NewPebbleStorage(cold) error = %v", err)
+		}
+		return hot, cold
+	}
+
+	hot, cold := open()
+	for h := uint64(0); h < 10; h++ {
+		if err := hot.SetBlock(ctx, createTestBlock(h)); err != nil {
+			t.Fatalf("SetBlock(%d) error = %v", h, err)
+		}
+	}
+	if err := hot.SetLatestHeight(ctx, 9); err != nil {
+		t.Fatalf("SetLatestHeight() error = %v", err)
+	}
+
+	tiered, err := OpenTieredStorage(ctx, hot, cold)
+	if err != nil {
+		t.Fatalf("OpenTieredStorage() error = %v", err)
+	}
+	if got := tiered.Boundary(); got != 0 {
+		t.Errorf("Boundary() of an empty archive = %d, want 0", got)
+	}
+	if _, err := tiered.MigrateToCold(ctx, cold, 3); err != nil {
+		t.Fatalf("MigrateToCold() error = %v", err)
+	}
+	if err := tiered.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	hot, cold = open()
+	tiered, err = OpenTieredStorage(ctx, hot, cold)
+	if err != nil {
+		t.Fatalf("reopened OpenTieredStorage() error = %v", err)
+	}
+	defer tiered.Close()
+
+	if got := tiered.Boundary(); got != 6 {
+		t.Errorf("reopened Boundary() = %d, want 6", got)
+	}
+	blocks, err := tiered.GetBlocks(ctx, 0, 9)
+	if err != nil {
+		t.Fatalf("GetBlocks() error = %v", err)
+	}
+	if len(blocks) != 10 {
+		t.Errorf("GetBlocks() returned %d blocks, want 10", len(blocks))
+	}
+}
+
+func TestTieredStorage_MissingReceiptsOfArchivedBlock(t *testing.T) {
+	ctx := context.Background()
+	hot, cold := newTieredTestStores(t)
+
+	block := createTestBlockWithTxs(t, 0, 2)
+	if err := hot.SetBlock(ctx, block); err != nil {
+		t.Fatalf("SetBlock() error = %v", err)
+	}
+	receipted := block.Transactions()[0].Hash()
+	if err := hot.SetReceipt(ctx, createTestReceipt(receipted, 21000)); err != nil {
+		t.Fatalf("SetReceipt() error = %v", err)
+	}
+	if err := hot.SetLatestHeight(ctx, 5); err != nil {
+		t.Fatalf("SetLatestHeight() error = %v", err)
+	}
+
+	tiered := NewTieredStorage(hot, cold, 0)
+	if moved, err := tiered.MigrateToCold(ctx, cold, 3); err != nil || moved != 1 {
+		t.Fatalf("MigrateToCold() = %d, %v, want 1 block moved", moved, err)
+	}
+
+	missing, err := tiered.GetMissingReceipts(ctx, 0)
+	if err != nil {
+		t.Fatalf("GetMissingReceipts() error = %v", err)
+	}
+	if want := block.Transactions()[1].Hash(); len(missing) != 1 || missing[0] != want {
+		t.Errorf("GetMissingReceipts() = %v, want [%s]", missing, want)
+	}
+}
+
+func TestTieredStorage_StartMigration(t *testing.T) {
+	ctx := context.Background()
+	hot, cold := newTieredTestStores(t)
+
+	for h := uint64(0); h < 10; h++ {
+		if err := hot.SetBlock(ctx, createTestBlock(h)); err != nil {
+			t.Fatalf("SetBlock(%d) error = %v", h, err)
+		}
+	}
+	if err := hot.SetLatestHeight(ctx, 9); err != nil {
+		t.Fatalf("SetLatestHeight() error = %v", err)
+	}
+
+	tiered := NewTieredStorage(hot, cold, 0)
+	tiered.StartMigration(cold, 3, 10*time.Millisecond)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for tiered.Boundary() < 6 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Boundary() = %d after 5s, want 6", tiered.Boundary())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if err := tiered.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if _, err := cold.GetLatestHeight(ctx); !errors.Is(err, ErrClosed) {
+		t.Errorf("cold GetLatestHeight() after Close() error = %v, want ErrClosed", err)
+	}
+}
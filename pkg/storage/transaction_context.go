@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// LookupTransactionContext assembles the context of the transaction with the
+// given hash from r's individual getters, reading the header through
+// BlockHeaderReader when r has one. Returns ErrNotFound if the transaction is
+// not indexed; a missing receipt leaves Receipt nil.
+func LookupTransactionContext(ctx context.Context, r Reader, hash common.Hash) (*TransactionContext, error) {
+	tx, location, err := r.GetTransaction(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	txCtx := &TransactionContext{Transaction: tx, Location: location}
+	if reader, ok := r.(BlockHeaderReader); ok {
+		txCtx.Header, err = reader.GetBlockHeader(ctx, location.BlockHeight)
+	} else {
+		block, blockErr := r.GetBlock(ctx, location.BlockHeight)
+		if blockErr == nil {
+			txCtx.Header = block.Header()
+		}
+		err = blockErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block header %d: %w", location.BlockHeight, err)
+	}
+
+	receipt, err := r.GetReceipt(ctx, hash)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("failed to get receipt: %w", err)
+	}
+	txCtx.Receipt = receipt
+
+	return txCtx, nil
+}
@@ -134,7 +134,7 @@ func ParseWBFTExtra(header *types.Header) (*WBFTBlockExtra, error) {
 	// Decode RLP
 	wbftExtraRLP := new(WBFTExtraRLP)
 	if err := rlp.DecodeBytes(header.Extra, wbftExtraRLP); err != nil {
-		return nil, fmt.Errorf("failed to decode WBFT extra: %w", err)
+		return nil, fmt.Errorf("%w WBFT extra: %w", ErrDecodeFailed, err)
 	}
 
 	// Convert to WBFTBlockExtra
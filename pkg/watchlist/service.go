@@ -3,6 +3,7 @@ package watchlist
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"sync"
 	"time"
 
@@ -615,7 +616,7 @@ func (s *WatchlistService) deleteWatchedAddress(ctx context.Context, watched *Wa
 func (s *WatchlistService) getWatchedAddressLocked(ctx context.Context, addressID string) (*WatchedAddress, error) {
 	data, err := s.storage.Get(ctx, WatchedAddressKey(addressID))
 	if err != nil {
-		if err == storage.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			return nil, ErrAddressNotFound
 		}
 		return nil, NewWatchlistError("get", err)
@@ -634,7 +635,7 @@ func (s *WatchlistService) getWatchedAddressByEthAddressLocked(ctx context.Conte
 	// Lookup address ID
 	data, err := s.storage.Get(ctx, AddressByEthAddressKey(chainID, addr))
 	if err != nil {
-		if err == storage.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			return nil, ErrAddressNotFound
 		}
 		return nil, NewWatchlistError("lookup", err)
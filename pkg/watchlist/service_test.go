@@ -103,6 +103,9 @@ func (m *mockStorage) GetTransaction(ctx context.Context, hash common.Hash) (*ty
 func (m *mockStorage) GetTransactions(ctx context.Context, hashes []common.Hash) ([]*types.Transaction, []*storage.TxLocation, error) {
 	return nil, nil, nil
 }
+func (m *mockStorage) GetTransactionsByBlock(ctx context.Context, height uint64) ([]*types.Transaction, []*storage.TxLocation, error) {
+	return nil, nil, nil
+}
 func (m *mockStorage) GetTransactionsByAddress(ctx context.Context, addr common.Address, limit, offset int) ([]common.Hash, error) {
 	return nil, nil
 }
@@ -196,7 +199,7 @@ func (m *mockStorage) GetMintEvents(ctx context.Context, fromBlock, toBlock uint
 func (m *mockStorage) GetBurnEvents(ctx context.Context, fromBlock, toBlock uint64, burner common.Address, limit, offset int) ([]*storage.BurnEvent, error) {
 	return nil, nil
 }
-func (m *mockStorage) GetActiveMinters(ctx context.Context) ([]common.Address, error) { return nil, nil }
+func (m *mockStorage) GetActiveMinters(ctx context.Context) ([]*storage.ActiveMinter, error) { return nil, nil }
 func (m *mockStorage) GetMinterAllowance(ctx context.Context, minter common.Address) (*big.Int, error) {
 	return big.NewInt(0), nil
 }
@@ -323,7 +326,7 @@ func (m *mockStorage) SetFeeDelegationTxMeta(ctx context.Context, meta *storage.
 }
 
 // HistoricalReader interface
-func (m *mockStorage) GetBlocksByTimeRange(ctx context.Context, fromTime, toTime uint64, limit, offset int) ([]*types.Block, error) {
+func (m *mockStorage) GetBlocksByTimeRange(ctx context.Context, fromTime, toTime uint64, limit, offset int, order storage.SortOrder) ([]*types.Block, error) {
 	return nil, nil
 }
 func (m *mockStorage) GetBlockByTimestamp(ctx context.Context, timestamp uint64) (*types.Block, error) {